@@ -30,6 +30,41 @@ export async function querySyncStatus(): Promise<SyncStatus> {
   return resp.data.syncing;
 }
 
+export interface LogEvent {
+  time: string;
+  level: string;
+  message: string;
+  fields?: Record<string, any>;
+}
+
+export interface LogFilter {
+  strategy?: string;
+  instance?: string;
+  level?: string;
+}
+
+// subscribeLogs streams the log events of the strategy instance over websocket,
+// close the returned websocket to unsubscribe.
+export function subscribeLogs(
+  filter: LogFilter,
+  cb: (event: LogEvent) => void
+): WebSocket {
+  const params = new URLSearchParams();
+  Object.entries(filter).forEach(([key, value]) => {
+    if (value) {
+      params.set(key, value);
+    }
+  });
+
+  const origin = baseURL || window.location.origin;
+  const url = origin.replace(/^http/, 'ws') + '/api/logs/stream?' + params;
+  const ws = new WebSocket(url);
+  ws.onmessage = (message) => {
+    cb(JSON.parse(message.data));
+  };
+  return ws;
+}
+
 export function testDatabaseConnection(params, cb) {
   return axios.post(baseURL + '/api/setup/test-db', params).then((response) => {
     cb(response.data);
@@ -0,0 +1,10 @@
+-- +up
+-- +begin
+CREATE TABLE `mexc_klines` LIKE `binance_klines`;
+-- +end
+
+-- +down
+
+-- +begin
+DROP TABLE `mexc_klines`;
+-- +end
@@ -0,0 +1,10 @@
+-- +up
+-- +begin
+CREATE TABLE `bitstamp_klines` LIKE `binance_klines`;
+-- +end
+
+-- +down
+
+-- +begin
+DROP TABLE `bitstamp_klines`;
+-- +end
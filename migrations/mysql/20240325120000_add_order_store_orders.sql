@@ -0,0 +1,19 @@
+-- +up
+-- +begin
+CREATE TABLE `order_store_orders`
+(
+    `store_id`   VARCHAR(128)    NOT NULL,
+    `exchange`   VARCHAR(24)     NOT NULL DEFAULT '',
+    `order_id`   BIGINT UNSIGNED NOT NULL,
+    `status`     VARCHAR(20)     NOT NULL,
+    `data`       MEDIUMTEXT      NOT NULL,
+    `updated_at` DATETIME(3)     NOT NULL,
+    PRIMARY KEY (`store_id`, `exchange`, `order_id`)
+);
+-- +end
+
+-- +down
+
+-- +begin
+DROP TABLE `order_store_orders`;
+-- +end
@@ -0,0 +1,32 @@
+-- +up
+-- +begin
+CREATE TABLE `regime_stats`
+(
+    `gid`                  BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    `strategy`             VARCHAR(32)     NOT NULL,
+    `strategy_instance_id` VARCHAR(64)     NOT NULL,
+    `exchange`             VARCHAR(24)     NOT NULL DEFAULT '',
+    `symbol`               VARCHAR(20)     NOT NULL,
+    `volatility_regime`    VARCHAR(16)     NOT NULL,
+    `trend_regime`         VARCHAR(16)     NOT NULL,
+    `start_time`           DATETIME(3)     NOT NULL,
+    `end_time`             DATETIME(3)     NOT NULL,
+    `num_orders`           INT UNSIGNED    NOT NULL DEFAULT 0,
+    `num_fills`            INT UNSIGNED    NOT NULL DEFAULT 0,
+    `quoted_volume`        DECIMAL(32, 8)  NOT NULL DEFAULT 0.0,
+    `filled_volume`        DECIMAL(32, 8)  NOT NULL DEFAULT 0.0,
+    `fill_ratio`           DECIMAL(16, 8)  NOT NULL DEFAULT 0.0,
+    `profit`               DECIMAL(32, 8)  NOT NULL DEFAULT 0.0,
+    `net_profit`           DECIMAL(32, 8)  NOT NULL DEFAULT 0.0,
+    `num_markouts`         INT UNSIGNED    NOT NULL DEFAULT 0,
+    `average_markout`      DECIMAL(16, 8)  NOT NULL DEFAULT 0.0,
+    PRIMARY KEY (`gid`),
+    INDEX `regime_stats_instance_time` (`strategy_instance_id`, `start_time`)
+);
+-- +end
+
+-- +down
+
+-- +begin
+DROP TABLE `regime_stats`;
+-- +end
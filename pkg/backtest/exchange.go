@@ -30,6 +30,7 @@ package backtest
 import (
 	"context"
 	"fmt"
+	"os"
 	"strconv"
 	"sync"
 	"time"
@@ -41,6 +42,7 @@ import (
 	"github.com/c9s/bbgo/pkg/cache"
 
 	"github.com/c9s/bbgo/pkg/bbgo"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
 	"github.com/c9s/bbgo/pkg/service"
 	"github.com/c9s/bbgo/pkg/types"
 )
@@ -74,6 +76,14 @@ type Exchange struct {
 
 	markets types.MarketMap
 
+	// borrowInterestRates are the hourly interest rates of the borrowed assets
+	borrowInterestRates map[string]fixedpoint.Value
+
+	// lastInterestTime is the last hour that the interest is accrued
+	lastInterestTime time.Time
+
+	userDataStream types.StandardStreamEmitter
+
 	Src *ExchangeDataSource
 }
 
@@ -109,6 +119,9 @@ func NewExchange(
 		currentTime:    startTime,
 		closedOrders:   make(map[string][]types.Order),
 		trades:         make(map[string][]types.Trade),
+
+		borrowInterestRates: configAccount.BorrowInterestRates,
+		lastInterestTime:    startTime.Truncate(time.Hour),
 	}
 
 	e.resetMatchingBooks()
@@ -329,6 +342,8 @@ func (e *Exchange) matchingBook(symbol string) (*SimplePriceMatching, bool) {
 }
 
 func (e *Exchange) BindUserData(userDataStream types.StandardStreamEmitter) {
+	e.userDataStream = userDataStream
+
 	userDataStream.OnTradeUpdate(func(trade types.Trade) {
 		e.addTrade(trade)
 	})
@@ -402,7 +417,26 @@ func (e *Exchange) SubscribeMarketData(
 		return c, nil
 	}
 
+	var klineCache *KLineCache
+	var klineCachePath string
+	if len(e.config.KLineCacheDir) > 0 {
+		klineCache = NewKLineCache(e.config.KLineCacheDir)
+		klineCachePath = klineCache.Path(e.Name(), symbols, intervals, startTime, endTime)
+
+		c, err := klineCache.Load(klineCachePath)
+		if err == nil {
+			log.Infof("loading klines from the kline cache file %s", klineCachePath)
+			return c, nil
+		} else if !os.IsNotExist(err) {
+			log.WithError(err).Warnf("unable to load the kline cache file %s, querying the database", klineCachePath)
+		}
+	}
+
 	klineC, errC := e.srv.QueryKLinesCh(startTime, endTime, e.publicExchange, symbols, intervals)
+	if klineCache != nil {
+		return klineCache.Tee(klineCachePath, e.Name(), symbols, intervals, klineC, errC), nil
+	}
+
 	go func() {
 		if err := <-errC; err != nil {
 			log.WithError(err).Error("backtest data feed error")
@@ -427,6 +461,8 @@ func (e *Exchange) ConsumeKLine(k types.KLine, requiredInterval types.Interval)
 			panic(fmt.Sprintf("expect required kline interval %s, got interval %s", requiredInterval.String(), requiredKline.Interval.String()))
 		}
 		e.currentTime = requiredKline.EndTime.Time()
+		e.accrueInterest()
+
 		// here we generate trades and order updates
 		matching.processKLine(requiredKline)
 		matching.nextKLine = &k
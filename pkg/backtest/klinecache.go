@@ -0,0 +1,414 @@
+package backtest
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// The kline cache file layout:
+//
+//	header:  magic "BKLC", version uint16, exchange, symbol table, interval table
+//	records: fixed size kline records until the end of the file
+//
+// Strings are prefixed with their uint16 length, and all numbers are little endian.
+// The file is written once and then memory mapped by the back-test processes that share it.
+const (
+	klineCacheMagic   = "BKLC"
+	klineCacheVersion = 1
+
+	// symbol index, interval index, start time, end time, 8 float64 values,
+	// last trade id, number of trades and the closed flag
+	klineCacheRecordSize = 2 + 2 + 8 + 8 + 8*8 + 8 + 8 + 1
+)
+
+// KLineCache stores the back-test klines queried from the database in the cache directory,
+// so that the parallel back-test runs of the optimizer read the same klines from the
+// memory mapped files instead of querying the database again.
+type KLineCache struct {
+	Dir string
+}
+
+func NewKLineCache(dir string) *KLineCache {
+	return &KLineCache{Dir: dir}
+}
+
+// Path returns the cache file path of the given kline query
+func (c *KLineCache) Path(
+	exchange types.ExchangeName, symbols []string, intervals []types.Interval, startTime, endTime time.Time,
+) string {
+	symbols, intervals = sortedKLineCacheKeys(symbols, intervals)
+
+	var intervalStrs []string
+	for _, interval := range intervals {
+		intervalStrs = append(intervalStrs, interval.String())
+	}
+
+	key := strings.Join([]string{
+		exchange.String(),
+		strings.Join(symbols, ","),
+		strings.Join(intervalStrs, ","),
+		startTime.UTC().Format(time.RFC3339),
+		endTime.UTC().Format(time.RFC3339),
+	}, "|")
+
+	sum := sha1.Sum([]byte(key))
+	return filepath.Join(c.Dir, fmt.Sprintf("%s-%s.klines", exchange.String(), hex.EncodeToString(sum[:8])))
+}
+
+// Load memory maps the cache file and emits the cached klines in the stored order.
+// os.ErrNotExist is returned if the cache file is not written yet.
+func (c *KLineCache) Load(path string) (chan types.KLine, error) {
+	data, closer, err := mapKLineCacheFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	h, offset, err := decodeKLineCacheHeader(data)
+	if err != nil {
+		_ = closer()
+		return nil, errors.Wrapf(err, "invalid kline cache file %s", path)
+	}
+
+	if (len(data)-offset)%klineCacheRecordSize != 0 {
+		_ = closer()
+		return nil, fmt.Errorf("kline cache file %s is truncated", path)
+	}
+
+	ch := make(chan types.KLine, 500)
+	go func() {
+		defer close(ch)
+		defer func() {
+			if err := closer(); err != nil {
+				log.WithError(err).Errorf("unable to unmap the kline cache file %s", path)
+			}
+		}()
+
+		for ; offset < len(data); offset += klineCacheRecordSize {
+			k, err := h.decodeRecord(data[offset : offset+klineCacheRecordSize])
+			if err != nil {
+				log.WithError(err).Errorf("kline cache file %s is corrupted", path)
+				return
+			}
+
+			ch <- k
+		}
+	}()
+
+	return ch, nil
+}
+
+// Tee forwards the klines from the source channel and writes them into the cache file.
+// The cache file is only committed when the source query is finished without error,
+// the klines are written into a temporary file and renamed, so the concurrent writers
+// of the same query never leave a partial cache file.
+func (c *KLineCache) Tee(
+	path string, exchange types.ExchangeName, symbols []string, intervals []types.Interval,
+	source chan types.KLine, errC chan error,
+) chan types.KLine {
+	ch := make(chan types.KLine, 500)
+
+	go func() {
+		defer close(ch)
+
+		w, err := newKLineCacheWriter(path, exchange, symbols, intervals)
+		if err != nil {
+			log.WithError(err).Errorf("unable to create the kline cache file %s", path)
+		}
+
+		for k := range source {
+			if w != nil {
+				if err := w.Write(k); err != nil {
+					log.WithError(err).Errorf("unable to write the kline cache file %s", path)
+					w.Discard()
+					w = nil
+				}
+			}
+
+			ch <- k
+		}
+
+		if err := <-errC; err != nil {
+			log.WithError(err).Error("backtest data feed error")
+			if w != nil {
+				w.Discard()
+			}
+			return
+		}
+
+		if w != nil {
+			if err := w.Commit(); err != nil {
+				log.WithError(err).Errorf("unable to commit the kline cache file %s", path)
+			}
+		}
+	}()
+
+	return ch
+}
+
+type klineCacheHeader struct {
+	exchange  types.ExchangeName
+	symbols   []string
+	intervals []types.Interval
+}
+
+func sortedKLineCacheKeys(symbols []string, intervals []types.Interval) ([]string, []types.Interval) {
+	symbols = append([]string(nil), symbols...)
+	sort.Strings(symbols)
+
+	intervals = append([]types.Interval(nil), intervals...)
+	sort.Slice(intervals, func(i, j int) bool {
+		return intervals[i] < intervals[j]
+	})
+
+	return symbols, intervals
+}
+
+func (h *klineCacheHeader) encode() []byte {
+	buf := []byte(klineCacheMagic)
+	buf = binary.LittleEndian.AppendUint16(buf, klineCacheVersion)
+	buf = appendKLineCacheString(buf, h.exchange.String())
+
+	buf = binary.LittleEndian.AppendUint16(buf, uint16(len(h.symbols)))
+	for _, symbol := range h.symbols {
+		buf = appendKLineCacheString(buf, symbol)
+	}
+
+	buf = binary.LittleEndian.AppendUint16(buf, uint16(len(h.intervals)))
+	for _, interval := range h.intervals {
+		buf = appendKLineCacheString(buf, interval.String())
+	}
+
+	return buf
+}
+
+func (h *klineCacheHeader) encodeRecord(buf []byte, k types.KLine) error {
+	symbolIdx, intervalIdx := -1, -1
+	for i, symbol := range h.symbols {
+		if symbol == k.Symbol {
+			symbolIdx = i
+			break
+		}
+	}
+
+	for i, interval := range h.intervals {
+		if interval == k.Interval {
+			intervalIdx = i
+			break
+		}
+	}
+
+	if symbolIdx < 0 || intervalIdx < 0 {
+		return fmt.Errorf("kline %s %s is not in the cache key", k.Symbol, k.Interval)
+	}
+
+	binary.LittleEndian.PutUint16(buf[0:], uint16(symbolIdx))
+	binary.LittleEndian.PutUint16(buf[2:], uint16(intervalIdx))
+	binary.LittleEndian.PutUint64(buf[4:], uint64(k.StartTime.Time().UnixMilli()))
+	binary.LittleEndian.PutUint64(buf[12:], uint64(k.EndTime.Time().UnixMilli()))
+
+	offset := 20
+	for _, v := range []fixedpoint.Value{
+		k.Open, k.Close, k.High, k.Low, k.Volume, k.QuoteVolume, k.TakerBuyBaseAssetVolume, k.TakerBuyQuoteAssetVolume,
+	} {
+		binary.LittleEndian.PutUint64(buf[offset:], math.Float64bits(v.Float64()))
+		offset += 8
+	}
+
+	binary.LittleEndian.PutUint64(buf[offset:], k.LastTradeID)
+	binary.LittleEndian.PutUint64(buf[offset+8:], k.NumberOfTrades)
+	if k.Closed {
+		buf[offset+16] = 1
+	} else {
+		buf[offset+16] = 0
+	}
+
+	return nil
+}
+
+func (h *klineCacheHeader) decodeRecord(buf []byte) (types.KLine, error) {
+	symbolIdx := int(binary.LittleEndian.Uint16(buf[0:]))
+	intervalIdx := int(binary.LittleEndian.Uint16(buf[2:]))
+	if symbolIdx >= len(h.symbols) || intervalIdx >= len(h.intervals) {
+		return types.KLine{}, fmt.Errorf("invalid symbol index %d or interval index %d", symbolIdx, intervalIdx)
+	}
+
+	var values [8]fixedpoint.Value
+	offset := 20
+	for i := range values {
+		values[i] = fixedpoint.NewFromFloat(math.Float64frombits(binary.LittleEndian.Uint64(buf[offset:])))
+		offset += 8
+	}
+
+	return types.KLine{
+		Exchange:                 h.exchange,
+		Symbol:                   h.symbols[symbolIdx],
+		Interval:                 h.intervals[intervalIdx],
+		StartTime:                types.Time(time.UnixMilli(int64(binary.LittleEndian.Uint64(buf[4:])))),
+		EndTime:                  types.Time(time.UnixMilli(int64(binary.LittleEndian.Uint64(buf[12:])))),
+		Open:                     values[0],
+		Close:                    values[1],
+		High:                     values[2],
+		Low:                      values[3],
+		Volume:                   values[4],
+		QuoteVolume:              values[5],
+		TakerBuyBaseAssetVolume:  values[6],
+		TakerBuyQuoteAssetVolume: values[7],
+		LastTradeID:              binary.LittleEndian.Uint64(buf[offset:]),
+		NumberOfTrades:           binary.LittleEndian.Uint64(buf[offset+8:]),
+		Closed:                   buf[offset+16] == 1,
+	}, nil
+}
+
+func decodeKLineCacheHeader(data []byte) (*klineCacheHeader, int, error) {
+	if len(data) < len(klineCacheMagic)+2 || string(data[:len(klineCacheMagic)]) != klineCacheMagic {
+		return nil, 0, errors.New("bad magic")
+	}
+
+	offset := len(klineCacheMagic)
+	if version := binary.LittleEndian.Uint16(data[offset:]); version != klineCacheVersion {
+		return nil, 0, fmt.Errorf("unsupported version %d", version)
+	}
+	offset += 2
+
+	var err error
+	var exchange string
+	if exchange, offset, err = readKLineCacheString(data, offset); err != nil {
+		return nil, 0, err
+	}
+
+	h := &klineCacheHeader{exchange: types.ExchangeName(exchange)}
+
+	n, offset, err := readKLineCacheUint16(data, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	for i := 0; i < n; i++ {
+		var symbol string
+		if symbol, offset, err = readKLineCacheString(data, offset); err != nil {
+			return nil, 0, err
+		}
+		h.symbols = append(h.symbols, symbol)
+	}
+
+	n, offset, err = readKLineCacheUint16(data, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	for i := 0; i < n; i++ {
+		var interval string
+		if interval, offset, err = readKLineCacheString(data, offset); err != nil {
+			return nil, 0, err
+		}
+		h.intervals = append(h.intervals, types.Interval(interval))
+	}
+
+	return h, offset, nil
+}
+
+func appendKLineCacheString(buf []byte, s string) []byte {
+	buf = binary.LittleEndian.AppendUint16(buf, uint16(len(s)))
+	return append(buf, s...)
+}
+
+func readKLineCacheUint16(data []byte, offset int) (int, int, error) {
+	if offset+2 > len(data) {
+		return 0, offset, io.ErrUnexpectedEOF
+	}
+
+	return int(binary.LittleEndian.Uint16(data[offset:])), offset + 2, nil
+}
+
+func readKLineCacheString(data []byte, offset int) (string, int, error) {
+	n, offset, err := readKLineCacheUint16(data, offset)
+	if err != nil {
+		return "", offset, err
+	}
+
+	if offset+n > len(data) {
+		return "", offset, io.ErrUnexpectedEOF
+	}
+
+	return string(data[offset : offset+n]), offset + n, nil
+}
+
+type klineCacheWriter struct {
+	path   string
+	file   *os.File
+	writer *bufio.Writer
+	header *klineCacheHeader
+	record []byte
+}
+
+func newKLineCacheWriter(
+	path string, exchange types.ExchangeName, symbols []string, intervals []types.Interval,
+) (*klineCacheWriter, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return nil, err
+	}
+
+	symbols, intervals = sortedKLineCacheKeys(symbols, intervals)
+	w := &klineCacheWriter{
+		path:   path,
+		file:   f,
+		writer: bufio.NewWriter(f),
+		header: &klineCacheHeader{exchange: exchange, symbols: symbols, intervals: intervals},
+		record: make([]byte, klineCacheRecordSize),
+	}
+
+	if _, err := w.writer.Write(w.header.encode()); err != nil {
+		w.Discard()
+		return nil, err
+	}
+
+	return w, nil
+}
+
+func (w *klineCacheWriter) Write(k types.KLine) error {
+	if err := w.header.encodeRecord(w.record, k); err != nil {
+		return err
+	}
+
+	_, err := w.writer.Write(w.record)
+	return err
+}
+
+func (w *klineCacheWriter) Commit() error {
+	if err := w.writer.Flush(); err != nil {
+		w.Discard()
+		return err
+	}
+
+	if err := w.file.Close(); err != nil {
+		_ = os.Remove(w.file.Name())
+		return err
+	}
+
+	return os.Rename(w.file.Name(), w.path)
+}
+
+func (w *klineCacheWriter) Discard() {
+	_ = w.file.Close()
+	_ = os.Remove(w.file.Name())
+}
@@ -0,0 +1,89 @@
+package backtest
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestKLineCache(t *testing.T) {
+	cache := NewKLineCache(t.TempDir())
+	startTime := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	endTime := startTime.Add(time.Hour)
+	symbols := []string{"ETHUSDT", "BTCUSDT"}
+	intervals := []types.Interval{types.Interval1m, types.Interval5m}
+
+	path := cache.Path(types.ExchangeBinance, symbols, intervals, startTime, endTime)
+	assert.Equal(t, path, cache.Path(types.ExchangeBinance, []string{"BTCUSDT", "ETHUSDT"}, intervals, startTime, endTime))
+
+	_, err := cache.Load(path)
+	assert.True(t, os.IsNotExist(err))
+
+	klines := []types.KLine{
+		{
+			Exchange:  types.ExchangeBinance,
+			Symbol:    "BTCUSDT",
+			Interval:  types.Interval1m,
+			StartTime: types.Time(startTime),
+			EndTime:   types.Time(startTime.Add(time.Minute - time.Millisecond)),
+			Open:      fixedpoint.NewFromFloat(16500.5),
+			Close:     fixedpoint.NewFromFloat(16510.25),
+			High:      fixedpoint.NewFromFloat(16520),
+			Low:       fixedpoint.NewFromFloat(16490.75),
+			Volume:    fixedpoint.NewFromFloat(12.345),
+			Closed:    true,
+		},
+		{
+			Exchange:       types.ExchangeBinance,
+			Symbol:         "ETHUSDT",
+			Interval:       types.Interval5m,
+			StartTime:      types.Time(startTime),
+			EndTime:        types.Time(startTime.Add(5*time.Minute - time.Millisecond)),
+			Open:           fixedpoint.NewFromFloat(1200.1),
+			Close:          fixedpoint.NewFromFloat(1201.2),
+			NumberOfTrades: 42,
+			Closed:         true,
+		},
+	}
+
+	source := make(chan types.KLine, len(klines))
+	errC := make(chan error, 1)
+	for _, k := range klines {
+		source <- k
+	}
+	close(source)
+	close(errC)
+
+	var forwarded []types.KLine
+	for k := range cache.Tee(path, types.ExchangeBinance, symbols, intervals, source, errC) {
+		forwarded = append(forwarded, k)
+	}
+	assert.Equal(t, klines, forwarded)
+
+	c, err := cache.Load(path)
+	if assert.NoError(t, err) {
+		var loaded []types.KLine
+		for k := range c {
+			loaded = append(loaded, k)
+		}
+
+		if assert.Len(t, loaded, len(klines)) {
+			for i, k := range loaded {
+				assert.Equal(t, klines[i].Symbol, k.Symbol)
+				assert.Equal(t, klines[i].Interval, k.Interval)
+				assert.Equal(t, klines[i].StartTime.Time().UnixMilli(), k.StartTime.Time().UnixMilli())
+				assert.Equal(t, klines[i].EndTime.Time().UnixMilli(), k.EndTime.Time().UnixMilli())
+				assert.Equal(t, klines[i].Open.String(), k.Open.String())
+				assert.Equal(t, klines[i].Close.String(), k.Close.String())
+				assert.Equal(t, klines[i].Volume.String(), k.Volume.String())
+				assert.Equal(t, klines[i].NumberOfTrades, k.NumberOfTrades)
+				assert.True(t, k.Closed)
+			}
+		}
+	}
+}
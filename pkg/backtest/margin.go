@@ -0,0 +1,83 @@
+package backtest
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// BorrowMarginAsset borrows the asset into the available balance,
+// the interest of the first hour is charged at the borrow time like the exchanges do.
+func (e *Exchange) BorrowMarginAsset(ctx context.Context, asset string, amount fixedpoint.Value) error {
+	if amount.Sign() <= 0 {
+		return fmt.Errorf("borrow amount of %s should be positive, got %v", asset, amount)
+	}
+
+	balance, _ := e.account.Balance(asset)
+	balance.Currency = asset
+	balance.Available = balance.Available.Add(amount)
+	balance.Borrowed = balance.Borrowed.Add(amount)
+	balance.Interest = balance.Interest.Add(amount.Mul(e.borrowInterestRates[asset]))
+
+	e.updateBalance(balance)
+	return nil
+}
+
+// RepayMarginAsset repays the interest first and then the borrowed balance from the available balance
+func (e *Exchange) RepayMarginAsset(ctx context.Context, asset string, amount fixedpoint.Value) error {
+	balance, ok := e.account.Balance(asset)
+	if !ok || balance.Debt().IsZero() {
+		return fmt.Errorf("there is no borrowed %s to repay", asset)
+	}
+
+	amount = fixedpoint.Min(amount, balance.Debt())
+	if balance.Available.Compare(amount) < 0 {
+		return fmt.Errorf("insufficient available %s to repay: want to repay %v, available %v", asset, amount, balance.Available)
+	}
+
+	interest := fixedpoint.Min(amount, balance.Interest)
+	balance.Available = balance.Available.Sub(amount)
+	balance.Interest = balance.Interest.Sub(interest)
+	balance.Borrowed = balance.Borrowed.Sub(amount.Sub(interest))
+
+	e.updateBalance(balance)
+	return nil
+}
+
+// QueryMarginAssetMaxBorrowable returns the unlimited amount, the borrowing is not limited in the back-test
+func (e *Exchange) QueryMarginAssetMaxBorrowable(ctx context.Context, asset string) (fixedpoint.Value, error) {
+	return fixedpoint.PosInf, nil
+}
+
+// accrueInterest charges the hourly interest of the borrowed balances for every hour passed since the last accrual
+func (e *Exchange) accrueInterest() {
+	hours := int64(e.currentTime.Sub(e.lastInterestTime) / time.Hour)
+	if hours <= 0 {
+		return
+	}
+
+	e.lastInterestTime = e.lastInterestTime.Add(time.Duration(hours) * time.Hour)
+
+	for asset, balance := range e.account.Balances() {
+		rate, ok := e.borrowInterestRates[asset]
+		if !ok || balance.Borrowed.IsZero() {
+			continue
+		}
+
+		balance.Interest = balance.Interest.Add(balance.Borrowed.Mul(rate).Mul(fixedpoint.NewFromInt(hours)))
+		e.updateBalance(balance)
+	}
+}
+
+func (e *Exchange) updateBalance(balance types.Balance) {
+	balance.NetAsset = balance.Net()
+	balances := types.BalanceMap{balance.Currency: balance}
+	e.account.UpdateBalances(balances)
+
+	if e.userDataStream != nil {
+		e.userDataStream.EmitBalanceUpdate(balances)
+	}
+}
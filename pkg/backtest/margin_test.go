@@ -0,0 +1,68 @@
+package backtest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestExchange_borrowInterest(t *testing.T) {
+	ctx := context.Background()
+	startTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	account := &types.Account{}
+	account.UpdateBalances(types.BalanceMap{
+		"USDT": {Currency: "USDT", Available: fixedpoint.NewFromInt(10000)},
+	})
+
+	e := &Exchange{
+		account:     account,
+		currentTime: startTime,
+		borrowInterestRates: map[string]fixedpoint.Value{
+			"BTC": fixedpoint.NewFromFloat(0.0001),
+		},
+		lastInterestTime: startTime,
+	}
+
+	// the interest of the first hour is charged at the borrow time
+	assert.NoError(t, e.BorrowMarginAsset(ctx, "BTC", fixedpoint.NewFromInt(10)))
+	balance, _ := account.Balance("BTC")
+	assert.Equal(t, "10", balance.Available.String())
+	assert.Equal(t, "10", balance.Borrowed.String())
+	assert.Equal(t, "0.001", balance.Interest.String())
+
+	// no interest is accrued within the hour
+	e.currentTime = startTime.Add(30 * time.Minute)
+	e.accrueInterest()
+	balance, _ = account.Balance("BTC")
+	assert.Equal(t, "0.001", balance.Interest.String())
+
+	e.currentTime = startTime.Add(2*time.Hour + 30*time.Minute)
+	e.accrueInterest()
+	balance, _ = account.Balance("BTC")
+	assert.Equal(t, "0.003", balance.Interest.String())
+	assert.Equal(t, "-0.003", balance.Net().String())
+
+	// the assets without a rate are borrowed without interest
+	assert.NoError(t, e.BorrowMarginAsset(ctx, "USDT", fixedpoint.NewFromInt(100)))
+	e.currentTime = startTime.Add(4 * time.Hour)
+	e.accrueInterest()
+	balance, _ = account.Balance("USDT")
+	assert.Equal(t, "100", balance.Borrowed.String())
+	assert.True(t, balance.Interest.IsZero())
+
+	// the interest is repaid first
+	assert.NoError(t, e.RepayMarginAsset(ctx, "BTC", fixedpoint.NewFromInt(5)))
+	balance, _ = account.Balance("BTC")
+	assert.Equal(t, "5", balance.Available.String())
+	assert.Equal(t, "5.005", balance.Borrowed.String())
+	assert.True(t, balance.Interest.IsZero())
+
+	assert.Error(t, e.RepayMarginAsset(ctx, "BTC", fixedpoint.NewFromInt(6)))
+	assert.Error(t, e.RepayMarginAsset(ctx, "ETH", fixedpoint.NewFromInt(1)))
+}
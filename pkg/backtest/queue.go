@@ -0,0 +1,192 @@
+package backtest
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// QueueFill is the simulated maker fill of the queued order
+type QueueFill struct {
+	OrderID  uint64
+	Side     types.SideType
+	Price    fixedpoint.Value
+	Quantity fixedpoint.Value
+}
+
+type queuedOrder struct {
+	orderID   uint64
+	side      types.SideType
+	price     fixedpoint.Value
+	remaining fixedpoint.Value
+
+	// ahead is the quantity queued ahead of the order at the same price level
+	ahead fixedpoint.Value
+}
+
+type queueLevelKey struct {
+	side  types.SideType
+	price string
+}
+
+// QueuePositionModel simulates the queue priority of the maker orders in the L2 replay back-tests.
+//
+// The order joins the back of the queue of its price level, so the current level size is queued ahead of it.
+// The traded volume at the price consumes the quantity ahead first, the order is only filled by the volume
+// that exceeds the quantity ahead. The level size decreases that are not explained by the trades are
+// the cancels, which are assumed to be uniformly distributed in the queue, so the quantity ahead
+// is reduced proportionally. The level size increases are queued behind the order.
+type QueuePositionModel struct {
+	mu sync.Mutex
+
+	levels map[queueLevelKey]fixedpoint.Value
+
+	// traded is the traded volume of the level that is not reflected by the level update yet
+	traded map[queueLevelKey]fixedpoint.Value
+
+	orders map[uint64]*queuedOrder
+}
+
+func NewQueuePositionModel() *QueuePositionModel {
+	return &QueuePositionModel{
+		levels: make(map[queueLevelKey]fixedpoint.Value),
+		traded: make(map[queueLevelKey]fixedpoint.Value),
+		orders: make(map[uint64]*queuedOrder),
+	}
+}
+
+func newQueueLevelKey(side types.SideType, price fixedpoint.Value) queueLevelKey {
+	return queueLevelKey{side: side, price: price.String()}
+}
+
+// AddOrder queues the maker order at the back of its price level
+func (m *QueuePositionModel) AddOrder(order types.Order) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := newQueueLevelKey(order.Side, order.Price)
+	m.orders[order.OrderID] = &queuedOrder{
+		orderID:   order.OrderID,
+		side:      order.Side,
+		price:     order.Price,
+		remaining: order.Quantity.Sub(order.ExecutedQuantity),
+		ahead:     m.levels[key],
+	}
+}
+
+// RemoveOrder removes the canceled or filled order from the queue
+func (m *QueuePositionModel) RemoveOrder(orderID uint64) {
+	m.mu.Lock()
+	delete(m.orders, orderID)
+	m.mu.Unlock()
+}
+
+// QueueAhead returns the quantity queued ahead of the order
+func (m *QueuePositionModel) QueueAhead(orderID uint64) (fixedpoint.Value, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	o, ok := m.orders[orderID]
+	if !ok {
+		return fixedpoint.Zero, false
+	}
+
+	return o.ahead, true
+}
+
+// UpdateLevel applies the L2 delta of the price level, the size is the new total size of the level
+func (m *QueuePositionModel) UpdateLevel(side types.SideType, price, size fixedpoint.Value) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := newQueueLevelKey(side, price)
+	prevSize := m.levels[key]
+	if size.Sign() > 0 {
+		m.levels[key] = size
+	} else {
+		delete(m.levels, key)
+	}
+
+	decrease := prevSize.Sub(size)
+
+	// the decrease caused by the trades is already applied to the queues
+	traded := m.traded[key]
+	delete(m.traded, key)
+
+	canceled := decrease.Sub(traded)
+	if canceled.Sign() <= 0 || prevSize.Sign() <= 0 {
+		return
+	}
+
+	for _, o := range m.orders {
+		if o.side != side || o.price.Compare(price) != 0 || o.ahead.Sign() <= 0 {
+			continue
+		}
+
+		o.ahead = o.ahead.Sub(canceled.Mul(o.ahead).Div(prevSize))
+		if o.ahead.Sign() < 0 {
+			o.ahead = fixedpoint.Zero
+		}
+	}
+}
+
+// Trade applies the market trade and returns the fills of the queued orders.
+// The takerSide is the side of the aggressor, e.g. a sell taker trade fills the queued buy orders.
+func (m *QueuePositionModel) Trade(takerSide types.SideType, price, quantity fixedpoint.Value) (fills []QueueFill) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	makerSide := types.SideTypeBuy
+	if takerSide == types.SideTypeBuy {
+		makerSide = types.SideTypeSell
+	}
+
+	key := newQueueLevelKey(makerSide, price)
+	m.traded[key] = m.traded[key].Add(quantity)
+
+	for _, o := range m.orders {
+		if o.side != makerSide || o.remaining.Sign() <= 0 {
+			continue
+		}
+
+		var filled fixedpoint.Value
+		switch cmp := o.price.Compare(price); {
+		case cmp == 0:
+			// the traded volume consumes the quantity ahead first
+			filled = fixedpoint.Max(quantity.Sub(o.ahead), fixedpoint.Zero)
+			o.ahead = fixedpoint.Max(o.ahead.Sub(quantity), fixedpoint.Zero)
+
+		case (makerSide == types.SideTypeBuy && cmp > 0) || (makerSide == types.SideTypeSell && cmp < 0):
+			// the market traded through the price of the order, the whole level is consumed
+			filled = o.remaining
+
+		default:
+			continue
+		}
+
+		filled = fixedpoint.Min(filled, o.remaining)
+		if filled.Sign() <= 0 {
+			continue
+		}
+
+		o.remaining = o.remaining.Sub(filled)
+		fills = append(fills, QueueFill{
+			OrderID:  o.orderID,
+			Side:     o.side,
+			Price:    o.price,
+			Quantity: filled,
+		})
+
+		if o.remaining.Sign() <= 0 {
+			delete(m.orders, o.orderID)
+		}
+	}
+
+	sort.Slice(fills, func(i, j int) bool {
+		return fills[i].OrderID < fills[j].OrderID
+	})
+
+	return fills
+}
@@ -0,0 +1,71 @@
+package backtest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestQueuePositionModel(t *testing.T) {
+	number := fixedpoint.MustNewFromString
+
+	m := NewQueuePositionModel()
+	m.UpdateLevel(types.SideTypeBuy, number("100"), number("10"))
+
+	m.AddOrder(types.Order{
+		OrderID: 1,
+		SubmitOrder: types.SubmitOrder{
+			Side:     types.SideTypeBuy,
+			Price:    number("100"),
+			Quantity: number("2"),
+		},
+	})
+
+	ahead, ok := m.QueueAhead(1)
+	assert.True(t, ok)
+	assert.Equal(t, "10", ahead.String())
+
+	// the orders joined after us are queued behind
+	m.UpdateLevel(types.SideTypeBuy, number("100"), number("20"))
+	ahead, _ = m.QueueAhead(1)
+	assert.Equal(t, "10", ahead.String())
+
+	// 5 is canceled out of 20, a quarter of the quantity ahead is canceled
+	m.UpdateLevel(types.SideTypeBuy, number("100"), number("15"))
+	ahead, _ = m.QueueAhead(1)
+	assert.Equal(t, "7.5", ahead.String())
+
+	// the trade smaller than the quantity ahead does not fill the order
+	fills := m.Trade(types.SideTypeSell, number("100"), number("5"))
+	assert.Empty(t, fills)
+	ahead, _ = m.QueueAhead(1)
+	assert.Equal(t, "2.5", ahead.String())
+
+	// the level decrease of the trade is not counted as cancels
+	m.UpdateLevel(types.SideTypeBuy, number("100"), number("10"))
+	ahead, _ = m.QueueAhead(1)
+	assert.Equal(t, "2.5", ahead.String())
+
+	// only the volume exceeding the quantity ahead fills the order
+	fills = m.Trade(types.SideTypeSell, number("100"), number("3.5"))
+	if assert.Len(t, fills, 1) {
+		assert.Equal(t, uint64(1), fills[0].OrderID)
+		assert.Equal(t, "1", fills[0].Quantity.String())
+	}
+
+	// the buy taker trade does not fill the bid
+	fills = m.Trade(types.SideTypeBuy, number("100"), number("10"))
+	assert.Empty(t, fills)
+
+	// trading through the price fills the remaining quantity
+	fills = m.Trade(types.SideTypeSell, number("99"), number("0.1"))
+	if assert.Len(t, fills, 1) {
+		assert.Equal(t, "1", fills[0].Quantity.String())
+	}
+
+	_, ok = m.QueueAhead(1)
+	assert.False(t, ok, "the filled order should be removed")
+}
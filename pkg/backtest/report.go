@@ -226,7 +226,7 @@ func AddReportIndexRun(outputDirectory string, run Run) error {
 func InQuoteAsset(balances types.BalanceMap, market types.Market, price fixedpoint.Value) fixedpoint.Value {
 	quote := balances[market.QuoteCurrency]
 	base := balances[market.BaseCurrency]
-	return base.Total().Mul(price).Add(quote.Total())
+	return base.Net().Mul(price).Add(quote.Net())
 }
 
 func getReportIndexPath(outputDirectory string) string {
@@ -0,0 +1,445 @@
+package bbgo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/service"
+	"github.com/c9s/bbgo/pkg/timerwheel"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+const (
+	accountStatementDateLayout       = "2006-01-02"
+	defaultMaxAccountStatements      = 30
+	accountStatementRolloverInterval = time.Minute
+)
+
+// AccountStatementConfig configures the daily account statements of the sessions,
+// the statements are generated from the session balances and trades, independent of the strategy profit stats.
+type AccountStatementConfig struct {
+	Enabled bool `json:"enabled" yaml:"enabled"`
+
+	// Sessions is the session names to generate the statements, all the sessions are included if it's empty
+	Sessions []string `json:"sessions,omitempty" yaml:"sessions,omitempty"`
+
+	// TimeZone is the time zone of the statement day boundary, e.g. Asia/Taipei, defaults to UTC
+	TimeZone string `json:"timeZone,omitempty" yaml:"timeZone,omitempty"`
+
+	// MaxStatements is the number of the closed statements kept for each session, defaults to 30
+	MaxStatements int `json:"maxStatements,omitempty" yaml:"maxStatements,omitempty"`
+}
+
+func (c *AccountStatementConfig) Defaults() {
+	if c.MaxStatements == 0 {
+		c.MaxStatements = defaultMaxAccountStatements
+	}
+}
+
+func (c *AccountStatementConfig) Validate() error {
+	if _, err := c.location(); err != nil {
+		return err
+	}
+
+	if c.MaxStatements < 0 {
+		return fmt.Errorf("maxStatements should not be negative, got %d", c.MaxStatements)
+	}
+
+	return nil
+}
+
+func (c *AccountStatementConfig) location() (*time.Location, error) {
+	if c.TimeZone == "" {
+		return time.UTC, nil
+	}
+
+	loc, err := time.LoadLocation(c.TimeZone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid account statement time zone %q: %w", c.TimeZone, err)
+	}
+
+	return loc, nil
+}
+
+// AccountStatement is the daily statement of the session account
+type AccountStatement struct {
+	Session   string    `json:"session"`
+	Date      string    `json:"date"`
+	StartTime time.Time `json:"startTime"`
+	EndTime   time.Time `json:"endTime,omitempty"`
+
+	StartingBalances types.BalanceMap `json:"startingBalances"`
+	EndingBalances   types.BalanceMap `json:"endingBalances,omitempty"`
+
+	NumOfTrades int                         `json:"numOfTrades"`
+	Fees        map[string]fixedpoint.Value `json:"fees"`
+
+	Deposits    map[string]fixedpoint.Value `json:"deposits,omitempty"`
+	Withdrawals map[string]fixedpoint.Value `json:"withdrawals,omitempty"`
+
+	// NetChanges are the balance changes of the currencies excluding the deposits and the withdrawals
+	NetChanges map[string]fixedpoint.Value `json:"netChanges,omitempty"`
+
+	// NetPnL is the net changes valued in USD, the currencies without the USD price are not included
+	NetPnL fixedpoint.Value `json:"netPnL"`
+
+	// TransferError is set when the deposits and the withdrawals can not be queried,
+	// the transfers are then counted as the net changes.
+	TransferError string `json:"transferError,omitempty"`
+
+	Closed bool `json:"closed"`
+}
+
+func newAccountStatement(session string, startTime time.Time, balances types.BalanceMap) *AccountStatement {
+	return &AccountStatement{
+		Session:          session,
+		Date:             startTime.Format(accountStatementDateLayout),
+		StartTime:        startTime,
+		StartingBalances: balances.Copy(),
+		Fees:             make(map[string]fixedpoint.Value),
+	}
+}
+
+func (s *AccountStatement) addTrade(trade types.Trade) {
+	s.NumOfTrades++
+	if trade.FeeCurrency != "" && !trade.Fee.IsZero() {
+		s.Fees[trade.FeeCurrency] = s.Fees[trade.FeeCurrency].Add(trade.Fee)
+	}
+}
+
+// close computes the net changes from the ending balances and the transfers
+func (s *AccountStatement) close(
+	endTime time.Time, balances types.BalanceMap, deposits []types.Deposit, withdraws []types.Withdraw,
+	priceInUSD func(currency string) (fixedpoint.Value, bool),
+) {
+	s.EndTime = endTime
+	s.EndingBalances = balances.Copy()
+	s.Deposits = make(map[string]fixedpoint.Value)
+	s.Withdrawals = make(map[string]fixedpoint.Value)
+	s.NetChanges = make(map[string]fixedpoint.Value)
+	s.NetPnL = fixedpoint.Zero
+	s.Closed = true
+
+	for _, d := range deposits {
+		s.Deposits[d.Asset] = s.Deposits[d.Asset].Add(d.Amount)
+	}
+
+	for _, w := range withdraws {
+		s.Withdrawals[w.Asset] = s.Withdrawals[w.Asset].Add(w.Amount)
+	}
+
+	currencies := make(map[string]struct{})
+	for currency := range s.StartingBalances {
+		currencies[currency] = struct{}{}
+	}
+	for currency := range s.EndingBalances {
+		currencies[currency] = struct{}{}
+	}
+
+	for currency := range currencies {
+		change := s.EndingBalances[currency].Total().Sub(s.StartingBalances[currency].Total()).
+			Sub(s.Deposits[currency]).
+			Add(s.Withdrawals[currency])
+		if change.IsZero() {
+			continue
+		}
+
+		s.NetChanges[currency] = change
+		if priceInUSD == nil {
+			continue
+		}
+
+		if price, ok := priceInUSD(currency); ok {
+			s.NetPnL = s.NetPnL.Add(change.Mul(price))
+		}
+	}
+}
+
+func (s *AccountStatement) String() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%s account statement %s: %d trades, net PnL %s USD\n",
+		s.Session, s.Date, s.NumOfTrades, s.NetPnL.FormatString(2)))
+
+	for _, currency := range sortedKeys(s.NetChanges) {
+		sb.WriteString(fmt.Sprintf("- %s: %v -> %v, net change %v",
+			currency,
+			s.StartingBalances[currency].Total(),
+			s.EndingBalances[currency].Total(),
+			s.NetChanges[currency]))
+
+		if deposit, ok := s.Deposits[currency]; ok {
+			sb.WriteString(fmt.Sprintf(", deposit %v", deposit))
+		}
+
+		if withdrawal, ok := s.Withdrawals[currency]; ok {
+			sb.WriteString(fmt.Sprintf(", withdrawal %v", withdrawal))
+		}
+
+		sb.WriteString("\n")
+	}
+
+	for _, currency := range sortedKeys(s.Fees) {
+		sb.WriteString(fmt.Sprintf("- fee %s: %v\n", currency, s.Fees[currency]))
+	}
+
+	if s.TransferError != "" {
+		sb.WriteString(fmt.Sprintf("- unable to query the transfers: %s\n", s.TransferError))
+	}
+
+	return sb.String()
+}
+
+// accountStatementState is the persisted statements of a session
+type accountStatementState struct {
+	Current    *AccountStatement  `json:"current"`
+	Statements []AccountStatement `json:"statements"`
+}
+
+// accountStatementRecorder records the statements of one session
+type accountStatementRecorder struct {
+	session *ExchangeSession
+	store   service.Store
+
+	mu    sync.Mutex
+	state accountStatementState
+}
+
+func (r *accountStatementRecorder) handleTrade(trade types.Trade) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.state.Current != nil {
+		r.state.Current.addTrade(trade)
+	}
+}
+
+func (r *accountStatementRecorder) save() {
+	if r.store == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.store.Save(&r.state); err != nil {
+		log.WithError(err).Errorf("[%s] unable to save the account statements", r.session.Name)
+	}
+}
+
+// AccountStatementService generates the daily account statements of the sessions,
+// the closed statements are persisted and sent to the notification channels.
+type AccountStatementService struct {
+	config   *AccountStatementConfig
+	location *time.Location
+
+	priceInUSD func(currency string) (fixedpoint.Value, bool)
+
+	recorders map[string]*accountStatementRecorder
+
+	// rolling prevents the overlapping rollovers while the transfers are being queried
+	rolling atomic.Bool
+}
+
+func NewAccountStatementService(
+	config *AccountStatementConfig, priceInUSD func(currency string) (fixedpoint.Value, bool),
+) (*AccountStatementService, error) {
+	config.Defaults()
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	location, _ := config.location()
+	return &AccountStatementService{
+		config:     config,
+		location:   location,
+		priceInUSD: priceInUSD,
+		recorders:  make(map[string]*accountStatementRecorder),
+	}, nil
+}
+
+// Bind loads the persisted statements and records the trades of the sessions,
+// the current statements start from the current balances if there is no statement of today.
+func (s *AccountStatementService) Bind(ctx context.Context, sessions map[string]*ExchangeSession, now time.Time) {
+	var persistence service.PersistenceService
+	if facade := GetIsolationFromContext(ctx).persistenceServiceFacade; facade != nil {
+		persistence = facade.Get()
+	}
+
+	sessionNames := s.config.Sessions
+	if len(sessionNames) == 0 {
+		sessionNames = sortedKeys(sessions)
+	}
+
+	for _, name := range sessionNames {
+		session, ok := sessions[name]
+		if !ok {
+			log.Warnf("account statement session %s not found", name)
+			continue
+		}
+
+		recorder := &accountStatementRecorder{session: session}
+		if persistence != nil {
+			recorder.store = persistence.NewStore("account-statements", name)
+			if err := recorder.store.Load(&recorder.state); err != nil && err != service.ErrPersistenceNotExists {
+				log.WithError(err).Warnf("[%s] unable to load the account statements", name)
+			}
+		}
+
+		session.UserDataStream.OnTradeUpdate(recorder.handleTrade)
+		s.recorders[name] = recorder
+	}
+
+	s.rollover(ctx, now)
+}
+
+// Start binds the sessions and checks the day rollover on the shared timer wheel
+func (s *AccountStatementService) Start(ctx context.Context, sessions map[string]*ExchangeSession) {
+	s.Bind(ctx, sessions, time.Now())
+
+	timer := timerwheel.Default().Every(accountStatementRolloverInterval, func() {
+		if !s.rolling.CompareAndSwap(false, true) {
+			return
+		}
+
+		// the transfer queries should not block the timer wheel
+		go func() {
+			defer s.rolling.Store(false)
+			s.rollover(ctx, time.Now())
+		}()
+	})
+
+	go func() {
+		<-ctx.Done()
+		timer.Stop()
+	}()
+}
+
+// Statements returns the current statement and the closed statements of the session, the oldest statement first
+func (s *AccountStatementService) Statements(sessionName string) (*AccountStatement, []AccountStatement, bool) {
+	recorder, ok := s.recorders[sessionName]
+	if !ok {
+		return nil, nil, false
+	}
+
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+
+	var current *AccountStatement
+	if recorder.state.Current != nil {
+		c := *recorder.state.Current
+		current = &c
+	}
+
+	statements := make([]AccountStatement, len(recorder.state.Statements))
+	copy(statements, recorder.state.Statements)
+	return current, statements, true
+}
+
+// rollover closes the statements of the previous days and starts the statements of the current day
+func (s *AccountStatementService) rollover(ctx context.Context, now time.Time) {
+	now = now.In(s.location)
+	date := now.Format(accountStatementDateLayout)
+
+	for _, name := range sortedKeys(s.recorders) {
+		recorder := s.recorders[name]
+
+		recorder.mu.Lock()
+		current := recorder.state.Current
+		recorder.mu.Unlock()
+
+		if current != nil && current.Date == date {
+			continue
+		}
+
+		balances := types.BalanceMap{}
+		if account := recorder.session.GetAccount(); account != nil {
+			balances = account.Balances()
+		}
+
+		// the first statement starts from now since the balances at the start of the day are unknown
+		startTime := now
+		if current != nil {
+			s.closeStatement(ctx, recorder, now, balances)
+			startTime = startOfDay(now)
+		}
+
+		recorder.mu.Lock()
+		recorder.state.Current = newAccountStatement(name, startTime, balances)
+		recorder.mu.Unlock()
+
+		recorder.save()
+	}
+}
+
+func (s *AccountStatementService) closeStatement(
+	ctx context.Context, recorder *accountStatementRecorder, now time.Time, balances types.BalanceMap,
+) {
+	recorder.mu.Lock()
+	statement := *recorder.state.Current
+	recorder.mu.Unlock()
+
+	// the statement of the previous day ends at the day boundary, the late statement is closed with the current balances
+	endTime := startOfDay(statement.StartTime.In(s.location)).AddDate(0, 0, 1)
+	if endTime.After(now) {
+		endTime = now
+	}
+
+	var deposits []types.Deposit
+	var withdraws []types.Withdraw
+	var transferErr error
+	if transferService, ok := recorder.session.Exchange.(types.ExchangeTransferService); ok {
+		deposits, transferErr = transferService.QueryDepositHistory(ctx, "", statement.StartTime, endTime)
+		if transferErr == nil {
+			withdraws, transferErr = transferService.QueryWithdrawHistory(ctx, "", statement.StartTime, endTime)
+		}
+	}
+
+	// the trades recorded after the copy are added to the closed statement
+	recorder.mu.Lock()
+	statement.NumOfTrades = recorder.state.Current.NumOfTrades
+	statement.Fees = recorder.state.Current.Fees
+	recorder.mu.Unlock()
+
+	statement.close(endTime, balances, deposits, withdraws, s.priceInUSD)
+	if transferErr != nil {
+		statement.TransferError = transferErr.Error()
+	}
+
+	recorder.mu.Lock()
+	recorder.state.Statements = append(recorder.state.Statements, statement)
+	if n := len(recorder.state.Statements); n > s.config.MaxStatements {
+		recorder.state.Statements = recorder.state.Statements[n-s.config.MaxStatements:]
+	}
+	recorder.mu.Unlock()
+
+	log.Info(statement.String())
+	Notify("%s", statement.String())
+}
+
+func startOfDay(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
+
+// AccountStatements returns the account statement service, it's nil when the account statements are not configured
+func (environ *Environment) AccountStatements() *AccountStatementService {
+	return environ.accountStatements
+}
+
+// ConfigureAccountStatements generates the daily account statements of the sessions
+func (trader *Trader) ConfigureAccountStatements(config *AccountStatementConfig) error {
+	statements, err := NewAccountStatementService(config, trader.environment.PriceInUSD)
+	if err != nil {
+		return err
+	}
+
+	trader.environment.accountStatements = statements
+	return nil
+}
@@ -0,0 +1,106 @@
+package bbgo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+	"github.com/c9s/bbgo/pkg/types/mocks"
+)
+
+func TestAccountStatement_close(t *testing.T) {
+	number := fixedpoint.MustNewFromString
+
+	statement := newAccountStatement("binance", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), types.BalanceMap{
+		"BTC":  {Currency: "BTC", Available: number("1.0")},
+		"USDT": {Currency: "USDT", Available: number("10000"), Locked: number("1000")},
+	})
+
+	statement.close(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), types.BalanceMap{
+		"BTC":  {Currency: "BTC", Available: number("1.5")},
+		"USDT": {Currency: "USDT", Available: number("8000")},
+		"ETH":  {Currency: "ETH", Available: number("2")},
+	}, []types.Deposit{
+		{Asset: "ETH", Amount: number("2")},
+	}, []types.Withdraw{
+		{Asset: "USDT", Amount: number("1000")},
+	}, func(currency string) (fixedpoint.Value, bool) {
+		switch currency {
+		case "BTC":
+			return number("40000"), true
+		case "USDT":
+			return fixedpoint.One, true
+		}
+		return fixedpoint.Zero, false
+	})
+
+	assert.True(t, statement.Closed)
+	assert.Equal(t, "0.5", statement.NetChanges["BTC"].String())
+	assert.Equal(t, "-2000", statement.NetChanges["USDT"].String())
+
+	// the deposited ETH is not a balance change
+	_, ok := statement.NetChanges["ETH"]
+	assert.False(t, ok)
+
+	assert.Equal(t, "18000", statement.NetPnL.String())
+}
+
+func TestAccountStatementService_rollover(t *testing.T) {
+	number := fixedpoint.MustNewFromString
+	ctx := context.Background()
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockEx := mocks.NewMockExchange(mockCtrl)
+	mockEx.EXPECT().NewStream().Return(&types.StandardStream{}).Times(2)
+	session := NewExchangeSession("binance", mockEx)
+	session.Account.UpdateBalances(types.BalanceMap{
+		"USDT": {Currency: "USDT", Available: number("1000")},
+	})
+
+	statements, err := NewAccountStatementService(&AccountStatementConfig{Enabled: true, MaxStatements: 1}, nil)
+	assert.NoError(t, err)
+
+	day1 := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	statements.Bind(ctx, map[string]*ExchangeSession{"binance": session}, day1)
+
+	session.UserDataStream.(*types.StandardStream).EmitTradeUpdate(types.Trade{Fee: number("0.1"), FeeCurrency: "USDT"})
+	session.UserDataStream.(*types.StandardStream).EmitTradeUpdate(types.Trade{Fee: number("0.2"), FeeCurrency: "USDT"})
+	session.Account.UpdateBalances(types.BalanceMap{
+		"USDT": {Currency: "USDT", Available: number("1100")},
+	})
+
+	// no rollover on the same day
+	statements.rollover(ctx, day1.Add(time.Hour))
+	current, closed, ok := statements.Statements("binance")
+	assert.True(t, ok)
+	assert.Empty(t, closed)
+	assert.Equal(t, 2, current.NumOfTrades)
+	assert.Equal(t, day1, current.StartTime)
+
+	statements.rollover(ctx, time.Date(2024, 1, 2, 0, 0, 30, 0, time.UTC))
+	current, closed, _ = statements.Statements("binance")
+	if assert.Len(t, closed, 1) {
+		assert.Equal(t, "2024-01-01", closed[0].Date)
+		assert.Equal(t, time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), closed[0].EndTime)
+		assert.Equal(t, "0.3", closed[0].Fees["USDT"].String())
+		assert.Equal(t, "100", closed[0].NetChanges["USDT"].String())
+	}
+
+	assert.Equal(t, "2024-01-02", current.Date)
+	assert.Equal(t, 0, current.NumOfTrades)
+	assert.Equal(t, "1100", current.StartingBalances["USDT"].Total().String())
+
+	// the closed statements are trimmed to the max statements
+	statements.rollover(ctx, time.Date(2024, 1, 3, 0, 0, 30, 0, time.UTC))
+	_, closed, _ = statements.Statements("binance")
+	if assert.Len(t, closed, 1) {
+		assert.Equal(t, "2024-01-02", closed[0].Date)
+	}
+}
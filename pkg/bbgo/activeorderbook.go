@@ -26,6 +26,15 @@ type ActiveOrderBook struct {
 	filledCallbacks   []func(o types.Order)
 	canceledCallbacks []func(o types.Order)
 
+	// ghostOrderCallbacks are called when the locally active order is not found in the open orders of the exchange
+	ghostOrderCallbacks []func(o types.Order)
+
+	// orphanOrderCallbacks are called when the open order of the exchange is not found in the local active orders
+	orphanOrderCallbacks []func(o types.Order)
+
+	// expiredCallbacks are called when the order is still active after the order ttl
+	expiredCallbacks []func(o types.Order)
+
 	pendingOrderUpdates *types.SyncOrderMap
 
 	// sig is the order update signal
@@ -35,6 +44,9 @@ type ActiveOrderBook struct {
 	mu sync.Mutex
 
 	cancelOrderWaitTime time.Duration
+
+	// orderTTL is set by EnableOrderTTL
+	orderTTL *activeOrderTTL
 }
 
 func NewActiveOrderBook(symbol string) *ActiveOrderBook {
@@ -434,6 +446,8 @@ func (b *ActiveOrderBook) add(order types.Order) {
 	} else {
 		b.orders.Add(order)
 	}
+
+	b.scheduleOrderTTL(order)
 }
 
 func (b *ActiveOrderBook) Exists(order types.Order) bool {
@@ -35,3 +35,33 @@ func (b *ActiveOrderBook) EmitCanceled(o types.Order) {
 		cb(o)
 	}
 }
+
+func (b *ActiveOrderBook) OnGhostOrder(cb func(o types.Order)) {
+	b.ghostOrderCallbacks = append(b.ghostOrderCallbacks, cb)
+}
+
+func (b *ActiveOrderBook) EmitGhostOrder(o types.Order) {
+	for _, cb := range b.ghostOrderCallbacks {
+		cb(o)
+	}
+}
+
+func (b *ActiveOrderBook) OnOrphanOrder(cb func(o types.Order)) {
+	b.orphanOrderCallbacks = append(b.orphanOrderCallbacks, cb)
+}
+
+func (b *ActiveOrderBook) EmitOrphanOrder(o types.Order) {
+	for _, cb := range b.orphanOrderCallbacks {
+		cb(o)
+	}
+}
+
+func (b *ActiveOrderBook) OnExpired(cb func(o types.Order)) {
+	b.expiredCallbacks = append(b.expiredCallbacks, cb)
+}
+
+func (b *ActiveOrderBook) EmitExpired(o types.Order) {
+	for _, cb := range b.expiredCallbacks {
+		cb(o)
+	}
+}
@@ -0,0 +1,77 @@
+package bbgo
+
+import (
+	"context"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/c9s/bbgo/pkg/timerwheel"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// activeOrderTTL emulates the good-till-time orders for the exchanges that don't support them
+type activeOrderTTL struct {
+	ctx   context.Context
+	ex    types.Exchange
+	ttl   time.Duration
+	wheel *timerwheel.Wheel
+}
+
+// EnableOrderTTL cancels the orders that are still active after the ttl since they are added to the order book,
+// the expiry timers of the orders are registered on the timer wheel instead of running a ticker for each order book.
+// The expired orders are emitted by OnExpired before they are canceled, and the timers stop when the context is canceled.
+//
+// The wheel defaults to the shared timer wheel when it's nil.
+func (b *ActiveOrderBook) EnableOrderTTL(ctx context.Context, ex types.Exchange, ttl time.Duration, wheel *timerwheel.Wheel) {
+	if wheel == nil {
+		wheel = timerwheel.Default()
+	}
+
+	b.mu.Lock()
+	b.orderTTL = &activeOrderTTL{
+		ctx:   ctx,
+		ex:    ex,
+		ttl:   ttl,
+		wheel: wheel,
+	}
+	b.mu.Unlock()
+
+	// the orders added before enabling the ttl expire after the ttl from now
+	for _, o := range b.Orders() {
+		b.scheduleOrderTTL(o)
+	}
+}
+
+func (b *ActiveOrderBook) scheduleOrderTTL(order types.Order) {
+	b.mu.Lock()
+	orderTTL := b.orderTTL
+	b.mu.Unlock()
+
+	if orderTTL == nil || orderTTL.ctx.Err() != nil {
+		return
+	}
+
+	orderID := order.OrderID
+	orderTTL.wheel.AfterFunc(orderTTL.ttl, func() {
+		if orderTTL.ctx.Err() != nil {
+			return
+		}
+
+		// the order is already filled or canceled
+		o, ok := b.Get(orderID)
+		if !ok {
+			return
+		}
+
+		log.Infof("[ActiveOrderBook] order #%d is expired after %s, canceling: %s", o.OrderID, orderTTL.ttl, o.String())
+		b.EmitExpired(o)
+
+		// the wheel goroutine should not be blocked by the cancel request
+		go func() {
+			if err := b.FastCancel(orderTTL.ctx, orderTTL.ex, o); err != nil {
+				log.WithError(err).Errorf("[ActiveOrderBook] unable to cancel the expired order #%d", o.OrderID)
+			}
+		}()
+	})
+}
@@ -0,0 +1,61 @@
+package bbgo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/c9s/bbgo/pkg/timerwheel"
+	"github.com/c9s/bbgo/pkg/types"
+	"github.com/c9s/bbgo/pkg/types/mocks"
+)
+
+func TestActiveOrderBook_EnableOrderTTL(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	start := time.Now()
+	wheel := timerwheel.New(time.Second, start)
+	mockExchange := mocks.NewMockExchange(mockCtrl)
+
+	book := NewActiveOrderBook("BTCUSDT")
+	book.Add(newWatchdogTestOrder(1, types.OrderStatusNew, start))
+	book.EnableOrderTTL(ctx, mockExchange, 10*time.Second, wheel)
+
+	wheel.Advance(start.Add(5 * time.Second))
+	book.Add(newWatchdogTestOrder(2, types.OrderStatusNew, start))
+	book.Add(newWatchdogTestOrder(3, types.OrderStatusNew, start))
+
+	var expired []uint64
+	book.OnExpired(func(o types.Order) { expired = append(expired, o.OrderID) })
+
+	canceledC := make(chan uint64, 3)
+	mockExchange.EXPECT().CancelOrders(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, orders ...types.Order) error {
+		for _, o := range orders {
+			canceledC <- o.OrderID
+		}
+		return nil
+	}).Times(2)
+
+	wheel.Advance(start.Add(10 * time.Second))
+	assert.Equal(t, []uint64{1}, expired)
+	assert.Equal(t, uint64(1), <-canceledC)
+
+	// the filled order is not canceled
+	filled := newWatchdogTestOrder(3, types.OrderStatusFilled, start.Add(time.Second))
+	book.Update(filled)
+
+	wheel.Advance(start.Add(15 * time.Second))
+	assert.Equal(t, []uint64{1, 2}, expired)
+	assert.Equal(t, uint64(2), <-canceledC)
+
+	assert.Eventually(t, func() bool {
+		return book.NumOfOrders() == 0
+	}, time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,187 @@
+package bbgo
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/c9s/bbgo/pkg/timerwheel"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+type OrphanOrderAction string
+
+const (
+	// OrphanOrderActionNone only emits the orphan order events
+	OrphanOrderActionNone OrphanOrderAction = ""
+
+	// OrphanOrderActionAdopt adds the orphan orders into the active order book
+	OrphanOrderActionAdopt OrphanOrderAction = "adopt"
+
+	// OrphanOrderActionCancel cancels the orphan orders on the exchange
+	OrphanOrderActionCancel OrphanOrderAction = "cancel"
+)
+
+const defaultActiveOrderBookWatchdogGracePeriod = time.Minute
+
+// ActiveOrderBookWatchdogConfig configures the watchdog that cross-checks the local active orders
+// against the open orders of the exchange.
+//
+// A ghost order is an order that is active locally but not found in the open orders of the exchange,
+// usually the order update of the order is lost. An orphan order is an order that is open on the exchange
+// but unknown locally, usually the order is placed but the response is lost.
+type ActiveOrderBookWatchdogConfig struct {
+	Interval types.Duration `json:"interval"`
+
+	// GracePeriod skips the orders updated within the period, the websocket order updates of them might be still on the way.
+	GracePeriod types.Duration `json:"gracePeriod,omitempty"`
+
+	// RepairGhostOrders queries the ghost orders from the exchange and updates the active order book with the queried orders,
+	// the ghost orders are removed if the exchange does not support the order query.
+	RepairGhostOrders bool `json:"repairGhostOrders,omitempty"`
+
+	// OrphanOrderAction is the action of the orphan orders: adopt or cancel, the orphan orders are only reported by default
+	OrphanOrderAction OrphanOrderAction `json:"orphanOrderAction,omitempty"`
+
+	// OrphanOrderFilter filters the open orders that belong to the active order book, e.g. by the client order ID,
+	// the open orders of the other strategies on the same symbol should be excluded. All the open orders are checked if it's nil.
+	OrphanOrderFilter func(order types.Order) bool `json:"-"`
+}
+
+func (c *ActiveOrderBookWatchdogConfig) Validate() error {
+	if c.Interval.Duration() <= 0 {
+		return fmt.Errorf("watchdog interval should be positive, got %s", c.Interval.Duration())
+	}
+
+	switch c.OrphanOrderAction {
+	case OrphanOrderActionNone, OrphanOrderActionAdopt, OrphanOrderActionCancel:
+	default:
+		return fmt.Errorf("invalid orphanOrderAction %q", c.OrphanOrderAction)
+	}
+
+	return nil
+}
+
+// StartWatchdog checks the stale orders periodically on the shared timer wheel until the context is canceled
+func (b *ActiveOrderBook) StartWatchdog(ctx context.Context, ex types.Exchange, config ActiveOrderBookWatchdogConfig) {
+	var checking atomic.Bool
+	timer := timerwheel.Default().Every(config.Interval.Duration(), func() {
+		// skip this round if the previous check is still querying the exchange
+		if !checking.CompareAndSwap(false, true) {
+			return
+		}
+
+		// the open order queries should not block the timer wheel
+		go func() {
+			defer checking.Store(false)
+			if err := b.CheckStaleOrders(ctx, ex, config, time.Now()); err != nil {
+				log.WithError(err).Errorf("[ActiveOrderBook] unable to check the stale %s orders", b.Symbol)
+			}
+		}()
+	})
+
+	go func() {
+		<-ctx.Done()
+		timer.Stop()
+	}()
+}
+
+// CheckStaleOrders cross-checks the active orders against the open orders of the exchange,
+// emits the ghost order and the orphan order events, and repairs them by the config.
+func (b *ActiveOrderBook) CheckStaleOrders(
+	ctx context.Context, ex types.Exchange, config ActiveOrderBookWatchdogConfig, now time.Time,
+) error {
+	openOrders, err := ex.QueryOpenOrders(ctx, b.Symbol)
+	if err != nil {
+		return err
+	}
+
+	gracePeriod := config.GracePeriod.Duration()
+	if gracePeriod == 0 {
+		gracePeriod = defaultActiveOrderBookWatchdogGracePeriod
+	}
+
+	checkBefore := now.Add(-gracePeriod)
+
+	openOrderMap := make(map[uint64]types.Order, len(openOrders))
+	for _, o := range openOrders {
+		openOrderMap[o.OrderID] = o
+	}
+
+	for _, o := range b.Orders() {
+		if _, ok := openOrderMap[o.OrderID]; ok {
+			delete(openOrderMap, o.OrderID)
+			continue
+		}
+
+		if isRecentOrder(o, checkBefore) {
+			continue
+		}
+
+		log.Warnf("[ActiveOrderBook] found ghost order #%d: %s", o.OrderID, o.String())
+		b.EmitGhostOrder(o)
+
+		if config.RepairGhostOrders {
+			b.repairGhostOrder(ctx, ex, o)
+		}
+	}
+
+	for _, o := range openOrderMap {
+		if isRecentOrder(o, checkBefore) {
+			continue
+		}
+
+		if config.OrphanOrderFilter != nil && !config.OrphanOrderFilter(o) {
+			continue
+		}
+
+		log.Warnf("[ActiveOrderBook] found orphan order #%d: %s", o.OrderID, o.String())
+		b.EmitOrphanOrder(o)
+
+		switch config.OrphanOrderAction {
+		case OrphanOrderActionAdopt:
+			b.Add(o)
+
+		case OrphanOrderActionCancel:
+			if err := ex.CancelOrders(ctx, o); err != nil {
+				log.WithError(err).Errorf("[ActiveOrderBook] unable to cancel the orphan order #%d", o.OrderID)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (b *ActiveOrderBook) repairGhostOrder(ctx context.Context, ex types.Exchange, order types.Order) {
+	service, ok := ex.(types.ExchangeOrderQueryService)
+	if !ok {
+		log.Warnf("[ActiveOrderBook] %T does not support the order query, removing the ghost order #%d", ex, order.OrderID)
+		b.Remove(order)
+		return
+	}
+
+	updatedOrder, err := service.QueryOrder(ctx, types.OrderQuery{
+		Symbol:  order.Symbol,
+		OrderID: strconv.FormatUint(order.OrderID, 10),
+	})
+	if err != nil {
+		log.WithError(err).Errorf("[ActiveOrderBook] unable to query the ghost order #%d", order.OrderID)
+		return
+	}
+
+	// the order update emits the filled and canceled events
+	b.orderUpdateHandler(*updatedOrder)
+}
+
+func isRecentOrder(order types.Order, before time.Time) bool {
+	updateTime := order.UpdateTime.Time()
+	if updateTime.IsZero() {
+		updateTime = order.CreationTime.Time()
+	}
+
+	return updateTime.After(before)
+}
@@ -0,0 +1,114 @@
+package bbgo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+	"github.com/c9s/bbgo/pkg/types/mocks"
+)
+
+func newWatchdogTestOrder(orderID uint64, status types.OrderStatus, updateTime time.Time) types.Order {
+	return types.Order{
+		SubmitOrder: types.SubmitOrder{
+			Symbol:   "BTCUSDT",
+			Side:     types.SideTypeBuy,
+			Type:     types.OrderTypeLimit,
+			Quantity: fixedpoint.One,
+			Price:    fixedpoint.NewFromFloat(40000.0),
+		},
+		OrderID:    orderID,
+		Status:     status,
+		UpdateTime: types.Time(updateTime),
+	}
+}
+
+func TestActiveOrderBook_CheckStaleOrders(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockExchange := mocks.NewMockExchange(mockCtrl)
+	mockQueryService := mocks.NewMockExchangeOrderQueryService(mockCtrl)
+	ex := &testOrderQueryExchange{
+		MockExchange:                  mockExchange,
+		MockExchangeOrderQueryService: mockQueryService,
+	}
+
+	now := time.Now()
+	before := now.Add(-10 * time.Minute)
+
+	book := NewActiveOrderBook("BTCUSDT")
+	book.Add(
+		newWatchdogTestOrder(1, types.OrderStatusNew, before),
+		// ghost order
+		newWatchdogTestOrder(2, types.OrderStatusNew, before),
+		// the recent order is skipped
+		newWatchdogTestOrder(3, types.OrderStatusNew, now),
+	)
+
+	var ghostOrders, orphanOrders, filledOrders []uint64
+	book.OnGhostOrder(func(o types.Order) { ghostOrders = append(ghostOrders, o.OrderID) })
+	book.OnOrphanOrder(func(o types.Order) { orphanOrders = append(orphanOrders, o.OrderID) })
+	book.OnFilled(func(o types.Order) { filledOrders = append(filledOrders, o.OrderID) })
+
+	orphanOrder := newWatchdogTestOrder(4, types.OrderStatusNew, before)
+	mockExchange.EXPECT().QueryOpenOrders(gomock.Any(), "BTCUSDT").Return([]types.Order{
+		newWatchdogTestOrder(1, types.OrderStatusNew, before),
+		orphanOrder,
+	}, nil)
+
+	filledOrder := newWatchdogTestOrder(2, types.OrderStatusFilled, now)
+	mockQueryService.EXPECT().QueryOrder(gomock.Any(), types.OrderQuery{
+		Symbol:  "BTCUSDT",
+		OrderID: "2",
+	}).Return(&filledOrder, nil)
+
+	mockExchange.EXPECT().CancelOrders(gomock.Any(), orphanOrder).Return(nil)
+
+	err := book.CheckStaleOrders(context.Background(), ex, ActiveOrderBookWatchdogConfig{
+		Interval:          types.Duration(time.Minute),
+		RepairGhostOrders: true,
+		OrphanOrderAction: OrphanOrderActionCancel,
+	}, now)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []uint64{2}, ghostOrders)
+	assert.Equal(t, []uint64{4}, orphanOrders)
+	assert.Equal(t, []uint64{2}, filledOrders)
+	assert.False(t, book.orders.Exists(2))
+	assert.True(t, book.orders.Exists(3))
+}
+
+func TestActiveOrderBook_CheckStaleOrders_adopt(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockExchange := mocks.NewMockExchange(mockCtrl)
+
+	now := time.Now()
+	before := now.Add(-10 * time.Minute)
+	book := NewActiveOrderBook("BTCUSDT")
+
+	otherOrder := newWatchdogTestOrder(5, types.OrderStatusNew, before)
+	otherOrder.ClientOrderID = "other"
+	mockExchange.EXPECT().QueryOpenOrders(gomock.Any(), "BTCUSDT").Return([]types.Order{
+		newWatchdogTestOrder(4, types.OrderStatusNew, before),
+		otherOrder,
+	}, nil)
+
+	err := book.CheckStaleOrders(context.Background(), mockExchange, ActiveOrderBookWatchdogConfig{
+		Interval:          types.Duration(time.Minute),
+		OrphanOrderAction: OrphanOrderActionAdopt,
+		OrphanOrderFilter: func(o types.Order) bool {
+			return o.ClientOrderID != "other"
+		},
+	}, now)
+	assert.NoError(t, err)
+	assert.True(t, book.orders.Exists(4))
+	assert.False(t, book.orders.Exists(5))
+}
@@ -0,0 +1,481 @@
+package bbgo
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// AlertFunction is the function applied to the metric values before the comparison
+type AlertFunction string
+
+const (
+	// AlertFunctionValue compares the current value of the metric
+	AlertFunctionValue AlertFunction = ""
+
+	// AlertFunctionIncrease compares the increase of the metric (usually a counter) over the window,
+	// e.g. the increase of the trades total is zero when there is no fill in the window
+	AlertFunctionIncrease AlertFunction = "increase"
+)
+
+// AlertOperator is the comparison operator of the alert rule
+type AlertOperator string
+
+const (
+	AlertOperatorGreaterThan        AlertOperator = ">"
+	AlertOperatorGreaterThanOrEqual AlertOperator = ">="
+	AlertOperatorLessThan           AlertOperator = "<"
+	AlertOperatorLessThanOrEqual    AlertOperator = "<="
+	AlertOperatorEqual              AlertOperator = "=="
+	AlertOperatorNotEqual           AlertOperator = "!="
+)
+
+func (o AlertOperator) Compare(value, threshold float64) bool {
+	switch o {
+	case AlertOperatorGreaterThan:
+		return value > threshold
+	case AlertOperatorGreaterThanOrEqual:
+		return value >= threshold
+	case AlertOperatorLessThan:
+		return value < threshold
+	case AlertOperatorLessThanOrEqual:
+		return value <= threshold
+	case AlertOperatorEqual:
+		return value == threshold
+	case AlertOperatorNotEqual:
+		return value != threshold
+	}
+
+	return false
+}
+
+// AlertRule is a user-defined rule evaluated over the internal metrics, for example:
+//
+//	alerts:
+//	  rules:
+//	  - name: uncovered exposure
+//	    metric: xmaker_uncovered_position_usd
+//	    operator: ">"
+//	    threshold: 5000
+//	    for: 60s
+//	  - name: no fills
+//	    metric: xmaker_maker_trades_total
+//	    labels: { symbol: BTCUSDT }
+//	    function: increase
+//	    window: 30m
+//	    operator: "=="
+//	    threshold: 0
+type AlertRule struct {
+	Name string `json:"name" yaml:"name"`
+
+	// Metric is the name of the registered alert value or the prometheus metric
+	Metric string `json:"metric" yaml:"metric"`
+
+	// Labels filters the metric series by the label values, every matched series is evaluated separately
+	Labels map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+
+	Function AlertFunction `json:"function,omitempty" yaml:"function,omitempty"`
+
+	// Window is the time window of the increase function
+	Window types.Duration `json:"window,omitempty" yaml:"window,omitempty"`
+
+	Operator  AlertOperator `json:"operator" yaml:"operator"`
+	Threshold float64       `json:"threshold" yaml:"threshold"`
+
+	// For is how long the condition must hold before the alert fires
+	For types.Duration `json:"for,omitempty" yaml:"for,omitempty"`
+
+	// RepeatInterval re-sends the notification while the alert is firing, the alert is only notified once if it's zero
+	RepeatInterval types.Duration `json:"repeatInterval,omitempty" yaml:"repeatInterval,omitempty"`
+
+	// Message is the custom notification message of the alert
+	Message string `json:"message,omitempty" yaml:"message,omitempty"`
+}
+
+func (r *AlertRule) Validate() error {
+	if r.Name == "" || r.Metric == "" {
+		return fmt.Errorf("alert rule name and metric can not be empty")
+	}
+
+	switch r.Operator {
+	case AlertOperatorGreaterThan, AlertOperatorGreaterThanOrEqual,
+		AlertOperatorLessThan, AlertOperatorLessThanOrEqual,
+		AlertOperatorEqual, AlertOperatorNotEqual:
+	default:
+		return fmt.Errorf("alert rule %s: invalid operator %q", r.Name, r.Operator)
+	}
+
+	switch r.Function {
+	case AlertFunctionValue:
+	case AlertFunctionIncrease:
+		if r.Window <= 0 {
+			return fmt.Errorf("alert rule %s: the window of the increase function should be positive", r.Name)
+		}
+	default:
+		return fmt.Errorf("alert rule %s: invalid function %q", r.Name, r.Function)
+	}
+
+	if r.For < 0 || r.RepeatInterval < 0 {
+		return fmt.Errorf("alert rule %s: for and repeatInterval should not be negative", r.Name)
+	}
+
+	return nil
+}
+
+// AlertConfig is the alert rules evaluated inside bbgo, it covers the basic alerting without the external alertmanager
+type AlertConfig struct {
+	// Interval is the evaluation interval of the rules, defaults to 10s
+	Interval types.Duration `json:"interval,omitempty" yaml:"interval,omitempty"`
+
+	Rules []AlertRule `json:"rules" yaml:"rules"`
+}
+
+func (c *AlertConfig) Validate() error {
+	if c.Interval < 0 {
+		return fmt.Errorf("alert interval should not be negative")
+	}
+
+	for i := range c.Rules {
+		if err := c.Rules[i].Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AlertValueFunc returns the current value of the internal state, false is returned if the value is not available
+type AlertValueFunc func() (float64, bool)
+
+var alertValues = struct {
+	sync.Mutex
+	funcs map[string]AlertValueFunc
+}{funcs: make(map[string]AlertValueFunc)}
+
+// RegisterAlertValue exposes the internal state that is not a prometheus metric to the alert rules
+func RegisterAlertValue(name string, fn AlertValueFunc) {
+	alertValues.Lock()
+	alertValues.funcs[name] = fn
+	alertValues.Unlock()
+}
+
+func lookupAlertValue(name string) (AlertValueFunc, bool) {
+	alertValues.Lock()
+	defer alertValues.Unlock()
+	fn, ok := alertValues.funcs[name]
+	return fn, ok
+}
+
+type alertSample struct {
+	time  time.Time
+	value float64
+}
+
+// alertSeriesState is the evaluation state of a metric series of the rule
+type alertSeriesState struct {
+	samples []alertSample
+
+	pendingSince time.Time
+	firing       bool
+	lastNotified time.Time
+}
+
+// increase returns the increase of the samples over the window, the counter resets are handled,
+// false is returned if the samples do not cover the window yet.
+func (s *alertSeriesState) increase(now time.Time, window time.Duration) (float64, bool) {
+	if len(s.samples) == 0 || now.Sub(s.samples[0].time) < window {
+		return 0, false
+	}
+
+	var increase float64
+	for i := 1; i < len(s.samples); i++ {
+		delta := s.samples[i].value - s.samples[i-1].value
+		if delta < 0 {
+			// the counter is reset
+			delta = s.samples[i].value
+		}
+
+		increase += delta
+	}
+
+	return increase, true
+}
+
+func (s *alertSeriesState) addSample(now time.Time, value float64, window time.Duration) {
+	s.samples = append(s.samples, alertSample{time: now, value: value})
+
+	// keep the last sample before the window as the base of the increase
+	var i int
+	for i+1 < len(s.samples) && now.Sub(s.samples[i+1].time) >= window {
+		i++
+	}
+
+	s.samples = s.samples[i:]
+}
+
+// AlertEvent is the notification of the alert state change
+type AlertEvent struct {
+	Rule     string
+	Series   string
+	Value    float64
+	Firing   bool
+	Message  string
+	Duration time.Duration
+}
+
+func (e *AlertEvent) PlainText() string {
+	series := ""
+	if e.Series != "" {
+		series = " {" + e.Series + "}"
+	}
+
+	if !e.Firing {
+		return fmt.Sprintf("✅ alert resolved: %s%s, value = %g", e.Rule, series, e.Value)
+	}
+
+	text := fmt.Sprintf("🚨 alert firing: %s%s, value = %g", e.Rule, series, e.Value)
+	if e.Duration > 0 {
+		text += fmt.Sprintf(" for %s", e.Duration)
+	}
+
+	if e.Message != "" {
+		text += ": " + e.Message
+	}
+
+	return text
+}
+
+// AlertEngine evaluates the alert rules periodically and notifies the alerts through the notification channels
+type AlertEngine struct {
+	config   *AlertConfig
+	gatherer prometheus.Gatherer
+
+	// notify is the notification function, defaults to Notify
+	notify func(obj interface{}, args ...interface{})
+
+	mu     sync.Mutex
+	states map[string]map[string]*alertSeriesState
+}
+
+func NewAlertEngine(config *AlertConfig, gatherer prometheus.Gatherer) *AlertEngine {
+	if gatherer == nil {
+		gatherer = prometheus.DefaultGatherer
+	}
+
+	return &AlertEngine{
+		config:   config,
+		gatherer: gatherer,
+		notify:   Notify,
+		states:   make(map[string]map[string]*alertSeriesState),
+	}
+}
+
+// Run evaluates the rules by the interval until the context is canceled
+func (e *AlertEngine) Run(ctx context.Context) {
+	interval := e.config.Interval.Duration()
+	if interval == 0 {
+		interval = 10 * time.Second
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case now := <-ticker.C:
+			e.Evaluate(now)
+		}
+	}
+}
+
+// Evaluate evaluates all the rules at the given time
+func (e *AlertEngine) Evaluate(now time.Time) {
+	families, err := e.gatherer.Gather()
+	if err != nil {
+		log.WithError(err).Warnf("alert: can not gather the metrics")
+	}
+
+	familyMap := make(map[string]*dto.MetricFamily, len(families))
+	for _, family := range families {
+		familyMap[family.GetName()] = family
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for i := range e.config.Rules {
+		rule := &e.config.Rules[i]
+		e.evaluateRule(rule, collectAlertSeries(rule, familyMap), now)
+	}
+}
+
+// collectAlertSeries returns the values of the series of the rule metric keyed by the series labels
+func collectAlertSeries(rule *AlertRule, families map[string]*dto.MetricFamily) map[string]float64 {
+	series := make(map[string]float64)
+
+	if fn, ok := lookupAlertValue(rule.Metric); ok {
+		if value, ok2 := fn(); ok2 {
+			series[""] = value
+		}
+
+		return series
+	}
+
+	family, ok := families[rule.Metric]
+	if !ok {
+		return series
+	}
+
+	for _, metric := range family.GetMetric() {
+		labels := make(map[string]string, len(metric.GetLabel()))
+		for _, pair := range metric.GetLabel() {
+			labels[pair.GetName()] = pair.GetValue()
+		}
+
+		if !matchAlertLabels(labels, rule.Labels) {
+			continue
+		}
+
+		value, ok := metricValue(family.GetType(), metric)
+		if !ok {
+			continue
+		}
+
+		series[formatAlertLabels(labels)] = value
+	}
+
+	return series
+}
+
+func matchAlertLabels(labels, matchers map[string]string) bool {
+	for k, v := range matchers {
+		if labels[k] != v {
+			return false
+		}
+	}
+
+	return true
+}
+
+func formatAlertLabels(labels map[string]string) string {
+	var pairs []string
+	for k, v := range labels {
+		pairs = append(pairs, k+"="+v)
+	}
+
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// metricValue returns the value of the metric, the sample count is used for the histograms and the summaries
+func metricValue(metricType dto.MetricType, metric *dto.Metric) (float64, bool) {
+	switch metricType {
+	case dto.MetricType_GAUGE:
+		return metric.GetGauge().GetValue(), true
+	case dto.MetricType_COUNTER:
+		return metric.GetCounter().GetValue(), true
+	case dto.MetricType_UNTYPED:
+		return metric.GetUntyped().GetValue(), true
+	case dto.MetricType_HISTOGRAM:
+		return float64(metric.GetHistogram().GetSampleCount()), true
+	case dto.MetricType_SUMMARY:
+		return float64(metric.GetSummary().GetSampleCount()), true
+	}
+
+	return 0, false
+}
+
+func (e *AlertEngine) evaluateRule(rule *AlertRule, series map[string]float64, now time.Time) {
+	states, ok := e.states[rule.Name]
+	if !ok {
+		states = make(map[string]*alertSeriesState)
+		e.states[rule.Name] = states
+	}
+
+	for key, value := range series {
+		state, ok := states[key]
+		if !ok {
+			state = &alertSeriesState{}
+			states[key] = state
+		}
+
+		if rule.Function == AlertFunctionIncrease {
+			state.addSample(now, value, rule.Window.Duration())
+
+			increase, ok := state.increase(now, rule.Window.Duration())
+			if !ok {
+				continue
+			}
+
+			value = increase
+		}
+
+		e.updateState(rule, key, state, value, rule.Operator.Compare(value, rule.Threshold), now)
+	}
+
+	// the series that disappear are resolved
+	for key, state := range states {
+		if _, ok := series[key]; ok {
+			continue
+		}
+
+		if state.firing {
+			e.notify(&AlertEvent{Rule: rule.Name, Series: key, Firing: false})
+		}
+
+		delete(states, key)
+	}
+}
+
+func (e *AlertEngine) updateState(rule *AlertRule, key string, state *alertSeriesState, value float64, matched bool, now time.Time) {
+	if !matched {
+		if state.firing {
+			log.Infof("alert %s {%s} resolved, value = %g", rule.Name, key, value)
+			e.notify(&AlertEvent{Rule: rule.Name, Series: key, Value: value, Firing: false})
+		}
+
+		state.pendingSince = time.Time{}
+		state.firing = false
+		return
+	}
+
+	if state.pendingSince.IsZero() {
+		state.pendingSince = now
+	}
+
+	duration := now.Sub(state.pendingSince)
+	if duration < rule.For.Duration() {
+		return
+	}
+
+	if state.firing {
+		if rule.RepeatInterval == 0 || now.Sub(state.lastNotified) < rule.RepeatInterval.Duration() {
+			return
+		}
+	}
+
+	log.Warnf("alert %s {%s} firing, value = %g", rule.Name, key, value)
+
+	state.firing = true
+	state.lastNotified = now
+	e.notify(&AlertEvent{
+		Rule:     rule.Name,
+		Series:   key,
+		Value:    value,
+		Firing:   true,
+		Message:  rule.Message,
+		Duration: duration,
+	})
+}
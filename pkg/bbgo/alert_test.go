@@ -0,0 +1,155 @@
+package bbgo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+type testAlertNotifier struct {
+	events []*AlertEvent
+}
+
+func (n *testAlertNotifier) Notify(obj interface{}, args ...interface{}) {
+	if event, ok := obj.(*AlertEvent); ok {
+		n.events = append(n.events, event)
+	}
+}
+
+func TestAlertRule_Validate(t *testing.T) {
+	assert.NoError(t, (&AlertRule{Name: "a", Metric: "m", Operator: ">"}).Validate())
+	assert.Error(t, (&AlertRule{Name: "a", Metric: "m", Operator: "=>"}).Validate())
+	assert.Error(t, (&AlertRule{Metric: "m", Operator: ">"}).Validate())
+	assert.Error(t, (&AlertRule{Name: "a", Metric: "m", Operator: "==", Function: AlertFunctionIncrease}).Validate())
+	assert.Error(t, (&AlertRule{Name: "a", Metric: "m", Operator: "==", Function: "rate"}).Validate())
+}
+
+func TestAlertEngine_gauge(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	gauge := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "test_uncovered_exposure_usd"}, []string{"symbol"})
+	registry.MustRegister(gauge)
+
+	engine := NewAlertEngine(&AlertConfig{
+		Rules: []AlertRule{
+			{
+				Name:      "uncovered exposure",
+				Metric:    "test_uncovered_exposure_usd",
+				Labels:    map[string]string{"symbol": "BTCUSDT"},
+				Operator:  AlertOperatorGreaterThan,
+				Threshold: 5000,
+				For:       types.Duration(time.Minute),
+			},
+		},
+	}, registry)
+
+	notifier := &testAlertNotifier{}
+	engine.notify = notifier.Notify
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	gauge.WithLabelValues("BTCUSDT").Set(6000)
+	gauge.WithLabelValues("ETHUSDT").Set(9000)
+
+	engine.Evaluate(now)
+	engine.Evaluate(now.Add(30 * time.Second))
+	assert.Empty(t, notifier.events, "the condition should hold for 1m")
+
+	engine.Evaluate(now.Add(time.Minute))
+	if assert.Len(t, notifier.events, 1) {
+		assert.True(t, notifier.events[0].Firing)
+		assert.Equal(t, "symbol=BTCUSDT", notifier.events[0].Series)
+		assert.Equal(t, 6000.0, notifier.events[0].Value)
+	}
+
+	// no repeated notification
+	engine.Evaluate(now.Add(2 * time.Minute))
+	assert.Len(t, notifier.events, 1)
+
+	gauge.WithLabelValues("BTCUSDT").Set(100)
+	engine.Evaluate(now.Add(3 * time.Minute))
+	if assert.Len(t, notifier.events, 2) {
+		assert.False(t, notifier.events[1].Firing)
+		assert.Contains(t, notifier.events[1].PlainText(), "alert resolved: uncovered exposure")
+	}
+}
+
+func TestAlertEngine_increase(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	counter := prometheus.NewCounter(prometheus.CounterOpts{Name: "test_maker_trades_total"})
+	registry.MustRegister(counter)
+
+	engine := NewAlertEngine(&AlertConfig{
+		Rules: []AlertRule{
+			{
+				Name:           "no fills",
+				Metric:         "test_maker_trades_total",
+				Function:       AlertFunctionIncrease,
+				Window:         types.Duration(30 * time.Minute),
+				Operator:       AlertOperatorEqual,
+				Threshold:      0,
+				RepeatInterval: types.Duration(time.Hour),
+			},
+		},
+	}, registry)
+
+	notifier := &testAlertNotifier{}
+	engine.notify = notifier.Notify
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	counter.Add(10)
+	for i := 0; i <= 30; i++ {
+		engine.Evaluate(now.Add(time.Duration(i) * time.Minute))
+	}
+
+	if assert.Len(t, notifier.events, 1) {
+		assert.True(t, notifier.events[0].Firing)
+	}
+
+	// repeat after the repeat interval
+	for i := 31; i <= 90; i++ {
+		engine.Evaluate(now.Add(time.Duration(i) * time.Minute))
+	}
+	assert.Len(t, notifier.events, 2)
+
+	counter.Inc()
+	engine.Evaluate(now.Add(91 * time.Minute))
+	if assert.Len(t, notifier.events, 3) {
+		assert.False(t, notifier.events[2].Firing)
+		assert.Equal(t, 1.0, notifier.events[2].Value)
+	}
+}
+
+func TestAlertEngine_registeredValue(t *testing.T) {
+	marginLevel := 3.0
+	RegisterAlertValue("test_margin_level", func() (float64, bool) {
+		return marginLevel, true
+	})
+
+	engine := NewAlertEngine(&AlertConfig{
+		Rules: []AlertRule{
+			{
+				Name:      "margin level",
+				Metric:    "test_margin_level",
+				Operator:  AlertOperatorLessThan,
+				Threshold: 2.0,
+				Message:   "repay the debt",
+			},
+		},
+	}, prometheus.NewRegistry())
+
+	notifier := &testAlertNotifier{}
+	engine.notify = notifier.Notify
+
+	now := time.Now()
+	engine.Evaluate(now)
+	assert.Empty(t, notifier.events)
+
+	marginLevel = 1.5
+	engine.Evaluate(now.Add(time.Second))
+	if assert.Len(t, notifier.events, 1) {
+		assert.Equal(t, "🚨 alert firing: margin level, value = 1.5: repay the debt", notifier.events[0].PlainText())
+	}
+}
@@ -0,0 +1,102 @@
+package bbgo
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+const holidayLayout = "2006-01-02"
+
+// TradingCalendar defines the banking holidays and the maintenance windows of the session venue,
+// the strategies can pause quoting on the venue when the session is paused by the calendar.
+type TradingCalendar struct {
+	// Holidays are the banking holidays in the YYYY-MM-DD format,
+	// the fiat deposits and withdrawals are not settled on these days.
+	Holidays []string `json:"holidays,omitempty" yaml:"holidays,omitempty"`
+
+	// MaintenanceWindows are the scheduled maintenance windows of the exchange
+	MaintenanceWindows []types.MaintenanceWindow `json:"maintenanceWindows,omitempty" yaml:"maintenanceWindows,omitempty"`
+
+	// TimeZone is the time zone of the holidays, e.g. America/New_York, defaults to UTC
+	TimeZone string `json:"timeZone,omitempty" yaml:"timeZone,omitempty"`
+}
+
+func (c *TradingCalendar) Validate() error {
+	for _, holiday := range c.Holidays {
+		if _, err := time.Parse(holidayLayout, holiday); err != nil {
+			return fmt.Errorf("invalid holiday %q: %w", holiday, err)
+		}
+	}
+
+	for _, w := range c.MaintenanceWindows {
+		if !w.End.After(w.Start) {
+			return fmt.Errorf("invalid maintenance window %s - %s: the end time should be after the start time", w.Start, w.End)
+		}
+	}
+
+	if _, err := c.location(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c *TradingCalendar) location() (*time.Location, error) {
+	if c.TimeZone == "" {
+		return time.UTC, nil
+	}
+
+	loc, err := time.LoadLocation(c.TimeZone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid calendar time zone %q: %w", c.TimeZone, err)
+	}
+
+	return loc, nil
+}
+
+// IsHoliday checks if the date of the time in the calendar time zone is a banking holiday
+func (c *TradingCalendar) IsHoliday(t time.Time) bool {
+	loc, err := c.location()
+	if err != nil {
+		return false
+	}
+
+	date := t.In(loc).Format(holidayLayout)
+	for _, holiday := range c.Holidays {
+		if holiday == date {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsPaused checks if trading on the venue should be paused at the time, the reason is returned when it's paused
+func (c *TradingCalendar) IsPaused(t time.Time) (bool, string) {
+	if c.IsHoliday(t) {
+		return true, "banking holiday"
+	}
+
+	for _, w := range c.MaintenanceWindows {
+		if w.Contains(t) {
+			if w.Reason != "" {
+				return true, "scheduled maintenance: " + w.Reason
+			}
+
+			return true, "scheduled maintenance"
+		}
+	}
+
+	return false, ""
+}
+
+// TradingPaused checks if trading on the session venue is paused by the trading calendar
+func (session *ExchangeSession) TradingPaused(t time.Time) (bool, string) {
+	if session.Calendar == nil {
+		return false, ""
+	}
+
+	return session.Calendar.IsPaused(t)
+}
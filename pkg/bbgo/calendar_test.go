@@ -0,0 +1,50 @@
+package bbgo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestTradingCalendar_Validate(t *testing.T) {
+	now := time.Date(2024, 12, 25, 0, 0, 0, 0, time.UTC)
+
+	assert.NoError(t, (&TradingCalendar{Holidays: []string{"2024-12-25"}, TimeZone: "America/New_York"}).Validate())
+	assert.Error(t, (&TradingCalendar{Holidays: []string{"12/25/2024"}}).Validate())
+	assert.Error(t, (&TradingCalendar{TimeZone: "Mars/Olympus"}).Validate())
+	assert.Error(t, (&TradingCalendar{MaintenanceWindows: []types.MaintenanceWindow{{Start: now, End: now}}}).Validate())
+}
+
+func TestTradingCalendar_IsPaused(t *testing.T) {
+	calendar := &TradingCalendar{
+		Holidays: []string{"2024-12-25"},
+		TimeZone: "America/New_York",
+		MaintenanceWindows: []types.MaintenanceWindow{
+			{
+				Start:  time.Date(2024, 6, 1, 2, 0, 0, 0, time.UTC),
+				End:    time.Date(2024, 6, 1, 4, 0, 0, 0, time.UTC),
+				Reason: "wallet upgrade",
+			},
+		},
+	}
+	assert.NoError(t, calendar.Validate())
+
+	// 2024-12-25 03:00 UTC is still 2024-12-24 in New York
+	paused, _ := calendar.IsPaused(time.Date(2024, 12, 25, 3, 0, 0, 0, time.UTC))
+	assert.False(t, paused)
+
+	paused, reason := calendar.IsPaused(time.Date(2024, 12, 25, 15, 0, 0, 0, time.UTC))
+	assert.True(t, paused)
+	assert.Equal(t, "banking holiday", reason)
+
+	paused, reason = calendar.IsPaused(time.Date(2024, 6, 1, 3, 0, 0, 0, time.UTC))
+	assert.True(t, paused)
+	assert.Equal(t, "scheduled maintenance: wallet upgrade", reason)
+
+	// the end time is exclusive
+	paused, _ = calendar.IsPaused(time.Date(2024, 6, 1, 4, 0, 0, 0, time.UTC))
+	assert.False(t, paused)
+}
@@ -0,0 +1,209 @@
+package bbgo
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+var ErrComplianceRejected = errors.New("order rejected by compliance")
+
+const tradingHourLayout = "15:04"
+
+// TradingHourRange is a daily time range that allows trading, in the HH:MM format.
+// The range can cross the midnight, e.g. 22:00 - 02:00.
+type TradingHourRange struct {
+	Start string `json:"start" yaml:"start"`
+	End   string `json:"end" yaml:"end"`
+}
+
+// minutes returns the minute of the day of the start and the end time
+func (r TradingHourRange) minutes() (start, end int, err error) {
+	startTime, err := time.Parse(tradingHourLayout, r.Start)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid trading hour start %q: %w", r.Start, err)
+	}
+
+	endTime, err := time.Parse(tradingHourLayout, r.End)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid trading hour end %q: %w", r.End, err)
+	}
+
+	return startTime.Hour()*60 + startTime.Minute(), endTime.Hour()*60 + endTime.Minute(), nil
+}
+
+// Contains checks whether the time of the day is in the range, the end time is exclusive
+func (r TradingHourRange) Contains(t time.Time) bool {
+	start, end, err := r.minutes()
+	if err != nil {
+		return false
+	}
+
+	m := t.Hour()*60 + t.Minute()
+	if start <= end {
+		return m >= start && m < end
+	}
+
+	// the range crosses the midnight
+	return m >= start || m < end
+}
+
+// ComplianceConfig defines the pre-trade compliance checks of the orders submitted to the session
+type ComplianceConfig struct {
+	// BlockedSymbols are the symbols that are not allowed to trade
+	BlockedSymbols []string `json:"blockedSymbols,omitempty" yaml:"blockedSymbols,omitempty"`
+
+	// MaxPositionNotional is the max session position notional of each symbol,
+	// the orders that increase the position beyond the limit are rejected, the orders that reduce the position are always allowed.
+	MaxPositionNotional map[string]fixedpoint.Value `json:"maxPositionNotional,omitempty" yaml:"maxPositionNotional,omitempty"`
+
+	// TradingHours are the daily time ranges that allow trading, trading is allowed all day if it's empty
+	TradingHours []TradingHourRange `json:"tradingHours,omitempty" yaml:"tradingHours,omitempty"`
+
+	// TimeZone is the time zone of the trading hours, e.g. Asia/Taipei, defaults to UTC
+	TimeZone string `json:"timeZone,omitempty" yaml:"timeZone,omitempty"`
+
+	// now is used for testing
+	now func() time.Time
+}
+
+func (c *ComplianceConfig) Validate() error {
+	for _, r := range c.TradingHours {
+		if _, _, err := r.minutes(); err != nil {
+			return err
+		}
+	}
+
+	if _, err := c.location(); err != nil {
+		return err
+	}
+
+	for symbol, notional := range c.MaxPositionNotional {
+		if notional.Sign() < 0 {
+			return fmt.Errorf("max position notional of %s should not be negative, got %v", symbol, notional)
+		}
+	}
+
+	return nil
+}
+
+func (c *ComplianceConfig) location() (*time.Location, error) {
+	if c.TimeZone == "" {
+		return time.UTC, nil
+	}
+
+	loc, err := time.LoadLocation(c.TimeZone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid compliance time zone %q: %w", c.TimeZone, err)
+	}
+
+	return loc, nil
+}
+
+func (c *ComplianceConfig) isBlocked(symbol string) bool {
+	for _, s := range c.BlockedSymbols {
+		if s == symbol {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c *ComplianceConfig) inTradingHours(now time.Time) bool {
+	if len(c.TradingHours) == 0 {
+		return true
+	}
+
+	loc, err := c.location()
+	if err != nil {
+		return false
+	}
+
+	now = now.In(loc)
+	for _, r := range c.TradingHours {
+		if r.Contains(now) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CheckOrder checks the order against the compliance rules of the session,
+// the rejected orders are logged for auditing.
+func (c *ComplianceConfig) CheckOrder(session *ExchangeSession, order types.SubmitOrder) error {
+	err := c.checkOrder(session, order)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"session":  session.Name,
+			"symbol":   order.Symbol,
+			"side":     order.Side,
+			"type":     order.Type,
+			"price":    order.Price.String(),
+			"quantity": order.Quantity.String(),
+			"tag":      order.Tag,
+		}).WithError(err).Warnf("compliance audit: order rejected")
+	}
+
+	return err
+}
+
+func (c *ComplianceConfig) checkOrder(session *ExchangeSession, order types.SubmitOrder) error {
+	if c.isBlocked(order.Symbol) {
+		return errors.Wrapf(ErrComplianceRejected, "symbol %s is blocked", order.Symbol)
+	}
+
+	now := time.Now()
+	if c.now != nil {
+		now = c.now()
+	}
+
+	if !c.inTradingHours(now) {
+		return errors.Wrapf(ErrComplianceRejected, "%s is out of the trading hours", now.Format(time.RFC3339))
+	}
+
+	maxNotional, ok := c.MaxPositionNotional[order.Symbol]
+	if !ok {
+		return nil
+	}
+
+	base := fixedpoint.Zero
+	if position, ok := session.Position(order.Symbol); ok {
+		base = position.GetBase()
+	}
+
+	projected := base
+	switch order.Side {
+	case types.SideTypeBuy:
+		projected = base.Add(order.Quantity)
+	case types.SideTypeSell:
+		projected = base.Sub(order.Quantity)
+	}
+
+	// reducing the position is always allowed
+	if projected.Abs().Compare(base.Abs()) <= 0 {
+		return nil
+	}
+
+	price := order.Price
+	if price.IsZero() {
+		lastPrice, ok := session.LastPrice(order.Symbol)
+		if !ok {
+			return errors.Wrapf(ErrComplianceRejected, "unable to check the position notional, %s last price not found", order.Symbol)
+		}
+
+		price = lastPrice
+	}
+
+	if notional := projected.Abs().Mul(price); notional.Compare(maxNotional) > 0 {
+		return errors.Wrapf(ErrComplianceRejected, "%s position notional %v exceeds the max position notional %v", order.Symbol, notional, maxNotional)
+	}
+
+	return nil
+}
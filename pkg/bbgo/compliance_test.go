@@ -0,0 +1,110 @@
+package bbgo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+	"github.com/c9s/bbgo/pkg/types/mocks"
+)
+
+func TestTradingHourRange_Contains(t *testing.T) {
+	at := func(hour, minute int) time.Time {
+		return time.Date(2024, 1, 1, hour, minute, 0, 0, time.UTC)
+	}
+
+	r := TradingHourRange{Start: "09:00", End: "17:30"}
+	assert.False(t, r.Contains(at(8, 59)))
+	assert.True(t, r.Contains(at(9, 0)))
+	assert.True(t, r.Contains(at(17, 29)))
+	assert.False(t, r.Contains(at(17, 30)))
+
+	overnight := TradingHourRange{Start: "22:00", End: "02:00"}
+	assert.True(t, overnight.Contains(at(23, 0)))
+	assert.True(t, overnight.Contains(at(1, 59)))
+	assert.False(t, overnight.Contains(at(12, 0)))
+}
+
+func TestComplianceConfig_Validate(t *testing.T) {
+	assert.NoError(t, (&ComplianceConfig{TradingHours: []TradingHourRange{{Start: "09:00", End: "17:00"}}}).Validate())
+	assert.Error(t, (&ComplianceConfig{TradingHours: []TradingHourRange{{Start: "9am", End: "17:00"}}}).Validate())
+	assert.Error(t, (&ComplianceConfig{TimeZone: "Mars/Olympus"}).Validate())
+}
+
+func TestExchangeSession_FormatOrder_Compliance(t *testing.T) {
+	number := fixedpoint.MustNewFromString
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	market := getTestMarket()
+	mockEx := mocks.NewMockExchange(mockCtrl)
+	mockEx.EXPECT().NewStream().Return(&types.StandardStream{}).Times(2)
+	session := NewExchangeSession("test", mockEx)
+	session.SetMarkets(types.MarketMap{market.Symbol: market, "ETHUSDT": {Symbol: "ETHUSDT"}})
+	session.lastPrices[market.Symbol] = number("20000")
+
+	position, _ := session.Position(market.Symbol)
+	position.Base = number("0.4")
+
+	session.Compliance = &ComplianceConfig{
+		BlockedSymbols:      []string{"ETHUSDT"},
+		MaxPositionNotional: map[string]fixedpoint.Value{market.Symbol: number("10000")},
+		TradingHours:        []TradingHourRange{{Start: "09:00", End: "17:00"}},
+		now: func() time.Time {
+			return time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+		},
+	}
+
+	newOrder := func(symbol string, side types.SideType, orderType types.OrderType, quantity string) types.SubmitOrder {
+		return types.SubmitOrder{
+			Symbol:   symbol,
+			Side:     side,
+			Type:     orderType,
+			Price:    number("20000"),
+			Quantity: number(quantity),
+		}
+	}
+
+	t.Run("blocked symbol", func(t *testing.T) {
+		_, err := session.FormatOrder(newOrder("ETHUSDT", types.SideTypeBuy, types.OrderTypeLimit, "1"))
+		assert.True(t, errors.Is(err, ErrComplianceRejected))
+	})
+
+	t.Run("within max position notional", func(t *testing.T) {
+		_, err := session.FormatOrder(newOrder(market.Symbol, types.SideTypeBuy, types.OrderTypeLimit, "0.1"))
+		assert.NoError(t, err)
+	})
+
+	t.Run("exceeds max position notional", func(t *testing.T) {
+		_, err := session.FormatOrder(newOrder(market.Symbol, types.SideTypeBuy, types.OrderTypeLimit, "0.2"))
+		assert.True(t, errors.Is(err, ErrComplianceRejected))
+	})
+
+	t.Run("market order uses the last price", func(t *testing.T) {
+		order := newOrder(market.Symbol, types.SideTypeBuy, types.OrderTypeMarket, "0.2")
+		order.Price = fixedpoint.Zero
+		_, err := session.FormatOrder(order)
+		assert.True(t, errors.Is(err, ErrComplianceRejected))
+	})
+
+	t.Run("reducing the position is allowed", func(t *testing.T) {
+		_, err := session.FormatOrder(newOrder(market.Symbol, types.SideTypeSell, types.OrderTypeLimit, "0.6"))
+		assert.NoError(t, err)
+	})
+
+	t.Run("out of trading hours", func(t *testing.T) {
+		session.Compliance.now = func() time.Time {
+			return time.Date(2024, 1, 1, 18, 0, 0, 0, time.UTC)
+		}
+
+		_, err := session.FormatOrder(newOrder(market.Symbol, types.SideTypeSell, types.OrderTypeLimit, "0.1"))
+		assert.True(t, errors.Is(err, ErrComplianceRejected))
+		assert.ErrorContains(t, err, "trading hours")
+	})
+}
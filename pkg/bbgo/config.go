@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"path/filepath"
 	"reflect"
 	"runtime"
 	"strings"
@@ -15,6 +16,8 @@ import (
 	"github.com/c9s/bbgo/pkg/datatype"
 	"github.com/c9s/bbgo/pkg/dynamic"
 	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/risk"
+	"github.com/c9s/bbgo/pkg/risk/valueatrisk"
 	"github.com/c9s/bbgo/pkg/service"
 	"github.com/c9s/bbgo/pkg/types"
 )
@@ -151,6 +154,13 @@ type Backtest struct {
 
 	// sync 1 second interval KLines
 	SyncSecKLines bool `json:"syncSecKLines,omitempty" yaml:"syncSecKLines,omitempty"`
+
+	// KLineCacheDir is the directory of the memory mapped kline cache files shared by the back-test runs,
+	// the klines are queried from the database once and then loaded from the cache files.
+	KLineCacheDir string `json:"klineCacheDir,omitempty" yaml:"klineCacheDir,omitempty"`
+
+	// Seed seeds the random number generator for reproducible back-test results, 0 keeps the random seed.
+	Seed int64 `json:"seed,omitempty" yaml:"seed,omitempty"`
 }
 
 func (b *Backtest) GetAccount(n string) BacktestAccount {
@@ -172,6 +182,10 @@ type BacktestAccount struct {
 	TakerFeeRate fixedpoint.Value `json:"takerFeeRate,omitempty" yaml:"takerFeeRate,omitempty"`
 
 	Balances BacktestAccountBalanceMap `json:"balances" yaml:"balances"`
+
+	// BorrowInterestRates are the hourly interest rates of the assets borrowed through the margin borrow api,
+	// the interest of the borrowed balances is accrued every hour, the assets without a rate are borrowed without interest.
+	BorrowInterestRates map[string]fixedpoint.Value `json:"borrowInterestRates,omitempty" yaml:"borrowInterestRates,omitempty"`
 }
 
 var DefaultBacktestAccount = BacktestAccount{
@@ -355,6 +369,9 @@ type Config struct {
 	// Deprecated: use BuildConfig instead
 	Imports []string `json:"imports,omitempty" yaml:"imports,omitempty"`
 
+	// Plugins is the list of the strategy plugin (.so) paths, relative paths are resolved from the config file directory
+	Plugins []string `json:"plugins,omitempty" yaml:"plugins,omitempty"`
+
 	Backtest *Backtest `json:"backtest,omitempty" yaml:"backtest,omitempty"`
 
 	Sync *SyncConfig `json:"sync,omitempty" yaml:"sync,omitempty"`
@@ -373,6 +390,24 @@ type Config struct {
 
 	RiskControls *RiskControls `json:"riskControls,omitempty" yaml:"riskControls,omitempty"`
 
+	// PortfolioRisk is the global exposure limits aggregated from the positions of all the strategies
+	PortfolioRisk *risk.ExposureLimitConfig `json:"portfolioRisk,omitempty" yaml:"portfolioRisk,omitempty"`
+
+	// StrategyBudgets is the execution budgets of the strategies, e.g. the max callback time and the max orders per minute
+	StrategyBudgets *StrategyBudgetConfig `json:"strategyBudgets,omitempty" yaml:"strategyBudgets,omitempty"`
+
+	// ValueAtRisk computes the value-at-risk and the stress scenario PnLs of the aggregate position of the strategies
+	ValueAtRisk *valueatrisk.Config `json:"valueAtRisk,omitempty" yaml:"valueAtRisk,omitempty"`
+
+	// AccountStatements generates the daily account statements of the sessions
+	AccountStatements *AccountStatementConfig `json:"accountStatements,omitempty" yaml:"accountStatements,omitempty"`
+
+	// ShutdownAudit audits the remaining open orders and positions of the sessions after the strategies are shut down
+	ShutdownAudit *ShutdownAuditConfig `json:"shutdownAudit,omitempty" yaml:"shutdownAudit,omitempty"`
+
+	// Alerts are the alert rules evaluated over the internal metrics, the alerts are sent to the notification channels
+	Alerts *AlertConfig `json:"alerts,omitempty" yaml:"alerts,omitempty"`
+
 	Logging *LoggingConfig `json:"logging,omitempty"`
 
 	ExchangeStrategies      []ExchangeStrategyMount `json:"-" yaml:"-"`
@@ -551,6 +586,10 @@ func Load(configFile string, loadStrategies bool) (*Config, error) {
 	}
 
 	if loadStrategies {
+		if err := LoadPlugins(resolvePluginPaths(configFile, config.Plugins)...); err != nil {
+			return nil, err
+		}
+
 		if err := loadExchangeStrategies(&config, stash); err != nil {
 			return nil, err
 		}
@@ -563,6 +602,20 @@ func Load(configFile string, loadStrategies bool) (*Config, error) {
 	return &config, nil
 }
 
+// resolvePluginPaths resolves the relative plugin paths from the directory of the config file
+func resolvePluginPaths(configFile string, paths []string) (resolved []string) {
+	dir := filepath.Dir(configFile)
+	for _, p := range paths {
+		if !filepath.IsAbs(p) {
+			p = filepath.Join(dir, p)
+		}
+
+		resolved = append(resolved, p)
+	}
+
+	return resolved
+}
+
 func loadCrossExchangeStrategies(config *Config, stash Stash) (err error) {
 	exchangeStrategiesConf, ok := stash["crossExchangeStrategies"]
 	if !ok {
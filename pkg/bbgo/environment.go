@@ -25,6 +25,7 @@ import (
 	"github.com/c9s/bbgo/pkg/interact"
 	"github.com/c9s/bbgo/pkg/notifier/slacknotifier"
 	"github.com/c9s/bbgo/pkg/notifier/telegramnotifier"
+	"github.com/c9s/bbgo/pkg/risk/valueatrisk"
 	"github.com/c9s/bbgo/pkg/service"
 	googleservice "github.com/c9s/bbgo/pkg/service/google"
 	"github.com/c9s/bbgo/pkg/slack/slacklog"
@@ -80,23 +81,27 @@ const (
 // Environment presents the real exchange data layer
 type Environment struct {
 	// built-in service
-	DatabaseService   *service.DatabaseService
-	OrderService      *service.OrderService
-	TradeService      *service.TradeService
-	ProfitService     *service.ProfitService
-	PositionService   *service.PositionService
-	BacktestService   *service.BacktestService
-	RewardService     *service.RewardService
-	MarginService     *service.MarginService
-	SyncService       *service.SyncService
-	AccountService    *service.AccountService
-	WithdrawService   *service.WithdrawService
-	DepositService    *service.DepositService
-	PersistentService *service.PersistenceServiceFacade
+	DatabaseService    *service.DatabaseService
+	OrderService       *service.OrderService
+	TradeService       *service.TradeService
+	ProfitService      *service.ProfitService
+	PositionService    *service.PositionService
+	RegimeStatsService *service.RegimeStatsService
+	BacktestService    *service.BacktestService
+	RewardService      *service.RewardService
+	MarginService      *service.MarginService
+	SyncService        *service.SyncService
+	AccountService     *service.AccountService
+	WithdrawService    *service.WithdrawService
+	DepositService     *service.DepositService
+	PersistentService  *service.PersistenceServiceFacade
 
 	// external services
 	GoogleSpreadSheetService *googleservice.SpreadSheetService
 
+	// Watchdog monitors the heartbeats of the strategy workers and the goroutine count
+	Watchdog *Watchdog
+
 	// startTime is the time of start point (which is used in the backtest)
 	startTime time.Time
 
@@ -112,6 +117,12 @@ type Environment struct {
 	environmentConfig *EnvironmentConfig
 
 	sessions map[string]*ExchangeSession
+
+	// valueAtRisk is the value-at-risk monitor of the aggregate portfolio, it's configured by the trader
+	valueAtRisk *valueatrisk.Monitor
+
+	// accountStatements generates the daily account statements of the sessions, it's configured by the trader
+	accountStatements *AccountStatementService
 }
 
 func NewEnvironment() *Environment {
@@ -123,6 +134,7 @@ func NewEnvironment() *Environment {
 		startTime:     now,
 
 		syncStatus: SyncNotStarted,
+		Watchdog:   NewWatchdog(DefaultWatchdogMaxGoroutines),
 	}
 }
 
@@ -217,6 +229,7 @@ func (environ *Environment) ConfigureDatabaseDriver(ctx context.Context, driver
 	environ.AccountService = &service.AccountService{DB: db}
 	environ.ProfitService = &service.ProfitService{DB: db}
 	environ.PositionService = &service.PositionService{DB: db}
+	environ.RegimeStatsService = &service.RegimeStatsService{DB: db}
 	environ.MarginService = &service.MarginService{DB: db}
 	environ.WithdrawService = &service.WithdrawService{DB: db}
 	environ.DepositService = &service.DepositService{DB: db}
@@ -306,6 +319,12 @@ func (environ *Environment) Init(ctx context.Context) (err error) {
 				return err
 			}
 		}
+
+		if environ.PersistentService != nil && !IsBackTesting {
+			if err = session.restoreKillSwitch(environ.PersistentService.Get()); err != nil {
+				return err
+			}
+		}
 	}
 
 	return
@@ -319,6 +338,10 @@ func (environ *Environment) Start(ctx context.Context) (err error) {
 			return err
 		}
 	}
+
+	if !IsBackTesting {
+		go environ.Watchdog.Run(ctx, DefaultWatchdogCheckInterval)
+	}
 	return
 }
 
@@ -430,6 +453,10 @@ func (environ *Environment) Connect(ctx context.Context) error {
 			}
 		}
 
+		if !IsBackTesting {
+			session.runSyntheticKLines(ctx)
+		}
+
 		logger.Infof("connecting %s market data stream...", session.Name)
 		if err := session.MarketDataStream.Connect(ctx); err != nil {
 			return err
@@ -441,6 +468,11 @@ func (environ *Environment) Connect(ctx context.Context) error {
 				return err
 			}
 		}
+
+		// the strategies are running at this point, so that the halted symbols can be wound down
+		if session.symbolStatusMonitor != nil && !IsBackTesting {
+			go session.symbolStatusMonitor.Run(ctx)
+		}
 	}
 
 	return nil
@@ -620,6 +652,22 @@ func (environ *Environment) RecordProfit(profit types.Profit) {
 	}
 }
 
+// RecordRegimeStats records the performance attribution of the strategy by the market regime
+func (environ *Environment) RecordRegimeStats(stats types.RegimeStats) {
+	// skip for back-test
+	if environ.BacktestService != nil {
+		return
+	}
+
+	if environ.DatabaseService == nil || environ.RegimeStatsService == nil {
+		return
+	}
+
+	if err := environ.RegimeStatsService.Insert(stats); err != nil {
+		log.WithError(err).Errorf("can not insert regime stats record: %+v", stats)
+	}
+}
+
 func (environ *Environment) SyncSession(ctx context.Context, session *ExchangeSession, defaultSymbols ...string) error {
 	if environ.SyncService == nil {
 		return nil
@@ -0,0 +1,100 @@
+package bbgo
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// FundingRateFeed polls the predicted funding rate of the perpetual futures market
+type FundingRateFeed struct {
+	Symbol string
+
+	service  types.FundingRateService
+	interval time.Duration
+
+	mu           sync.Mutex
+	premiumIndex *types.PremiumIndex
+}
+
+func NewFundingRateFeed(service types.FundingRateService, symbol string, interval time.Duration) *FundingRateFeed {
+	return &FundingRateFeed{
+		Symbol:   symbol,
+		service:  service,
+		interval: interval,
+	}
+}
+
+// FundingRateFeed returns the funding rate feed of the symbol,
+// ok is false when the session is not a futures session or the exchange does not provide the funding rate.
+func (session *ExchangeSession) FundingRateFeed(symbol string, interval time.Duration) (*FundingRateFeed, bool) {
+	if !session.Futures {
+		return nil, false
+	}
+
+	service, ok := session.Exchange.(types.FundingRateService)
+	if !ok {
+		return nil, false
+	}
+
+	return NewFundingRateFeed(service, symbol, interval), true
+}
+
+// Update queries the premium index and updates the funding rate
+func (f *FundingRateFeed) Update(ctx context.Context) error {
+	premiumIndex, err := f.service.QueryPremiumIndex(ctx, f.Symbol)
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	f.premiumIndex = premiumIndex
+	f.mu.Unlock()
+	return nil
+}
+
+// Run updates the funding rate on every interval until the context is canceled
+func (f *FundingRateFeed) Run(ctx context.Context) {
+	if err := f.Update(ctx); err != nil {
+		log.WithError(err).Errorf("unable to query %s funding rate", f.Symbol)
+	}
+
+	ticker := time.NewTicker(f.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-ticker.C:
+			if err := f.Update(ctx); err != nil {
+				log.WithError(err).Errorf("unable to query %s funding rate", f.Symbol)
+			}
+		}
+	}
+}
+
+// FundingRate returns the last predicted funding rate, ok is false before the first update
+func (f *FundingRateFeed) FundingRate() (fixedpoint.Value, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.premiumIndex == nil {
+		return fixedpoint.Zero, false
+	}
+
+	return f.premiumIndex.LastFundingRate, true
+}
+
+// PremiumIndex returns the last premium index
+func (f *FundingRateFeed) PremiumIndex() (*types.PremiumIndex, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.premiumIndex, f.premiumIndex != nil
+}
@@ -0,0 +1,32 @@
+package bbgo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+type testFundingRateService struct {
+	fundingRate fixedpoint.Value
+}
+
+func (s *testFundingRateService) QueryPremiumIndex(_ context.Context, symbol string) (*types.PremiumIndex, error) {
+	return &types.PremiumIndex{Symbol: symbol, LastFundingRate: s.fundingRate}, nil
+}
+
+func TestFundingRateFeed(t *testing.T) {
+	service := &testFundingRateService{fundingRate: fixedpoint.MustNewFromString("0.0001")}
+	feed := NewFundingRateFeed(service, "BTCUSDT", 0)
+
+	_, ok := feed.FundingRate()
+	assert.False(t, ok)
+
+	assert.NoError(t, feed.Update(context.Background()))
+	fundingRate, ok := feed.FundingRate()
+	assert.True(t, ok)
+	assert.Equal(t, "0.0001", fundingRate.String())
+}
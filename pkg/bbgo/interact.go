@@ -121,6 +121,42 @@ func (it *CoreInteraction) Commands(i *interact.Interact) {
 		return nil
 	})
 
+	i.PrivateCommand("/resetkillswitch", "Reset the tripped session kill switch", func(reply interact.Reply) error {
+		var tripped int
+		for name, session := range it.environment.Sessions() {
+			if state := session.KillSwitch().State(); state.Tripped {
+				reply.AddButton(name, "session", name)
+				tripped++
+			}
+		}
+
+		if tripped == 0 {
+			reply.Message("No session kill switch is tripped")
+			return nil
+		}
+
+		reply.Message("Please select the session to reset")
+		return nil
+	}).Next(func(sessionName string, reply interact.Reply) error {
+		session, ok := it.environment.Session(sessionName)
+		if !ok {
+			reply.Message(fmt.Sprintf("Session %s not found", sessionName))
+			return fmt.Errorf("session %s not found", sessionName)
+		}
+
+		if kc, ok := reply.(interact.KeyboardController); ok {
+			kc.RemoveKeyboard()
+		}
+
+		if err := session.ResetKillSwitch(); err != nil {
+			reply.Message(fmt.Sprintf("Failed to reset the kill switch, %s", err.Error()))
+			return err
+		}
+
+		reply.Message(fmt.Sprintf("Session %s kill switch is reset, the order submissions are allowed.", sessionName))
+		return nil
+	})
+
 	i.PrivateCommand("/position", "Show Position", func(reply interact.Reply) error {
 		// it.trader.exchangeStrategies
 		// send symbol options
@@ -436,6 +472,40 @@ func (it *CoreInteraction) Commands(i *interact.Interact) {
 		return nil
 	})
 
+	i.PrivateCommand("/winddown", "Wind Down Strategy", func(reply interact.Reply) error {
+		if strategies, err := filterStrategiesByInterface(it.exchangeStrategies, (*WindDowner)(nil)); err == nil && len(strategies) > 0 {
+			reply.AddMultipleButtons(generateStrategyButtonsForm(strategies))
+			reply.Message("Please choose one strategy")
+		} else {
+			reply.Message("No strategy supports WindDowner")
+		}
+		return nil
+	}).Next(func(signature string, reply interact.Reply) error {
+		strategy, ok := it.exchangeStrategies[signature]
+		if !ok {
+			reply.Message("Strategy not found")
+			return fmt.Errorf("strategy %s not found", signature)
+		}
+
+		controller, implemented := strategy.(WindDowner)
+		if !implemented {
+			reply.Message(fmt.Sprintf("Strategy %s does not support WindDowner", signature))
+			return fmt.Errorf("strategy %s does not implement WindDowner", signature)
+		}
+
+		if kc, ok := reply.(interact.KeyboardController); ok {
+			kc.RemoveKeyboard()
+		}
+
+		if err := controller.WindDown(context.Background()); err != nil {
+			reply.Message(fmt.Sprintf("Failed to wind down the strategy, %s", err.Error()))
+			return err
+		}
+
+		reply.Message(fmt.Sprintf("Strategy %s is winding down, it will stop once the position is flat.", signature))
+		return nil
+	})
+
 	// Position updater
 	i.PrivateCommand("/modifyposition", "Modify Strategy Position", func(reply interact.Reply) error {
 		// it.trader.exchangeStrategies
@@ -0,0 +1,245 @@
+package bbgo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"go.uber.org/multierr"
+
+	"github.com/c9s/bbgo/pkg/service"
+)
+
+// ErrKillSwitchTripped is returned when an order is submitted to the session whose kill switch is tripped
+var ErrKillSwitchTripped = errors.New("kill switch is tripped")
+
+const killSwitchPersistenceID = "kill-switch"
+
+// KillSwitchState is the state of the session kill switch, it's persisted so that a restart does not reset it
+type KillSwitchState struct {
+	Tripped   bool      `json:"tripped"`
+	Reason    string    `json:"reason,omitempty"`
+	TrippedAt time.Time `json:"trippedAt,omitempty"`
+}
+
+// KillSwitch blocks the order submissions of the session once it's tripped by any risk module,
+// it can only be reset by the operator explicitly.
+type KillSwitch struct {
+	mu    sync.Mutex
+	state KillSwitchState
+
+	tripCallbacks  []func(state KillSwitchState)
+	resetCallbacks []func()
+}
+
+func (k *KillSwitch) State() KillSwitchState {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	return k.state
+}
+
+func (k *KillSwitch) IsTripped() bool {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	return k.state.Tripped
+}
+
+// trip trips the kill switch, it returns false if the kill switch is already tripped
+func (k *KillSwitch) trip(reason string, now time.Time) bool {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if k.state.Tripped {
+		return false
+	}
+
+	k.state = KillSwitchState{Tripped: true, Reason: reason, TrippedAt: now}
+	return true
+}
+
+// reset resets the kill switch, it returns false if the kill switch is not tripped
+func (k *KillSwitch) reset() bool {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if !k.state.Tripped {
+		return false
+	}
+
+	k.state = KillSwitchState{}
+	return true
+}
+
+func (k *KillSwitch) OnTrip(cb func(state KillSwitchState)) {
+	k.tripCallbacks = append(k.tripCallbacks, cb)
+}
+
+func (k *KillSwitch) EmitTrip(state KillSwitchState) {
+	for _, cb := range k.tripCallbacks {
+		cb(state)
+	}
+}
+
+func (k *KillSwitch) OnReset(cb func()) {
+	k.resetCallbacks = append(k.resetCallbacks, cb)
+}
+
+func (k *KillSwitch) EmitReset() {
+	for _, cb := range k.resetCallbacks {
+		cb()
+	}
+}
+
+// KillSwitch returns the kill switch of the session, the strategies can register the trip and the reset callbacks on it
+func (session *ExchangeSession) KillSwitch() *KillSwitch {
+	return &session.killSwitch
+}
+
+// checkKillSwitch returns ErrKillSwitchTripped if the kill switch of the session is tripped
+func (session *ExchangeSession) checkKillSwitch() error {
+	state := session.killSwitch.State()
+	if state.Tripped {
+		return fmt.Errorf("session %s: %w: %s", session.Name, ErrKillSwitchTripped, state.Reason)
+	}
+
+	return nil
+}
+
+// TripKillSwitch trips the kill switch of the session, blocks the further order submissions,
+// and cancels all the open orders of the session symbols across all the strategies.
+func (session *ExchangeSession) TripKillSwitch(ctx context.Context, reason string) error {
+	if !session.killSwitch.trip(reason, time.Now()) {
+		return nil
+	}
+
+	state := session.killSwitch.State()
+	session.saveKillSwitch(state)
+
+	log.Errorf("session %s kill switch is tripped: %s", session.Name, reason)
+	Notify("🛑 session %s kill switch is tripped: %s, all the open orders are being canceled", session.Name, reason)
+
+	session.killSwitch.EmitTrip(state)
+
+	return session.cancelAllOpenOrders(ctx)
+}
+
+// ResetKillSwitch resets the tripped kill switch of the session, the order submissions are allowed again
+func (session *ExchangeSession) ResetKillSwitch() error {
+	if !session.killSwitch.reset() {
+		return nil
+	}
+
+	session.saveKillSwitch(KillSwitchState{})
+
+	log.Warnf("session %s kill switch is reset", session.Name)
+	Notify("session %s kill switch is reset by the operator", session.Name)
+
+	session.killSwitch.EmitReset()
+	return nil
+}
+
+// cancelAllOpenOrders cancels the open orders of all the symbols used by the session
+func (session *ExchangeSession) cancelAllOpenOrders(ctx context.Context) (err error) {
+	if session.Exchange == nil {
+		return nil
+	}
+
+	for symbol := range session.usedSymbols {
+		openOrders, err2 := session.Exchange.QueryOpenOrders(ctx, symbol)
+		if err2 != nil {
+			err = multierr.Append(err, fmt.Errorf("unable to query %s open orders: %w", symbol, err2))
+			continue
+		}
+
+		if len(openOrders) == 0 {
+			continue
+		}
+
+		log.Warnf("session %s kill switch: canceling %d %s open orders", session.Name, len(openOrders), symbol)
+		if err2 := session.Exchange.CancelOrders(ctx, openOrders...); err2 != nil {
+			err = multierr.Append(err, fmt.Errorf("unable to cancel %s open orders: %w", symbol, err2))
+		}
+	}
+
+	return err
+}
+
+func (session *ExchangeSession) saveKillSwitch(state KillSwitchState) {
+	if session.killSwitchStore == nil {
+		return
+	}
+
+	if err := session.killSwitchStore.Save(state); err != nil {
+		log.WithError(err).Errorf("unable to save the kill switch state of session %s", session.Name)
+	}
+}
+
+// restoreKillSwitch loads the persisted kill switch state, so that the tripped kill switch is kept tripped after restarting
+func (session *ExchangeSession) restoreKillSwitch(persistence service.PersistenceService) error {
+	session.killSwitchStore = persistence.NewStore(killSwitchPersistenceID, session.Name)
+
+	var state KillSwitchState
+	if err := session.killSwitchStore.Load(&state); err != nil {
+		if errors.Is(err, service.ErrPersistenceNotExists) {
+			return nil
+		}
+
+		return err
+	}
+
+	if state.Tripped && session.killSwitch.trip(state.Reason, state.TrippedAt) {
+		log.Warnf("session %s kill switch is still tripped since %s: %s, it must be reset by the operator",
+			session.Name, state.TrippedAt, state.Reason)
+	}
+
+	return nil
+}
+
+// TripKillSwitch trips the kill switch of the session, or all the sessions if the session name is empty
+func (environ *Environment) TripKillSwitch(ctx context.Context, sessionName string, reason string) error {
+	sessions, err := environ.killSwitchSessions(sessionName)
+	if err != nil {
+		return err
+	}
+
+	for _, session := range sessions {
+		err = multierr.Append(err, session.TripKillSwitch(ctx, reason))
+	}
+
+	return err
+}
+
+// ResetKillSwitch resets the kill switch of the session, or all the sessions if the session name is empty
+func (environ *Environment) ResetKillSwitch(sessionName string) error {
+	sessions, err := environ.killSwitchSessions(sessionName)
+	if err != nil {
+		return err
+	}
+
+	for _, session := range sessions {
+		err = multierr.Append(err, session.ResetKillSwitch())
+	}
+
+	return err
+}
+
+func (environ *Environment) killSwitchSessions(sessionName string) ([]*ExchangeSession, error) {
+	if sessionName == "" {
+		var sessions []*ExchangeSession
+		for _, session := range environ.Sessions() {
+			sessions = append(sessions, session)
+		}
+
+		return sessions, nil
+	}
+
+	session, ok := environ.Session(sessionName)
+	if !ok {
+		return nil, fmt.Errorf("session %s not found", sessionName)
+	}
+
+	return []*ExchangeSession{session}, nil
+}
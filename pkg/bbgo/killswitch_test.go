@@ -0,0 +1,85 @@
+package bbgo
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/service"
+	"github.com/c9s/bbgo/pkg/types"
+	"github.com/c9s/bbgo/pkg/types/mocks"
+)
+
+func TestExchangeSession_TripKillSwitch(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	openOrders := []types.Order{
+		{SubmitOrder: types.SubmitOrder{Symbol: "BTCUSDT", Side: types.SideTypeBuy}, OrderID: 1},
+		{SubmitOrder: types.SubmitOrder{Symbol: "BTCUSDT", Side: types.SideTypeSell}, OrderID: 2},
+	}
+
+	mockExchange := mocks.NewMockExchange(mockCtrl)
+	mockExchange.EXPECT().QueryOpenOrders(gomock.Any(), "BTCUSDT").Return(openOrders, nil)
+	mockExchange.EXPECT().CancelOrders(gomock.Any(), openOrders[0], openOrders[1]).Return(nil)
+
+	session := &ExchangeSession{
+		Name:        "binance",
+		Exchange:    mockExchange,
+		usedSymbols: map[string]struct{}{"BTCUSDT": {}},
+		markets: map[string]types.Market{
+			"BTCUSDT": types.Market{Symbol: "BTCUSDT", PricePrecision: 2, VolumePrecision: 6},
+		},
+	}
+
+	persistence := service.NewMemoryService()
+	assert.NoError(t, session.restoreKillSwitch(persistence))
+
+	var trippedReason string
+	session.KillSwitch().OnTrip(func(state KillSwitchState) {
+		trippedReason = state.Reason
+	})
+
+	assert.NoError(t, session.TripKillSwitch(context.Background(), "max drawdown"))
+	assert.Equal(t, "max drawdown", trippedReason)
+	assert.True(t, session.KillSwitch().IsTripped())
+
+	// tripping again does not cancel the orders again
+	assert.NoError(t, session.TripKillSwitch(context.Background(), "another reason"))
+	assert.Equal(t, "max drawdown", session.KillSwitch().State().Reason)
+
+	_, err := session.FormatOrder(types.SubmitOrder{
+		Symbol:   "BTCUSDT",
+		Side:     types.SideTypeBuy,
+		Type:     types.OrderTypeLimit,
+		Quantity: fixedpoint.One,
+		Price:    fixedpoint.NewFromFloat(40000.0),
+	})
+	assert.True(t, errors.Is(err, ErrKillSwitchTripped))
+
+	// the tripped state is restored after restarting
+	restarted := &ExchangeSession{Name: "binance"}
+	assert.NoError(t, restarted.restoreKillSwitch(persistence))
+	assert.True(t, restarted.KillSwitch().IsTripped())
+	assert.Equal(t, "max drawdown", restarted.KillSwitch().State().Reason)
+
+	assert.NoError(t, session.ResetKillSwitch())
+	assert.False(t, session.KillSwitch().IsTripped())
+
+	_, err = session.FormatOrder(types.SubmitOrder{
+		Symbol:   "BTCUSDT",
+		Side:     types.SideTypeBuy,
+		Type:     types.OrderTypeLimit,
+		Quantity: fixedpoint.One,
+		Price:    fixedpoint.NewFromFloat(40000.0),
+	})
+	assert.NoError(t, err)
+
+	restarted = &ExchangeSession{Name: "binance"}
+	assert.NoError(t, restarted.restoreKillSwitch(persistence))
+	assert.False(t, restarted.KillSwitch().IsTripped())
+}
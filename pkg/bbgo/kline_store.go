@@ -0,0 +1,315 @@
+package bbgo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/c9s/bbgo/pkg/cache"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// klineStoreQueryLimit is the max number of the klines of one kline query
+const klineStoreQueryLimit = 1000
+
+// klineStorePersistInterval is the min interval between two writes of the kline cache file of a series
+const klineStorePersistInterval = time.Minute
+
+type klineSeriesKey struct {
+	symbol   string
+	interval types.Interval
+}
+
+// klineSeries is the klines of one symbol and one interval
+type klineSeries struct {
+	mu sync.Mutex
+
+	key    klineSeriesKey
+	klines types.KLineWindow
+
+	// loaded is set after the klines are loaded from the cache file
+	loaded bool
+
+	// depth is the max number of the klines that are backfilled from the exchange
+	depth int
+
+	lastPersistedAt time.Time
+
+	subscriberID int
+	subscribers  map[int]func(k types.KLine)
+}
+
+// KLineStore is the session-scoped kline store, the klines of a symbol and an interval are backfilled from the exchange once,
+// and then kept up-to-date by the closed klines from the market data stream, so that the strategies and the indicators
+// share the same klines instead of querying the exchange again. The closed klines are fanned out to all the subscribers.
+//
+// When the persistence is enabled, the klines are written into the local cache directory,
+// so that only the klines after the cached ones are queried on the next start.
+type KLineStore struct {
+	exchange types.ExchangePublic
+	persist  bool
+
+	mu     sync.Mutex
+	series map[klineSeriesKey]*klineSeries
+
+	// now is used for testing
+	now func() time.Time
+}
+
+func NewKLineStore(exchange types.ExchangePublic, persist bool) *KLineStore {
+	return &KLineStore{
+		exchange: exchange,
+		persist:  persist,
+		series:   make(map[klineSeriesKey]*klineSeries),
+		now:      time.Now,
+	}
+}
+
+// BindStream keeps the klines up-to-date with the closed klines of the stream
+func (s *KLineStore) BindStream(stream types.Stream) {
+	stream.OnKLineClosed(s.AddKLine)
+}
+
+func (s *KLineStore) getSeries(symbol string, interval types.Interval) *klineSeries {
+	key := klineSeriesKey{symbol: symbol, interval: interval}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	series, ok := s.series[key]
+	if !ok {
+		series = &klineSeries{
+			key:         key,
+			subscribers: make(map[int]func(k types.KLine)),
+		}
+		s.series[key] = series
+	}
+
+	return series
+}
+
+// KLines returns the last closed klines of the symbol and the interval, up to the limit.
+// The klines are queried from the exchange only for the first time, or when the klines are not enough or outdated.
+func (s *KLineStore) KLines(ctx context.Context, symbol string, interval types.Interval, limit int) ([]types.KLine, error) {
+	series := s.getSeries(symbol, interval)
+
+	series.mu.Lock()
+	defer series.mu.Unlock()
+
+	if !series.loaded {
+		series.loaded = true
+		if s.persist {
+			s.loadCache(series)
+		}
+	}
+
+	now := s.now()
+	switch {
+	case series.depth < limit && len(series.klines) < limit:
+		if err := s.backfill(ctx, series, limit, now); err != nil {
+			return nil, err
+		}
+
+	case len(series.klines) > 0 && now.Sub(series.klines.Last().EndTime.Time()) > interval.Duration():
+		// the interval is not subscribed by the stream, or the stream was disconnected
+		if err := s.fillGap(ctx, series, now); err != nil {
+			return nil, err
+		}
+	}
+
+	klines := series.klines
+	if len(klines) > limit {
+		klines = klines[len(klines)-limit:]
+	}
+
+	return append([]types.KLine(nil), klines...), nil
+}
+
+// Subscribe registers the callback of the closed klines of the symbol and the interval, and returns the function to unsubscribe.
+func (s *KLineStore) Subscribe(symbol string, interval types.Interval, callback func(k types.KLine)) (unsubscribe func()) {
+	series := s.getSeries(symbol, interval)
+
+	series.mu.Lock()
+	series.subscriberID++
+	id := series.subscriberID
+	series.subscribers[id] = callback
+	series.mu.Unlock()
+
+	return func() {
+		series.mu.Lock()
+		delete(series.subscribers, id)
+		series.mu.Unlock()
+	}
+}
+
+// AddKLine adds the closed kline into the store and fans out the kline to the subscribers
+func (s *KLineStore) AddKLine(k types.KLine) {
+	series := s.getSeries(k.Symbol, k.Interval)
+
+	series.mu.Lock()
+	if !series.add(k) {
+		series.mu.Unlock()
+		return
+	}
+
+	if s.persist && s.now().Sub(series.lastPersistedAt) >= klineStorePersistInterval {
+		s.saveCache(series)
+	}
+
+	subscribers := make([]func(k types.KLine), 0, len(series.subscribers))
+	for _, callback := range series.subscribers {
+		subscribers = append(subscribers, callback)
+	}
+	series.mu.Unlock()
+
+	for _, callback := range subscribers {
+		callback(k)
+	}
+}
+
+// add appends the kline if it's newer than the last kline, it returns false for the duplicated klines
+func (series *klineSeries) add(k types.KLine) bool {
+	if len(series.klines) > 0 && !k.StartTime.After(series.klines.Last().StartTime.Time()) {
+		return false
+	}
+
+	series.klines.Add(k)
+	if len(series.klines) > MaxNumOfKLines {
+		series.klines = series.klines[MaxNumOfKLinesTruncate-1:]
+	}
+
+	return true
+}
+
+// backfill queries the last closed klines up to the limit backward from now
+func (s *KLineStore) backfill(ctx context.Context, series *klineSeries, limit int, now time.Time) error {
+	var klines []types.KLine
+	endTime := now
+	for len(klines) < limit {
+		batch, err := s.exchange.QueryKLines(ctx, series.key.symbol, series.key.interval, types.KLineQueryOptions{
+			EndTime: &endTime,
+			Limit:   min(limit-len(klines), klineStoreQueryLimit),
+		})
+		if err != nil {
+			return err
+		}
+
+		batch = closedKLines(batch, now)
+		if len(batch) == 0 {
+			break
+		}
+
+		klines = append(batch, klines...)
+		endTime = batch[0].StartTime.Time().Add(-time.Millisecond)
+	}
+
+	series.depth = limit
+
+	// the newer klines from the stream are kept
+	for _, k := range series.klines {
+		if len(klines) == 0 || k.StartTime.After(klines[len(klines)-1].StartTime.Time()) {
+			klines = append(klines, k)
+		}
+	}
+
+	series.klines = klines
+	if len(series.klines) > MaxNumOfKLines {
+		series.klines = series.klines[len(series.klines)-MaxNumOfKLines:]
+	}
+
+	if s.persist {
+		s.saveCache(series)
+	}
+
+	return nil
+}
+
+// fillGap queries the closed klines after the last kline of the series
+func (s *KLineStore) fillGap(ctx context.Context, series *klineSeries, now time.Time) error {
+	for {
+		startTime := series.klines.Last().EndTime.Time().Add(time.Millisecond)
+		batch, err := s.exchange.QueryKLines(ctx, series.key.symbol, series.key.interval, types.KLineQueryOptions{
+			StartTime: &startTime,
+			Limit:     klineStoreQueryLimit,
+		})
+		if err != nil {
+			return err
+		}
+
+		var added int
+		for _, k := range closedKLines(batch, now) {
+			if series.add(k) {
+				added++
+			}
+		}
+
+		if added == 0 || len(batch) < klineStoreQueryLimit {
+			break
+		}
+	}
+
+	if s.persist {
+		s.saveCache(series)
+	}
+
+	return nil
+}
+
+// closedKLines filters out the klines that are not closed yet at the given time
+func closedKLines(klines []types.KLine, now time.Time) []types.KLine {
+	for len(klines) > 0 && klines[len(klines)-1].EndTime.After(now) {
+		klines = klines[:len(klines)-1]
+	}
+
+	return klines
+}
+
+func (s *KLineStore) cacheFile(series *klineSeries) string {
+	dir := filepath.Join(cache.CacheDir(), "klines")
+	return filepath.Join(dir, fmt.Sprintf("%s-%s-%s.json", s.exchange.Name(), series.key.symbol, series.key.interval))
+}
+
+func (s *KLineStore) loadCache(series *klineSeries) {
+	data, err := os.ReadFile(s.cacheFile(series))
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.WithError(err).Warnf("unable to read the %s %s kline cache", series.key.symbol, series.key.interval)
+		}
+		return
+	}
+
+	var klines types.KLineWindow
+	if err := json.Unmarshal(data, &klines); err != nil {
+		log.WithError(err).Warnf("unable to parse the %s %s kline cache", series.key.symbol, series.key.interval)
+		return
+	}
+
+	series.klines = klines
+	series.depth = len(klines)
+}
+
+func (s *KLineStore) saveCache(series *klineSeries) {
+	series.lastPersistedAt = s.now()
+
+	file := s.cacheFile(series)
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		log.WithError(err).Warnf("unable to create the kline cache directory")
+		return
+	}
+
+	data, err := json.Marshal(series.klines)
+	if err != nil {
+		log.WithError(err).Warnf("unable to encode the %s %s klines", series.key.symbol, series.key.interval)
+		return
+	}
+
+	if err := os.WriteFile(file, data, 0666); err != nil {
+		log.WithError(err).Warnf("unable to write the %s %s kline cache", series.key.symbol, series.key.interval)
+	}
+}
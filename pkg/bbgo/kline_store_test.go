@@ -0,0 +1,129 @@
+package bbgo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+	"github.com/c9s/bbgo/pkg/types/mocks"
+)
+
+func newTestKLines(startTime time.Time, interval types.Interval, n int) []types.KLine {
+	var klines []types.KLine
+	for i := 0; i < n; i++ {
+		t := startTime.Add(time.Duration(i) * interval.Duration())
+		klines = append(klines, types.KLine{
+			Exchange:  types.ExchangeBinance,
+			Symbol:    "BTCUSDT",
+			Interval:  interval,
+			StartTime: types.Time(t),
+			EndTime:   types.Time(t.Add(interval.Duration() - time.Millisecond)),
+			Close:     fixedpoint.NewFromInt(int64(100 + i)),
+			Closed:    true,
+		})
+	}
+	return klines
+}
+
+func TestKLineStore_KLines(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	ctx := context.Background()
+	startTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := startTime.Add(5*time.Minute + 30*time.Second)
+
+	mockEx := mocks.NewMockExchange(mockCtrl)
+
+	// the last unclosed kline is dropped
+	mockEx.EXPECT().QueryKLines(ctx, "BTCUSDT", types.Interval1m, gomock.Any()).
+		Return(newTestKLines(startTime, types.Interval1m, 6), nil).Times(1)
+
+	store := NewKLineStore(mockEx, false)
+	store.now = func() time.Time { return now }
+
+	klines, err := store.KLines(ctx, "BTCUSDT", types.Interval1m, 5)
+	assert.NoError(t, err)
+	if assert.Len(t, klines, 5) {
+		assert.Equal(t, "104", klines[4].Close.String())
+	}
+
+	// the klines are only queried once
+	klines, err = store.KLines(ctx, "BTCUSDT", types.Interval1m, 3)
+	assert.NoError(t, err)
+	if assert.Len(t, klines, 3) {
+		assert.Equal(t, "102", klines[0].Close.String())
+	}
+
+	// the closed klines from the stream are fanned out to all the subscribers
+	var received1, received2 []types.KLine
+	unsubscribe := store.Subscribe("BTCUSDT", types.Interval1m, func(k types.KLine) { received1 = append(received1, k) })
+	store.Subscribe("BTCUSDT", types.Interval1m, func(k types.KLine) { received2 = append(received2, k) })
+
+	newKLines := newTestKLines(startTime.Add(5*time.Minute), types.Interval1m, 2)
+	now = now.Add(time.Minute)
+	store.AddKLine(newKLines[0])
+
+	// the duplicated kline is ignored
+	store.AddKLine(newKLines[0])
+	assert.Len(t, received1, 1)
+	assert.Len(t, received2, 1)
+
+	unsubscribe()
+	now = now.Add(time.Minute)
+	store.AddKLine(newKLines[1])
+	assert.Len(t, received1, 1)
+	assert.Len(t, received2, 2)
+
+	klines, err = store.KLines(ctx, "BTCUSDT", types.Interval1m, 7)
+	assert.NoError(t, err)
+	assert.Len(t, klines, 7)
+}
+
+func TestKLineStore_fillGap(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	t.Setenv("HOME", t.TempDir())
+
+	ctx := context.Background()
+	startTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := startTime.Add(3 * time.Minute)
+
+	mockEx := mocks.NewMockExchange(mockCtrl)
+	mockEx.EXPECT().Name().Return(types.ExchangeBinance).AnyTimes()
+	mockEx.EXPECT().QueryKLines(ctx, "BTCUSDT", types.Interval1m, gomock.Any()).
+		Return(newTestKLines(startTime, types.Interval1m, 3), nil).Times(1)
+
+	store := NewKLineStore(mockEx, true)
+	store.now = func() time.Time { return now }
+
+	klines, err := store.KLines(ctx, "BTCUSDT", types.Interval1m, 3)
+	assert.NoError(t, err)
+	assert.Len(t, klines, 3)
+
+	// the klines are loaded from the cache on the next start, and only the missing klines are queried
+	now = startTime.Add(5 * time.Minute)
+	mockEx.EXPECT().QueryKLines(ctx, "BTCUSDT", types.Interval1m, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, _ types.Interval, options types.KLineQueryOptions) ([]types.KLine, error) {
+			if assert.NotNil(t, options.StartTime) {
+				assert.Equal(t, startTime.Add(3*time.Minute), *options.StartTime)
+			}
+			return newTestKLines(startTime.Add(3*time.Minute), types.Interval1m, 2), nil
+		}).Times(1)
+
+	store = NewKLineStore(mockEx, true)
+	store.now = func() time.Time { return now }
+
+	klines, err = store.KLines(ctx, "BTCUSDT", types.Interval1m, 3)
+	assert.NoError(t, err)
+	if assert.Len(t, klines, 3) {
+		assert.Equal(t, "102", klines[0].Close.String())
+		assert.Equal(t, "101", klines[2].Close.String())
+	}
+}
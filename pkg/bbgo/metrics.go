@@ -97,6 +97,29 @@ var (
 			"currency",  // for balance
 		},
 	)
+
+	metricsStrategyCallbackTime = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "bbgo_strategy_callback_time_seconds",
+			Help:    "bbgo strategy callback execution time",
+			Buckets: prometheus.ExponentialBuckets(0.001, 4, 8),
+		},
+		[]string{
+			"strategy", // strategy instance ID
+			"callback", // callback name
+		},
+	)
+
+	metricsStrategyBudgetExceeded = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "bbgo_strategy_budget_exceeded_total",
+			Help: "bbgo strategy execution budget violations",
+		},
+		[]string{
+			"strategy", // strategy instance ID
+			"budget",   // budget: callbackTime or orderRate
+		},
+	)
 )
 
 func init() {
@@ -108,5 +131,7 @@ func init() {
 		metricsTradesTotal,
 		metricsTradingVolume,
 		metricsLastUpdateTimeBalance,
+		metricsStrategyCallbackTime,
+		metricsStrategyBudgetExceeded,
 	)
 }
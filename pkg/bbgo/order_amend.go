@@ -0,0 +1,39 @@
+package bbgo
+
+import (
+	"context"
+	"errors"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// ErrOrderAmendNotSupported is returned when the exchange of the session does not support amending the orders
+var ErrOrderAmendNotSupported = errors.New("order amend is not supported")
+
+// AmendOrder amends the price and the open quantity of the order by the order-amend API of the exchange.
+// Since the amended order can increase the order size, it goes through the same checks as a new order submission:
+// the kill switch, the compliance rules, the order middlewares and the order rate budget.
+func (session *ExchangeSession) AmendOrder(ctx context.Context, order types.Order, price, quantity fixedpoint.Value) (*types.Order, error) {
+	service, ok := session.Exchange.(types.ExchangeOrderAmendService)
+	if !ok {
+		return nil, ErrOrderAmendNotSupported
+	}
+
+	submitOrder := order.SubmitOrder
+	submitOrder.Price = price
+	submitOrder.Quantity = quantity
+
+	formattedOrder, err := session.FormatOrder(submitOrder)
+	if err != nil {
+		return nil, err
+	}
+
+	formattedOrders := []types.SubmitOrder{formattedOrder}
+	if err := session.CheckSubmitOrders(ctx, formattedOrders); err != nil {
+		return nil, err
+	}
+
+	order.Market = formattedOrder.Market
+	return service.AmendOrder(ctx, order, formattedOrders[0].Price, formattedOrders[0].Quantity)
+}
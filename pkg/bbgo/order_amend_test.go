@@ -0,0 +1,101 @@
+package bbgo
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+	"github.com/c9s/bbgo/pkg/types/mocks"
+)
+
+type testOrderAmendExchange struct {
+	*mocks.MockExchange
+
+	amendedOrders []types.Order
+}
+
+func (e *testOrderAmendExchange) AmendOrder(ctx context.Context, order types.Order, price, quantity fixedpoint.Value) (*types.Order, error) {
+	order.Price = price
+	order.Quantity = order.ExecutedQuantity.Add(quantity)
+	e.amendedOrders = append(e.amendedOrders, order)
+	return &order, nil
+}
+
+func TestExchangeSession_AmendOrder(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	market := types.Market{
+		Symbol:          "BTCUSDT",
+		BaseCurrency:    "BTC",
+		QuoteCurrency:   "USDT",
+		PricePrecision:  2,
+		VolumePrecision: 4,
+		TickSize:        fixedpoint.NewFromFloat(0.01),
+		StepSize:        fixedpoint.NewFromFloat(0.0001),
+	}
+
+	mockEx := mocks.NewMockExchange(mockCtrl)
+	mockEx.EXPECT().NewStream().Return(&types.StandardStream{}).AnyTimes()
+
+	exchange := &testOrderAmendExchange{MockExchange: mockEx}
+	session := NewExchangeSession("okex", exchange)
+	session.SetMarkets(types.MarketMap{market.Symbol: market})
+
+	// the order updated by the user data stream does not carry the market
+	order := types.Order{
+		SubmitOrder: types.SubmitOrder{
+			Symbol:   market.Symbol,
+			Side:     types.SideTypeBuy,
+			Type:     types.OrderTypeLimit,
+			Price:    fixedpoint.NewFromFloat(30000.0),
+			Quantity: fixedpoint.NewFromFloat(0.5),
+		},
+		OrderID:          1,
+		ExecutedQuantity: fixedpoint.NewFromFloat(0.2),
+	}
+
+	price := fixedpoint.NewFromFloat(30010.0)
+	quantity := fixedpoint.NewFromFloat(0.3)
+
+	amendedOrder, err := session.AmendOrder(context.Background(), order, price, quantity)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "30010", amendedOrder.Price.String())
+		assert.Equal(t, "0.5", amendedOrder.Quantity.String())
+		assert.Equal(t, market, amendedOrder.Market)
+	}
+
+	// the amended order is checked by the order middlewares as a new order
+	var checkedOrders []types.SubmitOrder
+	veto := errors.New("vetoed")
+	session.SetOrderMiddleware("veto", func(ctx context.Context, session *ExchangeSession, orders []types.SubmitOrder) error {
+		checkedOrders = append(checkedOrders, orders...)
+		return veto
+	})
+
+	_, err = session.AmendOrder(context.Background(), order, price, quantity)
+	assert.Equal(t, veto, err)
+	if assert.Len(t, checkedOrders, 1) {
+		assert.Equal(t, "0.3", checkedOrders[0].Quantity.String())
+	}
+
+	// the order can not be amended once the kill switch is tripped
+	session.SetOrderMiddleware("veto", func(ctx context.Context, session *ExchangeSession, orders []types.SubmitOrder) error {
+		return nil
+	})
+
+	assert.NoError(t, session.TripKillSwitch(context.Background(), "test"))
+	_, err = session.AmendOrder(context.Background(), order, price, quantity)
+	assert.ErrorIs(t, err, ErrKillSwitchTripped)
+
+	assert.Len(t, exchange.amendedOrders, 1)
+
+	// the exchange without the order-amend api
+	_, err = NewExchangeSession("binance", mockEx).AmendOrder(context.Background(), order, price, quantity)
+	assert.ErrorIs(t, err, ErrOrderAmendNotSupported)
+}
@@ -0,0 +1,280 @@
+package bbgo
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// OrderPriority is the priority class of the order submission in the order rate budget,
+// the orders of the higher priority preempt the waiting orders of the lower priority.
+type OrderPriority int
+
+const (
+	// OrderPriorityLow is for the orders that can be delayed, e.g. the quote refreshes of the market makers
+	OrderPriorityLow OrderPriority = iota
+
+	// OrderPriorityNormal is the default priority
+	OrderPriorityNormal
+
+	// OrderPriorityHigh is for the orders that reduce the risk, e.g. the hedge orders
+	OrderPriorityHigh
+
+	numOfOrderPriorities
+)
+
+func (p OrderPriority) String() string {
+	switch p {
+	case OrderPriorityLow:
+		return "low"
+	case OrderPriorityNormal:
+		return "normal"
+	case OrderPriorityHigh:
+		return "high"
+	}
+
+	return fmt.Sprintf("OrderPriority(%d)", int(p))
+}
+
+type orderPriorityContextKey struct{}
+
+// WithOrderPriority returns the context carrying the order priority,
+// the order executors consume the order rate budget with the priority.
+func WithOrderPriority(ctx context.Context, priority OrderPriority) context.Context {
+	return context.WithValue(ctx, orderPriorityContextKey{}, priority)
+}
+
+// OrderPriorityFromContext returns the order priority of the context, OrderPriorityNormal is returned if it's not set
+func OrderPriorityFromContext(ctx context.Context) OrderPriority {
+	if priority, ok := ctx.Value(orderPriorityContextKey{}).(OrderPriority); ok {
+		return priority
+	}
+
+	return OrderPriorityNormal
+}
+
+// OrderRateLimitConfig is the order placement rate limit of the exchange account
+type OrderRateLimitConfig struct {
+	// Group shares the budget between the sessions of the same exchange account, default to the session name
+	Group string `json:"group,omitempty" yaml:"group,omitempty"`
+
+	OrdersPerSecond float64 `json:"ordersPerSecond" yaml:"ordersPerSecond"`
+
+	// Burst is the max number of the orders that can be placed at once, default to the orders per second
+	Burst int `json:"burst,omitempty" yaml:"burst,omitempty"`
+
+	// ReservedRatio is the ratio of the burst that is reserved for the high priority orders,
+	// the lower priority orders can not consume the reserved budget.
+	ReservedRatio float64 `json:"reservedRatio,omitempty" yaml:"reservedRatio,omitempty"`
+}
+
+func (c *OrderRateLimitConfig) Validate() error {
+	if c.OrdersPerSecond <= 0 {
+		return fmt.Errorf("ordersPerSecond should be positive, got %f", c.OrdersPerSecond)
+	}
+
+	if c.Burst < 0 {
+		return fmt.Errorf("burst should not be negative, got %d", c.Burst)
+	}
+
+	if c.ReservedRatio < 0 || c.ReservedRatio >= 1.0 {
+		return fmt.Errorf("reservedRatio should be in the range of 0 to 1, got %f", c.ReservedRatio)
+	}
+
+	return nil
+}
+
+const orderRateBudgetPollInterval = 50 * time.Millisecond
+
+// OrderRateBudget is a token bucket of the order placements shared by the strategies,
+// the waiting orders are served by their priorities.
+type OrderRateBudget struct {
+	ratePerSecond float64
+	burst         float64
+	reserved      float64
+
+	mu      sync.Mutex
+	tokens  float64
+	last    time.Time
+	waiting [numOfOrderPriorities]int
+
+	now func() time.Time
+}
+
+func NewOrderRateBudget(config OrderRateLimitConfig) *OrderRateBudget {
+	burst := float64(config.Burst)
+	if burst == 0 {
+		burst = config.OrdersPerSecond
+	}
+
+	return &OrderRateBudget{
+		ratePerSecond: config.OrdersPerSecond,
+		burst:         burst,
+		reserved:      burst * config.ReservedRatio,
+		tokens:        burst,
+		now:           time.Now,
+	}
+}
+
+// Tokens returns the available budget
+func (b *OrderRateBudget) Tokens() float64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill(b.now())
+	return b.tokens
+}
+
+// Allow consumes the budget of n orders if it's available, it does not wait
+func (b *OrderRateBudget) Allow(priority OrderPriority, n int) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ok, _ := b.reserve(priority, float64(n))
+	return ok
+}
+
+// Wait waits until the budget of n orders is available or the context is canceled
+func (b *OrderRateBudget) Wait(ctx context.Context, priority OrderPriority, n int) error {
+	if priority < 0 || priority >= numOfOrderPriorities {
+		priority = OrderPriorityNormal
+	}
+
+	// the lower priority orders can not consume the reserved budget, so the batch over the unreserved budget
+	// would wait forever and block the waiting orders of the even lower priority
+	limit := b.burst
+	if priority < OrderPriorityHigh {
+		limit -= b.reserved
+	}
+
+	if float64(n) > limit {
+		return fmt.Errorf("the number of the %s priority orders %d exceeds the order rate budget %f", priority, n, limit)
+	}
+
+	registered := false
+	defer func() {
+		if registered {
+			b.mu.Lock()
+			b.waiting[priority]--
+			b.mu.Unlock()
+		}
+	}()
+
+	for {
+		b.mu.Lock()
+		ok, wait := b.reserve(priority, float64(n))
+		if !ok && !registered {
+			b.waiting[priority]++
+			registered = true
+		}
+		b.mu.Unlock()
+
+		if ok {
+			return nil
+		}
+
+		if wait > orderRateBudgetPollInterval || wait <= 0 {
+			wait = orderRateBudgetPollInterval
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+
+		case <-timer.C:
+		}
+	}
+}
+
+// reserve consumes the budget if it's available, it must be called with the lock held
+func (b *OrderRateBudget) reserve(priority OrderPriority, n float64) (bool, time.Duration) {
+	b.refill(b.now())
+
+	// the waiting orders of the higher priority are served first
+	for p := priority + 1; p < numOfOrderPriorities; p++ {
+		if b.waiting[p] > 0 {
+			return false, orderRateBudgetPollInterval
+		}
+	}
+
+	required := n
+	if priority < OrderPriorityHigh {
+		required += b.reserved
+	}
+
+	if b.tokens >= required {
+		b.tokens -= n
+		return true, 0
+	}
+
+	return false, time.Duration((required - b.tokens) / b.ratePerSecond * float64(time.Second))
+}
+
+func (b *OrderRateBudget) refill(now time.Time) {
+	if !b.last.IsZero() {
+		b.tokens += now.Sub(b.last).Seconds() * b.ratePerSecond
+		if b.tokens > b.burst {
+			b.tokens = b.burst
+		}
+	}
+
+	b.last = now
+}
+
+// OrderRateBudgetManager manages the order rate budgets by the groups,
+// the sessions of the same group share the same budget.
+type OrderRateBudgetManager struct {
+	mu      sync.Mutex
+	budgets map[string]*OrderRateBudget
+}
+
+func NewOrderRateBudgetManager() *OrderRateBudgetManager {
+	return &OrderRateBudgetManager{
+		budgets: make(map[string]*OrderRateBudget),
+	}
+}
+
+// Get returns the budget of the group, the budget is created by the config if it does not exist
+func (m *OrderRateBudgetManager) Get(group string, config OrderRateLimitConfig) *OrderRateBudget {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if budget, ok := m.budgets[group]; ok {
+		return budget
+	}
+
+	budget := NewOrderRateBudget(config)
+	m.budgets[group] = budget
+	return budget
+}
+
+// DefaultOrderRateBudgetManager is the budget manager shared by all the sessions
+var DefaultOrderRateBudgetManager = NewOrderRateBudgetManager()
+
+// OrderRateBudget returns the order rate budget of the session, nil is returned if the rate limit is not configured
+func (session *ExchangeSession) OrderRateBudget() *OrderRateBudget {
+	if session == nil || session.OrderRateLimit == nil {
+		return nil
+	}
+
+	group := session.OrderRateLimit.Group
+	if group == "" {
+		group = session.Name
+	}
+
+	return DefaultOrderRateBudgetManager.Get(group, *session.OrderRateLimit)
+}
+
+// WaitOrderRateBudget waits for the order rate budget of the session with the priority of the context,
+// the strategies that submit the orders to the exchange directly should call it before the submission.
+func (session *ExchangeSession) WaitOrderRateBudget(ctx context.Context, numOrders int) error {
+	budget := session.OrderRateBudget()
+	if budget == nil || numOrders == 0 {
+		return nil
+	}
+
+	return budget.Wait(ctx, OrderPriorityFromContext(ctx), numOrders)
+}
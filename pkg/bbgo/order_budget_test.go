@@ -0,0 +1,116 @@
+package bbgo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrderRateBudget_Allow(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	budget := NewOrderRateBudget(OrderRateLimitConfig{
+		OrdersPerSecond: 10,
+		Burst:           10,
+		ReservedRatio:   0.2,
+	})
+	budget.now = func() time.Time { return now }
+
+	// the low priority orders can not consume the reserved budget
+	assert.True(t, budget.Allow(OrderPriorityLow, 8))
+	assert.False(t, budget.Allow(OrderPriorityLow, 1))
+	assert.False(t, budget.Allow(OrderPriorityNormal, 1))
+
+	// the high priority orders can consume the reserved budget
+	assert.True(t, budget.Allow(OrderPriorityHigh, 2))
+	assert.False(t, budget.Allow(OrderPriorityHigh, 1))
+
+	// refill
+	now = now.Add(500 * time.Millisecond)
+	assert.InDelta(t, 5.0, budget.Tokens(), 1e-9)
+	assert.True(t, budget.Allow(OrderPriorityLow, 3))
+	assert.False(t, budget.Allow(OrderPriorityLow, 1))
+
+	// the tokens are capped by the burst
+	now = now.Add(time.Hour)
+	assert.InDelta(t, 10.0, budget.Tokens(), 1e-9)
+}
+
+func TestOrderRateBudget_Preemption(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	budget := NewOrderRateBudget(OrderRateLimitConfig{OrdersPerSecond: 1})
+	budget.now = func() time.Time { return now }
+
+	assert.True(t, budget.Allow(OrderPriorityNormal, 1))
+
+	// a high priority order is waiting for the budget
+	budget.waiting[OrderPriorityHigh] = 1
+
+	now = now.Add(time.Second)
+	assert.False(t, budget.Allow(OrderPriorityLow, 1), "the low priority order should yield to the waiting hedge order")
+	assert.True(t, budget.Allow(OrderPriorityHigh, 1))
+}
+
+func TestOrderRateBudget_Wait(t *testing.T) {
+	budget := NewOrderRateBudget(OrderRateLimitConfig{OrdersPerSecond: 1})
+
+	ctx := context.Background()
+	assert.NoError(t, budget.Wait(ctx, OrderPriorityNormal, 1))
+
+	ctx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+
+	err := budget.Wait(ctx, OrderPriorityNormal, 1)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, 0, budget.waiting[OrderPriorityNormal])
+
+	err = budget.Wait(context.Background(), OrderPriorityNormal, 2)
+	assert.Error(t, err, "the orders exceeding the burst can never be placed")
+}
+
+func TestOrderRateBudget_Wait_ExceedsUnreservedBudget(t *testing.T) {
+	budget := NewOrderRateBudget(OrderRateLimitConfig{
+		OrdersPerSecond: 10,
+		Burst:           10,
+		ReservedRatio:   0.5,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// the normal priority batch over the unreserved budget can never be placed, it's rejected without waiting
+	err := budget.Wait(ctx, OrderPriorityNormal, 6)
+	assert.Error(t, err)
+	assert.NoError(t, ctx.Err())
+	assert.Equal(t, 0, budget.waiting[OrderPriorityNormal])
+
+	// so the low priority orders are not blocked behind it
+	assert.NoError(t, budget.Wait(ctx, OrderPriorityLow, 1))
+
+	// the high priority orders can consume the reserved budget
+	assert.NoError(t, budget.Wait(ctx, OrderPriorityHigh, 6))
+	assert.NoError(t, budget.Wait(ctx, OrderPriorityNormal, 5))
+}
+
+func TestOrderRateLimitConfig_Validate(t *testing.T) {
+	assert.NoError(t, (&OrderRateLimitConfig{OrdersPerSecond: 5, Burst: 10, ReservedRatio: 0.3}).Validate())
+	assert.Error(t, (&OrderRateLimitConfig{}).Validate())
+	assert.Error(t, (&OrderRateLimitConfig{OrdersPerSecond: 5, Burst: -1}).Validate())
+	assert.Error(t, (&OrderRateLimitConfig{OrdersPerSecond: 5, ReservedRatio: 1}).Validate())
+}
+
+func TestExchangeSession_OrderRateBudget(t *testing.T) {
+	config := &OrderRateLimitConfig{Group: "test-account", OrdersPerSecond: 5}
+	s1 := &ExchangeSession{Name: "s1", OrderRateLimit: config}
+	s2 := &ExchangeSession{Name: "s2", OrderRateLimit: config}
+	assert.Same(t, s1.OrderRateBudget(), s2.OrderRateBudget(), "the sessions of the same group share the budget")
+
+	s3 := &ExchangeSession{}
+	assert.Nil(t, s3.OrderRateBudget())
+	assert.NoError(t, s3.WaitOrderRateBudget(context.Background(), 1))
+
+	ctx := WithOrderPriority(context.Background(), OrderPriorityHigh)
+	assert.Equal(t, OrderPriorityHigh, OrderPriorityFromContext(ctx))
+	assert.Equal(t, OrderPriorityNormal, OrderPriorityFromContext(context.Background()))
+}
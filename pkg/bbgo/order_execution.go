@@ -63,6 +63,10 @@ func (e *ExchangeOrderExecutionRouter) SubmitOrdersTo(ctx context.Context, sessi
 		return nil, err
 	}
 
+	if err := es.CheckSubmitOrders(ctx, formattedOrders); err != nil {
+		return nil, err
+	}
+
 	createdOrders, _, err := BatchPlaceOrder(ctx, es.Exchange, nil, formattedOrders...)
 	return createdOrders, err
 }
@@ -87,6 +91,9 @@ type ExchangeOrderExecutor struct {
 
 	Session *ExchangeSession `json:"-" yaml:"-"`
 
+	// OrderValidationPolicy overrides the order validation policy of the session when it's set
+	OrderValidationPolicy *OrderValidationPolicy `json:"orderValidationPolicy,omitempty" yaml:"orderValidationPolicy,omitempty"`
+
 	// private trade update callbacks
 	tradeUpdateCallbacks []func(trade types.Trade)
 
@@ -95,7 +102,12 @@ type ExchangeOrderExecutor struct {
 }
 
 func (e *ExchangeOrderExecutor) SubmitOrders(ctx context.Context, orders ...types.SubmitOrder) (types.OrderSlice, error) {
-	formattedOrders, err := e.Session.FormatOrders(orders)
+	policy := e.Session.OrderValidationPolicy
+	if e.OrderValidationPolicy != nil {
+		policy = *e.OrderValidationPolicy
+	}
+
+	formattedOrders, err := e.Session.FormatOrdersWithPolicy(orders, policy)
 	if err != nil {
 		return nil, err
 	}
@@ -104,6 +116,10 @@ func (e *ExchangeOrderExecutor) SubmitOrders(ctx context.Context, orders ...type
 		log.Infof("submitting order: %s", order.String())
 	}
 
+	if err := e.Session.CheckSubmitOrders(ctx, formattedOrders); err != nil {
+		return nil, err
+	}
+
 	createdOrders, _, err := BatchPlaceOrder(ctx, e.Session.Exchange, nil, formattedOrders...)
 	return createdOrders, err
 }
@@ -309,14 +325,26 @@ func (c *BasicRiskController) ProcessOrders(session *ExchangeSession, orders ...
 
 type OrderCallback func(order types.Order)
 
-// BatchPlaceOrder
+// BatchPlaceOrder places the orders one by one and returns the indexes of the failed orders.
+// When the submission of an order with the client order ID fails, e.g. the request is timed out,
+// the order is queried by the client order ID, so that the order that was actually placed is not reported as failed.
+// The orders without the client order ID get the deterministic client order IDs if the context carries the order epoch,
+// see WithOrderEpoch.
 func BatchPlaceOrder(ctx context.Context, exchange types.Exchange, orderCallback OrderCallback, submitOrders ...types.SubmitOrder) (types.OrderSlice, []int, error) {
+	assignContextClientOrderIDs(ctx, submitOrders)
+
 	var createdOrders types.OrderSlice
 	var err error
 
 	var errIndexes []int
 	for i, submitOrder := range submitOrders {
-		createdOrder, err2 := exchange.SubmitOrder(ctx, submitOrder)
+		createdOrder, err2 := placeOrder(ctx, exchange, submitOrder)
+		if err2 != nil {
+			if placedOrder, ok := queryPlacedOrder(ctx, exchange, submitOrder); ok {
+				createdOrder, err2 = placedOrder, nil
+			}
+		}
+
 		if err2 != nil {
 			err = multierr.Append(err, err2)
 			errIndexes = append(errIndexes, i)
@@ -340,6 +368,8 @@ func BatchRetryPlaceOrder(ctx context.Context, exchange types.Exchange, errIdx [
 		logger = log.StandardLogger()
 	}
 
+	assignContextClientOrderIDs(ctx, submitOrders)
+
 	var createdOrders types.OrderSlice
 	var werr error
 
@@ -379,8 +409,16 @@ batchRetryOrder:
 			submitOrder := submitOrders[idx]
 
 			op := func() error {
-				// can allocate permanent error backoff.Permanent(err) to stop backoff
-				createdOrder, err2 := exchange.SubmitOrder(timeoutCtx, submitOrder)
+				// the previous submission might be timed out after the order was placed,
+				// query the order by the client order ID before re-submitting it
+				createdOrder, placed := queryPlacedOrder(timeoutCtx, exchange, submitOrder)
+
+				var err2 error
+				if !placed {
+					// can allocate permanent error backoff.Permanent(err) to stop backoff
+					createdOrder, err2 = placeOrder(timeoutCtx, exchange, submitOrder)
+				}
+
 				if err2 != nil {
 					logger.WithError(err2).Errorf("submit order error: %s", submitOrder.String())
 				}
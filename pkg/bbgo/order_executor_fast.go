@@ -28,11 +28,15 @@ func NewFastOrderExecutor(session *ExchangeSession, symbol, strategy, strategyIn
 // @return *types.SubmitOrder: SubmitOrder with calculated quantity and price.
 // @return error: Error message.
 func (e *FastOrderExecutor) SubmitOrders(ctx context.Context, submitOrders ...types.SubmitOrder) (types.OrderSlice, error) {
-	formattedOrders, err := e.session.FormatOrders(submitOrders)
+	formattedOrders, err := e.formatOrders(submitOrders)
 	if err != nil {
 		return nil, err
 	}
 
+	if err := e.session.CheckSubmitOrders(ctx, formattedOrders); err != nil {
+		return nil, err
+	}
+
 	createdOrders, errIdx, err := BatchPlaceOrder(ctx, e.session.Exchange, nil, formattedOrders...)
 	if len(errIdx) > 0 {
 		return nil, err
@@ -51,6 +51,8 @@ func (e *BaseOrderExecutor) GracefulCancel(ctx context.Context, orders ...types.
 }
 
 // GeneralOrderExecutor implements the general order executor for strategy
+//
+//go:generate callbackgen -type GeneralOrderExecutor
 type GeneralOrderExecutor struct {
 	BaseOrderExecutor
 
@@ -66,6 +68,12 @@ type GeneralOrderExecutor struct {
 
 	maxRetries    uint
 	disableNotify bool
+
+	// orderValidationPolicy overrides the order validation policy of the session when it's set
+	orderValidationPolicy *OrderValidationPolicy
+
+	// orderErrorCallbacks are called when the exchange fails to place or cancel the orders
+	orderErrorCallbacks []func(err error)
 }
 
 // NewGeneralOrderExecutor allocates a GeneralOrderExecutor
@@ -106,6 +114,19 @@ func (e *GeneralOrderExecutor) DisableNotify() {
 	e.disableNotify = true
 }
 
+// SetOrderValidationPolicy overrides the order validation policy of the session for this executor
+func (e *GeneralOrderExecutor) SetOrderValidationPolicy(policy OrderValidationPolicy) {
+	e.orderValidationPolicy = &policy
+}
+
+func (e *GeneralOrderExecutor) formatOrders(orders []types.SubmitOrder) ([]types.SubmitOrder, error) {
+	if e.orderValidationPolicy != nil {
+		return e.session.FormatOrdersWithPolicy(orders, *e.orderValidationPolicy)
+	}
+
+	return e.session.FormatOrders(orders)
+}
+
 func (e *GeneralOrderExecutor) SetMaxRetries(maxRetries uint) {
 	e.maxRetries = maxRetries
 }
@@ -214,6 +235,10 @@ func (e *GeneralOrderExecutor) CancelOrders(ctx context.Context, orders ...types
 	if err != nil { // Retry once
 		err = e.session.Exchange.CancelOrders(ctx, orders...)
 	}
+
+	if err != nil {
+		e.EmitOrderError(err)
+	}
 	return err
 }
 
@@ -224,11 +249,15 @@ func (e *GeneralOrderExecutor) SetLogger(logger log.FieldLogger) {
 func (e *GeneralOrderExecutor) SubmitOrders(
 	ctx context.Context, submitOrders ...types.SubmitOrder,
 ) (types.OrderSlice, error) {
-	formattedOrders, err := e.session.FormatOrders(submitOrders)
+	formattedOrders, err := e.formatOrders(submitOrders)
 	if err != nil {
 		return nil, err
 	}
 
+	if err := e.session.CheckSubmitOrders(ctx, formattedOrders); err != nil {
+		return nil, err
+	}
+
 	orderCreateCallback := func(createdOrder types.Order) {
 		e.orderStore.Add(createdOrder)
 		e.activeMakerOrders.Add(createdOrder)
@@ -236,12 +265,17 @@ func (e *GeneralOrderExecutor) SubmitOrders(
 
 	defer e.tradeCollector.Process()
 
+	var createdOrders types.OrderSlice
 	if e.maxRetries == 0 {
-		createdOrders, _, err := BatchPlaceOrder(ctx, e.session.Exchange, orderCreateCallback, formattedOrders...)
-		return createdOrders, err
+		createdOrders, _, err = BatchPlaceOrder(ctx, e.session.Exchange, orderCreateCallback, formattedOrders...)
+	} else {
+		createdOrders, _, err = BatchRetryPlaceOrder(ctx, e.session.Exchange, nil, orderCreateCallback, e.logger, formattedOrders...)
+	}
+
+	if err != nil {
+		e.EmitOrderError(err)
 	}
 
-	createdOrders, _, err := BatchRetryPlaceOrder(ctx, e.session.Exchange, nil, orderCreateCallback, e.logger, formattedOrders...)
 	return createdOrders, err
 }
 
@@ -0,0 +1,112 @@
+package bbgo
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// deterministicClientOrderIDPrefix is the prefix of the deterministic client order IDs,
+// the ID is kept short so that it's not truncated by the exchange broker prefixes.
+const deterministicClientOrderIDPrefix = "bb"
+
+const deterministicClientOrderIDHashLength = 16
+
+// NewDeterministicClientOrderID generates the client order ID from the strategy instance, the layer, the price and the epoch,
+// the same inputs always generate the same ID, so that a re-submitted order can be identified by the exchange
+// and queried by the client order ID.
+func NewDeterministicClientOrderID(instanceID string, layer int, price fixedpoint.Value, epoch int64) string {
+	payload := strings.Join([]string{
+		instanceID,
+		strconv.Itoa(layer),
+		price.String(),
+		strconv.FormatInt(epoch, 10),
+	}, "|")
+
+	sum := sha256.Sum256([]byte(payload))
+	return deterministicClientOrderIDPrefix + hex.EncodeToString(sum[:])[:deterministicClientOrderIDHashLength]
+}
+
+// AssignDeterministicClientOrderIDs assigns the deterministic client order IDs to the submit orders
+// that don't have a client order ID, the index of the order in the batch is used as the layer.
+func AssignDeterministicClientOrderIDs(instanceID string, epoch int64, submitOrders []types.SubmitOrder) {
+	for i := range submitOrders {
+		if submitOrders[i].ClientOrderID != "" {
+			continue
+		}
+
+		submitOrders[i].ClientOrderID = NewDeterministicClientOrderID(instanceID, i, submitOrders[i].Price, epoch)
+	}
+}
+
+type orderEpochContextKey struct{}
+
+type orderEpoch struct {
+	instanceID string
+	epoch      int64
+}
+
+// WithOrderEpoch returns the context of the order submission of the strategy instance in the given epoch,
+// the orders placed by BatchPlaceOrder and BatchRetryPlaceOrder with the context get the deterministic client order IDs.
+//
+// The epoch should be derived from the quoting cycle or the tick, e.g., OrderEpoch(tickTime, interval),
+// so that re-running the same cycle after a restart generates the same client order IDs.
+func WithOrderEpoch(ctx context.Context, instanceID string, epoch int64) context.Context {
+	return context.WithValue(ctx, orderEpochContextKey{}, orderEpoch{instanceID: instanceID, epoch: epoch})
+}
+
+// OrderEpoch returns the epoch of the cycle that the tick time belongs to, the tick time is truncated by the cycle interval
+func OrderEpoch(tickTime time.Time, interval time.Duration) int64 {
+	if interval > 0 {
+		tickTime = tickTime.Truncate(interval)
+	}
+
+	return tickTime.UnixMilli()
+}
+
+// assignContextClientOrderIDs assigns the deterministic client order IDs by the epoch of the context,
+// the orders are not changed if the context does not carry the epoch
+func assignContextClientOrderIDs(ctx context.Context, submitOrders []types.SubmitOrder) {
+	e, ok := ctx.Value(orderEpochContextKey{}).(orderEpoch)
+	if !ok {
+		return
+	}
+
+	AssignDeterministicClientOrderIDs(e.instanceID, e.epoch, submitOrders)
+}
+
+// queryPlacedOrder queries the order by the client order ID, it's used for checking if the order was placed
+// before re-submitting the order after a failed or timed out submission, so that the order is not duplicated.
+func queryPlacedOrder(ctx context.Context, exchange types.Exchange, submitOrder types.SubmitOrder) (*types.Order, bool) {
+	if submitOrder.ClientOrderID == "" || submitOrder.ClientOrderID == types.NoClientOrderID {
+		return nil, false
+	}
+
+	service, ok := exchange.(types.ExchangeOrderQueryService)
+	if !ok {
+		return nil, false
+	}
+
+	order, err := service.QueryOrder(ctx, types.OrderQuery{
+		Symbol:        submitOrder.Symbol,
+		ClientOrderID: submitOrder.ClientOrderID,
+	})
+	if err != nil || order == nil {
+		return nil, false
+	}
+
+	if order.Status == types.OrderStatusRejected {
+		return nil, false
+	}
+
+	log.Warnf("order %s was already placed, skip re-submitting", submitOrder.ClientOrderID)
+	return order, true
+}
@@ -0,0 +1,138 @@
+package bbgo
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+	"github.com/c9s/bbgo/pkg/types/mocks"
+)
+
+type testOrderQueryExchange struct {
+	*mocks.MockExchange
+	*mocks.MockExchangeOrderQueryService
+}
+
+func TestNewDeterministicClientOrderID(t *testing.T) {
+	price := fixedpoint.NewFromFloat(40000.0)
+	id := NewDeterministicClientOrderID("xmaker:BTCUSDT", 1, price, 1700000000)
+	assert.Len(t, id, 18)
+	assert.Equal(t, id, NewDeterministicClientOrderID("xmaker:BTCUSDT", 1, price, 1700000000))
+	assert.NotEqual(t, id, NewDeterministicClientOrderID("xmaker:BTCUSDT", 2, price, 1700000000))
+	assert.NotEqual(t, id, NewDeterministicClientOrderID("xmaker:BTCUSDT", 1, price, 1700000001))
+
+	submitOrders := []types.SubmitOrder{
+		{Symbol: "BTCUSDT", Price: price},
+		{Symbol: "BTCUSDT", Price: price, ClientOrderID: "custom"},
+	}
+	AssignDeterministicClientOrderIDs("xmaker:BTCUSDT", 1700000000, submitOrders)
+	assert.Equal(t, NewDeterministicClientOrderID("xmaker:BTCUSDT", 0, price, 1700000000), submitOrders[0].ClientOrderID)
+	assert.Equal(t, "custom", submitOrders[1].ClientOrderID)
+}
+
+func TestBatchPlaceOrder_queryPlacedOrder(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockExchange := mocks.NewMockExchange(mockCtrl)
+	mockQueryService := mocks.NewMockExchangeOrderQueryService(mockCtrl)
+	exchange := &testOrderQueryExchange{
+		MockExchange:                  mockExchange,
+		MockExchangeOrderQueryService: mockQueryService,
+	}
+
+	submitOrder := types.SubmitOrder{
+		ClientOrderID: "bb0123456789abcdef",
+		Symbol:        "BTCUSDT",
+		Side:          types.SideTypeBuy,
+		Type:          types.OrderTypeLimit,
+		Quantity:      fixedpoint.One,
+		Price:         fixedpoint.NewFromFloat(40000.0),
+		Tag:           "layer1",
+	}
+
+	placedOrder := types.Order{
+		SubmitOrder: submitOrder,
+		OrderID:     1,
+		Status:      types.OrderStatusNew,
+	}
+
+	// the request is timed out after the order was placed
+	mockExchange.EXPECT().SubmitOrder(gomock.Any(), submitOrder).Return(nil, context.DeadlineExceeded)
+	mockQueryService.EXPECT().QueryOrder(gomock.Any(), types.OrderQuery{
+		Symbol:        "BTCUSDT",
+		ClientOrderID: "bb0123456789abcdef",
+	}).Return(&placedOrder, nil)
+
+	var callbackOrders []types.Order
+	createdOrders, errIdx, err := BatchPlaceOrder(context.Background(), exchange, func(order types.Order) {
+		callbackOrders = append(callbackOrders, order)
+	}, submitOrder)
+	assert.NoError(t, err)
+	assert.Empty(t, errIdx)
+	if assert.Len(t, createdOrders, 1) {
+		assert.Equal(t, uint64(1), createdOrders[0].OrderID)
+		assert.Equal(t, "layer1", createdOrders[0].Tag)
+	}
+	assert.Len(t, callbackOrders, 1)
+
+	// the order is not found, so the error is reported
+	mockExchange.EXPECT().SubmitOrder(gomock.Any(), submitOrder).Return(nil, errors.New("bad request"))
+	mockQueryService.EXPECT().QueryOrder(gomock.Any(), gomock.Any()).Return(nil, errors.New("order not found"))
+
+	createdOrders, errIdx, err = BatchPlaceOrder(context.Background(), exchange, nil, submitOrder)
+	assert.Error(t, err)
+	assert.Equal(t, []int{0}, errIdx)
+	assert.Empty(t, createdOrders)
+}
+
+func TestOrderEpoch(t *testing.T) {
+	tickTime := time.Date(2024, 1, 1, 0, 0, 4, 500*int(time.Millisecond), time.UTC)
+	assert.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).UnixMilli(), OrderEpoch(tickTime, 5*time.Second))
+	assert.Equal(t, OrderEpoch(tickTime, 5*time.Second), OrderEpoch(tickTime.Add(400*time.Millisecond), 5*time.Second))
+	assert.NotEqual(t, OrderEpoch(tickTime, 5*time.Second), OrderEpoch(tickTime.Add(time.Second), 5*time.Second))
+	assert.Equal(t, tickTime.UnixMilli(), OrderEpoch(tickTime, 0))
+}
+
+func TestBatchPlaceOrder_WithOrderEpoch(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockExchange := mocks.NewMockExchange(mockCtrl)
+
+	price := fixedpoint.NewFromFloat(40000.0)
+	submitOrders := []types.SubmitOrder{
+		{Symbol: "BTCUSDT", Side: types.SideTypeBuy, Type: types.OrderTypeLimit, Quantity: fixedpoint.One, Price: price},
+		{Symbol: "BTCUSDT", Side: types.SideTypeBuy, Type: types.OrderTypeLimit, Quantity: fixedpoint.One, Price: price, ClientOrderID: "custom"},
+	}
+
+	var clientOrderIDs []string
+	mockExchange.EXPECT().SubmitOrder(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, o types.SubmitOrder) (*types.Order, error) {
+		clientOrderIDs = append(clientOrderIDs, o.ClientOrderID)
+		return &types.Order{SubmitOrder: o, OrderID: uint64(len(clientOrderIDs))}, nil
+	}).Times(5)
+
+	// the same cycle of the same strategy instance generates the same client order IDs, e.g., after a restart
+	ctx := WithOrderEpoch(context.Background(), "xmaker:BTCUSDT", 1700000000000)
+	_, _, err := BatchPlaceOrder(ctx, mockExchange, nil, append([]types.SubmitOrder{}, submitOrders...)...)
+	assert.NoError(t, err)
+
+	_, _, err = BatchPlaceOrder(ctx, mockExchange, nil, append([]types.SubmitOrder{}, submitOrders...)...)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{
+		NewDeterministicClientOrderID("xmaker:BTCUSDT", 0, price, 1700000000000), "custom",
+		NewDeterministicClientOrderID("xmaker:BTCUSDT", 0, price, 1700000000000), "custom",
+	}, clientOrderIDs)
+
+	// the orders are not changed without the order epoch
+	_, _, err = BatchPlaceOrder(context.Background(), mockExchange, nil, submitOrders[0])
+	assert.NoError(t, err)
+	assert.Equal(t, "", clientOrderIDs[4])
+}
@@ -0,0 +1,66 @@
+package bbgo
+
+import (
+	"context"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// OrderMiddleware is called with the formatted orders before the orders are sent to the exchange,
+// returning an error vetoes the whole batch
+type OrderMiddleware func(ctx context.Context, session *ExchangeSession, orders []types.SubmitOrder) error
+
+type namedOrderMiddleware struct {
+	name       string
+	middleware OrderMiddleware
+}
+
+// SetOrderMiddleware registers the order middleware of the session by the name, it applies to the orders
+// submitted through the order executors of the session. The middleware registered with the same name is replaced,
+// so that re-configuring the risk modules does not keep the stale middlewares.
+func (session *ExchangeSession) SetOrderMiddleware(name string, middleware OrderMiddleware) {
+	session.orderMiddlewaresMutex.Lock()
+	defer session.orderMiddlewaresMutex.Unlock()
+
+	for i, m := range session.orderMiddlewares {
+		if m.name == name {
+			session.orderMiddlewares[i].middleware = middleware
+			return
+		}
+	}
+
+	session.orderMiddlewares = append(session.orderMiddlewares, namedOrderMiddleware{name: name, middleware: middleware})
+}
+
+// ApplyOrderMiddlewares runs the registered order middlewares in order and stops at the first veto,
+// the order submission paths that don't go through the order executors should call it before submitting the orders
+func (session *ExchangeSession) ApplyOrderMiddlewares(ctx context.Context, orders []types.SubmitOrder) error {
+	session.orderMiddlewaresMutex.Lock()
+	middlewares := session.orderMiddlewares
+	session.orderMiddlewaresMutex.Unlock()
+
+	for _, m := range middlewares {
+		if err := m.middleware(ctx, session, orders); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// CheckSubmitOrders runs the checks shared by all the order submission paths on the formatted orders:
+// the order middlewares, e.g., the portfolio exposure veto and the strategy budget, and then the order rate budget
+func (session *ExchangeSession) CheckSubmitOrders(ctx context.Context, orders []types.SubmitOrder) error {
+	if err := session.ApplyOrderMiddlewares(ctx, orders); err != nil {
+		return err
+	}
+
+	return session.WaitOrderRateBudget(ctx, len(orders))
+}
+
+// SetOrderMiddleware registers the order middleware by the name on all the sessions of the environment
+func (environ *Environment) SetOrderMiddleware(name string, middleware OrderMiddleware) {
+	for _, session := range environ.sessions {
+		session.SetOrderMiddleware(name, middleware)
+	}
+}
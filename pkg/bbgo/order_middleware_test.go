@@ -0,0 +1,91 @@
+package bbgo
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+	"github.com/c9s/bbgo/pkg/types/mocks"
+)
+
+func TestExchangeSession_ApplyOrderMiddlewares(t *testing.T) {
+	session := &ExchangeSession{Name: "binance"}
+	orders := []types.SubmitOrder{{Symbol: "BTCUSDT", Side: types.SideTypeBuy}}
+	assert.NoError(t, session.ApplyOrderMiddlewares(context.Background(), orders))
+
+	var calls int
+	session.SetOrderMiddleware("counter", func(ctx context.Context, session *ExchangeSession, orders []types.SubmitOrder) error {
+		calls++
+		return nil
+	})
+
+	veto := errors.New("vetoed")
+	session.SetOrderMiddleware("veto", func(ctx context.Context, session *ExchangeSession, orders []types.SubmitOrder) error {
+		if orders[0].Side == types.SideTypeBuy {
+			return veto
+		}
+		return nil
+	})
+
+	assert.Equal(t, veto, session.ApplyOrderMiddlewares(context.Background(), orders))
+	assert.Equal(t, 1, calls)
+
+	orders[0].Side = types.SideTypeSell
+	assert.NoError(t, session.ApplyOrderMiddlewares(context.Background(), orders))
+	assert.Equal(t, 2, calls)
+
+	// the middleware registered with the same name is replaced
+	session.SetOrderMiddleware("veto", func(ctx context.Context, session *ExchangeSession, orders []types.SubmitOrder) error {
+		return nil
+	})
+
+	orders[0].Side = types.SideTypeBuy
+	assert.NoError(t, session.ApplyOrderMiddlewares(context.Background(), orders))
+	assert.Equal(t, 3, calls)
+
+	// the middlewares are not shared with the other sessions
+	other := &ExchangeSession{Name: "max"}
+	assert.NoError(t, other.ApplyOrderMiddlewares(context.Background(), orders))
+	assert.Equal(t, 3, calls)
+}
+
+func TestFastOrderExecutor_SubmitOrders_Middlewares(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	market := types.Market{
+		Symbol:        "BTCUSDT",
+		BaseCurrency:  "BTC",
+		QuoteCurrency: "USDT",
+		TickSize:      fixedpoint.NewFromFloat(0.01),
+		StepSize:      fixedpoint.NewFromFloat(0.0001),
+	}
+
+	mockEx := mocks.NewMockExchange(mockCtrl)
+	mockEx.EXPECT().NewStream().Return(&types.StandardStream{}).Times(2)
+	session := NewExchangeSession("binance", mockEx)
+	session.SetMarkets(types.MarketMap{market.Symbol: market})
+
+	veto := errors.New("vetoed")
+	session.SetOrderMiddleware("veto", func(ctx context.Context, session *ExchangeSession, orders []types.SubmitOrder) error {
+		return veto
+	})
+
+	executor := NewFastOrderExecutor(session, market.Symbol, "test", "test:BTCUSDT", types.NewPositionFromMarket(market))
+
+	// the vetoed orders are not sent to the exchange
+	createdOrders, err := executor.SubmitOrders(context.Background(), types.SubmitOrder{
+		Symbol:   market.Symbol,
+		Side:     types.SideTypeBuy,
+		Type:     types.OrderTypeLimit,
+		Price:    fixedpoint.NewFromFloat(30000.0),
+		Quantity: fixedpoint.NewFromFloat(0.1),
+	})
+	assert.Equal(t, veto, err)
+	assert.Empty(t, createdOrders)
+}
@@ -0,0 +1,114 @@
+package bbgo
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// OrderValidationPolicy defines how the orders that fail the precision or the minimal requirement
+// validation of the market are handled when they are formatted
+type OrderValidationPolicy string
+
+const (
+	// OrderValidationPolicyNone keeps the orders as they are and lets the exchange validate them
+	OrderValidationPolicyNone OrderValidationPolicy = ""
+
+	// OrderValidationPolicyAdjust truncates the price and the quantity to the market precision,
+	// and rounds the quantity up to the minimal quantity and the minimal notional
+	OrderValidationPolicyAdjust OrderValidationPolicy = "adjust"
+
+	// OrderValidationPolicyReject rejects the orders that fail the validation
+	OrderValidationPolicyReject OrderValidationPolicy = "reject"
+)
+
+var ErrOrderValidationFailed = errors.New("order validation failed")
+
+func (p OrderValidationPolicy) Validate() error {
+	switch p {
+	case OrderValidationPolicyNone, OrderValidationPolicyAdjust, OrderValidationPolicyReject:
+		return nil
+	}
+
+	return fmt.Errorf("invalid order validation policy %q", p)
+}
+
+// ValidateOrder validates the order against its market by the policy,
+// the order market must be set. It returns the adjusted order in the adjust policy.
+func (p OrderValidationPolicy) ValidateOrder(order types.SubmitOrder) (types.SubmitOrder, error) {
+	switch p {
+	case OrderValidationPolicyNone:
+		return order, nil
+
+	case OrderValidationPolicyAdjust:
+		return adjustOrder(order), nil
+
+	case OrderValidationPolicyReject:
+		return order, rejectOrder(order)
+	}
+
+	return order, p.Validate()
+}
+
+// orderNotionalPrice returns the price for checking the minimal notional,
+// it's zero for the market orders since their prices are unknown before execution.
+func orderNotionalPrice(order types.SubmitOrder) fixedpoint.Value {
+	if order.Type == types.OrderTypeMarket || order.Type == types.OrderTypeStopMarket {
+		return fixedpoint.Zero
+	}
+
+	return order.Price
+}
+
+func adjustOrder(order types.SubmitOrder) types.SubmitOrder {
+	market := order.Market
+
+	if market.TickSize.Sign() > 0 {
+		if order.Price.Sign() > 0 {
+			order.Price = market.TruncatePrice(order.Price)
+		}
+
+		if order.StopPrice.Sign() > 0 {
+			order.StopPrice = market.TruncatePrice(order.StopPrice)
+		}
+	}
+
+	if market.StepSize.Sign() > 0 {
+		order.Quantity = market.TruncateQuantity(order.Quantity)
+	}
+
+	order.Quantity = market.AdjustQuantityByMinQuantity(order.Quantity)
+
+	if price := orderNotionalPrice(order); price.Sign() > 0 && market.MinNotional.Sign() > 0 && market.StepSize.Sign() > 0 {
+		order.Quantity = market.AdjustQuantityByMinNotional(order.Quantity, price)
+	}
+
+	return order
+}
+
+func rejectOrder(order types.SubmitOrder) error {
+	market := order.Market
+
+	if market.TickSize.Sign() > 0 && order.Price.Sign() > 0 && market.TruncatePrice(order.Price).Compare(order.Price) != 0 {
+		return errors.Wrapf(ErrOrderValidationFailed, "%s price %v does not match the tick size %v", order.Symbol, order.Price, market.TickSize)
+	}
+
+	if market.StepSize.Sign() > 0 && market.TruncateQuantity(order.Quantity).Compare(order.Quantity) != 0 {
+		return errors.Wrapf(ErrOrderValidationFailed, "%s quantity %v does not match the step size %v", order.Symbol, order.Quantity, market.StepSize)
+	}
+
+	if order.Quantity.Compare(market.MinQuantity) < 0 {
+		return errors.Wrapf(ErrOrderValidationFailed, "%s quantity %v is less than the min quantity %v", order.Symbol, order.Quantity, market.MinQuantity)
+	}
+
+	if price := orderNotionalPrice(order); price.Sign() > 0 {
+		if notional := order.Quantity.Mul(price); notional.Compare(market.MinNotional) < 0 {
+			return errors.Wrapf(ErrOrderValidationFailed, "%s notional %v is less than the min notional %v", order.Symbol, notional, market.MinNotional)
+		}
+	}
+
+	return nil
+}
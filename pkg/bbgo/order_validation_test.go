@@ -0,0 +1,86 @@
+package bbgo
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestOrderValidationPolicy_ValidateOrder(t *testing.T) {
+	number := fixedpoint.MustNewFromString
+
+	market := types.Market{
+		Symbol:        "BTCUSDT",
+		BaseCurrency:  "BTC",
+		QuoteCurrency: "USDT",
+		TickSize:      number("0.01"),
+		StepSize:      number("0.0001"),
+		MinQuantity:   number("0.001"),
+		MinNotional:   number("10"),
+	}
+
+	newOrder := func(price, quantity string) types.SubmitOrder {
+		return types.SubmitOrder{
+			Symbol:   "BTCUSDT",
+			Market:   market,
+			Side:     types.SideTypeBuy,
+			Type:     types.OrderTypeLimit,
+			Price:    number(price),
+			Quantity: number(quantity),
+		}
+	}
+
+	t.Run("none", func(t *testing.T) {
+		order := newOrder("20000.123", "0.00012")
+		validated, err := OrderValidationPolicyNone.ValidateOrder(order)
+		assert.NoError(t, err)
+		assert.Equal(t, order, validated)
+	})
+
+	t.Run("adjust precision", func(t *testing.T) {
+		validated, err := OrderValidationPolicyAdjust.ValidateOrder(newOrder("20000.123", "0.01234"))
+		assert.NoError(t, err)
+		assert.Equal(t, "20000.12", validated.Price.String())
+		assert.Equal(t, "0.0123", validated.Quantity.String())
+	})
+
+	t.Run("adjust min quantity and min notional", func(t *testing.T) {
+		validated, err := OrderValidationPolicyAdjust.ValidateOrder(newOrder("5000", "0.0001"))
+		assert.NoError(t, err)
+		assert.Equal(t, "0.002", validated.Quantity.String())
+	})
+
+	t.Run("reject", func(t *testing.T) {
+		_, err := OrderValidationPolicyReject.ValidateOrder(newOrder("20000.123", "0.01"))
+		assert.True(t, errors.Is(err, ErrOrderValidationFailed))
+		assert.ErrorContains(t, err, "tick size")
+
+		_, err = OrderValidationPolicyReject.ValidateOrder(newOrder("20000", "0.01234"))
+		assert.ErrorContains(t, err, "step size")
+
+		_, err = OrderValidationPolicyReject.ValidateOrder(newOrder("20000", "0.0001"))
+		assert.ErrorContains(t, err, "min quantity")
+
+		_, err = OrderValidationPolicyReject.ValidateOrder(newOrder("5000", "0.001"))
+		assert.ErrorContains(t, err, "min notional")
+
+		_, err = OrderValidationPolicyReject.ValidateOrder(newOrder("20000", "0.001"))
+		assert.NoError(t, err)
+	})
+
+	t.Run("market order skips the notional check", func(t *testing.T) {
+		order := newOrder("0", "0.001")
+		order.Type = types.OrderTypeMarket
+		_, err := OrderValidationPolicyReject.ValidateOrder(order)
+		assert.NoError(t, err)
+	})
+
+	t.Run("invalid policy", func(t *testing.T) {
+		_, err := OrderValidationPolicy("truncate").ValidateOrder(newOrder("20000", "0.001"))
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,45 @@
+package bbgo
+
+import (
+	"context"
+	"errors"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// placeOrder places the order through the websocket trading API of the exchange when it's available,
+// and falls back to the REST API when the order is not sent through the websocket connection.
+func placeOrder(ctx context.Context, exchange types.Exchange, submitOrder types.SubmitOrder) (*types.Order, error) {
+	service, ok := exchange.(types.ExchangeWebSocketOrderEntryService)
+	if !ok || !service.WebSocketOrderEntryAvailable() {
+		return exchange.SubmitOrder(ctx, submitOrder)
+	}
+
+	createdOrder, err := service.SubmitOrderByWebSocket(ctx, submitOrder)
+	if err == nil {
+		return createdOrder, nil
+	}
+
+	// the order might be sent through the websocket connection, falling back to the REST API may place a duplicated order
+	if !errors.Is(err, types.ErrWebSocketOrderEntryUnavailable) {
+		return createdOrder, err
+	}
+
+	log.WithError(err).Warnf("websocket order entry of %s is unavailable, falling back to the rest api: %s", exchange.Name(), submitOrder.String())
+	return exchange.SubmitOrder(ctx, submitOrder)
+}
+
+// EnableWebSocketOrderEntry opens the websocket trading connection of the exchange for the latency-sensitive strategies,
+// it returns false if the exchange doesn't support the websocket order entry.
+func EnableWebSocketOrderEntry(ctx context.Context, exchange types.Exchange) bool {
+	enabler, ok := exchange.(types.ExchangeWebSocketOrderEntryEnabler)
+	if !ok {
+		log.Warnf("%s does not support the websocket order entry, the orders are placed through the rest api", exchange.Name())
+		return false
+	}
+
+	enabler.EnableWebSocketOrderEntry(ctx)
+	return true
+}
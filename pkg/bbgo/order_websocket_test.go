@@ -0,0 +1,121 @@
+package bbgo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+	"github.com/c9s/bbgo/pkg/types/mocks"
+)
+
+type testWebSocketOrderExchange struct {
+	*mocks.MockExchange
+
+	available bool
+	err       error
+	orders    []types.SubmitOrder
+}
+
+func (e *testWebSocketOrderExchange) WebSocketOrderEntryAvailable() bool {
+	return e.available
+}
+
+func (e *testWebSocketOrderExchange) SubmitOrderByWebSocket(
+	ctx context.Context, order types.SubmitOrder,
+) (*types.Order, error) {
+	e.orders = append(e.orders, order)
+	if e.err != nil {
+		return nil, e.err
+	}
+
+	return &types.Order{SubmitOrder: order, OrderID: 1, Status: types.OrderStatusNew}, nil
+}
+
+func TestBatchPlaceOrder_webSocketOrderEntry(t *testing.T) {
+	submitOrder := types.SubmitOrder{
+		Symbol:   "BTCUSDT",
+		Side:     types.SideTypeBuy,
+		Type:     types.OrderTypeLimit,
+		Quantity: fixedpoint.One,
+		Price:    fixedpoint.NewFromFloat(40000.0),
+	}
+
+	restOrder := &types.Order{SubmitOrder: submitOrder, OrderID: 2, Status: types.OrderStatusNew}
+
+	t.Run("websocket", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		exchange := &testWebSocketOrderExchange{MockExchange: mocks.NewMockExchange(mockCtrl), available: true}
+
+		createdOrders, errIdx, err := BatchPlaceOrder(context.Background(), exchange, nil, submitOrder)
+		assert.NoError(t, err)
+		assert.Empty(t, errIdx)
+		assert.Len(t, exchange.orders, 1)
+		if assert.Len(t, createdOrders, 1) {
+			assert.Equal(t, uint64(1), createdOrders[0].OrderID)
+		}
+	})
+
+	t.Run("unavailable", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		mockExchange := mocks.NewMockExchange(mockCtrl)
+		mockExchange.EXPECT().SubmitOrder(gomock.Any(), submitOrder).Return(restOrder, nil)
+
+		exchange := &testWebSocketOrderExchange{MockExchange: mockExchange}
+
+		createdOrders, _, err := BatchPlaceOrder(context.Background(), exchange, nil, submitOrder)
+		assert.NoError(t, err)
+		assert.Empty(t, exchange.orders)
+		if assert.Len(t, createdOrders, 1) {
+			assert.Equal(t, uint64(2), createdOrders[0].OrderID)
+		}
+	})
+
+	t.Run("fallback to rest", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		mockExchange := mocks.NewMockExchange(mockCtrl)
+		mockExchange.EXPECT().Name().Return(types.ExchangeBinance).AnyTimes()
+		mockExchange.EXPECT().SubmitOrder(gomock.Any(), submitOrder).Return(restOrder, nil)
+
+		exchange := &testWebSocketOrderExchange{
+			MockExchange: mockExchange,
+			available:    true,
+			err:          fmt.Errorf("connection closed: %w", types.ErrWebSocketOrderEntryUnavailable),
+		}
+
+		createdOrders, _, err := BatchPlaceOrder(context.Background(), exchange, nil, submitOrder)
+		assert.NoError(t, err)
+		assert.Len(t, exchange.orders, 1)
+		if assert.Len(t, createdOrders, 1) {
+			assert.Equal(t, uint64(2), createdOrders[0].OrderID)
+		}
+	})
+
+	t.Run("rejected", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		// the rejected order should not be re-submitted through the rest api
+		exchange := &testWebSocketOrderExchange{
+			MockExchange: mocks.NewMockExchange(mockCtrl),
+			available:    true,
+			err:          errors.New("insufficient balance"),
+		}
+
+		createdOrders, errIdx, err := BatchPlaceOrder(context.Background(), exchange, nil, submitOrder)
+		assert.Error(t, err)
+		assert.Equal(t, []int{0}, errIdx)
+		assert.Empty(t, createdOrders)
+	})
+}
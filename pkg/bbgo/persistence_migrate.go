@@ -0,0 +1,92 @@
+package bbgo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/c9s/bbgo/pkg/dynamic"
+	"github.com/c9s/bbgo/pkg/service"
+)
+
+// PersistenceMigrationRecord is the migration result of one persistence field of a strategy instance
+type PersistenceMigrationRecord struct {
+	ID    string `json:"id"`
+	Field string `json:"field"`
+	Size  int    `json:"size"`
+
+	// Missing is true when the field is not persisted in the source backend
+	Missing bool `json:"missing,omitempty"`
+
+	// Verified is true when the copied data is loaded back from the destination backend and matches the source data
+	Verified bool `json:"verified,omitempty"`
+}
+
+// MigratePersistenceFields copies the persisted fields (the fields with the persistence tag) of the strategy instance
+// from one persistence backend to another. The data is copied as the raw JSON so that the field types are preserved,
+// and the copied data is verified by loading it back from the destination backend.
+// In the dry-run mode, the data is only loaded from the source backend.
+func MigratePersistenceFields(
+	obj interface{}, from, to service.PersistenceService, dryRun bool,
+) (records []PersistenceMigrationRecord, err error) {
+	id := dynamic.CallID(obj)
+	if len(id) == 0 {
+		return nil, fmt.Errorf("%T does not provide the instance ID", obj)
+	}
+
+	err = dynamic.IterateFieldsByTag(obj, "persistence", true, func(tag string, ft reflect.StructField, fv reflect.Value) error {
+		record := PersistenceMigrationRecord{ID: id, Field: tag}
+
+		var data json.RawMessage
+		if err := from.NewStore("state", id, tag).Load(&data); err != nil {
+			if err == service.ErrPersistenceNotExists {
+				record.Missing = true
+				records = append(records, record)
+				return nil
+			}
+
+			return fmt.Errorf("unable to load %s.%s from the source backend: %w", id, tag, err)
+		}
+
+		record.Size = len(data)
+		if dryRun {
+			records = append(records, record)
+			return nil
+		}
+
+		store := to.NewStore("state", id, tag)
+		if err := store.Save(data); err != nil {
+			return fmt.Errorf("unable to save %s.%s to the destination backend: %w", id, tag, err)
+		}
+
+		var saved json.RawMessage
+		if err := store.Load(&saved); err != nil {
+			return fmt.Errorf("unable to verify %s.%s on the destination backend: %w", id, tag, err)
+		}
+
+		if !equalJSON(data, saved) {
+			return fmt.Errorf("verification failed, %s.%s on the destination backend does not match the source data", id, tag)
+		}
+
+		record.Verified = true
+		records = append(records, record)
+		return nil
+	})
+
+	return records, err
+}
+
+// equalJSON compares the JSON data without the insignificant whitespaces
+func equalJSON(a, b []byte) bool {
+	var bufA, bufB bytes.Buffer
+	if err := json.Compact(&bufA, a); err != nil {
+		return false
+	}
+
+	if err := json.Compact(&bufB, b); err != nil {
+		return false
+	}
+
+	return bytes.Equal(bufA.Bytes(), bufB.Bytes())
+}
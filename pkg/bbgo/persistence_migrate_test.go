@@ -0,0 +1,61 @@
+package bbgo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/service"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestMigratePersistenceFields(t *testing.T) {
+	from := &service.JsonPersistenceService{Directory: t.TempDir()}
+	to := &service.JsonPersistenceService{Directory: t.TempDir()}
+
+	position := types.NewPositionFromMarket(types.Market{
+		Symbol:        "BTCUSDT",
+		BaseCurrency:  "BTC",
+		QuoteCurrency: "USDT",
+	})
+	position.Base = fixedpoint.NewFromFloat(0.5)
+	position.AverageCost = fixedpoint.NewFromFloat(20000.0)
+
+	// the string field is not persisted
+	origin := &TestStruct{Position: position, Integer: 7}
+	assert.NoError(t, storePersistenceFields(origin, "test-struct", from))
+	assert.NoError(t, from.NewStore("state", "test-struct", "string").Reset())
+
+	t.Run("dry-run", func(t *testing.T) {
+		records, err := MigratePersistenceFields(&TestStruct{}, from, to, true)
+		assert.NoError(t, err)
+		assert.Len(t, records, 5)
+
+		// nothing is copied in the dry-run mode
+		var loaded TestStruct
+		assert.NoError(t, loadPersistenceFields(&loaded, "test-struct", to))
+		assert.Nil(t, loaded.Position)
+	})
+
+	t.Run("migrate", func(t *testing.T) {
+		records, err := MigratePersistenceFields(&TestStruct{}, from, to, false)
+		assert.NoError(t, err)
+
+		for _, record := range records {
+			if record.Field == "string" {
+				assert.True(t, record.Missing)
+			} else {
+				assert.True(t, record.Verified, record.Field)
+			}
+		}
+
+		var loaded TestStruct
+		assert.NoError(t, loadPersistenceFields(&loaded, "test-struct", to))
+		if assert.NotNil(t, loaded.Position) {
+			assert.Equal(t, "0.5", loaded.Position.Base.String())
+			assert.Equal(t, "20000", loaded.Position.AverageCost.String())
+		}
+		assert.Equal(t, int64(7), loaded.Integer)
+	})
+}
@@ -0,0 +1,101 @@
+package bbgo
+
+import (
+	"fmt"
+	"path/filepath"
+	"plugin"
+	"sort"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// PluginRegisterSymbol is the optional function symbol exported by the strategy plugin,
+// it will be called after the plugin is opened. The plugin can also register its strategies in the init function.
+//
+//	func RegisterStrategies() {
+//		bbgo.RegisterStrategy("mystrategy", &Strategy{})
+//	}
+const PluginRegisterSymbol = "RegisterStrategies"
+
+var loadedPlugins = struct {
+	sync.Mutex
+	paths map[string]struct{}
+}{paths: make(map[string]struct{})}
+
+// LoadPlugins loads the strategy plugins built with `go build -buildmode=plugin`,
+// the plugin registers its strategies via RegisterStrategy, so that the strategy config
+// and the persistence fields are handled the same as the built-in strategies.
+//
+// Note that the plugin must be built with the same Go version and the same bbgo module version as the host binary.
+func LoadPlugins(paths ...string) error {
+	for _, p := range paths {
+		if err := LoadPlugin(p); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// LoadPlugin opens the strategy plugin of the given path, a plugin is only loaded once.
+func LoadPlugin(path string) error {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+
+	loadedPlugins.Lock()
+	defer loadedPlugins.Unlock()
+
+	if _, ok := loadedPlugins.paths[absPath]; ok {
+		return nil
+	}
+
+	before := registeredStrategyIDs()
+
+	p, err := plugin.Open(absPath)
+	if err != nil {
+		return fmt.Errorf("unable to open strategy plugin %s: %w", path, err)
+	}
+
+	if sym, err := p.Lookup(PluginRegisterSymbol); err == nil {
+		register, ok := sym.(func())
+		if !ok {
+			return fmt.Errorf("strategy plugin %s: %s should be a func(), got %T", path, PluginRegisterSymbol, sym)
+		}
+
+		register()
+	}
+
+	loadedPlugins.paths[absPath] = struct{}{}
+
+	var registered []string
+	for id := range registeredStrategyIDs() {
+		if _, ok := before[id]; !ok {
+			registered = append(registered, id)
+		}
+	}
+
+	sort.Strings(registered)
+	if len(registered) == 0 {
+		log.Warnf("strategy plugin %s does not register any strategy", path)
+	} else {
+		log.Infof("strategy plugin %s loaded, registered strategies: %v", path, registered)
+	}
+
+	return nil
+}
+
+func registeredStrategyIDs() map[string]struct{} {
+	ids := make(map[string]struct{}, len(LoadedExchangeStrategies)+len(LoadedCrossExchangeStrategies))
+	for id := range LoadedExchangeStrategies {
+		ids[id] = struct{}{}
+	}
+
+	for id := range LoadedCrossExchangeStrategies {
+		ids[id] = struct{}{}
+	}
+
+	return ids
+}
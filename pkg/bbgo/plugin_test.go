@@ -0,0 +1,21 @@
+package bbgo
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_resolvePluginPaths(t *testing.T) {
+	paths := resolvePluginPaths("config/bbgo.yaml", []string{"plugins/mystrategy.so", "/opt/bbgo/plugins/grid.so"})
+	assert.Equal(t, []string{
+		filepath.Join("config", "plugins", "mystrategy.so"),
+		"/opt/bbgo/plugins/grid.so",
+	}, paths)
+}
+
+func TestLoadPlugin_NotFound(t *testing.T) {
+	err := LoadPlugin(filepath.Join(t.TempDir(), "not-found.so"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,191 @@
+package bbgo
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/cache"
+	"github.com/c9s/bbgo/pkg/dynamic"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// PortfolioAsset is the consolidated exposure of one asset across all the sessions
+type PortfolioAsset struct {
+	Currency string `json:"currency"`
+
+	// NetExposure is the net asset (total - borrowed - interest) summed across the sessions
+	NetExposure fixedpoint.Value `json:"netExposure"`
+
+	PriceInUSD fixedpoint.Value `json:"priceInUSD"`
+	InUSD      fixedpoint.Value `json:"inUSD"`
+
+	// Sessions is the net asset of each session, keyed by the session name
+	Sessions map[string]fixedpoint.Value `json:"sessions"`
+
+	// Strategies is the position contributed by each strategy, keyed by the strategy instance ID
+	Strategies map[string]fixedpoint.Value `json:"strategies,omitempty"`
+}
+
+// Portfolio is the consolidated view of the assets across all the sessions
+type Portfolio struct {
+	Assets map[string]*PortfolioAsset `json:"assets"`
+
+	TotalInUSD fixedpoint.Value `json:"totalInUSD"`
+
+	Time time.Time `json:"time"`
+}
+
+func NewPortfolio(now time.Time) *Portfolio {
+	return &Portfolio{
+		Assets: make(map[string]*PortfolioAsset),
+		Time:   now,
+	}
+}
+
+func (p *Portfolio) asset(currency string) *PortfolioAsset {
+	asset, ok := p.Assets[currency]
+	if !ok {
+		asset = &PortfolioAsset{
+			Currency: currency,
+			Sessions: make(map[string]fixedpoint.Value),
+		}
+		p.Assets[currency] = asset
+	}
+
+	return asset
+}
+
+// AddSessionAssets adds the valued assets of the session to the portfolio
+func (p *Portfolio) AddSessionAssets(sessionName string, assets types.AssetMap) {
+	for currency, a := range assets {
+		asset := p.asset(currency)
+		asset.NetExposure = asset.NetExposure.Add(a.NetAsset)
+		asset.InUSD = asset.InUSD.Add(a.InUSD)
+		asset.Sessions[sessionName] = asset.Sessions[sessionName].Add(a.NetAsset)
+		if !a.PriceInUSD.IsZero() {
+			asset.PriceInUSD = a.PriceInUSD
+		}
+
+		p.TotalInUSD = p.TotalInUSD.Add(a.InUSD)
+	}
+}
+
+// AddStrategyPosition adds the base and the quote of the strategy position to the strategy contribution breakdown
+func (p *Portfolio) AddStrategyPosition(strategyID string, position *types.Position) {
+	contribute := func(currency string, amount fixedpoint.Value) {
+		if currency == "" || amount.IsZero() {
+			return
+		}
+
+		asset := p.asset(currency)
+		if asset.Strategies == nil {
+			asset.Strategies = make(map[string]fixedpoint.Value)
+		}
+
+		asset.Strategies[strategyID] = asset.Strategies[strategyID].Add(amount)
+	}
+
+	position.Lock()
+	base, quote := position.Base, position.Quote
+	position.Unlock()
+
+	contribute(position.BaseCurrency, base)
+	contribute(position.QuoteCurrency, quote)
+}
+
+func (p *Portfolio) String() string {
+	currencies := make([]string, 0, len(p.Assets))
+	for currency := range p.Assets {
+		currencies = append(currencies, currency)
+	}
+
+	// the largest exposure first
+	sort.Slice(currencies, func(i, j int) bool {
+		return p.Assets[currencies[i]].InUSD.Abs().Compare(p.Assets[currencies[j]].InUSD.Abs()) > 0
+	})
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("PORTFOLIO %s total %s\n", p.Time.Format(time.RFC3339), types.USD.FormatMoney(p.TotalInUSD)))
+	for _, currency := range currencies {
+		asset := p.Assets[currency]
+		sb.WriteString(fmt.Sprintf("%s: %v ~= %s\n", currency, asset.NetExposure, types.USD.FormatMoney(asset.InUSD)))
+
+		for _, sessionName := range sortedKeys(asset.Sessions) {
+			sb.WriteString(fmt.Sprintf("  session %s: %v\n", sessionName, asset.Sessions[sessionName]))
+		}
+
+		for _, strategyID := range sortedKeys(asset.Strategies) {
+			sb.WriteString(fmt.Sprintf("  strategy %s: %v\n", strategyID, asset.Strategies[strategyID]))
+		}
+	}
+
+	return sb.String()
+}
+
+// QueryPortfolio refreshes the accounts of the sessions from the exchanges and consolidates the balances
+// into a portfolio valued in USD
+func (environ *Environment) QueryPortfolio(ctx context.Context) (*Portfolio, error) {
+	portfolio := NewPortfolio(time.Now())
+
+	sessions := environ.Sessions()
+	for _, sessionName := range sortedKeys(sessions) {
+		session := sessions[sessionName]
+		if session.PublicOnly {
+			continue
+		}
+
+		// the markets are required for finding the price symbols
+		if len(session.Markets()) == 0 {
+			markets, err := cache.LoadExchangeMarketsWithCache(ctx, session.Exchange)
+			if err != nil {
+				return nil, fmt.Errorf("session %s: unable to load markets: %w", sessionName, err)
+			}
+
+			session.SetMarkets(markets)
+		}
+
+		account, err := session.UpdateAccount(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("session %s: unable to query account: %w", sessionName, err)
+		}
+
+		balances := account.Balances()
+		if err := session.UpdatePrices(ctx, balances.Currencies(), "USDT"); err != nil {
+			return nil, fmt.Errorf("session %s: unable to update prices: %w", sessionName, err)
+		}
+
+		portfolio.AddSessionAssets(sessionName, balances.Assets(session.LastPrices(), portfolio.Time))
+	}
+
+	return portfolio, nil
+}
+
+// AddStrategyPositions adds the positions of the strategies that have the Position field to the portfolio
+func (trader *Trader) AddStrategyPositions(portfolio *Portfolio) error {
+	return trader.IterateStrategies(func(strategy StrategyID) error {
+		if position, ok := FindStrategyPosition(strategy); ok {
+			portfolio.AddStrategyPosition(dynamic.CallID(strategy), position)
+		}
+		return nil
+	})
+}
+
+// FindStrategyPosition returns the position of the strategy from its Position field
+func FindStrategyPosition(strategy StrategyID) (*types.Position, bool) {
+	rs := reflect.ValueOf(strategy)
+	if rs.Kind() != reflect.Ptr || rs.Elem().Kind() != reflect.Struct {
+		return nil, false
+	}
+
+	field := rs.Elem().FieldByName("Position")
+	if !field.IsValid() || field.Type() != reflect.TypeOf(&types.Position{}) || field.IsNil() {
+		return nil, false
+	}
+
+	return field.Interface().(*types.Position), true
+}
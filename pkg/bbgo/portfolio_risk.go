@@ -0,0 +1,62 @@
+package bbgo
+
+import (
+	"context"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/risk"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// PriceInUSD returns the USD price of the currency from the last prices of the sessions
+func (environ *Environment) PriceInUSD(currency string) (fixedpoint.Value, bool) {
+	if types.IsUSDFiatCurrency(currency) {
+		return fixedpoint.One, true
+	}
+
+	sessions := environ.Sessions()
+	for _, sessionName := range sortedKeys(sessions) {
+		session := sessions[sessionName]
+		for _, fiat := range types.USDFiatCurrencies {
+			if price, ok := session.LastPrice(currency + fiat); ok && price.Sign() > 0 {
+				return price, true
+			}
+		}
+	}
+
+	return fixedpoint.Zero, false
+}
+
+// StrategyPositions returns the positions of the strategies that have the Position field
+func (trader *Trader) StrategyPositions() (positions []*types.Position) {
+	_ = trader.IterateStrategies(func(strategy StrategyID) error {
+		if position, ok := FindStrategyPosition(strategy); ok {
+			positions = append(positions, position)
+		}
+		return nil
+	})
+
+	return positions
+}
+
+// ConfigurePortfolioRisk aggregates the positions of the strategies into the portfolio exposure
+// and vetoes the orders that increase the exposure over the given limits
+func (trader *Trader) ConfigurePortfolioRisk(config *risk.ExposureLimitConfig) error {
+	if err := config.Validate(); err != nil {
+		return err
+	}
+
+	trader.portfolioRisk = risk.NewPortfolioRiskManager(config, trader.StrategyPositions, trader.environment.PriceInUSD)
+
+	manager := trader.portfolioRisk
+	trader.environment.SetOrderMiddleware("portfolioRisk", func(ctx context.Context, session *ExchangeSession, orders []types.SubmitOrder) error {
+		return manager.CheckOrders(orders...)
+	})
+
+	return nil
+}
+
+// PortfolioRisk returns the portfolio risk manager, it's nil when the portfolio risk is not configured
+func (trader *Trader) PortfolioRisk() *risk.PortfolioRiskManager {
+	return trader.portfolioRisk
+}
@@ -0,0 +1,46 @@
+package bbgo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestPortfolio_AddSessionAssets(t *testing.T) {
+	number := fixedpoint.MustNewFromString
+	now := time.Now()
+	prices := types.PriceMap{"BTCUSDT": number("20000")}
+
+	portfolio := NewPortfolio(now)
+	portfolio.AddSessionAssets("binance", types.BalanceMap{
+		"BTC":  {Currency: "BTC", Available: number("1")},
+		"USDT": {Currency: "USDT", Available: number("1000")},
+	}.Assets(prices, now))
+	portfolio.AddSessionAssets("max", types.BalanceMap{
+		"BTC": {Currency: "BTC", Available: number("0.5"), Borrowed: number("1")},
+	}.Assets(prices, now))
+
+	btc := portfolio.Assets["BTC"]
+	if assert.NotNil(t, btc) {
+		assert.Equal(t, "0.5", btc.NetExposure.String())
+		assert.Equal(t, "10000", btc.InUSD.String())
+		assert.Equal(t, "20000", btc.PriceInUSD.String())
+		assert.Equal(t, "1", btc.Sessions["binance"].String())
+		assert.Equal(t, "-0.5", btc.Sessions["max"].String())
+	}
+
+	assert.Equal(t, "11000", portfolio.TotalInUSD.String())
+
+	position := types.NewPositionFromMarket(types.Market{Symbol: "BTCUSDT", BaseCurrency: "BTC", QuoteCurrency: "USDT"})
+	position.Base = number("0.2")
+	position.Quote = number("-4000")
+	portfolio.AddStrategyPosition("xmaker:BTCUSDT", position)
+
+	assert.Equal(t, "0.2", portfolio.Assets["BTC"].Strategies["xmaker:BTCUSDT"].String())
+	assert.Equal(t, "-4000", portfolio.Assets["USDT"].Strategies["xmaker:BTCUSDT"].String())
+	assert.Contains(t, portfolio.String(), "strategy xmaker:BTCUSDT: 0.2")
+}
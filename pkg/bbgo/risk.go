@@ -8,6 +8,7 @@ import (
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 
+	"github.com/c9s/bbgo/pkg/core"
 	"github.com/c9s/bbgo/pkg/fixedpoint"
 	"github.com/c9s/bbgo/pkg/risk"
 	"github.com/c9s/bbgo/pkg/types"
@@ -25,6 +26,9 @@ type AccountValueCalculator struct {
 	prices        map[string]fixedpoint.Value
 	tickers       map[string]types.Ticker
 	updateTime    time.Time
+
+	// priceResolver provides the index prices that override the last prices of the session tickers
+	priceResolver core.PriceResolver
 }
 
 func NewAccountValueCalculator(session *ExchangeSession, quoteCurrency string) *AccountValueCalculator {
@@ -36,6 +40,12 @@ func NewAccountValueCalculator(session *ExchangeSession, quoteCurrency string) *
 	}
 }
 
+// SetPriceResolver marks the balances with the prices of the resolver, e.g. the external index prices,
+// instead of the last prices of the session, the last prices are used when the resolver has no price.
+func (c *AccountValueCalculator) SetPriceResolver(resolver core.PriceResolver) {
+	c.priceResolver = resolver
+}
+
 func (c *AccountValueCalculator) UpdatePrices(ctx context.Context) error {
 	balances := c.session.Account.Balances()
 	currencies := balances.Currencies()
@@ -61,6 +71,19 @@ func (c *AccountValueCalculator) UpdatePrices(ctx context.Context) error {
 			c.updateTime = ticker.Time
 		}
 	}
+
+	if c.priceResolver != nil {
+		for _, currency := range currencies {
+			if currency == c.quoteCurrency {
+				continue
+			}
+
+			if price, ok := c.priceResolver.ResolvePrice(currency, c.quoteCurrency); ok && price.Sign() > 0 {
+				c.prices[currency+c.quoteCurrency] = price
+			}
+		}
+	}
+
 	return nil
 }
 
@@ -148,6 +171,53 @@ func calculateNetValueInQuote(balances types.BalanceMap, prices types.PriceMap,
 	return accountValue
 }
 
+// CollateralValue calculates the unified collateral value of the portfolio margin account, the asset equities
+// of the cross margin and the futures are discounted by the collateral rates.
+// The net value is returned when the account is not in the portfolio margin mode.
+func (c *AccountValueCalculator) CollateralValue(ctx context.Context) (fixedpoint.Value, error) {
+	info := c.session.Account.PortfolioMarginInfo
+	if info == nil {
+		return c.NetValue(ctx)
+	}
+
+	if len(c.prices) == 0 {
+		if err := c.UpdatePrices(ctx); err != nil {
+			return fixedpoint.Zero, err
+		}
+	}
+
+	return calculateCollateralValueInQuote(info, c.prices, c.quoteCurrency), nil
+}
+
+func calculateCollateralValueInQuote(
+	info *types.PortfolioMarginAccountInfo, prices types.PriceMap, quoteCurrency string,
+) (collateralValue fixedpoint.Value) {
+	collateralValue = fixedpoint.Zero
+
+	for currency, asset := range info.Assets {
+		// only the positive equity is discounted, the negative equity is counted in full
+		equity := asset.Equity()
+		if equity.Sign() > 0 {
+			equity = equity.Mul(info.CollateralRate(currency))
+		}
+
+		if currency == quoteCurrency {
+			collateralValue = collateralValue.Add(equity)
+			continue
+		}
+
+		symbol := currency + quoteCurrency
+		symbolReverse := quoteCurrency + currency
+		if price, ok := prices[symbol]; ok {
+			collateralValue = collateralValue.Add(equity.Mul(price))
+		} else if priceReverse, ok2 := prices[symbolReverse]; ok2 {
+			collateralValue = collateralValue.Add(equity.Div(priceReverse))
+		}
+	}
+
+	return collateralValue
+}
+
 func (c *AccountValueCalculator) AvailableQuote(ctx context.Context) (fixedpoint.Value, error) {
 	accountValue := fixedpoint.Zero
 
@@ -178,8 +248,20 @@ func (c *AccountValueCalculator) AvailableQuote(ctx context.Context) (fixedpoint
 
 // MarginLevel calculates the margin level from the asset market value and the debt value
 // See https://www.binance.com/en/support/faq/360030493931
+//
+// For the portfolio margin account, the margin level is the unified maintenance margin ratio (uniMMR),
+// which is the collateral value divided by the maintenance margin.
 func (c *AccountValueCalculator) MarginLevel(ctx context.Context) (fixedpoint.Value, error) {
 	marginLevel := fixedpoint.Zero
+	if info := c.session.Account.PortfolioMarginInfo; info != nil && info.AccountMaintMargin.Sign() > 0 {
+		collateralValue, err := c.CollateralValue(ctx)
+		if err != nil {
+			return marginLevel, err
+		}
+
+		return collateralValue.Div(info.AccountMaintMargin), nil
+	}
+
 	marketValue, err := c.MarketValue(ctx)
 	if err != nil {
 		return marginLevel, err
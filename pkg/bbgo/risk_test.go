@@ -156,6 +156,79 @@ func TestNewAccountValueCalculator_MarginLevel(t *testing.T) {
 		marginLevel.FormatString(6))
 }
 
+func TestAccountValueCalculator_PortfolioMargin(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockEx := mocks.NewMockExchange(mockCtrl)
+	// for market data stream and user data stream
+	mockEx.EXPECT().NewStream().Return(&types.StandardStream{}).Times(2)
+	mockEx.EXPECT().QueryTickers(gomock.Any(), []string{"BTCUSDT"}).Return(map[string]types.Ticker{
+		"BTCUSDT": newTestTicker(),
+	}, nil)
+
+	session := NewExchangeSession("test", mockEx)
+	session.Account.UpdateBalances(types.BalanceMap{
+		"BTC":  {Currency: "BTC", Available: number(1.0)},
+		"USDT": {Currency: "USDT", Available: number(1000.0), Borrowed: number(500.0)},
+	})
+	session.Account.PortfolioMarginInfo = &types.PortfolioMarginAccountInfo{
+		AccountMaintMargin: number(1000.0),
+		Assets: types.PortfolioMarginAssetMap{
+			"BTC": {Asset: "BTC", WalletBalance: number(1.0)},
+			"USDT": {
+				Asset:            "USDT",
+				WalletBalance:    number(1000.0),
+				Borrowed:         number(500.0),
+				UnrealizedProfit: number(-100.0),
+			},
+		},
+		CollateralRates: map[string]fixedpoint.Value{
+			"BTC": number(0.95),
+		},
+	}
+
+	cal := NewAccountValueCalculator(session, "USDT")
+
+	ctx := context.Background()
+	collateralValue, err := cal.CollateralValue(ctx)
+	assert.NoError(t, err)
+
+	// 1.0 * 19000 * 0.95 + (1000 - 500 - 100)
+	assert.Equal(t, number(19000.0*0.95+400.0).String(), collateralValue.String())
+
+	marginLevel, err := cal.MarginLevel(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, number((19000.0*0.95+400.0)/1000.0).String(), marginLevel.String())
+}
+
+func Test_calculateCollateralValueInQuote(t *testing.T) {
+	info := &types.PortfolioMarginAccountInfo{
+		Assets: types.PortfolioMarginAssetMap{
+			"BTC":  {Asset: "BTC", WalletBalance: number(0.0), Borrowed: number(0.1)},
+			"ETH":  {Asset: "ETH", WalletBalance: number(2.0)},
+			"USDT": {Asset: "USDT", WalletBalance: number(5000.0)},
+			"TWD":  {Asset: "TWD", WalletBalance: number(3000.0)},
+		},
+		CollateralRates: map[string]fixedpoint.Value{
+			"BTC":  number(0.95),
+			"ETH":  number(0.9),
+			"USDT": number(1.0),
+			"TWD":  number(0.5),
+		},
+	}
+
+	prices := types.PriceMap{
+		"BTCUSDT": number(20000.0),
+		"ETHUSDT": number(1000.0),
+		"USDTTWD": number(30.0),
+	}
+
+	// the negative btc equity is not discounted
+	expected := number(-0.1*20000.0 + 2.0*0.9*1000.0 + 5000.0 + 3000.0*0.5/30.0)
+	assert.Equal(t, expected.String(), calculateCollateralValueInQuote(info, prices, "USDT").String())
+}
+
 func number(n float64) fixedpoint.Value {
 	return fixedpoint.NewFromFloat(n)
 }
@@ -20,6 +20,7 @@ import (
 
 	exchange2 "github.com/c9s/bbgo/pkg/exchange"
 	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/service"
 	"github.com/c9s/bbgo/pkg/types"
 	"github.com/c9s/bbgo/pkg/util"
 )
@@ -50,6 +51,37 @@ type ExchangeSession struct {
 	TakerFeeRate            fixedpoint.Value `json:"takerFeeRate" yaml:"takerFeeRate"`
 	ModifyOrderAmountForFee bool             `json:"modifyOrderAmountForFee" yaml:"modifyOrderAmountForFee"`
 
+	// OrderValidationPolicy is the default policy for the orders that fail the market validation: adjust or reject,
+	// the orders are not validated by default. Strategies can override it on their order executors.
+	OrderValidationPolicy OrderValidationPolicy `json:"orderValidationPolicy,omitempty" yaml:"orderValidationPolicy,omitempty"`
+
+	// OrderRateLimit is the order placement budget shared by the strategies of the session,
+	// the hedge orders (OrderPriorityHigh) preempt the quote refreshes (OrderPriorityLow).
+	OrderRateLimit *OrderRateLimitConfig `json:"orderRateLimit,omitempty" yaml:"orderRateLimit,omitempty"`
+
+	// Compliance defines the pre-trade compliance checks of the orders submitted to the session
+	Compliance *ComplianceConfig `json:"compliance,omitempty" yaml:"compliance,omitempty"`
+
+	// Calendar defines the banking holidays and the maintenance windows of the venue
+	Calendar *TradingCalendar `json:"calendar,omitempty" yaml:"calendar,omitempty"`
+
+	// SystemStatusInterval is the polling interval of the exchange system status endpoint,
+	// the session turns into the degraded mode when the exchange announces the system maintenance.
+	// The system status is not polled if it's zero or the exchange does not provide the endpoint.
+	SystemStatusInterval types.Duration `json:"systemStatusInterval,omitempty" yaml:"systemStatusInterval,omitempty"`
+
+	// SymbolStatusInterval is the polling interval of the symbol status and the market list,
+	// the strategies of the halted or delisted symbols are wound down. The symbol status is not polled if it's zero.
+	SymbolStatusInterval types.Duration `json:"symbolStatusInterval,omitempty" yaml:"symbolStatusInterval,omitempty"`
+
+	// MaxStreamSubscriptions shards the market data subscriptions across multiple websocket connections,
+	// each connection carries at most MaxStreamSubscriptions subscriptions. Sharding is disabled when it's zero.
+	MaxStreamSubscriptions int `json:"maxStreamSubscriptions,omitempty" yaml:"maxStreamSubscriptions,omitempty"`
+
+	// PersistKLines writes the klines of the shared kline store into the local cache directory,
+	// so that only the missing klines are queried from the exchange on the next start.
+	PersistKLines bool `json:"persistKLines,omitempty" yaml:"persistKLines,omitempty"`
+
 	// PublicOnly is used for setting the session to public only (without authentication, no private user data)
 	PublicOnly bool `json:"publicOnly,omitempty" yaml:"publicOnly"`
 
@@ -61,6 +93,10 @@ type ExchangeSession struct {
 	// This option is exchange specific
 	PrivateChannelSymbols []string `json:"privateChannelSymbols,omitempty" yaml:"privateChannelSymbols,omitempty"`
 
+	// MarginMode enables the margin session by the mode, "cross" or "isolated",
+	// it's the typed alternative of the margin and isolatedMargin options.
+	MarginMode types.MarginMode `json:"marginMode,omitempty" yaml:"marginMode,omitempty"`
+
 	Margin               bool   `json:"margin,omitempty" yaml:"margin"`
 	IsolatedMargin       bool   `json:"isolatedMargin,omitempty" yaml:"isolatedMargin,omitempty"`
 	IsolatedMarginSymbol string `json:"isolatedMarginSymbol,omitempty" yaml:"isolatedMarginSymbol,omitempty"`
@@ -77,6 +113,10 @@ type ExchangeSession struct {
 	Account      *types.Account `json:"-" yaml:"-"`
 	accountMutex sync.Mutex
 
+	// stateMutex guards the state updates of the balances, the orders and the positions for AtomicSnapshot
+	stateMutex   sync.RWMutex
+	stateVersion uint64
+
 	IsInitialized bool `json:"-" yaml:"-"`
 
 	OrderExecutor *ExchangeOrderExecutor `json:"orderExecutor,omitempty" yaml:"orderExecutor,omitempty"`
@@ -112,6 +152,13 @@ type ExchangeSession struct {
 	// marketDataStores contains the market data store of each market
 	marketDataStores map[string]*MarketDataStore
 
+	// syntheticKLines are the kline aggregators of the synthetic kline subscriptions
+	syntheticKLines map[types.Subscription]*types.KLineAggregator
+
+	// klineStore is the shared kline store of the strategies and the indicators
+	klineStore     *KLineStore
+	klineStoreOnce sync.Once
+
 	positions map[string]*types.Position
 
 	// standard indicators of each market
@@ -122,9 +169,20 @@ type ExchangeSession struct {
 
 	orderStores map[string]*core.OrderStore
 
+	systemStatusMonitor *SystemStatusMonitor
+	symbolStatusMonitor *SymbolStatusMonitor
+
 	usedSymbols        map[string]struct{}
 	initializedSymbols map[string]struct{}
 
+	// killSwitch blocks the order submissions of the session once it's tripped
+	killSwitch      KillSwitch
+	killSwitchStore service.Store
+
+	// orderMiddlewares are applied to the orders before the orders are sent to the exchange
+	orderMiddlewaresMutex sync.Mutex
+	orderMiddlewares      []namedOrderMiddleware
+
 	logger log.FieldLogger
 }
 
@@ -182,10 +240,20 @@ func (session *ExchangeSession) UpdateAccount(ctx context.Context) (*types.Accou
 	return account, nil
 }
 
+func (session *ExchangeSession) updateBalances(balances types.BalanceMap) {
+	session.updateState(func() {
+		session.accountMutex.Lock()
+		session.Account.UpdateBalances(balances)
+		session.accountMutex.Unlock()
+	})
+}
+
 func (session *ExchangeSession) setAccount(a *types.Account) {
-	session.accountMutex.Lock()
-	session.Account = a
-	session.accountMutex.Unlock()
+	session.updateState(func() {
+		session.accountMutex.Lock()
+		session.Account = a
+		session.accountMutex.Unlock()
+	})
 }
 
 // Init initializes the basic data structure and market information by its exchange.
@@ -282,17 +350,8 @@ func (session *ExchangeSession) Init(ctx context.Context, environ *Environment)
 		session.UserDataStream.OnTradeUpdate(session.OrderExecutor.EmitTradeUpdate)
 		session.UserDataStream.OnOrderUpdate(session.OrderExecutor.EmitOrderUpdate)
 
-		session.UserDataStream.OnBalanceSnapshot(func(balances types.BalanceMap) {
-			session.accountMutex.Lock()
-			session.Account.UpdateBalances(balances)
-			session.accountMutex.Unlock()
-		})
-
-		session.UserDataStream.OnBalanceUpdate(func(balances types.BalanceMap) {
-			session.accountMutex.Lock()
-			session.Account.UpdateBalances(balances)
-			session.accountMutex.Unlock()
-		})
+		session.UserDataStream.OnBalanceSnapshot(session.updateBalances)
+		session.UserDataStream.OnBalanceUpdate(session.updateBalances)
 
 		session.bindConnectionStatusNotification(session.UserDataStream, "user data")
 
@@ -302,6 +361,33 @@ func (session *ExchangeSession) Init(ctx context.Context, environ *Environment)
 		}
 	}
 
+	if session.systemStatusMonitor != nil {
+		session.systemStatusMonitor.OnDegradedMode(func(degraded bool, status types.SystemStatus) {
+			if degraded {
+				logger.Warnf("session %s is in the degraded mode: %s", session.Name, status.Message)
+				Notify("Session %s is in the degraded mode: %s", session.Name, status.Message)
+			} else {
+				logger.Infof("session %s is back to the normal mode", session.Name)
+				Notify("Session %s is back to the normal mode", session.Name)
+			}
+		})
+
+		go session.systemStatusMonitor.Run(ctx)
+	}
+
+	if session.symbolStatusMonitor != nil {
+		session.symbolStatusMonitor.OnSymbolStatus(func(status types.SymbolStatus) {
+			msg := formatSymbolStatus(session.Name, status)
+			if status.Normal() {
+				logger.Info(msg)
+			} else {
+				logger.Warn(msg)
+			}
+
+			Notify(msg)
+		})
+	}
+
 	if environ.loggingConfig != nil {
 		if environ.loggingConfig.Balance {
 			session.UserDataStream.OnBalanceSnapshot(func(balances types.BalanceMap) {
@@ -434,14 +520,33 @@ func (session *ExchangeSession) initSymbol(ctx context.Context, environ *Environ
 		BaseCurrency:  market.BaseCurrency,
 		QuoteCurrency: market.QuoteCurrency,
 	}
-	position.BindStream(session.UserDataStream)
 	session.positions[symbol] = position
 
 	orderStore := core.NewOrderStore(symbol)
 	orderStore.AddOrderUpdate = true
-	orderStore.BindStream(session.UserDataStream)
 	session.orderStores[symbol] = orderStore
 
+	// the position and the order store are updated under the state lock for AtomicSnapshot
+	session.UserDataStream.OnTradeUpdate(func(trade types.Trade) {
+		if trade.Symbol != symbol {
+			return
+		}
+
+		session.updateState(func() {
+			position.AddTrade(trade)
+		})
+	})
+
+	session.UserDataStream.OnOrderUpdate(func(order types.Order) {
+		if order.Symbol != symbol {
+			return
+		}
+
+		session.updateState(func() {
+			orderStore.HandleOrderUpdate(order)
+		})
+	})
+
 	marketDataStore := NewMarketDataStore(symbol)
 	if !disableMarketDataStore {
 		if _, ok := session.marketDataStores[symbol]; !ok {
@@ -596,6 +701,17 @@ func (session *ExchangeSession) MarketDataStore(symbol string) (s *MarketDataSto
 	return s, true
 }
 
+// KLineStore returns the shared kline store of the session, the klines are backfilled once
+// and the closed klines of the market data stream are fanned out to the subscribers of the store.
+func (session *ExchangeSession) KLineStore() *KLineStore {
+	session.klineStoreOnce.Do(func() {
+		session.klineStore = NewKLineStore(session.Exchange, session.PersistKLines && !IsBackTesting)
+		session.klineStore.BindStream(session.MarketDataStream)
+	})
+
+	return session.klineStore
+}
+
 // KLine updates will be received in the order listend in intervals array
 func (session *ExchangeSession) SerialMarketDataStore(
 	ctx context.Context, symbol string, intervals []types.Interval, useAggTrade ...bool,
@@ -680,6 +796,11 @@ func (session *ExchangeSession) Subscribe(
 		panic("subscription interval for kline can not be empty")
 	}
 
+	if channel == types.KLineChannel && session.isSyntheticInterval(options.Interval) {
+		session.subscribeSyntheticKLine(symbol, options.Interval)
+		return session
+	}
+
 	sub := types.Subscription{
 		Channel: channel,
 		Symbol:  symbol,
@@ -693,13 +814,30 @@ func (session *ExchangeSession) Subscribe(
 }
 
 func (session *ExchangeSession) FormatOrder(order types.SubmitOrder) (types.SubmitOrder, error) {
+	return session.FormatOrderWithPolicy(order, session.OrderValidationPolicy)
+}
+
+// FormatOrderWithPolicy sets the order market, checks the compliance rules of the session
+// and validates the order by the given validation policy
+func (session *ExchangeSession) FormatOrderWithPolicy(order types.SubmitOrder, policy OrderValidationPolicy) (types.SubmitOrder, error) {
 	market, ok := session.Market(order.Symbol)
 	if !ok {
 		return order, fmt.Errorf("market is not defined: %s", order.Symbol)
 	}
 
 	order.Market = market
-	return order, nil
+
+	if err := session.checkKillSwitch(); err != nil {
+		return order, err
+	}
+
+	if session.Compliance != nil {
+		if err := session.Compliance.CheckOrder(session, order); err != nil {
+			return order, err
+		}
+	}
+
+	return policy.ValidateOrder(order)
 }
 
 func (session *ExchangeSession) UpdatePrices(ctx context.Context, currencies []string, fiat string) (err error) {
@@ -816,6 +954,40 @@ func (session *ExchangeSession) InitExchange(name string, ex types.Exchange) err
 	var err error
 	var exchangeName = session.ExchangeName
 
+	if err := session.OrderValidationPolicy.Validate(); err != nil {
+		return fmt.Errorf("session %s: %w", name, err)
+	}
+
+	if session.Compliance != nil {
+		if err := session.Compliance.Validate(); err != nil {
+			return fmt.Errorf("session %s: %w", name, err)
+		}
+	}
+
+	if session.Calendar != nil {
+		if err := session.Calendar.Validate(); err != nil {
+			return fmt.Errorf("session %s: %w", name, err)
+		}
+	}
+
+	if session.OrderRateLimit != nil {
+		if err := session.OrderRateLimit.Validate(); err != nil {
+			return fmt.Errorf("session %s: %w", name, err)
+		}
+	}
+
+	if session.SystemStatusInterval < 0 {
+		return fmt.Errorf("session %s: systemStatusInterval should not be negative", name)
+	}
+
+	if session.SymbolStatusInterval < 0 {
+		return fmt.Errorf("session %s: symbolStatusInterval should not be negative", name)
+	}
+
+	if session.MaxStreamSubscriptions < 0 {
+		return fmt.Errorf("session %s: maxStreamSubscriptions should not be negative, got %d", name, session.MaxStreamSubscriptions)
+	}
+
 	if ex == nil {
 		if session.PublicOnly {
 			ex, err = exchange2.NewPublic(exchangeName)
@@ -828,6 +1000,18 @@ func (session *ExchangeSession) InitExchange(name string, ex types.Exchange) err
 		return err
 	}
 
+	switch session.MarginMode {
+	case types.MarginModeCross:
+		session.Margin = true
+	case types.MarginModeIsolated:
+		if session.IsolatedMarginSymbol == "" {
+			return fmt.Errorf("session %s: isolatedMarginSymbol is required for the isolated margin mode", name)
+		}
+
+		session.Margin = true
+		session.IsolatedMargin = true
+	}
+
 	// configure exchange
 	if session.Margin {
 		marginExchange, ok := ex.(types.MarginExchange)
@@ -858,9 +1042,25 @@ func (session *ExchangeSession) InitExchange(name string, ex types.Exchange) err
 	session.Name = name
 	session.Exchange = ex
 	session.UserDataStream = ex.NewStream()
-	session.MarketDataStream = ex.NewStream()
+	if session.MaxStreamSubscriptions > 0 {
+		session.MarketDataStream = types.NewShardedStream(ex.NewStream, session.MaxStreamSubscriptions)
+	} else {
+		session.MarketDataStream = ex.NewStream()
+	}
 	session.MarketDataStream.SetPublicOnly()
 
+	if session.SystemStatusInterval > 0 {
+		if service, ok := ex.(types.ExchangeSystemStatusService); ok {
+			session.systemStatusMonitor = NewSystemStatusMonitor(service, session.SystemStatusInterval.Duration())
+		} else {
+			log.Warnf("session %s: exchange %s does not provide the system status endpoint", name, exchangeName)
+		}
+	}
+
+	if session.SymbolStatusInterval > 0 {
+		session.symbolStatusMonitor = NewSymbolStatusMonitor(ex, session.SymbolStatusInterval.Duration(), session.watchedSymbols)
+	}
+
 	// pointer fields
 	session.Subscriptions = make(map[types.Subscription]types.Subscription)
 	session.Account = &types.Account{}
@@ -1021,8 +1221,13 @@ func (session *ExchangeSession) SlackAttachment() slack.Attachment {
 }
 
 func (session *ExchangeSession) FormatOrders(orders []types.SubmitOrder) (formattedOrders []types.SubmitOrder, err error) {
+	return session.FormatOrdersWithPolicy(orders, session.OrderValidationPolicy)
+}
+
+// FormatOrdersWithPolicy formats the orders by the given validation policy
+func (session *ExchangeSession) FormatOrdersWithPolicy(orders []types.SubmitOrder, policy OrderValidationPolicy) (formattedOrders []types.SubmitOrder, err error) {
 	for _, order := range orders {
-		o, err := session.FormatOrder(order)
+		o, err := session.FormatOrderWithPolicy(order, policy)
 		if err != nil {
 			return formattedOrders, err
 		}
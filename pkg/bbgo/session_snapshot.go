@@ -0,0 +1,90 @@
+package bbgo
+
+import (
+	"time"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// PositionSnapshot is the copy of the session position
+type PositionSnapshot struct {
+	Symbol      string           `json:"symbol"`
+	Base        fixedpoint.Value `json:"base"`
+	Quote       fixedpoint.Value `json:"quote"`
+	AverageCost fixedpoint.Value `json:"averageCost"`
+}
+
+// SessionSnapshot is the account state of the session captured at the same moment,
+// the balances, the open orders and the positions are never mixed from different stream updates.
+type SessionSnapshot struct {
+	// Version is increased on every state update of the session,
+	// two snapshots with the same version have the same state.
+	Version uint64    `json:"version"`
+	Time    time.Time `json:"time"`
+
+	Balances types.BalanceMap `json:"balances"`
+
+	// OpenOrders is the number of the open orders of each symbol
+	OpenOrders map[string]int `json:"openOrders"`
+
+	Positions map[string]PositionSnapshot `json:"positions"`
+}
+
+// NumOfOpenOrders returns the total number of the open orders
+func (s *SessionSnapshot) NumOfOpenOrders() (num int) {
+	for _, n := range s.OpenOrders {
+		num += n
+	}
+
+	return num
+}
+
+// updateState applies the state update of the balances, the orders or the positions,
+// so that AtomicSnapshot never captures a half-applied update.
+func (session *ExchangeSession) updateState(update func()) {
+	session.stateMutex.Lock()
+	update()
+	session.stateVersion++
+	session.stateMutex.Unlock()
+}
+
+// AtomicSnapshot returns the balances, the open orders count and the positions of the session captured under one lock,
+// use it when the quota computation needs a consistent view while the user data stream is busy.
+func (session *ExchangeSession) AtomicSnapshot() SessionSnapshot {
+	session.stateMutex.RLock()
+	defer session.stateMutex.RUnlock()
+
+	snapshot := SessionSnapshot{
+		Version:    session.stateVersion,
+		Time:       time.Now(),
+		Balances:   types.BalanceMap{},
+		OpenOrders: make(map[string]int, len(session.orderStores)),
+		Positions:  make(map[string]PositionSnapshot, len(session.positions)),
+	}
+
+	if account := session.GetAccount(); account != nil {
+		snapshot.Balances = account.Balances()
+	}
+
+	for symbol, store := range session.orderStores {
+		for _, o := range store.Orders() {
+			if types.IsActiveOrder(o) {
+				snapshot.OpenOrders[symbol]++
+			}
+		}
+	}
+
+	for symbol, position := range session.positions {
+		position.Lock()
+		snapshot.Positions[symbol] = PositionSnapshot{
+			Symbol:      symbol,
+			Base:        position.Base,
+			Quote:       position.Quote,
+			AverageCost: position.AverageCost,
+		}
+		position.Unlock()
+	}
+
+	return snapshot
+}
@@ -0,0 +1,73 @@
+package bbgo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/c9s/bbgo/pkg/core"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+	"github.com/c9s/bbgo/pkg/types/mocks"
+)
+
+func TestExchangeSession_AtomicSnapshot(t *testing.T) {
+	number := fixedpoint.MustNewFromString
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockEx := mocks.NewMockExchange(mockCtrl)
+	mockEx.EXPECT().NewStream().Return(&types.StandardStream{}).Times(2)
+
+	session := NewExchangeSession("binance", mockEx)
+	session.SetMarkets(types.MarketMap{"BTCUSDT": getTestMarket()})
+
+	position, ok := session.Position("BTCUSDT")
+	assert.True(t, ok)
+
+	orderStore := core.NewOrderStore("BTCUSDT")
+	orderStore.AddOrderUpdate = true
+	session.orderStores["BTCUSDT"] = orderStore
+
+	snapshot := session.AtomicSnapshot()
+	assert.Equal(t, uint64(0), snapshot.Version)
+	assert.Equal(t, 0, snapshot.NumOfOpenOrders())
+
+	session.updateBalances(types.BalanceMap{
+		"USDT": {Currency: "USDT", Available: number("1000")},
+	})
+
+	session.updateState(func() {
+		orderStore.HandleOrderUpdate(types.Order{
+			SubmitOrder: types.SubmitOrder{Symbol: "BTCUSDT", Side: types.SideTypeBuy, Price: number("20000"), Quantity: number("0.1")},
+			OrderID:     1,
+			Status:      types.OrderStatusNew,
+		})
+		orderStore.HandleOrderUpdate(types.Order{
+			SubmitOrder: types.SubmitOrder{Symbol: "BTCUSDT", Side: types.SideTypeBuy, Price: number("20000"), Quantity: number("0.1")},
+			OrderID:     2,
+			Status:      types.OrderStatusFilled,
+		})
+	})
+
+	session.updateState(func() {
+		position.AddTrade(types.Trade{
+			Symbol:        "BTCUSDT",
+			Side:          types.SideTypeBuy,
+			IsBuyer:       true,
+			Price:         number("20000"),
+			Quantity:      number("0.1"),
+			QuoteQuantity: number("2000"),
+		})
+	})
+
+	snapshot = session.AtomicSnapshot()
+	assert.Equal(t, uint64(3), snapshot.Version)
+	assert.Equal(t, "1000", snapshot.Balances["USDT"].Available.String())
+	assert.Equal(t, 1, snapshot.OpenOrders["BTCUSDT"])
+	assert.Equal(t, 1, snapshot.NumOfOpenOrders())
+	assert.Equal(t, "0.1", snapshot.Positions["BTCUSDT"].Base.String())
+	assert.Equal(t, "-2000", snapshot.Positions["BTCUSDT"].Quote.String())
+}
@@ -0,0 +1,176 @@
+package bbgo
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// ShutdownAuditPolicy defines what to do with the remaining open orders and positions found by the shutdown audit
+type ShutdownAuditPolicy string
+
+const (
+	// ShutdownAuditPolicyReport only reports the remaining open orders and positions
+	ShutdownAuditPolicyReport ShutdownAuditPolicy = "report"
+
+	// ShutdownAuditPolicyCancel cancels the remaining open orders
+	ShutdownAuditPolicyCancel ShutdownAuditPolicy = "cancel"
+
+	// ShutdownAuditPolicyFlatten cancels the remaining open orders and closes the remaining positions with market orders
+	ShutdownAuditPolicyFlatten ShutdownAuditPolicy = "flatten"
+)
+
+// ShutdownAuditConfig configures the session audit that runs after all the strategies are shut down
+type ShutdownAuditConfig struct {
+	Enabled bool `json:"enabled" yaml:"enabled"`
+
+	Policy ShutdownAuditPolicy `json:"policy" yaml:"policy"`
+
+	// Sessions is the session names to audit, all the sessions are audited if it's empty
+	Sessions []string `json:"sessions,omitempty" yaml:"sessions,omitempty"`
+}
+
+// ShutdownAuditReport is the result of the shutdown audit, keyed by the session name
+type ShutdownAuditReport struct {
+	OpenOrders map[string][]types.Order
+	Positions  map[string][]*types.Position
+
+	// Errors are the errors occurred while querying or cleaning up the sessions
+	Errors []error
+}
+
+func (r *ShutdownAuditReport) IsEmpty() bool {
+	return len(r.OpenOrders) == 0 && len(r.Positions) == 0 && len(r.Errors) == 0
+}
+
+func (r *ShutdownAuditReport) String() string {
+	if r.IsEmpty() {
+		return "shutdown audit: no open orders or positions left"
+	}
+
+	var sb strings.Builder
+	sb.WriteString("shutdown audit report:\n")
+
+	for _, sessionName := range sortedKeys(r.OpenOrders) {
+		orders := r.OpenOrders[sessionName]
+		sb.WriteString(fmt.Sprintf("- session %s has %d open orders:\n", sessionName, len(orders)))
+		for _, o := range orders {
+			sb.WriteString(fmt.Sprintf("  %s %s %s %v @ %v (executed %v)\n",
+				o.Symbol, o.Side, o.Type, o.Quantity, o.Price, o.ExecutedQuantity))
+		}
+	}
+
+	for _, sessionName := range sortedKeys(r.Positions) {
+		positions := r.Positions[sessionName]
+		sb.WriteString(fmt.Sprintf("- session %s has %d positions:\n", sessionName, len(positions)))
+		for _, p := range positions {
+			sb.WriteString(fmt.Sprintf("  %s base %v @ %v\n", p.Symbol, p.GetBase(), p.AverageCost))
+		}
+	}
+
+	for _, err := range r.Errors {
+		sb.WriteString(fmt.Sprintf("- error: %v\n", err))
+	}
+
+	return sb.String()
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
+
+// AuditSessions lists the remaining open orders and positions of the sessions,
+// and cancels or flattens them according to the policy.
+func (environ *Environment) AuditSessions(ctx context.Context, config *ShutdownAuditConfig) *ShutdownAuditReport {
+	report := &ShutdownAuditReport{
+		OpenOrders: make(map[string][]types.Order),
+		Positions:  make(map[string][]*types.Position),
+	}
+
+	sessions := environ.Sessions()
+	sessionNames := config.Sessions
+	if len(sessionNames) == 0 {
+		sessionNames = sortedKeys(sessions)
+	}
+
+	for _, sessionName := range sessionNames {
+		session, ok := sessions[sessionName]
+		if !ok {
+			report.Errors = append(report.Errors, fmt.Errorf("session %s not found", sessionName))
+			continue
+		}
+
+		auditSession(ctx, session, config.Policy, report)
+	}
+
+	return report
+}
+
+func auditSession(ctx context.Context, session *ExchangeSession, policy ShutdownAuditPolicy, report *ShutdownAuditReport) {
+	// the symbols that have been initialized have their order stores
+	for _, symbol := range sortedKeys(session.OrderStores()) {
+		openOrders, err := session.Exchange.QueryOpenOrders(ctx, symbol)
+		if err != nil {
+			report.Errors = append(report.Errors, fmt.Errorf("session %s: unable to query %s open orders: %w", session.Name, symbol, err))
+			continue
+		}
+
+		if len(openOrders) == 0 {
+			continue
+		}
+
+		report.OpenOrders[session.Name] = append(report.OpenOrders[session.Name], openOrders...)
+
+		if policy == ShutdownAuditPolicyCancel || policy == ShutdownAuditPolicyFlatten {
+			log.Infof("shutdown audit: canceling %d %s open orders on session %s", len(openOrders), symbol, session.Name)
+			if err := session.Exchange.CancelOrders(ctx, openOrders...); err != nil {
+				report.Errors = append(report.Errors, fmt.Errorf("session %s: unable to cancel %s open orders: %w", session.Name, symbol, err))
+			}
+		}
+	}
+
+	for _, symbol := range sortedKeys(session.Positions()) {
+		position := session.Positions()[symbol]
+		market, ok := session.Market(symbol)
+		if !ok {
+			continue
+		}
+
+		base := position.GetBase()
+		if base.IsZero() || market.IsDustQuantity(base.Abs(), position.AverageCost) {
+			continue
+		}
+
+		report.Positions[session.Name] = append(report.Positions[session.Name], position)
+
+		if policy == ShutdownAuditPolicyFlatten {
+			side := types.SideTypeSell
+			if base.Sign() < 0 {
+				side = types.SideTypeBuy
+			}
+
+			log.Infof("shutdown audit: closing %s position %v on session %s", symbol, base, session.Name)
+			if _, err := session.Exchange.SubmitOrder(ctx, types.SubmitOrder{
+				Symbol:   symbol,
+				Market:   market,
+				Side:     side,
+				Type:     types.OrderTypeMarket,
+				Quantity: market.TruncateQuantity(base.Abs()),
+				Tag:      "shutdownAudit",
+			}); err != nil {
+				report.Errors = append(report.Errors, fmt.Errorf("session %s: unable to close %s position: %w", session.Name, symbol, err))
+			}
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package bbgo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/c9s/bbgo/pkg/core"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+	"github.com/c9s/bbgo/pkg/types/mocks"
+)
+
+func newTestAuditEnvironment(t *testing.T, mockEx *mocks.MockExchange) (*Environment, types.Market) {
+	market := getTestMarket()
+	market.StepSize = fixedpoint.NewFromFloat(0.00000001)
+
+	mockEx.EXPECT().NewStream().Return(&types.StandardStream{}).Times(2)
+	session := NewExchangeSession("test", mockEx)
+	session.SetMarkets(types.MarketMap{market.Symbol: market})
+	session.orderStores[market.Symbol] = core.NewOrderStore(market.Symbol)
+
+	position, ok := session.Position(market.Symbol)
+	assert.True(t, ok)
+	position.Base = fixedpoint.NewFromFloat(0.5)
+	position.AverageCost = fixedpoint.NewFromFloat(20000.0)
+
+	environ := NewEnvironment()
+	environ.AddExchangeSession("test", session)
+	return environ, market
+}
+
+func TestEnvironment_AuditSessions(t *testing.T) {
+	openOrders := []types.Order{
+		{
+			SubmitOrder: types.SubmitOrder{
+				Symbol:   "BTCUSDT",
+				Side:     types.SideTypeBuy,
+				Type:     types.OrderTypeLimit,
+				Quantity: fixedpoint.NewFromFloat(0.1),
+				Price:    fixedpoint.NewFromFloat(19000.0),
+			},
+			OrderID: 1,
+		},
+	}
+
+	t.Run("report", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		mockEx := mocks.NewMockExchange(mockCtrl)
+		environ, _ := newTestAuditEnvironment(t, mockEx)
+		mockEx.EXPECT().QueryOpenOrders(gomock.Any(), "BTCUSDT").Return(openOrders, nil)
+
+		report := environ.AuditSessions(context.Background(), &ShutdownAuditConfig{Enabled: true, Policy: ShutdownAuditPolicyReport})
+		assert.False(t, report.IsEmpty())
+		assert.Len(t, report.OpenOrders["test"], 1)
+		assert.Len(t, report.Positions["test"], 1)
+		assert.Empty(t, report.Errors)
+		assert.Contains(t, report.String(), "session test has 1 open orders")
+	})
+
+	t.Run("flatten", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		mockEx := mocks.NewMockExchange(mockCtrl)
+		environ, market := newTestAuditEnvironment(t, mockEx)
+		mockEx.EXPECT().QueryOpenOrders(gomock.Any(), "BTCUSDT").Return(openOrders, nil)
+		mockEx.EXPECT().CancelOrders(gomock.Any(), openOrders[0]).Return(nil)
+		mockEx.EXPECT().SubmitOrder(gomock.Any(), types.SubmitOrder{
+			Symbol:   "BTCUSDT",
+			Market:   market,
+			Side:     types.SideTypeSell,
+			Type:     types.OrderTypeMarket,
+			Quantity: fixedpoint.NewFromFloat(0.5),
+			Tag:      "shutdownAudit",
+		}).Return(&types.Order{}, nil)
+
+		report := environ.AuditSessions(context.Background(), &ShutdownAuditConfig{Enabled: true, Policy: ShutdownAuditPolicyFlatten})
+		assert.Empty(t, report.Errors)
+	})
+
+	t.Run("unknown session", func(t *testing.T) {
+		environ := NewEnvironment()
+		report := environ.AuditSessions(context.Background(), &ShutdownAuditConfig{Enabled: true, Sessions: []string{"max"}})
+		assert.Len(t, report.Errors, 1)
+	})
+}
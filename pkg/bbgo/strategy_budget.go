@@ -0,0 +1,313 @@
+package bbgo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+var ErrStrategyBudgetExceeded = errors.New("strategy execution budget exceeded")
+
+// strategyBudgetAlertInterval is the min interval between the budget alerts of the same strategy
+const strategyBudgetAlertInterval = 5 * time.Minute
+
+const (
+	strategyBudgetCallbackTime = "callbackTime"
+	strategyBudgetOrderRate    = "orderRate"
+)
+
+// StrategyBudget is the execution budget of a strategy instance
+type StrategyBudget struct {
+	// MaxCallbackTime is the max execution time of a strategy callback, the slow callbacks are alerted,
+	// and the orders of the strategy are throttled until the strategy stays in the budget for a minute
+	MaxCallbackTime types.Duration `json:"maxCallbackTime,omitempty" yaml:"maxCallbackTime,omitempty"`
+
+	// MaxOrdersPerMinute is the max number of the orders the strategy submits in a sliding minute,
+	// the orders over the budget are rejected
+	MaxOrdersPerMinute int `json:"maxOrdersPerMinute,omitempty" yaml:"maxOrdersPerMinute,omitempty"`
+}
+
+// StrategyBudgetConfig is the execution budgets of the strategies sharing the process
+type StrategyBudgetConfig struct {
+	// Default is the budget of the strategies that are not listed
+	Default *StrategyBudget `json:"default,omitempty" yaml:"default,omitempty"`
+
+	// Strategies is the budget of the strategies keyed by the strategy instance ID or the strategy ID
+	Strategies map[string]*StrategyBudget `json:"strategies,omitempty" yaml:"strategies,omitempty"`
+}
+
+func (c *StrategyBudgetConfig) Validate() error {
+	validate := func(name string, budget *StrategyBudget) error {
+		if budget == nil {
+			return nil
+		}
+
+		if budget.MaxCallbackTime < 0 || budget.MaxOrdersPerMinute < 0 {
+			return fmt.Errorf("strategy budget: the budget of %s should not be negative", name)
+		}
+
+		return nil
+	}
+
+	if err := validate("default", c.Default); err != nil {
+		return err
+	}
+
+	for name, budget := range c.Strategies {
+		if err := validate(name, budget); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// StrategyBudgetUsage is the budget usage of a strategy instance
+type StrategyBudgetUsage struct {
+	InstanceID         string        `json:"instanceID"`
+	OrdersInLastMinute int           `json:"ordersInLastMinute"`
+	MaxCallbackTime    time.Duration `json:"maxCallbackTime"`
+	SlowCallbacks      int           `json:"slowCallbacks"`
+	RejectedOrders     int           `json:"rejectedOrders"`
+	Throttled          bool          `json:"throttled"`
+}
+
+type strategyBudgetState struct {
+	orderTimes []time.Time
+
+	maxCallbackTime time.Duration
+	slowCallbacks   int
+	rejectedOrders  int
+
+	// throttledUntil is set when the strategy overruns its callback time budget
+	throttledUntil time.Time
+
+	lastAlert time.Time
+}
+
+// StrategyBudgetManager enforces the execution budgets of the strategies so that a misbehaving strategy
+// can not starve the other strategies in the same process: the order submissions over the budget are rejected,
+// and a strategy that overruns its callback time budget is throttled to half of its order budget.
+// The high priority orders are counted in the budget but never rejected, so that the hedges still go through.
+type StrategyBudgetManager struct {
+	config *StrategyBudgetConfig
+
+	mu     sync.Mutex
+	states map[string]*strategyBudgetState
+}
+
+func NewStrategyBudgetManager(config *StrategyBudgetConfig) *StrategyBudgetManager {
+	return &StrategyBudgetManager{
+		config: config,
+		states: make(map[string]*strategyBudgetState),
+	}
+}
+
+// Budget returns the budget of the strategy instance, the instance ID is looked up first, then the strategy ID
+func (m *StrategyBudgetManager) Budget(instanceID string) *StrategyBudget {
+	if budget, ok := m.config.Strategies[instanceID]; ok {
+		return budget
+	}
+
+	if strategyID, _, found := strings.Cut(instanceID, ":"); found {
+		if budget, ok := m.config.Strategies[strategyID]; ok {
+			return budget
+		}
+	}
+
+	return m.config.Default
+}
+
+func (m *StrategyBudgetManager) state(instanceID string) *strategyBudgetState {
+	state, ok := m.states[instanceID]
+	if !ok {
+		state = &strategyBudgetState{}
+		m.states[instanceID] = state
+	}
+
+	return state
+}
+
+// alert sends the budget notification at most once per strategyBudgetAlertInterval for each strategy
+func (m *StrategyBudgetManager) alert(state *strategyBudgetState, now time.Time, format string, args ...interface{}) {
+	log.Warnf("[StrategyBudget] "+format, args...)
+
+	if now.Sub(state.lastAlert) < strategyBudgetAlertInterval {
+		return
+	}
+
+	state.lastAlert = now
+	Notify(format, args...)
+}
+
+// AllowOrders consumes the order budget of the strategy instance,
+// ErrStrategyBudgetExceeded is returned when the orders exceed the budget in the last minute.
+// The high priority orders, e.g. the hedge orders, reduce the risk, so they are only counted and never rejected.
+func (m *StrategyBudgetManager) AllowOrders(instanceID string, priority OrderPriority, numOrders int, now time.Time) error {
+	budget := m.Budget(instanceID)
+	if budget == nil || budget.MaxOrdersPerMinute <= 0 {
+		return nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	state := m.state(instanceID)
+
+	since := now.Add(-time.Minute)
+	orderTimes := state.orderTimes[:0]
+	for _, t := range state.orderTimes {
+		if t.After(since) {
+			orderTimes = append(orderTimes, t)
+		}
+	}
+	state.orderTimes = orderTimes
+
+	limit := budget.MaxOrdersPerMinute
+	if now.Before(state.throttledUntil) {
+		limit = (limit + 1) / 2
+	}
+
+	if priority < OrderPriorityHigh && len(state.orderTimes)+numOrders > limit {
+		state.rejectedOrders += numOrders
+		metricsStrategyBudgetExceeded.WithLabelValues(instanceID, strategyBudgetOrderRate).Inc()
+		m.alert(state, now, "Strategy %s exceeded its order budget: %d orders in the last minute, limit %d",
+			instanceID, len(state.orderTimes)+numOrders, limit)
+		return fmt.Errorf("%w: %s submitted %d orders in the last minute, limit %d",
+			ErrStrategyBudgetExceeded, instanceID, len(state.orderTimes), limit)
+	}
+
+	for i := 0; i < numOrders; i++ {
+		state.orderTimes = append(state.orderTimes, now)
+	}
+
+	return nil
+}
+
+// RecordCallback records the execution time of the strategy callback
+func (m *StrategyBudgetManager) RecordCallback(instanceID, callback string, elapsed time.Duration, now time.Time) {
+	metricsStrategyCallbackTime.WithLabelValues(instanceID, callback).Observe(elapsed.Seconds())
+
+	budget := m.Budget(instanceID)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	state := m.state(instanceID)
+	if elapsed > state.maxCallbackTime {
+		state.maxCallbackTime = elapsed
+	}
+
+	if budget == nil || budget.MaxCallbackTime <= 0 || elapsed <= budget.MaxCallbackTime.Duration() {
+		return
+	}
+
+	state.slowCallbacks++
+	state.throttledUntil = now.Add(time.Minute)
+	metricsStrategyBudgetExceeded.WithLabelValues(instanceID, strategyBudgetCallbackTime).Inc()
+	m.alert(state, now, "Strategy %s callback %s took %s, exceeded its budget %s, the orders are throttled",
+		instanceID, callback, elapsed, budget.MaxCallbackTime.Duration())
+}
+
+// Usages returns the budget usages of the strategy instances
+func (m *StrategyBudgetManager) Usages(now time.Time) (usages []StrategyBudgetUsage) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	since := now.Add(-time.Minute)
+	for _, instanceID := range sortedKeys(m.states) {
+		state := m.states[instanceID]
+		usage := StrategyBudgetUsage{
+			InstanceID:      instanceID,
+			MaxCallbackTime: state.maxCallbackTime,
+			SlowCallbacks:   state.slowCallbacks,
+			RejectedOrders:  state.rejectedOrders,
+			Throttled:       now.Before(state.throttledUntil),
+		}
+
+		for _, t := range state.orderTimes {
+			if t.After(since) {
+				usage.OrdersInLastMinute++
+			}
+		}
+
+		usages = append(usages, usage)
+	}
+
+	return usages
+}
+
+type strategyBudgetContextKey struct{}
+
+type strategyBudgetScope struct {
+	manager    *StrategyBudgetManager
+	instanceID string
+}
+
+// withStrategyBudget returns the context carrying the budget scope of the strategy instance,
+// the orders submitted with the derived contexts are counted in the budget of the strategy.
+func withStrategyBudget(ctx context.Context, manager *StrategyBudgetManager, instanceID string) context.Context {
+	if manager == nil {
+		return ctx
+	}
+
+	return context.WithValue(ctx, strategyBudgetContextKey{}, &strategyBudgetScope{
+		manager:    manager,
+		instanceID: instanceID,
+	})
+}
+
+func strategyBudgetFromContext(ctx context.Context) *strategyBudgetScope {
+	scope, _ := ctx.Value(strategyBudgetContextKey{}).(*strategyBudgetScope)
+	return scope
+}
+
+// TrackStrategyCallback measures the execution time of a strategy callback against the callback time budget
+// of the strategy, the context should be derived from the context passed to the strategy Run method:
+//
+//	defer bbgo.TrackStrategyCallback(ctx, "updateQuote")()
+func TrackStrategyCallback(ctx context.Context, callback string) func() {
+	scope := strategyBudgetFromContext(ctx)
+	if scope == nil {
+		return func() {}
+	}
+
+	start := time.Now()
+	return func() {
+		now := time.Now()
+		scope.manager.RecordCallback(scope.instanceID, callback, now.Sub(start), now)
+	}
+}
+
+// ConfigureStrategyBudgets enforces the execution budgets of the strategies, the order budget
+// is checked for the orders submitted with the context of the strategy
+func (trader *Trader) ConfigureStrategyBudgets(config *StrategyBudgetConfig) error {
+	if err := config.Validate(); err != nil {
+		return err
+	}
+
+	trader.strategyBudgets = NewStrategyBudgetManager(config)
+
+	trader.environment.SetOrderMiddleware("strategyBudget", func(ctx context.Context, session *ExchangeSession, orders []types.SubmitOrder) error {
+		scope := strategyBudgetFromContext(ctx)
+		if scope == nil {
+			return nil
+		}
+
+		return scope.manager.AllowOrders(scope.instanceID, OrderPriorityFromContext(ctx), len(orders), time.Now())
+	})
+
+	return nil
+}
+
+// StrategyBudgets returns the strategy budget manager, it's nil when the budgets are not configured
+func (trader *Trader) StrategyBudgets() *StrategyBudgetManager {
+	return trader.strategyBudgets
+}
@@ -0,0 +1,125 @@
+package bbgo
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestStrategyBudgetManager_Budget(t *testing.T) {
+	defaultBudget := &StrategyBudget{MaxOrdersPerMinute: 10}
+	xmakerBudget := &StrategyBudget{MaxOrdersPerMinute: 100}
+	instanceBudget := &StrategyBudget{MaxOrdersPerMinute: 5}
+
+	manager := NewStrategyBudgetManager(&StrategyBudgetConfig{
+		Default: defaultBudget,
+		Strategies: map[string]*StrategyBudget{
+			"xmaker":         xmakerBudget,
+			"xmaker:ETHUSDT": instanceBudget,
+		},
+	})
+
+	assert.Equal(t, instanceBudget, manager.Budget("xmaker:ETHUSDT"))
+	assert.Equal(t, xmakerBudget, manager.Budget("xmaker:BTCUSDT"))
+	assert.Equal(t, defaultBudget, manager.Budget("grid2:BTCUSDT"))
+}
+
+func TestStrategyBudgetManager_AllowOrders(t *testing.T) {
+	now := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	manager := NewStrategyBudgetManager(&StrategyBudgetConfig{
+		Default: &StrategyBudget{MaxOrdersPerMinute: 4, MaxCallbackTime: types.Duration(100 * time.Millisecond)},
+	})
+
+	assert.NoError(t, manager.AllowOrders("a", OrderPriorityNormal, 2, now))
+	assert.NoError(t, manager.AllowOrders("a", OrderPriorityNormal, 2, now.Add(10*time.Second)))
+
+	err := manager.AllowOrders("a", OrderPriorityNormal, 1, now.Add(20*time.Second))
+	assert.True(t, errors.Is(err, ErrStrategyBudgetExceeded))
+
+	// the other strategy has its own budget
+	assert.NoError(t, manager.AllowOrders("b", OrderPriorityNormal, 4, now))
+
+	// the first batch slides out of the window
+	assert.NoError(t, manager.AllowOrders("a", OrderPriorityNormal, 2, now.Add(61*time.Second)))
+
+	// the slow callback throttles the strategy to half of its order budget
+	now = now.Add(2 * time.Minute)
+	manager.RecordCallback("b", "kline", 200*time.Millisecond, now)
+	assert.NoError(t, manager.AllowOrders("b", OrderPriorityNormal, 2, now))
+	assert.Error(t, manager.AllowOrders("b", OrderPriorityNormal, 1, now))
+
+	usages := manager.Usages(now)
+	if assert.Len(t, usages, 2) {
+		assert.Equal(t, "b", usages[1].InstanceID)
+		assert.True(t, usages[1].Throttled)
+		assert.Equal(t, 1, usages[1].SlowCallbacks)
+		assert.Equal(t, 1, usages[1].RejectedOrders)
+		assert.Equal(t, 2, usages[1].OrdersInLastMinute)
+	}
+
+	// the throttle is lifted after a minute
+	assert.NoError(t, manager.AllowOrders("b", OrderPriorityNormal, 4, now.Add(61*time.Second)))
+}
+
+func TestStrategyBudgetManager_AllowOrders_HighPriority(t *testing.T) {
+	now := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	manager := NewStrategyBudgetManager(&StrategyBudgetConfig{
+		Default: &StrategyBudget{MaxOrdersPerMinute: 4, MaxCallbackTime: types.Duration(100 * time.Millisecond)},
+	})
+
+	// the slow quote update throttles the strategy to half of its order budget
+	manager.RecordCallback("xmaker:BTCUSDT", "updateQuote", 200*time.Millisecond, now)
+	assert.NoError(t, manager.AllowOrders("xmaker:BTCUSDT", OrderPriorityLow, 2, now))
+	assert.ErrorIs(t, manager.AllowOrders("xmaker:BTCUSDT", OrderPriorityLow, 1, now), ErrStrategyBudgetExceeded)
+
+	// the hedge order of the throttled strategy is still accepted, and it's counted in the budget
+	assert.NoError(t, manager.AllowOrders("xmaker:BTCUSDT", OrderPriorityHigh, 1, now))
+
+	usages := manager.Usages(now)
+	if assert.Len(t, usages, 1) {
+		assert.True(t, usages[0].Throttled)
+		assert.Equal(t, 3, usages[0].OrdersInLastMinute)
+		assert.Equal(t, 1, usages[0].RejectedOrders)
+	}
+}
+
+func TestTrader_ConfigureStrategyBudgets(t *testing.T) {
+	environ := NewEnvironment()
+	session := environ.AddExchangeSession("binance", &ExchangeSession{Name: "binance"})
+
+	trader := NewTrader(environ)
+	assert.NoError(t, trader.ConfigureStrategyBudgets(&StrategyBudgetConfig{
+		Default: &StrategyBudget{MaxOrdersPerMinute: 1},
+	}))
+
+	orders := []types.SubmitOrder{{Symbol: "BTCUSDT", Side: types.SideTypeBuy}}
+
+	// the orders without the strategy context are not counted
+	assert.NoError(t, session.ApplyOrderMiddlewares(context.Background(), orders))
+	assert.NoError(t, session.ApplyOrderMiddlewares(context.Background(), orders))
+
+	ctx := withStrategyBudget(context.Background(), trader.StrategyBudgets(), "xmaker:BTCUSDT")
+	assert.NoError(t, session.ApplyOrderMiddlewares(ctx, orders))
+	assert.ErrorIs(t, session.ApplyOrderMiddlewares(ctx, orders), ErrStrategyBudgetExceeded)
+
+	// the hedge orders over the budget are still submitted
+	assert.NoError(t, session.ApplyOrderMiddlewares(WithOrderPriority(ctx, OrderPriorityHigh), orders))
+
+	done := TrackStrategyCallback(ctx, "test")
+	done()
+	assert.Len(t, trader.StrategyBudgets().Usages(time.Now()), 1)
+
+	// re-configuring replaces the order middleware of the stale budget manager
+	assert.NoError(t, trader.ConfigureStrategyBudgets(&StrategyBudgetConfig{
+		Default: &StrategyBudget{MaxOrdersPerMinute: 10},
+	}))
+
+	ctx = withStrategyBudget(context.Background(), trader.StrategyBudgets(), "xmaker:BTCUSDT")
+	assert.NoError(t, session.ApplyOrderMiddlewares(ctx, orders))
+	assert.NoError(t, session.ApplyOrderMiddlewares(ctx, orders))
+}
@@ -1,6 +1,9 @@
 package bbgo
 
 import (
+	"context"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
 	"github.com/c9s/bbgo/pkg/types"
 )
 
@@ -55,3 +58,25 @@ type StrategyToggler interface {
 type EmergencyStopper interface {
 	EmergencyStop() error
 }
+
+// PositionFlattener is implemented by the strategies that can close their position on demand
+type PositionFlattener interface {
+	FlattenPosition(ctx context.Context) error
+}
+
+// MarginAdjuster is implemented by the market making strategies that can adjust their quote margins at runtime
+type MarginAdjuster interface {
+	AdjustMargins(bidMargin, askMargin fixedpoint.Value) error
+}
+
+// WindDowner is implemented by the strategies that can stop opening new exposure
+// and stop by themselves once their position is flat
+type WindDowner interface {
+	WindDown(ctx context.Context) error
+}
+
+// QuoteDiagnoser is implemented by the market making strategies that can report
+// why their recent quote updates didn't place any order
+type QuoteDiagnoser interface {
+	QuoteDiagnostics() interface{}
+}
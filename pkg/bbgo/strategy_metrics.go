@@ -0,0 +1,256 @@
+package bbgo
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/c9s/bbgo/pkg/dynamic"
+)
+
+const (
+	strategyMetricsTypeLabel     = "strategy_type"
+	strategyMetricsInstanceLabel = "strategy_id"
+)
+
+type strategyMetricKind string
+
+const (
+	strategyMetricGauge     strategyMetricKind = "gauge"
+	strategyMetricCounter   strategyMetricKind = "counter"
+	strategyMetricHistogram strategyMetricKind = "histogram"
+)
+
+// metricVec is the common interface of the gauge, counter and histogram vectors
+type metricVec interface {
+	prometheus.Collector
+	Delete(labels prometheus.Labels) bool
+}
+
+type strategyMetric struct {
+	kind       strategyMetricKind
+	labelNames []string
+	vec        metricVec
+
+	// refs is the number of the strategy instances that use the metric
+	refs int
+}
+
+// strategyMetricsRegistry shares the metric vectors of the same name between the strategy instances,
+// so that the instances of the same strategy don't collide in the prometheus registry.
+type strategyMetricsRegistry struct {
+	registerer prometheus.Registerer
+
+	mu      sync.Mutex
+	metrics map[string]*strategyMetric
+}
+
+func newStrategyMetricsRegistry(registerer prometheus.Registerer) *strategyMetricsRegistry {
+	return &strategyMetricsRegistry{
+		registerer: registerer,
+		metrics:    make(map[string]*strategyMetric),
+	}
+}
+
+var defaultStrategyMetricsRegistry = newStrategyMetricsRegistry(prometheus.DefaultRegisterer)
+
+// acquire returns the registered metric vector of the name, or registers the vector created by newVec
+func (r *strategyMetricsRegistry) acquire(
+	name string, kind strategyMetricKind, labelNames []string, newVec func() metricVec,
+) (metricVec, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if metric, ok := r.metrics[name]; ok {
+		if metric.kind != kind || !slices.Equal(metric.labelNames, labelNames) {
+			return nil, fmt.Errorf("metric %s is already registered as a %s with the labels %v", name, metric.kind, metric.labelNames)
+		}
+
+		metric.refs++
+		return metric.vec, nil
+	}
+
+	vec := newVec()
+	if err := r.registerer.Register(vec); err != nil {
+		return nil, fmt.Errorf("unable to register metric %s: %w", name, err)
+	}
+
+	r.metrics[name] = &strategyMetric{
+		kind:       kind,
+		labelNames: labelNames,
+		vec:        vec,
+		refs:       1,
+	}
+	return vec, nil
+}
+
+// release deletes the series of the instance, and unregisters the metric vector when it's not used by any instance
+func (r *strategyMetricsRegistry) release(name, instanceID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	metric, ok := r.metrics[name]
+	if !ok {
+		return
+	}
+
+	deleteInstanceSeries(metric.vec, instanceID)
+
+	metric.refs--
+	if metric.refs <= 0 {
+		r.registerer.Unregister(metric.vec)
+		delete(r.metrics, name)
+	}
+}
+
+// deleteInstanceSeries deletes the series labeled by the instance id from the metric vector
+func deleteInstanceSeries(vec metricVec, instanceID string) {
+	ch := make(chan prometheus.Metric)
+	go func() {
+		vec.Collect(ch)
+		close(ch)
+	}()
+
+	var series []prometheus.Labels
+	for m := range ch {
+		var pb dto.Metric
+		if err := m.Write(&pb); err != nil {
+			continue
+		}
+
+		labels := prometheus.Labels{}
+		for _, pair := range pb.GetLabel() {
+			labels[pair.GetName()] = pair.GetValue()
+		}
+
+		if labels[strategyMetricsInstanceLabel] == instanceID {
+			series = append(series, labels)
+		}
+	}
+
+	for _, labels := range series {
+		vec.Delete(labels)
+	}
+}
+
+// StrategyMetrics registers the custom metrics of a strategy instance.
+// The metrics are labeled by the strategy type and the strategy instance id automatically,
+// and the series of the instance are removed when the strategy is shut down.
+type StrategyMetrics struct {
+	registry     *strategyMetricsRegistry
+	strategyType string
+	instanceID   string
+
+	mu      sync.Mutex
+	metrics map[string]metricVec
+}
+
+func newStrategyMetrics(registry *strategyMetricsRegistry, strategyType, instanceID string) *StrategyMetrics {
+	return &StrategyMetrics{
+		registry:     registry,
+		strategyType: strategyType,
+		instanceID:   instanceID,
+		metrics:      make(map[string]metricVec),
+	}
+}
+
+// StrategyMetrics returns the metrics registry of the strategy instance, the metrics are released on shutdown
+func (environ *Environment) StrategyMetrics(ctx context.Context, strategy StrategyID) *StrategyMetrics {
+	metrics := newStrategyMetrics(defaultStrategyMetricsRegistry, strategy.ID(), dynamic.CallID(strategy))
+
+	OnShutdown(ctx, func(ctx context.Context, wg *sync.WaitGroup) {
+		defer wg.Done()
+		metrics.Close()
+	})
+
+	return metrics
+}
+
+func (m *StrategyMetrics) instanceLabels() prometheus.Labels {
+	return prometheus.Labels{
+		strategyMetricsTypeLabel:     m.strategyType,
+		strategyMetricsInstanceLabel: m.instanceID,
+	}
+}
+
+func (m *StrategyMetrics) acquire(
+	name string, kind strategyMetricKind, labelNames []string, newVec func(labelNames []string) metricVec,
+) (metricVec, error) {
+	for _, labelName := range labelNames {
+		if labelName == strategyMetricsTypeLabel || labelName == strategyMetricsInstanceLabel {
+			return nil, fmt.Errorf("metric %s label %s is reserved", name, labelName)
+		}
+	}
+
+	allLabelNames := append([]string{strategyMetricsTypeLabel, strategyMetricsInstanceLabel}, labelNames...)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if vec, ok := m.metrics[name]; ok {
+		return vec, nil
+	}
+
+	vec, err := m.registry.acquire(name, kind, allLabelNames, func() metricVec {
+		return newVec(allLabelNames)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	m.metrics[name] = vec
+	return vec, nil
+}
+
+// Gauge returns the gauge vector of the name, the instance labels are curried
+func (m *StrategyMetrics) Gauge(name, help string, labelNames ...string) (*prometheus.GaugeVec, error) {
+	vec, err := m.acquire(name, strategyMetricGauge, labelNames, func(labelNames []string) metricVec {
+		return prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: name, Help: help}, labelNames)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return vec.(*prometheus.GaugeVec).CurryWith(m.instanceLabels())
+}
+
+// Counter returns the counter vector of the name, the instance labels are curried
+func (m *StrategyMetrics) Counter(name, help string, labelNames ...string) (*prometheus.CounterVec, error) {
+	vec, err := m.acquire(name, strategyMetricCounter, labelNames, func(labelNames []string) metricVec {
+		return prometheus.NewCounterVec(prometheus.CounterOpts{Name: name, Help: help}, labelNames)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return vec.(*prometheus.CounterVec).CurryWith(m.instanceLabels())
+}
+
+// Histogram returns the histogram vector of the name, the instance labels are curried.
+// The default buckets are used when the buckets are empty.
+func (m *StrategyMetrics) Histogram(name, help string, buckets []float64, labelNames ...string) (prometheus.ObserverVec, error) {
+	vec, err := m.acquire(name, strategyMetricHistogram, labelNames, func(labelNames []string) metricVec {
+		return prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: name, Help: help, Buckets: buckets}, labelNames)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return vec.(*prometheus.HistogramVec).CurryWith(m.instanceLabels())
+}
+
+// Close removes the series of the instance, the metrics are unregistered when they are not used by the other instances
+func (m *StrategyMetrics) Close() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for name := range m.metrics {
+		m.registry.release(name, m.instanceID)
+	}
+
+	m.metrics = make(map[string]metricVec)
+}
@@ -0,0 +1,69 @@
+package bbgo
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+)
+
+func countSeries(t *testing.T, gatherer prometheus.Gatherer, name string) int {
+	families, err := gatherer.Gather()
+	assert.NoError(t, err)
+
+	for _, family := range families {
+		if family.GetName() == name {
+			return len(family.GetMetric())
+		}
+	}
+
+	return 0
+}
+
+func TestStrategyMetrics(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	metricsRegistry := newStrategyMetricsRegistry(registry)
+
+	metrics1 := newStrategyMetrics(metricsRegistry, "xmaker", "xmaker:BTCUSDT")
+	metrics2 := newStrategyMetrics(metricsRegistry, "xmaker", "xmaker:ETHUSDT")
+
+	// the instances of the same strategy share the same metric
+	gauge1, err := metrics1.Gauge("xmaker_custom_spread", "custom spread", "side")
+	assert.NoError(t, err)
+	gauge2, err := metrics2.Gauge("xmaker_custom_spread", "custom spread", "side")
+	assert.NoError(t, err)
+
+	gauge1.With(prometheus.Labels{"side": "bid"}).Set(1.0)
+	gauge2.With(prometheus.Labels{"side": "bid"}).Set(2.0)
+	assert.Equal(t, 2, countSeries(t, registry, "xmaker_custom_spread"))
+
+	counter, err := metrics1.Counter("xmaker_custom_events_total", "custom events")
+	assert.NoError(t, err)
+	counter.With(prometheus.Labels{}).Inc()
+
+	histogram, err := metrics1.Histogram("xmaker_custom_latency", "custom latency", []float64{0.1, 1.0})
+	assert.NoError(t, err)
+	histogram.With(prometheus.Labels{}).Observe(0.5)
+
+	// the same name with the different labels is rejected
+	_, err = metrics2.Gauge("xmaker_custom_events_total", "custom events")
+	assert.Error(t, err)
+	_, err = metrics2.Counter("xmaker_custom_events_total", "custom events", "side")
+	assert.Error(t, err)
+
+	// the instance labels are reserved
+	_, err = metrics2.Gauge("xmaker_custom_position", "custom position", "strategy_id")
+	assert.Error(t, err)
+
+	// the series of the closed instance are removed, the shared metric is kept for the other instance
+	metrics1.Close()
+	assert.Equal(t, 1, countSeries(t, registry, "xmaker_custom_spread"))
+	assert.Equal(t, 0, countSeries(t, registry, "xmaker_custom_events_total"))
+
+	// the metric is registered again after it's unregistered
+	_, err = metrics2.Counter("xmaker_custom_events_total", "custom events")
+	assert.NoError(t, err)
+
+	metrics2.Close()
+	assert.Equal(t, 0, countSeries(t, registry, "xmaker_custom_spread"))
+}
@@ -0,0 +1,233 @@
+package bbgo
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/c9s/bbgo/pkg/dynamic"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// SymbolStatusMonitor polls the symbol status endpoint and the market list of the exchange,
+// and emits the symbol status event when a watched symbol is halted, delisted or announced to be delisted,
+// and when the symbol is back to normal.
+//
+// A watched symbol that disappears from the market list is considered delisted.
+//
+//go:generate callbackgen -type SymbolStatusMonitor
+type SymbolStatusMonitor struct {
+	exchange types.Exchange
+	interval time.Duration
+
+	// symbols returns the symbols to watch
+	symbols func() []string
+
+	mu       sync.Mutex
+	statuses map[string]types.SymbolStatus
+
+	symbolStatusCallbacks []func(status types.SymbolStatus)
+}
+
+func NewSymbolStatusMonitor(exchange types.Exchange, interval time.Duration, symbols func() []string) *SymbolStatusMonitor {
+	return &SymbolStatusMonitor{
+		exchange: exchange,
+		interval: interval,
+		symbols:  symbols,
+		statuses: make(map[string]types.SymbolStatus),
+	}
+}
+
+// Status returns the last status of the symbol
+func (m *SymbolStatusMonitor) Status(symbol string) (types.SymbolStatus, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	status, ok := m.statuses[symbol]
+	return status, ok
+}
+
+// Update queries the symbol status and the markets, and emits the symbol status event for the changed symbols
+func (m *SymbolStatusMonitor) Update(ctx context.Context) error {
+	symbols := m.symbols()
+	if len(symbols) == 0 {
+		return nil
+	}
+
+	current := make(map[string]types.SymbolStatus, len(symbols))
+	for _, symbol := range symbols {
+		current[symbol] = types.SymbolStatus{Symbol: symbol, Status: types.SymbolTradingStatusTrading}
+	}
+
+	if service, ok := m.exchange.(types.ExchangeSymbolStatusService); ok {
+		statuses, err := service.QuerySymbolStatus(ctx)
+		if err != nil {
+			return fmt.Errorf("unable to query the symbol status: %w", err)
+		}
+
+		for _, symbol := range symbols {
+			if status, ok := statuses[symbol]; ok {
+				current[symbol] = status
+			}
+		}
+	}
+
+	markets, err := m.exchange.QueryMarkets(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to query the markets: %w", err)
+	}
+
+	// an empty market list is more likely a broken response than delisting everything
+	if len(markets) > 0 {
+		for _, symbol := range symbols {
+			if _, ok := markets[symbol]; !ok && current[symbol].Normal() {
+				current[symbol] = types.SymbolStatus{
+					Symbol:  symbol,
+					Status:  types.SymbolTradingStatusDelisted,
+					Message: "the market is removed from the exchange",
+				}
+			}
+		}
+	}
+
+	var changed []types.SymbolStatus
+	m.mu.Lock()
+	for symbol, status := range current {
+		last, ok := m.statuses[symbol]
+		if !ok {
+			last = types.SymbolStatus{Symbol: symbol, Status: types.SymbolTradingStatusTrading}
+		}
+
+		if last.Status != status.Status || !last.DelistTime.Equal(status.DelistTime) {
+			changed = append(changed, status)
+		}
+
+		m.statuses[symbol] = status
+	}
+	m.mu.Unlock()
+
+	sort.Slice(changed, func(i, j int) bool {
+		return changed[i].Symbol < changed[j].Symbol
+	})
+
+	for _, status := range changed {
+		m.EmitSymbolStatus(status)
+	}
+
+	return nil
+}
+
+// Run polls the symbol status on every interval until the context is canceled
+func (m *SymbolStatusMonitor) Run(ctx context.Context) {
+	if err := m.Update(ctx); err != nil {
+		log.WithError(err).Errorf("unable to update the symbol status")
+	}
+
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-ticker.C:
+			if err := m.Update(ctx); err != nil {
+				log.WithError(err).Errorf("unable to update the symbol status")
+			}
+		}
+	}
+}
+
+// OnSymbolStatus registers the callback of the symbol status event of the session,
+// the callback is never called if the symbol status monitor is not enabled on the session.
+func (session *ExchangeSession) OnSymbolStatus(cb func(status types.SymbolStatus)) {
+	if session.symbolStatusMonitor == nil {
+		return
+	}
+
+	session.symbolStatusMonitor.OnSymbolStatus(cb)
+}
+
+// watchedSymbols returns the symbols used by the strategies of the session
+func (session *ExchangeSession) watchedSymbols() []string {
+	symbols := make([]string, 0, len(session.usedSymbols))
+	for symbol := range session.usedSymbols {
+		symbols = append(symbols, symbol)
+	}
+
+	sort.Strings(symbols)
+	return symbols
+}
+
+func formatSymbolStatus(sessionName string, status types.SymbolStatus) string {
+	if status.Normal() {
+		return fmt.Sprintf("%s %s is back to normal trading", sessionName, status.Symbol)
+	}
+
+	msg := fmt.Sprintf("🚨 %s %s is %s", sessionName, status.Symbol, status.Status)
+	if !status.DelistTime.IsZero() {
+		msg += fmt.Sprintf(", delisting at %s", status.DelistTime.Format(time.RFC3339))
+	}
+
+	if status.Message != "" {
+		msg += ": " + status.Message
+	}
+
+	return msg
+}
+
+// bindSymbolWindDown winds down the strategies of the symbol when the symbol is halted or delisted
+func (trader *Trader) bindSymbolWindDown(ctx context.Context) {
+	for sessionName, session := range trader.environment.sessions {
+		sessionName := sessionName
+		session.OnSymbolStatus(func(status types.SymbolStatus) {
+			if status.Normal() {
+				return
+			}
+
+			for _, strategy := range trader.symbolStrategies(sessionName, status.Symbol) {
+				windDowner, ok := strategy.(WindDowner)
+				if !ok {
+					Notify("🚨 strategy %s does not support winding down, please stop it manually", dynamic.CallID(strategy))
+					continue
+				}
+
+				if err := windDowner.WindDown(ctx); err != nil {
+					log.WithError(err).Errorf("unable to wind down the strategy %s", dynamic.CallID(strategy))
+					Notify("🚨 unable to wind down the strategy %s: %v", dynamic.CallID(strategy), err)
+					continue
+				}
+
+				Notify("🚨 strategy %s is winding down, %s %s is %s", dynamic.CallID(strategy), sessionName, status.Symbol, status.Status)
+			}
+		})
+	}
+}
+
+// symbolStrategies returns the strategies trading the symbol on the session,
+// the cross exchange strategies are matched by their symbol only
+func (trader *Trader) symbolStrategies(sessionName, symbol string) (strategies []StrategyID) {
+	match := func(strategy StrategyID) bool {
+		s, ok := dynamic.LookupSymbolField(reflect.ValueOf(strategy))
+		return ok && s == symbol
+	}
+
+	for _, strategy := range trader.exchangeStrategies[sessionName] {
+		if match(strategy) {
+			strategies = append(strategies, strategy)
+		}
+	}
+
+	for _, strategy := range trader.crossExchangeStrategies {
+		if match(strategy) {
+			strategies = append(strategies, strategy)
+		}
+	}
+
+	return strategies
+}
@@ -0,0 +1,118 @@
+package bbgo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+type testSymbolStatusExchange struct {
+	types.Exchange
+
+	markets  types.MarketMap
+	statuses map[string]types.SymbolStatus
+}
+
+func (e *testSymbolStatusExchange) QueryMarkets(ctx context.Context) (types.MarketMap, error) {
+	return e.markets, nil
+}
+
+func (e *testSymbolStatusExchange) QuerySymbolStatus(ctx context.Context) (map[string]types.SymbolStatus, error) {
+	return e.statuses, nil
+}
+
+type windDownStrategy struct {
+	myStrategy
+
+	windDowns int
+}
+
+func (s *windDownStrategy) WindDown(ctx context.Context) error {
+	s.windDowns++
+	return nil
+}
+
+func TestSymbolStatusMonitor_Update(t *testing.T) {
+	ctx := context.Background()
+	ex := &testSymbolStatusExchange{
+		markets: types.MarketMap{
+			"BTCUSDT": types.Market{Symbol: "BTCUSDT"},
+			"ETHUSDT": types.Market{Symbol: "ETHUSDT"},
+			"LTCUSDT": types.Market{Symbol: "LTCUSDT"},
+		},
+		statuses: map[string]types.SymbolStatus{},
+	}
+
+	monitor := NewSymbolStatusMonitor(ex, time.Minute, func() []string {
+		return []string{"BTCUSDT", "ETHUSDT", "LTCUSDT"}
+	})
+
+	var events []types.SymbolStatus
+	monitor.OnSymbolStatus(func(status types.SymbolStatus) {
+		events = append(events, status)
+	})
+
+	// all the symbols are trading
+	assert.NoError(t, monitor.Update(ctx))
+	assert.Empty(t, events)
+
+	delistTime := time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC)
+	ex.statuses["BTCUSDT"] = types.SymbolStatus{Symbol: "BTCUSDT", Status: types.SymbolTradingStatusHalted}
+	ex.statuses["LTCUSDT"] = types.SymbolStatus{Symbol: "LTCUSDT", Status: types.SymbolTradingStatusTrading, DelistTime: delistTime}
+	delete(ex.markets, "ETHUSDT")
+
+	assert.NoError(t, monitor.Update(ctx))
+	if assert.Len(t, events, 3) {
+		assert.Equal(t, types.SymbolTradingStatusHalted, events[0].Status)
+		assert.Equal(t, "ETHUSDT", events[1].Symbol)
+		assert.Equal(t, types.SymbolTradingStatusDelisted, events[1].Status)
+		assert.Equal(t, delistTime, events[2].DelistTime)
+		assert.False(t, events[2].Normal())
+	}
+
+	// no changes, no events
+	assert.NoError(t, monitor.Update(ctx))
+	assert.Len(t, events, 3)
+
+	// back to normal
+	ex.statuses["BTCUSDT"] = types.SymbolStatus{Symbol: "BTCUSDT", Status: types.SymbolTradingStatusTrading}
+	assert.NoError(t, monitor.Update(ctx))
+	if assert.Len(t, events, 4) {
+		assert.True(t, events[3].Normal())
+	}
+
+	status, ok := monitor.Status("ETHUSDT")
+	assert.True(t, ok)
+	assert.Equal(t, types.SymbolTradingStatusDelisted, status.Status)
+}
+
+func TestTrader_bindSymbolWindDown(t *testing.T) {
+	ctx := context.Background()
+	ex := &testSymbolStatusExchange{
+		markets:  types.MarketMap{"BTCUSDT": types.Market{Symbol: "BTCUSDT"}},
+		statuses: map[string]types.SymbolStatus{"BTCUSDT": {Symbol: "BTCUSDT", Status: types.SymbolTradingStatusHalted}},
+	}
+
+	session := &ExchangeSession{Name: "binance"}
+	session.symbolStatusMonitor = NewSymbolStatusMonitor(ex, time.Minute, func() []string {
+		return []string{"BTCUSDT"}
+	})
+
+	environ := NewEnvironment()
+	environ.AddExchangeSession("binance", session)
+
+	btc := &windDownStrategy{myStrategy: myStrategy{Symbol: "BTCUSDT"}}
+	eth := &windDownStrategy{myStrategy: myStrategy{Symbol: "ETHUSDT"}}
+
+	trader := NewTrader(environ)
+	trader.exchangeStrategies["binance"] = []SingleExchangeStrategy{btc, eth}
+	trader.bindSymbolWindDown(ctx)
+
+	assert.NoError(t, session.symbolStatusMonitor.Update(ctx))
+	assert.Equal(t, 1, btc.windDowns)
+	assert.Equal(t, 0, eth.windDowns)
+}
@@ -0,0 +1,62 @@
+package bbgo
+
+import (
+	"context"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// isSyntheticInterval returns true if the klines of the interval are synthesized from the market trades,
+// the sub-second intervals are always synthesized, and the other synthetic intervals (1s) are only synthesized
+// when the exchange does not support them.
+func (session *ExchangeSession) isSyntheticInterval(interval types.Interval) bool {
+	if _, ok := types.SyntheticIntervals[interval]; !ok {
+		return false
+	}
+
+	if interval.IsSubSecond() {
+		return true
+	}
+
+	provider, ok := session.Exchange.(types.CustomIntervalProvider)
+	return ok && !provider.IsSupportedInterval(interval)
+}
+
+// subscribeSyntheticKLine subscribes the market trades of the symbol instead of the kline channel,
+// the klines built by the aggregator are emitted as the closed klines of the market data stream,
+// so that the synthetic klines are consumed in the same way as the klines from the exchange.
+func (session *ExchangeSession) subscribeSyntheticKLine(symbol string, interval types.Interval) {
+	key := types.Subscription{
+		Channel: types.KLineChannel,
+		Symbol:  symbol,
+		Options: types.SubscribeOptions{Interval: interval},
+	}
+
+	if session.syntheticKLines == nil {
+		session.syntheticKLines = make(map[types.Subscription]*types.KLineAggregator)
+	}
+
+	if _, ok := session.syntheticKLines[key]; ok {
+		return
+	}
+
+	aggregator := types.NewKLineAggregator(symbol, interval)
+	aggregator.BindStream(session.MarketDataStream)
+	if emitter, ok := session.MarketDataStream.(types.StandardStreamEmitter); ok {
+		aggregator.OnKLineClosed(emitter.EmitKLineClosed)
+	} else {
+		log.Warnf("session %s market data stream can not emit the synthetic %s %s klines", session.Name, symbol, interval)
+	}
+
+	session.syntheticKLines[key] = aggregator
+	session.Subscribe(types.MarketTradeChannel, symbol, types.SubscribeOptions{})
+}
+
+// runSyntheticKLines closes the synthetic klines on time when there is no market trade
+func (session *ExchangeSession) runSyntheticKLines(ctx context.Context) {
+	for _, aggregator := range session.syntheticKLines {
+		go aggregator.Run(ctx)
+	}
+}
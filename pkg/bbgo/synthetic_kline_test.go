@@ -0,0 +1,43 @@
+package bbgo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+	"github.com/c9s/bbgo/pkg/types/mocks"
+)
+
+func TestExchangeSession_SubscribeSyntheticKLine(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockEx := mocks.NewMockExchange(mockCtrl)
+	mockEx.EXPECT().NewStream().Return(&types.StandardStream{}).Times(2)
+	session := NewExchangeSession("binance", mockEx)
+
+	session.Subscribe(types.KLineChannel, "BTCUSDT", types.SubscribeOptions{Interval: types.Interval250ms})
+	session.Subscribe(types.KLineChannel, "BTCUSDT", types.SubscribeOptions{Interval: types.Interval1m})
+
+	// the market trades are subscribed instead of the synthetic kline channel
+	_, ok := session.Subscriptions[types.Subscription{Channel: types.MarketTradeChannel, Symbol: "BTCUSDT"}]
+	assert.True(t, ok)
+	assert.Len(t, session.Subscriptions, 2)
+
+	var klines []types.KLine
+	session.MarketDataStream.OnKLineClosed(func(k types.KLine) { klines = append(klines, k) })
+
+	stream := session.MarketDataStream.(*types.StandardStream)
+	startTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	stream.EmitMarketTrade(types.Trade{Symbol: "BTCUSDT", Price: fixedpoint.NewFromInt(100), Time: types.Time(startTime)})
+	stream.EmitMarketTrade(types.Trade{Symbol: "BTCUSDT", Price: fixedpoint.NewFromInt(101), Time: types.Time(startTime.Add(300 * time.Millisecond))})
+
+	if assert.Len(t, klines, 1) {
+		assert.Equal(t, types.Interval250ms, klines[0].Interval)
+		assert.Equal(t, "100", klines[0].Close.String())
+	}
+}
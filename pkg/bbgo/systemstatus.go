@@ -0,0 +1,124 @@
+package bbgo
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// DefaultMaxSystemStatusErrors is the number of the consecutive system status query errors that turn on the degraded mode
+const DefaultMaxSystemStatusErrors = 3
+
+// SystemStatusMonitor polls the exchange system status endpoint and emits the degraded mode event
+// when the exchange announces the system maintenance or the status endpoint keeps failing,
+// so that the strategies can pull the quotes and stop hedging before the outage hits the order placement.
+//
+//go:generate callbackgen -type SystemStatusMonitor
+type SystemStatusMonitor struct {
+	service  types.ExchangeSystemStatusService
+	interval time.Duration
+
+	// MaxErrors is the number of the consecutive query errors that turn on the degraded mode
+	MaxErrors int
+
+	mu       sync.Mutex
+	status   types.SystemStatus
+	errors   int
+	degraded bool
+
+	degradedModeCallbacks []func(degraded bool, status types.SystemStatus)
+}
+
+func NewSystemStatusMonitor(service types.ExchangeSystemStatusService, interval time.Duration) *SystemStatusMonitor {
+	return &SystemStatusMonitor{
+		service:   service,
+		interval:  interval,
+		MaxErrors: DefaultMaxSystemStatusErrors,
+	}
+}
+
+// Degraded returns true when the exchange is in the degraded mode
+func (m *SystemStatusMonitor) Degraded() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.degraded
+}
+
+// Status returns the last system status
+func (m *SystemStatusMonitor) Status() types.SystemStatus {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.status
+}
+
+// Update queries the system status and emits the degraded mode event when the degraded mode is changed
+func (m *SystemStatusMonitor) Update(ctx context.Context) error {
+	status, err := m.service.QuerySystemStatus(ctx)
+
+	m.mu.Lock()
+	if err != nil {
+		m.errors++
+		if m.MaxErrors > 0 && m.errors >= m.MaxErrors {
+			m.status = types.SystemStatus{Maintenance: true, Message: "system status endpoint unavailable: " + err.Error()}
+		}
+	} else {
+		m.errors = 0
+		m.status = *status
+	}
+
+	changed := m.status.Maintenance != m.degraded
+	m.degraded = m.status.Maintenance
+	degraded, current := m.degraded, m.status
+	m.mu.Unlock()
+
+	if changed {
+		m.EmitDegradedMode(degraded, current)
+	}
+
+	return err
+}
+
+// Run polls the system status on every interval until the context is canceled
+func (m *SystemStatusMonitor) Run(ctx context.Context) {
+	if err := m.Update(ctx); err != nil {
+		log.WithError(err).Errorf("unable to query the exchange system status")
+	}
+
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-ticker.C:
+			if err := m.Update(ctx); err != nil {
+				log.WithError(err).Errorf("unable to query the exchange system status")
+			}
+		}
+	}
+}
+
+// Degraded returns true when the session exchange is in the degraded mode reported by the system status monitor
+func (session *ExchangeSession) Degraded() bool {
+	if session.systemStatusMonitor == nil {
+		return false
+	}
+
+	return session.systemStatusMonitor.Degraded()
+}
+
+// OnDegradedMode registers the callback of the degraded mode event of the session,
+// the callback is never called if the system status monitor is not enabled on the session.
+func (session *ExchangeSession) OnDegradedMode(cb func(degraded bool, status types.SystemStatus)) {
+	if session.systemStatusMonitor == nil {
+		return
+	}
+
+	session.systemStatusMonitor.OnDegradedMode(cb)
+}
@@ -0,0 +1,68 @@
+package bbgo
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+type testSystemStatusService struct {
+	status types.SystemStatus
+	err    error
+}
+
+func (s *testSystemStatusService) QuerySystemStatus(ctx context.Context) (*types.SystemStatus, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	return &s.status, nil
+}
+
+func TestSystemStatusMonitor_Update(t *testing.T) {
+	ctx := context.Background()
+	service := &testSystemStatusService{status: types.SystemStatus{Message: "normal"}}
+	monitor := NewSystemStatusMonitor(service, 0)
+
+	var events []bool
+	monitor.OnDegradedMode(func(degraded bool, status types.SystemStatus) {
+		events = append(events, degraded)
+	})
+
+	assert.NoError(t, monitor.Update(ctx))
+	assert.False(t, monitor.Degraded())
+	assert.Empty(t, events)
+
+	service.status = types.SystemStatus{Maintenance: true, Message: "system maintenance"}
+	assert.NoError(t, monitor.Update(ctx))
+	assert.True(t, monitor.Degraded())
+	assert.Equal(t, "system maintenance", monitor.Status().Message)
+
+	// no event is emitted when the mode is not changed
+	assert.NoError(t, monitor.Update(ctx))
+	assert.Equal(t, []bool{true}, events)
+
+	service.status = types.SystemStatus{Message: "normal"}
+	assert.NoError(t, monitor.Update(ctx))
+	assert.False(t, monitor.Degraded())
+	assert.Equal(t, []bool{true, false}, events)
+
+	// the consecutive query errors turn on the degraded mode
+	service.err = errors.New("connection refused")
+	for i := 0; i < DefaultMaxSystemStatusErrors-1; i++ {
+		assert.Error(t, monitor.Update(ctx))
+		assert.False(t, monitor.Degraded())
+	}
+
+	assert.Error(t, monitor.Update(ctx))
+	assert.True(t, monitor.Degraded())
+	assert.Equal(t, []bool{true, false, true}, events)
+
+	service.err = nil
+	assert.NoError(t, monitor.Update(ctx))
+	assert.False(t, monitor.Degraded())
+}
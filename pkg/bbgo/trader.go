@@ -13,6 +13,7 @@ import (
 
 	"github.com/c9s/bbgo/pkg/dynamic"
 	"github.com/c9s/bbgo/pkg/interact"
+	"github.com/c9s/bbgo/pkg/risk"
 )
 
 // Strategy method calls:
@@ -85,6 +86,10 @@ type Trader struct {
 
 	riskControls *RiskControls
 
+	portfolioRisk *risk.PortfolioRiskManager
+
+	strategyBudgets *StrategyBudgetManager
+
 	crossExchangeStrategies []CrossExchangeStrategy
 	exchangeStrategies      map[string][]SingleExchangeStrategy
 
@@ -116,6 +121,30 @@ func (trader *Trader) Configure(userConfig *Config) error {
 		trader.SetRiskControls(userConfig.RiskControls)
 	}
 
+	if userConfig.PortfolioRisk != nil {
+		if err := trader.ConfigurePortfolioRisk(userConfig.PortfolioRisk); err != nil {
+			return err
+		}
+	}
+
+	if userConfig.StrategyBudgets != nil {
+		if err := trader.ConfigureStrategyBudgets(userConfig.StrategyBudgets); err != nil {
+			return err
+		}
+	}
+
+	if userConfig.ValueAtRisk != nil {
+		if err := trader.ConfigureValueAtRisk(userConfig.ValueAtRisk); err != nil {
+			return err
+		}
+	}
+
+	if userConfig.AccountStatements != nil && userConfig.AccountStatements.Enabled {
+		if err := trader.ConfigureAccountStatements(userConfig.AccountStatements); err != nil {
+			return err
+		}
+	}
+
 	for _, entry := range userConfig.ExchangeStrategies {
 		for _, mount := range entry.Mounts {
 			log.Infof("attaching strategy %T on %s...", entry.Strategy, mount)
@@ -181,6 +210,10 @@ func (trader *Trader) RunSingleExchangeStrategy(
 		trader.gracefulShutdown.OnShutdown(shutdown.Shutdown)
 	}
 
+	instanceID := dynamic.CallID(strategy)
+	ctx = withStrategyBudget(ctx, trader.strategyBudgets, instanceID)
+	defer TrackStrategyCallback(ctx, "run")()
+
 	return strategy.Run(ctx, orderExecutor, session)
 }
 
@@ -330,6 +363,8 @@ func (trader *Trader) Run(ctx context.Context) error {
 		return err
 	}
 
+	trader.bindSymbolWindDown(ctx)
+
 	if err := trader.environment.Start(ctx); err != nil {
 		return err
 	}
@@ -348,14 +383,31 @@ func (trader *Trader) Run(ctx context.Context) error {
 	}
 
 	for _, strategy := range trader.crossExchangeStrategies {
-		if err := strategy.CrossRun(ctx, router, trader.environment.sessions); err != nil {
+		if err := trader.runCrossExchangeStrategy(ctx, strategy, router); err != nil {
 			return err
 		}
 	}
 
+	if monitor := trader.environment.valueAtRisk; monitor != nil && !IsBackTesting {
+		go monitor.Run(ctx)
+	}
+
+	if statements := trader.environment.accountStatements; statements != nil && !IsBackTesting {
+		statements.Start(ctx, trader.environment.sessions)
+	}
+
 	return trader.environment.Connect(ctx)
 }
 
+func (trader *Trader) runCrossExchangeStrategy(
+	ctx context.Context, strategy CrossExchangeStrategy, router OrderExecutionRouter,
+) error {
+	ctx = withStrategyBudget(ctx, trader.strategyBudgets, dynamic.CallID(strategy))
+	defer TrackStrategyCallback(ctx, "run")()
+
+	return strategy.CrossRun(ctx, router, trader.environment.sessions)
+}
+
 func (trader *Trader) Initialize(ctx context.Context) error {
 	return trader.IterateStrategies(func(strategy StrategyID) error {
 		if initializer, ok := strategy.(StrategyInitializer); ok {
@@ -399,6 +451,20 @@ func (trader *Trader) IterateStrategies(f func(st StrategyID) error) error {
 	return nil
 }
 
+// FindStrategy finds the running strategy by its instance ID
+func (trader *Trader) FindStrategy(instanceID string) (StrategyID, bool) {
+	var found StrategyID
+	_ = trader.IterateStrategies(func(strategy StrategyID) error {
+		if found == nil && dynamic.CallID(strategy) == instanceID {
+			found = strategy
+		}
+
+		return nil
+	})
+
+	return found, found != nil
+}
+
 // NOTICE: the ctx here is the trading context, which could already be canceled.
 func (trader *Trader) SaveState(ctx context.Context) error {
 	if trader.environment.BacktestService != nil {
@@ -1 +1,23 @@
 package bbgo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrader_FindStrategy(t *testing.T) {
+	trader := NewTrader(NewEnvironment())
+	trader.exchangeStrategies["binance"] = []SingleExchangeStrategy{
+		&myStrategy{Symbol: "BTCUSDT"},
+		&myStrategy{Symbol: "ETHUSDT"},
+	}
+
+	strategy, ok := trader.FindStrategy("mystrategy:ETHUSDT")
+	if assert.True(t, ok) {
+		assert.Equal(t, "ETHUSDT", strategy.(*myStrategy).Symbol)
+	}
+
+	_, ok = trader.FindStrategy("mystrategy:BNBUSDT")
+	assert.False(t, ok)
+}
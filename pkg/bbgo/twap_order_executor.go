@@ -26,6 +26,10 @@ type TwapExecution struct {
 	UpdateInterval time.Duration
 	DeadlineTime   time.Time
 
+	// OrderStore is an optional order store, the created orders will also be added to this store,
+	// so that the caller can collect the trades of the execution with its own trade collector.
+	OrderStore *core.OrderStore
+
 	market           types.Market
 	marketDataStream types.Stream
 
@@ -280,6 +284,9 @@ func (e *TwapExecution) updateOrder(ctx context.Context) error {
 
 	e.activeMakerOrders.Add(createdOrders...)
 	e.orderStore.Add(createdOrders...)
+	if e.OrderStore != nil {
+		e.OrderStore.Add(createdOrders...)
+	}
 	return nil
 }
 
@@ -339,6 +346,19 @@ func (e *TwapExecution) orderUpdater(ctx context.Context) {
 	}
 }
 
+// FilledQuantity returns the executed quantity of the execution
+func (e *TwapExecution) FilledQuantity() fixedpoint.Value {
+	e.mu.Lock()
+	position := e.position
+	e.mu.Unlock()
+
+	if position == nil {
+		return fixedpoint.Zero
+	}
+
+	return position.GetBase().Abs()
+}
+
 func (e *TwapExecution) cancelContextIfTargetQuantityFilled() bool {
 	base := e.position.GetBase()
 
@@ -401,11 +421,13 @@ func (e *TwapExecution) Run(parentCtx context.Context) error {
 
 	e.userDataStream = e.Session.Exchange.NewStream()
 	e.userDataStream.OnTradeUpdate(e.handleTradeUpdate)
+	e.mu.Lock()
 	e.position = &types.Position{
 		Symbol:        e.Symbol,
 		BaseCurrency:  e.market.BaseCurrency,
 		QuoteCurrency: e.market.QuoteCurrency,
 	}
+	e.mu.Unlock()
 
 	e.orderStore = core.NewOrderStore(e.Symbol)
 	e.orderStore.BindStream(e.userDataStream)
@@ -0,0 +1,64 @@
+package bbgo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/c9s/bbgo/pkg/risk"
+	"github.com/c9s/bbgo/pkg/risk/valueatrisk"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// ValueAtRisk returns the value-at-risk monitor of the portfolio, it's nil when the value-at-risk is not configured
+func (environ *Environment) ValueAtRisk() *valueatrisk.Monitor {
+	return environ.valueAtRisk
+}
+
+// QueryUSDKLines queries the klines of the asset from the first session that has the USD market of the asset
+func (environ *Environment) QueryUSDKLines(
+	ctx context.Context, asset string, interval types.Interval, limit int,
+) ([]types.KLine, error) {
+	sessions := environ.Sessions()
+	for _, sessionName := range sortedKeys(sessions) {
+		session := sessions[sessionName]
+		for _, fiat := range types.USDFiatCurrencies {
+			symbol := asset + fiat
+			if _, ok := session.Market(symbol); !ok {
+				continue
+			}
+
+			return session.KLineStore().KLines(ctx, symbol, interval, limit)
+		}
+	}
+
+	return nil, fmt.Errorf("no USD market of %s is found in the sessions", asset)
+}
+
+// ConfigureValueAtRisk computes the value-at-risk of the aggregate position of the strategies
+func (trader *Trader) ConfigureValueAtRisk(config *valueatrisk.Config) error {
+	config.Defaults()
+	if err := config.Validate(); err != nil {
+		return err
+	}
+
+	environ := trader.environment
+
+	// the exposure limits are not used, the portfolio risk manager only aggregates the positions here
+	aggregator := risk.NewPortfolioRiskManager(&risk.ExposureLimitConfig{}, trader.StrategyPositions, environ.PriceInUSD)
+	exposures := func() map[string]float64 {
+		exposures := make(map[string]float64)
+		for currency, asset := range aggregator.Exposure().Assets {
+			exposures[currency] = asset.InUSD.Float64()
+		}
+		return exposures
+	}
+
+	monitor := valueatrisk.NewMonitor(config, exposures, environ.QueryUSDKLines)
+	monitor.OnBreach(func(report *valueatrisk.Report) {
+		Notify("🚨 portfolio value at risk %.2f USD (%.1f%%) exceeds the threshold %v USD",
+			report.VaR(), report.Confidence*100, config.MaxVaR)
+	})
+
+	environ.valueAtRisk = monitor
+	return nil
+}
@@ -0,0 +1,203 @@
+package bbgo
+
+import (
+	"context"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	DefaultWatchdogCheckInterval = 30 * time.Second
+	DefaultWatchdogMaxGoroutines = 10000
+)
+
+// WorkerStatus is the heartbeat status of a registered worker
+type WorkerStatus struct {
+	Name          string        `json:"name"`
+	Timeout       time.Duration `json:"timeout"`
+	LastHeartbeat time.Time     `json:"lastHeartbeat"`
+	Stalled       bool          `json:"stalled"`
+}
+
+// WatchdogStatus is the health status reported by the watchdog
+type WatchdogStatus struct {
+	Healthy       bool           `json:"healthy"`
+	Time          time.Time      `json:"time"`
+	Goroutines    int            `json:"goroutines"`
+	MaxGoroutines int            `json:"maxGoroutines"`
+	Workers       []WorkerStatus `json:"workers"`
+}
+
+// WorkerHeartbeat is the handle of a registered worker, the worker calls Beat on every iteration
+type WorkerHeartbeat struct {
+	watchdog *Watchdog
+	name     string
+}
+
+// Beat records the heartbeat of the worker, it's a no-op on the nil handle
+func (h *WorkerHeartbeat) Beat() {
+	if h == nil {
+		return
+	}
+
+	h.watchdog.beat(h.name, time.Now())
+}
+
+// Unregister removes the worker from the watchdog, it should be called when the worker exits normally
+func (h *WorkerHeartbeat) Unregister() {
+	if h == nil {
+		return
+	}
+
+	h.watchdog.Unregister(h.name)
+}
+
+type watchedWorker struct {
+	timeout       time.Duration
+	lastHeartbeat time.Time
+	stalled       bool
+}
+
+// Watchdog monitors the registered workers by their heartbeats and the goroutine count of the process,
+// it alerts when a worker stops ticking or the goroutine count exceeds the limit.
+type Watchdog struct {
+	MaxGoroutines int
+
+	mu      sync.Mutex
+	workers map[string]*watchedWorker
+
+	goroutineAlerted bool
+
+	// numGoroutine is used for testing
+	numGoroutine func() int
+}
+
+func NewWatchdog(maxGoroutines int) *Watchdog {
+	return &Watchdog{
+		MaxGoroutines: maxGoroutines,
+		workers:       make(map[string]*watchedWorker),
+		numGoroutine:  runtime.NumGoroutine,
+	}
+}
+
+// Register registers the worker with the heartbeat timeout, the worker is considered stalled
+// when no heartbeat is received within the timeout.
+func (w *Watchdog) Register(name string, timeout time.Duration) *WorkerHeartbeat {
+	w.mu.Lock()
+	w.workers[name] = &watchedWorker{
+		timeout:       timeout,
+		lastHeartbeat: time.Now(),
+	}
+	w.mu.Unlock()
+
+	return &WorkerHeartbeat{watchdog: w, name: name}
+}
+
+func (w *Watchdog) Unregister(name string) {
+	w.mu.Lock()
+	delete(w.workers, name)
+	w.mu.Unlock()
+}
+
+func (w *Watchdog) beat(name string, now time.Time) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	worker, ok := w.workers[name]
+	if !ok {
+		return
+	}
+
+	if worker.stalled {
+		log.Infof("watchdog: worker %s is ticking again", name)
+		Notify("Worker %s is ticking again", name)
+	}
+
+	worker.lastHeartbeat = now
+	worker.stalled = false
+}
+
+// Check checks the heartbeats of the workers and the goroutine count,
+// the alerts are sent when a worker becomes stalled or the goroutine count exceeds the limit.
+func (w *Watchdog) Check(now time.Time) WatchdogStatus {
+	goroutines := w.numGoroutine()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for name, worker := range w.workers {
+		stalled := now.Sub(worker.lastHeartbeat) > worker.timeout
+		if stalled && !worker.stalled {
+			log.Errorf("watchdog: worker %s stopped ticking, last heartbeat: %s", name, worker.lastHeartbeat)
+			Notify("Worker %s stopped ticking, last heartbeat %s ago", name, now.Sub(worker.lastHeartbeat).Round(time.Second))
+		}
+
+		worker.stalled = stalled
+	}
+
+	exceeded := w.MaxGoroutines > 0 && goroutines > w.MaxGoroutines
+	if exceeded && !w.goroutineAlerted {
+		log.Errorf("watchdog: the goroutine count %d exceeds the limit %d, possible goroutine leak", goroutines, w.MaxGoroutines)
+		Notify("The goroutine count %d exceeds the limit %d, possible goroutine leak", goroutines, w.MaxGoroutines)
+	}
+
+	w.goroutineAlerted = exceeded
+	return w.status(now, goroutines)
+}
+
+// Status returns the current health status without sending the alerts
+func (w *Watchdog) Status() WatchdogStatus {
+	goroutines := w.numGoroutine()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.status(time.Now(), goroutines)
+}
+
+func (w *Watchdog) status(now time.Time, goroutines int) WatchdogStatus {
+	status := WatchdogStatus{
+		Healthy:       w.MaxGoroutines <= 0 || goroutines <= w.MaxGoroutines,
+		Time:          now,
+		Goroutines:    goroutines,
+		MaxGoroutines: w.MaxGoroutines,
+	}
+
+	for name, worker := range w.workers {
+		stalled := now.Sub(worker.lastHeartbeat) > worker.timeout
+		if stalled {
+			status.Healthy = false
+		}
+
+		status.Workers = append(status.Workers, WorkerStatus{
+			Name:          name,
+			Timeout:       worker.timeout,
+			LastHeartbeat: worker.lastHeartbeat,
+			Stalled:       stalled,
+		})
+	}
+
+	sort.Slice(status.Workers, func(i, j int) bool {
+		return status.Workers[i].Name < status.Workers[j].Name
+	})
+
+	return status
+}
+
+func (w *Watchdog) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case now := <-ticker.C:
+			w.Check(now)
+		}
+	}
+}
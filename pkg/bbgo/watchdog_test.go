@@ -0,0 +1,48 @@
+package bbgo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWatchdog_Check(t *testing.T) {
+	watchdog := NewWatchdog(100)
+	watchdog.numGoroutine = func() int { return 10 }
+
+	quoteWorker := watchdog.Register("xmaker:BTCUSDT/quote", time.Minute)
+	watchdog.Register("xmaker:BTCUSDT/tradeRecover", time.Hour)
+
+	now := time.Now()
+	status := watchdog.Check(now)
+	assert.True(t, status.Healthy)
+	assert.Len(t, status.Workers, 2)
+
+	// the quote worker stops ticking
+	status = watchdog.Check(now.Add(2 * time.Minute))
+	assert.False(t, status.Healthy)
+	assert.Equal(t, "xmaker:BTCUSDT/quote", status.Workers[0].Name)
+	assert.True(t, status.Workers[0].Stalled)
+	assert.False(t, status.Workers[1].Stalled)
+
+	quoteWorker.Beat()
+	status = watchdog.Check(time.Now())
+	assert.True(t, status.Healthy)
+
+	quoteWorker.Unregister()
+	assert.Len(t, watchdog.Status().Workers, 1)
+}
+
+func TestWatchdog_Goroutines(t *testing.T) {
+	watchdog := NewWatchdog(100)
+	watchdog.numGoroutine = func() int { return 101 }
+
+	status := watchdog.Check(time.Now())
+	assert.False(t, status.Healthy)
+	assert.Equal(t, 101, status.Goroutines)
+
+	watchdog.numGoroutine = func() int { return 50 }
+	status = watchdog.Check(time.Now())
+	assert.True(t, status.Healthy)
+}
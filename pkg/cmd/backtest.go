@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"context"
 	"fmt"
+	"math/rand"
 	"os"
 	"path/filepath"
 	"strings"
@@ -266,6 +267,10 @@ var BacktestCmd = &cobra.Command{
 			log.SetLevel(log.ErrorLevel)
 		}
 
+		if userConfig.Backtest.Seed != 0 {
+			rand.Seed(userConfig.Backtest.Seed)
+		}
+
 		environ.SetStartTime(startTime)
 
 		// exchangeNameStr is the session name.
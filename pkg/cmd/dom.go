@@ -0,0 +1,242 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"syscall"
+	"time"
+
+	"github.com/fatih/color"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/c9s/bbgo/pkg/bbgo"
+	"github.com/c9s/bbgo/pkg/cmd/cmdutil"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// go run ./cmd/bbgo dom --session=binance --symbol=BTCUSDT
+var domCmd = &cobra.Command{
+	Use:   "dom --session=[exchange_name] --symbol=[pair_name]",
+	Short: "render a live depth-of-market view of the order book with the active orders highlighted",
+	PreRunE: cobraInitRequired([]string{
+		"config",
+		"session",
+		"symbol",
+	}),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		sessionName, err := cmd.Flags().GetString("session")
+		if err != nil {
+			return err
+		}
+
+		symbol, err := cmd.Flags().GetString("symbol")
+		if err != nil {
+			return fmt.Errorf("can not get the symbol from flags: %w", err)
+		}
+
+		if symbol == "" {
+			return fmt.Errorf("--symbol option is required")
+		}
+
+		depth, err := cmd.Flags().GetInt("depth")
+		if err != nil {
+			return err
+		}
+
+		interval, err := cmd.Flags().GetDuration("interval")
+		if err != nil {
+			return err
+		}
+
+		environ := bbgo.NewEnvironment()
+		if err := environ.ConfigureExchangeSessions(userConfig); err != nil {
+			return err
+		}
+
+		session, ok := environ.Session(sessionName)
+		if !ok {
+			return fmt.Errorf("session %s not found", sessionName)
+		}
+
+		orderBook := types.NewMutexOrderBook(symbol)
+
+		marketDataStream := session.Exchange.NewStream()
+		marketDataStream.SetPublicOnly()
+		marketDataStream.Subscribe(types.BookChannel, symbol, types.SubscribeOptions{})
+		marketDataStream.OnBookSnapshot(orderBook.Load)
+		marketDataStream.OnBookUpdate(orderBook.Update)
+
+		activeOrders := bbgo.NewActiveOrderBook(symbol)
+		openOrders, err := session.Exchange.QueryOpenOrders(ctx, symbol)
+		if err != nil {
+			return err
+		}
+		activeOrders.Add(openOrders...)
+
+		userDataStream := session.Exchange.NewStream()
+		activeOrders.BindStream(userDataStream)
+
+		log.Infof("connecting...")
+		if err := marketDataStream.Connect(ctx); err != nil {
+			return fmt.Errorf("failed to connect to %s", sessionName)
+		}
+
+		if err := userDataStream.Connect(ctx); err != nil {
+			return fmt.Errorf("failed to connect to %s", sessionName)
+		}
+
+		defer func() {
+			if err := marketDataStream.Close(); err != nil {
+				log.WithError(err).Errorf("connection close error")
+			}
+
+			if err := userDataStream.Close(); err != nil {
+				log.WithError(err).Errorf("connection close error")
+			}
+		}()
+
+		go func() {
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-ctx.Done():
+					return
+
+				case <-ticker.C:
+					var buf bytes.Buffer
+					renderDepthOfMarket(&buf, sessionName, symbol, orderBook.CopyDepth(depth), activeOrders.Orders())
+
+					// clear the screen and move the cursor to the top-left corner before rendering
+					fmt.Fprint(os.Stdout, "\033[H\033[2J")
+					_, _ = buf.WriteTo(os.Stdout)
+				}
+			}
+		}()
+
+		cmdutil.WaitForSignal(ctx, syscall.SIGINT, syscall.SIGTERM)
+		return nil
+	},
+}
+
+// domLevel is one price level of the depth-of-market view
+type domLevel struct {
+	price       fixedpoint.Value
+	bookVolume  fixedpoint.Value
+	orderVolume fixedpoint.Value
+}
+
+// buildDOMLevels merges the price levels of the book side and the active orders of the same side,
+// the orders at the prices that are not in the book are inserted as their own levels.
+func buildDOMLevels(pvs types.PriceVolumeSlice, orders []types.Order, side types.SideType) []domLevel {
+	var levels []domLevel
+	for _, pv := range pvs {
+		levels = append(levels, domLevel{price: pv.Price, bookVolume: pv.Volume})
+	}
+
+	for _, o := range orders {
+		if o.Side != side {
+			continue
+		}
+
+		remaining := o.Quantity.Sub(o.ExecutedQuantity)
+		found := false
+		for i := range levels {
+			if levels[i].price.Eq(o.Price) {
+				levels[i].orderVolume = levels[i].orderVolume.Add(remaining)
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			levels = append(levels, domLevel{price: o.Price, orderVolume: remaining})
+		}
+	}
+
+	// both sides are sorted from the highest price, so that the asks are rendered above the bids like a ladder
+	sort.Slice(levels, func(i, j int) bool {
+		return levels[i].price.Compare(levels[j].price) > 0
+	})
+
+	return levels
+}
+
+// domLadder is the price ladder of the depth-of-market view, both sides are sorted from the highest price
+type domLadder struct {
+	asks, bids []domLevel
+
+	spread    fixedpoint.Value
+	hasSpread bool
+}
+
+// buildDOMLadder builds the price ladder from the order book and the active orders
+func buildDOMLadder(book types.OrderBook, orders []types.Order) domLadder {
+	ladder := domLadder{
+		asks: buildDOMLevels(book.SideBook(types.SideTypeSell), orders, types.SideTypeSell),
+		bids: buildDOMLevels(book.SideBook(types.SideTypeBuy), orders, types.SideTypeBuy),
+	}
+
+	ladder.spread, ladder.hasSpread = book.Spread()
+	return ladder
+}
+
+func renderDepthOfMarket(w io.Writer, sessionName, symbol string, book types.OrderBook, orders []types.Order) {
+	ladder := buildDOMLadder(book, orders)
+
+	highlight := color.New(color.ReverseVideo).SprintFunc()
+	askColor := color.New(color.FgRed).SprintFunc()
+	bidColor := color.New(color.FgGreen).SprintFunc()
+
+	fmt.Fprintf(w, "%s %s depth of market, %d active orders, %s\n\n", sessionName, symbol, len(orders), time.Now().Format(time.RFC3339))
+	fmt.Fprintf(w, "%16s %16s %16s\n", "ORDERS", "PRICE", "VOLUME")
+
+	renderLevel := func(level domLevel, sprint func(a ...interface{}) string) {
+		line := fmt.Sprintf("%16s %16s %16s", formatDOMVolume(level.orderVolume), level.price.String(), formatDOMVolume(level.bookVolume))
+		if level.orderVolume.Sign() > 0 {
+			line = highlight(line)
+		}
+
+		fmt.Fprintln(w, sprint(line))
+	}
+
+	for _, level := range ladder.asks {
+		renderLevel(level, askColor)
+	}
+
+	if ladder.hasSpread {
+		fmt.Fprintf(w, "%16s %16s %16s\n", "", "spread "+ladder.spread.String(), "")
+	} else {
+		fmt.Fprintln(w, "")
+	}
+
+	for _, level := range ladder.bids {
+		renderLevel(level, bidColor)
+	}
+}
+
+func formatDOMVolume(v fixedpoint.Value) string {
+	if v.IsZero() {
+		return ""
+	}
+
+	return v.String()
+}
+
+func init() {
+	domCmd.Flags().String("session", "", "session name")
+	domCmd.Flags().String("symbol", "", "the trading pair. e.g, BTCUSDT, LTCUSDT...")
+	domCmd.Flags().Int("depth", 10, "the number of the price levels of each side")
+	domCmd.Flags().Duration("interval", 500*time.Millisecond, "the rendering interval")
+	RootCmd.AddCommand(domCmd)
+}
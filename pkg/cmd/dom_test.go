@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func newTestDOMOrder(side types.SideType, price, quantity, executed float64) types.Order {
+	return types.Order{
+		SubmitOrder: types.SubmitOrder{
+			Symbol:   "BTCUSDT",
+			Side:     side,
+			Price:    fixedpoint.NewFromFloat(price),
+			Quantity: fixedpoint.NewFromFloat(quantity),
+		},
+		ExecutedQuantity: fixedpoint.NewFromFloat(executed),
+	}
+}
+
+func assertDOMLevel(t *testing.T, level domLevel, price, bookVolume, orderVolume string) {
+	t.Helper()
+	assert.Equal(t, price, level.price.String(), "price")
+	assert.Equal(t, bookVolume, level.bookVolume.String(), "book volume of %s", price)
+	assert.Equal(t, orderVolume, level.orderVolume.String(), "order volume of %s", price)
+}
+
+func Test_buildDOMLevels(t *testing.T) {
+	pvs := types.PriceVolumeSlice{
+		{Price: fixedpoint.NewFromFloat(100.0), Volume: fixedpoint.NewFromFloat(1.0)},
+		{Price: fixedpoint.NewFromFloat(99.0), Volume: fixedpoint.NewFromFloat(2.0)},
+	}
+
+	orders := []types.Order{
+		// merged into the book level, the executed quantity is excluded
+		newTestDOMOrder(types.SideTypeBuy, 100.0, 0.5, 0.2),
+		newTestDOMOrder(types.SideTypeBuy, 100.0, 0.1, 0.0),
+		// not in the book, inserted as its own level
+		newTestDOMOrder(types.SideTypeBuy, 99.5, 0.3, 0.0),
+		// the orders of the other side are ignored
+		newTestDOMOrder(types.SideTypeSell, 99.0, 1.0, 0.0),
+	}
+
+	levels := buildDOMLevels(pvs, orders, types.SideTypeBuy)
+	if assert.Len(t, levels, 3) {
+		assertDOMLevel(t, levels[0], "100", "1", "0.4")
+		assertDOMLevel(t, levels[1], "99.5", "0", "0.3")
+		assertDOMLevel(t, levels[2], "99", "2", "0")
+	}
+}
+
+func Test_buildDOMLadder(t *testing.T) {
+	book := &types.SliceOrderBook{
+		Symbol: "BTCUSDT",
+		Bids: types.PriceVolumeSlice{
+			{Price: fixedpoint.NewFromFloat(99.0), Volume: fixedpoint.NewFromFloat(1.0)},
+			{Price: fixedpoint.NewFromFloat(98.0), Volume: fixedpoint.NewFromFloat(2.0)},
+		},
+		Asks: types.PriceVolumeSlice{
+			{Price: fixedpoint.NewFromFloat(101.0), Volume: fixedpoint.NewFromFloat(1.5)},
+			{Price: fixedpoint.NewFromFloat(102.0), Volume: fixedpoint.NewFromFloat(2.5)},
+		},
+	}
+
+	orders := []types.Order{
+		newTestDOMOrder(types.SideTypeSell, 102.0, 1.0, 0.0),
+		newTestDOMOrder(types.SideTypeBuy, 97.0, 1.0, 0.0),
+	}
+
+	ladder := buildDOMLadder(book, orders)
+
+	// the asks are sorted from the highest price so that the best ask is right above the spread
+	if assert.Len(t, ladder.asks, 2) {
+		assertDOMLevel(t, ladder.asks[0], "102", "2.5", "1")
+		assertDOMLevel(t, ladder.asks[1], "101", "1.5", "0")
+	}
+
+	if assert.Len(t, ladder.bids, 3) {
+		assertDOMLevel(t, ladder.bids[0], "99", "1", "0")
+		assertDOMLevel(t, ladder.bids[1], "98", "2", "0")
+		assertDOMLevel(t, ladder.bids[2], "97", "0", "1")
+	}
+
+	assert.True(t, ladder.hasSpread)
+	assert.Equal(t, "2", ladder.spread.String())
+
+	t.Run("empty book", func(t *testing.T) {
+		ladder := buildDOMLadder(types.NewSliceOrderBook("BTCUSDT"), orders)
+		assert.False(t, ladder.hasSpread)
+		assert.Len(t, ladder.asks, 1)
+		assert.Len(t, ladder.bids, 1)
+	})
+}
@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	killSwitchCmd.Flags().String("api", "http://localhost:8080", "the api endpoint of the running bbgo process")
+	killSwitchCmd.Flags().String("session", "", "the session of the kill switch")
+	killSwitchCmd.Flags().Bool("trip", false, "trip the kill switch, cancel all the open orders and block the order submissions")
+	killSwitchCmd.Flags().String("reason", "", "the reason of tripping the kill switch")
+	killSwitchCmd.Flags().Bool("reset", false, "reset the tripped kill switch")
+	RootCmd.AddCommand(killSwitchCmd)
+}
+
+// go run ./cmd/bbgo kill-switch --session=binance --reset
+var killSwitchCmd = &cobra.Command{
+	Use:          "kill-switch --session=[session] [--trip --reason=... | --reset]",
+	Short:        "show, trip or reset the session kill switch of the running bbgo process through its api",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		api, err := cmd.Flags().GetString("api")
+		if err != nil {
+			return err
+		}
+
+		sessionName, err := cmd.Flags().GetString("session")
+		if err != nil {
+			return err
+		}
+
+		if sessionName == "" {
+			return errors.New("--session option is required")
+		}
+
+		trip, err := cmd.Flags().GetBool("trip")
+		if err != nil {
+			return err
+		}
+
+		reason, err := cmd.Flags().GetString("reason")
+		if err != nil {
+			return err
+		}
+
+		reset, err := cmd.Flags().GetBool("reset")
+		if err != nil {
+			return err
+		}
+
+		if trip && reset {
+			return errors.New("--trip and --reset can not be used together")
+		}
+
+		url := strings.TrimSuffix(api, "/") + "/api/sessions/" + sessionName + "/kill-switch"
+		method := http.MethodGet
+		var body io.Reader
+
+		switch {
+		case trip:
+			url += "/trip"
+			method = http.MethodPost
+
+			payload, err := json.Marshal(map[string]string{"reason": reason})
+			if err != nil {
+				return err
+			}
+
+			body = bytes.NewReader(payload)
+
+		case reset:
+			url += "/reset"
+			method = http.MethodPost
+			body = strings.NewReader("{}")
+		}
+
+		req, err := http.NewRequestWithContext(ctx, method, url, body)
+		if err != nil {
+			return err
+		}
+
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return err
+		}
+
+		defer resp.Body.Close()
+
+		content, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return err
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("kill switch api error: %s %s", resp.Status, content)
+		}
+
+		fmt.Println(string(content))
+		return nil
+	},
+}
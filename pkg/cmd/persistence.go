@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/c9s/bbgo/pkg/bbgo"
+	"github.com/c9s/bbgo/pkg/service"
+)
+
+func init() {
+	migratePersistenceCmd.Flags().String("from", "", "the source persistence backend: redis or json")
+	migratePersistenceCmd.Flags().String("to", "", "the destination persistence backend: redis or json")
+	migratePersistenceCmd.Flags().Bool("dry-run", false, "only load the persisted states from the source backend without copying")
+	RootCmd.AddCommand(migratePersistenceCmd)
+}
+
+// go run ./cmd/bbgo migrate-persistence --config=config/xmaker.yaml --from=redis --to=json --dry-run
+var migratePersistenceCmd = &cobra.Command{
+	Use:          "migrate-persistence --from=[redis|json] --to=[redis|json]",
+	Short:        "copy the persisted strategy states (positions, profit stats, covered positions) between the persistence backends",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		configFile, err := cmd.Flags().GetString("config")
+		if err != nil {
+			return err
+		}
+
+		if len(configFile) == 0 {
+			return errors.New("--config option is required")
+		}
+
+		if _, err := os.Stat(configFile); os.IsNotExist(err) {
+			return err
+		}
+
+		fromName, err := cmd.Flags().GetString("from")
+		if err != nil {
+			return err
+		}
+
+		toName, err := cmd.Flags().GetString("to")
+		if err != nil {
+			return err
+		}
+
+		if fromName == toName {
+			return fmt.Errorf("the source backend and the destination backend should be different, got %q", fromName)
+		}
+
+		dryRun, err := cmd.Flags().GetBool("dry-run")
+		if err != nil {
+			return err
+		}
+
+		userConfig, err := bbgo.Load(configFile, true)
+		if err != nil {
+			return err
+		}
+
+		if userConfig.Persistence == nil {
+			return errors.New("persistence is not configured, both of the source and the destination backends are required")
+		}
+
+		facade, err := bbgo.NewPersistenceServiceFacade(userConfig.Persistence)
+		if err != nil {
+			return err
+		}
+
+		from, err := selectPersistenceService(facade, fromName)
+		if err != nil {
+			return err
+		}
+
+		to, err := selectPersistenceService(facade, toName)
+		if err != nil {
+			return err
+		}
+
+		var strategies []interface{}
+		for _, mount := range userConfig.ExchangeStrategies {
+			strategies = append(strategies, mount.Strategy)
+		}
+
+		for _, strategy := range userConfig.CrossExchangeStrategies {
+			strategies = append(strategies, strategy)
+		}
+
+		if len(strategies) == 0 {
+			return errors.New("no strategy is configured, the persisted states are located by the strategy instance IDs")
+		}
+
+		var numMigrated int
+		for _, strategy := range strategies {
+			records, err := bbgo.MigratePersistenceFields(strategy, from, to, dryRun)
+			if err != nil {
+				return err
+			}
+
+			for _, record := range records {
+				switch {
+				case record.Missing:
+					log.Infof("%s.%s: not persisted, skipped", record.ID, record.Field)
+				case dryRun:
+					log.Infof("[dry-run] %s.%s: %d bytes to copy", record.ID, record.Field, record.Size)
+					numMigrated++
+				default:
+					log.Infof("%s.%s: %d bytes copied and verified", record.ID, record.Field, record.Size)
+					numMigrated++
+				}
+			}
+		}
+
+		if dryRun {
+			log.Infof("[dry-run] %d persisted states can be migrated from %s to %s", numMigrated, fromName, toName)
+		} else {
+			log.Infof("%d persisted states are migrated from %s to %s", numMigrated, fromName, toName)
+		}
+
+		return nil
+	},
+}
+
+func selectPersistenceService(facade *service.PersistenceServiceFacade, name string) (service.PersistenceService, error) {
+	switch name {
+	case "redis":
+		if facade.Redis != nil {
+			return facade.Redis, nil
+		}
+
+	case "json":
+		if facade.Json != nil {
+			return facade.Json, nil
+		}
+
+	default:
+		return nil, fmt.Errorf("unsupported persistence backend %q, valid backends are: redis, json", name)
+	}
+
+	return nil, fmt.Errorf("persistence backend %s is not configured", name)
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/c9s/bbgo/pkg/bbgo"
+)
+
+func init() {
+	portfolioCmd.Flags().Bool("json", false, "print the portfolio in JSON")
+	RootCmd.AddCommand(portfolioCmd)
+}
+
+// go run ./cmd/bbgo portfolio --config config/bbgo.yaml
+var portfolioCmd = &cobra.Command{
+	Use:          "portfolio [--json]",
+	Short:        "Show the consolidated portfolio across all the sessions",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		printJSON, err := cmd.Flags().GetBool("json")
+		if err != nil {
+			return err
+		}
+
+		if userConfig == nil {
+			return fmt.Errorf("user config is not loaded")
+		}
+
+		environ := bbgo.NewEnvironment()
+		if err := environ.ConfigureExchangeSessions(userConfig); err != nil {
+			return err
+		}
+
+		portfolio, err := environ.QueryPortfolio(ctx)
+		if err != nil {
+			return err
+		}
+
+		if printJSON {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(portfolio)
+		}
+
+		fmt.Print(portfolio.String())
+		return nil
+	},
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/spf13/viper"
 
 	"github.com/c9s/bbgo/pkg/bbgo"
+	"github.com/c9s/bbgo/pkg/exchange/apistats"
 	"github.com/c9s/bbgo/pkg/util"
 
 	_ "time/tzdata"
@@ -73,6 +74,7 @@ var RootCmd = &cobra.Command{
 
 		if viper.GetBool("metrics") {
 			http.Handle("/metrics", promhttp.Handler())
+			http.HandleFunc("/debug/api-errors", apistats.ReportHandler)
 			go func() {
 				port := viper.GetString("metrics-port")
 				log.Infof("starting metrics server at :%s", port)
@@ -16,6 +16,7 @@ import (
 
 	"github.com/c9s/bbgo/pkg/bbgo"
 	"github.com/c9s/bbgo/pkg/cmd/cmdutil"
+	"github.com/c9s/bbgo/pkg/embed"
 	"github.com/c9s/bbgo/pkg/grpc"
 	"github.com/c9s/bbgo/pkg/server"
 )
@@ -121,57 +122,29 @@ func runConfig(basectx context.Context, cmd *cobra.Command, userConfig *bbgo.Con
 		return err
 	}
 
-	_ = grpcBind
-	_ = enableGrpc
-
-	tradingCtx, cancelTrading := context.WithCancel(basectx)
-	defer cancelTrading()
-
-	environ := bbgo.NewEnvironment()
-
 	lightweight, err := cmd.Flags().GetBool("lightweight")
 	if err != nil {
 		return err
 	}
 
-	if lightweight {
-		if err := bbgo.BootstrapEnvironmentLightweight(tradingCtx, environ, userConfig); err != nil {
-			return err
-		}
-	} else {
-		if err := bbgo.BootstrapEnvironment(tradingCtx, environ, userConfig); err != nil {
-			return err
-		}
-	}
-
-	if err := environ.Init(tradingCtx); err != nil {
-		return err
-	}
-
-	if !noSync {
-		if err := environ.Sync(tradingCtx, userConfig); err != nil {
-			return err
-		}
-
-		if userConfig.Sync != nil {
-			environ.BindSync(userConfig.Sync)
-		}
-	}
+	tradingCtx, cancelTrading := context.WithCancel(basectx)
+	defer cancelTrading()
 
-	trader := bbgo.NewTrader(environ)
-	if err := trader.Configure(userConfig); err != nil {
-		return err
+	var options []embed.Option
+	if lightweight {
+		options = append(options, embed.WithLightweight())
 	}
 
-	if err := trader.Initialize(tradingCtx); err != nil {
-		return err
+	if noSync {
+		options = append(options, embed.WithoutSync())
 	}
 
-	if err := trader.LoadState(tradingCtx); err != nil {
+	env, err := embed.NewEnvironment(tradingCtx, userConfig, options...)
+	if err != nil {
 		return err
 	}
 
-	if err := trader.Run(tradingCtx); err != nil {
+	if err := env.Start(tradingCtx); err != nil {
 		return err
 	}
 
@@ -179,8 +152,8 @@ func runConfig(basectx context.Context, cmd *cobra.Command, userConfig *bbgo.Con
 		go func() {
 			s := &server.Server{
 				Config:  userConfig,
-				Environ: environ,
-				Trader:  trader,
+				Environ: env.Environ(),
+				Trader:  env.Trader(),
 			}
 
 			if err := s.Run(tradingCtx, webServerBind); err != nil {
@@ -193,8 +166,8 @@ func runConfig(basectx context.Context, cmd *cobra.Command, userConfig *bbgo.Con
 		go func() {
 			s := &grpc.Server{
 				Config:  userConfig,
-				Environ: environ,
-				Trader:  trader,
+				Environ: env.Environ(),
+				Trader:  env.Trader(),
 			}
 			if err := s.ListenAndServe(grpcBind); err != nil {
 				log.WithError(err).Errorf("grpc server bind error")
@@ -205,25 +178,10 @@ func runConfig(basectx context.Context, cmd *cobra.Command, userConfig *bbgo.Con
 	cmdutil.WaitForSignal(tradingCtx, syscall.SIGINT, syscall.SIGTERM)
 	cancelTrading()
 
-	gracefulShutdownPeriod := 30 * time.Second
-	shtCtx, cancelShutdown := context.WithTimeout(bbgo.NewTodoContextWithExistingIsolation(tradingCtx), gracefulShutdownPeriod)
-	bbgo.Shutdown(shtCtx)
-
-	if err := trader.SaveState(shtCtx); err != nil {
-		log.WithError(err).Errorf("can not save strategy persistence states")
-	}
-
+	shtCtx, cancelShutdown := context.WithTimeout(bbgo.NewTodoContextWithExistingIsolation(tradingCtx), embed.DefaultGracefulShutdownPeriod)
+	env.Shutdown(shtCtx)
 	cancelShutdown()
 
-	for _, session := range environ.Sessions() {
-		if err := session.MarketDataStream.Close(); err != nil {
-			log.WithError(err).Errorf("[%s] market data stream close error", session.Name)
-		}
-		if err := session.UserDataStream.Close(); err != nil {
-			log.WithError(err).Errorf("[%s] user data stream close error", session.Name)
-		}
-	}
-
 	return nil
 }
 
@@ -0,0 +1,83 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// TradeConverter converts the trade before the trade is added to the position
+type TradeConverter interface {
+	ConvertTrade(trade types.Trade) (types.Trade, error)
+}
+
+// ConverterManager applies the trade converters in the order they are added
+type ConverterManager struct {
+	converters []TradeConverter
+}
+
+func (m *ConverterManager) AddConverter(converters ...TradeConverter) {
+	m.converters = append(m.converters, converters...)
+}
+
+// ConvertTrade converts the trade by the converters,
+// the converter that fails is skipped and the trade converted so far is passed to the next converter.
+func (m *ConverterManager) ConvertTrade(trade types.Trade) types.Trade {
+	for _, converter := range m.converters {
+		converted, err := converter.ConvertTrade(trade)
+		if err != nil {
+			logrus.WithError(err).Errorf("trade converter %T error, trade: %s", converter, trade.String())
+			continue
+		}
+
+		trade = converted
+	}
+
+	return trade
+}
+
+// PriceResolver resolves the price of the asset in the currency, e.g. pricesolver.SimplePriceSolver
+type PriceResolver interface {
+	ResolvePrice(asset, currency string, prefers ...string) (fixedpoint.Value, bool)
+}
+
+// FeeCurrencyConverter converts the fee paid in a third currency, e.g. BNB or the other exchange tokens,
+// into the quote currency of the trade market, and sets the converted fee to the FeeInQuote of the trade.
+// The Fee and the FeeCurrency are kept as-is since the fee is not deducted from the trade amounts.
+type FeeCurrencyConverter struct {
+	resolver PriceResolver
+	markets  types.MarketMap
+}
+
+func NewFeeCurrencyConverter(resolver PriceResolver, markets types.MarketMap) *FeeCurrencyConverter {
+	return &FeeCurrencyConverter{
+		resolver: resolver,
+		markets:  markets,
+	}
+}
+
+func (c *FeeCurrencyConverter) ConvertTrade(trade types.Trade) (types.Trade, error) {
+	if trade.Fee.IsZero() || trade.FeeCurrency == "" || !trade.FeeInQuote.IsZero() {
+		return trade, nil
+	}
+
+	market, ok := c.markets[trade.Symbol]
+	if !ok {
+		return trade, fmt.Errorf("market %s is not found", trade.Symbol)
+	}
+
+	if trade.FeeCurrency == market.BaseCurrency || trade.FeeCurrency == market.QuoteCurrency {
+		return trade, nil
+	}
+
+	price, ok := c.resolver.ResolvePrice(trade.FeeCurrency, market.QuoteCurrency)
+	if !ok {
+		return trade, fmt.Errorf("unable to resolve the price of the fee currency %s in %s", trade.FeeCurrency, market.QuoteCurrency)
+	}
+
+	trade.FeeInQuote = trade.Fee.Mul(price)
+	return trade, nil
+}
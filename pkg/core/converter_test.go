@@ -0,0 +1,101 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/pricesolver"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestFeeCurrencyConverter(t *testing.T) {
+	markets := types.MarketMap{
+		"BTCUSDT": {Symbol: "BTCUSDT", BaseCurrency: "BTC", QuoteCurrency: "USDT"},
+		"BNBUSDT": {Symbol: "BNBUSDT", BaseCurrency: "BNB", QuoteCurrency: "USDT"},
+	}
+
+	solver := pricesolver.NewSimplePriceResolver(markets)
+	solver.Update("BNBUSDT", fixedpoint.NewFromFloat(500.0))
+
+	converter := NewFeeCurrencyConverter(solver, markets)
+
+	trade, err := converter.ConvertTrade(types.Trade{
+		Symbol:      "BTCUSDT",
+		Fee:         fixedpoint.NewFromFloat(0.01),
+		FeeCurrency: "BNB",
+	})
+	if assert.NoError(t, err) {
+		assert.Equal(t, "5", trade.FeeInQuote.String())
+		assert.Equal(t, "BNB", trade.FeeCurrency)
+	}
+
+	// the fee in the base or the quote currency is not converted
+	trade, err = converter.ConvertTrade(types.Trade{
+		Symbol:      "BTCUSDT",
+		Fee:         fixedpoint.NewFromFloat(0.01),
+		FeeCurrency: "USDT",
+	})
+	if assert.NoError(t, err) {
+		assert.True(t, trade.FeeInQuote.IsZero())
+	}
+
+	_, err = converter.ConvertTrade(types.Trade{
+		Symbol:      "BTCUSDT",
+		Fee:         fixedpoint.NewFromFloat(0.01),
+		FeeCurrency: "OKB",
+	})
+	assert.Error(t, err)
+}
+
+func TestTradeCollector_ConvertTrade(t *testing.T) {
+	symbol := "BTCUSDT"
+	markets := types.MarketMap{
+		"BTCUSDT": {Symbol: "BTCUSDT", BaseCurrency: "BTC", QuoteCurrency: "USDT"},
+		"BNBUSDT": {Symbol: "BNBUSDT", BaseCurrency: "BNB", QuoteCurrency: "USDT"},
+	}
+
+	solver := pricesolver.NewSimplePriceResolver(markets)
+	solver.Update("BNBUSDT", fixedpoint.NewFromFloat(500.0))
+
+	position := types.NewPosition(symbol, "BTC", "USDT")
+	orderStore := NewOrderStore(symbol)
+	orderStore.Add(types.Order{OrderID: 1}, types.Order{OrderID: 2})
+
+	collector := NewTradeCollector(symbol, position, orderStore)
+	collector.AddConverter(NewFeeCurrencyConverter(solver, markets))
+
+	var netProfit fixedpoint.Value
+	collector.OnTrade(func(trade types.Trade, _, n fixedpoint.Value) {
+		netProfit = n
+	})
+
+	assert.True(t, collector.ProcessTrade(types.Trade{
+		ID:            1,
+		OrderID:       1,
+		Symbol:        symbol,
+		Side:          types.SideTypeBuy,
+		Price:         fixedpoint.NewFromInt(40000),
+		Quantity:      fixedpoint.One,
+		QuoteQuantity: fixedpoint.NewFromInt(40000),
+		Fee:           fixedpoint.NewFromFloat(0.01),
+		FeeCurrency:   "BNB",
+	}))
+
+	assert.True(t, collector.ProcessTrade(types.Trade{
+		ID:            2,
+		OrderID:       2,
+		Symbol:        symbol,
+		Side:          types.SideTypeSell,
+		Price:         fixedpoint.NewFromInt(41000),
+		Quantity:      fixedpoint.One,
+		QuoteQuantity: fixedpoint.NewFromInt(41000),
+		Fee:           fixedpoint.NewFromFloat(0.01),
+		FeeCurrency:   "BNB",
+	}))
+
+	// the buy fee 5 USDT is added to the cost, and the sell fee 5 USDT is deducted from the profit
+	assert.Equal(t, "990", netProfit.String())
+	assert.Equal(t, "0.02", position.TotalFee["BNB"].String())
+}
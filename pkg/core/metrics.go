@@ -0,0 +1,22 @@
+package core
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var (
+	metricsTradeCollectorDroppedTrades = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "bbgo_trade_collector_dropped_trades_total",
+			Help: "the number of the trades dropped by the trade collector",
+		},
+		[]string{
+			"symbol",
+			"reason", // duplicated or stale
+		},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(
+		metricsTradeCollectorDroppedTrades,
+	)
+}
@@ -24,6 +24,11 @@ type OrderStore struct {
 	// AddOrderUpdate adds the order into the store when receiving an order update when the order does not exist in the current store.
 	AddOrderUpdate bool
 	C              chan types.Order
+
+	// Compaction removes the closed orders from the store, nil means the closed orders are kept
+	Compaction *OrderStoreCompaction
+
+	writer *orderStoreWriter
 }
 
 func NewOrderStore(symbol string) *OrderStore {
@@ -104,6 +109,7 @@ func (s *OrderStore) Add(orders ...types.Order) {
 			o.Tag = old.Tag
 		}
 		s.orders[o.OrderID] = o
+		s.persistOrder(o)
 	}
 }
 
@@ -112,6 +118,7 @@ func (s *OrderStore) Remove(o types.Order) {
 	defer s.mu.Unlock()
 
 	delete(s.orders, o.OrderID)
+	s.unpersistOrder(o)
 }
 
 func (s *OrderStore) Update(o types.Order) bool {
@@ -122,6 +129,7 @@ func (s *OrderStore) Update(o types.Order) bool {
 	if ok {
 		o.Tag = old.Tag
 		s.orders[o.OrderID] = o
+		s.persistOrder(o)
 	}
 	return ok
 }
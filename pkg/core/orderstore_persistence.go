@@ -0,0 +1,332 @@
+package core
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// OrderStorePersistence persists the orders of the order store,
+// so that the order state survives the restarts and the in-flight orders can be reconciled after a crash.
+// See service.KVOrderStorePersistence and service.SQLOrderStorePersistence.
+type OrderStorePersistence interface {
+	LoadOrders(ctx context.Context) ([]types.Order, error)
+
+	// SaveOrder inserts or updates the order
+	SaveOrder(ctx context.Context, order types.Order) error
+
+	RemoveOrder(ctx context.Context, order types.Order) error
+}
+
+// DefaultOrderStoreCompactionInterval is the default interval of running the compaction
+const DefaultOrderStoreCompactionInterval = time.Minute
+
+// orderStorePersistenceTimeout is the timeout of writing an order to the persistence backend
+const orderStorePersistenceTimeout = 5 * time.Second
+
+// OrderStoreCompaction is the compaction policy of the closed orders, i.e. the filled, canceled and rejected orders.
+// The closed orders that are updated before the ClosedOrderTTL, or exceed the MaxClosedOrders, are removed from the store.
+type OrderStoreCompaction struct {
+	// MaxClosedOrders is the maximum number of the closed orders kept in the store, zero means no limit
+	MaxClosedOrders int `json:"maxClosedOrders"`
+
+	// ClosedOrderTTL is the time the closed orders are kept after the last update, zero means no limit
+	ClosedOrderTTL types.Duration `json:"closedOrderTTL"`
+
+	// GracePeriod keeps the recently closed orders in the store regardless of the limits, so that the trades
+	// of the just filled orders arriving after the order updates can still be matched, default to TradeExpiryTime
+	GracePeriod types.Duration `json:"gracePeriod,omitempty"`
+
+	// Interval is the interval of running the compaction, default to DefaultOrderStoreCompactionInterval
+	Interval types.Duration `json:"interval,omitempty"`
+}
+
+func (c *OrderStoreCompaction) gracePeriod() time.Duration {
+	if c.GracePeriod > 0 {
+		return c.GracePeriod.Duration()
+	}
+
+	return TradeExpiryTime
+}
+
+func (c *OrderStoreCompaction) interval() time.Duration {
+	if c.Interval > 0 {
+		return c.Interval.Duration()
+	}
+
+	return DefaultOrderStoreCompactionInterval
+}
+
+type orderStoreWrite struct {
+	order  types.Order
+	remove bool
+}
+
+// orderStoreWriter writes the orders to the persistence backend in the background, so that the slow backend
+// never blocks the order updates. The pending writes are coalesced by the order ID, only the latest state of
+// an order is written, hence the queue is bounded by the number of the orders.
+type orderStoreWriter struct {
+	persistence OrderStorePersistence
+
+	mu      sync.Mutex
+	pending map[uint64]orderStoreWrite
+
+	notifyC chan struct{}
+	flushC  chan chan struct{}
+	closeC  chan struct{}
+}
+
+func newOrderStoreWriter(persistence OrderStorePersistence) *orderStoreWriter {
+	w := &orderStoreWriter{
+		persistence: persistence,
+		pending:     make(map[uint64]orderStoreWrite),
+		notifyC:     make(chan struct{}, 1),
+		flushC:      make(chan chan struct{}),
+		closeC:      make(chan struct{}),
+	}
+
+	go w.run()
+	return w
+}
+
+func (w *orderStoreWriter) enqueue(o types.Order, remove bool) {
+	w.mu.Lock()
+	w.pending[o.OrderID] = orderStoreWrite{order: o, remove: remove}
+	w.mu.Unlock()
+
+	select {
+	case w.notifyC <- struct{}{}:
+	default:
+	}
+}
+
+func (w *orderStoreWriter) run() {
+	for {
+		select {
+		case <-w.notifyC:
+			w.writePending()
+
+		case done := <-w.flushC:
+			w.writePending()
+			close(done)
+
+		case <-w.closeC:
+			w.writePending()
+			return
+		}
+	}
+}
+
+func (w *orderStoreWriter) writePending() {
+	w.mu.Lock()
+	pending := w.pending
+	w.pending = make(map[uint64]orderStoreWrite)
+	w.mu.Unlock()
+
+	for _, write := range pending {
+		ctx, cancel := context.WithTimeout(context.Background(), orderStorePersistenceTimeout)
+		if write.remove {
+			if err := w.persistence.RemoveOrder(ctx, write.order); err != nil {
+				logrus.WithError(err).Errorf("unable to remove the order %d from the order store persistence", write.order.OrderID)
+			}
+		} else if err := w.persistence.SaveOrder(ctx, write.order); err != nil {
+			logrus.WithError(err).Errorf("unable to save the order %d to the order store persistence", write.order.OrderID)
+		}
+		cancel()
+	}
+}
+
+// flush waits until the pending writes are written
+func (w *orderStoreWriter) flush(ctx context.Context) error {
+	done := make(chan struct{})
+	select {
+	case w.flushC <- done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (w *orderStoreWriter) close() {
+	close(w.closeC)
+}
+
+// SetPersistence sets the persistence backend, the changed orders are written to the backend in the background
+func (s *OrderStore) SetPersistence(persistence OrderStorePersistence) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.writer != nil {
+		s.writer.close()
+		s.writer = nil
+	}
+
+	if persistence != nil {
+		s.writer = newOrderStoreWriter(persistence)
+	}
+}
+
+// FlushPersistence waits until the changed orders are written to the persistence backend, e.g. before shutting down
+func (s *OrderStore) FlushPersistence(ctx context.Context) error {
+	s.mu.Lock()
+	writer := s.writer
+	s.mu.Unlock()
+
+	if writer == nil {
+		return nil
+	}
+
+	return writer.flush(ctx)
+}
+
+// Restore loads the orders from the persistence backend into the store
+func (s *OrderStore) Restore(ctx context.Context) error {
+	s.mu.Lock()
+	writer := s.writer
+	s.mu.Unlock()
+
+	if writer == nil {
+		return nil
+	}
+
+	orders, err := writer.persistence.LoadOrders(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	for _, o := range orders {
+		if s.Symbol != "" && o.Symbol != s.Symbol {
+			continue
+		}
+
+		s.orders[o.OrderID] = o
+	}
+	s.mu.Unlock()
+
+	logrus.Infof("%d %s orders are restored from the order store persistence", len(orders), s.Symbol)
+	return nil
+}
+
+// Reconcile queries the restored in-flight orders from the exchange and updates their states,
+// the orders that were filled or canceled while the strategy was down are handled as the order updates.
+func (s *OrderStore) Reconcile(ctx context.Context, service types.ExchangeOrderQueryService) error {
+	var openOrders []types.Order
+	for _, o := range s.Orders() {
+		if !o.Status.Closed() {
+			openOrders = append(openOrders, o)
+		}
+	}
+
+	var lastErr error
+	for _, o := range openOrders {
+		updated, err := service.QueryOrder(ctx, types.OrderQuery{
+			Symbol:        o.Symbol,
+			OrderID:       strconv.FormatUint(o.OrderID, 10),
+			ClientOrderID: o.ClientOrderID,
+		})
+		if err != nil {
+			logrus.WithError(err).Errorf("unable to reconcile the order %s", o.String())
+			lastErr = err
+			continue
+		}
+
+		s.HandleOrderUpdate(*updated)
+	}
+
+	return lastErr
+}
+
+// StartCompaction runs the compaction periodically until the context is canceled
+func (s *OrderStore) StartCompaction(ctx context.Context) {
+	if s.Compaction == nil {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(s.Compaction.interval())
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+
+			case now := <-ticker.C:
+				if removed := s.Compact(now); removed > 0 {
+					logrus.Debugf("%d closed %s orders are compacted", removed, s.Symbol)
+				}
+			}
+		}
+	}()
+}
+
+// Compact removes the closed orders by the compaction policy, and returns the number of the removed orders,
+// the orders closed within the grace period are always kept.
+func (s *OrderStore) Compact(now time.Time) int {
+	if s.Compaction == nil {
+		return 0
+	}
+
+	s.mu.Lock()
+	var closedOrders []types.Order
+	for _, o := range s.orders {
+		if o.Status.Closed() {
+			closedOrders = append(closedOrders, o)
+		}
+	}
+	s.mu.Unlock()
+
+	maxClosedOrders := s.Compaction.MaxClosedOrders
+	if maxClosedOrders > 0 && len(closedOrders) > maxClosedOrders {
+		// the latest updated orders are kept
+		sort.Slice(closedOrders, func(i, j int) bool {
+			return closedOrders[i].UpdateTime.After(closedOrders[j].UpdateTime.Time())
+		})
+	}
+
+	ttl := s.Compaction.ClosedOrderTTL.Duration()
+	gracePeriod := s.Compaction.gracePeriod()
+	removed := 0
+	for i, o := range closedOrders {
+		age := now.Sub(o.UpdateTime.Time())
+		if age <= gracePeriod {
+			continue
+		}
+
+		expired := ttl > 0 && age > ttl
+		exceeded := maxClosedOrders > 0 && i >= maxClosedOrders
+		if expired || exceeded {
+			s.Remove(o)
+			removed++
+		}
+	}
+
+	return removed
+}
+
+// persistOrder enqueues the order to the persistence writer, it's called with the lock held
+func (s *OrderStore) persistOrder(o types.Order) {
+	if s.writer != nil {
+		s.writer.enqueue(o, false)
+	}
+}
+
+// unpersistOrder enqueues the order removal to the persistence writer, it's called with the lock held
+func (s *OrderStore) unpersistOrder(o types.Order) {
+	if s.writer != nil {
+		s.writer.enqueue(o, true)
+	}
+}
@@ -0,0 +1,220 @@
+package core
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+	"github.com/c9s/bbgo/pkg/types/mocks"
+)
+
+type testOrderStorePersistence struct {
+	mu     sync.Mutex
+	orders map[uint64]types.Order
+
+	// blockC blocks the writes until it's closed
+	blockC chan struct{}
+}
+
+func (p *testOrderStorePersistence) LoadOrders(ctx context.Context) (orders []types.Order, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, o := range p.orders {
+		orders = append(orders, o)
+	}
+	return orders, nil
+}
+
+func (p *testOrderStorePersistence) SaveOrder(ctx context.Context, order types.Order) error {
+	p.wait()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.orders[order.OrderID] = order
+	return nil
+}
+
+func (p *testOrderStorePersistence) RemoveOrder(ctx context.Context, order types.Order) error {
+	p.wait()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	delete(p.orders, order.OrderID)
+	return nil
+}
+
+func (p *testOrderStorePersistence) wait() {
+	if p.blockC != nil {
+		<-p.blockC
+	}
+}
+
+func (p *testOrderStorePersistence) Orders() map[uint64]types.Order {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	orders := make(map[uint64]types.Order, len(p.orders))
+	for k, o := range p.orders {
+		orders[k] = o
+	}
+	return orders
+}
+
+func newTestOrder(orderID uint64, status types.OrderStatus, updateTime time.Time) types.Order {
+	return types.Order{
+		SubmitOrder: types.SubmitOrder{
+			Symbol:   "BTCUSDT",
+			Side:     types.SideTypeBuy,
+			Type:     types.OrderTypeLimit,
+			Quantity: fixedpoint.One,
+			Price:    fixedpoint.NewFromInt(40000),
+		},
+		OrderID:    orderID,
+		Status:     status,
+		UpdateTime: types.Time(updateTime),
+	}
+}
+
+func TestOrderStore_Persistence(t *testing.T) {
+	persistence := &testOrderStorePersistence{orders: make(map[uint64]types.Order)}
+	now := time.Now()
+
+	store := NewOrderStore("BTCUSDT")
+	store.SetPersistence(persistence)
+	store.Add(newTestOrder(1, types.OrderStatusNew, now), newTestOrder(2, types.OrderStatusNew, now))
+	store.HandleOrderUpdate(newTestOrder(1, types.OrderStatusPartiallyFilled, now))
+	store.HandleOrderUpdate(newTestOrder(2, types.OrderStatusCanceled, now))
+	assert.NoError(t, store.FlushPersistence(context.Background()))
+
+	orders := persistence.Orders()
+	assert.Len(t, orders, 1)
+	assert.Equal(t, types.OrderStatusPartiallyFilled, orders[1].Status)
+
+	// simulate the restart
+	restored := NewOrderStore("BTCUSDT")
+	restored.SetPersistence(persistence)
+	assert.NoError(t, restored.Restore(context.Background()))
+	order, ok := restored.Get(1)
+	assert.True(t, ok)
+	assert.Equal(t, types.OrderStatusPartiallyFilled, order.Status)
+}
+
+func TestOrderStore_Reconcile(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	now := time.Now()
+	persistence := &testOrderStorePersistence{orders: map[uint64]types.Order{
+		1: newTestOrder(1, types.OrderStatusNew, now),
+		2: newTestOrder(2, types.OrderStatusFilled, now),
+	}}
+
+	store := NewOrderStore("BTCUSDT")
+	store.SetPersistence(persistence)
+	assert.NoError(t, store.Restore(context.Background()))
+
+	// only the in-flight order is queried
+	queryService := mocks.NewMockExchangeOrderQueryService(mockCtrl)
+	filled := newTestOrder(1, types.OrderStatusFilled, now)
+	queryService.EXPECT().QueryOrder(gomock.Any(), types.OrderQuery{
+		Symbol:  "BTCUSDT",
+		OrderID: "1",
+	}).Return(&filled, nil)
+
+	assert.NoError(t, store.Reconcile(context.Background(), queryService))
+	order, ok := store.Get(1)
+	assert.True(t, ok)
+	assert.Equal(t, types.OrderStatusFilled, order.Status)
+	assert.NoError(t, store.FlushPersistence(context.Background()))
+	assert.Equal(t, types.OrderStatusFilled, persistence.Orders()[1].Status)
+}
+
+func TestOrderStore_Persistence_SlowBackend(t *testing.T) {
+	persistence := &testOrderStorePersistence{
+		orders: make(map[uint64]types.Order),
+		blockC: make(chan struct{}),
+	}
+	now := time.Now()
+
+	store := NewOrderStore("BTCUSDT")
+	store.SetPersistence(persistence)
+
+	// the order updates and the readers are not blocked by the backend
+	store.Add(newTestOrder(1, types.OrderStatusNew, now))
+	store.HandleOrderUpdate(newTestOrder(1, types.OrderStatusPartiallyFilled, now))
+	store.HandleOrderUpdate(newTestOrder(1, types.OrderStatusFilled, now))
+	assert.True(t, store.Exists(1))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, store.FlushPersistence(ctx), context.DeadlineExceeded)
+
+	// the latest state of the order is written once the backend is available
+	close(persistence.blockC)
+	assert.NoError(t, store.FlushPersistence(context.Background()))
+	assert.Equal(t, types.OrderStatusFilled, persistence.Orders()[1].Status)
+}
+
+func TestOrderStore_Compact(t *testing.T) {
+	persistence := &testOrderStorePersistence{orders: make(map[uint64]types.Order)}
+	now := time.Now()
+
+	store := NewOrderStore("BTCUSDT")
+	store.SetPersistence(persistence)
+	store.Compaction = &OrderStoreCompaction{
+		MaxClosedOrders: 2,
+		ClosedOrderTTL:  types.Duration(time.Hour),
+		GracePeriod:     types.Duration(90 * time.Second),
+	}
+
+	store.Add(
+		newTestOrder(1, types.OrderStatusFilled, now.Add(-2*time.Hour)),
+		newTestOrder(2, types.OrderStatusFilled, now.Add(-3*time.Minute)),
+		newTestOrder(3, types.OrderStatusFilled, now.Add(-2*time.Minute)),
+		newTestOrder(4, types.OrderStatusFilled, now.Add(-time.Minute)),
+		newTestOrder(5, types.OrderStatusNew, now.Add(-3*time.Hour)),
+		newTestOrder(6, types.OrderStatusFilled, now.Add(-time.Second)),
+	)
+
+	assert.Equal(t, 3, store.Compact(now))
+	assert.False(t, store.Exists(1), "expired order should be removed")
+	assert.False(t, store.Exists(2), "the oldest order over the limit should be removed")
+	assert.False(t, store.Exists(3), "the older order over the limit should be removed")
+	assert.True(t, store.Exists(4), "the order in the grace period should be kept")
+	assert.True(t, store.Exists(5), "open order should never be compacted")
+	assert.True(t, store.Exists(6))
+
+	assert.NoError(t, store.FlushPersistence(context.Background()))
+	assert.Len(t, persistence.Orders(), 3)
+
+	// the just filled order is kept for matching its trades
+	store.HandleOrderUpdate(newTestOrder(5, types.OrderStatusFilled, now))
+	assert.Equal(t, 0, store.Compact(now))
+	assert.True(t, store.Exists(5))
+}
+
+func TestOrderStore_Compact_DefaultGracePeriod(t *testing.T) {
+	now := time.Now()
+
+	store := NewOrderStore("BTCUSDT")
+	store.Compaction = &OrderStoreCompaction{MaxClosedOrders: 1}
+	store.Add(
+		newTestOrder(1, types.OrderStatusFilled, now.Add(-TradeExpiryTime-time.Minute)),
+		newTestOrder(2, types.OrderStatusFilled, now.Add(-time.Hour)),
+		newTestOrder(3, types.OrderStatusFilled, now.Add(-time.Minute)),
+	)
+
+	assert.Equal(t, 1, store.Compact(now))
+	assert.False(t, store.Exists(1))
+	assert.True(t, store.Exists(2))
+	assert.True(t, store.Exists(3))
+}
@@ -2,6 +2,7 @@ package core
 
 import (
 	"context"
+	"sort"
 	"sync"
 	"time"
 
@@ -12,8 +13,16 @@ import (
 	"github.com/c9s/bbgo/pkg/types"
 )
 
+// MaximumDoneTrades is the size of the done trade keys that triggers the pruning,
+// the keys older than TradeExpiryTime are pruned.
+const MaximumDoneTrades = 10_000
+
 //go:generate callbackgen -type TradeCollector
 type TradeCollector struct {
+	// ConverterManager converts the trades before they are added to the position,
+	// e.g. converting the fee paid in a third currency into the quote currency
+	ConverterManager
+
 	Symbol   string
 	orderSig sigchan.Chan
 
@@ -21,7 +30,23 @@ type TradeCollector struct {
 	tradeC     chan types.Trade
 	position   *types.Position
 	orderStore *OrderStore
-	doneTrades map[types.TradeKey]struct{}
+
+	// doneTrades stores the keys of the processed trades with the trade time for deduplication
+	doneTrades map[types.TradeKey]time.Time
+
+	// doneTradeCutoff is the cutoff time of the pruned done trades,
+	// the trades before the cutoff can not be deduplicated anymore, so they are dropped as stale trades
+	doneTradeCutoff time.Time
+
+	// nextDoneTradesPruneSize is the size of the done trades that triggers the next pruning,
+	// it's raised when the pruning can't shrink the map, so that the map is not scanned on every trade
+	nextDoneTradesPruneSize int
+
+	// reorderWindow buffers the queued trades for the window, so that the trades arrived
+	// out of order are added to the position in the trade time order
+	reorderWindow  time.Duration
+	pendingTrades  []types.Trade
+	pendingSinceAt time.Time
 
 	mu sync.Mutex
 
@@ -43,9 +68,11 @@ func NewTradeCollector(symbol string, position *types.Position, orderStore *Orde
 
 		tradeC:     make(chan types.Trade, 100),
 		tradeStore: tradeStore,
-		doneTrades: make(map[types.TradeKey]struct{}),
+		doneTrades: make(map[types.TradeKey]time.Time),
 		position:   position,
 		orderStore: orderStore,
+
+		nextDoneTradesPruneSize: MaximumDoneTrades,
 	}
 }
 
@@ -67,6 +94,14 @@ func (c *TradeCollector) SetPosition(position *types.Position) {
 	c.position = position
 }
 
+// SetReorderWindow sets the window of buffering the queued trades in the background goroutine,
+// the buffered trades are sorted by the trade time before they are processed. Zero disables the buffering.
+func (c *TradeCollector) SetReorderWindow(window time.Duration) {
+	c.mu.Lock()
+	c.reorderWindow = window
+	c.mu.Unlock()
+}
+
 // QueueTrade sends the trade object to the trade channel,
 // so that the goroutine can receive the trade and process in the background.
 func (c *TradeCollector) QueueTrade(trade types.Trade) {
@@ -139,17 +174,15 @@ func (c *TradeCollector) Process() bool {
 	// if it's already done, remove the trade from the trade store
 	c.mu.Lock()
 	c.tradeStore.Filter(func(trade types.Trade) bool {
-		key := trade.Key()
-
 		// remove done trades
-		if _, done := c.doneTrades[key]; done {
+		if c.isDuplicatedTrade(trade) {
 			return true
 		}
 
 		// if it's the trade we're looking for, add it to the list and mark it as done
 		if c.orderStore.Exists(trade.OrderID) {
 			trades = append(trades, trade)
-			c.doneTrades[key] = struct{}{}
+			c.markTradeDone(trade)
 			return true
 		}
 
@@ -157,7 +190,12 @@ func (c *TradeCollector) Process() bool {
 	})
 	c.mu.Unlock()
 
+	// the trades in the trade store are not ordered, add them to the position in the trade time order
+	sortTrades(trades)
+
 	for _, trade := range trades {
+		trade = c.ConvertTrade(trade)
+
 		var p types.Profit
 		if c.position != nil {
 			profit, netProfit, madeProfit := c.position.AddTrade(trade)
@@ -188,12 +226,10 @@ func (c *TradeCollector) Process() bool {
 // return true when the given trade is added
 // return false when the given trade is not added
 func (c *TradeCollector) processTrade(trade types.Trade) bool {
-	key := trade.Key()
-
 	c.mu.Lock()
 
 	// if it's already done, remove the trade from the trade store
-	if _, done := c.doneTrades[key]; done {
+	if c.isDuplicatedTrade(trade) {
 		c.mu.Unlock()
 		return false
 	}
@@ -206,9 +242,11 @@ func (c *TradeCollector) processTrade(trade types.Trade) bool {
 		return false
 	}
 
-	c.doneTrades[key] = struct{}{}
+	c.markTradeDone(trade)
 	c.mu.Unlock()
 
+	trade = c.ConvertTrade(trade)
+
 	if c.position != nil {
 		profit, netProfit, madeProfit := c.position.AddTrade(trade)
 		if madeProfit {
@@ -227,6 +265,89 @@ func (c *TradeCollector) processTrade(trade types.Trade) bool {
 	return true
 }
 
+// isDuplicatedTrade checks if the trade is already processed, it must be called with the lock held
+func (c *TradeCollector) isDuplicatedTrade(trade types.Trade) bool {
+	if _, done := c.doneTrades[trade.Key()]; done {
+		metricsTradeCollectorDroppedTrades.WithLabelValues(c.Symbol, "duplicated").Inc()
+		return true
+	}
+
+	if !c.doneTradeCutoff.IsZero() && trade.Time.Before(c.doneTradeCutoff) {
+		logrus.Warnf("dropping the stale trade before the cutoff time %s: %s", c.doneTradeCutoff, trade.String())
+		metricsTradeCollectorDroppedTrades.WithLabelValues(c.Symbol, "stale").Inc()
+		return true
+	}
+
+	return false
+}
+
+// markTradeDone marks the trade as processed, it must be called with the lock held
+func (c *TradeCollector) markTradeDone(trade types.Trade) {
+	c.doneTrades[trade.Key()] = trade.Time.Time()
+
+	if len(c.doneTrades) > c.nextDoneTradesPruneSize {
+		c.pruneDoneTrades(trade.Time.Time().Add(-TradeExpiryTime))
+
+		// when most of the keys are still in the expiry window, defer the next pruning
+		// until another half of the maximum size is added, the pruning cost is amortized over the trades
+		c.nextDoneTradesPruneSize = max(MaximumDoneTrades, len(c.doneTrades)+MaximumDoneTrades/2)
+	}
+}
+
+func (c *TradeCollector) pruneDoneTrades(cutoffTime time.Time) {
+	for key, tradeTime := range c.doneTrades {
+		if tradeTime.Before(cutoffTime) {
+			delete(c.doneTrades, key)
+		}
+	}
+
+	if cutoffTime.After(c.doneTradeCutoff) {
+		c.doneTradeCutoff = cutoffTime
+	}
+}
+
+// bufferTrade adds the queued trade to the reorder buffer, the buffer is flushed after the reorder window
+func (c *TradeCollector) bufferTrade(trade types.Trade, now time.Time) {
+	c.mu.Lock()
+	if len(c.pendingTrades) == 0 {
+		c.pendingSinceAt = now
+	}
+	c.pendingTrades = append(c.pendingTrades, trade)
+	c.mu.Unlock()
+
+	c.flushPendingTrades(now)
+}
+
+// flushPendingTrades processes the buffered trades in the trade time order
+// when the oldest buffered trade has been waiting for the reorder window
+func (c *TradeCollector) flushPendingTrades(now time.Time) {
+	c.mu.Lock()
+	if len(c.pendingTrades) == 0 || now.Sub(c.pendingSinceAt) < c.reorderWindow {
+		c.mu.Unlock()
+		return
+	}
+
+	trades := c.pendingTrades
+	c.pendingTrades = nil
+	c.mu.Unlock()
+
+	sortTrades(trades)
+	for _, trade := range trades {
+		c.processTrade(trade)
+	}
+}
+
+// sortTrades sorts the trades by the trade time and the trade ID
+func sortTrades(trades []types.Trade) {
+	sort.SliceStable(trades, func(i, j int) bool {
+		ti, tj := trades[i].Time.Time(), trades[j].Time.Time()
+		if !ti.Equal(tj) {
+			return ti.Before(tj)
+		}
+		return trades[i].ID < trades[j].ID
+	})
+}
+
 // return true when the given trade is added
 // return false when the given trade is not added
 func (c *TradeCollector) ProcessTrade(trade types.Trade) bool {
@@ -237,6 +358,19 @@ func (c *TradeCollector) ProcessTrade(trade types.Trade) bool {
 // Do not use this function if you need back-testing
 func (c *TradeCollector) Run(ctx context.Context) {
 	var ticker = time.NewTicker(3 * time.Second)
+	defer ticker.Stop()
+
+	c.mu.Lock()
+	reorderWindow := c.reorderWindow
+	c.mu.Unlock()
+
+	var flushC <-chan time.Time
+	if reorderWindow > 0 {
+		flushTicker := time.NewTicker(reorderWindow)
+		defer flushTicker.Stop()
+		flushC = flushTicker.C
+	}
+
 	for {
 		select {
 		case <-ctx.Done():
@@ -248,8 +382,15 @@ func (c *TradeCollector) Run(ctx context.Context) {
 		case <-c.orderSig:
 			c.Process()
 
+		case now := <-flushC:
+			c.flushPendingTrades(now)
+
 		case trade := <-c.tradeC:
-			c.processTrade(trade)
+			if reorderWindow > 0 {
+				c.bufferTrade(trade, time.Now())
+			} else {
+				c.processTrade(trade)
+			}
 		}
 	}
 }
@@ -2,6 +2,7 @@ package core
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -63,3 +64,113 @@ func TestTradeCollector_ShouldNotCountDuplicatedTrade(t *testing.T) {
 	assert.False(t, matched, "the same trade should not match")
 	assert.Equal(t, 0, len(collector.tradeStore.Trades()), "the same trade should not be added to the trade store")
 }
+
+func newTestCollectorTrade(id uint64, side types.SideType, price float64, tradeTime time.Time) types.Trade {
+	return types.Trade{
+		ID:            id,
+		OrderID:       399,
+		Exchange:      types.ExchangeBinance,
+		Price:         fixedpoint.NewFromFloat(price),
+		Quantity:      fixedpoint.One,
+		QuoteQuantity: fixedpoint.NewFromFloat(price),
+		Symbol:        "BTCUSDT",
+		Side:          side,
+		Time:          types.Time(tradeTime),
+	}
+}
+
+func TestTradeCollector_ProcessInTradeTimeOrder(t *testing.T) {
+	symbol := "BTCUSDT"
+	position := types.NewPosition(symbol, "BTC", "USDT")
+	orderStore := NewOrderStore(symbol)
+	collector := NewTradeCollector(symbol, position, orderStore)
+
+	var tradeIDs []uint64
+	collector.OnTrade(func(trade types.Trade, profit, netProfit fixedpoint.Value) {
+		tradeIDs = append(tradeIDs, trade.ID)
+	})
+
+	now := time.Now()
+	for i := 5; i >= 1; i-- {
+		collector.RecoverTrade(newTestCollectorTrade(uint64(i), types.SideTypeBuy, 40000, now.Add(time.Duration(i)*time.Second)))
+	}
+
+	orderStore.Add(types.Order{OrderID: 399, Status: types.OrderStatusFilled})
+	assert.True(t, collector.Process())
+	assert.Equal(t, []uint64{1, 2, 3, 4, 5}, tradeIDs)
+}
+
+func TestTradeCollector_ReorderWindow(t *testing.T) {
+	symbol := "BTCUSDT"
+	position := types.NewPosition(symbol, "BTC", "USDT")
+	orderStore := NewOrderStore(symbol)
+	orderStore.Add(types.Order{OrderID: 399, Status: types.OrderStatusFilled})
+	collector := NewTradeCollector(symbol, position, orderStore)
+	collector.SetReorderWindow(time.Second)
+
+	var tradeIDs []uint64
+	collector.OnTrade(func(trade types.Trade, profit, netProfit fixedpoint.Value) {
+		tradeIDs = append(tradeIDs, trade.ID)
+	})
+
+	now := time.Now()
+	collector.bufferTrade(newTestCollectorTrade(2, types.SideTypeBuy, 40000, now.Add(2*time.Millisecond)), now)
+	collector.bufferTrade(newTestCollectorTrade(1, types.SideTypeBuy, 40000, now.Add(time.Millisecond)), now)
+	// the duplicated trade from the rest api recovery
+	collector.bufferTrade(newTestCollectorTrade(2, types.SideTypeBuy, 40000, now.Add(2*time.Millisecond)), now)
+	assert.Empty(t, tradeIDs, "the trades should be buffered in the reorder window")
+
+	collector.flushPendingTrades(now.Add(time.Second))
+	assert.Equal(t, []uint64{1, 2}, tradeIDs)
+	assert.Equal(t, "2", position.GetBase().String())
+}
+
+func TestTradeCollector_PruneDoneTrades(t *testing.T) {
+	symbol := "BTCUSDT"
+	position := types.NewPosition(symbol, "BTC", "USDT")
+	orderStore := NewOrderStore(symbol)
+	orderStore.Add(types.Order{OrderID: 399, Status: types.OrderStatusFilled})
+	collector := NewTradeCollector(symbol, position, orderStore)
+
+	now := time.Now()
+	assert.True(t, collector.ProcessTrade(newTestCollectorTrade(1, types.SideTypeBuy, 40000, now.Add(-4*time.Hour))))
+	assert.True(t, collector.ProcessTrade(newTestCollectorTrade(2, types.SideTypeBuy, 40000, now)))
+
+	collector.mu.Lock()
+	collector.pruneDoneTrades(now.Add(-TradeExpiryTime))
+	collector.mu.Unlock()
+
+	// the pruned trade can not be deduplicated by the key, it's dropped by the cutoff time
+	assert.False(t, collector.ProcessTrade(newTestCollectorTrade(1, types.SideTypeBuy, 40000, now.Add(-4*time.Hour))))
+	assert.False(t, collector.ProcessTrade(newTestCollectorTrade(2, types.SideTypeBuy, 40000, now)))
+	assert.Equal(t, "2", position.GetBase().String())
+}
+
+func TestTradeCollector_PruneDoneTrades_FullWindow(t *testing.T) {
+	symbol := "BTCUSDT"
+	position := types.NewPosition(symbol, "BTC", "USDT")
+	orderStore := NewOrderStore(symbol)
+	collector := NewTradeCollector(symbol, position, orderStore)
+
+	now := time.Now()
+	// all the done trades are inside the expiry window, so the pruning can't remove any of them
+	for i := 0; i <= MaximumDoneTrades; i++ {
+		collector.markTradeDone(newTestCollectorTrade(uint64(i+1), types.SideTypeBuy, 40000, now.Add(time.Duration(i)*time.Millisecond)))
+	}
+
+	assert.Len(t, collector.doneTrades, MaximumDoneTrades+1)
+	assert.Equal(t, MaximumDoneTrades+1+MaximumDoneTrades/2, collector.nextDoneTradesPruneSize)
+	cutoff := collector.doneTradeCutoff
+
+	// the following trades don't trigger the pruning until the map grows by another half of the maximum size
+	for i := 0; i < MaximumDoneTrades/2; i++ {
+		collector.markTradeDone(newTestCollectorTrade(uint64(MaximumDoneTrades+i+2), types.SideTypeBuy, 40000, now.Add(time.Hour)))
+	}
+
+	assert.Equal(t, cutoff, collector.doneTradeCutoff)
+
+	// the trades move the cutoff past the earlier trades, so they are pruned
+	collector.markTradeDone(newTestCollectorTrade(uint64(2*MaximumDoneTrades), types.SideTypeBuy, 40000, now.Add(TradeExpiryTime+time.Hour)))
+	assert.Len(t, collector.doneTrades, MaximumDoneTrades/2+1)
+	assert.Equal(t, MaximumDoneTrades+1, collector.nextDoneTradesPruneSize)
+}
@@ -0,0 +1,112 @@
+package parquet
+
+import (
+	"encoding/binary"
+)
+
+// the thrift compact protocol types
+const (
+	thriftTypeI32    = 5
+	thriftTypeI64    = 6
+	thriftTypeBinary = 8
+	thriftTypeList   = 9
+	thriftTypeStruct = 12
+)
+
+// thriftEncoder encodes the parquet metadata in the thrift compact protocol
+type thriftEncoder struct {
+	buf []byte
+
+	// lastFieldIDs is the stack of the last field id of the nested structs
+	lastFieldIDs []int16
+}
+
+func (e *thriftEncoder) Bytes() []byte {
+	return e.buf
+}
+
+func (e *thriftEncoder) writeUvarint(v uint64) {
+	e.buf = binary.AppendUvarint(e.buf, v)
+}
+
+func (e *thriftEncoder) writeVarint(v int64) {
+	// zigzag encoding
+	e.writeUvarint(uint64((v << 1) ^ (v >> 63)))
+}
+
+func (e *thriftEncoder) fieldHeader(id int16, typ byte) {
+	last := e.lastFieldIDs[len(e.lastFieldIDs)-1]
+	if delta := id - last; delta > 0 && delta <= 15 {
+		e.buf = append(e.buf, byte(delta)<<4|typ)
+	} else {
+		e.buf = append(e.buf, typ)
+		e.writeVarint(int64(id))
+	}
+
+	e.lastFieldIDs[len(e.lastFieldIDs)-1] = id
+}
+
+func (e *thriftEncoder) listHeader(size int, elemType byte) {
+	if size < 15 {
+		e.buf = append(e.buf, byte(size)<<4|elemType)
+		return
+	}
+
+	e.buf = append(e.buf, 0xf0|elemType)
+	e.writeUvarint(uint64(size))
+}
+
+// StructBegin begins the top-level struct or the struct element of a list
+func (e *thriftEncoder) StructBegin() {
+	e.lastFieldIDs = append(e.lastFieldIDs, 0)
+}
+
+func (e *thriftEncoder) StructEnd() {
+	e.buf = append(e.buf, 0)
+	e.lastFieldIDs = e.lastFieldIDs[:len(e.lastFieldIDs)-1]
+}
+
+func (e *thriftEncoder) FieldStruct(id int16) {
+	e.fieldHeader(id, thriftTypeStruct)
+	e.StructBegin()
+}
+
+func (e *thriftEncoder) FieldI32(id int16, v int32) {
+	e.fieldHeader(id, thriftTypeI32)
+	e.writeVarint(int64(v))
+}
+
+func (e *thriftEncoder) FieldI64(id int16, v int64) {
+	e.fieldHeader(id, thriftTypeI64)
+	e.writeVarint(v)
+}
+
+func (e *thriftEncoder) FieldString(id int16, v string) {
+	e.fieldHeader(id, thriftTypeBinary)
+	e.writeUvarint(uint64(len(v)))
+	e.buf = append(e.buf, v...)
+}
+
+func (e *thriftEncoder) FieldListI32(id int16, vs ...int32) {
+	e.fieldHeader(id, thriftTypeList)
+	e.listHeader(len(vs), thriftTypeI32)
+	for _, v := range vs {
+		e.writeVarint(int64(v))
+	}
+}
+
+func (e *thriftEncoder) FieldListString(id int16, vs ...string) {
+	e.fieldHeader(id, thriftTypeList)
+	e.listHeader(len(vs), thriftTypeBinary)
+	for _, v := range vs {
+		e.writeUvarint(uint64(len(v)))
+		e.buf = append(e.buf, v...)
+	}
+}
+
+// FieldListStruct writes the list header of n struct elements,
+// each element is written between StructBegin and StructEnd
+func (e *thriftEncoder) FieldListStruct(id int16, n int) {
+	e.fieldHeader(id, thriftTypeList)
+	e.listHeader(n, thriftTypeStruct)
+}
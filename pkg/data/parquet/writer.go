@@ -0,0 +1,368 @@
+// Package parquet implements a minimal parquet file writer for exporting the flat records,
+// the columns are required and PLAIN encoded without compression, which can be loaded by pandas and pyarrow.
+package parquet
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"time"
+)
+
+const magic = "PAR1"
+
+const DefaultRowGroupSize = 10000
+
+type ColumnType int
+
+const (
+	ColumnTypeBool ColumnType = iota
+	ColumnTypeInt64
+	ColumnTypeDouble
+	ColumnTypeString
+
+	// ColumnTypeTimestamp stores time.Time as the INT64 milliseconds since the epoch
+	ColumnTypeTimestamp
+)
+
+// the parquet physical types, repetition types, converted types and encodings
+const (
+	typeBoolean   = 0
+	typeInt64     = 2
+	typeDouble    = 5
+	typeByteArray = 6
+
+	repetitionRequired = 0
+
+	convertedTypeUTF8            = 0
+	convertedTypeTimestampMillis = 9
+
+	encodingPlain = 0
+	codecNone     = 0
+	pageTypeData  = 0
+	encodingRLE   = 3
+)
+
+func (t ColumnType) physicalType() int32 {
+	switch t {
+	case ColumnTypeBool:
+		return typeBoolean
+	case ColumnTypeDouble:
+		return typeDouble
+	case ColumnTypeString:
+		return typeByteArray
+	default:
+		return typeInt64
+	}
+}
+
+type Column struct {
+	Name string
+	Type ColumnType
+}
+
+type columnBuffer struct {
+	Column
+
+	data  []byte
+	bools []bool
+}
+
+func (b *columnBuffer) append(v interface{}) error {
+	switch b.Type {
+	case ColumnTypeBool:
+		x, ok := v.(bool)
+		if !ok {
+			return fmt.Errorf("column %s expects bool, got %T", b.Name, v)
+		}
+		b.bools = append(b.bools, x)
+
+	case ColumnTypeInt64:
+		var x int64
+		switch vv := v.(type) {
+		case int64:
+			x = vv
+		case int:
+			x = int64(vv)
+		case uint64:
+			x = int64(vv)
+		default:
+			return fmt.Errorf("column %s expects int64, got %T", b.Name, v)
+		}
+		b.data = binary.LittleEndian.AppendUint64(b.data, uint64(x))
+
+	case ColumnTypeDouble:
+		x, ok := v.(float64)
+		if !ok {
+			return fmt.Errorf("column %s expects float64, got %T", b.Name, v)
+		}
+		b.data = binary.LittleEndian.AppendUint64(b.data, math.Float64bits(x))
+
+	case ColumnTypeString:
+		x, ok := v.(string)
+		if !ok {
+			return fmt.Errorf("column %s expects string, got %T", b.Name, v)
+		}
+		b.data = binary.LittleEndian.AppendUint32(b.data, uint32(len(x)))
+		b.data = append(b.data, x...)
+
+	case ColumnTypeTimestamp:
+		x, ok := v.(time.Time)
+		if !ok {
+			return fmt.Errorf("column %s expects time.Time, got %T", b.Name, v)
+		}
+		b.data = binary.LittleEndian.AppendUint64(b.data, uint64(x.UnixMilli()))
+	}
+
+	return nil
+}
+
+// page returns the PLAIN encoded page data, the booleans are bit-packed with the LSB first
+func (b *columnBuffer) page() []byte {
+	if b.Type != ColumnTypeBool {
+		return b.data
+	}
+
+	data := make([]byte, (len(b.bools)+7)/8)
+	for i, v := range b.bools {
+		if v {
+			data[i/8] |= 1 << (i % 8)
+		}
+	}
+
+	return data
+}
+
+func (b *columnBuffer) reset() {
+	b.data = b.data[:0]
+	b.bools = b.bools[:0]
+}
+
+type columnChunk struct {
+	column           Column
+	numValues        int64
+	dataPageOffset   int64
+	totalSize        int64
+	uncompressedSize int64
+}
+
+type rowGroup struct {
+	chunks        []columnChunk
+	numRows       int64
+	totalByteSize int64
+}
+
+// Writer buffers the rows and writes them as the row groups of the parquet file,
+// the file is only readable after Close writes the file footer.
+type Writer struct {
+	file   io.WriteCloser
+	writer *bufio.Writer
+	offset int64
+
+	columns []*columnBuffer
+	numRows int64
+
+	rowGroups []rowGroup
+
+	// RowGroupSize is the number of the buffered rows that triggers writing a row group
+	RowGroupSize int
+}
+
+func NewWriterFile(filename string, columns []Column) (*Writer, error) {
+	f, err := os.Create(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewWriter(f, columns)
+}
+
+func NewWriter(file io.WriteCloser, columns []Column) (*Writer, error) {
+	w := &Writer{
+		file:         file,
+		writer:       bufio.NewWriter(file),
+		RowGroupSize: DefaultRowGroupSize,
+	}
+
+	for _, c := range columns {
+		w.columns = append(w.columns, &columnBuffer{Column: c})
+	}
+
+	if err := w.write([]byte(magic)); err != nil {
+		return nil, err
+	}
+
+	return w, nil
+}
+
+func (w *Writer) write(data []byte) error {
+	n, err := w.writer.Write(data)
+	w.offset += int64(n)
+	return err
+}
+
+// Write appends a row, the values are in the order of the columns
+func (w *Writer) Write(values ...interface{}) error {
+	if len(values) != len(w.columns) {
+		return fmt.Errorf("expect %d values, got %d", len(w.columns), len(values))
+	}
+
+	for i, v := range values {
+		if err := w.columns[i].append(v); err != nil {
+			return err
+		}
+	}
+
+	w.numRows++
+	if w.RowGroupSize > 0 && w.numRows >= int64(w.RowGroupSize) {
+		return w.Flush()
+	}
+
+	return nil
+}
+
+// Flush writes the buffered rows as a row group
+func (w *Writer) Flush() error {
+	if w.numRows == 0 {
+		return nil
+	}
+
+	group := rowGroup{numRows: w.numRows}
+	for _, c := range w.columns {
+		data := c.page()
+
+		var e thriftEncoder
+		e.StructBegin()
+		e.FieldI32(1, pageTypeData)
+		e.FieldI32(2, int32(len(data)))
+		e.FieldI32(3, int32(len(data)))
+		e.FieldStruct(5)
+		e.FieldI32(1, int32(w.numRows))
+		e.FieldI32(2, encodingPlain)
+		e.FieldI32(3, encodingRLE)
+		e.FieldI32(4, encodingRLE)
+		e.StructEnd()
+		e.StructEnd()
+		header := e.Bytes()
+
+		chunk := columnChunk{
+			column:           c.Column,
+			numValues:        w.numRows,
+			dataPageOffset:   w.offset,
+			totalSize:        int64(len(header) + len(data)),
+			uncompressedSize: int64(len(header) + len(data)),
+		}
+
+		if err := w.write(header); err != nil {
+			return err
+		}
+
+		if err := w.write(data); err != nil {
+			return err
+		}
+
+		group.chunks = append(group.chunks, chunk)
+		group.totalByteSize += chunk.uncompressedSize
+		c.reset()
+	}
+
+	w.rowGroups = append(w.rowGroups, group)
+	w.numRows = 0
+	return nil
+}
+
+func (w *Writer) footer() []byte {
+	var totalRows int64
+	for _, g := range w.rowGroups {
+		totalRows += g.numRows
+	}
+
+	var e thriftEncoder
+	e.StructBegin()
+	e.FieldI32(1, 1)
+
+	// the schema is flattened, the root element is followed by the columns
+	e.FieldListStruct(2, len(w.columns)+1)
+	e.StructBegin()
+	e.FieldString(4, "schema")
+	e.FieldI32(5, int32(len(w.columns)))
+	e.StructEnd()
+
+	for _, c := range w.columns {
+		e.StructBegin()
+		e.FieldI32(1, c.Type.physicalType())
+		e.FieldI32(3, repetitionRequired)
+		e.FieldString(4, c.Name)
+		switch c.Type {
+		case ColumnTypeString:
+			e.FieldI32(6, convertedTypeUTF8)
+		case ColumnTypeTimestamp:
+			e.FieldI32(6, convertedTypeTimestampMillis)
+		}
+		e.StructEnd()
+	}
+
+	e.FieldI64(3, totalRows)
+
+	e.FieldListStruct(4, len(w.rowGroups))
+	for _, g := range w.rowGroups {
+		e.StructBegin()
+		e.FieldListStruct(1, len(g.chunks))
+		for _, chunk := range g.chunks {
+			e.StructBegin()
+			e.FieldI64(2, chunk.dataPageOffset)
+			e.FieldStruct(3)
+			e.FieldI32(1, chunk.column.Type.physicalType())
+			e.FieldListI32(2, encodingPlain, encodingRLE)
+			e.FieldListString(3, chunk.column.Name)
+			e.FieldI32(4, codecNone)
+			e.FieldI64(5, chunk.numValues)
+			e.FieldI64(6, chunk.uncompressedSize)
+			e.FieldI64(7, chunk.totalSize)
+			e.FieldI64(9, chunk.dataPageOffset)
+			e.StructEnd()
+			e.StructEnd()
+		}
+		e.FieldI64(2, g.totalByteSize)
+		e.FieldI64(3, g.numRows)
+		e.StructEnd()
+	}
+
+	e.FieldString(6, "bbgo")
+	e.StructEnd()
+	return e.Bytes()
+}
+
+// Close flushes the buffered rows, writes the file footer and closes the file
+func (w *Writer) Close() error {
+	if err := w.Flush(); err != nil {
+		_ = w.file.Close()
+		return err
+	}
+
+	footer := w.footer()
+	if err := w.write(footer); err != nil {
+		_ = w.file.Close()
+		return err
+	}
+
+	if err := w.write(binary.LittleEndian.AppendUint32(nil, uint32(len(footer)))); err != nil {
+		_ = w.file.Close()
+		return err
+	}
+
+	if err := w.write([]byte(magic)); err != nil {
+		_ = w.file.Close()
+		return err
+	}
+
+	if err := w.writer.Flush(); err != nil {
+		_ = w.file.Close()
+		return err
+	}
+
+	return w.file.Close()
+}
@@ -0,0 +1,76 @@
+package parquet
+
+import (
+	"encoding/binary"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_thriftEncoder(t *testing.T) {
+	var e thriftEncoder
+	e.StructBegin()
+	e.FieldI32(1, 3)
+	e.FieldString(4, "ab")
+	e.FieldI64(20, -1)
+	e.StructEnd()
+
+	assert.Equal(t, []byte{
+		0x15, 0x06, // field 1, i32, zigzag(3)
+		0x38, 0x02, 'a', 'b', // field 4 (delta 3), binary
+		0x06, 0x28, 0x01, // field 20 (delta 16, long form), i64, zigzag(-1)
+		0x00,
+	}, e.Bytes())
+}
+
+func TestWriter(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "test.parquet")
+	w, err := NewWriterFile(filename, []Column{
+		{Name: "time", Type: ColumnTypeTimestamp},
+		{Name: "price", Type: ColumnTypeDouble},
+		{Name: "count", Type: ColumnTypeInt64},
+		{Name: "tag", Type: ColumnTypeString},
+		{Name: "ok", Type: ColumnTypeBool},
+	})
+	require.NoError(t, err)
+	w.RowGroupSize = 2
+
+	now := time.UnixMilli(1700000000000)
+	require.NoError(t, w.Write(now, 10.5, 1, "a", true))
+	require.NoError(t, w.Write(now.Add(time.Second), 11.5, int64(2), "bc", false))
+	require.NoError(t, w.Write(now.Add(2*time.Second), 12.5, 3, "", true))
+
+	assert.Error(t, w.Write(now, 1.0, 1, "a"), "the number of values does not match")
+	assert.Error(t, w.Write(now, "1.0", 1, "a", true), "the value type does not match")
+	require.NoError(t, w.Close())
+
+	data, err := os.ReadFile(filename)
+	require.NoError(t, err)
+
+	assert.Equal(t, magic, string(data[:4]))
+	assert.Equal(t, magic, string(data[len(data)-4:]))
+
+	footerLen := int(binary.LittleEndian.Uint32(data[len(data)-8:]))
+	footer := data[len(data)-8-footerLen : len(data)-8]
+	assert.Contains(t, string(footer), "bbgo")
+	assert.Contains(t, string(footer), "price")
+
+	if assert.Len(t, w.rowGroups, 2) {
+		assert.Equal(t, int64(2), w.rowGroups[0].numRows)
+		assert.Equal(t, int64(1), w.rowGroups[1].numRows)
+
+		// the page data follows the page header
+		chunk := w.rowGroups[0].chunks[1]
+		page := data[chunk.dataPageOffset+chunk.totalSize-16 : chunk.dataPageOffset+chunk.totalSize]
+		assert.Equal(t, 10.5, math.Float64frombits(binary.LittleEndian.Uint64(page[:8])))
+		assert.Equal(t, 11.5, math.Float64frombits(binary.LittleEndian.Uint64(page[8:])))
+
+		chunk = w.rowGroups[0].chunks[4]
+		assert.Equal(t, byte(0x01), data[chunk.dataPageOffset+chunk.totalSize-1])
+	}
+}
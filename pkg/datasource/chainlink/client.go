@@ -0,0 +1,177 @@
+package chainlink
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/c9s/requestgen"
+)
+
+const defaultHTTPTimeout = time.Second * 15
+
+// the function selectors of the AggregatorV3Interface
+const (
+	selectorDecimals        = "0x313ce567"
+	selectorLatestRoundData = "0xfeaf968c"
+)
+
+// Client reads the Chainlink price feeds through the JSON-RPC API of an EVM node
+type Client struct {
+	requestgen.BaseAPIClient
+
+	requestID atomic.Int64
+}
+
+func NewClient(rpcURL string) (*Client, error) {
+	u, err := url.Parse(rpcURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Client{
+		BaseAPIClient: requestgen.BaseAPIClient{
+			BaseURL: u,
+			HttpClient: &http.Client{
+				Timeout: defaultHTTPTimeout,
+			},
+		},
+	}, nil
+}
+
+type rpcRequest struct {
+	JSONRPC string        `json:"jsonrpc"`
+	ID      int64         `json:"id"`
+	Method  string        `json:"method"`
+	Params  []interface{} `json:"params"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type rpcResponse struct {
+	ID     int64     `json:"id"`
+	Result string    `json:"result"`
+	Error  *rpcError `json:"error,omitempty"`
+}
+
+type callMessage struct {
+	To   string `json:"to"`
+	Data string `json:"data"`
+}
+
+// call calls the read-only contract function at the latest block and returns the decoded return data
+func (c *Client) call(ctx context.Context, address, selector string) ([]byte, error) {
+	payload := rpcRequest{
+		JSONRPC: "2.0",
+		ID:      c.requestID.Add(1),
+		Method:  "eth_call",
+		Params:  []interface{}{callMessage{To: address, Data: selector}, "latest"},
+	}
+
+	req, err := c.NewRequest(ctx, "POST", "", nil, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.SendRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var rpcResp rpcResponse
+	if err := resp.DecodeJSON(&rpcResp); err != nil {
+		return nil, fmt.Errorf("unable to decode the eth_call response: %w", err)
+	}
+
+	if rpcResp.Error != nil {
+		return nil, fmt.Errorf("eth_call error %d: %s", rpcResp.Error.Code, rpcResp.Error.Message)
+	}
+
+	return hex.DecodeString(strings.TrimPrefix(rpcResp.Result, "0x"))
+}
+
+// Decimals returns the decimals of the answer of the price feed
+func (c *Client) Decimals(ctx context.Context, address string) (int, error) {
+	data, err := c.call(ctx, address, selectorDecimals)
+	if err != nil {
+		return 0, err
+	}
+
+	words, err := decodeWords(data, 1)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(words[0].Int64()), nil
+}
+
+// RoundData is the latest round of the price feed, the answer is scaled by the decimals of the feed
+type RoundData struct {
+	RoundID         *big.Int
+	Answer          *big.Int
+	StartedAt       time.Time
+	UpdatedAt       time.Time
+	AnsweredInRound *big.Int
+}
+
+// LatestRoundData returns the latest round of the price feed
+func (c *Client) LatestRoundData(ctx context.Context, address string) (*RoundData, error) {
+	data, err := c.call(ctx, address, selectorLatestRoundData)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeRoundData(data)
+}
+
+func decodeRoundData(data []byte) (*RoundData, error) {
+	words, err := decodeWords(data, 5)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RoundData{
+		RoundID:         words[0],
+		Answer:          toSigned(words[1]),
+		StartedAt:       time.Unix(words[2].Int64(), 0),
+		UpdatedAt:       time.Unix(words[3].Int64(), 0),
+		AnsweredInRound: words[4],
+	}, nil
+}
+
+// decodeWords decodes the ABI encoded static return values into the 32-byte words
+func decodeWords(data []byte, n int) ([]*big.Int, error) {
+	if len(data) < n*32 {
+		return nil, fmt.Errorf("unexpected return data length %d, expecting %d words", len(data), n)
+	}
+
+	words := make([]*big.Int, n)
+	for i := range words {
+		words[i] = new(big.Int).SetBytes(data[i*32 : (i+1)*32])
+	}
+
+	return words, nil
+}
+
+var twoTo255 = new(big.Int).Lsh(big.NewInt(1), 255)
+var twoTo256 = new(big.Int).Lsh(big.NewInt(1), 256)
+
+// toSigned converts the two's complement int256 word into the signed integer
+func toSigned(word *big.Int) *big.Int {
+	if word.Cmp(twoTo255) >= 0 {
+		return new(big.Int).Sub(word, twoTo256)
+	}
+
+	return word
+}
@@ -0,0 +1,40 @@
+package chainlink
+
+import (
+	"encoding/hex"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_decodeRoundData(t *testing.T) {
+	word := func(v int64) string {
+		b := make([]byte, 32)
+		new(big.Int).SetInt64(v).FillBytes(b)
+		return hex.EncodeToString(b)
+	}
+
+	data, err := hex.DecodeString(strings.Join([]string{
+		word(10),
+		word(6512345000000),
+		word(1700000000),
+		word(1700000060),
+		word(10),
+	}, ""))
+	assert.NoError(t, err)
+
+	round, err := decodeRoundData(data)
+	if assert.NoError(t, err) {
+		assert.Equal(t, int64(6512345000000), round.Answer.Int64())
+		assert.Equal(t, int64(1700000060), round.UpdatedAt.Unix())
+	}
+
+	_, err = decodeRoundData(data[:64])
+	assert.Error(t, err)
+
+	// the negative answer in two's complement
+	negative := new(big.Int).Sub(twoTo256, big.NewInt(5))
+	assert.Equal(t, int64(-5), toSigned(negative).Int64())
+}
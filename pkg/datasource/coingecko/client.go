@@ -0,0 +1,109 @@
+package coingecko
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/c9s/requestgen"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+)
+
+const (
+	defaultHTTPTimeout = time.Second * 15
+	defaultBaseURL     = "https://api.coingecko.com"
+	proBaseURL         = "https://pro-api.coingecko.com"
+)
+
+// Client is the client of the CoinGecko API, the public API is used without the API key
+type Client struct {
+	requestgen.BaseAPIClient
+
+	apiKey string
+	pro    bool
+}
+
+func NewClient() *Client {
+	u, err := url.Parse(defaultBaseURL)
+	if err != nil {
+		panic(err)
+	}
+
+	return &Client{
+		BaseAPIClient: requestgen.BaseAPIClient{
+			BaseURL: u,
+			HttpClient: &http.Client{
+				Timeout: defaultHTTPTimeout,
+			},
+		},
+	}
+}
+
+// Auth sets the demo API key, or the pro API key when pro is true, the pro API is served on a different host
+func (c *Client) Auth(apiKey string, pro bool) {
+	c.apiKey = apiKey
+	c.pro = pro
+
+	if pro {
+		u, err := url.Parse(proBaseURL)
+		if err != nil {
+			panic(err)
+		}
+
+		c.BaseURL = u
+	}
+}
+
+func (c *Client) newRequest(ctx context.Context, refURL string, params url.Values) (*http.Request, error) {
+	req, err := c.NewRequest(ctx, "GET", refURL, params, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Accept", "application/json")
+
+	if c.apiKey != "" {
+		if c.pro {
+			req.Header.Set("x-cg-pro-api-key", c.apiKey)
+		} else {
+			req.Header.Set("x-cg-demo-api-key", c.apiKey)
+		}
+	}
+
+	return req, nil
+}
+
+// SimplePrices maps the coin id to the prices in the vs currencies, e.g. prices["bitcoin"]["usd"]
+type SimplePrices map[string]map[string]fixedpoint.Value
+
+// QuerySimplePrice queries the prices of the coins in the vs currencies, the coin ids are the CoinGecko API ids,
+// e.g. bitcoin, and the vs currencies are in lower case, e.g. usd
+//
+// https://docs.coingecko.com/reference/simple-price
+func (c *Client) QuerySimplePrice(ctx context.Context, ids []string, vsCurrencies []string) (SimplePrices, error) {
+	params := url.Values{}
+	params.Set("ids", strings.Join(ids, ","))
+	params.Set("vs_currencies", strings.ToLower(strings.Join(vsCurrencies, ",")))
+	params.Set("precision", "full")
+
+	req, err := c.newRequest(ctx, "/api/v3/simple/price", params)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.SendRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var prices SimplePrices
+	if err := resp.DecodeJSON(&prices); err != nil {
+		return nil, fmt.Errorf("unable to decode the simple price response: %w", err)
+	}
+
+	return prices, nil
+}
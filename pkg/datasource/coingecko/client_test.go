@@ -0,0 +1,34 @@
+package coingecko
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_QuerySimplePrice(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v3/simple/price", r.URL.Path)
+		assert.Equal(t, "bitcoin,ethereum", r.URL.Query().Get("ids"))
+		assert.Equal(t, "usd", r.URL.Query().Get("vs_currencies"))
+		assert.Equal(t, "demo-key", r.Header.Get("x-cg-demo-api-key"))
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"bitcoin":{"usd":65123.45},"ethereum":{"usd":3456.7}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient()
+	client.Auth("demo-key", false)
+	client.BaseURL, _ = url.Parse(server.URL)
+
+	prices, err := client.QuerySimplePrice(context.Background(), []string{"bitcoin", "ethereum"}, []string{"USD"})
+	if assert.NoError(t, err) {
+		assert.Equal(t, "65123.45", prices["bitcoin"]["usd"].String())
+		assert.Equal(t, "3456.7", prices["ethereum"]["usd"].String())
+	}
+}
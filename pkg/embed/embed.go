@@ -0,0 +1,238 @@
+// Package embed provides a small and stable API for embedding the bbgo engine in your own binary,
+// so that you can run your own strategies without copying the command wiring.
+//
+// The exported API of this package is kept backward compatible within the same major version of bbgo.
+//
+//	config, err := embed.LoadConfig("bbgo.yaml")
+//	env, err := embed.NewEnvironment(ctx, config)
+//	err = env.AddStrategy("binance", &mystrategy.Strategy{Symbol: "BTCUSDT"})
+//	err = env.Run(ctx)
+package embed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/c9s/bbgo/pkg/bbgo"
+)
+
+const DefaultGracefulShutdownPeriod = 30 * time.Second
+
+var ErrEnvironmentStarted = errors.New("embed: environment is already started")
+
+type Option func(env *Environment)
+
+// WithoutSync disables the trade/order sync on startup
+func WithoutSync() Option {
+	return func(env *Environment) {
+		env.noSync = true
+	}
+}
+
+// WithLightweight bootstraps the environment in the lightweight mode,
+// which skips the database and the notification setup.
+func WithLightweight() Option {
+	return func(env *Environment) {
+		env.lightweight = true
+	}
+}
+
+// WithGracefulShutdownPeriod sets the timeout of the graceful shutdown in Run
+func WithGracefulShutdownPeriod(period time.Duration) Option {
+	return func(env *Environment) {
+		env.gracefulShutdownPeriod = period
+	}
+}
+
+// Environment is the embedded bbgo engine, it owns the exchange sessions and the trader
+type Environment struct {
+	config  *bbgo.Config
+	environ *bbgo.Environment
+	trader  *bbgo.Trader
+
+	noSync                 bool
+	lightweight            bool
+	gracefulShutdownPeriod time.Duration
+
+	mu      sync.Mutex
+	started bool
+}
+
+// LoadConfig loads the config file and the strategies defined in it,
+// the strategy packages must be imported before loading the config.
+func LoadConfig(configFile string) (*bbgo.Config, error) {
+	return bbgo.Load(configFile, true)
+}
+
+// NewEnvironment bootstraps the exchange sessions, the database, the persistence and the notification
+// from the config, and attaches the strategies defined in the config.
+func NewEnvironment(ctx context.Context, config *bbgo.Config, options ...Option) (*Environment, error) {
+	if config == nil {
+		config = &bbgo.Config{}
+	}
+
+	env := &Environment{
+		config:                 config,
+		environ:                bbgo.NewEnvironment(),
+		gracefulShutdownPeriod: DefaultGracefulShutdownPeriod,
+	}
+
+	for _, option := range options {
+		option(env)
+	}
+
+	if env.lightweight {
+		if err := bbgo.BootstrapEnvironmentLightweight(ctx, env.environ, config); err != nil {
+			return nil, err
+		}
+	} else {
+		if err := bbgo.BootstrapEnvironment(ctx, env.environ, config); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := env.environ.Init(ctx); err != nil {
+		return nil, err
+	}
+
+	if !env.noSync {
+		if err := env.environ.Sync(ctx, config); err != nil {
+			return nil, err
+		}
+
+		if config.Sync != nil {
+			env.environ.BindSync(config.Sync)
+		}
+	}
+
+	env.trader = bbgo.NewTrader(env.environ)
+	if err := env.trader.Configure(config); err != nil {
+		return nil, err
+	}
+
+	return env, nil
+}
+
+// Config returns the config of the environment
+func (e *Environment) Config() *bbgo.Config {
+	return e.config
+}
+
+// Environ returns the underlying bbgo environment
+func (e *Environment) Environ() *bbgo.Environment {
+	return e.environ
+}
+
+// Trader returns the underlying bbgo trader
+func (e *Environment) Trader() *bbgo.Trader {
+	return e.trader
+}
+
+// AddStrategy attaches a single exchange strategy on the given session,
+// it must be called before Start or Run.
+func (e *Environment) AddStrategy(session string, strategy bbgo.SingleExchangeStrategy) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.started {
+		return ErrEnvironmentStarted
+	}
+
+	return e.trader.AttachStrategyOn(session, strategy)
+}
+
+// AddCrossExchangeStrategy attaches a cross exchange strategy,
+// it must be called before Start or Run.
+func (e *Environment) AddCrossExchangeStrategy(strategy bbgo.CrossExchangeStrategy) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.started {
+		return ErrEnvironmentStarted
+	}
+
+	e.trader.AttachCrossExchangeStrategy(strategy)
+	return nil
+}
+
+// Start initializes the strategies, loads the persisted states and starts the trading,
+// it returns after the strategies are started.
+func (e *Environment) Start(ctx context.Context) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.started {
+		return ErrEnvironmentStarted
+	}
+
+	if e.config.Alerts != nil {
+		if err := e.config.Alerts.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if err := e.trader.Initialize(ctx); err != nil {
+		return err
+	}
+
+	if err := e.trader.LoadState(ctx); err != nil {
+		return err
+	}
+
+	if err := e.trader.Run(ctx); err != nil {
+		return err
+	}
+
+	if alerts := e.config.Alerts; alerts != nil && len(alerts.Rules) > 0 {
+		go bbgo.NewAlertEngine(alerts, nil).Run(ctx)
+	}
+
+	e.started = true
+	return nil
+}
+
+// Shutdown runs the graceful shutdown handlers of the strategies, saves the strategy states,
+// audits the sessions if the shutdown audit is enabled and closes the session streams.
+func (e *Environment) Shutdown(ctx context.Context) {
+	bbgo.Shutdown(ctx)
+
+	if err := e.trader.SaveState(ctx); err != nil {
+		log.WithError(err).Errorf("can not save strategy persistence states")
+	}
+
+	if audit := e.config.ShutdownAudit; audit != nil && audit.Enabled {
+		report := e.environ.AuditSessions(ctx, audit)
+		log.Info(report.String())
+		bbgo.Notify("%s", report.String())
+	}
+
+	for _, session := range e.environ.Sessions() {
+		if err := session.MarketDataStream.Close(); err != nil {
+			log.WithError(err).Errorf("[%s] market data stream close error", session.Name)
+		}
+		if err := session.UserDataStream.Close(); err != nil {
+			log.WithError(err).Errorf("[%s] user data stream close error", session.Name)
+		}
+	}
+}
+
+// Run starts the trading and blocks until the context is canceled,
+// then it shuts down the environment gracefully within the graceful shutdown period.
+func (e *Environment) Run(ctx context.Context) error {
+	if err := e.Start(ctx); err != nil {
+		return fmt.Errorf("embed: unable to start the environment: %w", err)
+	}
+
+	<-ctx.Done()
+
+	shutdownCtx, cancelShutdown := context.WithTimeout(bbgo.NewTodoContextWithExistingIsolation(ctx), e.gracefulShutdownPeriod)
+	defer cancelShutdown()
+
+	e.Shutdown(shutdownCtx)
+	return nil
+}
@@ -0,0 +1,61 @@
+package embed
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/bbgo"
+)
+
+type testCrossStrategy struct{}
+
+func (s *testCrossStrategy) ID() string { return "embed-test" }
+
+func (s *testCrossStrategy) InstanceID() string { return "embed-test" }
+
+func (s *testCrossStrategy) CrossSubscribe(sessions map[string]*bbgo.ExchangeSession) {}
+
+func (s *testCrossStrategy) CrossRun(ctx context.Context, orderExecutionRouter bbgo.OrderExecutionRouter, sessions map[string]*bbgo.ExchangeSession) error {
+	return nil
+}
+
+func TestLoadConfig(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "bbgo.yaml")
+	err := os.WriteFile(configFile, []byte("sessions: {}\n"), 0644)
+	assert.NoError(t, err)
+
+	config, err := LoadConfig(configFile)
+	if assert.NoError(t, err) {
+		assert.NotNil(t, config)
+		assert.Empty(t, config.ExchangeStrategies)
+	}
+}
+
+func TestEnvironment(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	env, err := NewEnvironment(ctx, nil, WithLightweight(), WithoutSync())
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.NotNil(t, env.Environ())
+	assert.NotNil(t, env.Trader())
+
+	err = env.AddStrategy("undefined", nil)
+	assert.Error(t, err)
+
+	err = env.AddCrossExchangeStrategy(&testCrossStrategy{})
+	assert.NoError(t, err)
+
+	err = env.Start(ctx)
+	assert.NoError(t, err)
+
+	err = env.AddCrossExchangeStrategy(&testCrossStrategy{})
+	assert.ErrorIs(t, err, ErrEnvironmentStarted)
+}
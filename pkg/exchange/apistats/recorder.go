@@ -0,0 +1,181 @@
+package apistats
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// DefaultWindow is the default rolling window of the in-memory error report
+const DefaultWindow = time.Hour
+
+var metricsAPIErrors = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "bbgo_exchange_api_errors_total",
+		Help: "the total number of the exchange REST API errors",
+	},
+	[]string{
+		"exchange", // exchange name
+		"endpoint", // the normalized request path
+		"code",     // http status code or "network"
+	},
+)
+
+func init() {
+	prometheus.MustRegister(metricsAPIErrors)
+}
+
+// DefaultRecorder is the global recorder used by the exchange REST clients
+var DefaultRecorder = NewRecorder(DefaultWindow)
+
+// Record records the API error with the default recorder
+func Record(exchange types.ExchangeName, endpoint, code, message string) {
+	DefaultRecorder.Record(exchange, endpoint, code, message)
+}
+
+// Report returns the error report of the default recorder
+func Report() []EndpointErrorStats {
+	return DefaultRecorder.Report()
+}
+
+type key struct {
+	exchange types.ExchangeName
+	endpoint string
+	code     string
+}
+
+type entry struct {
+	// buckets stores the error counts by minute
+	buckets map[int64]int
+
+	total       int64
+	lastTime    time.Time
+	lastMessage string
+}
+
+// EndpointErrorStats is the error statistics of one exchange endpoint and error code
+type EndpointErrorStats struct {
+	Exchange types.ExchangeName `json:"exchange"`
+	Endpoint string             `json:"endpoint"`
+	Code     string             `json:"code"`
+
+	// Count is the number of errors in the rolling window
+	Count int `json:"count"`
+
+	// Total is the number of errors since the process started
+	Total int64 `json:"total"`
+
+	LastTime    time.Time `json:"lastTime"`
+	LastMessage string    `json:"lastMessage,omitempty"`
+}
+
+// Recorder aggregates the exchange API errors per exchange, endpoint and error code
+// in a rolling window, so that we can tell whether the errors are caused by us or by the exchange.
+type Recorder struct {
+	mu      sync.Mutex
+	window  time.Duration
+	entries map[key]*entry
+}
+
+func NewRecorder(window time.Duration) *Recorder {
+	return &Recorder{
+		window:  window,
+		entries: make(map[key]*entry),
+	}
+}
+
+func (r *Recorder) Record(exchange types.ExchangeName, endpoint, code, message string) {
+	r.record(time.Now(), exchange, endpoint, code, message)
+}
+
+func (r *Recorder) record(now time.Time, exchange types.ExchangeName, endpoint, code, message string) {
+	endpoint = NormalizeEndpoint(endpoint)
+	metricsAPIErrors.With(prometheus.Labels{
+		"exchange": exchange.String(),
+		"endpoint": endpoint,
+		"code":     code,
+	}).Inc()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	k := key{exchange: exchange, endpoint: endpoint, code: code}
+	e, ok := r.entries[k]
+	if !ok {
+		e = &entry{buckets: make(map[int64]int)}
+		r.entries[k] = e
+	}
+
+	e.buckets[now.Unix()/60]++
+	e.total++
+	e.lastTime = now
+	e.lastMessage = message
+	r.prune(e, now)
+}
+
+func (r *Recorder) prune(e *entry, now time.Time) {
+	since := now.Add(-r.window).Unix() / 60
+	for minute := range e.buckets {
+		if minute < since {
+			delete(e.buckets, minute)
+		}
+	}
+}
+
+// Report returns the error statistics in the rolling window, sorted by the error count in descending order
+func (r *Recorder) Report() []EndpointErrorStats {
+	return r.report(time.Now())
+}
+
+func (r *Recorder) report(now time.Time) (stats []EndpointErrorStats) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for k, e := range r.entries {
+		r.prune(e, now)
+
+		count := 0
+		for _, c := range e.buckets {
+			count += c
+		}
+
+		stats = append(stats, EndpointErrorStats{
+			Exchange:    k.exchange,
+			Endpoint:    k.endpoint,
+			Code:        k.code,
+			Count:       count,
+			Total:       e.total,
+			LastTime:    e.lastTime,
+			LastMessage: e.lastMessage,
+		})
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Count == stats[j].Count {
+			return stats[i].LastTime.After(stats[j].LastTime)
+		}
+		return stats[i].Count > stats[j].Count
+	})
+
+	return stats
+}
+
+var idSegmentRE = regexp.MustCompile(`^[0-9a-fA-F-]*[0-9][0-9a-fA-F-]*$`)
+
+// NormalizeEndpoint replaces the ID segments of the request path with ":id" to reduce the cardinality
+func NormalizeEndpoint(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if len(segment) > 0 && idSegmentRE.MatchString(segment) {
+			segments[i] = ":id"
+		}
+	}
+
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,63 @@
+package apistats
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestNormalizeEndpoint(t *testing.T) {
+	assert.Equal(t, "/api/v2/orders/:id", NormalizeEndpoint("/api/v2/orders/123456"))
+	assert.Equal(t, "/api/v3/order", NormalizeEndpoint("/api/v3/order"))
+	assert.Equal(t, "/api/v5/trade/order/:id", NormalizeEndpoint("/api/v5/trade/order/5f3e2a1b-0c9d-4e8f"))
+}
+
+func TestRecorder_Report(t *testing.T) {
+	r := NewRecorder(10 * time.Minute)
+	now := time.Now()
+
+	r.record(now.Add(-20*time.Minute), types.ExchangeBinance, "/api/v3/order", "400", "old error")
+	r.record(now, types.ExchangeBinance, "/api/v3/order", "400", "insufficient balance")
+	r.record(now, types.ExchangeBinance, "/api/v3/order", "400", "insufficient balance")
+	r.record(now, types.ExchangeMax, "/api/v2/orders/123", "503", "service unavailable")
+
+	stats := r.report(now)
+	if assert.Len(t, stats, 2) {
+		assert.Equal(t, types.ExchangeBinance, stats[0].Exchange)
+		assert.Equal(t, 2, stats[0].Count)
+		assert.Equal(t, int64(3), stats[0].Total)
+		assert.Equal(t, "insufficient balance", stats[0].LastMessage)
+
+		assert.Equal(t, "/api/v2/orders/:id", stats[1].Endpoint)
+		assert.Equal(t, 1, stats[1].Count)
+	}
+}
+
+func TestTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = w.Write([]byte(`{"code":-1003}`))
+	}))
+	defer server.Close()
+
+	transport := NewTransport(types.ExchangeBinance, nil)
+	transport.Recorder = NewRecorder(DefaultWindow)
+
+	client := &http.Client{Transport: transport}
+	resp, err := client.Get(server.URL + "/api/v3/depth")
+	if assert.NoError(t, err) {
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	}
+
+	stats := transport.Recorder.Report()
+	if assert.Len(t, stats, 1) {
+		assert.Equal(t, "429", stats[0].Code)
+		assert.Equal(t, `{"code":-1003}`, stats[0].LastMessage)
+	}
+}
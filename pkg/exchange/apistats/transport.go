@@ -0,0 +1,68 @@
+package apistats
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// maxMessageLength is the max length of the error response body we keep in the report
+const maxMessageLength = 256
+
+// Transport is a http.RoundTripper that records the error responses of the exchange REST API
+type Transport struct {
+	Exchange types.ExchangeName
+	Base     http.RoundTripper
+	Recorder *Recorder
+}
+
+// NewTransport wraps the base round tripper, http.DefaultTransport is used when base is nil
+func NewTransport(exchange types.ExchangeName, base http.RoundTripper) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	return &Transport{
+		Exchange: exchange,
+		Base:     base,
+		Recorder: DefaultRecorder,
+	}
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.Base.RoundTrip(req)
+	if err != nil {
+		t.Recorder.Record(t.Exchange, req.URL.Path, "network", err.Error())
+		return resp, err
+	}
+
+	if resp.StatusCode >= 400 {
+		body, readErr := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		if readErr != nil {
+			return resp, readErr
+		}
+
+		message := string(body)
+		if len(message) > maxMessageLength {
+			message = message[:maxMessageLength]
+		}
+
+		t.Recorder.Record(t.Exchange, req.URL.Path, strconv.Itoa(resp.StatusCode), message)
+	}
+
+	return resp, nil
+}
+
+// ReportHandler serves the error report of the default recorder in JSON
+func ReportHandler(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(Report()); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 
+	"github.com/c9s/bbgo/pkg/exchange/apistats"
 	"github.com/c9s/bbgo/pkg/types"
 )
 
@@ -45,7 +46,7 @@ var defaultTransport = &http.Transport{
 
 var DefaultHttpClient = &http.Client{
 	Timeout:   defaultHTTPTimeout,
-	Transport: defaultTransport,
+	Transport: apistats.NewTransport(types.ExchangeBinance, defaultTransport),
 }
 
 type RestClient struct {
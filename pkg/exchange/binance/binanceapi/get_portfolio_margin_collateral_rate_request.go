@@ -0,0 +1,22 @@
+package binanceapi
+
+import (
+	"github.com/c9s/requestgen"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+)
+
+// PortfolioMarginCollateralRate is the rate of the asset value counted as the collateral of the portfolio margin account
+type PortfolioMarginCollateralRate struct {
+	Asset          string           `json:"asset"`
+	CollateralRate fixedpoint.Value `json:"collateralRate"`
+}
+
+//go:generate requestgen -method GET -url "/sapi/v1/portfolio/collateralRate" -type GetPortfolioMarginCollateralRateRequest -responseType []PortfolioMarginCollateralRate
+type GetPortfolioMarginCollateralRateRequest struct {
+	client requestgen.AuthenticatedAPIClient
+}
+
+func (c *RestClient) NewGetPortfolioMarginCollateralRateRequest() *GetPortfolioMarginCollateralRateRequest {
+	return &GetPortfolioMarginCollateralRateRequest{client: c}
+}
@@ -0,0 +1,18 @@
+package binanceapi
+
+import "github.com/c9s/requestgen"
+
+type SystemStatus struct {
+	// Status is 0 for normal, 1 for system maintenance
+	Status int    `json:"status"`
+	Msg    string `json:"msg"`
+}
+
+//go:generate requestgen -method GET -url "/sapi/v1/system/status" -type GetSystemStatusRequest -responseType .SystemStatus
+type GetSystemStatusRequest struct {
+	client requestgen.APIClient
+}
+
+func (c *RestClient) NewGetSystemStatusRequest() *GetSystemStatusRequest {
+	return &GetSystemStatusRequest{client: c}
+}
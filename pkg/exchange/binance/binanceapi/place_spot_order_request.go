@@ -0,0 +1,36 @@
+package binanceapi
+
+import (
+	"github.com/adshao/go-binance/v2"
+	"github.com/c9s/requestgen"
+)
+
+// PlaceSpotOrderResponse is the RESULT response of the spot order creation
+type PlaceSpotOrderResponse = binance.CreateOrderResponse
+
+// PlaceSpotOrderRequest places the spot order with the parameters that are not supported by the go-binance client,
+// e.g. selfTradePreventionMode
+//
+//go:generate requestgen -method POST -url "/api/v3/order" -type PlaceSpotOrderRequest -responseType .PlaceSpotOrderResponse
+type PlaceSpotOrderRequest struct {
+	client requestgen.AuthenticatedAPIClient
+
+	symbol    string    `param:"symbol"`
+	side      SideType  `param:"side"`
+	orderType OrderType `param:"type"`
+
+	timeInForce      *string `param:"timeInForce"`
+	quantity         *string `param:"quantity"`
+	price            *string `param:"price"`
+	stopPrice        *string `param:"stopPrice"`
+	newClientOrderId *string `param:"newClientOrderId"`
+
+	newOrderRespType *OrderRespType `param:"newOrderRespType"`
+
+	// selfTradePreventionMode is one of EXPIRE_TAKER, EXPIRE_MAKER, EXPIRE_BOTH and NONE
+	selfTradePreventionMode *string `param:"selfTradePreventionMode"`
+}
+
+func (c *RestClient) NewPlaceSpotOrderRequest() *PlaceSpotOrderRequest {
+	return &PlaceSpotOrderRequest{client: c}
+}
@@ -0,0 +1,20 @@
+package binanceapi
+
+import (
+	"github.com/c9s/requestgen"
+)
+
+//go:generate requestgen -method DELETE -url "/papi/v1/:market/order" -type PortfolioMarginCancelOrderRequest -responseType .PortfolioMarginOrder
+type PortfolioMarginCancelOrderRequest struct {
+	client requestgen.AuthenticatedAPIClient
+
+	market PortfolioMarginMarket `param:"market,slug" validValues:"margin,um"`
+
+	symbol            string  `param:"symbol"`
+	orderId           *uint64 `param:"orderId"`
+	origClientOrderId *string `param:"origClientOrderId"`
+}
+
+func (c *PortfolioMarginRestClient) NewPortfolioMarginCancelOrderRequest() *PortfolioMarginCancelOrderRequest {
+	return &PortfolioMarginCancelOrderRequest{client: c}
+}
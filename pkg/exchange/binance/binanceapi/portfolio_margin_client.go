@@ -0,0 +1,43 @@
+package binanceapi
+
+import (
+	"net/url"
+
+	"github.com/c9s/requestgen"
+)
+
+// PortfolioMarginRestClient is used for the portfolio margin account,
+// the cross margin and the usdt-m futures of the account are traded through the portfolio margin api.
+type PortfolioMarginRestClient struct {
+	RestClient
+}
+
+const PortfolioMarginRestBaseURL = "https://papi.binance.com"
+
+func NewPortfolioMarginRestClient(baseURL string) *PortfolioMarginRestClient {
+	if len(baseURL) == 0 {
+		baseURL = PortfolioMarginRestBaseURL
+	}
+
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		panic(err)
+	}
+
+	return &PortfolioMarginRestClient{
+		RestClient: RestClient{
+			BaseAPIClient: requestgen.BaseAPIClient{
+				BaseURL:    u,
+				HttpClient: DefaultHttpClient,
+			},
+		},
+	}
+}
+
+// PortfolioMarginMarket is the market of the portfolio margin order api
+type PortfolioMarginMarket string
+
+const (
+	PortfolioMarginMarketMargin PortfolioMarginMarket = "margin"
+	PortfolioMarginMarketUM     PortfolioMarginMarket = "um"
+)
@@ -0,0 +1,36 @@
+package binanceapi
+
+import (
+	"github.com/c9s/requestgen"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+type PortfolioMarginAccount struct {
+	// UniMMR is the unified maintenance margin ratio, the account is liquidated when it drops to 1.05
+	UniMMR fixedpoint.Value `json:"uniMMR"`
+
+	// AccountEquity is the account equity in USD
+	AccountEquity fixedpoint.Value `json:"accountEquity"`
+
+	// ActualEquity is the account equity in USD without the collateral rate
+	ActualEquity fixedpoint.Value `json:"actualEquity"`
+
+	AccountInitialMargin     fixedpoint.Value           `json:"accountInitialMargin"`
+	AccountMaintMargin       fixedpoint.Value           `json:"accountMaintMargin"`
+	AccountStatus            string                     `json:"accountStatus"`
+	VirtualMaxWithdrawAmount fixedpoint.Value           `json:"virtualMaxWithdrawAmount"`
+	TotalAvailableBalance    fixedpoint.Value           `json:"totalAvailableBalance"`
+	TotalMarginOpenLoss      fixedpoint.Value           `json:"totalMarginOpenLoss"`
+	UpdateTime               types.MillisecondTimestamp `json:"updateTime"`
+}
+
+//go:generate requestgen -method GET -url "/papi/v1/account" -type PortfolioMarginGetAccountRequest -responseType .PortfolioMarginAccount
+type PortfolioMarginGetAccountRequest struct {
+	client requestgen.AuthenticatedAPIClient
+}
+
+func (c *PortfolioMarginRestClient) NewPortfolioMarginGetAccountRequest() *PortfolioMarginGetAccountRequest {
+	return &PortfolioMarginGetAccountRequest{client: c}
+}
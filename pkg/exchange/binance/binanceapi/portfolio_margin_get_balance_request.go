@@ -0,0 +1,40 @@
+package binanceapi
+
+import (
+	"github.com/c9s/requestgen"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// PortfolioMarginBalance is the asset balance of the portfolio margin account,
+// the wallet balance is shared by the cross margin, the usdt-m futures and the coin-m futures.
+type PortfolioMarginBalance struct {
+	Asset              string           `json:"asset"`
+	TotalWalletBalance fixedpoint.Value `json:"totalWalletBalance"`
+
+	CrossMarginAsset    fixedpoint.Value `json:"crossMarginAsset"`
+	CrossMarginBorrowed fixedpoint.Value `json:"crossMarginBorrowed"`
+	CrossMarginFree     fixedpoint.Value `json:"crossMarginFree"`
+	CrossMarginInterest fixedpoint.Value `json:"crossMarginInterest"`
+	CrossMarginLocked   fixedpoint.Value `json:"crossMarginLocked"`
+
+	UMWalletBalance fixedpoint.Value `json:"umWalletBalance"`
+	UMUnrealizedPNL fixedpoint.Value `json:"umUnrealizedPNL"`
+	CMWalletBalance fixedpoint.Value `json:"cmWalletBalance"`
+	CMUnrealizedPNL fixedpoint.Value `json:"cmUnrealizedPNL"`
+
+	NegativeBalance fixedpoint.Value           `json:"negativeBalance"`
+	UpdateTime      types.MillisecondTimestamp `json:"updateTime"`
+}
+
+//go:generate requestgen -method GET -url "/papi/v1/balance" -type PortfolioMarginGetBalanceRequest -responseType []PortfolioMarginBalance
+type PortfolioMarginGetBalanceRequest struct {
+	client requestgen.AuthenticatedAPIClient
+
+	asset *string `param:"asset"`
+}
+
+func (c *PortfolioMarginRestClient) NewPortfolioMarginGetBalanceRequest() *PortfolioMarginGetBalanceRequest {
+	return &PortfolioMarginGetBalanceRequest{client: c}
+}
@@ -0,0 +1,18 @@
+package binanceapi
+
+import (
+	"github.com/c9s/requestgen"
+)
+
+//go:generate requestgen -method GET -url "/papi/v1/:market/openOrders" -type PortfolioMarginGetOpenOrdersRequest -responseType []PortfolioMarginOrder
+type PortfolioMarginGetOpenOrdersRequest struct {
+	client requestgen.AuthenticatedAPIClient
+
+	market PortfolioMarginMarket `param:"market,slug" validValues:"margin,um"`
+
+	symbol *string `param:"symbol"`
+}
+
+func (c *PortfolioMarginRestClient) NewPortfolioMarginGetOpenOrdersRequest() *PortfolioMarginGetOpenOrdersRequest {
+	return &PortfolioMarginGetOpenOrdersRequest{client: c}
+}
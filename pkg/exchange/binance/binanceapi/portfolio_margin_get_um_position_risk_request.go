@@ -0,0 +1,16 @@
+package binanceapi
+
+import (
+	"github.com/c9s/requestgen"
+)
+
+//go:generate requestgen -method GET -url "/papi/v1/um/positionRisk" -type PortfolioMarginGetUMPositionRiskRequest -responseType []FuturesPositionRisk
+type PortfolioMarginGetUMPositionRiskRequest struct {
+	client requestgen.AuthenticatedAPIClient
+
+	symbol *string `param:"symbol"`
+}
+
+func (c *PortfolioMarginRestClient) NewPortfolioMarginGetUMPositionRiskRequest() *PortfolioMarginGetUMPositionRiskRequest {
+	return &PortfolioMarginGetUMPositionRiskRequest{client: c}
+}
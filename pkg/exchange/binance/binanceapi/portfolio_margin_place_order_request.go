@@ -0,0 +1,72 @@
+package binanceapi
+
+import (
+	"github.com/c9s/requestgen"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// PortfolioMarginOrder is the order of the cross margin or the usdt-m futures of the portfolio margin account
+type PortfolioMarginOrder struct {
+	Symbol           string           `json:"symbol"`
+	OrderID          int64            `json:"orderId"`
+	ClientOrderID    string           `json:"clientOrderId"`
+	Price            fixedpoint.Value `json:"price"`
+	StopPrice        fixedpoint.Value `json:"stopPrice"`
+	OrigQuantity     fixedpoint.Value `json:"origQty"`
+	ExecutedQuantity fixedpoint.Value `json:"executedQty"`
+
+	// CumQuote is the executed quote quantity of the usdt-m futures order
+	CumQuote fixedpoint.Value `json:"cumQuote"`
+
+	// CummulativeQuoteQuantity is the executed quote quantity of the margin order
+	CummulativeQuoteQuantity fixedpoint.Value `json:"cummulativeQuoteQty"`
+
+	Status      OrderStatusType `json:"status"`
+	TimeInForce string          `json:"timeInForce"`
+	Type        OrderType       `json:"type"`
+	Side        SideType        `json:"side"`
+
+	// ReduceOnly and PositionSide are only returned by the usdt-m futures order
+	ReduceOnly   bool   `json:"reduceOnly"`
+	PositionSide string `json:"positionSide"`
+
+	// TransactTime is only returned by the margin order creation
+	TransactTime types.MillisecondTimestamp `json:"transactTime"`
+	Time         types.MillisecondTimestamp `json:"time"`
+	UpdateTime   types.MillisecondTimestamp `json:"updateTime"`
+}
+
+// PortfolioMarginPlaceOrderRequest places the cross margin order or the usdt-m futures order by the market
+//
+//go:generate requestgen -method POST -url "/papi/v1/:market/order" -type PortfolioMarginPlaceOrderRequest -responseType .PortfolioMarginOrder
+type PortfolioMarginPlaceOrderRequest struct {
+	client requestgen.AuthenticatedAPIClient
+
+	market PortfolioMarginMarket `param:"market,slug" validValues:"margin,um"`
+
+	symbol    string    `param:"symbol"`
+	side      SideType  `param:"side"`
+	orderType OrderType `param:"type"`
+
+	timeInForce      *string `param:"timeInForce"`
+	quantity         *string `param:"quantity"`
+	price            *string `param:"price"`
+	stopPrice        *string `param:"stopPrice"`
+	newClientOrderId *string `param:"newClientOrderId"`
+
+	// reduceOnly is only for the usdt-m futures order
+	reduceOnly *bool `param:"reduceOnly"`
+
+	// sideEffectType is only for the margin order, one of NO_SIDE_EFFECT, MARGIN_BUY, AUTO_REPAY
+	sideEffectType *string `param:"sideEffectType"`
+
+	newOrderRespType *OrderRespType `param:"newOrderRespType"`
+
+	selfTradePreventionMode *string `param:"selfTradePreventionMode"`
+}
+
+func (c *PortfolioMarginRestClient) NewPortfolioMarginPlaceOrderRequest() *PortfolioMarginPlaceOrderRequest {
+	return &PortfolioMarginPlaceOrderRequest{client: c}
+}
@@ -0,0 +1,339 @@
+package binanceapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/adshao/go-binance/v2"
+	"github.com/gorilla/websocket"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+const WsAPIBaseURL = "wss://ws-api.binance.com:443/ws-api/v3"
+const SandboxWsAPIBaseURL = "wss://ws-api.testnet.binance.vision/ws-api/v3"
+
+const wsAPIRequestTimeout = 10 * time.Second
+const wsAPIWriteTimeout = 5 * time.Second
+const wsAPIReconnectDelay = 5 * time.Second
+
+var wsAPIDialer = &websocket.Dialer{
+	Proxy:            http.ProxyFromEnvironment,
+	HandshakeTimeout: 10 * time.Second,
+}
+
+// ErrWsAPIDisconnected is returned when the connection is closed before the response is received,
+// the request might be executed by the server.
+var ErrWsAPIDisconnected = errors.New("binance websocket api connection is closed")
+
+// WsAPIError is the error status of the websocket api response
+type WsAPIError struct {
+	Status  int    `json:"-"`
+	Code    int    `json:"code"`
+	Message string `json:"msg"`
+}
+
+func (e *WsAPIError) Error() string {
+	return fmt.Sprintf("binance websocket api error: status=%d code=%d msg=%s", e.Status, e.Code, e.Message)
+}
+
+type WsAPIRequest struct {
+	ID     string                 `json:"id"`
+	Method string                 `json:"method"`
+	Params map[string]interface{} `json:"params,omitempty"`
+}
+
+type WsAPIResponse struct {
+	ID     string          `json:"id"`
+	Status int             `json:"status"`
+	Result json.RawMessage `json:"result"`
+	Error  *WsAPIError     `json:"error"`
+}
+
+// CancelSpotOrderResponse is the response of the spot order cancellation
+type CancelSpotOrderResponse = binance.CancelOrderResponse
+
+// WsAPIClient sends the trading requests through the binance websocket api, the responses are correlated by the request id.
+// The api key, the secret and the server time offset are shared with the rest client.
+type WsAPIClient struct {
+	BaseURL string
+
+	client *RestClient
+
+	requestID uint64
+
+	// mu protects conn and pending
+	mu      sync.Mutex
+	conn    *websocket.Conn
+	pending map[string]chan *WsAPIResponse
+
+	// writeMu serializes the writes of the connection
+	writeMu sync.Mutex
+}
+
+func (c *RestClient) NewWsAPIClient(baseURL string) *WsAPIClient {
+	if len(baseURL) == 0 {
+		baseURL = WsAPIBaseURL
+	}
+
+	return &WsAPIClient{
+		BaseURL: baseURL,
+		client:  c,
+		pending: make(map[string]chan *WsAPIResponse),
+	}
+}
+
+// Run keeps the connection alive until the context is canceled, the connection is re-established after it's closed.
+func (c *WsAPIClient) Run(ctx context.Context) {
+	for {
+		conn, _, err := wsAPIDialer.DialContext(ctx, c.BaseURL, nil)
+		if err != nil {
+			logrus.WithError(err).Errorf("unable to connect to the binance websocket api %s", c.BaseURL)
+		} else {
+			c.setConn(conn)
+			c.read(ctx, conn)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wsAPIReconnectDelay):
+		}
+	}
+}
+
+// Connected returns true when the connection is established
+func (c *WsAPIClient) Connected() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.conn != nil
+}
+
+func (c *WsAPIClient) setConn(conn *websocket.Conn) {
+	c.mu.Lock()
+	c.conn = conn
+	c.mu.Unlock()
+}
+
+// read dispatches the responses to the pending requests until the connection is closed
+func (c *WsAPIClient) read(ctx context.Context, conn *websocket.Conn) {
+	stop := context.AfterFunc(ctx, func() {
+		_ = conn.Close()
+	})
+	defer stop()
+
+	for {
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			if ctx.Err() == nil {
+				logrus.WithError(err).Warnf("binance websocket api connection is closed")
+			}
+			break
+		}
+
+		var response WsAPIResponse
+		if err := json.Unmarshal(message, &response); err != nil {
+			logrus.WithError(err).Errorf("unable to parse the binance websocket api response: %s", message)
+			continue
+		}
+
+		c.mu.Lock()
+		responseC, ok := c.pending[response.ID]
+		delete(c.pending, response.ID)
+		c.mu.Unlock()
+
+		if ok {
+			responseC <- &response
+		}
+	}
+
+	_ = conn.Close()
+
+	// the pending requests are failed, they might be executed by the server
+	c.mu.Lock()
+	if c.conn == conn {
+		c.conn = nil
+	}
+	for id, responseC := range c.pending {
+		close(responseC)
+		delete(c.pending, id)
+	}
+	c.mu.Unlock()
+}
+
+// Do sends the request and waits for the response, the signed request is authenticated by the api key and the signature.
+// The error wraps types.ErrWebSocketOrderEntryUnavailable if the request is not sent.
+func (c *WsAPIClient) Do(ctx context.Context, method string, params map[string]interface{}, signed bool, result interface{}) error {
+	if params == nil {
+		params = map[string]interface{}{}
+	}
+
+	if signed {
+		if err := c.sign(params); err != nil {
+			return err
+		}
+	}
+
+	request := WsAPIRequest{
+		ID:     strconv.FormatUint(atomic.AddUint64(&c.requestID, 1), 10),
+		Method: method,
+		Params: params,
+	}
+
+	payload, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+
+	responseC := make(chan *WsAPIResponse, 1)
+
+	c.mu.Lock()
+	conn := c.conn
+	if conn != nil {
+		c.pending[request.ID] = responseC
+	}
+	c.mu.Unlock()
+
+	if conn == nil {
+		return fmt.Errorf("binance websocket api is not connected: %w", types.ErrWebSocketOrderEntryUnavailable)
+	}
+
+	c.writeMu.Lock()
+	_ = conn.SetWriteDeadline(time.Now().Add(wsAPIWriteTimeout))
+	err = conn.WriteMessage(websocket.TextMessage, payload)
+	c.writeMu.Unlock()
+
+	if err != nil {
+		c.mu.Lock()
+		delete(c.pending, request.ID)
+		c.mu.Unlock()
+		return fmt.Errorf("unable to send the binance websocket api request %s: %v: %w", method, err, types.ErrWebSocketOrderEntryUnavailable)
+	}
+
+	timer := time.NewTimer(wsAPIRequestTimeout)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		c.cancelPending(request.ID)
+		return ctx.Err()
+
+	case <-timer.C:
+		c.cancelPending(request.ID)
+		return fmt.Errorf("binance websocket api request %s timeout", method)
+
+	case response, ok := <-responseC:
+		if !ok {
+			return ErrWsAPIDisconnected
+		}
+
+		if response.Status != http.StatusOK {
+			if response.Error == nil {
+				response.Error = &WsAPIError{}
+			}
+
+			response.Error.Status = response.Status
+			return response.Error
+		}
+
+		if result == nil {
+			return nil
+		}
+
+		return json.Unmarshal(response.Result, result)
+	}
+}
+
+func (c *WsAPIClient) cancelPending(id string) {
+	c.mu.Lock()
+	delete(c.pending, id)
+	c.mu.Unlock()
+}
+
+// sign adds the api key, the timestamp and the signature of the sorted parameters
+func (c *WsAPIClient) sign(params map[string]interface{}) error {
+	if len(c.client.Key) == 0 {
+		return errors.New("empty api key")
+	}
+
+	if len(c.client.Secret) == 0 {
+		return errors.New("empty api secret")
+	}
+
+	params["apiKey"] = c.client.Key
+	params["timestamp"] = currentTimestamp() - c.client.timeOffset
+	if c.client.recvWindow > 0 {
+		params["recvWindow"] = c.client.recvWindow
+	}
+
+	params["signature"] = sign(c.client.Secret, encodeWsAPIParams(params))
+	return nil
+}
+
+// encodeWsAPIParams encodes the parameters in the alphabetical order as the payload of the signature
+func encodeWsAPIParams(params map[string]interface{}) string {
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		if key != "signature" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	var payload string
+	for i, key := range keys {
+		if i > 0 {
+			payload += "&"
+		}
+		payload += key + "=" + url.QueryEscape(fmt.Sprintf("%v", params[key]))
+	}
+
+	return payload
+}
+
+// PlaceOrder places the spot order, the parameters are the same as the rest api
+func (c *WsAPIClient) PlaceOrder(ctx context.Context, params map[string]interface{}) (*PlaceSpotOrderResponse, error) {
+	var response PlaceSpotOrderResponse
+	if err := c.Do(ctx, "order.place", params, true, &response); err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+// CancelOrder cancels the spot order by the order id, or the client order id when the order id is zero
+func (c *WsAPIClient) CancelOrder(ctx context.Context, symbol string, orderID uint64, clientOrderID string) (*CancelSpotOrderResponse, error) {
+	params := map[string]interface{}{"symbol": symbol}
+	if orderID > 0 {
+		params["orderId"] = orderID
+	} else {
+		params["origClientOrderId"] = clientOrderID
+	}
+
+	var response CancelSpotOrderResponse
+	if err := c.Do(ctx, "order.cancel", params, true, &response); err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+// CancelReplaceOrder cancels the spot order and places the new order atomically, the parameters are the same as the rest api
+func (c *WsAPIClient) CancelReplaceOrder(ctx context.Context, params map[string]interface{}) (*CancelReplaceSpotOrderData, error) {
+	var response CancelReplaceSpotOrderData
+	if err := c.Do(ctx, "order.cancelReplace", params, true, &response); err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
@@ -0,0 +1,117 @@
+package binanceapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func newTestWsAPIServer(t *testing.T, handler func(request WsAPIRequest) interface{}) *httptest.Server {
+	upgrader := websocket.Upgrader{}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer conn.Close()
+
+		for {
+			_, message, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+
+			// keep the numbers as they are sent for verifying the signature
+			var request WsAPIRequest
+			decoder := json.NewDecoder(bytes.NewReader(message))
+			decoder.UseNumber()
+			if !assert.NoError(t, decoder.Decode(&request)) {
+				return
+			}
+
+			if err := conn.WriteJSON(handler(request)); err != nil {
+				return
+			}
+		}
+	}))
+}
+
+func TestWsAPIClient(t *testing.T) {
+	server := newTestWsAPIServer(t, func(request WsAPIRequest) interface{} {
+		// the signature is verified with the parameters in the alphabetical order
+		signature := request.Params["signature"]
+		assert.Equal(t, sign("secret", encodeWsAPIParams(request.Params)), signature)
+		assert.Equal(t, "key", request.Params["apiKey"])
+
+		switch request.Method {
+		case "order.place":
+			return map[string]interface{}{
+				"id":     request.ID,
+				"status": 200,
+				"result": map[string]interface{}{
+					"symbol":        request.Params["symbol"],
+					"orderId":       12345,
+					"clientOrderId": request.Params["newClientOrderId"],
+					"price":         request.Params["price"],
+					"origQty":       request.Params["quantity"],
+					"status":        "NEW",
+				},
+			}
+
+		default:
+			return map[string]interface{}{
+				"id":     request.ID,
+				"status": 400,
+				"error":  map[string]interface{}{"code": -2011, "msg": "Unknown order sent."},
+			}
+		}
+	})
+	defer server.Close()
+
+	client := NewClient("")
+	client.Auth("key", "secret")
+
+	wsClient := client.NewWsAPIClient("ws" + strings.TrimPrefix(server.URL, "http"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	_, err := wsClient.PlaceOrder(ctx, map[string]interface{}{"symbol": "BTCUSDT"})
+	assert.True(t, errors.Is(err, types.ErrWebSocketOrderEntryUnavailable))
+
+	go wsClient.Run(ctx)
+	assert.Eventually(t, wsClient.Connected, time.Second, 10*time.Millisecond)
+
+	response, err := wsClient.PlaceOrder(ctx, map[string]interface{}{
+		"symbol":           "BTCUSDT",
+		"side":             "BUY",
+		"type":             "LIMIT",
+		"price":            "40000.00",
+		"quantity":         "0.001",
+		"newClientOrderId": "x-test",
+	})
+	if assert.NoError(t, err) {
+		assert.Equal(t, int64(12345), response.OrderID)
+		assert.Equal(t, "x-test", response.ClientOrderID)
+		assert.Equal(t, "40000.00", response.Price)
+	}
+
+	_, err = wsClient.CancelOrder(ctx, "BTCUSDT", 1, "")
+	var apiErr *WsAPIError
+	if assert.True(t, errors.As(err, &apiErr)) {
+		assert.Equal(t, 400, apiErr.Status)
+		assert.Equal(t, -2011, apiErr.Code)
+	}
+	assert.False(t, errors.Is(err, types.ErrWebSocketOrderEntryUnavailable))
+}
@@ -19,6 +19,11 @@ func (e *Exchange) CancelReplace(ctx context.Context, cancelReplaceMode types.Ca
 		// Not supported at the moment
 		return nil, nil
 	}
+
+	if e.WebSocketOrderEntryAvailable() {
+		return e.cancelReplaceByWebSocket(ctx, cancelReplaceMode, o)
+	}
+
 	var req = e.client2.NewCancelReplaceSpotOrderRequest()
 	req.Symbol(o.Symbol)
 	req.Side(binance.SideType(o.Side))
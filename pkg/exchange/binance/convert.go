@@ -94,6 +94,38 @@ func toGlobalFuturesMarket(symbol futures.Symbol) types.Market {
 	return market
 }
 
+// perpetualDeliveryDate is the delivery date of the perpetual contracts that are not going to be delisted
+var perpetualDeliveryDate = time.Date(2100, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// toGlobalSymbolTradingStatus converts the symbol status of the exchange info,
+// BREAK is the status of the delisted spot symbols, the pre-trading statuses are treated as halted.
+func toGlobalSymbolTradingStatus(status string) types.SymbolTradingStatus {
+	switch status {
+	case "TRADING":
+		return types.SymbolTradingStatusTrading
+	case "BREAK", "CLOSE", "SETTLING", "DELIVERED":
+		return types.SymbolTradingStatusDelisted
+	default:
+		return types.SymbolTradingStatusHalted
+	}
+}
+
+func toGlobalFuturesSymbolStatus(symbol futures.Symbol) types.SymbolStatus {
+	status := types.SymbolStatus{
+		Symbol:  symbol.Symbol,
+		Status:  toGlobalSymbolTradingStatus(symbol.Status),
+		Message: symbol.Status,
+	}
+
+	if symbol.ContractType == futures.ContractTypePerpetual && symbol.DeliveryDate > 0 {
+		if deliveryDate := time.UnixMilli(symbol.DeliveryDate); deliveryDate.Before(perpetualDeliveryDate) {
+			status.DelistTime = deliveryDate
+		}
+	}
+
+	return status
+}
+
 // func toGlobalIsolatedMarginAccount(account *binance.IsolatedMarginAccount) *types.IsolatedMarginAccount {
 //	return &types.IsolatedMarginAccount{
 //		TotalAssetOfBTC:     fixedpoint.MustNewFromString(account.TotalNetAssetOfBTC),
@@ -113,6 +145,10 @@ func toGlobalTicker(stats *binance.PriceChangeStats) (*types.Ticker, error) {
 		Buy:    fixedpoint.MustNewFromString(stats.BidPrice),
 		Sell:   fixedpoint.MustNewFromString(stats.AskPrice),
 		Time:   time.Unix(0, stats.CloseTime*int64(time.Millisecond)),
+
+		BuySize:  fixedpoint.MustNewFromString(stats.BidQty),
+		SellSize: fixedpoint.MustNewFromString(stats.AskQty),
+		LastSize: fixedpoint.MustNewFromString(stats.LastQty),
 	}, nil
 }
 
@@ -126,6 +162,8 @@ func toGlobalFuturesTicker(stats *futures.PriceChangeStats) (*types.Ticker, erro
 		Buy:    fixedpoint.MustNewFromString(stats.LastPrice),
 		Sell:   fixedpoint.MustNewFromString(stats.LastPrice),
 		Time:   time.Unix(0, stats.CloseTime*int64(time.Millisecond)),
+
+		LastSize: fixedpoint.MustNewFromString(stats.LastQuantity),
 	}, nil
 }
 
@@ -165,7 +203,7 @@ func toGlobalOrders(binanceOrders []*binance.Order, isMargin bool) (orders []typ
 }
 
 func toGlobalOrder(binanceOrder *binance.Order, isMargin bool) (*types.Order, error) {
-	return &types.Order{
+	order := &types.Order{
 		SubmitOrder: types.SubmitOrder{
 			ClientOrderID: binanceOrder.ClientOrderID,
 			Symbol:        binanceOrder.Symbol,
@@ -185,7 +223,14 @@ func toGlobalOrder(binanceOrder *binance.Order, isMargin bool) (*types.Order, er
 		UpdateTime:       types.Time(millisecondTime(binanceOrder.UpdateTime)),
 		IsMargin:         isMargin,
 		IsIsolated:       binanceOrder.IsIsolated,
-	}, nil
+	}
+
+	// the order list id is -1 if the order is not in an order list
+	if binanceOrder.OrderListId > 0 {
+		order.OrderListID = uint64(binanceOrder.OrderListId)
+	}
+
+	return order, nil
 }
 
 func millisecondTime(t int64) time.Time {
@@ -0,0 +1,37 @@
+package binance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/adshao/go-binance/v2/futures"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func Test_toGlobalSymbolTradingStatus(t *testing.T) {
+	assert.Equal(t, types.SymbolTradingStatusTrading, toGlobalSymbolTradingStatus("TRADING"))
+	assert.Equal(t, types.SymbolTradingStatusHalted, toGlobalSymbolTradingStatus("HALT"))
+	assert.Equal(t, types.SymbolTradingStatusDelisted, toGlobalSymbolTradingStatus("BREAK"))
+}
+
+func Test_toGlobalFuturesSymbolStatus(t *testing.T) {
+	status := toGlobalFuturesSymbolStatus(futures.Symbol{
+		Symbol:       "BTCUSDT",
+		ContractType: futures.ContractTypePerpetual,
+		DeliveryDate: 4133404800000,
+		Status:       "TRADING",
+	})
+	assert.True(t, status.Normal())
+
+	delistTime := time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC)
+	status = toGlobalFuturesSymbolStatus(futures.Symbol{
+		Symbol:       "XYZUSDT",
+		ContractType: futures.ContractTypePerpetual,
+		DeliveryDate: delistTime.UnixMilli(),
+		Status:       "TRADING",
+	})
+	assert.False(t, status.Normal())
+	assert.True(t, delistTime.Equal(status.DelistTime))
+}
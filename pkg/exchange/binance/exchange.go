@@ -6,6 +6,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/adshao/go-binance/v2"
@@ -55,6 +56,8 @@ func init() {
 	_ = types.Exchange(&Exchange{})
 	_ = types.MarginExchange(&Exchange{})
 	_ = types.FuturesExchange(&Exchange{})
+	_ = types.ExchangeWebSocketOrderEntryService(&Exchange{})
+	_ = types.ExchangeWebSocketOrderEntryEnabler(&Exchange{})
 
 	if n, ok := util.GetEnvVarInt("BINANCE_ORDER_RATE_LIMITER"); ok {
 		orderLimiter = rate.NewLimiter(rate.Every(time.Duration(n)*time.Minute), 2)
@@ -86,6 +89,19 @@ type Exchange struct {
 	client2 *binanceapi.RestClient
 
 	futuresClient2 *binanceapi.FuturesRestClient
+
+	// wsAPIClient is used for the spot order entry when the websocket order entry is enabled
+	wsAPIClient atomic.Pointer[binanceapi.WsAPIClient]
+	wsAPIOnce   sync.Once
+
+	// portfolioMarginClient is used for the margin and futures when the account is in the portfolio margin mode
+	portfolioMarginClient *binanceapi.PortfolioMarginRestClient
+
+	// portfolioMarginMu protects the detected account mode and the cached collateral rates
+	portfolioMarginMu         sync.Mutex
+	portfolioMargin           *bool
+	collateralRates           map[string]fixedpoint.Value
+	collateralRatesUpdateTime time.Time
 }
 
 var timeSetterOnce sync.Once
@@ -108,19 +124,22 @@ func New(key, secret string) *Exchange {
 
 	client2 := binanceapi.NewClient(client.BaseURL)
 	futuresClient2 := binanceapi.NewFuturesRestClient(futuresClient.BaseURL)
+	portfolioMarginClient := binanceapi.NewPortfolioMarginRestClient("")
 
 	ex := &Exchange{
-		key:            key,
-		secret:         secret,
-		client:         client,
-		futuresClient:  futuresClient,
-		client2:        client2,
-		futuresClient2: futuresClient2,
+		key:                   key,
+		secret:                secret,
+		client:                client,
+		futuresClient:         futuresClient,
+		client2:               client2,
+		futuresClient2:        futuresClient2,
+		portfolioMarginClient: portfolioMarginClient,
 	}
 
 	if len(key) > 0 && len(secret) > 0 {
 		client2.Auth(key, secret)
 		futuresClient2.Auth(key, secret)
+		portfolioMarginClient.Auth(key, secret)
 	}
 
 	ctx := context.Background()
@@ -215,18 +234,12 @@ func (e *Exchange) QueryTickers(ctx context.Context, symbol ...string) (map[stri
 				continue
 			}
 
-			tick := types.Ticker{
-				Volume: fixedpoint.MustNewFromString(stats.Volume),
-				Last:   fixedpoint.MustNewFromString(stats.LastPrice),
-				Open:   fixedpoint.MustNewFromString(stats.OpenPrice),
-				High:   fixedpoint.MustNewFromString(stats.HighPrice),
-				Low:    fixedpoint.MustNewFromString(stats.LowPrice),
-				Buy:    fixedpoint.MustNewFromString(stats.LastPrice),
-				Sell:   fixedpoint.MustNewFromString(stats.LastPrice),
-				Time:   time.Unix(0, stats.CloseTime*int64(time.Millisecond)),
+			tick, err := toGlobalFuturesTicker(stats)
+			if err != nil {
+				return nil, err
 			}
 
-			tickers[stats.Symbol] = tick
+			tickers[stats.Symbol] = *tick
 		}
 
 		return tickers, nil
@@ -243,18 +256,12 @@ func (e *Exchange) QueryTickers(ctx context.Context, symbol ...string) (map[stri
 			continue
 		}
 
-		tick := types.Ticker{
-			Volume: fixedpoint.MustNewFromString(stats.Volume),
-			Last:   fixedpoint.MustNewFromString(stats.LastPrice),
-			Open:   fixedpoint.MustNewFromString(stats.OpenPrice),
-			High:   fixedpoint.MustNewFromString(stats.HighPrice),
-			Low:    fixedpoint.MustNewFromString(stats.LowPrice),
-			Buy:    fixedpoint.MustNewFromString(stats.BidPrice),
-			Sell:   fixedpoint.MustNewFromString(stats.AskPrice),
-			Time:   time.Unix(0, stats.CloseTime*int64(time.Millisecond)),
+		tick, err := toGlobalTicker(stats)
+		if err != nil {
+			return nil, err
 		}
 
-		tickers[stats.Symbol] = tick
+		tickers[stats.Symbol] = *tick
 	}
 
 	return tickers, nil
@@ -289,6 +296,40 @@ func (e *Exchange) QueryMarkets(ctx context.Context) (types.MarketMap, error) {
 	return markets, nil
 }
 
+// QuerySymbolStatus queries the trading status of the symbols from the exchange info,
+// the perpetual contracts with a delivery date are the contracts announced to be delisted.
+func (e *Exchange) QuerySymbolStatus(ctx context.Context) (map[string]types.SymbolStatus, error) {
+	statuses := make(map[string]types.SymbolStatus)
+
+	if e.IsFutures {
+		exchangeInfo, err := e.futuresClient.NewExchangeInfoService().Do(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, symbol := range exchangeInfo.Symbols {
+			statuses[symbol.Symbol] = toGlobalFuturesSymbolStatus(symbol)
+		}
+
+		return statuses, nil
+	}
+
+	exchangeInfo, err := e.client.NewExchangeInfoService().Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, symbol := range exchangeInfo.Symbols {
+		statuses[symbol.Symbol] = types.SymbolStatus{
+			Symbol:  symbol.Symbol,
+			Status:  toGlobalSymbolTradingStatus(symbol.Status),
+			Message: symbol.Status,
+		}
+	}
+
+	return statuses, nil
+}
+
 func (e *Exchange) QueryAveragePrice(ctx context.Context, symbol string) (fixedpoint.Value, error) {
 	resp, err := e.client.NewAveragePriceService().Symbol(symbol).Do(ctx)
 	if err != nil {
@@ -690,7 +731,9 @@ func (e *Exchange) QuerySpotAccount(ctx context.Context) (*types.Account, error)
 func (e *Exchange) QueryAccount(ctx context.Context) (*types.Account, error) {
 	var account *types.Account
 	var err error
-	if e.IsFutures {
+	if e.usePortfolioMargin(ctx) {
+		account, err = e.QueryPortfolioMarginAccount(ctx)
+	} else if e.IsFutures {
 		account, err = e.QueryFuturesAccount(ctx)
 	} else if e.IsIsolatedMargin {
 		account, err = e.QueryIsolatedMarginAccount(ctx)
@@ -704,6 +747,10 @@ func (e *Exchange) QueryAccount(ctx context.Context) (*types.Account, error) {
 }
 
 func (e *Exchange) QueryOpenOrders(ctx context.Context, symbol string) (orders []types.Order, err error) {
+	if e.usePortfolioMargin(ctx) {
+		return e.queryPortfolioMarginOpenOrders(ctx, symbol)
+	}
+
 	if e.IsMargin {
 		req := e.client.NewListMarginOpenOrdersService().Symbol(symbol)
 		req.IsIsolated(e.IsIsolatedMargin)
@@ -861,6 +908,10 @@ func (e *Exchange) CancelOrders(ctx context.Context, orders ...types.Order) (err
 		return err
 	}
 
+	if e.usePortfolioMargin(ctx) {
+		return e.cancelPortfolioMarginOrders(ctx, orders...)
+	}
+
 	if e.IsFutures {
 		return e.cancelFuturesOrders(ctx, orders...)
 	}
@@ -888,6 +939,23 @@ func (e *Exchange) CancelOrders(ctx context.Context, orders ...types.Order) (err
 			}
 		} else {
 			// SPOT
+			if o.Type == types.OrderTypeOCO && o.OrderListID > 0 {
+				if err2 := e.cancelSpotOCOOrder(ctx, o); err2 != nil {
+					err = multierr.Append(err, types.NewOrderError(err2, o))
+				}
+				continue
+			}
+
+			if e.WebSocketOrderEntryAvailable() {
+				err2 := e.cancelSpotOrderByWebSocket(ctx, o)
+				if err2 == nil {
+					continue
+				}
+
+				// the cancel request might be sent, the order will be canceled again through the rest api
+				log.WithError(err2).Warnf("unable to cancel the order through the websocket api, falling back to the rest api: %s", o.String())
+			}
+
 			var req = e.client.NewCancelOrderService()
 			req.Symbol(o.Symbol)
 
@@ -923,6 +991,10 @@ func (e *Exchange) submitMarginOrder(ctx context.Context, order types.SubmitOrde
 		Type(orderType).
 		Side(binance.SideType(order.Side))
 
+	if len(order.SelfTradePreventionMode) > 0 {
+		log.Warnf("self trade prevention mode %s is not supported by the margin order, ignored", order.SelfTradePreventionMode)
+	}
+
 	clientOrderID := newSpotClientOrderID(order.ClientOrderID)
 	if len(clientOrderID) > 0 {
 		req.NewClientOrderID(clientOrderID)
@@ -1043,6 +1115,11 @@ func (e *Exchange) submitSpotOrder(ctx context.Context, order types.SubmitOrder)
 		return nil, err
 	}
 
+	// the go-binance client doesn't support the self trade prevention mode parameter
+	if len(order.SelfTradePreventionMode) > 0 {
+		return e.submitSpotOrderWithSelfTradePrevention(ctx, order, orderType)
+	}
+
 	req := e.client.NewCreateOrderService().
 		Symbol(order.Symbol).
 		Side(binance.SideType(order.Side)).
@@ -1120,13 +1197,83 @@ func (e *Exchange) submitSpotOrder(ctx context.Context, order types.SubmitOrder)
 	return createdOrder, err
 }
 
+// submitSpotOrderWithSelfTradePrevention submits the spot order through our own api client
+// with the selfTradePreventionMode parameter
+func (e *Exchange) submitSpotOrderWithSelfTradePrevention(
+	ctx context.Context, order types.SubmitOrder, orderType binance.OrderType,
+) (*types.Order, error) {
+	req := e.client2.NewPlaceSpotOrderRequest().
+		Symbol(order.Symbol).
+		Side(binance.SideType(order.Side)).
+		OrderType(orderType).
+		SelfTradePreventionMode(string(order.SelfTradePreventionMode)).
+		NewOrderRespType(binanceapi.Result)
+
+	clientOrderID := newSpotClientOrderID(order.ClientOrderID)
+	if len(clientOrderID) > 0 {
+		req.NewClientOrderId(clientOrderID)
+	}
+
+	if order.Market.Symbol != "" {
+		req.Quantity(order.Market.FormatQuantity(order.Quantity))
+	} else {
+		req.Quantity(order.Quantity.FormatString(8))
+	}
+
+	switch order.Type {
+	case types.OrderTypeStopLimit, types.OrderTypeLimit, types.OrderTypeLimitMaker:
+		if order.Market.Symbol != "" {
+			req.Price(order.Market.FormatPrice(order.Price))
+		} else {
+			req.Price(order.Price.FormatString(8))
+		}
+	}
+
+	switch order.Type {
+	case types.OrderTypeStopLimit, types.OrderTypeStopMarket:
+		if order.Market.Symbol != "" {
+			req.StopPrice(order.Market.FormatPrice(order.StopPrice))
+		} else {
+			req.StopPrice(order.StopPrice.FormatString(8))
+		}
+	}
+
+	if len(order.TimeInForce) > 0 {
+		req.TimeInForce(string(order.TimeInForce))
+	} else {
+		switch order.Type {
+		case types.OrderTypeLimit, types.OrderTypeStopLimit:
+			req.TimeInForce(string(binance.TimeInForceTypeGTC))
+		}
+	}
+
+	response, err := req.Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Infof("spot order creation response: %+v", response)
+
+	return toGlobalSpotOrder(response)
+}
+
 func (e *Exchange) SubmitOrder(ctx context.Context, order types.SubmitOrder) (createdOrder *types.Order, err error) {
 	if err = orderLimiter.Wait(ctx); err != nil {
 		log.WithError(err).Errorf("order rate limiter wait error")
 		return nil, err
 	}
 
-	if e.IsMargin {
+	if order.Type == types.OrderTypeOCO {
+		if e.IsMargin || e.IsFutures {
+			return nil, fmt.Errorf("oco order is only supported by the spot account: %+v", order)
+		}
+
+		return e.submitSpotOCOOrder(ctx, order)
+	}
+
+	if e.usePortfolioMargin(ctx) {
+		createdOrder, err = e.submitPortfolioMarginOrder(ctx, order)
+	} else if e.IsMargin {
 		createdOrder, err = e.submitMarginOrder(ctx, order)
 	} else if e.IsFutures {
 		createdOrder, err = e.submitFuturesOrder(ctx, order)
@@ -1405,6 +1552,19 @@ func convertDepthLegacy(
 }
 
 // QueryPremiumIndex is only for futures
+// QuerySystemStatus queries the system status, the status is 1 when the exchange is under the system maintenance
+func (e *Exchange) QuerySystemStatus(ctx context.Context) (*types.SystemStatus, error) {
+	status, err := e.client2.NewGetSystemStatusRequest().Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.SystemStatus{
+		Maintenance: status.Status == 1,
+		Message:     status.Msg,
+	}, nil
+}
+
 func (e *Exchange) QueryPremiumIndex(ctx context.Context, symbol string) (*types.PremiumIndex, error) {
 	// when symbol is set, only one index will be returned.
 	indexes, err := e.futuresClient.NewPremiumIndexService().Symbol(symbol).Do(ctx)
@@ -1442,6 +1602,10 @@ func (e *Exchange) QueryFundingRateHistory(ctx context.Context, symbol string) (
 }
 
 func (e *Exchange) QueryPositionRisk(ctx context.Context, symbol string) (*types.PositionRisk, error) {
+	if e.usePortfolioMargin(ctx) {
+		return e.queryPortfolioMarginPositionRisk(ctx, symbol)
+	}
+
 	// when symbol is set, only one position risk will be returned.
 	risks, err := e.futuresClient.NewGetPositionRiskService().Symbol(symbol).Do(ctx)
 	if err != nil {
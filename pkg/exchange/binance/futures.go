@@ -198,7 +198,14 @@ func (e *Exchange) submitFuturesOrder(ctx context.Context, order types.SubmitOrd
 		}
 	}
 
-	response, err := req.Do(ctx)
+	var options []futures.RequestOption
+	if len(order.SelfTradePreventionMode) > 0 {
+		options = append(options, futures.WithExtraForm(map[string]any{
+			"selfTradePreventionMode": string(order.SelfTradePreventionMode),
+		}))
+	}
+
+	response, err := req.Do(ctx, options...)
 	if err != nil {
 		return nil, err
 	}
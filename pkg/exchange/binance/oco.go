@@ -0,0 +1,100 @@
+package binance
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/adshao/go-binance/v2"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// submitSpotOCOOrder places the OCO order list of a limit maker order at the order price and a stop market order
+// triggered at the order stop price. The returned order is the limit maker order, the stop order is the linked order.
+func (e *Exchange) submitSpotOCOOrder(ctx context.Context, order types.SubmitOrder) (*types.Order, error) {
+	if order.Price.IsZero() || order.StopPrice.IsZero() {
+		return nil, fmt.Errorf("oco order requires both price and stop price: %+v", order)
+	}
+
+	req := e.client.NewCreateOCOService().
+		Symbol(order.Symbol).
+		Side(binance.SideType(order.Side))
+
+	clientOrderID := newSpotClientOrderID(order.ClientOrderID)
+	if len(clientOrderID) > 0 {
+		req.ListClientOrderID(clientOrderID)
+	}
+
+	if order.Market.Symbol != "" {
+		req.Quantity(order.Market.FormatQuantity(order.Quantity))
+		req.Price(order.Market.FormatPrice(order.Price))
+		req.StopPrice(order.Market.FormatPrice(order.StopPrice))
+	} else {
+		req.Quantity(order.Quantity.FormatString(8))
+		req.Price(order.Price.FormatString(8))
+		req.StopPrice(order.StopPrice.FormatString(8))
+	}
+
+	req.NewOrderRespType(binance.NewOrderRespTypeRESULT)
+
+	response, err := req.Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Infof("spot oco order creation response: %+v", response)
+
+	return toGlobalOCOOrder(response)
+}
+
+// toGlobalOCOOrder converts the order reports of the OCO order list into the OCO order,
+// the limit maker order is the order and the stop order is the linked order
+func toGlobalOCOOrder(response *binance.CreateOCOResponse) (*types.Order, error) {
+	var limitReport, stopReport *binance.OCOOrderReport
+	for _, report := range response.OrderReports {
+		switch report.Type {
+		case binance.OrderTypeLimitMaker:
+			limitReport = report
+		default:
+			stopReport = report
+		}
+	}
+
+	if limitReport == nil || stopReport == nil {
+		return nil, fmt.Errorf("unexpected oco order reports of order list %d: %+v", response.OrderListID, response.OrderReports)
+	}
+
+	return &types.Order{
+		SubmitOrder: types.SubmitOrder{
+			ClientOrderID: response.ListClientOrderID,
+			Symbol:        response.Symbol,
+			Side:          toGlobalSideType(limitReport.Side),
+			Type:          types.OrderTypeOCO,
+			Quantity:      fixedpoint.MustNewFromString(limitReport.OrigQuantity),
+			Price:         fixedpoint.MustNewFromString(limitReport.Price),
+			StopPrice:     fixedpoint.MustNewFromString(stopReport.StopPrice),
+			TimeInForce:   types.TimeInForce(limitReport.TimeInForce),
+		},
+		Exchange:         types.ExchangeBinance,
+		IsWorking:        true,
+		OrderID:          uint64(limitReport.OrderID),
+		Status:           toGlobalOrderStatus(limitReport.Status),
+		OriginalStatus:   string(limitReport.Status),
+		ExecutedQuantity: fixedpoint.MustNewFromString(limitReport.ExecutedQuantity),
+		CreationTime:     types.Time(millisecondTime(response.TransactionTime)),
+		UpdateTime:       types.Time(millisecondTime(response.TransactionTime)),
+		OrderListID:      uint64(response.OrderListID),
+		LinkedOrderIDs:   []uint64{uint64(stopReport.OrderID)},
+	}, nil
+}
+
+// cancelSpotOCOOrder cancels all the orders of the OCO order list
+func (e *Exchange) cancelSpotOCOOrder(ctx context.Context, o types.Order) error {
+	req := e.client.NewCancelOCOService().
+		Symbol(o.Symbol).
+		OrderListID(int64(o.OrderListID))
+
+	_, err := req.Do(ctx)
+	return err
+}
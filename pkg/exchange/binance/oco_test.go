@@ -0,0 +1,62 @@
+package binance
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/adshao/go-binance/v2"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func Test_toGlobalOCOOrder(t *testing.T) {
+	data := `{
+		"orderListId": 0,
+		"contingencyType": "OCO",
+		"listStatusType": "EXEC_STARTED",
+		"listOrderStatus": "EXECUTING",
+		"listClientOrderId": "x-NSUYEBKMtest",
+		"transactionTime": 1563417480525,
+		"symbol": "LTCBTC",
+		"orders": [
+			{"symbol": "LTCBTC", "orderId": 2, "clientOrderId": "Kk7sqHb9J6mJWTMDVW7Vos"},
+			{"symbol": "LTCBTC", "orderId": 3, "clientOrderId": "xTXKaGYd4bluPVp78IVRvl"}
+		],
+		"orderReports": [
+			{
+				"symbol": "LTCBTC", "orderId": 2, "orderListId": 7, "clientOrderId": "Kk7sqHb9J6mJWTMDVW7Vos",
+				"transactTime": 1563417480525, "price": "0.000000", "origQty": "0.624363", "executedQty": "0.000000",
+				"cummulativeQuoteQty": "0.000000", "status": "NEW", "timeInForce": "GTC", "type": "STOP_LOSS",
+				"side": "BUY", "stopPrice": "0.960664"
+			},
+			{
+				"symbol": "LTCBTC", "orderId": 3, "orderListId": 7, "clientOrderId": "xTXKaGYd4bluPVp78IVRvl",
+				"transactTime": 1563417480525, "price": "0.036435", "origQty": "0.624363", "executedQty": "0.000000",
+				"cummulativeQuoteQty": "0.000000", "status": "NEW", "timeInForce": "GTC", "type": "LIMIT_MAKER",
+				"side": "BUY"
+			}
+		]
+	}`
+
+	var response binance.CreateOCOResponse
+	assert.NoError(t, json.Unmarshal([]byte(data), &response))
+	response.OrderListID = 7
+
+	order, err := toGlobalOCOOrder(&response)
+	if assert.NoError(t, err) {
+		assert.Equal(t, types.OrderTypeOCO, order.Type)
+		assert.Equal(t, types.SideTypeBuy, order.Side)
+		assert.Equal(t, uint64(3), order.OrderID)
+		assert.Equal(t, uint64(7), order.OrderListID)
+		assert.Equal(t, []uint64{2}, order.LinkedOrderIDs)
+		assert.Equal(t, "0.036435", order.Price.String())
+		assert.Equal(t, "0.960664", order.StopPrice.String())
+		assert.Equal(t, types.OrderStatusNew, order.Status)
+	}
+
+	// the stop order report is missing
+	response.OrderReports = response.OrderReports[1:]
+	_, err = toGlobalOCOOrder(&response)
+	assert.Error(t, err)
+}
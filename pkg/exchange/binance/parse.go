@@ -118,6 +118,9 @@ type ExecutionReportEvent struct {
 	OrderID int64 `json:"i"`
 	Ignored int64 `json:"I"`
 
+	// OrderListID is -1 if the order is not in an order list
+	OrderListID int64 `json:"g"`
+
 	TradeID         int64 `json:"t"`
 	TransactionTime int64 `json:"T"`
 
@@ -140,7 +143,7 @@ func (e *ExecutionReportEvent) Order() (*types.Order, error) {
 	}
 
 	orderCreationTime := time.Unix(0, e.OrderCreationTime*int64(time.Millisecond))
-	return &types.Order{
+	order := &types.Order{
 		SubmitOrder: types.SubmitOrder{
 			ClientOrderID: e.ClientOrderID,
 			Symbol:        e.Symbol,
@@ -160,7 +163,13 @@ func (e *ExecutionReportEvent) Order() (*types.Order, error) {
 		ExecutedQuantity: e.CumulativeFilledQuantity,
 		CreationTime:     types.Time(orderCreationTime),
 		UpdateTime:       types.Time(orderCreationTime),
-	}, nil
+	}
+
+	if e.OrderListID > 0 {
+		order.OrderListID = uint64(e.OrderListID)
+	}
+
+	return order, nil
 }
 
 func (e *ExecutionReportEvent) Trade() (*types.Trade, error) {
@@ -523,12 +532,12 @@ func parseDepthEntry(val *fastjson.Value) (*types.PriceVolume, error) {
 		return nil, errors.New("incorrect depth entry element length")
 	}
 
-	price, err := fixedpoint.NewFromString(string(arr[0].GetStringBytes()))
+	price, err := fixedpoint.NewFromBytes(arr[0].GetStringBytes())
 	if err != nil {
 		return nil, err
 	}
 
-	quantity, err := fixedpoint.NewFromString(string(arr[1].GetStringBytes()))
+	quantity, err := fixedpoint.NewFromBytes(arr[1].GetStringBytes())
 	if err != nil {
 		return nil, err
 	}
@@ -597,6 +606,7 @@ type ForceOrderEvent struct {
 func (e *ForceOrderEvent) LiquidationInfo() types.LiquidationInfo {
 	o := e.Order
 	return types.LiquidationInfo{
+		Exchange:     types.ExchangeBinance,
 		Symbol:       o.Symbol,
 		Side:         types.SideType(o.Side),
 		OrderType:    types.OrderType(o.OrderType),
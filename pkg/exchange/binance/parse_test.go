@@ -204,6 +204,7 @@ func TestParseOrderUpdate(t *testing.T) {
 	assert.Equal(t, executionReport.CurrentOrderStatus, "NEW")
 	assert.Equal(t, executionReport.OrderID, int64(4293153))
 	assert.Equal(t, executionReport.Ignored, int64(8641984))
+	assert.Equal(t, executionReport.OrderListID, int64(-1))
 	assert.Equal(t, executionReport.TradeID, int64(-1))
 	assert.Equal(t, executionReport.TransactionTime, int64(1499405658657))
 	assert.Equal(t, executionReport.LastExecutedQuantity, fixedpoint.MustNewFromString("0.00000000"))
@@ -214,7 +215,9 @@ func TestParseOrderUpdate(t *testing.T) {
 
 	orderUpdate, err := executionReport.Order()
 	assert.NoError(t, err)
-	assert.NotNil(t, orderUpdate)
+	if assert.NotNil(t, orderUpdate) {
+		assert.Zero(t, orderUpdate.OrderListID)
+	}
 }
 
 func TestFuturesResponseParsing(t *testing.T) {
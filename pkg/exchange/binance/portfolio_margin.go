@@ -0,0 +1,405 @@
+package binance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/adshao/go-binance/v2/futures"
+	"go.uber.org/multierr"
+
+	"github.com/c9s/bbgo/pkg/exchange/binance/binanceapi"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+	"github.com/c9s/bbgo/pkg/util"
+)
+
+// the collateral rates are adjusted by binance occasionally
+const portfolioMarginCollateralRatesTTL = time.Hour
+
+// the portfolio margin account is liquidated when the uniMMR drops to 1.05
+var portfolioMarginLiquidationUniMMR = fixedpoint.NewFromFloat(1.05)
+
+// IsPortfolioMargin returns true when the account is in the portfolio margin mode.
+// The mode is detected by querying the portfolio margin account, it can be set by the BINANCE_PORTFOLIO_MARGIN env var.
+func (e *Exchange) IsPortfolioMargin(ctx context.Context) bool {
+	if v, ok := util.GetEnvVarBool("BINANCE_PORTFOLIO_MARGIN"); ok {
+		return v
+	}
+
+	// the portfolio margin is not available on the testnet and binance us
+	if util.IsPaperTrade() || isBinanceUs() || len(e.key) == 0 {
+		return false
+	}
+
+	e.portfolioMarginMu.Lock()
+	defer e.portfolioMarginMu.Unlock()
+
+	if e.portfolioMargin != nil {
+		return *e.portfolioMargin
+	}
+
+	_, err := e.portfolioMarginClient.NewPortfolioMarginGetAccountRequest().Do(ctx)
+	if err != nil {
+		// the account mode is detected again on the next call if the request is not responded
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) || ctx.Err() != nil {
+			log.WithError(err).Warnf("unable to detect the portfolio margin account mode")
+			return false
+		}
+	}
+
+	isPortfolioMargin := err == nil
+	e.portfolioMargin = &isPortfolioMargin
+	if isPortfolioMargin {
+		log.Infof("binance portfolio margin account mode is detected")
+	}
+
+	return isPortfolioMargin
+}
+
+// usePortfolioMargin returns true when the cross margin or the usdt-m futures requests are sent to the portfolio margin api
+func (e *Exchange) usePortfolioMargin(ctx context.Context) bool {
+	if e.IsIsolatedMargin || !(e.IsMargin || e.IsFutures) {
+		return false
+	}
+
+	return e.IsPortfolioMargin(ctx)
+}
+
+// QueryPortfolioMarginAccount queries the portfolio margin account, the balances are the cross margin balances
+// for the margin session, and the usdt-m futures balances for the futures session.
+func (e *Exchange) QueryPortfolioMarginAccount(ctx context.Context) (*types.Account, error) {
+	account, err := e.portfolioMarginClient.NewPortfolioMarginGetAccountRequest().Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	balances, err := e.portfolioMarginClient.NewPortfolioMarginGetBalanceRequest().Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	collateralRates, err := e.queryPortfolioMarginCollateralRates(ctx)
+	if err != nil {
+		log.WithError(err).Warnf("unable to query the portfolio margin collateral rates, the assets are counted in full")
+	}
+
+	a := &types.Account{
+		AccountType:         types.AccountTypeMargin,
+		PortfolioMarginInfo: toGlobalPortfolioMarginAccountInfo(account, balances, collateralRates),
+		MarginLevel:         account.UniMMR,
+		MarginTolerance:     calculatePortfolioMarginTolerance(account.UniMMR),
+		BorrowEnabled:       true,
+		CanTrade:            true,
+	}
+
+	if e.IsFutures {
+		a.AccountType = types.AccountTypeFutures
+	}
+
+	a.UpdateBalances(toGlobalPortfolioMarginBalances(balances, e.IsFutures))
+	return a, nil
+}
+
+// queryPortfolioMarginCollateralRates returns the cached collateral rates, the rates are queried again after the ttl
+func (e *Exchange) queryPortfolioMarginCollateralRates(ctx context.Context) (map[string]fixedpoint.Value, error) {
+	e.portfolioMarginMu.Lock()
+	defer e.portfolioMarginMu.Unlock()
+
+	if e.collateralRates != nil && time.Since(e.collateralRatesUpdateTime) < portfolioMarginCollateralRatesTTL {
+		return e.collateralRates, nil
+	}
+
+	rates, err := e.client2.NewGetPortfolioMarginCollateralRateRequest().Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	collateralRates := make(map[string]fixedpoint.Value, len(rates))
+	for _, rate := range rates {
+		collateralRates[rate.Asset] = rate.CollateralRate
+	}
+
+	e.collateralRates = collateralRates
+	e.collateralRatesUpdateTime = time.Now()
+	return collateralRates, nil
+}
+
+func (e *Exchange) portfolioMarginMarket() binanceapi.PortfolioMarginMarket {
+	if e.IsFutures {
+		return binanceapi.PortfolioMarginMarketUM
+	}
+
+	return binanceapi.PortfolioMarginMarketMargin
+}
+
+func (e *Exchange) submitPortfolioMarginOrder(ctx context.Context, order types.SubmitOrder) (*types.Order, error) {
+	req := e.portfolioMarginClient.NewPortfolioMarginPlaceOrderRequest().
+		Market(e.portfolioMarginMarket()).
+		Symbol(order.Symbol).
+		Side(binanceapi.SideType(order.Side))
+
+	var clientOrderID string
+	if e.IsFutures {
+		orderType, err := toLocalFuturesOrderType(order.Type)
+		if err != nil {
+			return nil, err
+		}
+
+		req.OrderType(binanceapi.OrderType(orderType))
+		clientOrderID = newFuturesClientOrderID(order.ClientOrderID)
+
+		if order.ReduceOnly {
+			req.ReduceOnly(order.ReduceOnly)
+		}
+	} else {
+		orderType, err := toLocalOrderType(order.Type)
+		if err != nil {
+			return nil, err
+		}
+
+		req.OrderType(orderType)
+		clientOrderID = newSpotClientOrderID(order.ClientOrderID)
+
+		if len(order.MarginSideEffect) > 0 {
+			req.SideEffectType(string(order.MarginSideEffect))
+		}
+	}
+
+	if len(clientOrderID) > 0 {
+		req.NewClientOrderId(clientOrderID)
+	}
+
+	if len(order.SelfTradePreventionMode) > 0 {
+		req.SelfTradePreventionMode(string(order.SelfTradePreventionMode))
+	}
+
+	// use response result format
+	req.NewOrderRespType(binanceapi.Result)
+
+	if order.Market.Symbol != "" {
+		req.Quantity(order.Market.FormatQuantity(order.Quantity))
+	} else {
+		req.Quantity(order.Quantity.FormatString(8))
+	}
+
+	// set price field for limit orders
+	switch order.Type {
+	case types.OrderTypeStopLimit, types.OrderTypeLimit, types.OrderTypeLimitMaker:
+		if order.Market.Symbol != "" {
+			req.Price(order.Market.FormatPrice(order.Price))
+		} else {
+			req.Price(order.Price.FormatString(8))
+		}
+	}
+
+	// set stop price
+	switch order.Type {
+	case types.OrderTypeStopLimit, types.OrderTypeStopMarket:
+		if order.Market.Symbol != "" {
+			req.StopPrice(order.Market.FormatPrice(order.StopPrice))
+		} else {
+			req.StopPrice(order.StopPrice.FormatString(8))
+		}
+	}
+
+	if len(order.TimeInForce) > 0 {
+		req.TimeInForce(string(order.TimeInForce))
+	} else {
+		switch order.Type {
+		case types.OrderTypeLimit, types.OrderTypeStopLimit:
+			req.TimeInForce(string(futures.TimeInForceTypeGTC))
+
+		case types.OrderTypeLimitMaker:
+			// the usdt-m futures limit maker order is the post only limit order
+			if e.IsFutures {
+				req.TimeInForce(string(futures.TimeInForceTypeGTX))
+			}
+		}
+	}
+
+	response, err := req.Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Infof("portfolio margin order creation response: %+v", response)
+
+	return toGlobalPortfolioMarginOrder(response, e.IsFutures)
+}
+
+func (e *Exchange) cancelPortfolioMarginOrders(ctx context.Context, orders ...types.Order) (err error) {
+	for _, o := range orders {
+		req := e.portfolioMarginClient.NewPortfolioMarginCancelOrderRequest().
+			Market(e.portfolioMarginMarket()).
+			Symbol(o.Symbol)
+
+		if o.OrderID > 0 {
+			req.OrderId(o.OrderID)
+		} else if len(o.ClientOrderID) > 0 {
+			req.OrigClientOrderId(o.ClientOrderID)
+		} else {
+			err = multierr.Append(err, types.NewOrderError(
+				fmt.Errorf("can not cancel %s order, order does not contain orderID or clientOrderID", o.Symbol),
+				o))
+			continue
+		}
+
+		if _, err2 := req.Do(ctx); err2 != nil {
+			err = multierr.Append(err, types.NewOrderError(err2, o))
+		}
+	}
+
+	return err
+}
+
+func (e *Exchange) queryPortfolioMarginOpenOrders(ctx context.Context, symbol string) (orders []types.Order, err error) {
+	req := e.portfolioMarginClient.NewPortfolioMarginGetOpenOrdersRequest().
+		Market(e.portfolioMarginMarket()).
+		Symbol(symbol)
+
+	openOrders, err := req.Do(ctx)
+	if err != nil {
+		return orders, err
+	}
+
+	for _, o := range openOrders {
+		order, err := toGlobalPortfolioMarginOrder(&o, e.IsFutures)
+		if err != nil {
+			return orders, err
+		}
+
+		orders = append(orders, *order)
+	}
+
+	return orders, nil
+}
+
+func (e *Exchange) queryPortfolioMarginPositionRisk(ctx context.Context, symbol string) (*types.PositionRisk, error) {
+	if !e.IsFutures {
+		return nil, fmt.Errorf("position risk is only available for the futures account")
+	}
+
+	risks, err := e.portfolioMarginClient.NewPortfolioMarginGetUMPositionRiskRequest().Symbol(symbol).Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(risks) == 0 {
+		return nil, fmt.Errorf("position risk of %s not found", symbol)
+	}
+
+	return &types.PositionRisk{
+		Leverage:         risks[0].Leverage,
+		LiquidationPrice: risks[0].LiquidationPrice,
+	}, nil
+}
+
+func toGlobalPortfolioMarginAccountInfo(
+	account *binanceapi.PortfolioMarginAccount, balances []binanceapi.PortfolioMarginBalance,
+	collateralRates map[string]fixedpoint.Value,
+) *types.PortfolioMarginAccountInfo {
+	assets := make(types.PortfolioMarginAssetMap, len(balances))
+	for _, b := range balances {
+		assets[b.Asset] = types.PortfolioMarginAsset{
+			Asset:            b.Asset,
+			WalletBalance:    b.TotalWalletBalance,
+			Borrowed:         b.CrossMarginBorrowed,
+			Interest:         b.CrossMarginInterest,
+			UnrealizedProfit: b.UMUnrealizedPNL.Add(b.CMUnrealizedPNL),
+		}
+	}
+
+	return &types.PortfolioMarginAccountInfo{
+		UniMMR:                account.UniMMR,
+		AccountEquity:         account.AccountEquity,
+		ActualEquity:          account.ActualEquity,
+		AccountInitialMargin:  account.AccountInitialMargin,
+		AccountMaintMargin:    account.AccountMaintMargin,
+		AccountStatus:         account.AccountStatus,
+		TotalAvailableBalance: account.TotalAvailableBalance,
+		Assets:                assets,
+		CollateralRates:       collateralRates,
+	}
+}
+
+// toGlobalPortfolioMarginBalances converts the cross margin balances for the margin session,
+// and the usdt-m futures wallet balances with the unrealized profit for the futures session
+func toGlobalPortfolioMarginBalances(balances []binanceapi.PortfolioMarginBalance, isFutures bool) types.BalanceMap {
+	retBalances := make(types.BalanceMap, len(balances))
+	for _, b := range balances {
+		if isFutures {
+			retBalances[b.Asset] = types.Balance{
+				Currency:  b.Asset,
+				Available: b.UMWalletBalance.Add(b.UMUnrealizedPNL),
+			}
+			continue
+		}
+
+		retBalances[b.Asset] = types.Balance{
+			Currency:  b.Asset,
+			Available: b.CrossMarginFree,
+			Locked:    b.CrossMarginLocked,
+			Borrowed:  b.CrossMarginBorrowed,
+			Interest:  b.CrossMarginInterest,
+			NetAsset:  b.CrossMarginAsset.Sub(b.CrossMarginBorrowed).Sub(b.CrossMarginInterest),
+		}
+	}
+
+	return retBalances
+}
+
+func toGlobalPortfolioMarginOrder(o *binanceapi.PortfolioMarginOrder, isFutures bool) (*types.Order, error) {
+	orderType := toGlobalOrderType(o.Type)
+	if isFutures {
+		orderType = toGlobalFuturesOrderType(futures.OrderType(o.Type))
+	}
+
+	// the margin order creation response only contains the transaction time
+	creationTime := o.Time
+	if creationTime.Time().IsZero() {
+		creationTime = o.TransactTime
+	}
+
+	updateTime := o.UpdateTime
+	if updateTime.Time().IsZero() {
+		updateTime = creationTime
+	}
+
+	status := toGlobalOrderStatus(o.Status)
+	return &types.Order{
+		SubmitOrder: types.SubmitOrder{
+			ClientOrderID: o.ClientOrderID,
+			Symbol:        o.Symbol,
+			Side:          toGlobalSideType(o.Side),
+			Type:          orderType,
+			ReduceOnly:    o.ReduceOnly,
+			Quantity:      o.OrigQuantity,
+			Price:         o.Price,
+			StopPrice:     o.StopPrice,
+			TimeInForce:   types.TimeInForce(o.TimeInForce),
+		},
+		Exchange:         types.ExchangeBinance,
+		IsWorking:        status == types.OrderStatusNew || status == types.OrderStatusPartiallyFilled,
+		OrderID:          uint64(o.OrderID),
+		Status:           status,
+		OriginalStatus:   string(o.Status),
+		ExecutedQuantity: o.ExecutedQuantity,
+		CreationTime:     types.Time(creationTime.Time()),
+		UpdateTime:       types.Time(updateTime.Time()),
+		IsMargin:         !isFutures,
+		IsFutures:        isFutures,
+	}, nil
+}
+
+// calculatePortfolioMarginTolerance returns 0 when the uniMMR drops to the liquidation level
+func calculatePortfolioMarginTolerance(uniMMR fixedpoint.Value) fixedpoint.Value {
+	if uniMMR.IsZero() {
+		return fixedpoint.Zero
+	}
+
+	return fixedpoint.One.Sub(portfolioMarginLiquidationUniMMR.Div(uniMMR))
+}
@@ -0,0 +1,158 @@
+package binance
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/exchange/binance/binanceapi"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestExchange_IsPortfolioMargin(t *testing.T) {
+	testCases := []struct {
+		name     string
+		status   int
+		expected bool
+	}{
+		{name: "portfolio margin account", status: http.StatusOK, expected: true},
+		{name: "classic account", status: http.StatusUnauthorized, expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			requests := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				assert.Equal(t, "/papi/v1/account", r.URL.Path)
+
+				w.WriteHeader(tc.status)
+				if tc.status == http.StatusOK {
+					_, _ = w.Write([]byte(`{"uniMMR": "5239.47", "accountStatus": "NORMAL"}`))
+				} else {
+					_, _ = w.Write([]byte(`{"code": -2015, "msg": "Invalid API-key, IP, or permissions for action."}`))
+				}
+			}))
+			defer server.Close()
+
+			client := binanceapi.NewPortfolioMarginRestClient(server.URL)
+			client.Auth("key", "secret")
+
+			e := &Exchange{key: "key", portfolioMarginClient: client}
+			e.UseMargin()
+
+			ctx := context.Background()
+			assert.Equal(t, tc.expected, e.IsPortfolioMargin(ctx))
+			assert.Equal(t, tc.expected, e.usePortfolioMargin(ctx))
+
+			// the detected mode is cached
+			assert.Equal(t, 1, requests)
+
+			e.UseIsolatedMargin("BTCUSDT")
+			assert.False(t, e.usePortfolioMargin(ctx))
+		})
+	}
+}
+
+func Test_toGlobalPortfolioMarginOrder(t *testing.T) {
+	t.Run("margin order creation", func(t *testing.T) {
+		data := `{
+			"symbol": "BTCUSDT", "orderId": 28, "clientOrderId": "x-NSUYEBKMtest", "transactTime": 1507725176595,
+			"price": "40000.00", "origQty": "0.001", "executedQty": "0.000", "cummulativeQuoteQty": "0.00",
+			"status": "NEW", "timeInForce": "GTC", "type": "LIMIT", "side": "BUY"
+		}`
+
+		var response binanceapi.PortfolioMarginOrder
+		assert.NoError(t, json.Unmarshal([]byte(data), &response))
+
+		order, err := toGlobalPortfolioMarginOrder(&response, false)
+		if assert.NoError(t, err) {
+			assert.Equal(t, uint64(28), order.OrderID)
+			assert.Equal(t, types.OrderTypeLimit, order.Type)
+			assert.Equal(t, types.SideTypeBuy, order.Side)
+			assert.Equal(t, fixedpoint.NewFromFloat(40000.0), order.Price)
+			assert.Equal(t, types.OrderStatusNew, order.Status)
+			assert.True(t, order.IsWorking)
+			assert.True(t, order.IsMargin)
+			assert.False(t, order.IsFutures)
+			assert.Equal(t, int64(1507725176595), order.CreationTime.Time().UnixMilli())
+			assert.Equal(t, int64(1507725176595), order.UpdateTime.Time().UnixMilli())
+		}
+	})
+
+	t.Run("um open order", func(t *testing.T) {
+		data := `{
+			"symbol": "BTCUSDT", "orderId": 1917641, "clientOrderId": "x-testum", "price": "30000", "origQty": "0.40",
+			"executedQty": "0.10", "cumQuote": "3000", "status": "PARTIALLY_FILLED", "timeInForce": "GTC",
+			"type": "LIMIT", "side": "SELL", "reduceOnly": true, "positionSide": "BOTH",
+			"time": 1579276756075, "updateTime": 1579276756080
+		}`
+
+		var response binanceapi.PortfolioMarginOrder
+		assert.NoError(t, json.Unmarshal([]byte(data), &response))
+
+		order, err := toGlobalPortfolioMarginOrder(&response, true)
+		if assert.NoError(t, err) {
+			assert.Equal(t, types.OrderTypeLimit, order.Type)
+			assert.Equal(t, types.SideTypeSell, order.Side)
+			assert.Equal(t, types.OrderStatusPartiallyFilled, order.Status)
+			assert.Equal(t, fixedpoint.NewFromFloat(0.1), order.ExecutedQuantity)
+			assert.True(t, order.ReduceOnly)
+			assert.True(t, order.IsFutures)
+			assert.False(t, order.IsMargin)
+			assert.Equal(t, int64(1579276756075), order.CreationTime.Time().UnixMilli())
+			assert.Equal(t, int64(1579276756080), order.UpdateTime.Time().UnixMilli())
+		}
+	})
+}
+
+func Test_toGlobalPortfolioMarginBalances(t *testing.T) {
+	data := `[{
+		"asset": "USDT", "totalWalletBalance": "122607.35", "crossMarginAsset": "92.27",
+		"crossMarginBorrowed": "10.00", "crossMarginFree": "90.27", "crossMarginInterest": "0.01",
+		"crossMarginLocked": "2.00", "umWalletBalance": "122515.08", "umUnrealizedPNL": "-100.08",
+		"cmWalletBalance": "0", "cmUnrealizedPNL": "0", "updateTime": 0, "negativeBalance": "0"
+	}]`
+
+	var balances []binanceapi.PortfolioMarginBalance
+	assert.NoError(t, json.Unmarshal([]byte(data), &balances))
+
+	marginBalances := toGlobalPortfolioMarginBalances(balances, false)
+	if assert.Contains(t, marginBalances, "USDT") {
+		b := marginBalances["USDT"]
+		assert.Equal(t, fixedpoint.NewFromFloat(90.27), b.Available)
+		assert.Equal(t, fixedpoint.NewFromFloat(2.0), b.Locked)
+		assert.Equal(t, fixedpoint.NewFromFloat(10.0), b.Borrowed)
+		assert.Equal(t, fixedpoint.NewFromFloat(82.26), b.NetAsset)
+	}
+
+	futuresBalances := toGlobalPortfolioMarginBalances(balances, true)
+	if assert.Contains(t, futuresBalances, "USDT") {
+		assert.Equal(t, fixedpoint.NewFromFloat(122415.0), futuresBalances["USDT"].Available)
+	}
+
+	account := &binanceapi.PortfolioMarginAccount{
+		UniMMR:             fixedpoint.NewFromFloat(2.1),
+		AccountMaintMargin: fixedpoint.NewFromFloat(100.0),
+	}
+
+	info := toGlobalPortfolioMarginAccountInfo(account, balances, map[string]fixedpoint.Value{
+		"USDT": fixedpoint.One,
+		"BTC":  fixedpoint.NewFromFloat(0.95),
+	})
+
+	if assert.Contains(t, info.Assets, "USDT") {
+		// 122607.35 - 100.08 - 10.00 - 0.01
+		assert.Equal(t, fixedpoint.NewFromFloat(122497.26), info.Assets["USDT"].Equity())
+	}
+
+	assert.Equal(t, fixedpoint.NewFromFloat(0.95), info.CollateralRate("BTC"))
+	assert.Equal(t, fixedpoint.One, info.CollateralRate("ETH"))
+
+	assert.Equal(t, "0.5", calculatePortfolioMarginTolerance(account.UniMMR).String())
+}
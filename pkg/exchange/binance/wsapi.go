@@ -0,0 +1,189 @@
+package binance
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/adshao/go-binance/v2"
+
+	"github.com/c9s/bbgo/pkg/exchange/binance/binanceapi"
+	"github.com/c9s/bbgo/pkg/types"
+	"github.com/c9s/bbgo/pkg/util"
+)
+
+const BinanceUSWsAPIURL = "wss://ws-api.binance.us:443/ws-api/v3"
+
+// EnableWebSocketOrderEntry opens the websocket api connection, the spot orders are placed and canceled through
+// the websocket api when the connection is established, and through the rest api otherwise.
+func (e *Exchange) EnableWebSocketOrderEntry(ctx context.Context) {
+	e.wsAPIOnce.Do(func() {
+		baseURL := binanceapi.WsAPIBaseURL
+		if util.IsPaperTrade() {
+			baseURL = binanceapi.SandboxWsAPIBaseURL
+		} else if isBinanceUs() {
+			baseURL = BinanceUSWsAPIURL
+		}
+
+		client := e.client2.NewWsAPIClient(baseURL)
+		e.wsAPIClient.Store(client)
+		go client.Run(ctx)
+	})
+}
+
+// WebSocketOrderEntryAvailable returns true when the websocket api is connected, only the spot orders are supported
+func (e *Exchange) WebSocketOrderEntryAvailable() bool {
+	if e.IsMargin || e.IsFutures {
+		return false
+	}
+
+	client := e.wsAPIClient.Load()
+	return client != nil && client.Connected()
+}
+
+// SubmitOrderByWebSocket places the spot order through the websocket api
+func (e *Exchange) SubmitOrderByWebSocket(ctx context.Context, order types.SubmitOrder) (*types.Order, error) {
+	if !e.WebSocketOrderEntryAvailable() {
+		return nil, types.ErrWebSocketOrderEntryUnavailable
+	}
+
+	// the order lists are placed through the rest api
+	if order.Type == types.OrderTypeOCO {
+		return nil, fmt.Errorf("oco order is not supported by the websocket api: %w", types.ErrWebSocketOrderEntryUnavailable)
+	}
+
+	orderType, err := toLocalOrderType(order.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := orderLimiter.Wait(ctx); err != nil {
+		log.WithError(err).Errorf("order rate limiter wait error")
+		return nil, err
+	}
+
+	params := toWsAPIOrderParams(order, orderType)
+	params["newOrderRespType"] = binanceapi.Result
+
+	response, err := e.wsAPIClient.Load().PlaceOrder(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Infof("spot order creation response: %+v", response)
+
+	return toGlobalSpotOrder(response)
+}
+
+// cancelSpotOrderByWebSocket cancels the spot order through the websocket api
+func (e *Exchange) cancelSpotOrderByWebSocket(ctx context.Context, o types.Order) error {
+	if o.OrderID == 0 && len(o.ClientOrderID) == 0 {
+		return types.NewOrderError(
+			fmt.Errorf("can not cancel %s order, order does not contain orderID or clientOrderID", o.Symbol), o)
+	}
+
+	_, err := e.wsAPIClient.Load().CancelOrder(ctx, o.Symbol, o.OrderID, o.ClientOrderID)
+	return err
+}
+
+// cancelReplaceByWebSocket cancels the spot order and places the new order of the same side through the websocket api
+func (e *Exchange) cancelReplaceByWebSocket(
+	ctx context.Context, cancelReplaceMode types.CancelReplaceModeType, o types.Order,
+) (*types.Order, error) {
+	orderType, err := toLocalOrderType(o.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	if o.OrderID == 0 {
+		return nil, types.NewOrderError(fmt.Errorf("cannot cancel %s order", o.Symbol), o)
+	}
+
+	submitOrder := o.SubmitOrder
+	submitOrder.ClientOrderID = ""
+
+	params := toWsAPIOrderParams(submitOrder, orderType)
+	params["cancelReplaceMode"] = cancelReplaceMode
+	params["cancelOrderId"] = o.OrderID
+	params["newOrderRespType"] = binanceapi.Full
+
+	response, err := e.wsAPIClient.Load().CancelReplaceOrder(ctx, params)
+	if response != nil && response.NewOrderResponse != nil {
+		return toGlobalOrder(response.NewOrderResponse, false)
+	}
+
+	return nil, err
+}
+
+// toWsAPIOrderParams converts the submit order into the order parameters of the websocket api,
+// which are the same as the parameters of the rest api
+func toWsAPIOrderParams(order types.SubmitOrder, orderType binance.OrderType) map[string]interface{} {
+	params := map[string]interface{}{
+		"symbol": order.Symbol,
+		"side":   binance.SideType(order.Side),
+		"type":   orderType,
+	}
+
+	if len(order.SelfTradePreventionMode) > 0 {
+		params["selfTradePreventionMode"] = order.SelfTradePreventionMode
+	}
+
+	clientOrderID := newSpotClientOrderID(order.ClientOrderID)
+	if len(clientOrderID) > 0 {
+		params["newClientOrderId"] = clientOrderID
+	}
+
+	if order.Market.Symbol != "" {
+		params["quantity"] = order.Market.FormatQuantity(order.Quantity)
+	} else {
+		params["quantity"] = order.Quantity.FormatString(8)
+	}
+
+	switch order.Type {
+	case types.OrderTypeStopLimit, types.OrderTypeLimit, types.OrderTypeLimitMaker:
+		if order.Market.Symbol != "" {
+			params["price"] = order.Market.FormatPrice(order.Price)
+		} else {
+			params["price"] = order.Price.FormatString(8)
+		}
+	}
+
+	switch order.Type {
+	case types.OrderTypeStopLimit, types.OrderTypeStopMarket:
+		if order.Market.Symbol != "" {
+			params["stopPrice"] = order.Market.FormatPrice(order.StopPrice)
+		} else {
+			params["stopPrice"] = order.StopPrice.FormatString(8)
+		}
+	}
+
+	if len(order.TimeInForce) > 0 {
+		params["timeInForce"] = order.TimeInForce
+	} else {
+		switch order.Type {
+		case types.OrderTypeLimit, types.OrderTypeStopLimit:
+			params["timeInForce"] = binance.TimeInForceTypeGTC
+		}
+	}
+
+	return params
+}
+
+// toGlobalSpotOrder converts the RESULT response of the spot order creation
+func toGlobalSpotOrder(response *binanceapi.PlaceSpotOrderResponse) (*types.Order, error) {
+	return toGlobalOrder(&binance.Order{
+		Symbol:                   response.Symbol,
+		OrderID:                  response.OrderID,
+		ClientOrderID:            response.ClientOrderID,
+		Price:                    response.Price,
+		OrigQuantity:             response.OrigQuantity,
+		ExecutedQuantity:         response.ExecutedQuantity,
+		CummulativeQuoteQuantity: response.CummulativeQuoteQuantity,
+		Status:                   response.Status,
+		TimeInForce:              response.TimeInForce,
+		Type:                     response.Type,
+		Side:                     response.Side,
+		UpdateTime:               response.TransactTime,
+		Time:                     response.TransactTime,
+		IsIsolated:               response.IsIsolated,
+	}, false)
+}
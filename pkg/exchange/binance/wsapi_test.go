@@ -0,0 +1,37 @@
+package binance
+
+import (
+	"testing"
+
+	"github.com/adshao/go-binance/v2"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func Test_toWsAPIOrderParams(t *testing.T) {
+	order := types.SubmitOrder{
+		Symbol:        "BTCUSDT",
+		Side:          types.SideTypeBuy,
+		Type:          types.OrderTypeLimit,
+		Quantity:      fixedpoint.NewFromFloat(0.001),
+		Price:         fixedpoint.NewFromFloat(40000.0),
+		ClientOrderID: "test",
+	}
+
+	params := toWsAPIOrderParams(order, binance.OrderTypeLimit)
+	assert.Equal(t, "BTCUSDT", params["symbol"])
+	assert.Equal(t, binance.SideTypeBuy, params["side"])
+	assert.Equal(t, binance.OrderTypeLimit, params["type"])
+	assert.Equal(t, "x-"+spotBrokerID+"test", params["newClientOrderId"])
+	assert.Equal(t, "0.00100000", params["quantity"])
+	assert.Equal(t, "40000.00000000", params["price"])
+	assert.Equal(t, binance.TimeInForceTypeGTC, params["timeInForce"])
+	assert.NotContains(t, params, "stopPrice")
+
+	order.Type = types.OrderTypeMarket
+	params = toWsAPIOrderParams(order, binance.OrderTypeMarket)
+	assert.NotContains(t, params, "price")
+	assert.NotContains(t, params, "timeInForce")
+}
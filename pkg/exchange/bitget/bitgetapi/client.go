@@ -16,6 +16,9 @@ import (
 
 	"github.com/c9s/requestgen"
 	"github.com/pkg/errors"
+
+	"github.com/c9s/bbgo/pkg/exchange/apistats"
+	"github.com/c9s/bbgo/pkg/types"
 )
 
 const defaultHTTPTimeout = time.Second * 15
@@ -39,7 +42,8 @@ func NewClient() *RestClient {
 		BaseAPIClient: requestgen.BaseAPIClient{
 			BaseURL: u,
 			HttpClient: &http.Client{
-				Timeout: defaultHTTPTimeout,
+				Timeout:   defaultHTTPTimeout,
+				Transport: apistats.NewTransport(types.ExchangeBitget, nil),
 			},
 		},
 	}
@@ -58,6 +58,9 @@ func toGlobalTicker(ticker v2.Ticker) types.Ticker {
 		Low:    ticker.Low24H,
 		Buy:    ticker.BidPr,
 		Sell:   ticker.AskPr,
+
+		BuySize:  ticker.BidSz,
+		SellSize: ticker.AskSz,
 	}
 }
 
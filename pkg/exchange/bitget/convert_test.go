@@ -146,6 +146,9 @@ func Test_toGlobalTicker(t *testing.T) {
 		Low:    fixedpoint.NewFromFloat(23677.75),
 		Buy:    fixedpoint.NewFromFloat(24013.94),
 		Sell:   fixedpoint.NewFromFloat(24014.06),
+
+		BuySize:  fixedpoint.NewFromFloat(0.0663),
+		SellSize: fixedpoint.NewFromFloat(0.0119),
 	}, toGlobalTicker(ticker))
 }
 
@@ -148,16 +148,13 @@ func (e *Exchange) QueryTicker(ctx context.Context, symbol string) (*types.Ticke
 
 func (e *Exchange) QueryTickers(ctx context.Context, symbols ...string) (map[string]types.Ticker, error) {
 	tickers := map[string]types.Ticker{}
-	if len(symbols) > 0 {
-		for _, s := range symbols {
-			t, err := e.QueryTicker(ctx, s)
-			if err != nil {
-				return nil, err
-			}
-
-			tickers[s] = *t
+	if len(symbols) == 1 {
+		t, err := e.QueryTicker(ctx, symbols[0])
+		if err != nil {
+			return nil, err
 		}
 
+		tickers[symbols[0]] = *t
 		return tickers, nil
 	}
 
@@ -174,7 +171,7 @@ func (e *Exchange) QueryTickers(ctx context.Context, symbols ...string) (map[str
 		tickers[s.Symbol] = toGlobalTicker(s)
 	}
 
-	return tickers, nil
+	return types.FilterTickers(tickers, symbols...), nil
 }
 
 // QueryKLines queries the k line data by interval and time range...etc.
@@ -122,6 +122,9 @@ func TestExchange_QueryTicker(t *testing.T) {
 			Low:    fixedpoint.MustNewFromString("64583.42"),
 			Buy:    fixedpoint.MustNewFromString("66554"),
 			Sell:   fixedpoint.MustNewFromString("66554.07"),
+
+			BuySize:  fixedpoint.MustNewFromString("0.000237"),
+			SellSize: fixedpoint.MustNewFromString("0.08228"),
 		}
 		assert.Equal(expTicker, tickers)
 	})
@@ -172,6 +175,9 @@ func TestExchange_QueryTickers(t *testing.T) {
 			Low:    fixedpoint.MustNewFromString("64583.42"),
 			Buy:    fixedpoint.MustNewFromString("66554"),
 			Sell:   fixedpoint.MustNewFromString("66554.07"),
+
+			BuySize:  fixedpoint.MustNewFromString("0.000237"),
+			SellSize: fixedpoint.MustNewFromString("0.08228"),
 		}
 	)
 
@@ -199,11 +205,36 @@ func TestExchange_QueryTickers(t *testing.T) {
 				Low:    fixedpoint.MustNewFromString("3461.17"),
 				Buy:    fixedpoint.MustNewFromString("3686.94"),
 				Sell:   fixedpoint.MustNewFromString("3686.98"),
+
+				BuySize:  fixedpoint.MustNewFromString("1.0046"),
+				SellSize: fixedpoint.MustNewFromString("1.0015"),
 			},
 		}
 		assert.Equal(expTickers, tickers)
 	})
 
+	t.Run("succeeds for query multiple markets in one request", func(t *testing.T) {
+		transport := &httptesting.MockTransport{}
+		ex.client.HttpClient.Transport = transport
+
+		f, err := os.ReadFile("bitgetapi/v2/testdata/get_tickers_request.json")
+		assert.NoError(err)
+
+		requests := 0
+		transport.GET(url, func(req *http.Request) (*http.Response, error) {
+			requests++
+			assert.NotContains(req.URL.Query(), "symbol")
+			return httptesting.BuildResponseString(http.StatusOK, string(f)), nil
+		})
+
+		tickers, err := ex.QueryTickers(context.Background(), expBtcSymbol, "LTCUSDT")
+		assert.NoError(err)
+		assert.Equal(1, requests)
+		assert.Equal(map[string]types.Ticker{
+			expBtcSymbol: expBtcTicker,
+		}, tickers)
+	})
+
 	t.Run("succeeds for query one markets", func(t *testing.T) {
 		transport := &httptesting.MockTransport{}
 		ex.client.HttpClient.Transport = transport
@@ -0,0 +1,16 @@
+package bitstampapi
+
+import (
+	"github.com/c9s/requestgen"
+)
+
+//go:generate requestgen -method POST -url "/api/v2/cancel_order/" -type CancelOrderRequest -responseType .Order
+type CancelOrderRequest struct {
+	client requestgen.AuthenticatedAPIClient
+
+	orderID string `param:"id"`
+}
+
+func (c *RestClient) NewCancelOrderRequest() *CancelOrderRequest {
+	return &CancelOrderRequest{client: c}
+}
@@ -0,0 +1,209 @@
+package bitstampapi
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/c9s/requestgen"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/c9s/bbgo/pkg/exchange/apistats"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+const (
+	defaultHTTPTimeout = time.Second * 15
+
+	RestBaseURL = "https://www.bitstamp.net"
+	WsBaseURL   = "wss://ws.bitstamp.net"
+
+	authVersion     = "v2"
+	formContentType = "application/x-www-form-urlencoded"
+)
+
+type RestClient struct {
+	requestgen.BaseAPIClient
+
+	key, secret string
+}
+
+func NewClient() *RestClient {
+	u, err := url.Parse(RestBaseURL)
+	if err != nil {
+		panic(err)
+	}
+
+	return &RestClient{
+		BaseAPIClient: requestgen.BaseAPIClient{
+			BaseURL: u,
+			HttpClient: &http.Client{
+				Timeout:   defaultHTTPTimeout,
+				Transport: apistats.NewTransport(types.ExchangeBitstamp, nil),
+			},
+		},
+	}
+}
+
+func (c *RestClient) Auth(key, secret string) {
+	c.key = key
+	// pragma: allowlist nextline secret
+	c.secret = secret
+}
+
+// NewRequest creates new http request for the public routes.
+func (c *RestClient) NewRequest(ctx context.Context, method, refURL string, params url.Values, payload interface{}) (*http.Request, error) {
+	rel, err := url.Parse(refURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		rel.RawQuery = params.Encode()
+	}
+
+	pathURL := c.BaseURL.ResolveReference(rel)
+	return http.NewRequestWithContext(ctx, method, pathURL.String(), nil)
+}
+
+// NewAuthenticatedRequest creates new http request for authenticated routes.
+//
+// See https://www.bitstamp.net/api/#section/Authentication
+//
+// The private routes are all POST requests with the url-encoded form body, the request is signed by
+//
+//	"BITSTAMP " + key + method + host + path + query + content type + nonce + timestamp + version + body
+//
+// The nonce is an uuid that can't be reused in 150 seconds, and the timestamp must be within 150 seconds of the server time.
+// The content type is omitted from both the header and the message when the body is empty.
+func (c *RestClient) NewAuthenticatedRequest(ctx context.Context, method, refURL string, params url.Values, payload interface{}) (*http.Request, error) {
+	if len(c.key) == 0 {
+		return nil, errors.New("empty api key")
+	}
+
+	if len(c.secret) == 0 {
+		return nil, errors.New("empty api secret")
+	}
+
+	rel, err := url.Parse(refURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		rel.RawQuery = params.Encode()
+	}
+
+	form := url.Values{}
+	if m, ok := payload.(map[string]interface{}); ok {
+		for k, v := range m {
+			form.Set(k, fmt.Sprintf("%v", v))
+		}
+	}
+
+	body := form.Encode()
+	contentType := ""
+	if len(body) > 0 {
+		contentType = formContentType
+	}
+
+	pathURL := c.BaseURL.ResolveReference(rel)
+	nonce := uuid.New().String()
+	timestamp := strconv.FormatInt(time.Now().UnixMilli(), 10)
+	message := SignatureMessage(c.key, method, pathURL, contentType, nonce, timestamp, body)
+
+	req, err := http.NewRequestWithContext(ctx, method, pathURL.String(), bytes.NewReader([]byte(body)))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(contentType) > 0 {
+		req.Header.Add("Content-Type", contentType)
+	}
+
+	req.Header.Add("X-Auth", "BITSTAMP "+c.key)
+	req.Header.Add("X-Auth-Signature", Sign(message, c.secret))
+	req.Header.Add("X-Auth-Nonce", nonce)
+	req.Header.Add("X-Auth-Timestamp", timestamp)
+	req.Header.Add("X-Auth-Version", authVersion)
+	return req, nil
+}
+
+// SendRequest sends the request and converts the error response into the APIError,
+// note that some of the errors are responded with the status code 200.
+func (c *RestClient) SendRequest(req *http.Request) (*requestgen.Response, error) {
+	response, err := c.BaseAPIClient.SendRequest(req)
+	if response == nil {
+		return response, err
+	}
+
+	if apiErr := ParseAPIError(response.Body); apiErr != nil {
+		return response, apiErr
+	}
+
+	return response, err
+}
+
+// SignatureMessage builds the message to be signed
+func SignatureMessage(key, method string, u *url.URL, contentType, nonce, timestamp, body string) string {
+	query := ""
+	if len(u.RawQuery) > 0 {
+		query = "?" + u.RawQuery
+	}
+
+	return "BITSTAMP " + key + method + u.Host + u.Path + query + contentType + nonce + timestamp + authVersion + body
+}
+
+// Sign signs the payload with the secret by HMAC SHA256 in the lowercase hex
+func Sign(payload string, secret string) string {
+	var sig = hmac.New(sha256.New, []byte(secret))
+	_, err := sig.Write([]byte(payload))
+	if err != nil {
+		return ""
+	}
+
+	return hex.EncodeToString(sig.Sum(nil))
+}
+
+/*
+APIError is the error response of the api, the reason could be a string or the errors of the fields, e.g.
+
+	{
+	  "status": "error",
+	  "reason": {"__all__": ["You have only 0.00000 USD available. Check your account balance for details."]},
+	  "code": "API0004"
+	}
+*/
+type APIError struct {
+	Status string          `json:"status"`
+	Reason json.RawMessage `json:"reason"`
+	Code   string          `json:"code"`
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("bitstamp api error, code: %s, reason: %s", e.Code, string(e.Reason))
+}
+
+// ParseAPIError parses the error response body, nil is returned if the body is not an api error
+func ParseAPIError(body []byte) *APIError {
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) == 0 || trimmed[0] != '{' {
+		return nil
+	}
+
+	var apiErr APIError
+	if err := json.Unmarshal(trimmed, &apiErr); err != nil || apiErr.Status != "error" {
+		return nil
+	}
+
+	return &apiErr
+}
@@ -0,0 +1,61 @@
+package bitstampapi
+
+import (
+	"encoding/json"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSign(t *testing.T) {
+	u, err := url.Parse(RestBaseURL + "/api/v2/user_transactions/")
+	assert.NoError(t, err)
+
+	message := SignatureMessage("apikey", "POST", u, formContentType,
+		"f93c979d-b00d-43a9-9b9c-fd4cd9547fa6", "1567755304968", "limit=100&offset=1")
+	assert.Equal(t, "BITSTAMP apikeyPOSTwww.bitstamp.net/api/v2/user_transactions/application/x-www-form-urlencoded"+
+		"f93c979d-b00d-43a9-9b9c-fd4cd9547fa61567755304968v2limit=100&offset=1", message)
+	assert.Equal(t, "be7825ea0c9f0f9b77d6f998aba77b113d3f5fbc917a661bde1a5a6c68fbf458", Sign(message, "apisecret"))
+}
+
+func TestSignatureMessage_Query(t *testing.T) {
+	u, err := url.Parse(RestBaseURL + "/api/v2/ohlc/btcusd/?step=60")
+	assert.NoError(t, err)
+
+	// the content type is omitted when the body is empty
+	message := SignatureMessage("apikey", "GET", u, "", "nonce", "1567755304968", "")
+	assert.Equal(t, "BITSTAMP apikeyGETwww.bitstamp.net/api/v2/ohlc/btcusd/?step=60nonce1567755304968v2", message)
+}
+
+func TestParseAPIError(t *testing.T) {
+	apiErr := ParseAPIError([]byte(`{"status": "error", "reason": {"__all__": ["Minimum order size is 10.0 USD."]}, "code": "API0011"}`))
+	if assert.NotNil(t, apiErr) {
+		assert.Equal(t, "API0011", apiErr.Code)
+		assert.Contains(t, apiErr.Error(), "Minimum order size")
+	}
+
+	assert.Nil(t, ParseAPIError([]byte(`[{"currency": "usd", "total": "1.0"}]`)))
+	assert.Nil(t, ParseAPIError([]byte(`{"id": "1234", "status": "Open"}`)))
+}
+
+func TestUserTransaction_UnmarshalJSON(t *testing.T) {
+	var txs []UserTransaction
+	err := json.Unmarshal([]byte(`[{
+		"id": 1234, "order_id": 5678, "datetime": "2022-01-31 14:43:15.796000", "type": "2", "fee": "0.62",
+		"usd": "-310.00", "btc": "0.01", "btc_usd": "31000.00", "eur": 0.0
+	}]`), &txs)
+	if assert.NoError(t, err) && assert.Len(t, txs, 1) {
+		tx := txs[0]
+		assert.Equal(t, uint64(1234), tx.ID)
+		assert.Equal(t, uint64(5678), tx.OrderID)
+		assert.Equal(t, TransactionTypeMarketTrade, tx.Type)
+		assert.Equal(t, time.Date(2022, 1, 31, 14, 43, 15, 796000000, time.UTC), tx.Datetime.Time())
+		assert.Equal(t, "0.62", tx.Fee.String())
+		assert.Equal(t, "0.01", tx.Amount("BTC").String())
+		assert.Equal(t, "-310", tx.Amount("USD").String())
+		assert.Equal(t, "31000", tx.Price("BTC", "USD").String())
+		assert.NotContains(t, tx.Amounts, "order_id")
+	}
+}
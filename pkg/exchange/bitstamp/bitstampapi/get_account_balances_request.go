@@ -0,0 +1,14 @@
+package bitstampapi
+
+import (
+	"github.com/c9s/requestgen"
+)
+
+//go:generate requestgen -method POST -url "/api/v2/account_balances/" -type GetAccountBalancesRequest -responseType []Balance
+type GetAccountBalancesRequest struct {
+	client requestgen.AuthenticatedAPIClient
+}
+
+func (c *RestClient) NewGetAccountBalancesRequest() *GetAccountBalancesRequest {
+	return &GetAccountBalancesRequest{client: c}
+}
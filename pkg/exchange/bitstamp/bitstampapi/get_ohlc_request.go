@@ -0,0 +1,46 @@
+package bitstampapi
+
+import (
+	"time"
+
+	"github.com/c9s/requestgen"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+type OHLC struct {
+	Timestamp types.StrInt64   `json:"timestamp"`
+	Open      fixedpoint.Value `json:"open"`
+	High      fixedpoint.Value `json:"high"`
+	Low       fixedpoint.Value `json:"low"`
+	Close     fixedpoint.Value `json:"close"`
+	Volume    fixedpoint.Value `json:"volume"`
+}
+
+type OHLCResponse struct {
+	Data struct {
+		Pair string `json:"pair"`
+		OHLC []OHLC `json:"ohlc"`
+	} `json:"data"`
+}
+
+//go:generate requestgen -method GET -url "/api/v2/ohlc/:pair/" -type GetOHLCRequest -responseType .OHLCResponse
+type GetOHLCRequest struct {
+	client requestgen.APIClient
+
+	pair string `param:"pair,slug"`
+
+	// step is the timeframe in seconds
+	step int `param:"step,query"`
+
+	// limit is the number of the candles, up to 1000
+	limit int `param:"limit,query"`
+
+	start *time.Time `param:"start,query,seconds"`
+	end   *time.Time `param:"end,query,seconds"`
+}
+
+func (c *RestClient) NewGetOHLCRequest() *GetOHLCRequest {
+	return &GetOHLCRequest{client: c}
+}
@@ -0,0 +1,18 @@
+package bitstampapi
+
+import (
+	"github.com/c9s/requestgen"
+)
+
+// GetOpenOrdersRequest queries the open orders of the pair, the pair "all" queries the open orders of all the pairs
+//
+//go:generate requestgen -method POST -url "/api/v2/open_orders/:pair/" -type GetOpenOrdersRequest -responseType []OpenOrder
+type GetOpenOrdersRequest struct {
+	client requestgen.AuthenticatedAPIClient
+
+	pair string `param:"pair,slug"`
+}
+
+func (c *RestClient) NewGetOpenOrdersRequest() *GetOpenOrdersRequest {
+	return &GetOpenOrdersRequest{client: c}
+}
@@ -0,0 +1,20 @@
+package bitstampapi
+
+import (
+	"github.com/c9s/requestgen"
+)
+
+// GetOrderStatusRequest queries the order by the id or the client order id,
+// the orders that are canceled without any trade are only available for a short time.
+//
+//go:generate requestgen -method POST -url "/api/v2/order_status/" -type GetOrderStatusRequest -responseType .OrderStatusResponse
+type GetOrderStatusRequest struct {
+	client requestgen.AuthenticatedAPIClient
+
+	orderID       *string `param:"id"`
+	clientOrderID *string `param:"client_order_id"`
+}
+
+func (c *RestClient) NewGetOrderStatusRequest() *GetOrderStatusRequest {
+	return &GetOrderStatusRequest{client: c}
+}
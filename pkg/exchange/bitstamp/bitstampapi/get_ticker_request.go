@@ -0,0 +1,42 @@
+package bitstampapi
+
+import (
+	"github.com/c9s/requestgen"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+type Ticker struct {
+	// Pair is only returned by the ticker list, e.g. BTC/USD
+	Pair      string           `json:"pair"`
+	Last      fixedpoint.Value `json:"last"`
+	High      fixedpoint.Value `json:"high"`
+	Low       fixedpoint.Value `json:"low"`
+	Vwap      fixedpoint.Value `json:"vwap"`
+	Volume    fixedpoint.Value `json:"volume"`
+	Bid       fixedpoint.Value `json:"bid"`
+	Ask       fixedpoint.Value `json:"ask"`
+	Open      fixedpoint.Value `json:"open"`
+	Timestamp types.StrInt64   `json:"timestamp"`
+}
+
+//go:generate requestgen -method GET -url "/api/v2/ticker/:pair/" -type GetTickerRequest -responseType .Ticker
+type GetTickerRequest struct {
+	client requestgen.APIClient
+
+	pair string `param:"pair,slug"`
+}
+
+func (c *RestClient) NewGetTickerRequest() *GetTickerRequest {
+	return &GetTickerRequest{client: c}
+}
+
+//go:generate requestgen -method GET -url "/api/v2/ticker/" -type GetTickersRequest -responseType []Ticker
+type GetTickersRequest struct {
+	client requestgen.APIClient
+}
+
+func (c *RestClient) NewGetTickersRequest() *GetTickersRequest {
+	return &GetTickersRequest{client: c}
+}
@@ -0,0 +1,27 @@
+package bitstampapi
+
+import (
+	"github.com/c9s/requestgen"
+)
+
+// TradingPair is the market of the pair, the minimum order is the quote amount with the currency, e.g. "10.0 USD"
+type TradingPair struct {
+	Name                        string `json:"name"`
+	URLSymbol                   string `json:"url_symbol"`
+	BaseDecimals                int    `json:"base_decimals"`
+	CounterDecimals             int    `json:"counter_decimals"`
+	InstantOrderCounterDecimals int    `json:"instant_order_counter_decimals"`
+	MinimumOrder                string `json:"minimum_order"`
+	Trading                     string `json:"trading"`
+	InstantAndMarketOrders      string `json:"instant_and_market_orders"`
+	Description                 string `json:"description"`
+}
+
+//go:generate requestgen -method GET -url "/api/v2/trading-pairs-info/" -type GetTradingPairsInfoRequest -responseType []TradingPair
+type GetTradingPairsInfoRequest struct {
+	client requestgen.APIClient
+}
+
+func (c *RestClient) NewGetTradingPairsInfoRequest() *GetTradingPairsInfoRequest {
+	return &GetTradingPairsInfoRequest{client: c}
+}
@@ -0,0 +1,31 @@
+package bitstampapi
+
+import (
+	"github.com/c9s/requestgen"
+)
+
+//go:generate requestgen -method POST -url "/api/v2/user_transactions/:pair/" -type GetUserTransactionsRequest -responseType []UserTransaction
+type GetUserTransactionsRequest struct {
+	client requestgen.AuthenticatedAPIClient
+
+	pair string `param:"pair,slug"`
+
+	offset *int `param:"offset"`
+
+	// limit is the number of the transactions, up to 1000
+	limit *int `param:"limit"`
+
+	sort *string `param:"sort" validValues:"asc,desc"`
+
+	// sinceTimestamp is the unix timestamp in seconds
+	sinceTimestamp *int64 `param:"since_timestamp"`
+
+	// untilTimestamp is the unix timestamp in seconds
+	untilTimestamp *int64 `param:"until_timestamp"`
+
+	sinceID *uint64 `param:"since_id"`
+}
+
+func (c *RestClient) NewGetUserTransactionsRequest() *GetUserTransactionsRequest {
+	return &GetUserTransactionsRequest{client: c}
+}
@@ -0,0 +1,21 @@
+package bitstampapi
+
+import (
+	"github.com/c9s/requestgen"
+)
+
+// WebsocketToken is the token for subscribing the private channels, the token is valid in ValidSec seconds
+type WebsocketToken struct {
+	Token    string `json:"token"`
+	ValidSec int    `json:"valid_sec"`
+	UserID   int64  `json:"user_id"`
+}
+
+//go:generate requestgen -method POST -url "/api/v2/websockets_token/" -type GetWebsocketTokenRequest -responseType .WebsocketToken
+type GetWebsocketTokenRequest struct {
+	client requestgen.AuthenticatedAPIClient
+}
+
+func (c *RestClient) NewGetWebsocketTokenRequest() *GetWebsocketTokenRequest {
+	return &GetWebsocketTokenRequest{client: c}
+}
@@ -0,0 +1,51 @@
+package bitstampapi
+
+import (
+	"github.com/c9s/requestgen"
+)
+
+// PlaceLimitOrderRequest places the limit order, the side is in the path, e.g. /api/v2/buy/btcusd/
+//
+//go:generate requestgen -method POST -url "/api/v2/:side/:pair/" -type PlaceLimitOrderRequest -responseType .Order
+type PlaceLimitOrderRequest struct {
+	client requestgen.AuthenticatedAPIClient
+
+	side string `param:"side,slug" validValues:"buy,sell"`
+	pair string `param:"pair,slug"`
+
+	amount string `param:"amount"`
+	price  string `param:"price"`
+
+	// iocOrder is the immediate-or-cancel order
+	iocOrder *bool `param:"ioc_order"`
+
+	// fokOrder is the fill-or-kill order
+	fokOrder *bool `param:"fok_order"`
+
+	// mocOrder is the maker-or-cancel order, the post only order
+	mocOrder *bool `param:"moc_order"`
+
+	clientOrderID *string `param:"client_order_id"`
+}
+
+func (c *RestClient) NewPlaceLimitOrderRequest() *PlaceLimitOrderRequest {
+	return &PlaceLimitOrderRequest{client: c}
+}
+
+// PlaceMarketOrderRequest places the market order, the amount is always the base amount
+//
+//go:generate requestgen -method POST -url "/api/v2/:side/market/:pair/" -type PlaceMarketOrderRequest -responseType .Order
+type PlaceMarketOrderRequest struct {
+	client requestgen.AuthenticatedAPIClient
+
+	side string `param:"side,slug" validValues:"buy,sell"`
+	pair string `param:"pair,slug"`
+
+	amount string `param:"amount"`
+
+	clientOrderID *string `param:"client_order_id"`
+}
+
+func (c *RestClient) NewPlaceMarketOrderRequest() *PlaceMarketOrderRequest {
+	return &PlaceMarketOrderRequest{client: c}
+}
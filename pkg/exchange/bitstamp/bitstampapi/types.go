@@ -0,0 +1,210 @@
+package bitstampapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+)
+
+// OrderType is the side of the order, "0" for buy and "1" for sell
+type OrderType string
+
+const (
+	OrderTypeBuy  OrderType = "0"
+	OrderTypeSell OrderType = "1"
+)
+
+// UnmarshalJSON accepts both the string and the numeric order type, the websocket events send the numeric ones
+func (t *OrderType) UnmarshalJSON(data []byte) error {
+	*t = OrderType(strings.Trim(string(data), `"`))
+	return nil
+}
+
+type OrderStatus string
+
+const (
+	OrderStatusOpen     OrderStatus = "Open"
+	OrderStatusFinished OrderStatus = "Finished"
+	OrderStatusExpired  OrderStatus = "Expired"
+	OrderStatusCanceled OrderStatus = "Canceled"
+)
+
+// TransactionType is the type of the user transaction
+type TransactionType string
+
+const (
+	TransactionTypeDeposit     TransactionType = "0"
+	TransactionTypeWithdrawal  TransactionType = "1"
+	TransactionTypeMarketTrade TransactionType = "2"
+)
+
+func (t *TransactionType) UnmarshalJSON(data []byte) error {
+	*t = TransactionType(strings.Trim(string(data), `"`))
+	return nil
+}
+
+const datetimeLayout = "2006-01-02 15:04:05.999999"
+
+// Datetime is the UTC datetime string of the api, e.g. 2022-01-31 14:43:15.796000
+type Datetime time.Time
+
+func (t *Datetime) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	if len(s) == 0 {
+		*t = Datetime(time.Time{})
+		return nil
+	}
+
+	tt, err := time.ParseInLocation(datetimeLayout, s, time.UTC)
+	if err != nil {
+		return fmt.Errorf("unexpected datetime %q: %w", s, err)
+	}
+
+	*t = Datetime(tt)
+	return nil
+}
+
+func (t Datetime) Time() time.Time {
+	return time.Time(t)
+}
+
+type Balance struct {
+	Currency  string           `json:"currency"`
+	Total     fixedpoint.Value `json:"total"`
+	Available fixedpoint.Value `json:"available"`
+	Reserved  fixedpoint.Value `json:"reserved"`
+}
+
+type Order struct {
+	ID            string           `json:"id"`
+	Market        string           `json:"market"`
+	Datetime      Datetime         `json:"datetime"`
+	Type          OrderType        `json:"type"`
+	Price         fixedpoint.Value `json:"price"`
+	Amount        fixedpoint.Value `json:"amount"`
+	ClientOrderID string           `json:"client_order_id"`
+}
+
+type OpenOrder struct {
+	Order
+
+	// AmountAtCreate is the original amount of the order, the Amount is the remaining amount
+	AmountAtCreate fixedpoint.Value `json:"amount_at_create"`
+	CurrencyPair   string           `json:"currency_pair"`
+	LimitPrice     fixedpoint.Value `json:"limit_price"`
+}
+
+// OrderTransaction is the trade of the order, the amounts are keyed by the lowercase currency
+//
+//	{"tid": 123, "price": "31000.00", "btc": "0.01", "usd": "310.00", "fee": "0.62", "datetime": "2022-01-31 14:43:15", "type": 2}
+type OrderTransaction struct {
+	TID      uint64           `json:"tid"`
+	Price    fixedpoint.Value `json:"price"`
+	Fee      fixedpoint.Value `json:"fee"`
+	Datetime Datetime         `json:"datetime"`
+	Type     TransactionType  `json:"type"`
+
+	Amounts map[string]fixedpoint.Value `json:"-"`
+}
+
+func (t *OrderTransaction) UnmarshalJSON(data []byte) error {
+	type alias OrderTransaction
+	if err := json.Unmarshal(data, (*alias)(t)); err != nil {
+		return err
+	}
+
+	amounts, err := parseAmounts(data, "tid", "price", "fee", "datetime", "type")
+	if err != nil {
+		return err
+	}
+
+	t.Amounts = amounts
+	return nil
+}
+
+type OrderStatusResponse struct {
+	ID              string             `json:"id"`
+	Datetime        Datetime           `json:"datetime"`
+	Type            OrderType          `json:"type"`
+	Status          OrderStatus        `json:"status"`
+	Market          string             `json:"market"`
+	Transactions    []OrderTransaction `json:"transactions"`
+	AmountRemaining fixedpoint.Value   `json:"amount_remaining"`
+	ClientOrderID   string             `json:"client_order_id"`
+}
+
+/*
+UserTransaction is the transaction of the account, the amounts are keyed by the lowercase currency,
+and the price is keyed by the lowercase pair separated by the underscore, e.g.
+
+	{
+	  "id": 1234, "order_id": 5678, "datetime": "2022-01-31 14:43:15.796000", "type": "2", "fee": "0.62",
+	  "usd": "-310.00", "btc": "0.01", "btc_usd": "31000.00", "eur": 0.0
+	}
+*/
+type UserTransaction struct {
+	ID       uint64           `json:"id"`
+	OrderID  uint64           `json:"order_id"`
+	Datetime Datetime         `json:"datetime"`
+	Type     TransactionType  `json:"type"`
+	Fee      fixedpoint.Value `json:"fee"`
+
+	Amounts map[string]fixedpoint.Value `json:"-"`
+}
+
+func (t *UserTransaction) UnmarshalJSON(data []byte) error {
+	type alias UserTransaction
+	if err := json.Unmarshal(data, (*alias)(t)); err != nil {
+		return err
+	}
+
+	amounts, err := parseAmounts(data, "id", "order_id", "datetime", "type", "fee")
+	if err != nil {
+		return err
+	}
+
+	t.Amounts = amounts
+	return nil
+}
+
+// Amount returns the amount of the currency, the currency is case-insensitive
+func (t UserTransaction) Amount(currency string) fixedpoint.Value {
+	return t.Amounts[strings.ToLower(currency)]
+}
+
+// Price returns the execution price of the trade in the pair of the base and quote currency
+func (t UserTransaction) Price(base, quote string) fixedpoint.Value {
+	return t.Amounts[strings.ToLower(base)+"_"+strings.ToLower(quote)]
+}
+
+// parseAmounts parses the dynamic currency fields, the numeric fields except the given keys are collected
+func parseAmounts(data []byte, excludedKeys ...string) (map[string]fixedpoint.Value, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+
+	for _, k := range excludedKeys {
+		delete(fields, k)
+	}
+
+	amounts := make(map[string]fixedpoint.Value, len(fields))
+	for k, raw := range fields {
+		var v fixedpoint.Value
+		if err := json.Unmarshal(raw, &v); err != nil {
+			// skip the non-numeric fields
+			continue
+		}
+
+		amounts[k] = v
+	}
+
+	return amounts, nil
+}
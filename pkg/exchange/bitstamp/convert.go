@@ -0,0 +1,341 @@
+package bitstamp
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/exchange/bitstamp/bitstampapi"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// toGlobalSymbol converts the pair into the global symbol, the pair could be the url symbol, e.g. btcusd,
+// or the name of the pair, e.g. BTC/USD
+func toGlobalSymbol(pair string) string {
+	return strings.ToUpper(strings.ReplaceAll(pair, "/", ""))
+}
+
+// toLocalSymbol converts the global symbol into the url symbol of the pair
+func toLocalSymbol(symbol string) string {
+	return strings.ToLower(symbol)
+}
+
+// splitPairName splits the name of the pair into the base and quote currency, e.g. BTC/USD
+func splitPairName(name string) (base, quote string, err error) {
+	parts := strings.Split(name, "/")
+	if len(parts) != 2 {
+		return "", "", fmt.Errorf("unexpected pair name: %s", name)
+	}
+
+	return strings.ToUpper(parts[0]), strings.ToUpper(parts[1]), nil
+}
+
+func parseID(id string) (uint64, error) {
+	n, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("unexpected id %q: %w", id, err)
+	}
+
+	return n, nil
+}
+
+func precisionToStep(precision int) fixedpoint.Value {
+	return fixedpoint.NewFromFloat(math.Pow10(-precision))
+}
+
+// parseMinimumOrder parses the minimum order of the pair, which is the quote amount with the currency, e.g. "10.0 USD"
+func parseMinimumOrder(s string) fixedpoint.Value {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return fixedpoint.Zero
+	}
+
+	v, err := fixedpoint.NewFromString(fields[0])
+	if err != nil {
+		return fixedpoint.Zero
+	}
+
+	return v
+}
+
+func toGlobalMarket(p bitstampapi.TradingPair) (types.Market, error) {
+	base, quote, err := splitPairName(p.Name)
+	if err != nil {
+		return types.Market{}, err
+	}
+
+	minNotional := parseMinimumOrder(p.MinimumOrder)
+	return types.Market{
+		Exchange:        types.ExchangeBitstamp,
+		Symbol:          toGlobalSymbol(p.Name),
+		LocalSymbol:     p.URLSymbol,
+		PricePrecision:  p.CounterDecimals,
+		VolumePrecision: p.BaseDecimals,
+		QuoteCurrency:   quote,
+		BaseCurrency:    base,
+		MinNotional:     minNotional,
+		MinAmount:       minNotional,
+		MinQuantity:     precisionToStep(p.BaseDecimals),
+		MaxQuantity:     fixedpoint.NewFromFloat(math.MaxFloat64),
+		StepSize:        precisionToStep(p.BaseDecimals),
+		MinPrice:        fixedpoint.Zero,
+		MaxPrice:        fixedpoint.NewFromFloat(math.MaxFloat64),
+		TickSize:        precisionToStep(p.CounterDecimals),
+	}, nil
+}
+
+func toGlobalTicker(t bitstampapi.Ticker) types.Ticker {
+	return types.Ticker{
+		Time:   time.Unix(int64(t.Timestamp), 0),
+		Volume: t.Volume,
+		Last:   t.Last,
+		Open:   t.Open,
+		High:   t.High,
+		Low:    t.Low,
+		Buy:    t.Bid,
+		Sell:   t.Ask,
+	}
+}
+
+func toGlobalBalanceMap(balances []bitstampapi.Balance) types.BalanceMap {
+	bm := types.BalanceMap{}
+	for _, b := range balances {
+		currency := strings.ToUpper(b.Currency)
+		bm[currency] = types.Balance{
+			Currency:  currency,
+			Available: b.Available,
+			Locked:    b.Reserved,
+		}
+	}
+	return bm
+}
+
+// supportedIntervals are the steps of the ohlc api in seconds
+var supportedIntervals = map[types.Interval]int{
+	types.Interval1m:  1 * 60,
+	types.Interval3m:  3 * 60,
+	types.Interval5m:  5 * 60,
+	types.Interval15m: 15 * 60,
+	types.Interval30m: 30 * 60,
+	types.Interval1h:  60 * 60,
+	types.Interval2h:  2 * 60 * 60,
+	types.Interval4h:  4 * 60 * 60,
+	types.Interval6h:  6 * 60 * 60,
+	types.Interval12h: 12 * 60 * 60,
+	types.Interval1d:  24 * 60 * 60,
+	types.Interval3d:  3 * 24 * 60 * 60,
+}
+
+func toLocalInterval(interval types.Interval) (int, error) {
+	if step, ok := supportedIntervals[interval]; ok {
+		return step, nil
+	}
+
+	return 0, fmt.Errorf("interval %s is not supported", interval)
+}
+
+func toGlobalKLine(symbol string, interval types.Interval, k bitstampapi.OHLC) types.KLine {
+	startTime := time.Unix(int64(k.Timestamp), 0)
+	endTime := startTime.Add(interval.Duration() - time.Millisecond)
+	return types.KLine{
+		Exchange:    types.ExchangeBitstamp,
+		Symbol:      symbol,
+		StartTime:   types.Time(startTime),
+		EndTime:     types.Time(endTime),
+		Interval:    interval,
+		Open:        k.Open,
+		Close:       k.Close,
+		High:        k.High,
+		Low:         k.Low,
+		Volume:      k.Volume,
+		QuoteVolume: k.Volume.Mul(k.Close),
+		Closed:      endTime.Before(time.Now()),
+	}
+}
+
+func toGlobalSideType(orderType bitstampapi.OrderType) (types.SideType, error) {
+	switch orderType {
+	case bitstampapi.OrderTypeBuy:
+		return types.SideTypeBuy, nil
+	case bitstampapi.OrderTypeSell:
+		return types.SideTypeSell, nil
+	}
+
+	return "", fmt.Errorf("unexpected order type: %s", orderType)
+}
+
+func toLocalSideType(side types.SideType) (string, error) {
+	switch side {
+	case types.SideTypeBuy:
+		return "buy", nil
+	case types.SideTypeSell:
+		return "sell", nil
+	}
+
+	return "", fmt.Errorf("side type %s is not supported", side)
+}
+
+func toGlobalOrderStatus(status bitstampapi.OrderStatus, executedQuantity fixedpoint.Value) (types.OrderStatus, error) {
+	switch status {
+	case bitstampapi.OrderStatusOpen:
+		if executedQuantity.Sign() > 0 {
+			return types.OrderStatusPartiallyFilled, nil
+		}
+		return types.OrderStatusNew, nil
+
+	case bitstampapi.OrderStatusFinished:
+		return types.OrderStatusFilled, nil
+
+	case bitstampapi.OrderStatusCanceled, bitstampapi.OrderStatusExpired:
+		return types.OrderStatusCanceled, nil
+	}
+
+	return "", fmt.Errorf("unexpected order status: %s", status)
+}
+
+// toGlobalOrder converts the order status, the order type is not returned by Bitstamp, so the limit order is assumed,
+// and the quantity is the sum of the executed amount and the remaining amount.
+func toGlobalOrder(o bitstampapi.OrderStatusResponse) (*types.Order, error) {
+	orderID, err := parseID(o.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	side, err := toGlobalSideType(o.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	base, _, err := splitPairName(o.Market)
+	if err != nil {
+		return nil, err
+	}
+
+	executedQuantity := fixedpoint.Zero
+	price := fixedpoint.Zero
+	for _, t := range o.Transactions {
+		executedQuantity = executedQuantity.Add(t.Amounts[strings.ToLower(base)].Abs())
+		price = t.Price
+	}
+
+	status, err := toGlobalOrderStatus(o.Status, executedQuantity)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.Order{
+		SubmitOrder: types.SubmitOrder{
+			ClientOrderID: o.ClientOrderID,
+			Symbol:        toGlobalSymbol(o.Market),
+			Side:          side,
+			Type:          types.OrderTypeLimit,
+			Quantity:      executedQuantity.Add(o.AmountRemaining),
+			Price:         price,
+			TimeInForce:   types.TimeInForceGTC,
+		},
+		Exchange:         types.ExchangeBitstamp,
+		OrderID:          orderID,
+		UUID:             o.ID,
+		Status:           status,
+		OriginalStatus:   string(o.Status),
+		ExecutedQuantity: executedQuantity,
+		IsWorking:        o.Status == bitstampapi.OrderStatusOpen,
+		CreationTime:     types.Time(o.Datetime.Time()),
+		UpdateTime:       types.Time(o.Datetime.Time()),
+	}, nil
+}
+
+func toGlobalOpenOrder(o bitstampapi.OpenOrder) (*types.Order, error) {
+	orderID, err := parseID(o.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	side, err := toGlobalSideType(o.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	pair := o.CurrencyPair
+	if len(pair) == 0 {
+		pair = o.Market
+	}
+
+	quantity := o.AmountAtCreate
+	if quantity.IsZero() {
+		quantity = o.Amount
+	}
+
+	executedQuantity := quantity.Sub(o.Amount)
+	status := types.OrderStatusNew
+	if executedQuantity.Sign() > 0 {
+		status = types.OrderStatusPartiallyFilled
+	}
+
+	return &types.Order{
+		SubmitOrder: types.SubmitOrder{
+			ClientOrderID: o.ClientOrderID,
+			Symbol:        toGlobalSymbol(pair),
+			Side:          side,
+			Type:          types.OrderTypeLimit,
+			Quantity:      quantity,
+			Price:         o.Price,
+			TimeInForce:   types.TimeInForceGTC,
+		},
+		Exchange:         types.ExchangeBitstamp,
+		OrderID:          orderID,
+		UUID:             o.ID,
+		Status:           status,
+		OriginalStatus:   string(bitstampapi.OrderStatusOpen),
+		ExecutedQuantity: executedQuantity,
+		IsWorking:        true,
+		CreationTime:     types.Time(o.Datetime.Time()),
+		UpdateTime:       types.Time(o.Datetime.Time()),
+	}, nil
+}
+
+// toGlobalOrderTrade converts the transaction of the order, the fee is charged in the quote currency
+func toGlobalOrderTrade(order types.Order, market types.Market, t bitstampapi.OrderTransaction) types.Trade {
+	quantity := t.Amounts[strings.ToLower(market.BaseCurrency)].Abs()
+	return types.Trade{
+		ID:            t.TID,
+		OrderID:       order.OrderID,
+		Exchange:      types.ExchangeBitstamp,
+		Price:         t.Price,
+		Quantity:      quantity,
+		QuoteQuantity: t.Amounts[strings.ToLower(market.QuoteCurrency)].Abs(),
+		Symbol:        market.Symbol,
+		Side:          order.Side,
+		IsBuyer:       order.Side == types.SideTypeBuy,
+		Time:          types.Time(t.Datetime.Time()),
+		Fee:           t.Fee,
+		FeeCurrency:   market.QuoteCurrency,
+	}
+}
+
+// toGlobalTrade converts the market trade transaction, the buy trade has the positive base amount
+func toGlobalTrade(market types.Market, t bitstampapi.UserTransaction) types.Trade {
+	baseAmount := t.Amount(market.BaseCurrency)
+	side := types.SideTypeSell
+	if baseAmount.Sign() > 0 {
+		side = types.SideTypeBuy
+	}
+
+	return types.Trade{
+		ID:            t.ID,
+		OrderID:       t.OrderID,
+		Exchange:      types.ExchangeBitstamp,
+		Price:         t.Price(market.BaseCurrency, market.QuoteCurrency),
+		Quantity:      baseAmount.Abs(),
+		QuoteQuantity: t.Amount(market.QuoteCurrency).Abs(),
+		Symbol:        market.Symbol,
+		Side:          side,
+		IsBuyer:       side == types.SideTypeBuy,
+		Time:          types.Time(t.Datetime.Time()),
+		Fee:           t.Fee,
+		FeeCurrency:   market.QuoteCurrency,
+	}
+}
@@ -0,0 +1,109 @@
+package bitstamp
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/exchange/bitstamp/bitstampapi"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestToGlobalMarket(t *testing.T) {
+	market, err := toGlobalMarket(bitstampapi.TradingPair{
+		Name:            "BTC/EUR",
+		URLSymbol:       "btceur",
+		BaseDecimals:    8,
+		CounterDecimals: 0,
+		MinimumOrder:    "10.0 EUR",
+		Trading:         "Enabled",
+	})
+	if assert.NoError(t, err) {
+		assert.Equal(t, "BTCEUR", market.Symbol)
+		assert.Equal(t, "btceur", market.LocalSymbol)
+		assert.Equal(t, "BTC", market.BaseCurrency)
+		assert.Equal(t, "EUR", market.QuoteCurrency)
+		assert.Equal(t, "10", market.MinNotional.String())
+		assert.Equal(t, "1", market.TickSize.String())
+		assert.Equal(t, "0.00000001", market.StepSize.String())
+	}
+
+	_, err = toGlobalMarket(bitstampapi.TradingPair{Name: "BTCEUR"})
+	assert.Error(t, err)
+}
+
+func TestToGlobalOrder(t *testing.T) {
+	var res bitstampapi.OrderStatusResponse
+	err := json.Unmarshal([]byte(`{
+		"id": "1458532827766784",
+		"datetime": "2022-01-31 14:43:15.796000",
+		"type": "0",
+		"status": "Open",
+		"market": "BTC/USD",
+		"amount_remaining": "0.30000000",
+		"client_order_id": "my-order",
+		"transactions": [
+			{"tid": 1, "price": "30000.00", "btc": "0.10000000", "usd": "3000.00", "fee": "1.50", "datetime": "2022-01-31 14:43:16.000000", "type": 2},
+			{"tid": 2, "price": "30010.00", "btc": "0.10000000", "usd": "3001.00", "fee": "1.50", "datetime": "2022-01-31 14:43:17.000000", "type": 2}
+		]
+	}`), &res)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	order, err := toGlobalOrder(res)
+	if assert.NoError(t, err) {
+		assert.Equal(t, uint64(1458532827766784), order.OrderID)
+		assert.Equal(t, "BTCUSD", order.Symbol)
+		assert.Equal(t, types.SideTypeBuy, order.Side)
+		assert.Equal(t, types.OrderStatusPartiallyFilled, order.Status)
+		assert.Equal(t, "0.2", order.ExecutedQuantity.String())
+		assert.Equal(t, "0.5", order.Quantity.String())
+		assert.Equal(t, "my-order", order.ClientOrderID)
+		assert.True(t, order.IsWorking)
+	}
+
+	market := types.Market{Symbol: "BTCUSD", BaseCurrency: "BTC", QuoteCurrency: "USD"}
+	trade := toGlobalOrderTrade(*order, market, res.Transactions[1])
+	assert.Equal(t, uint64(2), trade.ID)
+	assert.Equal(t, order.OrderID, trade.OrderID)
+	assert.Equal(t, "0.1", trade.Quantity.String())
+	assert.Equal(t, "3001", trade.QuoteQuantity.String())
+	assert.Equal(t, "USD", trade.FeeCurrency)
+	assert.True(t, trade.IsBuyer)
+}
+
+func TestToGlobalOrderStatus(t *testing.T) {
+	status, err := toGlobalOrderStatus(bitstampapi.OrderStatusFinished, fixedpoint.One)
+	assert.NoError(t, err)
+	assert.Equal(t, types.OrderStatusFilled, status)
+
+	status, err = toGlobalOrderStatus(bitstampapi.OrderStatusExpired, fixedpoint.Zero)
+	assert.NoError(t, err)
+	assert.Equal(t, types.OrderStatusCanceled, status)
+
+	_, err = toGlobalOrderStatus("Unknown", fixedpoint.Zero)
+	assert.Error(t, err)
+}
+
+func TestToGlobalTrade(t *testing.T) {
+	var tx bitstampapi.UserTransaction
+	err := json.Unmarshal([]byte(`{
+		"id": 1234, "order_id": 5678, "datetime": "2022-01-31 14:43:15.796000", "type": "2", "fee": "0.62",
+		"eur": "310.00", "btc": "-0.01", "btc_eur": "31000.00", "usd": 0.0
+	}`), &tx)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	trade := toGlobalTrade(types.Market{Symbol: "BTCEUR", BaseCurrency: "BTC", QuoteCurrency: "EUR"}, tx)
+	assert.Equal(t, uint64(1234), trade.ID)
+	assert.Equal(t, uint64(5678), trade.OrderID)
+	assert.Equal(t, types.SideTypeSell, trade.Side)
+	assert.Equal(t, "31000", trade.Price.String())
+	assert.Equal(t, "0.01", trade.Quantity.String())
+	assert.Equal(t, "310", trade.QuoteQuantity.String())
+	assert.Equal(t, "EUR", trade.FeeCurrency)
+}
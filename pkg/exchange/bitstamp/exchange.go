@@ -0,0 +1,523 @@
+package bitstamp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.uber.org/multierr"
+	"golang.org/x/time/rate"
+
+	"github.com/c9s/bbgo/pkg/exchange/bitstamp/bitstampapi"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+const (
+	defaultQueryLimit = 1000
+	defaultKLineLimit = 1000
+)
+
+// https://www.bitstamp.net/api/#section/Request-limits
+// The request limit is 400 requests per second, and the default limit is 10,000 requests per 10 minutes.
+var (
+	sharedRateLimiter     = rate.NewLimiter(rate.Every(time.Second/10), 10)
+	orderRateLimiter      = rate.NewLimiter(rate.Every(time.Second/10), 10)
+	queryOrderRateLimiter = rate.NewLimiter(rate.Every(time.Second/5), 5)
+	queryTradeRateLimiter = rate.NewLimiter(rate.Every(time.Second/2), 2)
+
+	log = logrus.WithFields(logrus.Fields{
+		"exchange": "bitstamp",
+	})
+
+	_ types.ExchangeAccountService      = &Exchange{}
+	_ types.ExchangeMarketDataService   = &Exchange{}
+	_ types.CustomIntervalProvider      = &Exchange{}
+	_ types.ExchangeMinimal             = &Exchange{}
+	_ types.ExchangeTradeService        = &Exchange{}
+	_ types.Exchange                    = &Exchange{}
+	_ types.ExchangeOrderQueryService   = &Exchange{}
+	_ types.ExchangeTradeHistoryService = &Exchange{}
+)
+
+type Exchange struct {
+	key, secret string
+	client      *bitstampapi.RestClient
+
+	// markets is the cache of the markets, the currencies of the pair are required for parsing the transactions
+	markets   types.MarketMap
+	marketsMu sync.Mutex
+}
+
+func New(key, secret string) *Exchange {
+	client := bitstampapi.NewClient()
+	if len(key) > 0 && len(secret) > 0 {
+		client.Auth(key, secret)
+	}
+
+	return &Exchange{
+		key: key,
+		// pragma: allowlist nextline secret
+		secret: secret,
+		client: client,
+	}
+}
+
+func (e *Exchange) Name() types.ExchangeName {
+	return types.ExchangeBitstamp
+}
+
+// PlatformFeeCurrency returns an empty currency, Bitstamp has no platform token and charges the fee in the quote currency.
+func (e *Exchange) PlatformFeeCurrency() string {
+	return ""
+}
+
+func (e *Exchange) NewStream() types.Stream {
+	return NewStream(e.client, e)
+}
+
+func (e *Exchange) QueryMarkets(ctx context.Context) (types.MarketMap, error) {
+	if err := sharedRateLimiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("markets rate limiter wait error: %w", err)
+	}
+
+	pairs, err := e.client.NewGetTradingPairsInfoRequest().Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query trading pairs, err: %w", err)
+	}
+
+	markets := types.MarketMap{}
+	for _, p := range pairs {
+		if p.Trading != "Enabled" {
+			continue
+		}
+
+		market, err := toGlobalMarket(p)
+		if err != nil {
+			log.WithError(err).Warnf("skip the trading pair %s", p.Name)
+			continue
+		}
+
+		markets.Add(market)
+	}
+
+	e.marketsMu.Lock()
+	e.markets = markets
+	e.marketsMu.Unlock()
+
+	return markets, nil
+}
+
+// QueryMarket returns the market of the symbol from the cache, the markets are queried if they are not cached yet
+func (e *Exchange) QueryMarket(ctx context.Context, symbol string) (types.Market, error) {
+	e.marketsMu.Lock()
+	markets := e.markets
+	e.marketsMu.Unlock()
+
+	if markets == nil {
+		var err error
+		if markets, err = e.QueryMarkets(ctx); err != nil {
+			return types.Market{}, err
+		}
+	}
+
+	market, ok := markets[symbol]
+	if !ok {
+		return types.Market{}, fmt.Errorf("market %s is not found", symbol)
+	}
+
+	return market, nil
+}
+
+func (e *Exchange) QueryTicker(ctx context.Context, symbol string) (*types.Ticker, error) {
+	if err := sharedRateLimiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("ticker rate limiter wait error: %w", err)
+	}
+
+	t, err := e.client.NewGetTickerRequest().Pair(toLocalSymbol(symbol)).Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query ticker, symbol: %s, err: %w", symbol, err)
+	}
+
+	ticker := toGlobalTicker(*t)
+	return &ticker, nil
+}
+
+func (e *Exchange) QueryTickers(ctx context.Context, symbols ...string) (map[string]types.Ticker, error) {
+	tickers := map[string]types.Ticker{}
+	if len(symbols) == 1 {
+		t, err := e.QueryTicker(ctx, symbols[0])
+		if err != nil {
+			return nil, err
+		}
+
+		tickers[symbols[0]] = *t
+		return tickers, nil
+	}
+
+	if err := sharedRateLimiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("tickers rate limiter wait error: %w", err)
+	}
+
+	allTickers, err := e.client.NewGetTickersRequest().Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tickers, err: %w", err)
+	}
+
+	for _, t := range allTickers {
+		tickers[toGlobalSymbol(t.Pair)] = toGlobalTicker(t)
+	}
+
+	return types.FilterTickers(tickers, symbols...), nil
+}
+
+func (e *Exchange) QueryKLines(ctx context.Context, symbol string, interval types.Interval, options types.KLineQueryOptions) ([]types.KLine, error) {
+	step, err := toLocalInterval(interval)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := options.Limit
+	if limit > defaultKLineLimit || limit <= 0 {
+		limit = defaultKLineLimit
+	}
+
+	req := e.client.NewGetOHLCRequest().
+		Pair(toLocalSymbol(symbol)).
+		Step(step).
+		Limit(limit)
+
+	if options.StartTime != nil {
+		req.Start(*options.StartTime)
+	}
+
+	if options.EndTime != nil {
+		req.End(*options.EndTime)
+	}
+
+	if err := sharedRateLimiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("query klines rate limiter wait error: %w", err)
+	}
+
+	resp, err := req.Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query klines, err: %w", err)
+	}
+
+	var kLines []types.KLine
+	for _, k := range resp.Data.OHLC {
+		kLines = append(kLines, toGlobalKLine(symbol, interval, k))
+	}
+
+	return types.SortKLinesAscending(kLines), nil
+}
+
+func (e *Exchange) SupportedInterval() map[types.Interval]int {
+	return supportedIntervals
+}
+
+func (e *Exchange) IsSupportedInterval(interval types.Interval) bool {
+	_, ok := supportedIntervals[interval]
+	return ok
+}
+
+func (e *Exchange) QueryAccount(ctx context.Context) (*types.Account, error) {
+	balances, err := e.QueryAccountBalances(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	a := &types.Account{
+		AccountType: types.AccountTypeSpot,
+	}
+	a.UpdateBalances(balances)
+	return a, nil
+}
+
+func (e *Exchange) QueryAccountBalances(ctx context.Context) (types.BalanceMap, error) {
+	if err := sharedRateLimiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("query balances rate limiter wait error: %w", err)
+	}
+
+	balances, err := e.client.NewGetAccountBalancesRequest().Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query balances, err: %w", err)
+	}
+
+	return toGlobalBalanceMap(balances), nil
+}
+
+func (e *Exchange) SubmitOrder(ctx context.Context, order types.SubmitOrder) (*types.Order, error) {
+	if len(order.Market.Symbol) == 0 {
+		return nil, fmt.Errorf("order.Market.Symbol is required: %+v", order)
+	}
+
+	side, err := toLocalSideType(order.Side)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := orderRateLimiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("place order rate limiter wait error: %w", err)
+	}
+
+	var res *bitstampapi.Order
+	switch order.Type {
+	case types.OrderTypeMarket:
+		req := e.client.NewPlaceMarketOrderRequest().
+			Side(side).
+			Pair(toLocalSymbol(order.Symbol)).
+			Amount(order.Market.FormatQuantity(order.Quantity))
+
+		if len(order.ClientOrderID) > 0 {
+			req.ClientOrderID(order.ClientOrderID)
+		}
+
+		res, err = req.Do(ctx)
+
+	case types.OrderTypeLimit, types.OrderTypeLimitMaker:
+		req := e.client.NewPlaceLimitOrderRequest().
+			Side(side).
+			Pair(toLocalSymbol(order.Symbol)).
+			Amount(order.Market.FormatQuantity(order.Quantity)).
+			Price(order.Market.FormatPrice(order.Price))
+
+		switch {
+		case order.Type == types.OrderTypeLimitMaker:
+			req.MocOrder(true)
+		case order.TimeInForce == types.TimeInForceIOC:
+			req.IocOrder(true)
+		case order.TimeInForce == types.TimeInForceFOK:
+			req.FokOrder(true)
+		}
+
+		if len(order.ClientOrderID) > 0 {
+			req.ClientOrderID(order.ClientOrderID)
+		}
+
+		res, err = req.Do(ctx)
+
+	default:
+		return nil, fmt.Errorf("order type %s is not supported", order.Type)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to place order, order: %#v, err: %w", order, err)
+	}
+
+	orderID, err := parseID(res.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.Order{
+		SubmitOrder:      order,
+		Exchange:         types.ExchangeBitstamp,
+		OrderID:          orderID,
+		UUID:             res.ID,
+		Status:           types.OrderStatusNew,
+		ExecutedQuantity: fixedpoint.Zero,
+		IsWorking:        true,
+		CreationTime:     types.Time(res.Datetime.Time()),
+		UpdateTime:       types.Time(res.Datetime.Time()),
+	}, nil
+}
+
+func (e *Exchange) CancelOrders(ctx context.Context, orders ...types.Order) (errs error) {
+	for _, order := range orders {
+		if order.OrderID == 0 {
+			errs = multierr.Append(errs, fmt.Errorf("the order id is empty, order: %#v", order))
+			continue
+		}
+
+		if err := orderRateLimiter.Wait(ctx); err != nil {
+			errs = multierr.Append(errs, fmt.Errorf("cancel order rate limiter wait error: %w", err))
+			continue
+		}
+
+		if _, err := e.client.NewCancelOrderRequest().OrderID(strconv.FormatUint(order.OrderID, 10)).Do(ctx); err != nil {
+			errs = multierr.Append(errs, fmt.Errorf("failed to cancel order, order: %s, err: %w", order.String(), err))
+		}
+	}
+
+	return errs
+}
+
+func (e *Exchange) QueryOpenOrders(ctx context.Context, symbol string) (orders []types.Order, err error) {
+	if err := queryOrderRateLimiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("query open orders rate limiter wait error: %w", err)
+	}
+
+	res, err := e.client.NewGetOpenOrdersRequest().Pair(toLocalSymbol(symbol)).Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query open orders, err: %w", err)
+	}
+
+	for _, o := range res {
+		order, err := toGlobalOpenOrder(o)
+		if err != nil {
+			return nil, err
+		}
+
+		orders = append(orders, *order)
+	}
+
+	return orders, nil
+}
+
+func (e *Exchange) queryOrderStatus(ctx context.Context, q types.OrderQuery) (*bitstampapi.OrderStatusResponse, error) {
+	req := e.client.NewGetOrderStatusRequest()
+	switch {
+	case len(q.OrderID) > 0:
+		req.OrderID(q.OrderID)
+
+	case len(q.ClientOrderID) > 0:
+		req.ClientOrderID(q.ClientOrderID)
+
+	default:
+		return nil, errors.New("one of OrderID/ClientOrderID is required")
+	}
+
+	if err := queryOrderRateLimiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("query order rate limiter wait error: %w", err)
+	}
+
+	res, err := req.Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query order, query: %+v, err: %w", q, err)
+	}
+
+	return res, nil
+}
+
+func (e *Exchange) QueryOrder(ctx context.Context, q types.OrderQuery) (*types.Order, error) {
+	res, err := e.queryOrderStatus(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	return toGlobalOrder(*res)
+}
+
+func (e *Exchange) QueryOrderTrades(ctx context.Context, q types.OrderQuery) (trades []types.Trade, err error) {
+	res, err := e.queryOrderStatus(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	order, err := toGlobalOrder(*res)
+	if err != nil {
+		return nil, err
+	}
+
+	market, err := e.QueryMarket(ctx, order.Symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, t := range res.Transactions {
+		trades = append(trades, toGlobalOrderTrade(*order, market, t))
+	}
+
+	return trades, nil
+}
+
+// QueryClosedOrders queries the orders of the trades in the time range, since Bitstamp doesn't provide the order history,
+// the orders that are canceled without any trade are not returned.
+func (e *Exchange) QueryClosedOrders(ctx context.Context, symbol string, since, until time.Time, lastOrderID uint64) (orders []types.Order, err error) {
+	options := &types.TradeQueryOptions{StartTime: &since}
+	if !until.IsZero() {
+		options.EndTime = &until
+	}
+
+	trades, err := e.QueryTrades(ctx, symbol, options)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[uint64]struct{}{}
+	for _, trade := range trades {
+		if _, ok := seen[trade.OrderID]; ok || trade.OrderID <= lastOrderID {
+			continue
+		}
+		seen[trade.OrderID] = struct{}{}
+
+		order, err2 := e.QueryOrder(ctx, types.OrderQuery{
+			Symbol:  symbol,
+			OrderID: strconv.FormatUint(trade.OrderID, 10),
+		})
+		if err2 != nil {
+			err = multierr.Append(err, err2)
+			continue
+		}
+
+		if order.Status.Closed() {
+			orders = append(orders, *order)
+		}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return types.SortOrdersAscending(orders), nil
+}
+
+// QueryTrades queries the market trades of the user transactions, the LastTradeID is used as the since id,
+// which takes precedence over the time range.
+func (e *Exchange) QueryTrades(ctx context.Context, symbol string, options *types.TradeQueryOptions) (trades []types.Trade, err error) {
+	market, err := e.QueryMarket(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	req := e.client.NewGetUserTransactionsRequest().
+		Pair(toLocalSymbol(symbol)).
+		Sort("asc")
+
+	if options.LastTradeID > 0 {
+		req.SinceID(options.LastTradeID)
+	} else {
+		if options.StartTime != nil {
+			req.SinceTimestamp(options.StartTime.Unix())
+		}
+
+		if options.EndTime != nil {
+			req.UntilTimestamp(options.EndTime.Unix())
+		}
+	}
+
+	limit := int(options.Limit)
+	if limit > defaultQueryLimit || limit <= 0 {
+		limit = defaultQueryLimit
+	}
+	req.Limit(limit)
+
+	if err := queryTradeRateLimiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("query trades rate limiter wait error: %w", err)
+	}
+
+	res, err := req.Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query trades, err: %w", err)
+	}
+
+	for _, t := range res {
+		if t.Type != bitstampapi.TransactionTypeMarketTrade {
+			continue
+		}
+
+		// the since id is inclusive
+		if t.ID <= options.LastTradeID {
+			continue
+		}
+
+		trades = append(trades, toGlobalTrade(market, t))
+	}
+
+	return types.SortTradesAscending(trades), nil
+}
@@ -0,0 +1,251 @@
+package bitstamp
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/c9s/bbgo/pkg/exchange/bitstamp/bitstampapi"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+const (
+	// balanceUpdateInterval throttles the balance queries triggered by the private events,
+	// since Bitstamp doesn't push the balance updates.
+	balanceUpdateInterval = time.Second
+
+	tokenRequestTimeout = 10 * time.Second
+)
+
+// StreamExchange provides the balances and the markets for the private channels
+type StreamExchange interface {
+	QueryAccountBalances(ctx context.Context) (types.BalanceMap, error)
+	QueryMarket(ctx context.Context, symbol string) (types.Market, error)
+}
+
+//go:generate callbackgen -type Stream
+type Stream struct {
+	types.StandardStream
+
+	client   *bitstampapi.RestClient
+	exchange StreamExchange
+
+	// privateChannelSymbols are the symbols of the private order and trade channels,
+	// Bitstamp only provides the private channels of each pair.
+	privateChannelSymbols []string
+
+	balanceUpdateC    chan struct{}
+	balanceUpdateOnce sync.Once
+
+	orderBookEventCallbacks []func(e OrderBookEvent)
+	liveTradeEventCallbacks []func(e LiveTradeEvent)
+	myOrderEventCallbacks   []func(e MyOrderEvent)
+	myTradeEventCallbacks   []func(e MyTradeEvent)
+}
+
+func NewStream(client *bitstampapi.RestClient, exchange StreamExchange) *Stream {
+	stream := &Stream{
+		StandardStream: types.NewStandardStream(),
+		client:         client,
+		exchange:       exchange,
+		balanceUpdateC: make(chan struct{}, 1),
+	}
+
+	stream.SetEndpointCreator(stream.createEndpoint)
+	stream.SetParser(parseWebSocketEvent)
+	stream.SetDispatcher(stream.dispatchEvent)
+	stream.SetHeartBeat(stream.heartbeat)
+	stream.OnConnect(stream.handleConnect)
+	stream.OnAuth(stream.handleAuth)
+
+	stream.OnOrderBookEvent(stream.handleOrderBookEvent)
+	stream.OnLiveTradeEvent(stream.handleLiveTradeEvent)
+	stream.OnMyOrderEvent(stream.handleMyOrderEvent)
+	stream.OnMyTradeEvent(stream.handleMyTradeEvent)
+	return stream
+}
+
+func (s *Stream) SetPrivateChannelSymbols(symbols []string) {
+	s.privateChannelSymbols = symbols
+}
+
+func (s *Stream) createEndpoint(ctx context.Context) (string, error) {
+	return bitstampapi.WsBaseURL, nil
+}
+
+func (s *Stream) dispatchEvent(event interface{}) {
+	switch e := event.(type) {
+	case *WsResponse:
+		switch e.Event {
+		case WsEventRequestReconnect:
+			log.Warn("received the reconnect request")
+			s.Reconnect()
+
+		case WsEventError:
+			log.Errorf("websocket error: %+v", e)
+		}
+
+	case *OrderBookEvent:
+		s.EmitOrderBookEvent(*e)
+
+	case *LiveTradeEvent:
+		s.EmitLiveTradeEvent(*e)
+
+	case *MyOrderEvent:
+		s.EmitMyOrderEvent(*e)
+
+	case *MyTradeEvent:
+		s.EmitMyTradeEvent(*e)
+	}
+}
+
+// heartbeat sends the bts:heartbeat event, the server responds with the bts:heartbeat event
+func (s *Stream) heartbeat(conn *websocket.Conn) error {
+	if err := conn.WriteJSON(WsRequest{Event: WsEventHeartbeat}); err != nil {
+		log.WithError(err).Error("heartbeat error")
+		return err
+	}
+
+	return nil
+}
+
+func (s *Stream) handleConnect() {
+	if s.PublicOnly {
+		for _, sub := range s.Subscriptions {
+			channel, err := convertSubscription(sub)
+			if err != nil {
+				log.WithError(err).Errorf("subscription convert error")
+				continue
+			}
+
+			s.subscribe(WsSubscriptionData{Channel: channel})
+		}
+		return
+	}
+
+	if len(s.privateChannelSymbols) == 0 {
+		log.Warnf("you have not subscribed to any private channels, please set the private channel symbols of the session")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), tokenRequestTimeout)
+	defer cancel()
+
+	// the token is only used for the subscriptions, so a new token is requested on every connection
+	token, err := s.client.NewGetWebsocketTokenRequest().Do(ctx)
+	if err != nil {
+		log.WithError(err).Error("failed to get the websocket token")
+		s.Reconnect()
+		return
+	}
+
+	for _, symbol := range s.privateChannelSymbols {
+		for _, prefix := range []Channel{ChannelMyOrders, ChannelMyTrades} {
+			s.subscribe(WsSubscriptionData{
+				Channel: fmt.Sprintf("%s%s-%d", prefix, toLocalSymbol(symbol), token.UserID),
+				Auth:    token.Token,
+			})
+		}
+	}
+
+	s.EmitAuth()
+}
+
+func (s *Stream) subscribe(data WsSubscriptionData) {
+	log.Infof("subscribing channel: %s", data.Channel)
+	if err := s.Conn.WriteJSON(WsRequest{
+		Event: WsEventSubscribe,
+		Data:  &data,
+	}); err != nil {
+		log.WithError(err).Errorf("failed to send subscription request, channel: %s", data.Channel)
+	}
+}
+
+func (s *Stream) handleAuth() {
+	s.balanceUpdateOnce.Do(func() {
+		go s.balanceUpdater()
+	})
+
+	balances, err := s.exchange.QueryAccountBalances(context.Background())
+	if err != nil {
+		log.WithError(err).Error("failed to query balances")
+		return
+	}
+
+	s.EmitBalanceSnapshot(balances)
+}
+
+// triggerBalanceUpdate requests a balance update, the request is dropped if there is a pending one
+func (s *Stream) triggerBalanceUpdate() {
+	select {
+	case s.balanceUpdateC <- struct{}{}:
+	default:
+	}
+}
+
+// balanceUpdater queries and emits the balances on the private events, since there is no balance channel
+func (s *Stream) balanceUpdater() {
+	for {
+		select {
+		case <-s.CloseC:
+			return
+
+		case <-s.balanceUpdateC:
+			balances, err := s.exchange.QueryAccountBalances(context.Background())
+			if err != nil {
+				log.WithError(err).Error("failed to query balances")
+			} else {
+				s.EmitBalanceUpdate(balances)
+			}
+
+			time.Sleep(balanceUpdateInterval)
+		}
+	}
+}
+
+func convertSubscription(sub types.Subscription) (string, error) {
+	pair := toLocalSymbol(sub.Symbol)
+
+	switch sub.Channel {
+	case types.BookChannel:
+		return string(ChannelOrderBook) + pair, nil
+
+	case types.MarketTradeChannel:
+		return string(ChannelLiveTrades) + pair, nil
+	}
+
+	return "", fmt.Errorf("unsupported stream channel: %s", sub.Channel)
+}
+
+// handleOrderBookEvent emits the book as the snapshot, since the order_book channel pushes the top 100 levels on every change
+func (s *Stream) handleOrderBookEvent(e OrderBookEvent) {
+	s.EmitBookSnapshot(e.OrderBook())
+}
+
+func (s *Stream) handleLiveTradeEvent(e LiveTradeEvent) {
+	s.EmitMarketTrade(e.Trade())
+}
+
+func (s *Stream) handleMyOrderEvent(e MyOrderEvent) {
+	order, err := e.Order()
+	if err != nil {
+		log.WithError(err).Error("failed to convert order")
+		return
+	}
+
+	s.EmitOrderUpdate(*order)
+	s.triggerBalanceUpdate()
+}
+
+func (s *Stream) handleMyTradeEvent(e MyTradeEvent) {
+	market, err := s.exchange.QueryMarket(context.Background(), e.Symbol)
+	if err != nil {
+		log.WithError(err).Errorf("failed to query market %s", e.Symbol)
+		return
+	}
+
+	s.EmitTradeUpdate(e.Trade(market.QuoteCurrency))
+	s.triggerBalanceUpdate()
+}
@@ -0,0 +1,285 @@
+package bitstamp
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// Channel is the prefix of the websocket channels, the channel is the prefix followed by the pair, e.g. live_trades_btcusd,
+// and the private channels are suffixed by the user id, e.g. private-my_orders_btcusd-123
+type Channel string
+
+const (
+	ChannelOrderBook  Channel = "order_book_"
+	ChannelLiveTrades Channel = "live_trades_"
+
+	ChannelMyOrders Channel = "private-my_orders_"
+	ChannelMyTrades Channel = "private-my_trades_"
+)
+
+type WsEventType string
+
+const (
+	WsEventSubscribe             WsEventType = "bts:subscribe"
+	WsEventHeartbeat             WsEventType = "bts:heartbeat"
+	WsEventSubscriptionSucceeded WsEventType = "bts:subscription_succeeded"
+	WsEventRequestReconnect      WsEventType = "bts:request_reconnect"
+	WsEventError                 WsEventType = "bts:error"
+
+	WsEventData         WsEventType = "data"
+	WsEventTrade        WsEventType = "trade"
+	WsEventOrderCreated WsEventType = "order_created"
+	WsEventOrderChanged WsEventType = "order_changed"
+	WsEventOrderDeleted WsEventType = "order_deleted"
+)
+
+type WsSubscriptionData struct {
+	Channel string `json:"channel"`
+
+	// Auth is the websocket token for the private channels
+	Auth string `json:"auth,omitempty"`
+}
+
+type WsRequest struct {
+	Event WsEventType         `json:"event"`
+	Data  *WsSubscriptionData `json:"data,omitempty"`
+}
+
+// WsMessage is the message of the websocket, e.g.
+//
+//	{"data": {...}, "channel": "live_trades_btcusd", "event": "trade"}
+type WsMessage struct {
+	Event   WsEventType     `json:"event"`
+	Channel string          `json:"channel"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// WsResponse is the message of the control events, e.g. the subscription result, the heartbeat and the reconnect request
+type WsResponse struct {
+	Event   WsEventType `json:"event"`
+	Channel string      `json:"channel"`
+
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+// parseMicroTimestamp parses the microtimestamp string of the events
+func parseMicroTimestamp(s string) time.Time {
+	ts, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+
+	return time.UnixMicro(ts)
+}
+
+type OrderBookEvent struct {
+	Symbol string `json:"-"`
+
+	Timestamp      string                 `json:"timestamp"`
+	Microtimestamp string                 `json:"microtimestamp"`
+	Bids           types.PriceVolumeSlice `json:"bids"`
+	Asks           types.PriceVolumeSlice `json:"asks"`
+}
+
+// OrderBook returns the top 100 levels of the book, the order_book channel pushes the full snapshot on every change
+func (e OrderBookEvent) OrderBook() types.SliceOrderBook {
+	return types.SliceOrderBook{
+		Symbol: e.Symbol,
+		Time:   parseMicroTimestamp(e.Microtimestamp),
+		Bids:   e.Bids,
+		Asks:   e.Asks,
+	}
+}
+
+type LiveTradeEvent struct {
+	Symbol string `json:"-"`
+
+	ID             uint64           `json:"id"`
+	Amount         fixedpoint.Value `json:"amount_str"`
+	Price          fixedpoint.Value `json:"price_str"`
+	Type           int              `json:"type"`
+	Microtimestamp string           `json:"microtimestamp"`
+	BuyOrderID     uint64           `json:"buy_order_id"`
+	SellOrderID    uint64           `json:"sell_order_id"`
+}
+
+// Trade returns the market trade, the type is the side of the taker, 0 for buy and 1 for sell
+func (e LiveTradeEvent) Trade() types.Trade {
+	side := types.SideTypeBuy
+	if e.Type == 1 {
+		side = types.SideTypeSell
+	}
+
+	return types.Trade{
+		ID:            e.ID,
+		Exchange:      types.ExchangeBitstamp,
+		Price:         e.Price,
+		Quantity:      e.Amount,
+		QuoteQuantity: e.Price.Mul(e.Amount),
+		Symbol:        e.Symbol,
+		Side:          side,
+		IsBuyer:       side == types.SideTypeBuy,
+		Time:          types.Time(parseMicroTimestamp(e.Microtimestamp)),
+	}
+}
+
+// MyOrderEvent is the order event of the private-my_orders channel, the amount is the remaining amount of the order
+type MyOrderEvent struct {
+	Symbol string      `json:"-"`
+	Event  WsEventType `json:"-"`
+
+	ID             string           `json:"id_str"`
+	ClientOrderID  string           `json:"client_order_id"`
+	Amount         fixedpoint.Value `json:"amount_str"`
+	AmountTraded   fixedpoint.Value `json:"amount_traded"`
+	AmountAtCreate fixedpoint.Value `json:"amount_at_create"`
+	Price          fixedpoint.Value `json:"price_str"`
+	OrderType      int              `json:"order_type"`
+	Datetime       json.Number      `json:"datetime"`
+	Microtimestamp string           `json:"microtimestamp"`
+}
+
+func (e MyOrderEvent) Order() (*types.Order, error) {
+	orderID, err := parseID(e.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	side := types.SideTypeBuy
+	if e.OrderType == 1 {
+		side = types.SideTypeSell
+	}
+
+	quantity := e.AmountAtCreate
+	if quantity.IsZero() {
+		quantity = e.Amount.Add(e.AmountTraded)
+	}
+
+	status := types.OrderStatusNew
+	switch e.Event {
+	case WsEventOrderChanged:
+		if e.AmountTraded.Sign() > 0 {
+			status = types.OrderStatusPartiallyFilled
+		}
+	case WsEventOrderDeleted:
+		status = types.OrderStatusCanceled
+		if e.Amount.IsZero() && e.AmountTraded.Sign() > 0 {
+			status = types.OrderStatusFilled
+		}
+	}
+
+	creationTime, _ := e.Datetime.Int64()
+	updateTime := parseMicroTimestamp(e.Microtimestamp)
+	return &types.Order{
+		SubmitOrder: types.SubmitOrder{
+			ClientOrderID: e.ClientOrderID,
+			Symbol:        e.Symbol,
+			Side:          side,
+			Type:          types.OrderTypeLimit,
+			Quantity:      quantity,
+			Price:         e.Price,
+			TimeInForce:   types.TimeInForceGTC,
+		},
+		Exchange:         types.ExchangeBitstamp,
+		OrderID:          orderID,
+		UUID:             e.ID,
+		Status:           status,
+		OriginalStatus:   string(e.Event),
+		ExecutedQuantity: e.AmountTraded,
+		IsWorking:        e.Event != WsEventOrderDeleted,
+		CreationTime:     types.Time(time.Unix(creationTime, 0)),
+		UpdateTime:       types.Time(updateTime),
+	}, nil
+}
+
+// MyTradeEvent is the trade event of the private-my_trades channel, the fee is charged in the quote currency
+type MyTradeEvent struct {
+	Symbol string `json:"-"`
+
+	ID             uint64           `json:"id"`
+	OrderID        uint64           `json:"order_id"`
+	ClientOrderID  string           `json:"client_order_id"`
+	Amount         fixedpoint.Value `json:"amount"`
+	Price          fixedpoint.Value `json:"price"`
+	Fee            fixedpoint.Value `json:"fee"`
+	Side           string           `json:"side"`
+	Microtimestamp string           `json:"microtimestamp"`
+}
+
+func (e MyTradeEvent) Trade(feeCurrency string) types.Trade {
+	side := types.SideTypeSell
+	if e.Side == "buy" {
+		side = types.SideTypeBuy
+	}
+
+	return types.Trade{
+		ID:            e.ID,
+		OrderID:       e.OrderID,
+		Exchange:      types.ExchangeBitstamp,
+		Price:         e.Price,
+		Quantity:      e.Amount,
+		QuoteQuantity: e.Price.Mul(e.Amount),
+		Symbol:        e.Symbol,
+		Side:          side,
+		IsBuyer:       side == types.SideTypeBuy,
+		Time:          types.Time(parseMicroTimestamp(e.Microtimestamp)),
+		Fee:           e.Fee,
+		FeeCurrency:   feeCurrency,
+	}
+}
+
+// channelPair returns the pair of the channel, the user id suffix of the private channels is trimmed
+func channelPair(channel string, prefix Channel) string {
+	pair := strings.TrimPrefix(channel, string(prefix))
+	if idx := strings.LastIndex(pair, "-"); idx > 0 {
+		pair = pair[:idx]
+	}
+
+	return pair
+}
+
+func parseWebSocketEvent(in []byte) (interface{}, error) {
+	var msg WsMessage
+	if err := json.Unmarshal(in, &msg); err != nil {
+		return nil, err
+	}
+
+	switch msg.Event {
+	case WsEventHeartbeat, WsEventSubscriptionSucceeded, WsEventRequestReconnect, WsEventError:
+		resp := &WsResponse{Event: msg.Event, Channel: msg.Channel}
+		// the data could be an empty string
+		if len(msg.Data) > 0 && msg.Data[0] == '{' {
+			if err := json.Unmarshal(msg.Data, resp); err != nil {
+				return nil, err
+			}
+		}
+		return resp, nil
+	}
+
+	switch {
+	case strings.HasPrefix(msg.Channel, string(ChannelOrderBook)) && msg.Event == WsEventData:
+		e := &OrderBookEvent{Symbol: toGlobalSymbol(channelPair(msg.Channel, ChannelOrderBook))}
+		return e, json.Unmarshal(msg.Data, e)
+
+	case strings.HasPrefix(msg.Channel, string(ChannelLiveTrades)) && msg.Event == WsEventTrade:
+		e := &LiveTradeEvent{Symbol: toGlobalSymbol(channelPair(msg.Channel, ChannelLiveTrades))}
+		return e, json.Unmarshal(msg.Data, e)
+
+	case strings.HasPrefix(msg.Channel, string(ChannelMyOrders)):
+		e := &MyOrderEvent{Symbol: toGlobalSymbol(channelPair(msg.Channel, ChannelMyOrders)), Event: msg.Event}
+		return e, json.Unmarshal(msg.Data, e)
+
+	case strings.HasPrefix(msg.Channel, string(ChannelMyTrades)) && msg.Event == WsEventTrade:
+		e := &MyTradeEvent{Symbol: toGlobalSymbol(channelPair(msg.Channel, ChannelMyTrades))}
+		return e, json.Unmarshal(msg.Data, e)
+	}
+
+	return nil, fmt.Errorf("unhandled websocket event: %s", string(in))
+}
@@ -0,0 +1,83 @@
+package bitstamp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestParseWebSocketEvent(t *testing.T) {
+	t.Run("order book", func(t *testing.T) {
+		e, err := parseWebSocketEvent([]byte(`{"data": {"timestamp": "1649858416", "microtimestamp": "1649858416123456",
+			"bids": [["40000.00", "0.50000000"]], "asks": [["40001.00", "0.20000000"]]},
+			"channel": "order_book_btceur", "event": "data"}`))
+		if assert.NoError(t, err) && assert.IsType(t, &OrderBookEvent{}, e) {
+			book := e.(*OrderBookEvent).OrderBook()
+			assert.Equal(t, "BTCEUR", book.Symbol)
+			assert.Equal(t, "40000", book.Bids[0].Price.String())
+			assert.Equal(t, "0.2", book.Asks[0].Volume.String())
+			assert.Equal(t, int64(1649858416123456), book.Time.UnixMicro())
+		}
+	})
+
+	t.Run("live trade", func(t *testing.T) {
+		e, err := parseWebSocketEvent([]byte(`{"data": {"id": 229843941, "timestamp": "1649858416", "amount": 0.001,
+			"amount_str": "0.00100000", "price": 40000, "price_str": "40000.00", "type": 1,
+			"microtimestamp": "1649858416123456", "buy_order_id": 1, "sell_order_id": 2},
+			"channel": "live_trades_btcusd", "event": "trade"}`))
+		if assert.NoError(t, err) && assert.IsType(t, &LiveTradeEvent{}, e) {
+			trade := e.(*LiveTradeEvent).Trade()
+			assert.Equal(t, "BTCUSD", trade.Symbol)
+			assert.Equal(t, types.SideTypeSell, trade.Side)
+			assert.Equal(t, "0.001", trade.Quantity.String())
+			assert.Equal(t, "40", trade.QuoteQuantity.String())
+		}
+	})
+
+	t.Run("my order", func(t *testing.T) {
+		e, err := parseWebSocketEvent([]byte(`{"data": {"id": 1458532827766784, "id_str": "1458532827766784",
+			"order_type": 1, "datetime": "1649858416", "microtimestamp": "1649858416123456",
+			"amount": 0, "amount_str": "0.00000000", "amount_traded": "0.5", "amount_at_create": "0.5",
+			"price": 40000, "price_str": "40000.00", "client_order_id": "my-order"},
+			"channel": "private-my_orders_btcusd-123", "event": "order_deleted"}`))
+		if assert.NoError(t, err) && assert.IsType(t, &MyOrderEvent{}, e) {
+			order, err := e.(*MyOrderEvent).Order()
+			if assert.NoError(t, err) {
+				assert.Equal(t, "BTCUSD", order.Symbol)
+				assert.Equal(t, uint64(1458532827766784), order.OrderID)
+				assert.Equal(t, types.SideTypeSell, order.Side)
+				assert.Equal(t, types.OrderStatusFilled, order.Status)
+				assert.Equal(t, "0.5", order.Quantity.String())
+				assert.False(t, order.IsWorking)
+			}
+		}
+	})
+
+	t.Run("my trade", func(t *testing.T) {
+		e, err := parseWebSocketEvent([]byte(`{"data": {"id": 1, "order_id": 1458532827766784, "client_order_id": "my-order",
+			"amount": "0.5", "price": "40000.00", "fee": "10.00", "side": "buy", "microtimestamp": "1649858416123456"},
+			"channel": "private-my_trades_btcusd-123", "event": "trade"}`))
+		if assert.NoError(t, err) && assert.IsType(t, &MyTradeEvent{}, e) {
+			trade := e.(*MyTradeEvent).Trade("USD")
+			assert.Equal(t, "BTCUSD", trade.Symbol)
+			assert.Equal(t, types.SideTypeBuy, trade.Side)
+			assert.Equal(t, uint64(1458532827766784), trade.OrderID)
+			assert.Equal(t, "20000", trade.QuoteQuantity.String())
+			assert.Equal(t, "USD", trade.FeeCurrency)
+		}
+	})
+
+	t.Run("control events", func(t *testing.T) {
+		e, err := parseWebSocketEvent([]byte(`{"event": "bts:request_reconnect", "channel": "", "data": ""}`))
+		if assert.NoError(t, err) && assert.IsType(t, &WsResponse{}, e) {
+			assert.Equal(t, WsEventRequestReconnect, e.(*WsResponse).Event)
+		}
+
+		e, err = parseWebSocketEvent([]byte(`{"event": "bts:heartbeat", "channel": "", "data": {"status": "success"}}`))
+		if assert.NoError(t, err) && assert.IsType(t, &WsResponse{}, e) {
+			assert.Equal(t, "success", e.(*WsResponse).Status)
+		}
+	})
+}
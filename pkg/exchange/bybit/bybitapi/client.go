@@ -16,6 +16,7 @@ import (
 	"github.com/c9s/requestgen"
 	"github.com/pkg/errors"
 
+	"github.com/c9s/bbgo/pkg/exchange/apistats"
 	"github.com/c9s/bbgo/pkg/types"
 )
 
@@ -46,7 +47,8 @@ func NewClient() (*RestClient, error) {
 		BaseAPIClient: requestgen.BaseAPIClient{
 			BaseURL: u,
 			HttpClient: &http.Client{
-				Timeout: defaultHTTPTimeout,
+				Timeout:   defaultHTTPTimeout,
+				Transport: apistats.NewTransport(types.ExchangeBybit, nil),
 			},
 		},
 	}, nil
@@ -44,6 +44,9 @@ func toGlobalTicker(stats bybitapi.Ticker, time time.Time) types.Ticker {
 		Buy:    stats.Bid1Price,
 		Sell:   stats.Ask1Price,
 		Time:   time,
+
+		BuySize:  stats.Bid1Size,
+		SellSize: stats.Ask1Size,
 	}
 }
 
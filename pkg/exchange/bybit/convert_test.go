@@ -127,6 +127,9 @@ func TestToGlobalTicker(t *testing.T) {
 		Low:    ticker.LowPrice24H,
 		Buy:    ticker.Bid1Price,
 		Sell:   ticker.Ask1Price,
+
+		BuySize:  ticker.Bid1Size,
+		SellSize: ticker.Ask1Size,
 	}
 
 	assert.Equal(t, toGlobalTicker(ticker, timeNow), exp)
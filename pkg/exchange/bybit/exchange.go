@@ -122,16 +122,13 @@ func (e *Exchange) QueryTicker(ctx context.Context, symbol string) (*types.Ticke
 
 func (e *Exchange) QueryTickers(ctx context.Context, symbols ...string) (map[string]types.Ticker, error) {
 	tickers := map[string]types.Ticker{}
-	if len(symbols) > 0 {
-		for _, s := range symbols {
-			t, err := e.QueryTicker(ctx, s)
-			if err != nil {
-				return nil, err
-			}
-
-			tickers[s] = *t
+	if len(symbols) == 1 {
+		t, err := e.QueryTicker(ctx, symbols[0])
+		if err != nil {
+			return nil, err
 		}
 
+		tickers[symbols[0]] = *t
 		return tickers, nil
 	}
 
@@ -147,7 +144,7 @@ func (e *Exchange) QueryTickers(ctx context.Context, symbols ...string) (map[str
 		tickers[s.Symbol] = toGlobalTicker(s, allTickers.ClosedTime.Time())
 	}
 
-	return tickers, nil
+	return types.FilterTickers(tickers, symbols...), nil
 }
 
 func (e *Exchange) QueryOpenOrders(ctx context.Context, symbol string) (orders []types.Order, err error) {
@@ -7,9 +7,11 @@ import (
 
 	"github.com/c9s/bbgo/pkg/exchange/binance"
 	"github.com/c9s/bbgo/pkg/exchange/bitget"
+	"github.com/c9s/bbgo/pkg/exchange/bitstamp"
 	"github.com/c9s/bbgo/pkg/exchange/bybit"
 	"github.com/c9s/bbgo/pkg/exchange/kucoin"
 	"github.com/c9s/bbgo/pkg/exchange/max"
+	"github.com/c9s/bbgo/pkg/exchange/mexc"
 	"github.com/c9s/bbgo/pkg/exchange/okex"
 	"github.com/c9s/bbgo/pkg/types"
 )
@@ -48,6 +50,12 @@ func New(n types.ExchangeName, key, secret, passphrase string) (types.ExchangeMi
 	case types.ExchangeBybit:
 		return bybit.New(key, secret)
 
+	case types.ExchangeMEXC:
+		return mexc.New(key, secret), nil
+
+	case types.ExchangeBitstamp:
+		return bitstamp.New(key, secret), nil
+
 	default:
 		return nil, fmt.Errorf("unsupported exchange: %v", n)
 
@@ -64,6 +64,9 @@ func toGlobalTicker(s kucoinapi.Ticker24H) types.Ticker {
 		Low:    s.Low,
 		Buy:    s.Buy,
 		Sell:   s.Sell,
+
+		BuySize:  s.BestBidSize,
+		SellSize: s.BestAskSize,
 	}
 }
 
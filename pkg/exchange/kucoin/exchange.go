@@ -99,7 +99,7 @@ func (e *Exchange) QueryMarkets(ctx context.Context) (types.MarketMap, error) {
 }
 
 func (e *Exchange) QueryTicker(ctx context.Context, symbol string) (*types.Ticker, error) {
-	s, err := e.client.MarketDataService.GetTicker24HStat(symbol)
+	s, err := e.client.MarketDataService.GetTicker24HStat(toLocalSymbol(symbol))
 	if err != nil {
 		return nil, err
 	}
@@ -110,16 +110,13 @@ func (e *Exchange) QueryTicker(ctx context.Context, symbol string) (*types.Ticke
 
 func (e *Exchange) QueryTickers(ctx context.Context, symbols ...string) (map[string]types.Ticker, error) {
 	tickers := map[string]types.Ticker{}
-	if len(symbols) > 0 {
-		for _, s := range symbols {
-			t, err := e.QueryTicker(ctx, s)
-			if err != nil {
-				return nil, err
-			}
-
-			tickers[s] = *t
+	if len(symbols) == 1 {
+		t, err := e.QueryTicker(ctx, symbols[0])
+		if err != nil {
+			return nil, err
 		}
 
+		tickers[symbols[0]] = *t
 		return tickers, nil
 	}
 
@@ -129,10 +126,10 @@ func (e *Exchange) QueryTickers(ctx context.Context, symbols ...string) (map[str
 	}
 
 	for _, s := range allTickers.Ticker {
-		tickers[s.Symbol] = toGlobalTicker(s)
+		tickers[toGlobalSymbol(s.Symbol)] = toGlobalTicker(s)
 	}
 
-	return tickers, nil
+	return types.FilterTickers(tickers, symbols...), nil
 }
 
 // From the doc
@@ -84,6 +84,7 @@ type Ticker struct {
 	BestAsk     fixedpoint.Value           `json:"bestAsk"`
 	BestBid     fixedpoint.Value           `json:"bestBid"`
 	BestBidSize fixedpoint.Value           `json:"bestBidSize"`
+	BestAskSize fixedpoint.Value           `json:"bestAskSize"`
 	Time        types.MillisecondTimestamp `json:"time"`
 }
 
@@ -105,7 +106,9 @@ func (s *MarketDataService) NewGetTickerRequest(symbol string) *GetTickerRequest
             "symbol": "BTC-USDT",   // symbol
             "symbolName":"BTC-USDT", // SymbolName of trading pairs, it would change after renaming
             "buy": "11328.9",   // bestAsk
+            "bestBidSize": "0.1",
             "sell": "11329",    // bestBid
+            "bestAskSize": "0.1",
             "changeRate": "-0.0055",    // 24h change rate
             "changePrice": "-63.6", // 24h change price
             "high": "11610",    // 24h highest price
@@ -128,6 +131,8 @@ type Ticker24H struct {
 	SymbolName   string           `json:"symbolName"`
 	Buy          fixedpoint.Value `json:"buy"`
 	Sell         fixedpoint.Value `json:"sell"`
+	BestBidSize  fixedpoint.Value `json:"bestBidSize"`
+	BestAskSize  fixedpoint.Value `json:"bestAskSize"`
 	ChangeRate   fixedpoint.Value `json:"changeRate"`
 	ChangePrice  fixedpoint.Value `json:"changePrice"`
 	High         fixedpoint.Value `json:"high"`
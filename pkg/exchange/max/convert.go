@@ -27,6 +27,22 @@ func toGlobalSymbol(symbol string) string {
 	return strings.ToUpper(symbol)
 }
 
+// toGlobalTicker converts the max ticker, the ticker doesn't include the last trade size
+func toGlobalTicker(t max.Ticker) types.Ticker {
+	return types.Ticker{
+		Time:     t.Time,
+		Volume:   t.Volume,
+		Last:     t.Last,
+		Open:     t.Open,
+		High:     t.High,
+		Low:      t.Low,
+		Buy:      t.Buy,
+		Sell:     t.Sell,
+		BuySize:  t.BuyVolume,
+		SellSize: t.SellVolume,
+	}
+}
+
 func toLocalSideType(side types.SideType) string {
 	return strings.ToLower(string(side))
 }
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"testing"
 
+	max "github.com/c9s/bbgo/pkg/exchange/max/maxapi"
 	v3 "github.com/c9s/bbgo/pkg/exchange/max/maxapi/v3"
 	"github.com/c9s/bbgo/pkg/types"
 	"github.com/stretchr/testify/assert"
@@ -114,3 +115,30 @@ func Test_toGlobalTradeV3(t *testing.T) {
 		assert.Equal(types.SideTypeSell, trades[1].Side)
 	})
 }
+
+func Test_toGlobalTicker(t *testing.T) {
+	str := `{
+		"at": 1537410304,
+		"buy": "200001.0",
+		"buy_vol": "0.3",
+		"sell": "200002.0",
+		"sell_vol": "1.2",
+		"open": "195000.0",
+		"low": "190000.0",
+		"high": "205000.0",
+		"last": "200001.5",
+		"vol": "350.5",
+		"vol_in_btc": "350.5"
+	}`
+
+	var ticker max.Ticker
+	if assert.NoError(t, json.Unmarshal([]byte(str), &ticker)) {
+		globalTicker := toGlobalTicker(ticker)
+		assert.Equal(t, "200001", globalTicker.Buy.String())
+		assert.Equal(t, "0.3", globalTicker.BuySize.String())
+		assert.Equal(t, "200002", globalTicker.Sell.String())
+		assert.Equal(t, "1.2", globalTicker.SellSize.String())
+		assert.Equal(t, "200001.5", globalTicker.Last.String())
+		assert.Equal(t, "350.5", globalTicker.Volume.String())
+	}
+}
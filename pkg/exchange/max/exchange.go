@@ -74,24 +74,30 @@ func (e *Exchange) Name() types.ExchangeName {
 func (e *Exchange) QueryTicker(ctx context.Context, symbol string) (*types.Ticker, error) {
 	req := e.client.NewGetTickerRequest()
 	req.Market(toLocalSymbol(symbol))
-	ticker, err := req.Do(ctx)
+	maxTicker, err := req.Do(ctx)
 
 	if err != nil {
 		return nil, err
 	}
 
-	return &types.Ticker{
-		Time:   ticker.Time,
-		Volume: ticker.Volume,
-		Last:   ticker.Last,
-		Open:   ticker.Open,
-		High:   ticker.High,
-		Low:    ticker.Low,
-		Buy:    ticker.Buy,
-		Sell:   ticker.Sell,
-	}, nil
+	ticker := toGlobalTicker(*maxTicker)
+
+	// the max ticker doesn't include the last trade size, it's filled by the latest public trade
+	trades, err := e.client.NewGetPublicTradesRequest().
+		Market(toLocalSymbol(symbol)).
+		Limit(1).
+		Do(ctx)
+	if err != nil {
+		log.WithError(err).Warnf("unable to query the latest %s trade for the ticker", symbol)
+	} else if len(trades) > 0 {
+		ticker.LastSize = trades[0].Volume
+	}
+
+	return &ticker, nil
 }
 
+// QueryTickers queries the tickers of the symbols by a single all-tickers request, the last trade sizes are
+// only filled when one symbol is given, since they are queried from the public trades of each market.
 func (e *Exchange) QueryTickers(ctx context.Context, symbol ...string) (map[string]types.Ticker, error) {
 	if err := e.marketDataLimiter.Wait(ctx); err != nil {
 		return nil, err
@@ -123,16 +129,7 @@ func (e *Exchange) QueryTickers(ctx context.Context, symbol ...string) (map[stri
 				continue
 			}
 
-			tickers[toGlobalSymbol(k)] = types.Ticker{
-				Time:   v.Time,
-				Volume: v.Volume,
-				Last:   v.Last,
-				Open:   v.Open,
-				High:   v.High,
-				Low:    v.Low,
-				Buy:    v.Buy,
-				Sell:   v.Sell,
-			}
+			tickers[toGlobalSymbol(k)] = toGlobalTicker(v)
 		}
 	}
 
@@ -0,0 +1,21 @@
+package max
+
+import (
+	"github.com/c9s/requestgen"
+)
+
+//go:generate -command GetRequest requestgen -method GET
+//go:generate -command PostRequest requestgen -method POST
+//go:generate -command DeleteRequest requestgen -method DELETE
+
+//go:generate GetRequest -url "/api/v2/trades" -type GetPublicTradesRequest -responseType []Trade
+type GetPublicTradesRequest struct {
+	client requestgen.APIClient
+
+	market string `param:"market,required"`
+	limit  *int   `param:"limit"`
+}
+
+func (c *RestClient) NewGetPublicTradesRequest() *GetPublicTradesRequest {
+	return &GetPublicTradesRequest{client: c}
+}
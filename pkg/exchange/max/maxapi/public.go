@@ -32,7 +32,9 @@ type Ticker struct {
 
 	At          int64            `json:"at"`
 	Buy         fixedpoint.Value `json:"buy"`
+	BuyVolume   fixedpoint.Value `json:"buy_vol"`
 	Sell        fixedpoint.Value `json:"sell"`
+	SellVolume  fixedpoint.Value `json:"sell_vol"`
 	Open        fixedpoint.Value `json:"open"`
 	High        fixedpoint.Value `json:"high"`
 	Low         fixedpoint.Value `json:"low"`
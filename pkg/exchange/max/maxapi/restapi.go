@@ -23,6 +23,8 @@ import (
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 
+	"github.com/c9s/bbgo/pkg/exchange/apistats"
+	"github.com/c9s/bbgo/pkg/types"
 	"github.com/c9s/bbgo/pkg/util"
 	"github.com/c9s/bbgo/pkg/util/backoff"
 	"github.com/c9s/bbgo/pkg/version"
@@ -103,7 +105,7 @@ var httpTransport = &http.Transport{
 
 var defaultHttpClient = &http.Client{
 	Timeout:   defaultHTTPTimeout,
-	Transport: httpTransport,
+	Transport: apistats.NewTransport(types.ExchangeMax, httpTransport),
 }
 
 type RestClient struct {
@@ -0,0 +1,309 @@
+package mexc
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/exchange/mexc/mexcapi"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// toGlobalSymbol converts the MEXC symbol into the global symbol,
+// the symbols are upper-cased without the separator in the v3 API, e.g. BTCUSDT,
+// but the legacy API and some of the websocket channels use the underscore separated ones, e.g. BTC_USDT.
+func toGlobalSymbol(symbol string) string {
+	return strings.ToUpper(strings.ReplaceAll(symbol, "_", ""))
+}
+
+// toLocalSymbol converts the global symbol into the symbol of the v3 API
+func toLocalSymbol(symbol string) string {
+	return strings.ToUpper(symbol)
+}
+
+// toGlobalID converts the string id of MEXC into the numeric id,
+// the order ids are prefixed by the shard, e.g. C02__413823734787342336, and the trade ids are suffixed, e.g. 505979017439002624X1.
+// The numeric part of the id is used when it's the only number in the id, otherwise the id is hashed.
+func toGlobalID(id string) uint64 {
+	if n, err := strconv.ParseUint(id, 10, 64); err == nil {
+		return n
+	}
+
+	if idx := strings.LastIndex(id, "__"); idx >= 0 {
+		if n, err := strconv.ParseUint(id[idx+2:], 10, 64); err == nil {
+			return n
+		}
+	}
+
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(id))
+	return h.Sum64()
+}
+
+func precisionToStep(precision int) fixedpoint.Value {
+	return fixedpoint.NewFromFloat(math.Pow10(-precision))
+}
+
+func toGlobalMarket(s mexcapi.Symbol) types.Market {
+	return types.Market{
+		Exchange:        types.ExchangeMEXC,
+		Symbol:          toGlobalSymbol(s.Symbol),
+		LocalSymbol:     s.Symbol,
+		PricePrecision:  s.QuotePrecision,
+		VolumePrecision: s.BaseAssetPrecision,
+		QuoteCurrency:   s.QuoteAsset,
+		BaseCurrency:    s.BaseAsset,
+		MinNotional:     s.QuoteAmountPrecision,
+		MinAmount:       s.QuoteAmountPrecision,
+		MinQuantity:     s.BaseSizePrecision,
+		MaxQuantity:     fixedpoint.NewFromFloat(math.MaxFloat64),
+		StepSize:        precisionToStep(s.BaseAssetPrecision),
+		MinPrice:        fixedpoint.Zero,
+		MaxPrice:        fixedpoint.NewFromFloat(math.MaxFloat64),
+		TickSize:        precisionToStep(s.QuotePrecision),
+	}
+}
+
+func toGlobalTicker(t mexcapi.Ticker) types.Ticker {
+	return types.Ticker{
+		Time:     t.CloseTime.Time(),
+		Volume:   t.Volume,
+		Last:     t.LastPrice,
+		Open:     t.OpenPrice,
+		High:     t.HighPrice,
+		Low:      t.LowPrice,
+		Buy:      t.BidPrice,
+		BuySize:  t.BidQty,
+		Sell:     t.AskPrice,
+		SellSize: t.AskQty,
+	}
+}
+
+func toGlobalBalanceMap(balances []mexcapi.Balance) types.BalanceMap {
+	bm := types.BalanceMap{}
+	for _, b := range balances {
+		bm[b.Asset] = types.Balance{
+			Currency:  b.Asset,
+			Available: b.Free,
+			Locked:    b.Locked,
+		}
+	}
+	return bm
+}
+
+var supportedIntervals = map[types.Interval]int{
+	types.Interval1m:  1 * 60,
+	types.Interval5m:  5 * 60,
+	types.Interval15m: 15 * 60,
+	types.Interval30m: 30 * 60,
+	types.Interval1h:  60 * 60,
+	types.Interval4h:  4 * 60 * 60,
+	types.Interval1d:  24 * 60 * 60,
+	types.Interval1w:  7 * 24 * 60 * 60,
+	types.Interval1mo: 30 * 24 * 60 * 60,
+}
+
+// localIntervals maps the global interval to the REST API interval and the websocket interval
+var localIntervals = map[types.Interval][2]string{
+	types.Interval1m:  {"1m", "Min1"},
+	types.Interval5m:  {"5m", "Min5"},
+	types.Interval15m: {"15m", "Min15"},
+	types.Interval30m: {"30m", "Min30"},
+	types.Interval1h:  {"60m", "Min60"},
+	types.Interval4h:  {"4h", "Hour4"},
+	types.Interval1d:  {"1d", "Day1"},
+	types.Interval1w:  {"1W", "Week1"},
+	types.Interval1mo: {"1M", "Month1"},
+}
+
+func toLocalInterval(interval types.Interval) (string, error) {
+	if s, ok := localIntervals[interval]; ok {
+		return s[0], nil
+	}
+
+	return "", fmt.Errorf("interval %s is not supported", interval)
+}
+
+func toLocalWsInterval(interval types.Interval) (string, error) {
+	if s, ok := localIntervals[interval]; ok {
+		return s[1], nil
+	}
+
+	return "", fmt.Errorf("interval %s is not supported", interval)
+}
+
+func toGlobalWsInterval(interval string) (types.Interval, error) {
+	for k, s := range localIntervals {
+		if s[1] == interval {
+			return k, nil
+		}
+	}
+
+	return "", fmt.Errorf("unexpected interval %s", interval)
+}
+
+func toGlobalKLine(symbol string, interval types.Interval, k mexcapi.KLine) types.KLine {
+	return types.KLine{
+		Exchange:    types.ExchangeMEXC,
+		Symbol:      symbol,
+		StartTime:   types.Time(k.OpenTime.Time()),
+		EndTime:     types.Time(k.OpenTime.Time().Add(interval.Duration() - time.Millisecond)),
+		Interval:    interval,
+		Open:        k.Open,
+		Close:       k.Close,
+		High:        k.High,
+		Low:         k.Low,
+		Volume:      k.Volume,
+		QuoteVolume: k.QuoteVolume,
+		Closed:      k.CloseTime.Time().Before(time.Now()),
+	}
+}
+
+func toGlobalSideType(side mexcapi.SideType) (types.SideType, error) {
+	switch side {
+	case mexcapi.SideTypeBuy:
+		return types.SideTypeBuy, nil
+	case mexcapi.SideTypeSell:
+		return types.SideTypeSell, nil
+	}
+
+	return "", fmt.Errorf("unexpected side: %s", side)
+}
+
+func toLocalSideType(side types.SideType) (mexcapi.SideType, error) {
+	switch side {
+	case types.SideTypeBuy:
+		return mexcapi.SideTypeBuy, nil
+	case types.SideTypeSell:
+		return mexcapi.SideTypeSell, nil
+	}
+
+	return "", fmt.Errorf("side type %s is not supported", side)
+}
+
+func toGlobalOrderType(orderType mexcapi.OrderType) (types.OrderType, types.TimeInForce, error) {
+	switch orderType {
+	case mexcapi.OrderTypeLimit:
+		return types.OrderTypeLimit, types.TimeInForceGTC, nil
+	case mexcapi.OrderTypeLimitMaker:
+		return types.OrderTypeLimitMaker, types.TimeInForceGTC, nil
+	case mexcapi.OrderTypeMarket:
+		return types.OrderTypeMarket, "", nil
+	case mexcapi.OrderTypeImmediateOrCancel:
+		return types.OrderTypeLimit, types.TimeInForceIOC, nil
+	case mexcapi.OrderTypeFillOrKill:
+		return types.OrderTypeLimit, types.TimeInForceFOK, nil
+	}
+
+	return "", "", fmt.Errorf("unexpected order type: %s", orderType)
+}
+
+func toLocalOrderType(orderType types.OrderType, timeInForce types.TimeInForce) (mexcapi.OrderType, error) {
+	switch orderType {
+	case types.OrderTypeLimitMaker:
+		return mexcapi.OrderTypeLimitMaker, nil
+
+	case types.OrderTypeMarket:
+		return mexcapi.OrderTypeMarket, nil
+
+	case types.OrderTypeLimit:
+		switch timeInForce {
+		case types.TimeInForceIOC:
+			return mexcapi.OrderTypeImmediateOrCancel, nil
+		case types.TimeInForceFOK:
+			return mexcapi.OrderTypeFillOrKill, nil
+		}
+		return mexcapi.OrderTypeLimit, nil
+	}
+
+	return "", fmt.Errorf("order type %s is not supported", orderType)
+}
+
+func toGlobalOrderStatus(status mexcapi.OrderStatus, executedQuantity fixedpoint.Value) (types.OrderStatus, error) {
+	switch status {
+	case mexcapi.OrderStatusNew:
+		return types.OrderStatusNew, nil
+	case mexcapi.OrderStatusPartiallyFilled:
+		return types.OrderStatusPartiallyFilled, nil
+	case mexcapi.OrderStatusFilled:
+		return types.OrderStatusFilled, nil
+	case mexcapi.OrderStatusCanceled, mexcapi.OrderStatusPartiallyCanceled:
+		if executedQuantity.Sign() > 0 {
+			return types.OrderStatusPartiallyFilled, nil
+		}
+		return types.OrderStatusCanceled, nil
+	}
+
+	return "", fmt.Errorf("unexpected order status: %s", status)
+}
+
+func toGlobalOrder(o mexcapi.Order) (*types.Order, error) {
+	side, err := toGlobalSideType(o.Side)
+	if err != nil {
+		return nil, err
+	}
+
+	orderType, timeInForce, err := toGlobalOrderType(o.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := toGlobalOrderStatus(o.Status, o.ExecutedQty)
+	if err != nil {
+		return nil, err
+	}
+
+	quantity := o.OrigQty
+	// the market buy order could be placed by the quote amount
+	if orderType == types.OrderTypeMarket && quantity.IsZero() {
+		quantity = o.ExecutedQty
+	}
+
+	return &types.Order{
+		SubmitOrder: types.SubmitOrder{
+			ClientOrderID: o.ClientOrderID,
+			Symbol:        toGlobalSymbol(o.Symbol),
+			Side:          side,
+			Type:          orderType,
+			Quantity:      quantity,
+			Price:         o.Price,
+			TimeInForce:   timeInForce,
+		},
+		Exchange:         types.ExchangeMEXC,
+		OrderID:          toGlobalID(o.OrderID),
+		UUID:             o.OrderID,
+		Status:           status,
+		OriginalStatus:   string(o.Status),
+		ExecutedQuantity: o.ExecutedQty,
+		IsWorking:        o.Status == mexcapi.OrderStatusNew || o.Status == mexcapi.OrderStatusPartiallyFilled,
+		CreationTime:     types.Time(o.Time.Time()),
+		UpdateTime:       types.Time(o.UpdateTime.Time()),
+	}, nil
+}
+
+func toGlobalTrade(t mexcapi.Trade) types.Trade {
+	side := types.SideTypeSell
+	if t.IsBuyer {
+		side = types.SideTypeBuy
+	}
+
+	return types.Trade{
+		ID:            toGlobalID(t.ID),
+		OrderID:       toGlobalID(t.OrderID),
+		Exchange:      types.ExchangeMEXC,
+		Price:         t.Price,
+		Quantity:      t.Qty,
+		QuoteQuantity: t.QuoteQty,
+		Symbol:        toGlobalSymbol(t.Symbol),
+		Side:          side,
+		IsBuyer:       t.IsBuyer,
+		IsMaker:       t.IsMaker,
+		Time:          types.Time(t.Time.Time()),
+		Fee:           t.Commission,
+		FeeCurrency:   t.CommissionAsset,
+	}
+}
@@ -0,0 +1,101 @@
+package mexc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/exchange/mexc/mexcapi"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func Test_toGlobalSymbol(t *testing.T) {
+	assert.Equal(t, "BTCUSDT", toGlobalSymbol("BTCUSDT"))
+	assert.Equal(t, "BTCUSDT", toGlobalSymbol("BTC_USDT"))
+	assert.Equal(t, "BTCUSDT", toGlobalSymbol("btc_usdt"))
+	assert.Equal(t, "BTCUSDT", toLocalSymbol("btcusdt"))
+}
+
+func Test_toGlobalID(t *testing.T) {
+	assert.Equal(t, uint64(413823734787342336), toGlobalID("413823734787342336"))
+	assert.Equal(t, uint64(413823734787342336), toGlobalID("C02__413823734787342336"))
+
+	// the hashed id is stable
+	id := toGlobalID("505979017439002624X1")
+	assert.NotZero(t, id)
+	assert.Equal(t, id, toGlobalID("505979017439002624X1"))
+	assert.NotEqual(t, id, toGlobalID("505979017439002624X2"))
+}
+
+func Test_toGlobalMarket(t *testing.T) {
+	market := toGlobalMarket(mexcapi.Symbol{
+		Symbol:               "BTCUSDT",
+		Status:               "1",
+		BaseAsset:            "BTC",
+		BaseAssetPrecision:   6,
+		QuoteAsset:           "USDT",
+		QuotePrecision:       2,
+		BaseSizePrecision:    fixedpoint.NewFromFloat(0.0001),
+		QuoteAmountPrecision: fixedpoint.NewFromFloat(5),
+		IsSpotTradingAllowed: true,
+	})
+
+	assert.Equal(t, types.ExchangeMEXC, market.Exchange)
+	assert.Equal(t, "BTCUSDT", market.Symbol)
+	assert.Equal(t, 2, market.PricePrecision)
+	assert.Equal(t, 6, market.VolumePrecision)
+	assert.Equal(t, fixedpoint.NewFromFloat(0.01), market.TickSize)
+	assert.Equal(t, fixedpoint.NewFromFloat(0.000001), market.StepSize)
+	assert.Equal(t, fixedpoint.NewFromFloat(0.0001), market.MinQuantity)
+	assert.Equal(t, fixedpoint.NewFromFloat(5), market.MinNotional)
+}
+
+func Test_toLocalOrderType(t *testing.T) {
+	orderType, err := toLocalOrderType(types.OrderTypeLimit, types.TimeInForceIOC)
+	assert.NoError(t, err)
+	assert.Equal(t, mexcapi.OrderTypeImmediateOrCancel, orderType)
+
+	orderType, err = toLocalOrderType(types.OrderTypeLimitMaker, "")
+	assert.NoError(t, err)
+	assert.Equal(t, mexcapi.OrderTypeLimitMaker, orderType)
+
+	_, err = toLocalOrderType(types.OrderTypeStopLimit, "")
+	assert.Error(t, err)
+}
+
+func Test_toGlobalOrder(t *testing.T) {
+	order, err := toGlobalOrder(mexcapi.Order{
+		Symbol:        "BTCUSDT",
+		OrderID:       "C02__413823734787342336",
+		ClientOrderID: "abc",
+		Price:         fixedpoint.NewFromFloat(20000),
+		OrigQty:       fixedpoint.NewFromFloat(0.01),
+		ExecutedQty:   fixedpoint.NewFromFloat(0.005),
+		Status:        mexcapi.OrderStatusPartiallyCanceled,
+		Type:          mexcapi.OrderTypeLimit,
+		Side:          mexcapi.SideTypeBuy,
+	})
+	if assert.NoError(t, err) {
+		assert.Equal(t, uint64(413823734787342336), order.OrderID)
+		assert.Equal(t, "C02__413823734787342336", order.UUID)
+		assert.Equal(t, types.OrderStatusPartiallyFilled, order.Status)
+		assert.Equal(t, types.SideTypeBuy, order.Side)
+		assert.Equal(t, types.TimeInForceGTC, order.TimeInForce)
+		assert.False(t, order.IsWorking)
+	}
+}
+
+func Test_toLocalWsInterval(t *testing.T) {
+	for interval := range supportedIntervals {
+		local, err := toLocalWsInterval(interval)
+		if assert.NoError(t, err) {
+			global, err := toGlobalWsInterval(local)
+			assert.NoError(t, err)
+			assert.Equal(t, interval, global)
+		}
+	}
+
+	_, err := toLocalInterval(types.Interval3m)
+	assert.Error(t, err)
+}
@@ -0,0 +1,507 @@
+package mexc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.uber.org/multierr"
+	"golang.org/x/time/rate"
+
+	"github.com/c9s/bbgo/pkg/exchange/mexc/mexcapi"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+const (
+	defaultQueryLimit = 1000
+	defaultKLineLimit = 1000
+
+	// maxOrderQueryPeriod is the maximum period of the order history query
+	maxOrderQueryPeriod = 7 * 24 * time.Hour
+
+	// maxTradeQueryPeriod is the maximum period of the trade history query
+	maxTradeQueryPeriod = 24 * time.Hour
+)
+
+// https://mexcdevelop.github.io/apidocs/spot_v3_en/#limits
+// The rate limits are 500 weights per 10 seconds for each endpoint by the ip and the uid.
+var (
+	sharedRateLimiter     = rate.NewLimiter(rate.Every(time.Second/10), 10)
+	orderRateLimiter      = rate.NewLimiter(rate.Every(time.Second/20), 20)
+	queryOrderRateLimiter = rate.NewLimiter(rate.Every(time.Second/10), 10)
+	queryTradeRateLimiter = rate.NewLimiter(rate.Every(time.Second/5), 5)
+
+	log = logrus.WithFields(logrus.Fields{
+		"exchange": "mexc",
+	})
+
+	_ types.ExchangeAccountService    = &Exchange{}
+	_ types.ExchangeMarketDataService = &Exchange{}
+	_ types.CustomIntervalProvider    = &Exchange{}
+	_ types.ExchangeMinimal           = &Exchange{}
+	_ types.ExchangeTradeService      = &Exchange{}
+	_ types.Exchange                  = &Exchange{}
+	_ types.ExchangeOrderQueryService = &Exchange{}
+)
+
+type Exchange struct {
+	key, secret string
+	client      *mexcapi.RestClient
+
+	// orderIDs maps the numeric order id to the original string order id,
+	// since the order query only carries the numeric id converted by toGlobalID.
+	orderIDs sync.Map
+}
+
+func New(key, secret string) *Exchange {
+	client := mexcapi.NewClient()
+	if len(key) > 0 && len(secret) > 0 {
+		client.Auth(key, secret)
+	}
+
+	return &Exchange{
+		key: key,
+		// pragma: allowlist nextline secret
+		secret: secret,
+		client: client,
+	}
+}
+
+func (e *Exchange) Name() types.ExchangeName {
+	return types.ExchangeMEXC
+}
+
+// PlatformFeeCurrency returns the platform token MX, the fee discount is applied when the MX deduction is enabled.
+func (e *Exchange) PlatformFeeCurrency() string {
+	return "MX"
+}
+
+func (e *Exchange) NewStream() types.Stream {
+	return NewStream(e.client, e)
+}
+
+func (e *Exchange) QueryMarkets(ctx context.Context) (types.MarketMap, error) {
+	if err := sharedRateLimiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("markets rate limiter wait error: %w", err)
+	}
+
+	info, err := e.client.NewGetExchangeInfoRequest().Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query exchange info, err: %w", err)
+	}
+
+	markets := types.MarketMap{}
+	for _, s := range info.Symbols {
+		if !s.IsOnline() {
+			continue
+		}
+
+		markets.Add(toGlobalMarket(s))
+	}
+
+	return markets, nil
+}
+
+func (e *Exchange) QueryTicker(ctx context.Context, symbol string) (*types.Ticker, error) {
+	if err := sharedRateLimiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("ticker rate limiter wait error: %w", err)
+	}
+
+	t, err := e.client.NewGetTickerRequest().Symbol(toLocalSymbol(symbol)).Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query ticker, symbol: %s, err: %w", symbol, err)
+	}
+
+	ticker := toGlobalTicker(*t)
+	return &ticker, nil
+}
+
+func (e *Exchange) QueryTickers(ctx context.Context, symbols ...string) (map[string]types.Ticker, error) {
+	tickers := map[string]types.Ticker{}
+	if len(symbols) == 1 {
+		t, err := e.QueryTicker(ctx, symbols[0])
+		if err != nil {
+			return nil, err
+		}
+
+		tickers[symbols[0]] = *t
+		return tickers, nil
+	}
+
+	if err := sharedRateLimiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("tickers rate limiter wait error: %w", err)
+	}
+
+	allTickers, err := e.client.NewGetTickersRequest().Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tickers, err: %w", err)
+	}
+
+	for _, t := range allTickers {
+		tickers[toGlobalSymbol(t.Symbol)] = toGlobalTicker(t)
+	}
+
+	return types.FilterTickers(tickers, symbols...), nil
+}
+
+func (e *Exchange) QueryKLines(ctx context.Context, symbol string, interval types.Interval, options types.KLineQueryOptions) ([]types.KLine, error) {
+	localInterval, err := toLocalInterval(interval)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := options.Limit
+	if limit > defaultKLineLimit || limit <= 0 {
+		limit = defaultKLineLimit
+	}
+
+	req := e.client.NewGetKLinesRequest().
+		Symbol(toLocalSymbol(symbol)).
+		Interval(localInterval).
+		Limit(limit)
+
+	if options.StartTime != nil {
+		req.StartTime(*options.StartTime)
+	}
+
+	if options.EndTime != nil {
+		req.EndTime(*options.EndTime)
+	}
+
+	if err := sharedRateLimiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("query klines rate limiter wait error: %w", err)
+	}
+
+	resp, err := req.Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query klines, err: %w", err)
+	}
+
+	var kLines []types.KLine
+	for _, k := range resp {
+		kLines = append(kLines, toGlobalKLine(symbol, interval, k))
+	}
+
+	return types.SortKLinesAscending(kLines), nil
+}
+
+func (e *Exchange) SupportedInterval() map[types.Interval]int {
+	return supportedIntervals
+}
+
+func (e *Exchange) IsSupportedInterval(interval types.Interval) bool {
+	_, ok := supportedIntervals[interval]
+	return ok
+}
+
+func (e *Exchange) QueryAccount(ctx context.Context) (*types.Account, error) {
+	if err := sharedRateLimiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("query account rate limiter wait error: %w", err)
+	}
+
+	account, err := e.client.NewGetAccountRequest().Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query account, err: %w", err)
+	}
+
+	a := &types.Account{
+		AccountType: types.AccountTypeSpot,
+		// the commissions are in basis points
+		MakerFeeRate: account.MakerCommission.Div(fixedpoint.NewFromInt(10000)),
+		TakerFeeRate: account.TakerCommission.Div(fixedpoint.NewFromInt(10000)),
+	}
+	a.UpdateBalances(toGlobalBalanceMap(account.Balances))
+	return a, nil
+}
+
+func (e *Exchange) QueryAccountBalances(ctx context.Context) (types.BalanceMap, error) {
+	account, err := e.QueryAccount(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return account.Balances(), nil
+}
+
+func (e *Exchange) SubmitOrder(ctx context.Context, order types.SubmitOrder) (*types.Order, error) {
+	if len(order.Market.Symbol) == 0 {
+		return nil, fmt.Errorf("order.Market.Symbol is required: %+v", order)
+	}
+
+	side, err := toLocalSideType(order.Side)
+	if err != nil {
+		return nil, err
+	}
+
+	orderType, err := toLocalOrderType(order.Type, order.TimeInForce)
+	if err != nil {
+		return nil, err
+	}
+
+	req := e.client.NewPlaceOrderRequest().
+		Symbol(toLocalSymbol(order.Symbol)).
+		Side(side).
+		OrderType(orderType)
+
+	switch {
+	case order.Type == types.OrderTypeMarket && order.Side == types.SideTypeBuy && order.Price.Sign() > 0:
+		// the market buy order is placed by the quote amount when the price is given
+		req.QuoteOrderQty(order.Quantity.Mul(order.Price).FormatString(order.Market.PricePrecision))
+
+	default:
+		req.Quantity(order.Market.FormatQuantity(order.Quantity))
+	}
+
+	if order.Type != types.OrderTypeMarket {
+		req.Price(order.Market.FormatPrice(order.Price))
+	}
+
+	if len(order.ClientOrderID) > 0 {
+		req.NewClientOrderID(order.ClientOrderID)
+	}
+
+	if err := orderRateLimiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("place order rate limiter wait error: %w", err)
+	}
+
+	res, err := req.Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to place order, order: %#v, err: %w", order, err)
+	}
+
+	e.orderIDs.Store(toGlobalID(res.OrderID), res.OrderID)
+
+	return &types.Order{
+		SubmitOrder:      order,
+		Exchange:         types.ExchangeMEXC,
+		OrderID:          toGlobalID(res.OrderID),
+		UUID:             res.OrderID,
+		Status:           types.OrderStatusNew,
+		ExecutedQuantity: fixedpoint.Zero,
+		IsWorking:        true,
+		CreationTime:     types.Time(res.TransactTime.Time()),
+		UpdateTime:       types.Time(res.TransactTime.Time()),
+	}, nil
+}
+
+func (e *Exchange) CancelOrders(ctx context.Context, orders ...types.Order) (errs error) {
+	for _, order := range orders {
+		req := e.client.NewCancelOrderRequest().Symbol(toLocalSymbol(order.Symbol))
+
+		switch {
+		case len(order.UUID) > 0:
+			req.OrderID(order.UUID)
+
+		case len(order.ClientOrderID) > 0:
+			req.OrigClientOrderID(order.ClientOrderID)
+
+		default:
+			errs = multierr.Append(errs, fmt.Errorf("the order uuid and client order id are empty, order: %#v", order))
+			continue
+		}
+
+		if err := orderRateLimiter.Wait(ctx); err != nil {
+			errs = multierr.Append(errs, fmt.Errorf("cancel order rate limiter wait error: %w", err))
+			continue
+		}
+
+		if _, err := req.Do(ctx); err != nil {
+			errs = multierr.Append(errs, fmt.Errorf("failed to cancel order, order: %s, err: %w", order.String(), err))
+		}
+	}
+
+	return errs
+}
+
+func (e *Exchange) QueryOpenOrders(ctx context.Context, symbol string) (orders []types.Order, err error) {
+	if err := queryOrderRateLimiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("query open orders rate limiter wait error: %w", err)
+	}
+
+	res, err := e.client.NewGetOpenOrdersRequest().Symbol(toLocalSymbol(symbol)).Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query open orders, err: %w", err)
+	}
+
+	for _, o := range res {
+		order, err := e.toGlobalOrder(o)
+		if err != nil {
+			return nil, err
+		}
+
+		orders = append(orders, *order)
+	}
+
+	return orders, nil
+}
+
+func (e *Exchange) QueryOrder(ctx context.Context, q types.OrderQuery) (*types.Order, error) {
+	if len(q.Symbol) == 0 {
+		return nil, errors.New("symbol is required")
+	}
+
+	req := e.client.NewGetOrderRequest().Symbol(toLocalSymbol(q.Symbol))
+	switch {
+	case len(q.OrderID) > 0:
+		req.OrderID(e.localOrderID(q.OrderID))
+
+	case len(q.ClientOrderID) > 0:
+		req.OrigClientOrderID(q.ClientOrderID)
+
+	default:
+		return nil, errors.New("one of OrderID/ClientOrderID is required")
+	}
+
+	if err := queryOrderRateLimiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("query order rate limiter wait error: %w", err)
+	}
+
+	order, err := req.Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query order, query: %+v, err: %w", q, err)
+	}
+
+	return e.toGlobalOrder(*order)
+}
+
+func (e *Exchange) QueryOrderTrades(ctx context.Context, q types.OrderQuery) (trades []types.Trade, err error) {
+	if len(q.Symbol) == 0 {
+		return nil, errors.New("symbol is required")
+	}
+
+	if len(q.OrderID) == 0 {
+		return nil, errors.New("order id is required, the client order id is not supported")
+	}
+
+	if err := queryTradeRateLimiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("query order trades rate limiter wait error: %w", err)
+	}
+
+	res, err := e.client.NewGetMyTradesRequest().
+		Symbol(toLocalSymbol(q.Symbol)).
+		OrderID(e.localOrderID(q.OrderID)).
+		Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query order trades, err: %w", err)
+	}
+
+	for _, t := range res {
+		trades = append(trades, toGlobalTrade(t))
+	}
+
+	return trades, nil
+}
+
+// QueryClosedOrders queries the closed orders in the time range, the maximum period is 7 days.
+// The lastOrderID is not supported by MEXC, and the returned orders are sorted by the creation time.
+func (e *Exchange) QueryClosedOrders(ctx context.Context, symbol string, since, until time.Time, lastOrderID uint64) (orders []types.Order, err error) {
+	if until.IsZero() {
+		until = time.Now()
+	}
+
+	if until.Sub(since) > maxOrderQueryPeriod {
+		until = since.Add(maxOrderQueryPeriod)
+	}
+
+	if err := queryOrderRateLimiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("query closed orders rate limiter wait error: %w", err)
+	}
+
+	res, err := e.client.NewGetAllOrdersRequest().
+		Symbol(toLocalSymbol(symbol)).
+		StartTime(since).
+		EndTime(until).
+		Limit(defaultQueryLimit).
+		Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query closed orders, err: %w", err)
+	}
+
+	for _, o := range res {
+		order, err2 := e.toGlobalOrder(o)
+		if err2 != nil {
+			err = multierr.Append(err, err2)
+			continue
+		}
+
+		if order.Status.Closed() {
+			orders = append(orders, *order)
+		}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return types.SortOrdersAscending(orders), nil
+}
+
+// QueryTrades queries the trades in the time range, the maximum period is 24 hours,
+// and only the trades of the last month can be queried. The LastTradeID is not supported by MEXC.
+func (e *Exchange) QueryTrades(ctx context.Context, symbol string, options *types.TradeQueryOptions) (trades []types.Trade, err error) {
+	req := e.client.NewGetMyTradesRequest().Symbol(toLocalSymbol(symbol))
+
+	if options.StartTime != nil {
+		startTime := *options.StartTime
+		endTime := startTime.Add(maxTradeQueryPeriod)
+		if options.EndTime != nil && options.EndTime.Before(endTime) {
+			endTime = *options.EndTime
+		}
+
+		req.StartTime(startTime).EndTime(endTime)
+	} else if options.EndTime != nil {
+		req.StartTime(options.EndTime.Add(-maxTradeQueryPeriod)).EndTime(*options.EndTime)
+	}
+
+	limit := int(options.Limit)
+	if limit > defaultQueryLimit || limit <= 0 {
+		limit = defaultQueryLimit
+	}
+	req.Limit(limit)
+
+	if err := queryTradeRateLimiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("query trades rate limiter wait error: %w", err)
+	}
+
+	res, err := req.Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query trades, err: %w", err)
+	}
+
+	for _, t := range res {
+		trades = append(trades, toGlobalTrade(t))
+	}
+
+	return types.SortTradesAscending(trades), nil
+}
+
+// localOrderID returns the original string order id of the numeric order id,
+// the id is returned as-is if it's not seen by the exchange instance.
+func (e *Exchange) localOrderID(orderID string) string {
+	id, err := strconv.ParseUint(orderID, 10, 64)
+	if err != nil {
+		return orderID
+	}
+
+	if v, ok := e.orderIDs.Load(id); ok {
+		return v.(string)
+	}
+
+	return orderID
+}
+
+// toGlobalOrder converts the order and remembers its original string order id
+func (e *Exchange) toGlobalOrder(o mexcapi.Order) (*types.Order, error) {
+	order, err := toGlobalOrder(o)
+	if err != nil {
+		return nil, err
+	}
+
+	e.orderIDs.Store(order.OrderID, o.OrderID)
+	return order, nil
+}
@@ -0,0 +1,18 @@
+package mexcapi
+
+import (
+	"github.com/c9s/requestgen"
+)
+
+//go:generate requestgen -method DELETE -url "/api/v3/order" -type CancelOrderRequest -responseType .Order
+type CancelOrderRequest struct {
+	client requestgen.AuthenticatedAPIClient
+
+	symbol            string  `param:"symbol"`
+	orderID           *string `param:"orderId"`
+	origClientOrderID *string `param:"origClientOrderId"`
+}
+
+func (c *RestClient) NewCancelOrderRequest() *CancelOrderRequest {
+	return &CancelOrderRequest{client: c}
+}
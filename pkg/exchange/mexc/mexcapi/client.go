@@ -0,0 +1,174 @@
+package mexcapi
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/c9s/requestgen"
+	"github.com/pkg/errors"
+
+	"github.com/c9s/bbgo/pkg/exchange/apistats"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+const (
+	defaultHTTPTimeout = time.Second * 15
+
+	RestBaseURL = "https://api.mexc.com"
+	WsBaseURL   = "wss://wbs.mexc.com/ws"
+)
+
+// defaultRecvWindow specifies how long an HTTP request is valid in milliseconds, it's used to prevent replay attacks.
+var defaultRecvWindow = strconv.FormatInt(5*time.Second.Milliseconds(), 10)
+
+type RestClient struct {
+	requestgen.BaseAPIClient
+
+	key, secret string
+}
+
+func NewClient() *RestClient {
+	u, err := url.Parse(RestBaseURL)
+	if err != nil {
+		panic(err)
+	}
+
+	return &RestClient{
+		BaseAPIClient: requestgen.BaseAPIClient{
+			BaseURL: u,
+			HttpClient: &http.Client{
+				Timeout:   defaultHTTPTimeout,
+				Transport: apistats.NewTransport(types.ExchangeMEXC, nil),
+			},
+		},
+	}
+}
+
+func (c *RestClient) Auth(key, secret string) {
+	c.key = key
+	// pragma: allowlist nextline secret
+	c.secret = secret
+}
+
+// NewRequest creates new http request for the public routes.
+func (c *RestClient) NewRequest(ctx context.Context, method, refURL string, params url.Values, payload interface{}) (*http.Request, error) {
+	rel, err := url.Parse(refURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		rel.RawQuery = params.Encode()
+	}
+
+	pathURL := c.BaseURL.ResolveReference(rel)
+	return http.NewRequestWithContext(ctx, method, pathURL.String(), nil)
+}
+
+// NewAuthenticatedRequest creates new http request for authenticated routes.
+//
+// See https://mexcdevelop.github.io/apidocs/spot_v3_en/#signed
+//
+// All the parameters, including the parameters of the POST and DELETE requests, are sent in the query string,
+// the signature is the HMAC SHA256 of the query string with the timestamp and the recvWindow.
+func (c *RestClient) NewAuthenticatedRequest(ctx context.Context, method, refURL string, params url.Values, payload interface{}) (*http.Request, error) {
+	if len(c.key) == 0 {
+		return nil, errors.New("empty api key")
+	}
+
+	if len(c.secret) == 0 {
+		return nil, errors.New("empty api secret")
+	}
+
+	rel, err := url.Parse(refURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if params == nil {
+		params = url.Values{}
+	}
+
+	if m, ok := payload.(map[string]interface{}); ok {
+		for k, v := range m {
+			params.Set(k, fmt.Sprintf("%v", v))
+		}
+	}
+
+	params.Set("recvWindow", defaultRecvWindow)
+	params.Set("timestamp", strconv.FormatInt(time.Now().UnixMilli(), 10))
+
+	rawQuery := params.Encode()
+	rawQuery += "&signature=" + Sign(rawQuery, c.secret)
+
+	pathURL := c.BaseURL.ResolveReference(rel)
+	pathURL.RawQuery = rawQuery
+
+	req, err := http.NewRequestWithContext(ctx, method, pathURL.String(), bytes.NewReader(nil))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", "application/json")
+	req.Header.Add("X-MEXC-APIKEY", c.key)
+	return req, nil
+}
+
+// SendRequest sends the request and converts the error response into the APIError
+func (c *RestClient) SendRequest(req *http.Request) (*requestgen.Response, error) {
+	response, err := c.BaseAPIClient.SendRequest(req)
+	if err != nil && response != nil {
+		if apiErr := ParseAPIError(response.Body); apiErr != nil {
+			return response, apiErr
+		}
+	}
+
+	return response, err
+}
+
+// Sign signs the payload with the secret by HMAC SHA256 in the lowercase hex
+func Sign(payload string, secret string) string {
+	var sig = hmac.New(sha256.New, []byte(secret))
+	_, err := sig.Write([]byte(payload))
+	if err != nil {
+		return ""
+	}
+
+	return hex.EncodeToString(sig.Sum(nil))
+}
+
+/*
+APIError is the error response of the api, e.g.
+
+	{
+	  "code": 700002,
+	  "msg": "Signature for this request is not valid."
+	}
+*/
+type APIError struct {
+	Code    int    `json:"code"`
+	Message string `json:"msg"`
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("mexc api error, code: %d, msg: %s", e.Code, e.Message)
+}
+
+// ParseAPIError parses the error response body, nil is returned if the body is not an api error
+func ParseAPIError(body []byte) *APIError {
+	var apiErr APIError
+	if err := json.Unmarshal(body, &apiErr); err != nil || apiErr.Code == 0 {
+		return nil
+	}
+
+	return &apiErr
+}
@@ -0,0 +1,22 @@
+package mexcapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSign(t *testing.T) {
+	// the signature is the lowercase hex of the HMAC SHA256 digest of the query string
+	payload := "symbol=BTCUSDT&side=BUY&type=LIMIT&quantity=1&price=11&recvWindow=5000&timestamp=1644489390087"
+	secret := "45d0b3c26f2644f19bfb98b07741b2f5"
+	assert.Equal(t, "fd3e4e8543c5188531eb7279d68ae7d26a573d0fc5ab0d18eb692451654d837a", Sign(payload, secret))
+}
+
+func TestParseAPIError(t *testing.T) {
+	apiErr := ParseAPIError([]byte(`{"code":30004,"msg":"Insufficient position"}`))
+	if assert.NotNil(t, apiErr) {
+		assert.Equal(t, 30004, apiErr.Code)
+		assert.Equal(t, "Insufficient position", apiErr.Message)
+	}
+}
@@ -0,0 +1,34 @@
+package mexcapi
+
+import (
+	"github.com/c9s/requestgen"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+)
+
+type Balance struct {
+	Asset  string           `json:"asset"`
+	Free   fixedpoint.Value `json:"free"`
+	Locked fixedpoint.Value `json:"locked"`
+}
+
+type Account struct {
+	MakerCommission fixedpoint.Value `json:"makerCommission"`
+	TakerCommission fixedpoint.Value `json:"takerCommission"`
+	CanTrade        bool             `json:"canTrade"`
+	CanWithdraw     bool             `json:"canWithdraw"`
+	CanDeposit      bool             `json:"canDeposit"`
+	UpdateTime      *int64           `json:"updateTime"`
+	AccountType     string           `json:"accountType"`
+	Balances        []Balance        `json:"balances"`
+	Permissions     []string         `json:"permissions"`
+}
+
+//go:generate requestgen -method GET -url "/api/v3/account" -type GetAccountRequest -responseType .Account
+type GetAccountRequest struct {
+	client requestgen.AuthenticatedAPIClient
+}
+
+func (c *RestClient) NewGetAccountRequest() *GetAccountRequest {
+	return &GetAccountRequest{client: c}
+}
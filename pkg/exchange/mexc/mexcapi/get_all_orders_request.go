@@ -0,0 +1,26 @@
+package mexcapi
+
+import (
+	"time"
+
+	"github.com/c9s/requestgen"
+)
+
+// GetAllOrdersRequest queries the orders of the symbol, the query period is the last 24 hours by default,
+// and the maximum period is 7 days.
+//
+//go:generate requestgen -method GET -url "/api/v3/allOrders" -type GetAllOrdersRequest -responseType []Order
+type GetAllOrdersRequest struct {
+	client requestgen.AuthenticatedAPIClient
+
+	symbol    string     `param:"symbol,query"`
+	startTime *time.Time `param:"startTime,query,milliseconds"`
+	endTime   *time.Time `param:"endTime,query,milliseconds"`
+
+	// limit defaults to 500, max 1000
+	limit *int `param:"limit,query"`
+}
+
+func (c *RestClient) NewGetAllOrdersRequest() *GetAllOrdersRequest {
+	return &GetAllOrdersRequest{client: c}
+}
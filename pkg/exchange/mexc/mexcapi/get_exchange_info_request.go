@@ -0,0 +1,54 @@
+package mexcapi
+
+import (
+	"github.com/c9s/requestgen"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+)
+
+type Symbol struct {
+	Symbol string `json:"symbol"`
+
+	// Status is "1" for the online symbols, the legacy response uses "ENABLED"
+	Status string `json:"status"`
+
+	BaseAsset           string `json:"baseAsset"`
+	BaseAssetPrecision  int    `json:"baseAssetPrecision"`
+	QuoteAsset          string `json:"quoteAsset"`
+	QuotePrecision      int    `json:"quotePrecision"`
+	QuoteAssetPrecision int    `json:"quoteAssetPrecision"`
+
+	// BaseSizePrecision is the minimum order quantity of the base asset, e.g. "0.0001", it can be "0"
+	BaseSizePrecision fixedpoint.Value `json:"baseSizePrecision"`
+
+	// QuoteAmountPrecision is the minimum order amount of the quote asset, e.g. "5"
+	QuoteAmountPrecision fixedpoint.Value `json:"quoteAmountPrecision"`
+
+	MaxQuoteAmount       fixedpoint.Value `json:"maxQuoteAmount"`
+	MakerCommission      fixedpoint.Value `json:"makerCommission"`
+	TakerCommission      fixedpoint.Value `json:"takerCommission"`
+	IsSpotTradingAllowed bool             `json:"isSpotTradingAllowed"`
+	OrderTypes           []string         `json:"orderTypes"`
+}
+
+// IsOnline returns true if the symbol is tradable
+func (s Symbol) IsOnline() bool {
+	return (s.Status == "1" || s.Status == "ENABLED") && s.IsSpotTradingAllowed
+}
+
+type ExchangeInfo struct {
+	Timezone   string   `json:"timezone"`
+	ServerTime int64    `json:"serverTime"`
+	Symbols    []Symbol `json:"symbols"`
+}
+
+//go:generate requestgen -method GET -url "/api/v3/exchangeInfo" -type GetExchangeInfoRequest -responseType .ExchangeInfo
+type GetExchangeInfoRequest struct {
+	client requestgen.APIClient
+
+	symbol *string `param:"symbol,query"`
+}
+
+func (c *RestClient) NewGetExchangeInfoRequest() *GetExchangeInfoRequest {
+	return &GetExchangeInfoRequest{client: c}
+}
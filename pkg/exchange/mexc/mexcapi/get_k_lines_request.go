@@ -0,0 +1,27 @@
+package mexcapi
+
+import (
+	"time"
+
+	"github.com/c9s/requestgen"
+)
+
+//go:generate requestgen -method GET -url "/api/v3/klines" -type GetKLinesRequest -responseType []KLine
+type GetKLinesRequest struct {
+	client requestgen.APIClient
+
+	symbol string `param:"symbol,query"`
+
+	// interval is one of 1m, 5m, 15m, 30m, 60m, 4h, 1d, 1W, 1M
+	interval string `param:"interval,query"`
+
+	startTime *time.Time `param:"startTime,query,milliseconds"`
+	endTime   *time.Time `param:"endTime,query,milliseconds"`
+
+	// limit defaults to 500, max 1000
+	limit *int `param:"limit,query"`
+}
+
+func (c *RestClient) NewGetKLinesRequest() *GetKLinesRequest {
+	return &GetKLinesRequest{client: c}
+}
@@ -0,0 +1,27 @@
+package mexcapi
+
+import (
+	"time"
+
+	"github.com/c9s/requestgen"
+)
+
+// GetMyTradesRequest queries the trades of the symbol, the query period is the last 24 hours by default,
+// and the trades of the last month can be queried.
+//
+//go:generate requestgen -method GET -url "/api/v3/myTrades" -type GetMyTradesRequest -responseType []Trade
+type GetMyTradesRequest struct {
+	client requestgen.AuthenticatedAPIClient
+
+	symbol    string     `param:"symbol,query"`
+	orderID   *string    `param:"orderId,query"`
+	startTime *time.Time `param:"startTime,query,milliseconds"`
+	endTime   *time.Time `param:"endTime,query,milliseconds"`
+
+	// limit defaults to 500, max 1000
+	limit *int `param:"limit,query"`
+}
+
+func (c *RestClient) NewGetMyTradesRequest() *GetMyTradesRequest {
+	return &GetMyTradesRequest{client: c}
+}
@@ -0,0 +1,16 @@
+package mexcapi
+
+import (
+	"github.com/c9s/requestgen"
+)
+
+//go:generate requestgen -method GET -url "/api/v3/openOrders" -type GetOpenOrdersRequest -responseType []Order
+type GetOpenOrdersRequest struct {
+	client requestgen.AuthenticatedAPIClient
+
+	symbol string `param:"symbol,query"`
+}
+
+func (c *RestClient) NewGetOpenOrdersRequest() *GetOpenOrdersRequest {
+	return &GetOpenOrdersRequest{client: c}
+}
@@ -0,0 +1,18 @@
+package mexcapi
+
+import (
+	"github.com/c9s/requestgen"
+)
+
+//go:generate requestgen -method GET -url "/api/v3/order" -type GetOrderRequest -responseType .Order
+type GetOrderRequest struct {
+	client requestgen.AuthenticatedAPIClient
+
+	symbol            string  `param:"symbol,query"`
+	orderID           *string `param:"orderId,query"`
+	origClientOrderID *string `param:"origClientOrderId,query"`
+}
+
+func (c *RestClient) NewGetOrderRequest() *GetOrderRequest {
+	return &GetOrderRequest{client: c}
+}
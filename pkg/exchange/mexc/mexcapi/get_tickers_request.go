@@ -0,0 +1,45 @@
+package mexcapi
+
+import (
+	"github.com/c9s/requestgen"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+type Ticker struct {
+	Symbol      string                     `json:"symbol"`
+	PriceChange fixedpoint.Value           `json:"priceChange"`
+	LastPrice   fixedpoint.Value           `json:"lastPrice"`
+	BidPrice    fixedpoint.Value           `json:"bidPrice"`
+	BidQty      fixedpoint.Value           `json:"bidQty"`
+	AskPrice    fixedpoint.Value           `json:"askPrice"`
+	AskQty      fixedpoint.Value           `json:"askQty"`
+	OpenPrice   fixedpoint.Value           `json:"openPrice"`
+	HighPrice   fixedpoint.Value           `json:"highPrice"`
+	LowPrice    fixedpoint.Value           `json:"lowPrice"`
+	Volume      fixedpoint.Value           `json:"volume"`
+	QuoteVolume fixedpoint.Value           `json:"quoteVolume"`
+	OpenTime    types.MillisecondTimestamp `json:"openTime"`
+	CloseTime   types.MillisecondTimestamp `json:"closeTime"`
+}
+
+//go:generate requestgen -method GET -url "/api/v3/ticker/24hr" -type GetTickerRequest -responseType .Ticker
+type GetTickerRequest struct {
+	client requestgen.APIClient
+
+	symbol string `param:"symbol,query"`
+}
+
+func (c *RestClient) NewGetTickerRequest() *GetTickerRequest {
+	return &GetTickerRequest{client: c}
+}
+
+//go:generate requestgen -method GET -url "/api/v3/ticker/24hr" -type GetTickersRequest -responseType []Ticker
+type GetTickersRequest struct {
+	client requestgen.APIClient
+}
+
+func (c *RestClient) NewGetTickersRequest() *GetTickersRequest {
+	return &GetTickersRequest{client: c}
+}
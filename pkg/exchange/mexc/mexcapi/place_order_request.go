@@ -0,0 +1,42 @@
+package mexcapi
+
+import (
+	"github.com/c9s/requestgen"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+type OrderResponse struct {
+	Symbol       string                     `json:"symbol"`
+	OrderID      string                     `json:"orderId"`
+	OrderListID  int64                      `json:"orderListId"`
+	Price        fixedpoint.Value           `json:"price"`
+	OrigQty      fixedpoint.Value           `json:"origQty"`
+	Type         OrderType                  `json:"type"`
+	Side         SideType                   `json:"side"`
+	TransactTime types.MillisecondTimestamp `json:"transactTime"`
+}
+
+//go:generate requestgen -method POST -url "/api/v3/order" -type PlaceOrderRequest -responseType .OrderResponse
+type PlaceOrderRequest struct {
+	client requestgen.AuthenticatedAPIClient
+
+	symbol    string    `param:"symbol"`
+	side      SideType  `param:"side" validValues:"BUY,SELL"`
+	orderType OrderType `param:"type" validValues:"LIMIT,MARKET,LIMIT_MAKER,IMMEDIATE_OR_CANCEL,FILL_OR_KILL"`
+
+	quantity *string `param:"quantity"`
+
+	// quoteOrderQty is the quote amount of the market order
+	quoteOrderQty *string `param:"quoteOrderQty"`
+
+	price *string `param:"price"`
+
+	// newClientOrderID is the client order id, up to 32 characters
+	newClientOrderID *string `param:"newClientOrderId"`
+}
+
+func (c *RestClient) NewPlaceOrderRequest() *PlaceOrderRequest {
+	return &PlaceOrderRequest{client: c}
+}
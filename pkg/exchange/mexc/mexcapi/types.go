@@ -0,0 +1,118 @@
+package mexcapi
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+type SideType string
+
+const (
+	SideTypeBuy  SideType = "BUY"
+	SideTypeSell SideType = "SELL"
+)
+
+type OrderType string
+
+const (
+	OrderTypeLimit             OrderType = "LIMIT"
+	OrderTypeMarket            OrderType = "MARKET"
+	OrderTypeLimitMaker        OrderType = "LIMIT_MAKER"
+	OrderTypeImmediateOrCancel OrderType = "IMMEDIATE_OR_CANCEL"
+	OrderTypeFillOrKill        OrderType = "FILL_OR_KILL"
+)
+
+type OrderStatus string
+
+const (
+	OrderStatusNew               OrderStatus = "NEW"
+	OrderStatusFilled            OrderStatus = "FILLED"
+	OrderStatusPartiallyFilled   OrderStatus = "PARTIALLY_FILLED"
+	OrderStatusCanceled          OrderStatus = "CANCELED"
+	OrderStatusPartiallyCanceled OrderStatus = "PARTIALLY_CANCELED"
+)
+
+type Order struct {
+	Symbol              string                     `json:"symbol"`
+	OrderID             string                     `json:"orderId"`
+	ClientOrderID       string                     `json:"clientOrderId"`
+	Price               fixedpoint.Value           `json:"price"`
+	OrigQty             fixedpoint.Value           `json:"origQty"`
+	ExecutedQty         fixedpoint.Value           `json:"executedQty"`
+	CummulativeQuoteQty fixedpoint.Value           `json:"cummulativeQuoteQty"`
+	Status              OrderStatus                `json:"status"`
+	TimeInForce         string                     `json:"timeInForce"`
+	Type                OrderType                  `json:"type"`
+	Side                SideType                   `json:"side"`
+	StopPrice           fixedpoint.Value           `json:"stopPrice"`
+	Time                types.MillisecondTimestamp `json:"time"`
+	UpdateTime          types.MillisecondTimestamp `json:"updateTime"`
+	IsWorking           bool                       `json:"isWorking"`
+	OrigQuoteOrderQty   fixedpoint.Value           `json:"origQuoteOrderQty"`
+}
+
+type Trade struct {
+	Symbol          string                     `json:"symbol"`
+	ID              string                     `json:"id"`
+	OrderID         string                     `json:"orderId"`
+	Price           fixedpoint.Value           `json:"price"`
+	Qty             fixedpoint.Value           `json:"qty"`
+	QuoteQty        fixedpoint.Value           `json:"quoteQty"`
+	Commission      fixedpoint.Value           `json:"commission"`
+	CommissionAsset string                     `json:"commissionAsset"`
+	Time            types.MillisecondTimestamp `json:"time"`
+	IsBuyer         bool                       `json:"isBuyer"`
+	IsMaker         bool                       `json:"isMaker"`
+	IsSelfTrade     bool                       `json:"isSelfTrade"`
+	ClientOrderID   string                     `json:"clientOrderId"`
+}
+
+/*
+KLine is the kline of the array format:
+
+	[
+	  1640804880000, // open time
+	  "47482.36",    // open
+	  "47482.36",    // high
+	  "47416.57",    // low
+	  "47436.1",     // close
+	  "3.550717",    // volume
+	  1640804940000, // close time
+	  "168387.3"     // quote asset volume
+	]
+*/
+type KLine struct {
+	OpenTime    types.MillisecondTimestamp
+	Open        fixedpoint.Value
+	High        fixedpoint.Value
+	Low         fixedpoint.Value
+	Close       fixedpoint.Value
+	Volume      fixedpoint.Value
+	CloseTime   types.MillisecondTimestamp
+	QuoteVolume fixedpoint.Value
+}
+
+func (k *KLine) UnmarshalJSON(data []byte) error {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	if len(raw) < 8 {
+		return fmt.Errorf("unexpected kline length: %d, data: %s", len(raw), data)
+	}
+
+	fields := []interface{}{
+		&k.OpenTime, &k.Open, &k.High, &k.Low, &k.Close, &k.Volume, &k.CloseTime, &k.QuoteVolume,
+	}
+	for i, field := range fields {
+		if err := json.Unmarshal(raw[i], field); err != nil {
+			return fmt.Errorf("unable to parse the kline field #%d: %w", i, err)
+		}
+	}
+
+	return nil
+}
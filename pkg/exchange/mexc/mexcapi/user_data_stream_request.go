@@ -0,0 +1,44 @@
+package mexcapi
+
+import (
+	"github.com/c9s/requestgen"
+)
+
+type ListenKeyResponse struct {
+	ListenKey string `json:"listenKey"`
+}
+
+// CreateListenKeyRequest creates the listen key of the user data stream, the listen key is valid for 60 minutes
+//
+//go:generate requestgen -method POST -url "/api/v3/userDataStream" -type CreateListenKeyRequest -responseType .ListenKeyResponse
+type CreateListenKeyRequest struct {
+	client requestgen.AuthenticatedAPIClient
+}
+
+func (c *RestClient) NewCreateListenKeyRequest() *CreateListenKeyRequest {
+	return &CreateListenKeyRequest{client: c}
+}
+
+// KeepaliveListenKeyRequest extends the validity of the listen key to 60 minutes
+//
+//go:generate requestgen -method PUT -url "/api/v3/userDataStream" -type KeepaliveListenKeyRequest -responseType .ListenKeyResponse
+type KeepaliveListenKeyRequest struct {
+	client requestgen.AuthenticatedAPIClient
+
+	listenKey string `param:"listenKey"`
+}
+
+func (c *RestClient) NewKeepaliveListenKeyRequest() *KeepaliveListenKeyRequest {
+	return &KeepaliveListenKeyRequest{client: c}
+}
+
+//go:generate requestgen -method DELETE -url "/api/v3/userDataStream" -type CloseListenKeyRequest -responseType .ListenKeyResponse
+type CloseListenKeyRequest struct {
+	client requestgen.AuthenticatedAPIClient
+
+	listenKey string `param:"listenKey"`
+}
+
+func (c *RestClient) NewCloseListenKeyRequest() *CloseListenKeyRequest {
+	return &CloseListenKeyRequest{client: c}
+}
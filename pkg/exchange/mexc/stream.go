@@ -0,0 +1,307 @@
+package mexc
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/c9s/bbgo/pkg/exchange/mexc/mexcapi"
+	"github.com/c9s/bbgo/pkg/types"
+	"github.com/c9s/bbgo/pkg/util"
+)
+
+const (
+	// maxSubscriptions is the maximum number of the subscriptions of a connection
+	maxSubscriptions = 30
+
+	// listenKeyKeepAliveInterval keeps the listen key alive, the listen key expires in 60 minutes
+	listenKeyKeepAliveInterval = 30 * time.Minute
+)
+
+// AccountBalanceProvider provides the balances for emitting the balance snapshot on the stream authenticated.
+type AccountBalanceProvider interface {
+	QueryAccountBalances(ctx context.Context) (types.BalanceMap, error)
+}
+
+//go:generate callbackgen -type Stream
+type Stream struct {
+	types.StandardStream
+
+	client          *mexcapi.RestClient
+	balanceProvider AccountBalanceProvider
+
+	// lastKLines is the last kline of each symbol and interval, for emitting the closed kline
+	lastKLines   map[string]types.KLine
+	lastKLinesMu sync.Mutex
+
+	depthEventCallbacks       []func(e DepthEvent)
+	dealsEventCallbacks       []func(e DealsEvent)
+	kLineEventCallbacks       []func(e KLineEvent)
+	bookTickerEventCallbacks  []func(e BookTickerEvent)
+	accountEventCallbacks     []func(e AccountEvent)
+	orderEventCallbacks       []func(e OrderEvent)
+	privateDealEventCallbacks []func(e PrivateDealEvent)
+}
+
+func NewStream(client *mexcapi.RestClient, balanceProvider AccountBalanceProvider) *Stream {
+	stream := &Stream{
+		StandardStream:  types.NewStandardStream(),
+		client:          client,
+		balanceProvider: balanceProvider,
+		lastKLines:      make(map[string]types.KLine),
+	}
+
+	stream.SetEndpointCreator(stream.createEndpoint)
+	stream.SetParser(parseWebSocketEvent)
+	stream.SetDispatcher(stream.dispatchEvent)
+	stream.SetHeartBeat(stream.ping)
+	stream.OnConnect(stream.handleConnect)
+	stream.OnAuth(stream.handleAuth)
+
+	stream.OnDepthEvent(stream.handleDepthEvent)
+	stream.OnDealsEvent(stream.handleDealsEvent)
+	stream.OnKLineEvent(stream.handleKLineEvent)
+	stream.OnBookTickerEvent(stream.handleBookTickerEvent)
+	stream.OnAccountEvent(stream.handleAccountEvent)
+	stream.OnOrderEvent(stream.handleOrderEvent)
+	stream.OnPrivateDealEvent(stream.handlePrivateDealEvent)
+	return stream
+}
+
+func (s *Stream) createEndpoint(ctx context.Context) (string, error) {
+	if s.PublicOnly {
+		return mexcapi.WsBaseURL, nil
+	}
+
+	resp, err := s.client.NewCreateListenKeyRequest().Do(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to create listen key: %w", err)
+	}
+
+	log.Debugf("listen key is created: %s", util.MaskKey(resp.ListenKey))
+	go s.listenKeyKeepAlive(ctx, resp.ListenKey)
+
+	return mexcapi.WsBaseURL + "?listenKey=" + resp.ListenKey, nil
+}
+
+// listenKeyKeepAlive extends the validity of the listen key periodically, and closes the listen key on exit.
+func (s *Stream) listenKeyKeepAlive(ctx context.Context, listenKey string) {
+	ticker := time.NewTicker(listenKeyKeepAliveInterval)
+	defer ticker.Stop()
+
+	defer func() {
+		if _, err := s.client.NewCloseListenKeyRequest().ListenKey(listenKey).Do(context.Background()); err != nil {
+			log.WithError(err).Errorf("failed to close listen key: %s", util.MaskKey(listenKey))
+		}
+	}()
+
+	for {
+		select {
+		case <-s.CloseC:
+			return
+
+		case <-ctx.Done():
+			return
+
+		case <-ticker.C:
+			if _, err := s.client.NewKeepaliveListenKeyRequest().ListenKey(listenKey).Do(ctx); err != nil {
+				log.WithError(err).Errorf("failed to keep the listen key alive: %s", util.MaskKey(listenKey))
+				s.Reconnect()
+				return
+			}
+		}
+	}
+}
+
+func (s *Stream) dispatchEvent(event interface{}) {
+	switch e := event.(type) {
+	case *WsResponse:
+		if e.Code != 0 {
+			log.Errorf("websocket request error: %+v", e)
+		}
+
+	case *DepthEvent:
+		s.EmitDepthEvent(*e)
+
+	case *DealsEvent:
+		s.EmitDealsEvent(*e)
+
+	case *KLineEvent:
+		s.EmitKLineEvent(*e)
+
+	case *BookTickerEvent:
+		s.EmitBookTickerEvent(*e)
+
+	case *AccountEvent:
+		s.EmitAccountEvent(*e)
+
+	case *OrderEvent:
+		s.EmitOrderEvent(*e)
+
+	case *PrivateDealEvent:
+		s.EmitPrivateDealEvent(*e)
+	}
+}
+
+// ping sends the text PING message, the server closes the connection if there is no ping in 60 seconds
+func (s *Stream) ping(conn *websocket.Conn) error {
+	if err := conn.WriteJSON(WsRequest{Method: WsMethodPing}); err != nil {
+		log.WithError(err).Error("ping error")
+		return err
+	}
+
+	return nil
+}
+
+func (s *Stream) handleConnect() {
+	var params []string
+	if s.PublicOnly {
+		for _, sub := range s.Subscriptions {
+			param, err := convertSubscription(sub)
+			if err != nil {
+				log.WithError(err).Errorf("subscription convert error")
+				continue
+			}
+
+			params = append(params, param)
+		}
+
+		if len(params) > maxSubscriptions {
+			log.Errorf("the number of subscriptions %d exceeds the limit %d, the rest are dropped", len(params), maxSubscriptions)
+			params = params[:maxSubscriptions]
+		}
+	} else {
+		params = []string{
+			string(ChannelAccount),
+			string(ChannelPrivateDeals),
+			string(ChannelOrders),
+		}
+	}
+
+	if len(params) == 0 {
+		return
+	}
+
+	log.Infof("subscribing channels: %+v", params)
+	if err := s.Conn.WriteJSON(WsRequest{
+		Method: WsMethodSubscription,
+		Params: params,
+	}); err != nil {
+		log.WithError(err).Error("failed to send subscription request")
+		return
+	}
+
+	if !s.PublicOnly {
+		// the user data stream is authenticated by the listen key
+		s.EmitAuth()
+	}
+}
+
+func (s *Stream) handleAuth() {
+	balances, err := s.balanceProvider.QueryAccountBalances(context.Background())
+	if err != nil {
+		log.WithError(err).Error("failed to query balances")
+		return
+	}
+
+	s.EmitBalanceSnapshot(balances)
+}
+
+func convertSubscription(sub types.Subscription) (string, error) {
+	symbol := toLocalSymbol(sub.Symbol)
+
+	switch sub.Channel {
+	case types.BookChannel:
+		depth := "20"
+		switch sub.Options.Depth {
+		case types.DepthLevel5:
+			depth = "5"
+		case types.DepthLevel10:
+			depth = "10"
+		}
+
+		return fmt.Sprintf("%s@%s@%s", ChannelDepth, symbol, depth), nil
+
+	case types.MarketTradeChannel:
+		return fmt.Sprintf("%s@%s", ChannelDeals, symbol), nil
+
+	case types.BookTickerChannel:
+		return fmt.Sprintf("%s@%s", ChannelBookTicker, symbol), nil
+
+	case types.KLineChannel:
+		interval, err := toLocalWsInterval(sub.Options.Interval)
+		if err != nil {
+			return "", err
+		}
+
+		return fmt.Sprintf("%s@%s@%s", ChannelKLine, symbol, interval), nil
+	}
+
+	return "", fmt.Errorf("unsupported stream channel: %s", sub.Channel)
+}
+
+// handleDepthEvent emits the partial book as the snapshot, since the limit depth channel pushes the full levels of the depth
+func (s *Stream) handleDepthEvent(e DepthEvent) {
+	s.EmitBookSnapshot(e.OrderBook())
+}
+
+func (s *Stream) handleDealsEvent(e DealsEvent) {
+	for _, trade := range e.Trades() {
+		s.EmitMarketTrade(trade)
+	}
+}
+
+func (s *Stream) handleBookTickerEvent(e BookTickerEvent) {
+	s.EmitBookTickerUpdate(e.BookTicker())
+}
+
+// handleKLineEvent emits the kline update, and emits the previous kline as closed when a new kline is started,
+// since MEXC doesn't push the closed flag of the kline.
+func (s *Stream) handleKLineEvent(e KLineEvent) {
+	kline, err := e.GlobalKLine()
+	if err != nil {
+		log.WithError(err).Error("failed to convert kline")
+		return
+	}
+
+	key := kline.Symbol + "." + kline.Interval.String()
+
+	s.lastKLinesMu.Lock()
+	last, ok := s.lastKLines[key]
+	s.lastKLines[key] = kline
+	s.lastKLinesMu.Unlock()
+
+	if ok && kline.StartTime.Time().After(last.StartTime.Time()) {
+		last.Closed = true
+		s.EmitKLineClosed(last)
+	}
+
+	s.EmitKLine(kline)
+}
+
+func (s *Stream) handleAccountEvent(e AccountEvent) {
+	s.EmitBalanceUpdate(types.BalanceMap{
+		e.Asset: types.Balance{
+			Currency:  e.Asset,
+			Available: e.Free,
+			Locked:    e.Locked,
+		},
+	})
+}
+
+func (s *Stream) handleOrderEvent(e OrderEvent) {
+	order, err := e.Order()
+	if err != nil {
+		log.WithError(err).Error("failed to convert order")
+		return
+	}
+
+	s.EmitOrderUpdate(*order)
+}
+
+func (s *Stream) handlePrivateDealEvent(e PrivateDealEvent) {
+	s.EmitTradeUpdate(e.Trade())
+}
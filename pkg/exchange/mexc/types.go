@@ -0,0 +1,390 @@
+package mexc
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// Channel is the prefix of the websocket channels, the channel of the message is the prefix followed by the parameters, e.g.
+// spot@public.deals.v3.api@BTCUSDT
+type Channel string
+
+const (
+	ChannelDepth      Channel = "spot@public.limit.depth.v3.api"
+	ChannelDeals      Channel = "spot@public.deals.v3.api"
+	ChannelKLine      Channel = "spot@public.kline.v3.api"
+	ChannelBookTicker Channel = "spot@public.bookTicker.v3.api"
+
+	ChannelAccount      Channel = "spot@private.account.v3.api"
+	ChannelOrders       Channel = "spot@private.orders.v3.api"
+	ChannelPrivateDeals Channel = "spot@private.deals.v3.api"
+)
+
+type WsMethod string
+
+const (
+	WsMethodSubscription   WsMethod = "SUBSCRIPTION"
+	WsMethodUnsubscription WsMethod = "UNSUBSCRIPTION"
+	WsMethodPing           WsMethod = "PING"
+)
+
+type WsRequest struct {
+	Method WsMethod `json:"method"`
+	Params []string `json:"params,omitempty"`
+}
+
+// WsResponse is the response of the subscription and the ping requests, e.g.
+//
+//	{"id":0,"code":0,"msg":"spot@public.deals.v3.api@BTCUSDT"}
+//	{"id":0,"code":0,"msg":"PONG"}
+type WsResponse struct {
+	ID   int    `json:"id"`
+	Code int    `json:"code"`
+	Msg  string `json:"msg"`
+}
+
+func (r WsResponse) IsPong() bool {
+	return r.Msg == "PONG"
+}
+
+// WsMessage is the message of the pushed data, e.g.
+//
+//	{"c":"spot@public.deals.v3.api@BTCUSDT","d":{...},"s":"BTCUSDT","t":1661927587836}
+type WsMessage struct {
+	WsResponse
+
+	Channel string                     `json:"c"`
+	Data    json.RawMessage            `json:"d"`
+	Symbol  string                     `json:"s"`
+	Time    types.MillisecondTimestamp `json:"t"`
+}
+
+type PriceVolume struct {
+	Price  fixedpoint.Value `json:"p"`
+	Volume fixedpoint.Value `json:"v"`
+}
+
+type DepthEvent struct {
+	Symbol string    `json:"-"`
+	Time   time.Time `json:"-"`
+
+	Asks []PriceVolume `json:"asks"`
+	Bids []PriceVolume `json:"bids"`
+}
+
+func (e DepthEvent) OrderBook() types.SliceOrderBook {
+	book := types.SliceOrderBook{
+		Symbol: e.Symbol,
+		Time:   e.Time,
+	}
+
+	for _, pv := range e.Asks {
+		book.Asks = append(book.Asks, types.PriceVolume{Price: pv.Price, Volume: pv.Volume})
+	}
+
+	for _, pv := range e.Bids {
+		book.Bids = append(book.Bids, types.PriceVolume{Price: pv.Price, Volume: pv.Volume})
+	}
+
+	return book
+}
+
+// WsSide is the side of the websocket events, 1 for buy and 2 for sell
+type WsSide int
+
+const (
+	WsSideBuy  WsSide = 1
+	WsSideSell WsSide = 2
+)
+
+func (s WsSide) SideType() types.SideType {
+	if s == WsSideBuy {
+		return types.SideTypeBuy
+	}
+	return types.SideTypeSell
+}
+
+type Deal struct {
+	Side     WsSide                     `json:"S"`
+	Price    fixedpoint.Value           `json:"p"`
+	Quantity fixedpoint.Value           `json:"v"`
+	Time     types.MillisecondTimestamp `json:"t"`
+}
+
+type DealsEvent struct {
+	Symbol string `json:"-"`
+
+	Deals []Deal `json:"deals"`
+}
+
+func (e DealsEvent) Trades() (trades []types.Trade) {
+	for _, d := range e.Deals {
+		trades = append(trades, types.Trade{
+			Exchange:      types.ExchangeMEXC,
+			Price:         d.Price,
+			Quantity:      d.Quantity,
+			QuoteQuantity: d.Price.Mul(d.Quantity),
+			Symbol:        e.Symbol,
+			Side:          d.Side.SideType(),
+			IsBuyer:       d.Side == WsSideBuy,
+			Time:          types.Time(d.Time.Time()),
+		})
+	}
+	return trades
+}
+
+type KLine struct {
+	// StartTime and EndTime are in seconds
+	StartTime   int64            `json:"t"`
+	EndTime     int64            `json:"T"`
+	Interval    string           `json:"i"`
+	Open        fixedpoint.Value `json:"o"`
+	Close       fixedpoint.Value `json:"c"`
+	High        fixedpoint.Value `json:"h"`
+	Low         fixedpoint.Value `json:"l"`
+	Volume      fixedpoint.Value `json:"v"`
+	QuoteVolume fixedpoint.Value `json:"a"`
+}
+
+type KLineEvent struct {
+	Symbol string `json:"-"`
+
+	KLine KLine `json:"k"`
+}
+
+func (e KLineEvent) GlobalKLine() (types.KLine, error) {
+	interval, err := toGlobalWsInterval(e.KLine.Interval)
+	if err != nil {
+		return types.KLine{}, err
+	}
+
+	startTime := time.Unix(e.KLine.StartTime, 0)
+	return types.KLine{
+		Exchange:    types.ExchangeMEXC,
+		Symbol:      e.Symbol,
+		StartTime:   types.Time(startTime),
+		EndTime:     types.Time(startTime.Add(interval.Duration() - time.Millisecond)),
+		Interval:    interval,
+		Open:        e.KLine.Open,
+		Close:       e.KLine.Close,
+		High:        e.KLine.High,
+		Low:         e.KLine.Low,
+		Volume:      e.KLine.Volume,
+		QuoteVolume: e.KLine.QuoteVolume,
+	}, nil
+}
+
+type BookTickerEvent struct {
+	Symbol string `json:"-"`
+
+	AskPrice    fixedpoint.Value `json:"a"`
+	AskQuantity fixedpoint.Value `json:"A"`
+	BidPrice    fixedpoint.Value `json:"b"`
+	BidQuantity fixedpoint.Value `json:"B"`
+}
+
+func (e BookTickerEvent) BookTicker() types.BookTicker {
+	return types.BookTicker{
+		Symbol:   e.Symbol,
+		Buy:      e.BidPrice,
+		BuySize:  e.BidQuantity,
+		Sell:     e.AskPrice,
+		SellSize: e.AskQuantity,
+	}
+}
+
+type AccountEvent struct {
+	Asset  string                     `json:"a"`
+	Free   fixedpoint.Value           `json:"f"`
+	Locked fixedpoint.Value           `json:"l"`
+	Time   types.MillisecondTimestamp `json:"c"`
+}
+
+// WsOrderType is the order type of the order events
+type WsOrderType int
+
+const (
+	WsOrderTypeLimit             WsOrderType = 1
+	WsOrderTypePostOnly          WsOrderType = 2
+	WsOrderTypeImmediateOrCancel WsOrderType = 3
+	WsOrderTypeFillOrKill        WsOrderType = 4
+	WsOrderTypeMarket            WsOrderType = 5
+)
+
+// WsOrderStatus is the order status of the order events
+type WsOrderStatus int
+
+const (
+	WsOrderStatusNew               WsOrderStatus = 1
+	WsOrderStatusFilled            WsOrderStatus = 2
+	WsOrderStatusPartiallyFilled   WsOrderStatus = 3
+	WsOrderStatusCanceled          WsOrderStatus = 4
+	WsOrderStatusPartiallyCanceled WsOrderStatus = 5
+)
+
+type OrderEvent struct {
+	Symbol string `json:"-"`
+
+	OrderID             string                     `json:"i"`
+	ClientOrderID       string                     `json:"c"`
+	Price               fixedpoint.Value           `json:"p"`
+	Quantity            fixedpoint.Value           `json:"v"`
+	Side                WsSide                     `json:"S"`
+	OrderType           WsOrderType                `json:"o"`
+	Status              WsOrderStatus              `json:"s"`
+	IsMaker             int                        `json:"m"`
+	CreateTime          types.MillisecondTimestamp `json:"O"`
+	CumulativeQuantity  fixedpoint.Value           `json:"cv"`
+	CumulativeAmount    fixedpoint.Value           `json:"ca"`
+	AveragePrice        fixedpoint.Value           `json:"ap"`
+	RemainingQuantity   fixedpoint.Value           `json:"V"`
+	RemainingAmount     fixedpoint.Value           `json:"A"`
+	LastUpdateTimestamp types.MillisecondTimestamp `json:"-"`
+}
+
+func (e OrderEvent) Order() (*types.Order, error) {
+	var orderType types.OrderType
+	var timeInForce types.TimeInForce
+	switch e.OrderType {
+	case WsOrderTypeLimit:
+		orderType, timeInForce = types.OrderTypeLimit, types.TimeInForceGTC
+	case WsOrderTypePostOnly:
+		orderType, timeInForce = types.OrderTypeLimitMaker, types.TimeInForceGTC
+	case WsOrderTypeImmediateOrCancel:
+		orderType, timeInForce = types.OrderTypeLimit, types.TimeInForceIOC
+	case WsOrderTypeFillOrKill:
+		orderType, timeInForce = types.OrderTypeLimit, types.TimeInForceFOK
+	case WsOrderTypeMarket:
+		orderType = types.OrderTypeMarket
+	default:
+		return nil, fmt.Errorf("unexpected order type: %d", e.OrderType)
+	}
+
+	var status types.OrderStatus
+	switch e.Status {
+	case WsOrderStatusNew:
+		status = types.OrderStatusNew
+	case WsOrderStatusFilled:
+		status = types.OrderStatusFilled
+	case WsOrderStatusPartiallyFilled:
+		status = types.OrderStatusPartiallyFilled
+	case WsOrderStatusCanceled, WsOrderStatusPartiallyCanceled:
+		status = types.OrderStatusCanceled
+		if e.CumulativeQuantity.Sign() > 0 {
+			status = types.OrderStatusPartiallyFilled
+		}
+	default:
+		return nil, fmt.Errorf("unexpected order status: %d", e.Status)
+	}
+
+	return &types.Order{
+		SubmitOrder: types.SubmitOrder{
+			ClientOrderID: e.ClientOrderID,
+			Symbol:        e.Symbol,
+			Side:          e.Side.SideType(),
+			Type:          orderType,
+			Quantity:      e.Quantity,
+			Price:         e.Price,
+			TimeInForce:   timeInForce,
+		},
+		Exchange:         types.ExchangeMEXC,
+		OrderID:          toGlobalID(e.OrderID),
+		UUID:             e.OrderID,
+		Status:           status,
+		OriginalStatus:   fmt.Sprintf("%d", e.Status),
+		ExecutedQuantity: e.CumulativeQuantity,
+		IsWorking:        e.Status == WsOrderStatusNew || e.Status == WsOrderStatusPartiallyFilled,
+		CreationTime:     types.Time(e.CreateTime.Time()),
+		UpdateTime:       types.Time(e.LastUpdateTimestamp.Time()),
+	}, nil
+}
+
+type PrivateDealEvent struct {
+	Symbol string `json:"-"`
+
+	Price         fixedpoint.Value           `json:"p"`
+	Quantity      fixedpoint.Value           `json:"v"`
+	Amount        fixedpoint.Value           `json:"a"`
+	Side          WsSide                     `json:"S"`
+	Time          types.MillisecondTimestamp `json:"T"`
+	TradeID       string                     `json:"t"`
+	ClientOrderID string                     `json:"c"`
+	OrderID       string                     `json:"i"`
+	IsMaker       int                        `json:"m"`
+	Fee           fixedpoint.Value           `json:"n"`
+	FeeCurrency   string                     `json:"N"`
+}
+
+func (e PrivateDealEvent) Trade() types.Trade {
+	return types.Trade{
+		ID:            toGlobalID(e.TradeID),
+		OrderID:       toGlobalID(e.OrderID),
+		Exchange:      types.ExchangeMEXC,
+		Price:         e.Price,
+		Quantity:      e.Quantity,
+		QuoteQuantity: e.Amount,
+		Symbol:        e.Symbol,
+		Side:          e.Side.SideType(),
+		IsBuyer:       e.Side == WsSideBuy,
+		IsMaker:       e.IsMaker == 1,
+		Time:          types.Time(e.Time.Time()),
+		Fee:           e.Fee,
+		FeeCurrency:   e.FeeCurrency,
+	}
+}
+
+func parseWebSocketEvent(in []byte) (interface{}, error) {
+	var msg WsMessage
+	if err := json.Unmarshal(in, &msg); err != nil {
+		return nil, err
+	}
+
+	if len(msg.Channel) == 0 {
+		return &msg.WsResponse, nil
+	}
+
+	symbol := toGlobalSymbol(msg.Symbol)
+	channel := Channel(msg.Channel)
+	if len(msg.Symbol) > 0 {
+		if idx := strings.Index(msg.Channel, "@"+msg.Symbol); idx > 0 {
+			channel = Channel(msg.Channel[:idx])
+		}
+	}
+
+	switch channel {
+	case ChannelDepth:
+		e := &DepthEvent{Symbol: symbol, Time: msg.Time.Time()}
+		return e, json.Unmarshal(msg.Data, e)
+
+	case ChannelDeals:
+		e := &DealsEvent{Symbol: symbol}
+		return e, json.Unmarshal(msg.Data, e)
+
+	case ChannelKLine:
+		e := &KLineEvent{Symbol: symbol}
+		return e, json.Unmarshal(msg.Data, e)
+
+	case ChannelBookTicker:
+		e := &BookTickerEvent{Symbol: symbol}
+		return e, json.Unmarshal(msg.Data, e)
+
+	case ChannelAccount:
+		e := &AccountEvent{}
+		return e, json.Unmarshal(msg.Data, e)
+
+	case ChannelOrders:
+		e := &OrderEvent{Symbol: symbol, LastUpdateTimestamp: msg.Time}
+		return e, json.Unmarshal(msg.Data, e)
+
+	case ChannelPrivateDeals:
+		e := &PrivateDealEvent{Symbol: symbol}
+		return e, json.Unmarshal(msg.Data, e)
+	}
+
+	return nil, fmt.Errorf("unhandled websocket event: %s", string(in))
+}
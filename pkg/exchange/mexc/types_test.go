@@ -0,0 +1,122 @@
+package mexc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func Test_parseWebSocketEvent(t *testing.T) {
+	t.Run("pong", func(t *testing.T) {
+		e, err := parseWebSocketEvent([]byte(`{"id":0,"code":0,"msg":"PONG"}`))
+		if assert.NoError(t, err) {
+			assert.True(t, e.(*WsResponse).IsPong())
+		}
+	})
+
+	t.Run("depth", func(t *testing.T) {
+		e, err := parseWebSocketEvent([]byte(`{"c":"spot@public.limit.depth.v3.api@BTCUSDT@5","d":{"asks":[{"p":"20290.89","v":"0.001000"}],"bids":[{"p":"20290.88","v":"0.5"}],"e":"spot@public.limit.depth.v3.api","r":"3407459756"},"s":"BTCUSDT","t":1661932660144}`))
+		if assert.NoError(t, err) {
+			book := e.(*DepthEvent).OrderBook()
+			assert.Equal(t, "BTCUSDT", book.Symbol)
+			assert.Equal(t, fixedpoint.NewFromFloat(20290.89), book.Asks[0].Price)
+			assert.Equal(t, fixedpoint.NewFromFloat(0.5), book.Bids[0].Volume)
+		}
+	})
+
+	t.Run("deals", func(t *testing.T) {
+		e, err := parseWebSocketEvent([]byte(`{"c":"spot@public.deals.v3.api@BTCUSDT","d":{"deals":[{"S":2,"p":"20233.84","t":1661927587825,"v":"0.001028"}],"e":"spot@public.deals.v3.api"},"s":"BTCUSDT","t":1661927587836}`))
+		if assert.NoError(t, err) {
+			trades := e.(*DealsEvent).Trades()
+			if assert.Len(t, trades, 1) {
+				assert.Equal(t, types.SideTypeSell, trades[0].Side)
+				assert.Equal(t, fixedpoint.NewFromFloat(0.001028), trades[0].Quantity)
+			}
+		}
+	})
+
+	t.Run("kline", func(t *testing.T) {
+		e, err := parseWebSocketEvent([]byte(`{"c":"spot@public.kline.v3.api@BTCUSDT@Min15","d":{"k":{"t":1661931900,"o":20802.49,"c":20795.45,"h":20803.16,"l":20795.18,"v":1.0178,"a":21166.25,"T":1661932800,"i":"Min15"},"e":"spot@public.kline.v3.api"},"s":"BTCUSDT","t":1661931900000}`))
+		if assert.NoError(t, err) {
+			kline, err := e.(*KLineEvent).GlobalKLine()
+			if assert.NoError(t, err) {
+				assert.Equal(t, types.Interval15m, kline.Interval)
+				assert.Equal(t, int64(1661931900000), kline.StartTime.Time().UnixMilli())
+				assert.Equal(t, fixedpoint.NewFromFloat(20795.45), kline.Close)
+				assert.Equal(t, fixedpoint.NewFromFloat(21166.25), kline.QuoteVolume)
+			}
+		}
+	})
+
+	t.Run("order", func(t *testing.T) {
+		e, err := parseWebSocketEvent([]byte(`{"c":"spot@private.orders.v3.api","d":{"A":8.0,"O":1661938138000,"S":1,"V":10,"a":8,"c":"abc","i":"e03a5c7441e44ed899466a7140b71391","m":0,"o":1,"p":0.8,"s":3,"v":10,"ap":0.8,"cv":2,"ca":1.6},"s":"MXUSDT","t":1661938138193}`))
+		if assert.NoError(t, err) {
+			order, err := e.(*OrderEvent).Order()
+			if assert.NoError(t, err) {
+				assert.Equal(t, "MXUSDT", order.Symbol)
+				assert.Equal(t, "abc", order.ClientOrderID)
+				assert.Equal(t, types.SideTypeBuy, order.Side)
+				assert.Equal(t, types.OrderStatusPartiallyFilled, order.Status)
+				assert.Equal(t, fixedpoint.NewFromFloat(10), order.Quantity)
+				assert.Equal(t, fixedpoint.NewFromFloat(2), order.ExecutedQuantity)
+				assert.True(t, order.IsWorking)
+			}
+		}
+	})
+
+	t.Run("private deal", func(t *testing.T) {
+		e, err := parseWebSocketEvent([]byte(`{"c":"spot@private.deals.v3.api","d":{"p":"1.804","v":"0.31","a":"0.55924","S":2,"T":1678901086198,"t":"5bbb6ad8b4474570b155610e3960cd","c":"","i":"2dd9d34c03b5c3fd4a74f6f4fa6d43a8","m":1,"st":0,"n":"0.000248206380027","N":"USDT"},"s":"MXUSDT","t":1661938980285}`))
+		if assert.NoError(t, err) {
+			trade := e.(*PrivateDealEvent).Trade()
+			assert.Equal(t, types.SideTypeSell, trade.Side)
+			assert.True(t, trade.IsMaker)
+			assert.Equal(t, "USDT", trade.FeeCurrency)
+			assert.Equal(t, toGlobalID("2dd9d34c03b5c3fd4a74f6f4fa6d43a8"), trade.OrderID)
+			assert.Equal(t, int64(1678901086198), trade.Time.Time().UnixMilli())
+		}
+	})
+
+	t.Run("account", func(t *testing.T) {
+		e, err := parseWebSocketEvent([]byte(`{"c":"spot@private.account.v3.api","d":{"a":"USDT","c":1678185928428,"f":"302.185","fd":"-4.99","l":"4.99","ld":"4.99","o":"ENTRUST_PLACE"},"t":1678185928435}`))
+		if assert.NoError(t, err) {
+			account := e.(*AccountEvent)
+			assert.Equal(t, "USDT", account.Asset)
+			assert.Equal(t, fixedpoint.NewFromFloat(302.185), account.Free)
+			assert.Equal(t, fixedpoint.NewFromFloat(4.99), account.Locked)
+		}
+	})
+}
+
+func Test_convertSubscription(t *testing.T) {
+	s, err := convertSubscription(types.Subscription{Channel: types.BookChannel, Symbol: "BTCUSDT", Options: types.SubscribeOptions{Depth: types.DepthLevel5}})
+	assert.NoError(t, err)
+	assert.Equal(t, "spot@public.limit.depth.v3.api@BTCUSDT@5", s)
+
+	s, err = convertSubscription(types.Subscription{Channel: types.KLineChannel, Symbol: "BTCUSDT", Options: types.SubscribeOptions{Interval: types.Interval1h}})
+	assert.NoError(t, err)
+	assert.Equal(t, "spot@public.kline.v3.api@BTCUSDT@Min60", s)
+}
+
+func TestStream_handleKLineEvent(t *testing.T) {
+	s := NewStream(nil, nil)
+
+	var closed []types.KLine
+	s.OnKLineClosed(func(k types.KLine) {
+		closed = append(closed, k)
+	})
+
+	event := KLineEvent{Symbol: "BTCUSDT", KLine: KLine{StartTime: 1661931900, Interval: "Min1"}}
+	s.handleKLineEvent(event)
+	s.handleKLineEvent(event)
+	assert.Empty(t, closed)
+
+	event.KLine.StartTime += 60
+	s.handleKLineEvent(event)
+	if assert.Len(t, closed, 1) {
+		assert.True(t, closed[0].Closed)
+		assert.Equal(t, int64(1661931900), closed[0].StartTime.Time().Unix())
+	}
+}
@@ -0,0 +1,85 @@
+package okex
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// AmendOrder amends the price and the quantity of the open order in place, the order keeps its order id.
+// The quantity is the new open quantity of the order. Since the new size of the okx amend-order api includes
+// the filled size, the executed quantity of the partially filled order is added to the quantity.
+func (e *Exchange) AmendOrder(ctx context.Context, order types.Order, price, quantity fixedpoint.Value) (*types.Order, error) {
+	if len(order.Symbol) == 0 {
+		return nil, ErrSymbolRequired
+	}
+
+	if isAlgoOrder(order.SubmitOrder) {
+		return nil, fmt.Errorf("algo order #%d can not be amended", order.OrderID)
+	}
+
+	// the orders updated by the user data stream do not carry their market, so we always look up the market
+	market, err := e.queryMarket(ctx, order.Symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	newSize := order.ExecutedQuantity.Add(quantity)
+
+	req := e.client.NewAmendOrderRequest()
+	req.InstrumentID(toLocalSymbol(order.Symbol))
+	req.OrderID(strconv.FormatUint(order.OrderID, 10))
+	req.NewSize(market.FormatQuantity(newSize))
+	req.NewPrice(market.FormatPrice(price))
+
+	if err := amendOrderLimiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("amend order rate limiter wait error: %w", err)
+	}
+
+	responses, err := req.Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(responses) != 1 {
+		return nil, fmt.Errorf("unexpected length of amend order response: %v", responses)
+	}
+
+	if responses[0].Code != "0" {
+		return nil, fmt.Errorf("unable to amend order #%d, code: %s, message: %s", order.OrderID, responses[0].Code, responses[0].Message)
+	}
+
+	amendedOrder := order
+	amendedOrder.Market = market
+	amendedOrder.Price = price
+	amendedOrder.Quantity = newSize
+	amendedOrder.UpdateTime = types.Time(time.Now())
+	return &amendedOrder, nil
+}
+
+// queryMarket returns the cached market of the symbol, the markets are queried when the symbol is not cached yet
+func (e *Exchange) queryMarket(ctx context.Context, symbol string) (types.Market, error) {
+	e.marketsMutex.Lock()
+	market, ok := e.markets[symbol]
+	e.marketsMutex.Unlock()
+
+	if ok {
+		return market, nil
+	}
+
+	markets, err := e.QueryMarkets(ctx)
+	if err != nil {
+		return types.Market{}, fmt.Errorf("unable to query the market of %s: %w", symbol, err)
+	}
+
+	market, ok = markets[symbol]
+	if !ok {
+		return types.Market{}, fmt.Errorf("market %s not found", symbol)
+	}
+
+	return market, nil
+}
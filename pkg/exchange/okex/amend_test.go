@@ -0,0 +1,98 @@
+package okex
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/testing/httptesting"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestExchange_AmendOrder(t *testing.T) {
+	ex := New("key", "secret", "passphrase")
+	transport := &httptesting.MockTransport{}
+	ex.client.HttpClient.Transport = transport
+
+	numOfInstrumentQueries := 0
+	transport.GET("/api/v5/public/instruments", func(req *http.Request) (*http.Response, error) {
+		numOfInstrumentQueries++
+		return httptesting.BuildResponseString(http.StatusOK,
+			`{"code":"0","msg":"","data":[{"instType":"SPOT","instId":"BTC-USDT","baseCcy":"BTC","quoteCcy":"USDT","tickSz":"0.1","lotSz":"0.0001","minSz":"0.00001","state":"live"}]}`), nil
+	})
+
+	// the orders updated by the user data stream do not carry their market
+	order := types.Order{
+		SubmitOrder: types.SubmitOrder{
+			Symbol:   "BTCUSDT",
+			Side:     types.SideTypeBuy,
+			Type:     types.OrderTypeLimit,
+			Price:    fixedpoint.NewFromFloat(30000.0),
+			Quantity: fixedpoint.NewFromFloat(0.5),
+		},
+		OrderID:          688362711456706560,
+		ExecutedQuantity: fixedpoint.NewFromFloat(0.2),
+	}
+
+	t.Run("succeeds", func(t *testing.T) {
+		transport.POST("/api/v5/trade/amend-order", func(req *http.Request) (*http.Response, error) {
+			body, err := io.ReadAll(req.Body)
+			assert.NoError(t, err)
+
+			var params map[string]interface{}
+			assert.NoError(t, json.Unmarshal(body, &params))
+			assert.Equal(t, "BTC-USDT", params["instId"])
+			assert.Equal(t, "688362711456706560", params["ordId"])
+			assert.Equal(t, "30010.0", params["newPx"])
+
+			// the new size includes the filled size
+			assert.Equal(t, "0.5000", params["newSz"])
+
+			return httptesting.BuildResponseString(http.StatusOK,
+				`{"code":"0","msg":"","data":[{"ordId":"688362711456706560","clOrdId":"","reqId":"","sCode":"0","sMsg":""}]}`), nil
+		})
+
+		amendedOrder, err := ex.AmendOrder(context.Background(), order, fixedpoint.NewFromFloat(30010.0), fixedpoint.NewFromFloat(0.3))
+		if assert.NoError(t, err) {
+			assert.Equal(t, order.OrderID, amendedOrder.OrderID)
+			assert.Equal(t, "30010", amendedOrder.Price.String())
+			assert.Equal(t, "0.5", amendedOrder.Quantity.String())
+			assert.Equal(t, "0.2", amendedOrder.ExecutedQuantity.String())
+			assert.Equal(t, 1, amendedOrder.Market.PricePrecision)
+		}
+	})
+
+	t.Run("uses the cached market", func(t *testing.T) {
+		transport.POST("/api/v5/trade/amend-order", func(req *http.Request) (*http.Response, error) {
+			body, err := io.ReadAll(req.Body)
+			assert.NoError(t, err)
+
+			var params map[string]interface{}
+			assert.NoError(t, json.Unmarshal(body, &params))
+			assert.Equal(t, "30010.1", params["newPx"])
+			assert.Equal(t, "0.2500", params["newSz"])
+
+			return httptesting.BuildResponseString(http.StatusOK,
+				`{"code":"0","msg":"","data":[{"ordId":"688362711456706560","clOrdId":"","reqId":"","sCode":"0","sMsg":""}]}`), nil
+		})
+
+		_, err := ex.AmendOrder(context.Background(), order, fixedpoint.NewFromFloat(30010.1), fixedpoint.NewFromFloat(0.05))
+		assert.NoError(t, err)
+		assert.Equal(t, 1, numOfInstrumentQueries)
+	})
+
+	t.Run("fails", func(t *testing.T) {
+		transport.POST("/api/v5/trade/amend-order", func(req *http.Request) (*http.Response, error) {
+			return httptesting.BuildResponseString(http.StatusOK,
+				`{"code":"1","msg":"","data":[{"ordId":"688362711456706560","clOrdId":"","reqId":"","sCode":"51503","sMsg":"Order modification failed as the order has been filled or canceled"}]}`), nil
+		})
+
+		_, err := ex.AmendOrder(context.Background(), order, fixedpoint.NewFromFloat(30010.0), fixedpoint.NewFromFloat(0.3))
+		assert.Error(t, err)
+	})
+}
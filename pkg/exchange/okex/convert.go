@@ -36,6 +36,10 @@ func toGlobalTicker(marketTicker okexapi.MarketTicker) *types.Ticker {
 		Low:    marketTicker.Low24H,
 		Buy:    marketTicker.BidPrice,
 		Sell:   marketTicker.AskPrice,
+
+		BuySize:  marketTicker.BidSize,
+		SellSize: marketTicker.AskSize,
+		LastSize: marketTicker.LastSize,
 	}
 }
 
@@ -112,6 +116,13 @@ func convertSubscription(s types.Subscription) (WebsocketSubscription, error) {
 			Channel:      ChannelMarketTrades,
 			InstrumentID: toLocalSymbol(s.Symbol),
 		}, nil
+
+	case types.ForceOrderChannel:
+		// the liquidation orders are pushed for all the perpetual swaps, the events are filtered by the subscribed symbols
+		return WebsocketSubscription{
+			Channel:        ChannelLiquidationOrders,
+			InstrumentType: string(okexapi.InstrumentTypeSwap),
+		}, nil
 	}
 
 	return WebsocketSubscription{}, fmt.Errorf("unsupported public stream channel %s", s.Channel)
@@ -218,6 +229,78 @@ func orderDetailToGlobal(order *okexapi.OrderDetail) (*types.Order, error) {
 	}, nil
 }
 
+// marketOrderPrice is the order price of the algo orders for placing the market orders
+var marketOrderPrice = fixedpoint.NewFromInt(-1)
+
+func algoOrderToGlobal(order *okexapi.AlgoOrder) (*types.Order, error) {
+	orderStatus, err := toGlobalAlgoOrderStatus(order.State)
+	if err != nil {
+		return nil, err
+	}
+
+	submitOrder := types.SubmitOrder{
+		ClientOrderID: order.AlgoClientOrderID,
+		Symbol:        toGlobalSymbol(order.InstrumentID),
+		Side:          toGlobalSide(order.Side),
+		Quantity:      order.Size,
+		TimeInForce:   types.TimeInForceGTC,
+		ReduceOnly:    order.ReduceOnly == "true",
+	}
+
+	switch order.OrderType {
+	case okexapi.AlgoOrderTypeTrigger:
+		submitOrder.StopPrice = order.TriggerPrice
+		if order.OrderPrice.Eq(marketOrderPrice) {
+			submitOrder.Type = types.OrderTypeStopMarket
+		} else {
+			submitOrder.Type = types.OrderTypeStopLimit
+			submitOrder.Price = order.OrderPrice
+		}
+
+	case okexapi.AlgoOrderTypeConditional, okexapi.AlgoOrderTypeOCO:
+		submitOrder.TakeProfitPrice = order.TakeProfitTriggerPrice
+		submitOrder.StopLossPrice = order.StopLossTriggerPrice
+		if order.TakeProfitOrderPrice.Eq(marketOrderPrice) || order.StopLossOrderPrice.Eq(marketOrderPrice) {
+			submitOrder.Type = types.OrderTypeMarket
+		} else {
+			submitOrder.Type = types.OrderTypeLimit
+		}
+
+	default:
+		return nil, fmt.Errorf("unknown or unsupported okex algo order type: %s", order.OrderType)
+	}
+
+	return &types.Order{
+		SubmitOrder:      submitOrder,
+		Exchange:         types.ExchangeOKEx,
+		OrderID:          uint64(order.AlgoID),
+		UUID:             strconv.FormatInt(int64(order.AlgoID), 10),
+		Status:           orderStatus,
+		OriginalStatus:   string(order.State),
+		ExecutedQuantity: order.ActualSize,
+		IsWorking:        order.State.IsWorking(),
+		CreationTime:     types.Time(order.CreatedTime),
+		UpdateTime:       types.Time(order.UpdatedTime),
+	}, nil
+}
+
+// toGlobalAlgoOrderStatus converts the algo order state, the algo order is filled once it's triggered
+// and the order is placed, the fills are reported by the placed order.
+func toGlobalAlgoOrderStatus(state okexapi.AlgoOrderState) (types.OrderStatus, error) {
+	switch state {
+	case okexapi.AlgoOrderStateLive, okexapi.AlgoOrderStatePause, okexapi.AlgoOrderStatePartiallyEffective:
+		return types.OrderStatusNew, nil
+	case okexapi.AlgoOrderStateEffective:
+		return types.OrderStatusFilled, nil
+	case okexapi.AlgoOrderStateCanceled:
+		return types.OrderStatusCanceled, nil
+	case okexapi.AlgoOrderStateOrderFailed, okexapi.AlgoOrderStatePartiallyFailed:
+		return types.OrderStatusRejected, nil
+	}
+
+	return "", fmt.Errorf("unknown or unsupported okex algo order state: %s", state)
+}
+
 func toGlobalOrderStatus(state okexapi.OrderState) (types.OrderStatus, error) {
 	switch state {
 	case okexapi.OrderStateCanceled:
@@ -243,3 +243,54 @@ func Test_processMarketBuyQuantity(t *testing.T) {
 		assert.ErrorContains(err, "unexpected")
 	})
 }
+
+func Test_algoOrderToGlobal(t *testing.T) {
+	t.Run("trigger order", func(t *testing.T) {
+		var algoOrder okexapi.AlgoOrder
+		err := json.Unmarshal([]byte(`{"instType":"SPOT","instId":"BTC-USDT","algoId":"681096944655273984","algoClOrdId":"a1","ordType":"trigger","side":"buy","sz":"0.001","tgtCcy":"base_ccy","state":"live","reduceOnly":"false","tpTriggerPx":"","tpOrdPx":"","slTriggerPx":"","slOrdPx":"","triggerPx":"70000","ordPx":"-1","actualSz":"","actualPx":"","cTime":"1708587373361","uTime":"1708587373362"}`), &algoOrder)
+		assert.NoError(t, err)
+
+		order, err := algoOrderToGlobal(&algoOrder)
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(681096944655273984), order.OrderID)
+		assert.Equal(t, "a1", order.ClientOrderID)
+		assert.Equal(t, "BTCUSDT", order.Symbol)
+		assert.Equal(t, types.SideTypeBuy, order.Side)
+		assert.Equal(t, types.OrderTypeStopMarket, order.Type)
+		assert.Equal(t, fixedpoint.NewFromFloat(70000), order.StopPrice)
+		assert.Equal(t, fixedpoint.Zero, order.Price)
+		assert.Equal(t, fixedpoint.NewFromFloat(0.001), order.Quantity)
+		assert.Equal(t, types.OrderStatusNew, order.Status)
+		assert.True(t, order.IsWorking)
+	})
+
+	t.Run("oco order", func(t *testing.T) {
+		algoOrder := okexapi.AlgoOrder{
+			InstrumentID:           "BTC-USDT",
+			AlgoID:                 681096944655273985,
+			OrderType:              okexapi.AlgoOrderTypeOCO,
+			Side:                   okexapi.SideTypeSell,
+			Size:                   fixedpoint.NewFromFloat(0.001),
+			State:                  okexapi.AlgoOrderStateEffective,
+			TakeProfitTriggerPrice: fixedpoint.NewFromFloat(72000),
+			TakeProfitOrderPrice:   fixedpoint.NewFromFloat(72000),
+			StopLossTriggerPrice:   fixedpoint.NewFromFloat(65000),
+			StopLossOrderPrice:     fixedpoint.NewFromFloat(65000),
+			ActualSize:             fixedpoint.NewFromFloat(0.001),
+		}
+
+		order, err := algoOrderToGlobal(&algoOrder)
+		assert.NoError(t, err)
+		assert.Equal(t, types.OrderTypeLimit, order.Type)
+		assert.Equal(t, fixedpoint.NewFromFloat(72000), order.TakeProfitPrice)
+		assert.Equal(t, fixedpoint.NewFromFloat(65000), order.StopLossPrice)
+		assert.Equal(t, types.OrderStatusFilled, order.Status)
+		assert.Equal(t, fixedpoint.NewFromFloat(0.001), order.ExecutedQuantity)
+		assert.False(t, order.IsWorking)
+	})
+
+	t.Run("unknown state", func(t *testing.T) {
+		_, err := algoOrderToGlobal(&okexapi.AlgoOrder{OrderType: okexapi.AlgoOrderTypeTrigger, State: "unknown"})
+		assert.Error(t, err)
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"regexp"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/pkg/errors"
@@ -36,6 +37,15 @@ var (
 	// Rate Limit: 60 requests per 2 seconds, Rate limit rule (except Options): UserID + Instrument ID
 	// TODO: support UserID + Instrument ID
 	batchCancelOrderLimiter = rate.NewLimiter(rate.Every(33*time.Millisecond), 1)
+	// Rate Limit: 60 requests per 2 seconds, Rate limit rule (except Options): UserID + Instrument ID
+	// TODO: support UserID + Instrument ID
+	amendOrderLimiter = rate.NewLimiter(rate.Every(33*time.Millisecond), 1)
+	// Rate Limit: 20 requests per 2 seconds, Rate limit rule: UserID
+	placeAlgoOrderLimiter = rate.NewLimiter(rate.Every(100*time.Millisecond), 1)
+	// Rate Limit: 20 requests per 2 seconds, Rate limit rule: UserID
+	cancelAlgoOrderLimiter = rate.NewLimiter(rate.Every(100*time.Millisecond), 1)
+	// Rate Limit: 20 requests per 2 seconds, Rate limit rule: UserID
+	queryAlgoOrderLimiter = rate.NewLimiter(rate.Every(100*time.Millisecond), 1)
 	// Rate Limit: 60 requests per 2 seconds, Rate limit rule: UserID
 	queryOpenOrderLimiter = rate.NewLimiter(rate.Every(33*time.Millisecond), 1)
 	// Rate Limit: 20 requests per 2 seconds, Rate limit rule: UserID
@@ -69,6 +79,10 @@ type Exchange struct {
 
 	client      *okexapi.RestClient
 	timeNowFunc func() time.Time
+
+	// markets caches the queried markets for the orders that do not carry their market, e.g., the amended orders
+	marketsMutex sync.Mutex
+	markets      types.MarketMap
 }
 
 func New(key, secret, passphrase string) *Exchange {
@@ -132,6 +146,10 @@ func (e *Exchange) QueryMarkets(ctx context.Context) (types.MarketMap, error) {
 		markets[symbol] = market
 	}
 
+	e.marketsMutex.Lock()
+	e.markets = markets
+	e.marketsMutex.Unlock()
+
 	return markets, nil
 }
 
@@ -170,18 +188,7 @@ func (e *Exchange) QueryTickers(ctx context.Context, symbols ...string) (map[str
 		tickers[symbol] = *ticker
 	}
 
-	if len(symbols) == 0 {
-		return tickers, nil
-	}
-
-	selectedTickers := make(map[string]types.Ticker, len(symbols))
-	for _, symbol := range symbols {
-		if ticker, ok := tickers[symbol]; ok {
-			selectedTickers[symbol] = ticker
-		}
-	}
-
-	return selectedTickers, nil
+	return types.FilterTickers(tickers, symbols...), nil
 }
 
 func (e *Exchange) PlatformFeeCurrency() string {
@@ -217,6 +224,10 @@ func (e *Exchange) QueryAccountBalances(ctx context.Context) (types.BalanceMap,
 }
 
 func (e *Exchange) SubmitOrder(ctx context.Context, order types.SubmitOrder) (*types.Order, error) {
+	if isAlgoOrder(order) {
+		return e.submitAlgoOrder(ctx, order)
+	}
+
 	orderReq := e.client.NewPlaceOrderRequest()
 
 	orderReq.InstrumentID(toLocalSymbol(order.Symbol))
@@ -321,6 +332,121 @@ func (e *Exchange) SubmitOrder(ctx context.Context, order types.SubmitOrder) (*t
 	*/
 }
 
+// isAlgoOrder returns true if the order is placed as the okx algo order,
+// which are the stop orders and the orders with the take-profit or the stop-loss prices.
+func isAlgoOrder(order types.SubmitOrder) bool {
+	switch order.Type {
+	case types.OrderTypeStopLimit, types.OrderTypeStopMarket:
+		return true
+	}
+
+	return !order.TakeProfitPrice.IsZero() || !order.StopLossPrice.IsZero()
+}
+
+/*
+submitAlgoOrder places the algo order:
+
+  - the stop orders are placed as the trigger orders, the order is placed at the price when the stop price is reached.
+  - the orders with the take-profit price or the stop-loss price are placed as the conditional orders,
+    and the orders with both prices are placed as the oco orders. The take-profit and the stop-loss orders are
+    the market orders for the market order type, otherwise they're the limit orders at their trigger prices.
+
+The order id of the returned order is the algo id.
+*/
+func (e *Exchange) submitAlgoOrder(ctx context.Context, order types.SubmitOrder) (*types.Order, error) {
+	orderReq := e.client.NewPlaceAlgoOrderRequest()
+
+	orderReq.InstrumentID(toLocalSymbol(order.Symbol))
+	orderReq.Side(toLocalSideType(order.Side))
+	orderReq.Size(order.Market.FormatQuantity(order.Quantity))
+	// our order.Quantity unit is base coin
+	orderReq.TargetCurrency(okexapi.TargetCurrencyBase)
+
+	if order.ReduceOnly {
+		orderReq.ReduceOnly(true)
+	}
+
+	switch order.Type {
+	case types.OrderTypeStopLimit, types.OrderTypeStopMarket:
+		if order.StopPrice.IsZero() {
+			return nil, fmt.Errorf("stop price is required for the %s order", order.Type)
+		}
+
+		orderReq.OrderType(okexapi.AlgoOrderTypeTrigger)
+		orderReq.TriggerPrice(order.Market.FormatPrice(order.StopPrice))
+		if order.Type == types.OrderTypeStopMarket {
+			orderReq.OrderPrice(marketOrderPrice.String())
+		} else {
+			orderReq.OrderPrice(order.Market.FormatPrice(order.Price))
+		}
+
+	case types.OrderTypeMarket, types.OrderTypeLimit:
+		if !order.TakeProfitPrice.IsZero() && !order.StopLossPrice.IsZero() {
+			orderReq.OrderType(okexapi.AlgoOrderTypeOCO)
+		} else {
+			orderReq.OrderType(okexapi.AlgoOrderTypeConditional)
+		}
+
+		if !order.TakeProfitPrice.IsZero() {
+			orderReq.TakeProfitTriggerPrice(order.Market.FormatPrice(order.TakeProfitPrice))
+			if order.Type == types.OrderTypeMarket {
+				orderReq.TakeProfitOrderPrice(marketOrderPrice.String())
+			} else {
+				orderReq.TakeProfitOrderPrice(order.Market.FormatPrice(order.TakeProfitPrice))
+			}
+		}
+
+		if !order.StopLossPrice.IsZero() {
+			orderReq.StopLossTriggerPrice(order.Market.FormatPrice(order.StopLossPrice))
+			if order.Type == types.OrderTypeMarket {
+				orderReq.StopLossOrderPrice(marketOrderPrice.String())
+			} else {
+				orderReq.StopLossOrderPrice(order.Market.FormatPrice(order.StopLossPrice))
+			}
+		}
+
+	default:
+		return nil, fmt.Errorf("unsupported okex algo order type: %s", order.Type)
+	}
+
+	if len(order.ClientOrderID) > 0 {
+		if ok := clientOrderIdRegex.MatchString(order.ClientOrderID); !ok {
+			return nil, fmt.Errorf("client order id should be case-sensitive alphanumerics, all numbers, or all letters of up to 32 characters: %s", order.ClientOrderID)
+		}
+		orderReq.AlgoClientOrderID(order.ClientOrderID)
+	}
+
+	if err := placeAlgoOrderLimiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("place algo order rate limiter wait error: %w", err)
+	}
+
+	timeNow := time.Now()
+	orders, err := orderReq.Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(orders) != 1 {
+		return nil, fmt.Errorf("unexpected length of algo order response: %v", orders)
+	}
+
+	orderID, err := strconv.ParseUint(orders[0].AlgoID, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse response algo id: %w", err)
+	}
+
+	return &types.Order{
+		SubmitOrder:      order,
+		Exchange:         types.ExchangeOKEx,
+		OrderID:          orderID,
+		Status:           types.OrderStatusNew,
+		ExecutedQuantity: fixedpoint.Zero,
+		IsWorking:        true,
+		CreationTime:     types.Time(timeNow),
+		UpdateTime:       types.Time(timeNow),
+	}, nil
+}
+
 // QueryOpenOrders retrieves the pending orders. The data returned is ordered by createdTime, and we utilized the
 // `After` parameter to acquire all orders.
 func (e *Exchange) QueryOpenOrders(ctx context.Context, symbol string) (orders []types.Order, err error) {
@@ -370,11 +496,17 @@ func (e *Exchange) CancelOrders(ctx context.Context, orders ...types.Order) erro
 	}
 
 	var reqs []*okexapi.CancelOrderRequest
+	var algoOrders []types.Order
 	for _, order := range orders {
 		if len(order.Symbol) == 0 {
 			return ErrSymbolRequired
 		}
 
+		if isAlgoOrder(order.SubmitOrder) {
+			algoOrders = append(algoOrders, order)
+			continue
+		}
+
 		req := e.client.NewCancelOrderRequest()
 		req.InstrumentID(toLocalSymbol(order.Symbol))
 		req.OrderID(strconv.FormatUint(order.OrderID, 10))
@@ -387,6 +519,16 @@ func (e *Exchange) CancelOrders(ctx context.Context, orders ...types.Order) erro
 		reqs = append(reqs, req)
 	}
 
+	if len(algoOrders) > 0 {
+		if err := e.cancelAlgoOrders(ctx, algoOrders...); err != nil {
+			return err
+		}
+	}
+
+	if len(reqs) == 0 {
+		return nil
+	}
+
 	if err := batchCancelOrderLimiter.Wait(ctx); err != nil {
 		return fmt.Errorf("batch cancel order rate limiter wait error: %w", err)
 	}
@@ -396,6 +538,97 @@ func (e *Exchange) CancelOrders(ctx context.Context, orders ...types.Order) erro
 	return err
 }
 
+func (e *Exchange) cancelAlgoOrders(ctx context.Context, orders ...types.Order) error {
+	req := e.client.NewCancelAlgoOrderRequest()
+	for _, order := range orders {
+		req.Add(toLocalSymbol(order.Symbol), strconv.FormatUint(order.OrderID, 10))
+	}
+
+	if err := cancelAlgoOrderLimiter.Wait(ctx); err != nil {
+		return fmt.Errorf("cancel algo order rate limiter wait error: %w", err)
+	}
+
+	_, err := req.Do(ctx)
+	return err
+}
+
+// QueryOpenAlgoOrders retrieves the pending algo orders, including the trigger orders and the take-profit/stop-loss orders.
+func (e *Exchange) QueryOpenAlgoOrders(ctx context.Context, symbol string) (orders []types.Order, err error) {
+	instrumentID := toLocalSymbol(symbol)
+
+	// the trigger orders can not be queried with the other algo order types
+	for _, orderType := range []string{
+		string(okexapi.AlgoOrderTypeConditional) + "," + string(okexapi.AlgoOrderTypeOCO),
+		string(okexapi.AlgoOrderTypeTrigger),
+	} {
+		var nextCursor string
+		for {
+			if err := queryAlgoOrderLimiter.Wait(ctx); err != nil {
+				return nil, fmt.Errorf("query open algo orders rate limiter wait error: %w", err)
+			}
+
+			req := e.client.NewGetOpenAlgoOrdersRequest().
+				OrderType(orderType).
+				InstrumentID(instrumentID).
+				Limit(strconv.Itoa(defaultQueryLimit))
+			if len(nextCursor) > 0 {
+				req.After(nextCursor)
+			}
+
+			openOrders, err := req.Do(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to query open algo orders: %w", err)
+			}
+
+			for _, o := range openOrders {
+				o, err := algoOrderToGlobal(&o)
+				if err != nil {
+					return nil, fmt.Errorf("failed to convert algo order, err: %v", err)
+				}
+
+				orders = append(orders, *o)
+			}
+
+			orderLen := len(openOrders)
+			if orderLen < defaultQueryLimit {
+				break
+			}
+			nextCursor = strconv.FormatInt(int64(openOrders[orderLen-1].AlgoID), 10)
+		}
+	}
+
+	return orders, nil
+}
+
+// QueryAlgoOrder queries the algo order by the algo id (OrderID) or the algo client order id (ClientOrderID)
+func (e *Exchange) QueryAlgoOrder(ctx context.Context, q types.OrderQuery) (*types.Order, error) {
+	if len(q.OrderID) == 0 && len(q.ClientOrderID) == 0 {
+		return nil, errors.New("okex.QueryAlgoOrder: OrderId or ClientOrderId is required parameter")
+	}
+
+	req := e.client.NewGetAlgoOrderRequest()
+	if len(q.OrderID) > 0 {
+		req.AlgoID(q.OrderID)
+	} else {
+		req.AlgoClientOrderID(q.ClientOrderID)
+	}
+
+	if err := queryAlgoOrderLimiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("query algo order rate limiter wait error: %w", err)
+	}
+
+	orders, err := req.Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(orders) != 1 {
+		return nil, fmt.Errorf("unexpected length of algo order response: %v", orders)
+	}
+
+	return algoOrderToGlobal(&orders[0])
+}
+
 func (e *Exchange) NewStream() types.Stream {
 	return NewStream(e.client, e)
 }
@@ -384,3 +384,104 @@ func TestExchange_QueryTrades(t *testing.T) {
 		assert.ErrorContains(err, ErrSymbolRequired.Error())
 	})
 }
+
+func TestExchange_SubmitAlgoOrder(t *testing.T) {
+	ex := New("key", "secret", "passphrase")
+	market := types.Market{
+		Symbol:          "BTCUSDT",
+		PricePrecision:  1,
+		VolumePrecision: 8,
+		TickSize:        fixedpoint.NewFromFloat(0.1),
+		StepSize:        fixedpoint.NewFromFloat(0.00000001),
+	}
+
+	submit := func(t *testing.T, order types.SubmitOrder) map[string]interface{} {
+		transport := &httptesting.MockTransport{}
+		ex.client.HttpClient.Transport = transport
+
+		var params map[string]interface{}
+		transport.POST("/api/v5/trade/order-algo", func(req *http.Request) (*http.Response, error) {
+			assert.NoError(t, json.NewDecoder(req.Body).Decode(&params))
+			return httptesting.BuildResponseString(http.StatusOK,
+				`{"code":"0","msg":"","data":[{"algoId":"681096944655273984","algoClOrdId":"","sCode":"0","sMsg":""}]}`), nil
+		})
+
+		createdOrder, err := ex.SubmitOrder(context.Background(), order)
+		assert.NoError(t, err)
+		if assert.NotNil(t, createdOrder) {
+			assert.Equal(t, uint64(681096944655273984), createdOrder.OrderID)
+		}
+		return params
+	}
+
+	t.Run("stop limit order", func(t *testing.T) {
+		params := submit(t, types.SubmitOrder{
+			Symbol:    "BTCUSDT",
+			Side:      types.SideTypeBuy,
+			Type:      types.OrderTypeStopLimit,
+			Quantity:  fixedpoint.NewFromFloat(0.001),
+			Price:     fixedpoint.NewFromFloat(70100),
+			StopPrice: fixedpoint.NewFromFloat(70000),
+			Market:    market,
+		})
+		assert.Equal(t, "trigger", params["ordType"])
+		assert.Equal(t, "70000.0", params["triggerPx"])
+		assert.Equal(t, "70100.0", params["orderPx"])
+		assert.Equal(t, "base_ccy", params["tgtCcy"])
+	})
+
+	t.Run("market order with take-profit and stop-loss", func(t *testing.T) {
+		params := submit(t, types.SubmitOrder{
+			Symbol:          "BTCUSDT",
+			Side:            types.SideTypeSell,
+			Type:            types.OrderTypeMarket,
+			Quantity:        fixedpoint.NewFromFloat(0.001),
+			TakeProfitPrice: fixedpoint.NewFromFloat(72000),
+			StopLossPrice:   fixedpoint.NewFromFloat(65000),
+			Market:          market,
+		})
+		assert.Equal(t, "oco", params["ordType"])
+		assert.Equal(t, "72000.0", params["tpTriggerPx"])
+		assert.Equal(t, "-1", params["tpOrdPx"])
+		assert.Equal(t, "65000.0", params["slTriggerPx"])
+		assert.Equal(t, "-1", params["slOrdPx"])
+	})
+
+	t.Run("limit order with stop-loss", func(t *testing.T) {
+		params := submit(t, types.SubmitOrder{
+			Symbol:        "BTCUSDT",
+			Side:          types.SideTypeSell,
+			Type:          types.OrderTypeLimit,
+			Quantity:      fixedpoint.NewFromFloat(0.001),
+			StopLossPrice: fixedpoint.NewFromFloat(65000),
+			Market:        market,
+		})
+		assert.Equal(t, "conditional", params["ordType"])
+		assert.Equal(t, "65000.0", params["slOrdPx"])
+		assert.NotContains(t, params, "tpTriggerPx")
+	})
+}
+
+func TestExchange_CancelAlgoOrders(t *testing.T) {
+	ex := New("key", "secret", "passphrase")
+	transport := &httptesting.MockTransport{}
+	ex.client.HttpClient.Transport = transport
+
+	var params []map[string]interface{}
+	transport.POST("/api/v5/trade/cancel-algos", func(req *http.Request) (*http.Response, error) {
+		assert.NoError(t, json.NewDecoder(req.Body).Decode(&params))
+		return httptesting.BuildResponseString(http.StatusOK,
+			`{"code":"0","msg":"","data":[{"algoId":"681096944655273984","sCode":"0","sMsg":""}]}`), nil
+	})
+
+	err := ex.CancelOrders(context.Background(), types.Order{
+		SubmitOrder: types.SubmitOrder{
+			Symbol:    "BTCUSDT",
+			Type:      types.OrderTypeStopMarket,
+			StopPrice: fixedpoint.NewFromFloat(70000),
+		},
+		OrderID: 681096944655273984,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []map[string]interface{}{{"instId": "BTC-USDT", "algoId": "681096944655273984"}}, params)
+}
@@ -13,7 +13,9 @@ import (
 	"strings"
 	"time"
 
+	"github.com/c9s/bbgo/pkg/exchange/apistats"
 	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
 	"github.com/c9s/requestgen"
 	"github.com/pkg/errors"
 )
@@ -85,7 +87,8 @@ func NewClient() *RestClient {
 		BaseAPIClient: requestgen.BaseAPIClient{
 			BaseURL: parsedBaseURL,
 			HttpClient: &http.Client{
-				Timeout: defaultHTTPTimeout,
+				Timeout:   defaultHTTPTimeout,
+				Transport: apistats.NewTransport(types.ExchangeOKEx, nil),
 			},
 		},
 	}
@@ -0,0 +1,53 @@
+package okexapi
+
+import (
+	"github.com/c9s/requestgen"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+//go:generate -command GetRequest requestgen -method GET -responseType .APIResponse -responseDataField Data
+//go:generate -command PostRequest requestgen -method POST -responseType .APIResponse -responseDataField Data
+
+type AlgoOrder struct {
+	InstrumentType    InstrumentType   `json:"instType"`
+	InstrumentID      string           `json:"instId"`
+	AlgoID            types.StrInt64   `json:"algoId"`
+	AlgoClientOrderID string           `json:"algoClOrdId"`
+	OrderType         AlgoOrderType    `json:"ordType"`
+	Side              SideType         `json:"side"`
+	Size              fixedpoint.Value `json:"sz"`
+	TargetCurrency    TargetCurrency   `json:"tgtCcy"`
+	State             AlgoOrderState   `json:"state"`
+	ReduceOnly        string           `json:"reduceOnly"`
+
+	TakeProfitTriggerPrice fixedpoint.Value `json:"tpTriggerPx"`
+	TakeProfitOrderPrice   fixedpoint.Value `json:"tpOrdPx"`
+	StopLossTriggerPrice   fixedpoint.Value `json:"slTriggerPx"`
+	StopLossOrderPrice     fixedpoint.Value `json:"slOrdPx"`
+
+	TriggerPrice fixedpoint.Value `json:"triggerPx"`
+	OrderPrice   fixedpoint.Value `json:"ordPx"`
+
+	// the actual size and the actual price of the placed order after it's triggered
+	ActualSize  fixedpoint.Value `json:"actualSz"`
+	ActualPrice fixedpoint.Value `json:"actualPx"`
+
+	CreatedTime types.MillisecondTimestamp `json:"cTime"`
+	UpdatedTime types.MillisecondTimestamp `json:"uTime"`
+}
+
+//go:generate GetRequest -url "/api/v5/trade/order-algo" -type GetAlgoOrderRequest -responseDataType []AlgoOrder
+type GetAlgoOrderRequest struct {
+	client requestgen.AuthenticatedAPIClient
+
+	algoID            *string `param:"algoId,query"`
+	algoClientOrderID *string `param:"algoClOrdId,query"`
+}
+
+func (c *RestClient) NewGetAlgoOrderRequest() *GetAlgoOrderRequest {
+	return &GetAlgoOrderRequest{
+		client: c,
+	}
+}
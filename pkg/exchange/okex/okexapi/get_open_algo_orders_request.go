@@ -0,0 +1,26 @@
+package okexapi
+
+import "github.com/c9s/requestgen"
+
+//go:generate -command GetRequest requestgen -method GET -responseType .APIResponse -responseDataField Data
+//go:generate -command PostRequest requestgen -method POST -responseType .APIResponse -responseDataField Data
+
+//go:generate GetRequest -url "/api/v5/trade/orders-algo-pending" -type GetOpenAlgoOrdersRequest -responseDataType []AlgoOrder
+type GetOpenAlgoOrdersRequest struct {
+	client requestgen.AuthenticatedAPIClient
+
+	// orderType is required, the conditional and the oco orders can be queried together by "conditional,oco"
+	orderType      string         `param:"ordType,query"`
+	instrumentType InstrumentType `param:"instType,query"`
+	instrumentID   *string        `param:"instId,query"`
+	// Pagination of data to return records earlier than the requested algoId
+	after *string `param:"after,query"`
+	limit *string `param:"limit,query"`
+}
+
+func (c *RestClient) NewGetOpenAlgoOrdersRequest() *GetOpenAlgoOrdersRequest {
+	return &GetOpenAlgoOrdersRequest{
+		client:         c,
+		instrumentType: InstrumentTypeSpot,
+	}
+}
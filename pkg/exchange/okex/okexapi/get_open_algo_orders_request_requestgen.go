@@ -0,0 +1,230 @@
+// Code generated by "requestgen -method GET -responseType .APIResponse -responseDataField Data -url /api/v5/trade/orders-algo-pending -type GetOpenAlgoOrdersRequest -responseDataType []AlgoOrder"; DO NOT EDIT.
+
+package okexapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"regexp"
+)
+
+func (g *GetOpenAlgoOrdersRequest) OrderType(orderType string) *GetOpenAlgoOrdersRequest {
+	g.orderType = orderType
+	return g
+}
+
+func (g *GetOpenAlgoOrdersRequest) InstrumentType(instrumentType InstrumentType) *GetOpenAlgoOrdersRequest {
+	g.instrumentType = instrumentType
+	return g
+}
+
+func (g *GetOpenAlgoOrdersRequest) InstrumentID(instrumentID string) *GetOpenAlgoOrdersRequest {
+	g.instrumentID = &instrumentID
+	return g
+}
+
+func (g *GetOpenAlgoOrdersRequest) After(after string) *GetOpenAlgoOrdersRequest {
+	g.after = &after
+	return g
+}
+
+func (g *GetOpenAlgoOrdersRequest) Limit(limit string) *GetOpenAlgoOrdersRequest {
+	g.limit = &limit
+	return g
+}
+
+// GetQueryParameters builds and checks the query parameters and returns url.Values
+func (g *GetOpenAlgoOrdersRequest) GetQueryParameters() (url.Values, error) {
+	var params = map[string]interface{}{}
+	// check orderType field -> json key ordType
+	orderType := g.orderType
+
+	// assign parameter of orderType
+	params["ordType"] = orderType
+	// check instrumentType field -> json key instType
+	instrumentType := g.instrumentType
+
+	// TEMPLATE check-valid-values
+	switch instrumentType {
+	case InstrumentTypeSpot, InstrumentTypeSwap, InstrumentTypeFutures, InstrumentTypeOption, InstrumentTypeMARGIN:
+		params["instType"] = instrumentType
+
+	default:
+		return nil, fmt.Errorf("instType value %v is invalid", instrumentType)
+
+	}
+	// END TEMPLATE check-valid-values
+
+	// assign parameter of instrumentType
+	params["instType"] = instrumentType
+	// check instrumentID field -> json key instId
+	if g.instrumentID != nil {
+		instrumentID := *g.instrumentID
+
+		// assign parameter of instrumentID
+		params["instId"] = instrumentID
+	} else {
+	}
+	// check after field -> json key after
+	if g.after != nil {
+		after := *g.after
+
+		// assign parameter of after
+		params["after"] = after
+	} else {
+	}
+	// check limit field -> json key limit
+	if g.limit != nil {
+		limit := *g.limit
+
+		// assign parameter of limit
+		params["limit"] = limit
+	} else {
+	}
+
+	query := url.Values{}
+	for _k, _v := range params {
+		query.Add(_k, fmt.Sprintf("%v", _v))
+	}
+
+	return query, nil
+}
+
+// GetParameters builds and checks the parameters and return the result in a map object
+func (g *GetOpenAlgoOrdersRequest) GetParameters() (map[string]interface{}, error) {
+	var params = map[string]interface{}{}
+
+	return params, nil
+}
+
+// GetParametersQuery converts the parameters from GetParameters into the url.Values format
+func (g *GetOpenAlgoOrdersRequest) GetParametersQuery() (url.Values, error) {
+	query := url.Values{}
+
+	params, err := g.GetParameters()
+	if err != nil {
+		return query, err
+	}
+
+	for _k, _v := range params {
+		if g.isVarSlice(_v) {
+			g.iterateSlice(_v, func(it interface{}) {
+				query.Add(_k+"[]", fmt.Sprintf("%v", it))
+			})
+		} else {
+			query.Add(_k, fmt.Sprintf("%v", _v))
+		}
+	}
+
+	return query, nil
+}
+
+// GetParametersJSON converts the parameters from GetParameters into the JSON format
+func (g *GetOpenAlgoOrdersRequest) GetParametersJSON() ([]byte, error) {
+	params, err := g.GetParameters()
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(params)
+}
+
+// GetSlugParameters builds and checks the slug parameters and return the result in a map object
+func (g *GetOpenAlgoOrdersRequest) GetSlugParameters() (map[string]interface{}, error) {
+	var params = map[string]interface{}{}
+
+	return params, nil
+}
+
+func (g *GetOpenAlgoOrdersRequest) applySlugsToUrl(url string, slugs map[string]string) string {
+	for _k, _v := range slugs {
+		needleRE := regexp.MustCompile(":" + _k + "\\b")
+		url = needleRE.ReplaceAllString(url, _v)
+	}
+
+	return url
+}
+
+func (g *GetOpenAlgoOrdersRequest) iterateSlice(slice interface{}, _f func(it interface{})) {
+	sliceValue := reflect.ValueOf(slice)
+	for _i := 0; _i < sliceValue.Len(); _i++ {
+		it := sliceValue.Index(_i).Interface()
+		_f(it)
+	}
+}
+
+func (g *GetOpenAlgoOrdersRequest) isVarSlice(_v interface{}) bool {
+	rt := reflect.TypeOf(_v)
+	switch rt.Kind() {
+	case reflect.Slice:
+		return true
+	}
+	return false
+}
+
+func (g *GetOpenAlgoOrdersRequest) GetSlugsMap() (map[string]string, error) {
+	slugs := map[string]string{}
+	params, err := g.GetSlugParameters()
+	if err != nil {
+		return slugs, nil
+	}
+
+	for _k, _v := range params {
+		slugs[_k] = fmt.Sprintf("%v", _v)
+	}
+
+	return slugs, nil
+}
+
+// GetPath returns the request path of the API
+func (g *GetOpenAlgoOrdersRequest) GetPath() string {
+	return "/api/v5/trade/orders-algo-pending"
+}
+
+// Do generates the request object and send the request object to the API endpoint
+func (g *GetOpenAlgoOrdersRequest) Do(ctx context.Context) ([]AlgoOrder, error) {
+
+	// no body params
+	var params interface{}
+	query, err := g.GetQueryParameters()
+	if err != nil {
+		return nil, err
+	}
+
+	var apiURL string
+
+	apiURL = g.GetPath()
+
+	req, err := g.client.NewAuthenticatedRequest(ctx, "GET", apiURL, query, params)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := g.client.SendRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var apiResponse APIResponse
+	if err := response.DecodeJSON(&apiResponse); err != nil {
+		return nil, err
+	}
+
+	type responseValidator interface {
+		Validate() error
+	}
+	validator, ok := interface{}(apiResponse).(responseValidator)
+	if ok {
+		if err := validator.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	var data []AlgoOrder
+	if err := json.Unmarshal(apiResponse.Data, &data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
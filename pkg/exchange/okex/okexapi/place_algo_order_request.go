@@ -0,0 +1,81 @@
+package okexapi
+
+import "github.com/c9s/requestgen"
+
+type AlgoOrderType string
+
+const (
+	// AlgoOrderTypeConditional is the one-way take-profit or stop-loss order
+	AlgoOrderTypeConditional AlgoOrderType = "conditional"
+	// AlgoOrderTypeOCO is the one-cancels-the-other order of both the take-profit and the stop-loss
+	AlgoOrderTypeOCO AlgoOrderType = "oco"
+	// AlgoOrderTypeTrigger places the order when the trigger price is reached
+	AlgoOrderTypeTrigger AlgoOrderType = "trigger"
+)
+
+type AlgoOrderState string
+
+const (
+	AlgoOrderStateLive               AlgoOrderState = "live"
+	AlgoOrderStatePause              AlgoOrderState = "pause"
+	AlgoOrderStatePartiallyEffective AlgoOrderState = "partially_effective"
+	AlgoOrderStateEffective          AlgoOrderState = "effective"
+	AlgoOrderStateCanceled           AlgoOrderState = "canceled"
+	AlgoOrderStateOrderFailed        AlgoOrderState = "order_failed"
+	AlgoOrderStatePartiallyFailed    AlgoOrderState = "partially_failed"
+)
+
+func (o AlgoOrderState) IsWorking() bool {
+	return o == AlgoOrderStateLive || o == AlgoOrderStatePause || o == AlgoOrderStatePartiallyEffective
+}
+
+//go:generate -command GetRequest requestgen -method GET -responseType .APIResponse -responseDataField Data
+//go:generate -command PostRequest requestgen -method POST -responseType .APIResponse -responseDataField Data
+
+type AlgoOrderResponse struct {
+	AlgoID            string `json:"algoId"`
+	AlgoClientOrderID string `json:"algoClOrdId"`
+	Code              string `json:"sCode"`
+	Message           string `json:"sMsg"`
+}
+
+//go:generate PostRequest -url "/api/v5/trade/order-algo" -type PlaceAlgoOrderRequest -responseDataType []AlgoOrderResponse
+type PlaceAlgoOrderRequest struct {
+	client requestgen.AuthenticatedAPIClient
+
+	instrumentID string `param:"instId"`
+
+	tradeMode TradeMode `param:"tdMode" validValues:"cross,isolated,cash"`
+
+	side SideType `param:"side" validValues:"buy,sell"`
+
+	orderType AlgoOrderType `param:"ordType" validValues:"conditional,oco,trigger"`
+
+	size string `param:"sz"`
+
+	targetCurrency *TargetCurrency `param:"tgtCcy" validValues:"quote_ccy,base_ccy"`
+
+	// A combination of case-sensitive alphanumerics, all numbers, or all letters of up to 32 characters.
+	algoClientOrderID *string `param:"algoClOrdId"`
+
+	reduceOnly *bool `param:"reduceOnly"`
+
+	// take-profit and stop-loss of the conditional and the oco orders,
+	// the order price -1 means the market price
+	takeProfitTriggerPrice *string `param:"tpTriggerPx"`
+	takeProfitOrderPrice   *string `param:"tpOrdPx"`
+	stopLossTriggerPrice   *string `param:"slTriggerPx"`
+	stopLossOrderPrice     *string `param:"slOrdPx"`
+
+	// trigger price and order price of the trigger orders,
+	// the order price -1 means the market price
+	triggerPrice *string `param:"triggerPx"`
+	orderPrice   *string `param:"orderPx"`
+}
+
+func (c *RestClient) NewPlaceAlgoOrderRequest() *PlaceAlgoOrderRequest {
+	return &PlaceAlgoOrderRequest{
+		client:    c,
+		tradeMode: TradeModeCash,
+	}
+}
@@ -0,0 +1,370 @@
+// Code generated by "requestgen -method POST -responseType .APIResponse -responseDataField Data -url /api/v5/trade/order-algo -type PlaceAlgoOrderRequest -responseDataType []AlgoOrderResponse"; DO NOT EDIT.
+
+package okexapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"regexp"
+)
+
+func (p *PlaceAlgoOrderRequest) InstrumentID(instrumentID string) *PlaceAlgoOrderRequest {
+	p.instrumentID = instrumentID
+	return p
+}
+
+func (p *PlaceAlgoOrderRequest) TradeMode(tradeMode TradeMode) *PlaceAlgoOrderRequest {
+	p.tradeMode = tradeMode
+	return p
+}
+
+func (p *PlaceAlgoOrderRequest) Side(side SideType) *PlaceAlgoOrderRequest {
+	p.side = side
+	return p
+}
+
+func (p *PlaceAlgoOrderRequest) OrderType(orderType AlgoOrderType) *PlaceAlgoOrderRequest {
+	p.orderType = orderType
+	return p
+}
+
+func (p *PlaceAlgoOrderRequest) Size(size string) *PlaceAlgoOrderRequest {
+	p.size = size
+	return p
+}
+
+func (p *PlaceAlgoOrderRequest) TargetCurrency(targetCurrency TargetCurrency) *PlaceAlgoOrderRequest {
+	p.targetCurrency = &targetCurrency
+	return p
+}
+
+func (p *PlaceAlgoOrderRequest) AlgoClientOrderID(algoClientOrderID string) *PlaceAlgoOrderRequest {
+	p.algoClientOrderID = &algoClientOrderID
+	return p
+}
+
+func (p *PlaceAlgoOrderRequest) ReduceOnly(reduceOnly bool) *PlaceAlgoOrderRequest {
+	p.reduceOnly = &reduceOnly
+	return p
+}
+
+func (p *PlaceAlgoOrderRequest) TakeProfitTriggerPrice(takeProfitTriggerPrice string) *PlaceAlgoOrderRequest {
+	p.takeProfitTriggerPrice = &takeProfitTriggerPrice
+	return p
+}
+
+func (p *PlaceAlgoOrderRequest) TakeProfitOrderPrice(takeProfitOrderPrice string) *PlaceAlgoOrderRequest {
+	p.takeProfitOrderPrice = &takeProfitOrderPrice
+	return p
+}
+
+func (p *PlaceAlgoOrderRequest) StopLossTriggerPrice(stopLossTriggerPrice string) *PlaceAlgoOrderRequest {
+	p.stopLossTriggerPrice = &stopLossTriggerPrice
+	return p
+}
+
+func (p *PlaceAlgoOrderRequest) StopLossOrderPrice(stopLossOrderPrice string) *PlaceAlgoOrderRequest {
+	p.stopLossOrderPrice = &stopLossOrderPrice
+	return p
+}
+
+func (p *PlaceAlgoOrderRequest) TriggerPrice(triggerPrice string) *PlaceAlgoOrderRequest {
+	p.triggerPrice = &triggerPrice
+	return p
+}
+
+func (p *PlaceAlgoOrderRequest) OrderPrice(orderPrice string) *PlaceAlgoOrderRequest {
+	p.orderPrice = &orderPrice
+	return p
+}
+
+// GetQueryParameters builds and checks the query parameters and returns url.Values
+func (p *PlaceAlgoOrderRequest) GetQueryParameters() (url.Values, error) {
+	var params = map[string]interface{}{}
+
+	query := url.Values{}
+	for _k, _v := range params {
+		query.Add(_k, fmt.Sprintf("%v", _v))
+	}
+
+	return query, nil
+}
+
+// GetParameters builds and checks the parameters and return the result in a map object
+func (p *PlaceAlgoOrderRequest) GetParameters() (map[string]interface{}, error) {
+	var params = map[string]interface{}{}
+	// check instrumentID field -> json key instId
+	instrumentID := p.instrumentID
+
+	// assign parameter of instrumentID
+	params["instId"] = instrumentID
+	// check tradeMode field -> json key tdMode
+	tradeMode := p.tradeMode
+
+	// TEMPLATE check-valid-values
+	switch tradeMode {
+	case "cross", "isolated", "cash":
+		params["tdMode"] = tradeMode
+
+	default:
+		return nil, fmt.Errorf("tdMode value %v is invalid", tradeMode)
+
+	}
+	// END TEMPLATE check-valid-values
+
+	// assign parameter of tradeMode
+	params["tdMode"] = tradeMode
+	// check side field -> json key side
+	side := p.side
+
+	// TEMPLATE check-valid-values
+	switch side {
+	case "buy", "sell":
+		params["side"] = side
+
+	default:
+		return nil, fmt.Errorf("side value %v is invalid", side)
+
+	}
+	// END TEMPLATE check-valid-values
+
+	// assign parameter of side
+	params["side"] = side
+	// check orderType field -> json key ordType
+	orderType := p.orderType
+
+	// TEMPLATE check-valid-values
+	switch orderType {
+	case "conditional", "oco", "trigger":
+		params["ordType"] = orderType
+
+	default:
+		return nil, fmt.Errorf("ordType value %v is invalid", orderType)
+
+	}
+	// END TEMPLATE check-valid-values
+
+	// assign parameter of orderType
+	params["ordType"] = orderType
+	// check size field -> json key sz
+	size := p.size
+
+	// assign parameter of size
+	params["sz"] = size
+	// check targetCurrency field -> json key tgtCcy
+	if p.targetCurrency != nil {
+		targetCurrency := *p.targetCurrency
+
+		// TEMPLATE check-valid-values
+		switch targetCurrency {
+		case "quote_ccy", "base_ccy":
+			params["tgtCcy"] = targetCurrency
+
+		default:
+			return nil, fmt.Errorf("tgtCcy value %v is invalid", targetCurrency)
+
+		}
+		// END TEMPLATE check-valid-values
+
+		// assign parameter of targetCurrency
+		params["tgtCcy"] = targetCurrency
+	} else {
+	}
+	// check algoClientOrderID field -> json key algoClOrdId
+	if p.algoClientOrderID != nil {
+		algoClientOrderID := *p.algoClientOrderID
+
+		// assign parameter of algoClientOrderID
+		params["algoClOrdId"] = algoClientOrderID
+	} else {
+	}
+	// check reduceOnly field -> json key reduceOnly
+	if p.reduceOnly != nil {
+		reduceOnly := *p.reduceOnly
+
+		// assign parameter of reduceOnly
+		params["reduceOnly"] = reduceOnly
+	} else {
+	}
+	// check takeProfitTriggerPrice field -> json key tpTriggerPx
+	if p.takeProfitTriggerPrice != nil {
+		takeProfitTriggerPrice := *p.takeProfitTriggerPrice
+
+		// assign parameter of takeProfitTriggerPrice
+		params["tpTriggerPx"] = takeProfitTriggerPrice
+	} else {
+	}
+	// check takeProfitOrderPrice field -> json key tpOrdPx
+	if p.takeProfitOrderPrice != nil {
+		takeProfitOrderPrice := *p.takeProfitOrderPrice
+
+		// assign parameter of takeProfitOrderPrice
+		params["tpOrdPx"] = takeProfitOrderPrice
+	} else {
+	}
+	// check stopLossTriggerPrice field -> json key slTriggerPx
+	if p.stopLossTriggerPrice != nil {
+		stopLossTriggerPrice := *p.stopLossTriggerPrice
+
+		// assign parameter of stopLossTriggerPrice
+		params["slTriggerPx"] = stopLossTriggerPrice
+	} else {
+	}
+	// check stopLossOrderPrice field -> json key slOrdPx
+	if p.stopLossOrderPrice != nil {
+		stopLossOrderPrice := *p.stopLossOrderPrice
+
+		// assign parameter of stopLossOrderPrice
+		params["slOrdPx"] = stopLossOrderPrice
+	} else {
+	}
+	// check triggerPrice field -> json key triggerPx
+	if p.triggerPrice != nil {
+		triggerPrice := *p.triggerPrice
+
+		// assign parameter of triggerPrice
+		params["triggerPx"] = triggerPrice
+	} else {
+	}
+	// check orderPrice field -> json key orderPx
+	if p.orderPrice != nil {
+		orderPrice := *p.orderPrice
+
+		// assign parameter of orderPrice
+		params["orderPx"] = orderPrice
+	} else {
+	}
+
+	return params, nil
+}
+
+// GetParametersQuery converts the parameters from GetParameters into the url.Values format
+func (p *PlaceAlgoOrderRequest) GetParametersQuery() (url.Values, error) {
+	query := url.Values{}
+
+	params, err := p.GetParameters()
+	if err != nil {
+		return query, err
+	}
+
+	for _k, _v := range params {
+		if p.isVarSlice(_v) {
+			p.iterateSlice(_v, func(it interface{}) {
+				query.Add(_k+"[]", fmt.Sprintf("%v", it))
+			})
+		} else {
+			query.Add(_k, fmt.Sprintf("%v", _v))
+		}
+	}
+
+	return query, nil
+}
+
+// GetParametersJSON converts the parameters from GetParameters into the JSON format
+func (p *PlaceAlgoOrderRequest) GetParametersJSON() ([]byte, error) {
+	params, err := p.GetParameters()
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(params)
+}
+
+// GetSlugParameters builds and checks the slug parameters and return the result in a map object
+func (p *PlaceAlgoOrderRequest) GetSlugParameters() (map[string]interface{}, error) {
+	var params = map[string]interface{}{}
+
+	return params, nil
+}
+
+func (p *PlaceAlgoOrderRequest) applySlugsToUrl(url string, slugs map[string]string) string {
+	for _k, _v := range slugs {
+		needleRE := regexp.MustCompile(":" + _k + "\\b")
+		url = needleRE.ReplaceAllString(url, _v)
+	}
+
+	return url
+}
+
+func (p *PlaceAlgoOrderRequest) iterateSlice(slice interface{}, _f func(it interface{})) {
+	sliceValue := reflect.ValueOf(slice)
+	for _i := 0; _i < sliceValue.Len(); _i++ {
+		it := sliceValue.Index(_i).Interface()
+		_f(it)
+	}
+}
+
+func (p *PlaceAlgoOrderRequest) isVarSlice(_v interface{}) bool {
+	rt := reflect.TypeOf(_v)
+	switch rt.Kind() {
+	case reflect.Slice:
+		return true
+	}
+	return false
+}
+
+func (p *PlaceAlgoOrderRequest) GetSlugsMap() (map[string]string, error) {
+	slugs := map[string]string{}
+	params, err := p.GetSlugParameters()
+	if err != nil {
+		return slugs, nil
+	}
+
+	for _k, _v := range params {
+		slugs[_k] = fmt.Sprintf("%v", _v)
+	}
+
+	return slugs, nil
+}
+
+// GetPath returns the request path of the API
+func (p *PlaceAlgoOrderRequest) GetPath() string {
+	return "/api/v5/trade/order-algo"
+}
+
+// Do generates the request object and send the request object to the API endpoint
+func (p *PlaceAlgoOrderRequest) Do(ctx context.Context) ([]AlgoOrderResponse, error) {
+
+	params, err := p.GetParameters()
+	if err != nil {
+		return nil, err
+	}
+	query := url.Values{}
+
+	var apiURL string
+
+	apiURL = p.GetPath()
+
+	req, err := p.client.NewAuthenticatedRequest(ctx, "POST", apiURL, query, params)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := p.client.SendRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var apiResponse APIResponse
+	if err := response.DecodeJSON(&apiResponse); err != nil {
+		return nil, err
+	}
+
+	type responseValidator interface {
+		Validate() error
+	}
+	validator, ok := interface{}(apiResponse).(responseValidator)
+	if ok {
+		if err := validator.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	var data []AlgoOrderResponse
+	if err := json.Unmarshal(apiResponse.Data, &data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
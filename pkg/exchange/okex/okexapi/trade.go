@@ -23,6 +23,12 @@ func (c *RestClient) NewBatchCancelOrderRequest() *BatchCancelOrderRequest {
 	}
 }
 
+func (c *RestClient) NewCancelAlgoOrderRequest() *CancelAlgoOrderRequest {
+	return &CancelAlgoOrderRequest{
+		client: c,
+	}
+}
+
 func (c *RestClient) NewGetOrderDetailsRequest() *GetOrderDetailsRequest {
 	return &GetOrderDetailsRequest{
 		client: c,
@@ -84,6 +90,44 @@ func (r *BatchCancelOrderRequest) Do(ctx context.Context) ([]OrderResponse, erro
 	return data, nil
 }
 
+// CancelAlgoOrderRequest cancels the algo orders, the endpoint only accepts the array of the algo orders
+type CancelAlgoOrderRequest struct {
+	client *RestClient
+
+	params []map[string]interface{}
+}
+
+func (r *CancelAlgoOrderRequest) Add(instrumentID, algoID string) *CancelAlgoOrderRequest {
+	r.params = append(r.params, map[string]interface{}{
+		"instId": instrumentID,
+		"algoId": algoID,
+	})
+	return r
+}
+
+func (r *CancelAlgoOrderRequest) Do(ctx context.Context) ([]AlgoOrderResponse, error) {
+	req, err := r.client.NewAuthenticatedRequest(ctx, "POST", "/api/v5/trade/cancel-algos", nil, r.params)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := r.client.SendRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var apiResponse APIResponse
+	if err := response.DecodeJSON(&apiResponse); err != nil {
+		return nil, err
+	}
+	var data []AlgoOrderResponse
+	if err := json.Unmarshal(apiResponse.Data, &data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
 type BatchPlaceOrderRequest struct {
 	client *RestClient
 
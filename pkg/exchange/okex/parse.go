@@ -21,6 +21,9 @@ const (
 	ChannelAccount      Channel = "account"
 	ChannelMarketTrades Channel = "trades"
 	ChannelOrderTrades  Channel = "orders"
+
+	// ChannelLiquidationOrders pushes the liquidation orders of all the instruments of the instrument type
+	ChannelLiquidationOrders Channel = "liquidation-orders"
 )
 
 type ActionType string
@@ -66,6 +69,14 @@ func parseWebSocketEvent(in []byte) (interface{}, error) {
 		}
 		return trade, nil
 
+	case ChannelLiquidationOrders:
+		var liquidationOrders []LiquidationOrderEvent
+		if err := json.Unmarshal(event.Data, &liquidationOrders); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal data into LiquidationOrderEvent: %+v, err: %w", string(event.Data), err)
+		}
+
+		return liquidationOrders, nil
+
 	case ChannelOrderTrades:
 		var orderTrade []OrderTradeEvent
 		err := json.Unmarshal(event.Data, &orderTrade)
@@ -364,6 +375,57 @@ func toGlobalSideType(side okexapi.SideType) (types.SideType, error) {
 	}
 }
 
+// LiquidationOrderEvent is the event of the liquidation-orders channel
+//
+//	{"details":[{"bkLoss":"0","bkPx":"26312.5","ccy":"","posSide":"short","side":"buy","sz":"13","ts":"1692266434010"}],
+//	 "instFamily":"BTC-USDT","instId":"BTC-USDT-SWAP","instType":"SWAP","uly":"BTC-USDT"}
+type LiquidationOrderEvent struct {
+	InstrumentID   string                   `json:"instId"`
+	InstrumentType string                   `json:"instType"`
+	Details        []LiquidationOrderDetail `json:"details"`
+}
+
+type LiquidationOrderDetail struct {
+	// BankruptcyPrice is the price of the liquidation order
+	BankruptcyPrice fixedpoint.Value `json:"bkPx"`
+
+	// BankruptcyLoss is the loss of the liquidation covered by the insurance fund
+	BankruptcyLoss fixedpoint.Value `json:"bkLoss"`
+
+	PosSide   string                     `json:"posSide"`
+	Side      okexapi.SideType           `json:"side"`
+	Size      fixedpoint.Value           `json:"sz"`
+	Timestamp types.MillisecondTimestamp `json:"ts"`
+}
+
+// LiquidationInfos converts the liquidation orders of the perpetual swap to the liquidation info of the underlying symbol,
+// the size is in the contracts of the swap.
+func (e *LiquidationOrderEvent) LiquidationInfos() (infos []types.LiquidationInfo) {
+	symbol := toGlobalSymbol(strings.TrimSuffix(e.InstrumentID, "-"+e.InstrumentType))
+	for _, detail := range e.Details {
+		side, err := toGlobalSideType(detail.Side)
+		if err != nil {
+			log.WithError(err).Warnf("unexpected liquidation order side: %+v", detail)
+			continue
+		}
+
+		infos = append(infos, types.LiquidationInfo{
+			Exchange:       types.ExchangeOKEx,
+			Symbol:         symbol,
+			Side:           side,
+			OrderType:      types.OrderTypeMarket,
+			Quantity:       detail.Size,
+			Price:          detail.BankruptcyPrice,
+			AveragePrice:   detail.BankruptcyPrice,
+			OrderStatus:    types.OrderStatusFilled,
+			TradeTime:      types.Time(detail.Timestamp.Time()),
+			BankruptcyLoss: detail.BankruptcyLoss,
+		})
+	}
+
+	return infos
+}
+
 type MarketTradeEvent struct {
 	InstId    string                     `json:"instId"`
 	TradeId   types.StrInt64             `json:"tradeId"`
@@ -1139,3 +1139,30 @@ func TestOrderTradeEvent_toGlobalTrade1(t *testing.T) {
 	})
 
 }
+
+func Test_parseWebSocketEvent_liquidationOrders(t *testing.T) {
+	in := `{"arg":{"channel":"liquidation-orders","instType":"SWAP"},"data":[{"details":[{"bkLoss":"0","bkPx":"26312.5","ccy":"","posSide":"short","side":"buy","sz":"13","ts":"1692266434010"}],"instFamily":"BTC-USDT","instId":"BTC-USDT-SWAP","instType":"SWAP","uly":"BTC-USDT"}]}`
+
+	res, err := parseWebSocketEvent([]byte(in))
+	assert.NoError(t, err)
+
+	events, ok := res.([]LiquidationOrderEvent)
+	assert.True(t, ok)
+	assert.Len(t, events, 1)
+
+	infos := events[0].LiquidationInfos()
+	if assert.Len(t, infos, 1) {
+		assert.Equal(t, types.LiquidationInfo{
+			Exchange:       types.ExchangeOKEx,
+			Symbol:         "BTCUSDT",
+			Side:           types.SideTypeBuy,
+			OrderType:      types.OrderTypeMarket,
+			Quantity:       fixedpoint.NewFromInt(13),
+			Price:          fixedpoint.NewFromFloat(26312.5),
+			AveragePrice:   fixedpoint.NewFromFloat(26312.5),
+			OrderStatus:    types.OrderStatusFilled,
+			TradeTime:      types.Time(types.NewMillisecondTimestampFromInt(1692266434010).Time()),
+			BankruptcyLoss: fixedpoint.Zero,
+		}, infos[0])
+	}
+}
@@ -48,6 +48,8 @@ type Stream struct {
 	accountEventCallbacks     []func(account okexapi.Account)
 	orderTradesEventCallbacks []func(orderTrades []OrderTradeEvent)
 	marketTradeEventCallbacks []func(tradeDetail []MarketTradeEvent)
+
+	liquidationOrderEventCallbacks []func(liquidationOrders []LiquidationOrderEvent)
 }
 
 func NewStream(client *okexapi.RestClient, balanceProvider types.ExchangeAccountService) *Stream {
@@ -66,6 +68,7 @@ func NewStream(client *okexapi.RestClient, balanceProvider types.ExchangeAccount
 	stream.OnBookEvent(stream.handleBookEvent)
 	stream.OnAccountEvent(stream.handleAccountEvent)
 	stream.OnMarketTradeEvent(stream.handleMarketTradeEvent)
+	stream.OnLiquidationOrderEvent(stream.handleLiquidationOrderEvent)
 	stream.OnOrderTradesEvent(stream.handleOrderDetailsEvent)
 	stream.OnConnect(stream.handleConnect)
 	stream.OnAuth(stream.subscribePrivateChannels(stream.emitBalanceSnapshot))
@@ -159,6 +162,11 @@ func (s *Stream) handleConnect() {
 				continue
 			}
 
+			// the channel without the instrument id, e.g. liquidation-orders, is subscribed once
+			if containsSubscription(subs, sub) {
+				continue
+			}
+
 			subs = append(subs, sub)
 		}
 		subscribe(s.StandardStream.Conn, subs)
@@ -280,6 +288,35 @@ func (s *Stream) handleMarketTradeEvent(data []MarketTradeEvent) {
 	}
 }
 
+func (s *Stream) handleLiquidationOrderEvent(data []LiquidationOrderEvent) {
+	symbols := make(map[string]struct{})
+	for _, sub := range s.Subscriptions {
+		if sub.Channel == types.ForceOrderChannel {
+			symbols[sub.Symbol] = struct{}{}
+		}
+	}
+
+	for _, event := range data {
+		for _, info := range event.LiquidationInfos() {
+			if _, ok := symbols[info.Symbol]; !ok {
+				continue
+			}
+
+			s.EmitForceOrder(info)
+		}
+	}
+}
+
+func containsSubscription(subs []WebsocketSubscription, sub WebsocketSubscription) bool {
+	for _, s := range subs {
+		if s == sub {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (s *Stream) createEndpoint(ctx context.Context) (string, error) {
 	var url string
 	if s.PublicOnly {
@@ -317,5 +354,8 @@ func (s *Stream) dispatchEvent(e interface{}) {
 	case []MarketTradeEvent:
 		s.EmitMarketTradeEvent(et)
 
+	case []LiquidationOrderEvent:
+		s.EmitLiquidationOrderEvent(et)
+
 	}
 }
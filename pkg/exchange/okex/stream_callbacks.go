@@ -56,6 +56,16 @@ func (s *Stream) EmitMarketTradeEvent(tradeDetail []MarketTradeEvent) {
 	}
 }
 
+func (s *Stream) OnLiquidationOrderEvent(cb func(liquidationOrders []LiquidationOrderEvent)) {
+	s.liquidationOrderEventCallbacks = append(s.liquidationOrderEventCallbacks, cb)
+}
+
+func (s *Stream) EmitLiquidationOrderEvent(liquidationOrders []LiquidationOrderEvent) {
+	for _, cb := range s.liquidationOrderEventCallbacks {
+		cb(liquidationOrders)
+	}
+}
+
 type StreamEventHub interface {
 	OnKLineEvent(cb func(candle KLineEvent))
 
@@ -66,4 +76,6 @@ type StreamEventHub interface {
 	OnOrderTradesEvent(cb func(orderTrades []OrderTradeEvent))
 
 	OnMarketTradeEvent(cb func(tradeDetail []MarketTradeEvent))
+
+	OnLiquidationOrderEvent(cb func(liquidationOrders []LiquidationOrderEvent))
 }
@@ -288,7 +288,37 @@ func (v Value) MarshalJSON() ([]byte, error) {
 	if v.IsInf() {
 		return []byte("\"" + v.String() + "\""), nil
 	}
-	return []byte(v.FormatString(DefaultPrecision)), nil
+	return v.appendFormat(make([]byte, 0, 24)), nil
+}
+
+// appendFormat appends the value formatted in the default precision to the buffer,
+// it's the allocation-free version of FormatString(DefaultPrecision).
+func (v Value) appendFormat(buf []byte) []byte {
+	u := int64(v)
+	if u < 0 {
+		buf = append(buf, '-')
+	}
+
+	a := u / DefaultPow
+	b := u % DefaultPow
+	if a < 0 {
+		a = -a
+	}
+
+	if b < 0 {
+		b = -b
+	}
+
+	buf = strconv.AppendInt(buf, a, 10)
+	buf = append(buf, '.')
+
+	var frac [DefaultPrecision]byte
+	for i := DefaultPrecision - 1; i >= 0; i-- {
+		frac[i] = byte('0' + b%10)
+		b /= 10
+	}
+
+	return append(buf, frac[:]...)
 }
 
 func (v *Value) UnmarshalJSON(data []byte) error {
@@ -304,7 +334,7 @@ func (v *Value) UnmarshalJSON(data []byte) error {
 	if data[0] == '"' {
 		data = data[1 : len(data)-1]
 	}
-	if *v, err = NewFromString(string(data)); err != nil {
+	if *v, err = NewFromBytes(data); err != nil {
 		return err
 	}
 	return nil
@@ -476,17 +506,89 @@ func MustNewFromString(input string) Value {
 }
 
 func NewFromBytes(input []byte) (Value, error) {
+	if v, ok := parseDecimalBytes(input); ok {
+		return v, nil
+	}
+
 	return NewFromString(string(input))
 }
 
 func MustNewFromBytes(input []byte) (v Value) {
 	var err error
-	if v, err = NewFromString(string(input)); err != nil {
+	if v, err = NewFromBytes(input); err != nil {
 		return Zero
 	}
 	return v
 }
 
+// maxFastParseValue is the upper bound of the fast path parser,
+// the values beyond it are left to the float parser of NewFromString to keep the same rounding behavior.
+const maxFastParseValue = 1 << 53
+
+// parseDecimalBytes is the fast path of parsing the plain decimal numbers like "-123.45678" that
+// exchanges send in their JSON messages, the digits are parsed directly without the string allocations.
+// It returns false for the inputs that it doesn't handle, e.g., scientific notation, percentage, inf or
+// more than 8 fractional digits, the caller should fall back to NewFromString.
+func parseDecimalBytes(input []byte) (Value, bool) {
+	i := 0
+	neg := false
+	if len(input) > 0 && input[0] == '-' {
+		neg = true
+		i++
+	}
+
+	var num int64
+	intDigits := 0
+	for ; i < len(input) && input[i] >= '0' && input[i] <= '9'; i++ {
+		num = num*10 + int64(input[i]-'0')
+		intDigits++
+		if num >= maxFastParseValue {
+			return 0, false
+		}
+	}
+
+	if intDigits == 0 {
+		return 0, false
+	}
+
+	fracDigits := 0
+	if i < len(input) && input[i] == '.' {
+		i++
+		for ; i < len(input) && input[i] >= '0' && input[i] <= '9'; i++ {
+			fracDigits++
+			if fracDigits > DefaultPrecision {
+				return 0, false
+			}
+
+			num = num*10 + int64(input[i]-'0')
+			if num >= maxFastParseValue {
+				return 0, false
+			}
+		}
+
+		if fracDigits == 0 {
+			return 0, false
+		}
+	}
+
+	if i != len(input) {
+		return 0, false
+	}
+
+	for ; fracDigits < DefaultPrecision; fracDigits++ {
+		num *= 10
+		if num >= maxFastParseValue {
+			return 0, false
+		}
+	}
+
+	if neg {
+		num = -num
+	}
+
+	return Value(num), true
+}
+
 func Must(v Value, err error) Value {
 	if err != nil {
 		panic(err)
@@ -0,0 +1,128 @@
+//go:build !dnum
+
+package fixedpoint
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_parseDecimalBytes(t *testing.T) {
+	cases := []struct {
+		input    string
+		expected Value
+		ok       bool
+	}{
+		{input: "0", expected: 0, ok: true},
+		{input: "1", expected: One, ok: true},
+		{input: "-1", expected: NegOne, ok: true},
+		{input: "0.1", expected: Value(1e7), ok: true},
+		{input: "-0.00000001", expected: Value(-1), ok: true},
+		{input: "27000.12345678", expected: Value(2700012345678), ok: true},
+		{input: "", ok: false},
+		{input: "-", ok: false},
+		{input: ".5", ok: false},
+		{input: "1.", ok: false},
+		{input: "1e3", ok: false},
+		{input: "1%", ok: false},
+		{input: "inf", ok: false},
+		{input: "+1", ok: false},
+		{input: "0.123456789", ok: false},
+		{input: "99999999999999999999", ok: false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.input, func(t *testing.T) {
+			v, ok := parseDecimalBytes([]byte(c.input))
+			assert.Equal(t, c.ok, ok)
+			if c.ok {
+				assert.Equal(t, c.expected, v)
+			}
+		})
+	}
+}
+
+func Test_JSONRoundTrip(t *testing.T) {
+	type message struct {
+		Price    Value `json:"p"`
+		Quantity Value `json:"q"`
+	}
+
+	var msg message
+	err := json.Unmarshal([]byte(`{"p":"27000.10","q":0.0015}`), &msg)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "27000.1", msg.Price.String())
+		assert.Equal(t, "0.0015", msg.Quantity.String())
+	}
+
+	out, err := json.Marshal(msg)
+	if assert.NoError(t, err) {
+		assert.Equal(t, `{"p":27000.10000000,"q":0.00150000}`, string(out))
+	}
+}
+
+func FuzzNewFromBytes(f *testing.F) {
+	for _, seed := range []string{
+		"0", "1", "-1", "0.1", "123.456", "-0.00000001", "27000.12345678", "1.123456789",
+		"1e3", "1.5%", "inf", "-inf", ".5", "1.", "+1", "00012.5", "90071992.54740991",
+	} {
+		f.Add([]byte(seed))
+	}
+
+	f.Fuzz(func(t *testing.T, input []byte) {
+		v, ok := parseDecimalBytes(input)
+		if !ok {
+			return
+		}
+
+		expected, err := NewFromString(string(input))
+		if err != nil {
+			t.Fatalf("fast path parsed %q as %v, but NewFromString failed: %v", input, v, err)
+		}
+
+		if v != expected {
+			t.Fatalf("fast path parsed %q as %d, expected %d", input, int64(v), int64(expected))
+		}
+	})
+}
+
+func FuzzMarshalJSON(f *testing.F) {
+	for _, seed := range []int64{0, 1, -1, 1e8, -1e8, 50000000, -50000000, 2700012345678, 1<<62 + 12345} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, i int64) {
+		v := Value(i)
+		if v.IsInf() {
+			return
+		}
+
+		out, err := v.MarshalJSON()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if expected := v.FormatString(DefaultPrecision); string(out) != expected {
+			t.Fatalf("MarshalJSON(%d) = %s, expected %s", i, out, expected)
+		}
+	})
+}
+
+func BenchmarkUnmarshalJSON(b *testing.B) {
+	data := []byte(`"27000.12345678"`)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var v Value
+		_ = v.UnmarshalJSON(data)
+	}
+}
+
+func BenchmarkMarshalJSON(b *testing.B) {
+	v := MustNewFromString("27000.12345678")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = v.MarshalJSON()
+	}
+}
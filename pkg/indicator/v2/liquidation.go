@@ -0,0 +1,69 @@
+package indicatorv2
+
+import (
+	"sync"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// LiquidationVolumeStream accumulates the notional value of the forced liquidation orders in each kline interval,
+// the value is pushed when the kline is closed, so that the strategies can detect the liquidation cascades.
+type LiquidationVolumeStream struct {
+	*types.Float64Series
+
+	// BuyVolume is the liquidation volume of the short positions, SellVolume is the volume of the long positions
+	BuyVolume, SellVolume *types.Float64Series
+
+	mu              sync.Mutex
+	buyVol, sellVol fixedpoint.Value
+}
+
+// LiquidationVolume creates the liquidation volume stream of the symbol,
+// the source stream should subscribe both the ForceOrderChannel and the KLineChannel of the interval.
+func LiquidationVolume(source types.Stream, symbol string, interval types.Interval) *LiquidationVolumeStream {
+	s := &LiquidationVolumeStream{
+		Float64Series: types.NewFloat64Series(),
+		BuyVolume:     types.NewFloat64Series(),
+		SellVolume:    types.NewFloat64Series(),
+	}
+
+	source.OnForceOrder(func(info types.LiquidationInfo) {
+		if info.Symbol != symbol {
+			return
+		}
+
+		s.Add(info)
+	})
+
+	source.OnKLineClosed(types.KLineWith(symbol, interval, func(k types.KLine) {
+		s.Flush()
+	}))
+
+	return s
+}
+
+// Add accumulates the liquidation order into the current interval
+func (s *LiquidationVolumeStream) Add(info types.LiquidationInfo) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch info.Side {
+	case types.SideTypeBuy:
+		s.buyVol = s.buyVol.Add(info.QuoteQuantity())
+	case types.SideTypeSell:
+		s.sellVol = s.sellVol.Add(info.QuoteQuantity())
+	}
+}
+
+// Flush pushes the accumulated liquidation volume of the current interval and resets it
+func (s *LiquidationVolumeStream) Flush() {
+	s.mu.Lock()
+	buyVol, sellVol := s.buyVol, s.sellVol
+	s.buyVol, s.sellVol = fixedpoint.Zero, fixedpoint.Zero
+	s.mu.Unlock()
+
+	s.BuyVolume.PushAndEmit(buyVol.Float64())
+	s.SellVolume.PushAndEmit(sellVol.Float64())
+	s.PushAndEmit(buyVol.Add(sellVol).Float64())
+}
@@ -0,0 +1,44 @@
+package indicatorv2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestLiquidationVolume(t *testing.T) {
+	stream := &types.StandardStream{}
+	liquidationVolume := LiquidationVolume(stream, "BTCUSDT", types.Interval1m)
+
+	stream.EmitForceOrder(types.LiquidationInfo{
+		Symbol:       "BTCUSDT",
+		Side:         types.SideTypeSell,
+		Quantity:     fixedpoint.NewFromFloat(0.5),
+		AveragePrice: fixedpoint.NewFromFloat(40000.0),
+	})
+	stream.EmitForceOrder(types.LiquidationInfo{
+		Symbol:   "BTCUSDT",
+		Side:     types.SideTypeBuy,
+		Quantity: fixedpoint.NewFromFloat(0.1),
+		Price:    fixedpoint.NewFromFloat(40000.0),
+	})
+	stream.EmitForceOrder(types.LiquidationInfo{
+		Symbol:   "ETHUSDT",
+		Side:     types.SideTypeBuy,
+		Quantity: fixedpoint.NewFromFloat(10),
+		Price:    fixedpoint.NewFromFloat(2000.0),
+	})
+	stream.EmitKLineClosed(types.KLine{Symbol: "BTCUSDT", Interval: types.Interval1m})
+
+	assert.InDelta(t, 24000.0, liquidationVolume.Last(0), 0.0001)
+	assert.InDelta(t, 4000.0, liquidationVolume.BuyVolume.Last(0), 0.0001)
+	assert.InDelta(t, 20000.0, liquidationVolume.SellVolume.Last(0), 0.0001)
+
+	// the volume is reset after the kline is closed
+	stream.EmitKLineClosed(types.KLine{Symbol: "BTCUSDT", Interval: types.Interval1m})
+	assert.Equal(t, 0.0, liquidationVolume.Last(0))
+	assert.Equal(t, 2, liquidationVolume.Length())
+}
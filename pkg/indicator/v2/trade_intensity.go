@@ -0,0 +1,179 @@
+package indicatorv2
+
+import (
+	"math"
+	"sync"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// MidPriceFunc returns the mid price at the time of the market trade
+type MidPriceFunc func() (fixedpoint.Value, bool)
+
+// TradeIntensityStream estimates the arrival intensity of the market orders that reach a distance from the mid price,
+// it's the lambda(delta) = A * exp(-k * delta) of the Avellaneda-Stoikov model, the distance delta is the ratio to the mid price.
+//
+// The market trades are counted by the distance levels in each kline interval, the intensity of a level is the number of
+// the trades that reach the level per second over the window, and A and k are fitted by the least squares of the log intensities.
+// The pushed value is A, the arrival rate of all the market trades.
+type TradeIntensityStream struct {
+	*types.Float64Series
+
+	// Levels are the distance ratios of the intensity levels
+	Levels []float64
+
+	window   int
+	interval types.Interval
+	midPrice MidPriceFunc
+
+	mu     sync.Mutex
+	counts []float64
+
+	// windowCounts are the trade counts of the levels in the closed intervals
+	windowCounts [][]float64
+
+	a, k float64
+}
+
+// TradeIntensity creates the trade intensity stream of the symbol, the levels are spaced evenly up to the max distance,
+// the source stream should subscribe both the MarketTradeChannel and the KLineChannel of the interval.
+func TradeIntensity(
+	source types.Stream, symbol string, iw types.IntervalWindow, maxDistance float64, numLevels int, midPrice MidPriceFunc,
+) *TradeIntensityStream {
+	s := NewTradeIntensityStream(iw, maxDistance, numLevels, midPrice)
+
+	source.OnMarketTrade(func(trade types.Trade) {
+		if trade.Symbol != symbol {
+			return
+		}
+
+		s.AddTrade(trade)
+	})
+
+	source.OnKLineClosed(types.KLineWith(symbol, iw.Interval, func(k types.KLine) {
+		s.Flush()
+	}))
+
+	return s
+}
+
+func NewTradeIntensityStream(iw types.IntervalWindow, maxDistance float64, numLevels int, midPrice MidPriceFunc) *TradeIntensityStream {
+	levels := make([]float64, numLevels)
+	for i := range levels {
+		levels[i] = maxDistance * float64(i) / float64(numLevels)
+	}
+
+	return &TradeIntensityStream{
+		Float64Series: types.NewFloat64Series(),
+		Levels:        levels,
+		window:        iw.Window,
+		interval:      iw.Interval,
+		midPrice:      midPrice,
+		counts:        make([]float64, numLevels),
+	}
+}
+
+// AddTrade counts the market trade into the levels that are reached by the trade price
+func (s *TradeIntensityStream) AddTrade(trade types.Trade) {
+	mid, ok := s.midPrice()
+	if !ok || mid.Sign() <= 0 {
+		return
+	}
+
+	distance := math.Abs(trade.Price.Sub(mid).Div(mid).Float64())
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, level := range s.Levels {
+		if distance < level {
+			break
+		}
+
+		s.counts[i]++
+	}
+}
+
+// Flush closes the trade counts of the current interval, and fits the intensity curve over the window
+func (s *TradeIntensityStream) Flush() {
+	s.mu.Lock()
+	s.windowCounts = append(s.windowCounts, s.counts)
+	if s.window > 0 && len(s.windowCounts) > s.window {
+		s.windowCounts = s.windowCounts[len(s.windowCounts)-s.window:]
+	}
+	s.counts = make([]float64, len(s.Levels))
+
+	seconds := s.interval.Duration().Seconds() * float64(len(s.windowCounts))
+	intensities := make([]float64, len(s.Levels))
+	for _, counts := range s.windowCounts {
+		for i, count := range counts {
+			intensities[i] += count / seconds
+		}
+	}
+
+	a, k, ok := fitExponentialDecay(s.Levels, intensities)
+	if ok {
+		s.a, s.k = a, k
+	}
+	s.mu.Unlock()
+
+	if ok {
+		s.PushAndEmit(a)
+	}
+}
+
+// Parameters returns the fitted A and k of the intensity curve, ok is false before the first fit
+func (s *TradeIntensityStream) Parameters() (a, k float64, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.a, s.k, s.k > 0
+}
+
+// Intensity returns the arrival rate per second of the market orders that reach the distance ratio from the mid price
+func (s *TradeIntensityStream) Intensity(distance float64) float64 {
+	a, k, ok := s.Parameters()
+	if !ok {
+		return 0
+	}
+
+	return a * math.Exp(-k*distance)
+}
+
+// FillProbability returns the probability that the quote at the distance ratio from the mid price is filled
+// within the horizon in seconds, assuming the market orders arrive as a poisson process.
+func (s *TradeIntensityStream) FillProbability(distance, horizon float64) float64 {
+	return 1.0 - math.Exp(-s.Intensity(distance)*horizon)
+}
+
+// fitExponentialDecay fits y = a * exp(-k * x) by the least squares of log(y), the zero intensities are skipped
+func fitExponentialDecay(xs, ys []float64) (a, k float64, ok bool) {
+	var n, sumX, sumY, sumXX, sumXY float64
+	for i, y := range ys {
+		if y <= 0 {
+			continue
+		}
+
+		x, logY := xs[i], math.Log(y)
+		n++
+		sumX += x
+		sumY += logY
+		sumXX += x * x
+		sumXY += x * logY
+	}
+
+	denominator := n*sumXX - sumX*sumX
+	if n < 2 || denominator == 0 {
+		return 0, 0, false
+	}
+
+	slope := (n*sumXY - sumX*sumY) / denominator
+	intercept := (sumY - slope*sumX) / n
+
+	// the intensity should decay with the distance
+	if slope >= 0 {
+		return 0, 0, false
+	}
+
+	return math.Exp(intercept), -slope, true
+}
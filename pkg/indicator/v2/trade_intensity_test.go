@@ -0,0 +1,67 @@
+package indicatorv2
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestTradeIntensity(t *testing.T) {
+	stream := &types.StandardStream{}
+	mid := fixedpoint.NewFromFloat(100.0)
+	intensity := TradeIntensity(stream, "BTCUSDT", types.IntervalWindow{Interval: types.Interval1s, Window: 2}, 0.004, 4,
+		func() (fixedpoint.Value, bool) { return mid, true })
+
+	assert.Equal(t, []float64{0, 0.001, 0.002, 0.003}, intensity.Levels)
+
+	emitTrades := func(price float64, n int) {
+		for i := 0; i < n; i++ {
+			stream.EmitMarketTrade(types.Trade{Symbol: "BTCUSDT", Price: fixedpoint.NewFromFloat(price)})
+		}
+	}
+
+	emitTrades(100.05, 4)  // distance 0.0005
+	emitTrades(99.85, 2)   // distance 0.0015
+	emitTrades(100.25, 1)  // distance 0.0025
+	emitTrades(100.35, 1)  // distance 0.0035
+	emitTrades(100.0, 100) // distance 0
+
+	// the trades of the other symbols are ignored
+	stream.EmitMarketTrade(types.Trade{Symbol: "ETHUSDT", Price: fixedpoint.NewFromFloat(1000.0)})
+
+	_, _, ok := intensity.Parameters()
+	assert.False(t, ok)
+
+	stream.EmitKLineClosed(types.KLine{Symbol: "BTCUSDT", Interval: types.Interval1s})
+	a, k, ok := intensity.Parameters()
+	assert.True(t, ok)
+	assert.Equal(t, 1, intensity.Length())
+	assert.InDelta(t, a, intensity.Last(0), 1e-9)
+
+	// the counts of the levels are 108, 4, 2, 1 in one second
+	assert.Greater(t, k, 0.0)
+	assert.Greater(t, intensity.Intensity(0), intensity.Intensity(0.002))
+	assert.Greater(t, intensity.FillProbability(0.001, 1.0), intensity.FillProbability(0.003, 1.0))
+	assert.InDelta(t, 1-math.Exp(-intensity.Intensity(0.001)*2), intensity.FillProbability(0.001, 2), 1e-9)
+}
+
+func TestFitExponentialDecay(t *testing.T) {
+	xs := []float64{0, 0.001, 0.002, 0.003}
+	ys := make([]float64, len(xs))
+	for i, x := range xs {
+		ys[i] = 5.0 * math.Exp(-700.0*x)
+	}
+
+	a, k, ok := fitExponentialDecay(xs, ys)
+	assert.True(t, ok)
+	assert.InDelta(t, 5.0, a, 1e-6)
+	assert.InDelta(t, 700.0, k, 1e-6)
+
+	// the increasing intensities can not be fitted
+	_, _, ok = fitExponentialDecay(xs, []float64{1, 2, 3, 4})
+	assert.False(t, ok)
+}
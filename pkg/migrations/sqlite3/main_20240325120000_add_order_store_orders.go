@@ -0,0 +1,29 @@
+package sqlite3
+
+import (
+	"context"
+
+	"github.com/c9s/rockhopper/v2"
+)
+
+func init() {
+	AddMigration("main", up_main_addOrderStoreOrders, down_main_addOrderStoreOrders)
+}
+
+func up_main_addOrderStoreOrders(ctx context.Context, tx rockhopper.SQLExecutor) (err error) {
+	// This code is executed when the migration is applied.
+	_, err = tx.ExecContext(ctx, "CREATE TABLE `order_store_orders`\n(\n    `store_id`   VARCHAR(128) NOT NULL,\n    `exchange`   VARCHAR(24)  NOT NULL DEFAULT '',\n    `order_id`   INTEGER      NOT NULL,\n    `status`     VARCHAR(20)  NOT NULL,\n    `data`       TEXT         NOT NULL,\n    `updated_at` DATETIME(3)  NOT NULL,\n    PRIMARY KEY (`store_id`, `exchange`, `order_id`)\n);")
+	if err != nil {
+		return err
+	}
+	return err
+}
+
+func down_main_addOrderStoreOrders(ctx context.Context, tx rockhopper.SQLExecutor) (err error) {
+	// This code is executed when the migration is rolled back.
+	_, err = tx.ExecContext(ctx, "DROP TABLE `order_store_orders`;")
+	if err != nil {
+		return err
+	}
+	return err
+}
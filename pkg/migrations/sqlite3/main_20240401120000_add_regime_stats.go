@@ -0,0 +1,33 @@
+package sqlite3
+
+import (
+	"context"
+
+	"github.com/c9s/rockhopper/v2"
+)
+
+func init() {
+	AddMigration("main", up_main_addRegimeStats, down_main_addRegimeStats)
+}
+
+func up_main_addRegimeStats(ctx context.Context, tx rockhopper.SQLExecutor) (err error) {
+	// This code is executed when the migration is applied.
+	_, err = tx.ExecContext(ctx, "CREATE TABLE `regime_stats`\n(\n    `gid`                  INTEGER PRIMARY KEY AUTOINCREMENT,\n    `strategy`             VARCHAR(32)    NOT NULL,\n    `strategy_instance_id` VARCHAR(64)    NOT NULL,\n    `exchange`             VARCHAR(24)    NOT NULL DEFAULT '',\n    `symbol`               VARCHAR(20)    NOT NULL,\n    `volatility_regime`    VARCHAR(16)    NOT NULL,\n    `trend_regime`         VARCHAR(16)    NOT NULL,\n    `start_time`           DATETIME(3)    NOT NULL,\n    `end_time`             DATETIME(3)    NOT NULL,\n    `num_orders`           INTEGER        NOT NULL DEFAULT 0,\n    `num_fills`            INTEGER        NOT NULL DEFAULT 0,\n    `quoted_volume`        DECIMAL(32, 8) NOT NULL DEFAULT 0.0,\n    `filled_volume`        DECIMAL(32, 8) NOT NULL DEFAULT 0.0,\n    `fill_ratio`           DECIMAL(16, 8) NOT NULL DEFAULT 0.0,\n    `profit`               DECIMAL(32, 8) NOT NULL DEFAULT 0.0,\n    `net_profit`           DECIMAL(32, 8) NOT NULL DEFAULT 0.0,\n    `num_markouts`         INTEGER        NOT NULL DEFAULT 0,\n    `average_markout`      DECIMAL(16, 8) NOT NULL DEFAULT 0.0\n);")
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, "CREATE INDEX `regime_stats_instance_time` ON `regime_stats` (`strategy_instance_id`, `start_time`);")
+	if err != nil {
+		return err
+	}
+	return err
+}
+
+func down_main_addRegimeStats(ctx context.Context, tx rockhopper.SQLExecutor) (err error) {
+	// This code is executed when the migration is rolled back.
+	_, err = tx.ExecContext(ctx, "DROP TABLE `regime_stats`;")
+	if err != nil {
+		return err
+	}
+	return err
+}
@@ -31,6 +31,14 @@ type SelectorConfig struct {
 
 type LocalExecutorConfig struct {
 	MaxNumberOfProcesses int `json:"maxNumberOfProcesses" yaml:"maxNumberOfProcesses"`
+
+	// KLineCacheDir is the kline cache directory shared by the back-test processes,
+	// defaults to the "shared/klinecache" directory under the output directory.
+	KLineCacheDir string `json:"klineCacheDir,omitempty" yaml:"klineCacheDir,omitempty"`
+
+	// Seed is the base seed of the back-test runs, each parameter set is seeded with the seed derived from its config,
+	// so that the same parameter set always produces the same result no matter which worker runs it.
+	Seed int64 `json:"seed,omitempty" yaml:"seed,omitempty"`
 }
 
 type ExecutorConfig struct {
@@ -6,8 +6,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"hash/fnv"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 	"sync"
 
@@ -94,9 +96,59 @@ func (e *LocalProcessExecutor) Prepare(configJson []byte) error {
 		return errors.Wrapf(err, "failed to sync backtest data: %s", string(output))
 	}
 
+	// run the base config once, so that the parallel back tests load the klines from the shared cache files
+	// instead of querying the database at the same time
+	log.Debugln("warming up the kline cache before starting backtests...")
+	if _, err := e.Execute(configJson); err != nil {
+		return errors.Wrap(err, "failed to warm up the kline cache")
+	}
+
 	return nil
 }
 
+func (e *LocalProcessExecutor) klineCacheDir() string {
+	if e.Config != nil && len(e.Config.KLineCacheDir) > 0 {
+		return e.Config.KLineCacheDir
+	}
+
+	return filepath.Join(e.OutputDir, "shared", "klinecache")
+}
+
+// withBacktestOptions sets the shared kline cache directory and the deterministic seed of the back-test config
+func (e *LocalProcessExecutor) withBacktestOptions(configJson []byte) ([]byte, error) {
+	var baseSeed int64
+	if e.Config != nil {
+		baseSeed = e.Config.Seed
+	}
+
+	return setBacktestOptions(configJson, e.klineCacheDir(), baseSeed)
+}
+
+func setBacktestOptions(configJson []byte, klineCacheDir string, baseSeed int64) ([]byte, error) {
+	var o map[string]interface{}
+	if err := json.Unmarshal(configJson, &o); err != nil {
+		return nil, err
+	}
+
+	backtestConfig, ok := o["backtest"].(map[string]interface{})
+	if !ok {
+		return configJson, nil
+	}
+
+	// the seed is derived from the config before the options are set, so it only depends on the parameters.
+	// keep 53 bits since the config is converted to YAML through float64 numbers
+	h := fnv.New64a()
+	_, _ = h.Write(configJson)
+	seed := int64((h.Sum64() ^ uint64(baseSeed)) >> 11)
+	if seed == 0 {
+		seed = 1
+	}
+
+	backtestConfig["klineCacheDir"] = klineCacheDir
+	backtestConfig["seed"] = seed
+	return json.Marshal(o)
+}
+
 func (e *LocalProcessExecutor) Run(ctx context.Context, taskC chan BacktestTask, bar *pb.ProgressBar) (chan BacktestTask, error) {
 	var maxNumOfProcess = e.Config.MaxNumberOfProcesses
 	var resultsC = make(chan BacktestTask, maxNumOfProcess*2)
@@ -153,6 +205,11 @@ func (e *LocalProcessExecutor) Run(ctx context.Context, taskC chan BacktestTask,
 
 // Execute runs the config json and returns the summary report. This is a blocking operation.
 func (e *LocalProcessExecutor) Execute(configJson []byte) (*backtest.SummaryReport, error) {
+	configJson, err := e.withBacktestOptions(configJson)
+	if err != nil {
+		return nil, err
+	}
+
 	tf, err := jsonToYamlConfig(e.ConfigDir, configJson)
 	if err != nil {
 		return nil, err
@@ -1,6 +1,7 @@
 package optimizer
 
 import (
+	"encoding/json"
 	"os"
 	"testing"
 
@@ -19,3 +20,28 @@ func Test_jsonToYamlConfig(t *testing.T) {
 
 	_ = os.RemoveAll(".tmpconfig")
 }
+
+func Test_setBacktestOptions(t *testing.T) {
+	configJson := []byte(`{"backtest":{"startTime":"2023-01-01"},"exchangeStrategies":[{"on":"binance","xmaker":{"margin":0.001}}]}`)
+
+	out, err := setBacktestOptions(configJson, "cache", 0)
+	assert.NoError(t, err)
+
+	out2, err := setBacktestOptions(configJson, "cache", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, out, out2, "the same parameter set should be seeded with the same seed")
+
+	var o struct {
+		Backtest struct {
+			KLineCacheDir string `json:"klineCacheDir"`
+			Seed          int64  `json:"seed"`
+		} `json:"backtest"`
+	}
+	assert.NoError(t, json.Unmarshal(out, &o))
+	assert.Equal(t, "cache", o.Backtest.KLineCacheDir)
+	assert.NotZero(t, o.Backtest.Seed)
+
+	out3, err := setBacktestOptions([]byte(`{"backtest":{"startTime":"2023-01-01"},"exchangeStrategies":[{"on":"binance","xmaker":{"margin":0.002}}]}`), "cache", 0)
+	assert.NoError(t, err)
+	assert.NotEqual(t, out, out3)
+}
@@ -0,0 +1,265 @@
+package pricesolver
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/c9s/bbgo/pkg/datasource/wise"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+const defaultFXRateUpdateInterval = 5 * time.Minute
+
+// CurrencyPair is the pair of the exchange rate, e.g. USD/TWD is the price of USD in TWD
+type CurrencyPair struct {
+	Base  string
+	Quote string
+}
+
+// ParseCurrencyPair parses the currency pair separated by the slash, e.g. USD/TWD
+func ParseCurrencyPair(s string) (CurrencyPair, error) {
+	parts := strings.Split(s, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return CurrencyPair{}, fmt.Errorf("invalid currency pair %q, the pair should be like USD/TWD", s)
+	}
+
+	return CurrencyPair{
+		Base:  strings.ToUpper(parts[0]),
+		Quote: strings.ToUpper(parts[1]),
+	}, nil
+}
+
+func (p CurrencyPair) String() string {
+	return p.Base + "/" + p.Quote
+}
+
+// FXRateProvider provides the exchange rate of the currency pair, the rate is the price of the base currency in the quote currency
+type FXRateProvider interface {
+	QueryRate(ctx context.Context, base, quote string) (fixedpoint.Value, error)
+}
+
+// BatchFXRateProvider queries the rates of the currency pairs in one batch, the pairs that can't be resolved
+// are not included in the returned rates
+type BatchFXRateProvider interface {
+	QueryRates(ctx context.Context, pairs ...CurrencyPair) (map[CurrencyPair]fixedpoint.Value, error)
+}
+
+// ExchangeFXRateProvider provides the cross rates from the tickers of the exchange markets,
+// e.g. the USDTTWD market of MAX for the USDT/TWD rate, the inverse market is used if the direct market doesn't exist.
+type ExchangeFXRateProvider struct {
+	service types.ExchangeMarketDataService
+	markets types.MarketMap
+}
+
+func NewExchangeFXRateProvider(service types.ExchangeMarketDataService, markets types.MarketMap) *ExchangeFXRateProvider {
+	return &ExchangeFXRateProvider{
+		service: service,
+		markets: markets,
+	}
+}
+
+func (p *ExchangeFXRateProvider) QueryRate(ctx context.Context, base, quote string) (fixedpoint.Value, error) {
+	if market, ok := findMarket(p.markets, base, quote); ok {
+		return p.queryMidPrice(ctx, market.Symbol)
+	}
+
+	if market, ok := findMarket(p.markets, quote, base); ok {
+		price, err := p.queryMidPrice(ctx, market.Symbol)
+		if err != nil {
+			return fixedpoint.Zero, err
+		}
+
+		return fixedpoint.One.Div(price), nil
+	}
+
+	return fixedpoint.Zero, fmt.Errorf("market of %s/%s is not found", base, quote)
+}
+
+func findMarket(markets types.MarketMap, base, quote string) (types.Market, bool) {
+	for _, market := range markets {
+		if market.BaseCurrency == base && market.QuoteCurrency == quote {
+			return market, true
+		}
+	}
+
+	return types.Market{}, false
+}
+
+// QueryRates resolves the rates of the pairs from the tickers queried by a single QueryTickers request
+func (p *ExchangeFXRateProvider) QueryRates(ctx context.Context, pairs ...CurrencyPair) (map[CurrencyPair]fixedpoint.Value, error) {
+	var symbols []string
+	for _, pair := range pairs {
+		if market, ok := findMarket(p.markets, pair.Base, pair.Quote); ok {
+			symbols = append(symbols, market.Symbol)
+		} else if market, ok := findMarket(p.markets, pair.Quote, pair.Base); ok {
+			symbols = append(symbols, market.Symbol)
+		}
+	}
+
+	rates := make(map[CurrencyPair]fixedpoint.Value, len(pairs))
+	if len(symbols) == 0 {
+		return rates, nil
+	}
+
+	tickers, err := p.service.QueryTickers(ctx, symbols...)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, pair := range pairs {
+		if market, ok := findMarket(p.markets, pair.Base, pair.Quote); ok {
+			if ticker, ok := tickers[market.Symbol]; ok {
+				if price, err := tickerMidPrice(market.Symbol, ticker); err == nil {
+					rates[pair] = price
+				}
+			}
+		} else if market, ok := findMarket(p.markets, pair.Quote, pair.Base); ok {
+			if ticker, ok := tickers[market.Symbol]; ok {
+				if price, err := tickerMidPrice(market.Symbol, ticker); err == nil {
+					rates[pair] = fixedpoint.One.Div(price)
+				}
+			}
+		}
+	}
+
+	return rates, nil
+}
+
+// queryMidPrice returns the mid-price of the ticker, the last price is used if the book price is not available
+func (p *ExchangeFXRateProvider) queryMidPrice(ctx context.Context, symbol string) (fixedpoint.Value, error) {
+	ticker, err := p.service.QueryTicker(ctx, symbol)
+	if err != nil {
+		return fixedpoint.Zero, err
+	}
+
+	return tickerMidPrice(symbol, *ticker)
+}
+
+func tickerMidPrice(symbol string, ticker types.Ticker) (fixedpoint.Value, error) {
+	price := ticker.Last
+	if ticker.Buy.Sign() > 0 && ticker.Sell.Sign() > 0 {
+		price = ticker.Buy.Add(ticker.Sell).Div(fixedpoint.Two)
+	}
+
+	if price.Sign() <= 0 {
+		return fixedpoint.Zero, fmt.Errorf("invalid %s ticker price: %s", symbol, price.String())
+	}
+
+	return price, nil
+}
+
+// WiseFXRateProvider provides the fiat exchange rates from the Wise API
+type WiseFXRateProvider struct {
+	client *wise.Client
+}
+
+func NewWiseFXRateProvider(client *wise.Client) *WiseFXRateProvider {
+	return &WiseFXRateProvider{client: client}
+}
+
+func (p *WiseFXRateProvider) QueryRate(ctx context.Context, base, quote string) (fixedpoint.Value, error) {
+	rates, err := p.client.QueryRate(ctx, base, quote)
+	if err != nil {
+		return fixedpoint.Zero, err
+	}
+
+	if len(rates) == 0 {
+		return fixedpoint.Zero, fmt.Errorf("no rate of %s/%s is returned", base, quote)
+	}
+
+	latest := rates[0]
+	for _, rate := range rates[1:] {
+		if rate.Time.Time().After(latest.Time.Time()) {
+			latest = rate
+		}
+	}
+
+	return latest.Value, nil
+}
+
+// FXRateFeed updates the exchange rates of the currency pairs into the price solver periodically,
+// so that the prices in the fiat quote currencies, e.g. EUR, KRW and TWD, can be converted into the reporting currency.
+type FXRateFeed struct {
+	solver   *SimplePriceSolver
+	provider FXRateProvider
+	pairs    []CurrencyPair
+	interval time.Duration
+
+	logger logrus.FieldLogger
+}
+
+func NewFXRateFeed(solver *SimplePriceSolver, provider FXRateProvider, interval time.Duration, pairs ...CurrencyPair) *FXRateFeed {
+	if interval <= 0 {
+		interval = defaultFXRateUpdateInterval
+	}
+
+	return &FXRateFeed{
+		solver:   solver,
+		provider: provider,
+		pairs:    pairs,
+		interval: interval,
+		logger:   logrus.WithField("component", "fxRateFeed"),
+	}
+}
+
+// Update queries the rates of the pairs and updates them into the price solver,
+// the pairs that fail are skipped, and the last error is returned.
+// The rates are queried in one batch if the provider supports it.
+func (f *FXRateFeed) Update(ctx context.Context) (err error) {
+	if batch, ok := f.provider.(BatchFXRateProvider); ok {
+		rates, err2 := batch.QueryRates(ctx, f.pairs...)
+		if err2 != nil {
+			f.logger.WithError(err2).Warnf("unable to query the rates of %v", f.pairs)
+			return err2
+		}
+
+		for _, pair := range f.pairs {
+			rate, ok := rates[pair]
+			if !ok {
+				f.logger.Warnf("unable to resolve the %s rate", pair)
+				err = fmt.Errorf("unable to resolve the %s rate", pair)
+				continue
+			}
+
+			f.solver.UpdateRate(pair.Base, pair.Quote, rate)
+		}
+
+		return err
+	}
+
+	for _, pair := range f.pairs {
+		rate, err2 := f.provider.QueryRate(ctx, pair.Base, pair.Quote)
+		if err2 != nil {
+			f.logger.WithError(err2).Warnf("unable to query the %s rate", pair)
+			err = err2
+			continue
+		}
+
+		f.solver.UpdateRate(pair.Base, pair.Quote, rate)
+	}
+
+	return err
+}
+
+// Run updates the rates immediately and then updates them periodically until the context is canceled
+func (f *FXRateFeed) Run(ctx context.Context) {
+	_ = f.Update(ctx)
+
+	ticker := time.NewTicker(f.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-ticker.C:
+			_ = f.Update(ctx)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package pricesolver
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+	"github.com/c9s/bbgo/pkg/types/mocks"
+)
+
+type fakeFXRateProvider map[string]fixedpoint.Value
+
+func (p fakeFXRateProvider) QueryRate(ctx context.Context, base, quote string) (fixedpoint.Value, error) {
+	if rate, ok := p[base+"/"+quote]; ok {
+		return rate, nil
+	}
+
+	return fixedpoint.Zero, fmt.Errorf("rate %s/%s is not found", base, quote)
+}
+
+func TestParseCurrencyPair(t *testing.T) {
+	pair, err := ParseCurrencyPair("usd/TWD")
+	if assert.NoError(t, err) {
+		assert.Equal(t, CurrencyPair{Base: "USD", Quote: "TWD"}, pair)
+		assert.Equal(t, "USD/TWD", pair.String())
+	}
+
+	_, err = ParseCurrencyPair("USDTWD")
+	assert.Error(t, err)
+}
+
+func TestFXRateFeed_Update(t *testing.T) {
+	markets := types.MarketMap{
+		"BTCTWD":  {Symbol: "BTCTWD", BaseCurrency: "BTC", QuoteCurrency: "TWD"},
+		"BTCUSDT": {Symbol: "BTCUSDT", BaseCurrency: "BTC", QuoteCurrency: "USDT"},
+	}
+
+	solver := NewSimplePriceResolver(markets)
+	solver.Update("BTCTWD", fixedpoint.NewFromFloat(3_200_000.0))
+
+	provider := fakeFXRateProvider{
+		"USD/TWD": fixedpoint.NewFromFloat(32.0),
+		"USD/EUR": fixedpoint.NewFromFloat(0.9),
+	}
+
+	feed := NewFXRateFeed(solver, provider, 0,
+		CurrencyPair{Base: "USD", Quote: "TWD"},
+		CurrencyPair{Base: "USD", Quote: "EUR"},
+		CurrencyPair{Base: "USD", Quote: "KRW"},
+	)
+
+	// the missing KRW rate is skipped
+	assert.Error(t, feed.Update(context.Background()))
+
+	price, ok := solver.ResolvePrice("BTC", "USD")
+	assert.True(t, ok)
+	assert.Equal(t, "100000", price.String())
+
+	price, ok = solver.ResolvePrice("TWD", "EUR", "USD")
+	assert.True(t, ok)
+	assert.InDelta(t, 0.028125, price.Float64(), 1e-9)
+}
+
+func TestExchangeFXRateProvider(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	markets := types.MarketMap{
+		"USDTTWD": {Symbol: "USDTTWD", BaseCurrency: "USDT", QuoteCurrency: "TWD"},
+	}
+
+	ex := mocks.NewMockExchangePublic(mockCtrl)
+	ex.EXPECT().QueryTicker(gomock.Any(), "USDTTWD").Return(&types.Ticker{
+		Buy:  fixedpoint.NewFromFloat(31.9),
+		Sell: fixedpoint.NewFromFloat(32.1),
+		Last: fixedpoint.NewFromFloat(31.5),
+	}, nil).Times(2)
+
+	provider := NewExchangeFXRateProvider(ex, markets)
+
+	rate, err := provider.QueryRate(context.Background(), "USDT", "TWD")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "32", rate.String())
+	}
+
+	rate, err = provider.QueryRate(context.Background(), "TWD", "USDT")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "0.03125", rate.String())
+	}
+
+	_, err = provider.QueryRate(context.Background(), "EUR", "TWD")
+	assert.Error(t, err)
+}
+
+func TestExchangeFXRateProvider_QueryRates(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	markets := types.MarketMap{
+		"USDTTWD": {Symbol: "USDTTWD", BaseCurrency: "USDT", QuoteCurrency: "TWD"},
+		"BTCUSDT": {Symbol: "BTCUSDT", BaseCurrency: "BTC", QuoteCurrency: "USDT"},
+	}
+
+	ex := mocks.NewMockExchangePublic(mockCtrl)
+	ex.EXPECT().QueryTickers(gomock.Any(), "USDTTWD", "BTCUSDT").Return(map[string]types.Ticker{
+		"USDTTWD": {
+			Buy:  fixedpoint.NewFromFloat(31.9),
+			Sell: fixedpoint.NewFromFloat(32.1),
+		},
+		"BTCUSDT": {
+			Last: fixedpoint.NewFromFloat(100_000.0),
+		},
+	}, nil).Times(1)
+
+	provider := NewExchangeFXRateProvider(ex, markets)
+
+	solver := NewSimplePriceResolver(markets)
+	feed := NewFXRateFeed(solver, provider, 0,
+		CurrencyPair{Base: "USDT", Quote: "TWD"},
+		CurrencyPair{Base: "USDT", Quote: "BTC"},
+		CurrencyPair{Base: "EUR", Quote: "TWD"},
+	)
+
+	// the pairs are resolved by one QueryTickers request, the pair without a market is skipped
+	assert.Error(t, feed.Update(context.Background()))
+
+	price, ok := solver.ResolvePrice("USDT", "TWD")
+	assert.True(t, ok)
+	assert.Equal(t, "32", price.String())
+
+	price, ok = solver.ResolvePrice("USDT", "BTC")
+	assert.True(t, ok)
+	assert.Equal(t, "0.00001", price.String())
+
+	_, ok = solver.ResolvePrice("EUR", "TWD")
+	assert.False(t, ok)
+}
@@ -0,0 +1,278 @@
+package pricesolver
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+const defaultMaxHops = 4
+
+var (
+	ErrNoPricePath = errors.New("no price path")
+	ErrStalePrice  = errors.New("stale price")
+)
+
+type priceEdge struct {
+	price      fixedpoint.Value
+	updateTime time.Time
+}
+
+// PriceHop is one conversion of the price path
+type PriceHop struct {
+	From       string           `json:"from"`
+	To         string           `json:"to"`
+	Price      fixedpoint.Value `json:"price"`
+	UpdateTime time.Time        `json:"updateTime"`
+}
+
+// PricePath is the conversion path of the resolved price
+type PricePath struct {
+	Hops  []PriceHop       `json:"hops"`
+	Price fixedpoint.Value `json:"price"`
+}
+
+// OldestUpdateTime returns the update time of the oldest edge of the path
+func (p *PricePath) OldestUpdateTime() (oldest time.Time) {
+	for i, hop := range p.Hops {
+		if i == 0 || hop.UpdateTime.Before(oldest) {
+			oldest = hop.UpdateTime
+		}
+	}
+
+	return oldest
+}
+
+func (p *PricePath) String() string {
+	if len(p.Hops) == 0 {
+		return ""
+	}
+
+	currencies := []string{p.Hops[0].From}
+	for _, hop := range p.Hops {
+		currencies = append(currencies, hop.To)
+	}
+
+	return strings.Join(currencies, "->")
+}
+
+// GraphPriceSolver resolves the price of an asset in a currency through the conversion graph of the markets,
+// the currencies are the nodes and the last prices of the markets are the edges in both directions.
+// The path with the fewest hops is used, and the freshest one is picked among the paths of the same hops.
+// The edges older than MaxStaleness are never used, so the price is refused when every path has a stale edge.
+type GraphPriceSolver struct {
+	// MaxStaleness is the max age of the edges in the path, zero means no staleness bound
+	MaxStaleness time.Duration
+
+	// MaxHops is the max number of the conversions in the path, defaults to 4
+	MaxHops int
+
+	mu sync.Mutex
+
+	markets types.MarketMap
+
+	// edges maps the currency to the currencies it converts to
+	edges map[string]map[string]*priceEdge
+}
+
+func NewGraphPriceSolver(markets types.MarketMap, maxStaleness time.Duration) *GraphPriceSolver {
+	return &GraphPriceSolver{
+		MaxStaleness: maxStaleness,
+		MaxHops:      defaultMaxHops,
+		markets:      markets,
+		edges:        make(map[string]map[string]*priceEdge),
+	}
+}
+
+// Update updates the last price of the market with the current time
+func (s *GraphPriceSolver) Update(symbol string, price fixedpoint.Value) {
+	s.UpdateAt(symbol, price, time.Now())
+}
+
+// UpdateAt updates the last price of the market at the given time
+func (s *GraphPriceSolver) UpdateAt(symbol string, price fixedpoint.Value, updateTime time.Time) {
+	market, ok := s.markets[symbol]
+	if !ok {
+		return
+	}
+
+	s.UpdateRate(market.BaseCurrency, market.QuoteCurrency, price, updateTime)
+}
+
+// UpdateRate updates the price of the base currency in the quote currency without a market,
+// e.g. the fiat exchange rates from the FXRateFeed
+func (s *GraphPriceSolver) UpdateRate(base, quote string, price fixedpoint.Value, updateTime time.Time) {
+	if price.Sign() <= 0 || base == quote {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.setEdge(base, quote, price, updateTime)
+	s.setEdge(quote, base, fixedpoint.One.Div(price), updateTime)
+}
+
+func (s *GraphPriceSolver) setEdge(from, to string, price fixedpoint.Value, updateTime time.Time) {
+	edges, ok := s.edges[from]
+	if !ok {
+		edges = make(map[string]*priceEdge)
+		s.edges[from] = edges
+	}
+
+	edge, ok := edges[to]
+	if !ok {
+		edges[to] = &priceEdge{price: price, updateTime: updateTime}
+		return
+	}
+
+	// the out-of-order updates are ignored
+	if updateTime.Before(edge.updateTime) {
+		return
+	}
+
+	edge.price = price
+	edge.updateTime = updateTime
+}
+
+// BindStream updates the prices from the kline updates of the stream
+func (s *GraphPriceSolver) BindStream(stream types.Stream) {
+	stream.OnKLine(func(k types.KLine) {
+		s.UpdateAt(k.Symbol, k.Close, k.EndTime.Time())
+	})
+
+	stream.OnKLineClosed(func(k types.KLine) {
+		s.UpdateAt(k.Symbol, k.Close, k.EndTime.Time())
+	})
+}
+
+// ResolvePrice resolves the price of the asset in the currency with the current time,
+// it implements the core.PriceResolver interface. The preferred intermediate currencies are tried first.
+func (s *GraphPriceSolver) ResolvePrice(asset, currency string, prefers ...string) (fixedpoint.Value, bool) {
+	now := time.Now()
+
+	for _, intermediate := range prefers {
+		if intermediate == asset || intermediate == currency {
+			continue
+		}
+
+		path1, err := s.resolvePath(asset, intermediate, now, 1)
+		if err != nil {
+			continue
+		}
+
+		path2, err := s.resolvePath(intermediate, currency, now, 1)
+		if err != nil {
+			continue
+		}
+
+		return path1.Price.Mul(path2.Price), true
+	}
+
+	path, err := s.ResolvePath(asset, currency, now)
+	if err != nil {
+		return fixedpoint.Zero, false
+	}
+
+	return path.Price, true
+}
+
+// ResolvePath finds the best conversion path from the asset to the currency at the given time,
+// ErrStalePrice is returned when the paths exist but every path has an edge older than MaxStaleness.
+func (s *GraphPriceSolver) ResolvePath(asset, currency string, now time.Time) (*PricePath, error) {
+	maxHops := s.MaxHops
+	if maxHops <= 0 {
+		maxHops = defaultMaxHops
+	}
+
+	return s.resolvePath(asset, currency, now, maxHops)
+}
+
+func (s *GraphPriceSolver) resolvePath(asset, currency string, now time.Time, maxHops int) (*PricePath, error) {
+	if asset == currency {
+		return &PricePath{Price: fixedpoint.One}, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var since time.Time
+	if s.MaxStaleness > 0 {
+		since = now.Add(-s.MaxStaleness)
+	}
+
+	if path := s.search(asset, currency, maxHops, since); path != nil {
+		return path, nil
+	}
+
+	if !since.IsZero() {
+		if path := s.search(asset, currency, maxHops, time.Time{}); path != nil {
+			return nil, fmt.Errorf("%w: %s path %s is older than %s, last update %s",
+				ErrStalePrice, asset+currency, path, s.MaxStaleness, path.OldestUpdateTime())
+		}
+	}
+
+	return nil, fmt.Errorf("%w: from %s to %s", ErrNoPricePath, asset, currency)
+}
+
+// search relaxes the paths hop by hop, the first hop count that reaches the currency wins,
+// and the path of which the oldest edge is the freshest is kept for each currency in the same hop count.
+func (s *GraphPriceSolver) search(asset, currency string, maxHops int, since time.Time) *PricePath {
+	visited := map[string]bool{asset: true}
+	frontier := map[string]*PricePath{asset: {Price: fixedpoint.One}}
+
+	for hop := 0; hop < maxHops && len(frontier) > 0; hop++ {
+		next := make(map[string]*PricePath)
+		for from, path := range frontier {
+			for to, edge := range s.edges[from] {
+				if visited[to] || edge.updateTime.Before(since) {
+					continue
+				}
+
+				candidate := &PricePath{
+					Hops: append(append([]PriceHop{}, path.Hops...), PriceHop{
+						From:       from,
+						To:         to,
+						Price:      edge.price,
+						UpdateTime: edge.updateTime,
+					}),
+					Price: path.Price.Mul(edge.price),
+				}
+
+				if best, ok := next[to]; ok && !fresherPath(candidate, best) {
+					continue
+				}
+
+				next[to] = candidate
+			}
+		}
+
+		if path, ok := next[currency]; ok {
+			return path
+		}
+
+		for to := range next {
+			visited[to] = true
+		}
+
+		frontier = next
+	}
+
+	return nil
+}
+
+// fresherPath returns true when the oldest edge of the path a is newer than the one of the path b,
+// the path string is compared for the tie to keep the result deterministic.
+func fresherPath(a, b *PricePath) bool {
+	ta, tb := a.OldestUpdateTime(), b.OldestUpdateTime()
+	if !ta.Equal(tb) {
+		return ta.After(tb)
+	}
+
+	return a.String() < b.String()
+}
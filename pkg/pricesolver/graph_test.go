@@ -0,0 +1,105 @@
+package pricesolver
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestGraphPriceSolver(t *testing.T) {
+	now := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	markets := types.MarketMap{
+		"ETHBTC":   {Symbol: "ETHBTC", BaseCurrency: "ETH", QuoteCurrency: "BTC"},
+		"BTCUSDC":  {Symbol: "BTCUSDC", BaseCurrency: "BTC", QuoteCurrency: "USDC"},
+		"BTCUSDT":  {Symbol: "BTCUSDT", BaseCurrency: "BTC", QuoteCurrency: "USDT"},
+		"USDCUSDT": {Symbol: "USDCUSDT", BaseCurrency: "USDC", QuoteCurrency: "USDT"},
+		"USDTTWD":  {Symbol: "USDTTWD", BaseCurrency: "USDT", QuoteCurrency: "TWD"},
+	}
+
+	solver := NewGraphPriceSolver(markets, time.Minute)
+	solver.UpdateAt("ETHBTC", fixedpoint.NewFromFloat(0.05), now)
+	solver.UpdateAt("BTCUSDC", fixedpoint.NewFromFloat(20000.0), now)
+	solver.UpdateAt("USDCUSDT", fixedpoint.NewFromFloat(1.0), now)
+	solver.UpdateAt("USDTTWD", fixedpoint.NewFromFloat(30.0), now)
+
+	t.Run("direct and inverse", func(t *testing.T) {
+		path, err := solver.ResolvePath("BTC", "USDC", now)
+		if assert.NoError(t, err) {
+			assert.Equal(t, "20000", path.Price.String())
+		}
+
+		path, err = solver.ResolvePath("USDC", "BTC", now)
+		if assert.NoError(t, err) {
+			assert.Equal(t, "0.00005", path.Price.String())
+		}
+	})
+
+	t.Run("multi-hop", func(t *testing.T) {
+		path, err := solver.ResolvePath("ETH", "TWD", now)
+		if assert.NoError(t, err) {
+			assert.Equal(t, "ETH->BTC->USDC->USDT->TWD", path.String())
+			assert.Equal(t, "30000", path.Price.String())
+		}
+	})
+
+	t.Run("fewest hops", func(t *testing.T) {
+		solver.UpdateAt("BTCUSDT", fixedpoint.NewFromFloat(20100.0), now.Add(time.Second))
+
+		path, err := solver.ResolvePath("ETH", "TWD", now.Add(time.Second))
+		if assert.NoError(t, err) {
+			assert.Equal(t, "ETH->BTC->USDT->TWD", path.String())
+			assert.Equal(t, "30150", path.Price.String())
+		}
+	})
+
+	t.Run("stale", func(t *testing.T) {
+		later := now.Add(30 * time.Second)
+		solver.UpdateAt("ETHBTC", fixedpoint.NewFromFloat(0.05), later)
+		solver.UpdateAt("BTCUSDT", fixedpoint.NewFromFloat(20100.0), later)
+
+		// USDT/TWD is older than the staleness bound
+		_, err := solver.ResolvePath("ETH", "TWD", now.Add(90*time.Second))
+		assert.True(t, errors.Is(err, ErrStalePrice))
+
+		_, ok := solver.ResolvePrice("ETH", "TWD")
+		assert.False(t, ok)
+
+		// the fresh edges are still usable
+		path, err := solver.ResolvePath("ETH", "USDT", now.Add(80*time.Second))
+		if assert.NoError(t, err) {
+			assert.Equal(t, "ETH->BTC->USDT", path.String())
+		}
+	})
+
+	t.Run("no path", func(t *testing.T) {
+		_, err := solver.ResolvePath("DOGE", "USDT", now)
+		assert.True(t, errors.Is(err, ErrNoPricePath))
+	})
+}
+
+func TestGraphPriceSolver_MaxHops(t *testing.T) {
+	now := time.Now()
+	solver := NewGraphPriceSolver(types.MarketMap{}, 0)
+	solver.MaxHops = 2
+	solver.UpdateRate("A", "B", fixedpoint.NewFromFloat(2.0), now)
+	solver.UpdateRate("B", "C", fixedpoint.NewFromFloat(3.0), now)
+	solver.UpdateRate("C", "D", fixedpoint.NewFromFloat(4.0), now)
+
+	price, ok := solver.ResolvePrice("A", "C")
+	assert.True(t, ok)
+	assert.Equal(t, "6", price.String())
+
+	_, ok = solver.ResolvePrice("A", "D")
+	assert.False(t, ok)
+
+	// out-of-order updates are ignored
+	solver.UpdateRate("A", "B", fixedpoint.NewFromFloat(5.0), now.Add(-time.Second))
+	price, ok = solver.ResolvePrice("A", "B")
+	assert.True(t, ok)
+	assert.Equal(t, "2", price.String())
+}
@@ -0,0 +1,408 @@
+package pricesolver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/c9s/bbgo/pkg/datasource/chainlink"
+	"github.com/c9s/bbgo/pkg/datasource/coingecko"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+var ErrRateLimited = errors.New("price feed is rate limited")
+
+// the index price providers implement the FXRateProvider interface, so that the index prices
+// can be fed into the price solver by the FXRateFeed, e.g. for marking the positions and the account value.
+
+// CoingeckoPriceProvider provides the aggregated prices from CoinGecko
+type CoingeckoPriceProvider struct {
+	client *coingecko.Client
+
+	// ids maps the asset to the CoinGecko API id, e.g. BTC -> bitcoin
+	ids map[string]string
+}
+
+func NewCoingeckoPriceProvider(client *coingecko.Client, ids map[string]string) *CoingeckoPriceProvider {
+	return &CoingeckoPriceProvider{
+		client: client,
+		ids:    ids,
+	}
+}
+
+func (p *CoingeckoPriceProvider) QueryRate(ctx context.Context, base, quote string) (fixedpoint.Value, error) {
+	id, ok := p.ids[base]
+	if !ok {
+		return fixedpoint.Zero, fmt.Errorf("coingecko id of %s is not configured", base)
+	}
+
+	vsCurrency := strings.ToLower(quote)
+	prices, err := p.client.QuerySimplePrice(ctx, []string{id}, []string{vsCurrency})
+	if err != nil {
+		return fixedpoint.Zero, err
+	}
+
+	price, ok := prices[id][vsCurrency]
+	if !ok || price.Sign() <= 0 {
+		return fixedpoint.Zero, fmt.Errorf("coingecko price of %s in %s is not available", id, vsCurrency)
+	}
+
+	return price, nil
+}
+
+// ChainlinkPriceProvider provides the prices from the Chainlink price feeds
+type ChainlinkPriceProvider struct {
+	client *chainlink.Client
+
+	// feeds maps the currency pair to the address of the price feed
+	feeds map[CurrencyPair]string
+
+	// maxAge rejects the answers that are not updated within the max age, zero means no limit
+	maxAge time.Duration
+
+	mu       sync.Mutex
+	decimals map[string]int
+}
+
+func NewChainlinkPriceProvider(client *chainlink.Client, feeds map[CurrencyPair]string, maxAge time.Duration) *ChainlinkPriceProvider {
+	return &ChainlinkPriceProvider{
+		client:   client,
+		feeds:    feeds,
+		maxAge:   maxAge,
+		decimals: make(map[string]int),
+	}
+}
+
+func (p *ChainlinkPriceProvider) QueryRate(ctx context.Context, base, quote string) (fixedpoint.Value, error) {
+	if address, ok := p.feeds[CurrencyPair{Base: base, Quote: quote}]; ok {
+		return p.queryFeed(ctx, address)
+	}
+
+	if address, ok := p.feeds[CurrencyPair{Base: quote, Quote: base}]; ok {
+		price, err := p.queryFeed(ctx, address)
+		if err != nil {
+			return fixedpoint.Zero, err
+		}
+
+		return fixedpoint.One.Div(price), nil
+	}
+
+	return fixedpoint.Zero, fmt.Errorf("chainlink feed of %s/%s is not configured", base, quote)
+}
+
+func (p *ChainlinkPriceProvider) feedDecimals(ctx context.Context, address string) (int, error) {
+	p.mu.Lock()
+	decimals, ok := p.decimals[address]
+	p.mu.Unlock()
+
+	if ok {
+		return decimals, nil
+	}
+
+	decimals, err := p.client.Decimals(ctx, address)
+	if err != nil {
+		return 0, err
+	}
+
+	p.mu.Lock()
+	p.decimals[address] = decimals
+	p.mu.Unlock()
+	return decimals, nil
+}
+
+func (p *ChainlinkPriceProvider) queryFeed(ctx context.Context, address string) (fixedpoint.Value, error) {
+	decimals, err := p.feedDecimals(ctx, address)
+	if err != nil {
+		return fixedpoint.Zero, err
+	}
+
+	round, err := p.client.LatestRoundData(ctx, address)
+	if err != nil {
+		return fixedpoint.Zero, err
+	}
+
+	if p.maxAge > 0 && time.Since(round.UpdatedAt) > p.maxAge {
+		return fixedpoint.Zero, fmt.Errorf("%w: chainlink feed %s is not updated since %s", ErrStalePrice, address, round.UpdatedAt)
+	}
+
+	return scaleAnswer(round.Answer, decimals)
+}
+
+// scaleAnswer converts the integer answer scaled by the decimals into the price
+func scaleAnswer(answer *big.Int, decimals int) (fixedpoint.Value, error) {
+	if answer.Sign() <= 0 {
+		return fixedpoint.Zero, fmt.Errorf("invalid chainlink answer %s", answer)
+	}
+
+	price, _ := new(big.Rat).SetFrac(answer, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)).Float64()
+	return fixedpoint.NewFromFloat(price), nil
+}
+
+// ExchangeIndexPriceProvider provides the mark prices of the perpetual futures markets,
+// the mark price is derived from the index price of the exchange, which is aggregated from the spot venues.
+type ExchangeIndexPriceProvider struct {
+	service types.FundingRateService
+}
+
+func NewExchangeIndexPriceProvider(service types.FundingRateService) *ExchangeIndexPriceProvider {
+	return &ExchangeIndexPriceProvider{service: service}
+}
+
+func (p *ExchangeIndexPriceProvider) QueryRate(ctx context.Context, base, quote string) (fixedpoint.Value, error) {
+	index, err := p.service.QueryPremiumIndex(ctx, base+quote)
+	if err != nil {
+		return fixedpoint.Zero, err
+	}
+
+	if index.MarkPrice.Sign() <= 0 {
+		return fixedpoint.Zero, fmt.Errorf("invalid %s%s mark price: %s", base, quote, index.MarkPrice.String())
+	}
+
+	return index.MarkPrice, nil
+}
+
+// FallbackFXRateProvider queries the providers in order and returns the first available rate
+type FallbackFXRateProvider []FXRateProvider
+
+func (providers FallbackFXRateProvider) QueryRate(ctx context.Context, base, quote string) (fixedpoint.Value, error) {
+	var errs []error
+	for _, provider := range providers {
+		price, err := provider.QueryRate(ctx, base, quote)
+		if err == nil {
+			return price, nil
+		}
+
+		errs = append(errs, err)
+	}
+
+	if len(errs) == 0 {
+		return fixedpoint.Zero, fmt.Errorf("no provider of %s/%s is configured", base, quote)
+	}
+
+	return fixedpoint.Zero, errors.Join(errs...)
+}
+
+type cachedRate struct {
+	price     fixedpoint.Value
+	queryTime time.Time
+}
+
+// CachedFXRateProvider caches the rates of the provider for the ttl, and limits the request rate
+// of the provider, e.g. the public HTTP APIs with the strict rate limits.
+// The cached rate is returned when the request is rate limited, ErrRateLimited is returned if there is no cache.
+type CachedFXRateProvider struct {
+	provider FXRateProvider
+	ttl      time.Duration
+	limiter  *rate.Limiter
+
+	mu    sync.Mutex
+	cache map[CurrencyPair]cachedRate
+
+	// now is used for testing
+	now func() time.Time
+}
+
+// NewCachedFXRateProvider creates the cached provider, the limiter is optional
+func NewCachedFXRateProvider(provider FXRateProvider, ttl time.Duration, limiter *rate.Limiter) *CachedFXRateProvider {
+	return &CachedFXRateProvider{
+		provider: provider,
+		ttl:      ttl,
+		limiter:  limiter,
+		cache:    make(map[CurrencyPair]cachedRate),
+		now:      time.Now,
+	}
+}
+
+func (p *CachedFXRateProvider) QueryRate(ctx context.Context, base, quote string) (fixedpoint.Value, error) {
+	pair := CurrencyPair{Base: base, Quote: quote}
+	now := p.now()
+
+	p.mu.Lock()
+	cached, ok := p.cache[pair]
+	p.mu.Unlock()
+
+	if ok && now.Sub(cached.queryTime) < p.ttl {
+		return cached.price, nil
+	}
+
+	if p.limiter != nil && !p.limiter.AllowN(now, 1) {
+		if ok {
+			return cached.price, nil
+		}
+
+		return fixedpoint.Zero, fmt.Errorf("%w: %s", ErrRateLimited, pair)
+	}
+
+	price, err := p.provider.QueryRate(ctx, base, quote)
+	if err != nil {
+		return fixedpoint.Zero, err
+	}
+
+	p.mu.Lock()
+	p.cache[pair] = cachedRate{price: price, queryTime: now}
+	p.mu.Unlock()
+
+	return price, nil
+}
+
+// CoingeckoFeedConfig is the config of the CoinGecko price feed
+type CoingeckoFeedConfig struct {
+	APIKey string `json:"apiKey,omitempty"`
+	Pro    bool   `json:"pro,omitempty"`
+
+	// IDs maps the asset to the CoinGecko API id, e.g. BTC: bitcoin
+	IDs map[string]string `json:"ids"`
+}
+
+// ChainlinkFeedConfig is the config of the Chainlink price feeds
+type ChainlinkFeedConfig struct {
+	RPCURL string `json:"rpcURL"`
+
+	// Feeds maps the currency pair to the address of the price feed, e.g. BTC/USD: 0xF4030086522a5bEEa4988F8cA5B36dbC97BeE88c
+	Feeds map[string]string `json:"feeds"`
+
+	// MaxAge rejects the answers that are not updated within the max age
+	MaxAge types.Duration `json:"maxAge,omitempty"`
+}
+
+// IndexPriceFeedConfig configures the external index price feeds, the feeds are queried in the order of
+// coingecko, chainlink and the exchange index, and the first available price is used.
+type IndexPriceFeedConfig struct {
+	// Pairs are the currency pairs to update, e.g. BTC/USDT
+	Pairs []string `json:"pairs"`
+
+	// Interval is the update interval of the pairs, defaults to 5m
+	Interval types.Duration `json:"interval,omitempty"`
+
+	// CacheTTL is the ttl of the cached prices of the HTTP feeds, defaults to the interval
+	CacheTTL types.Duration `json:"cacheTTL,omitempty"`
+
+	// RateLimit is the max requests per minute of each HTTP feed, zero means no limit
+	RateLimit int `json:"rateLimit,omitempty"`
+
+	Coingecko *CoingeckoFeedConfig `json:"coingecko,omitempty"`
+	Chainlink *ChainlinkFeedConfig `json:"chainlink,omitempty"`
+
+	// ExchangeIndex is the session name of the futures exchange whose mark prices are used as the index prices
+	ExchangeIndex string `json:"exchangeIndex,omitempty"`
+}
+
+func (c *IndexPriceFeedConfig) CurrencyPairs() ([]CurrencyPair, error) {
+	var pairs []CurrencyPair
+	for _, s := range c.Pairs {
+		pair, err := ParseCurrencyPair(s)
+		if err != nil {
+			return nil, err
+		}
+
+		pairs = append(pairs, pair)
+	}
+
+	return pairs, nil
+}
+
+func (c *IndexPriceFeedConfig) Validate() error {
+	if len(c.Pairs) == 0 {
+		return errors.New("index price feed: pairs are required")
+	}
+
+	if _, err := c.CurrencyPairs(); err != nil {
+		return fmt.Errorf("index price feed: %w", err)
+	}
+
+	if c.Coingecko == nil && c.Chainlink == nil && c.ExchangeIndex == "" {
+		return errors.New("index price feed: at least one of coingecko, chainlink and exchangeIndex is required")
+	}
+
+	if c.Chainlink != nil && c.Chainlink.RPCURL == "" {
+		return errors.New("index price feed: chainlink.rpcURL is required")
+	}
+
+	return nil
+}
+
+func (c *IndexPriceFeedConfig) interval() time.Duration {
+	if c.Interval > 0 {
+		return c.Interval.Duration()
+	}
+
+	return defaultFXRateUpdateInterval
+}
+
+// cached wraps the HTTP feed provider with the cache and the rate limit
+func (c *IndexPriceFeedConfig) cached(provider FXRateProvider) FXRateProvider {
+	ttl := c.CacheTTL.Duration()
+	if ttl <= 0 {
+		ttl = c.interval()
+	}
+
+	var limiter *rate.Limiter
+	if c.RateLimit > 0 {
+		limiter = rate.NewLimiter(rate.Every(time.Minute/time.Duration(c.RateLimit)), 1)
+	}
+
+	return NewCachedFXRateProvider(provider, ttl, limiter)
+}
+
+// NewProvider builds the fallback provider of the configured feeds, the exchange index provider
+// is given by the caller since it's resolved from the exchange sessions.
+func (c *IndexPriceFeedConfig) NewProvider(exchangeIndex FXRateProvider) (FXRateProvider, error) {
+	var providers FallbackFXRateProvider
+
+	if c.Coingecko != nil {
+		client := coingecko.NewClient()
+		if c.Coingecko.APIKey != "" {
+			client.Auth(c.Coingecko.APIKey, c.Coingecko.Pro)
+		}
+
+		providers = append(providers, c.cached(NewCoingeckoPriceProvider(client, c.Coingecko.IDs)))
+	}
+
+	if c.Chainlink != nil {
+		client, err := chainlink.NewClient(c.Chainlink.RPCURL)
+		if err != nil {
+			return nil, err
+		}
+
+		feeds := make(map[CurrencyPair]string, len(c.Chainlink.Feeds))
+		for s, address := range c.Chainlink.Feeds {
+			pair, err := ParseCurrencyPair(s)
+			if err != nil {
+				return nil, err
+			}
+
+			feeds[pair] = address
+		}
+
+		providers = append(providers, c.cached(NewChainlinkPriceProvider(client, feeds, c.Chainlink.MaxAge.Duration())))
+	}
+
+	if exchangeIndex != nil {
+		providers = append(providers, exchangeIndex)
+	}
+
+	return providers, nil
+}
+
+// NewIndexPriceFeed creates the price solver of the index prices and the feed that updates it
+func NewIndexPriceFeed(config *IndexPriceFeedConfig, exchangeIndex FXRateProvider) (*SimplePriceSolver, *FXRateFeed, error) {
+	pairs, err := config.CurrencyPairs()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	provider, err := config.NewProvider(exchangeIndex)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	solver := NewSimplePriceResolver(types.MarketMap{})
+	return solver, NewFXRateFeed(solver, provider, config.interval(), pairs...), nil
+}
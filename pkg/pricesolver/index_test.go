@@ -0,0 +1,94 @@
+package pricesolver
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/time/rate"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+)
+
+type countingFXRateProvider struct {
+	price   fixedpoint.Value
+	err     error
+	queries int
+}
+
+func (p *countingFXRateProvider) QueryRate(ctx context.Context, base, quote string) (fixedpoint.Value, error) {
+	p.queries++
+	return p.price, p.err
+}
+
+func TestCachedFXRateProvider(t *testing.T) {
+	now := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	provider := &countingFXRateProvider{price: fixedpoint.NewFromFloat(100.0)}
+
+	// one request per minute
+	cached := NewCachedFXRateProvider(provider, 10*time.Second, rate.NewLimiter(rate.Every(time.Minute), 1))
+	cached.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	price, err := cached.QueryRate(ctx, "BTC", "USD")
+	assert.NoError(t, err)
+	assert.Equal(t, fixedpoint.NewFromFloat(100.0), price)
+
+	// cached
+	now = now.Add(5 * time.Second)
+	_, _ = cached.QueryRate(ctx, "BTC", "USD")
+	assert.Equal(t, 1, provider.queries)
+
+	// expired but rate limited, the stale cache is returned
+	provider.price = fixedpoint.NewFromFloat(101.0)
+	now = now.Add(10 * time.Second)
+	price, err = cached.QueryRate(ctx, "BTC", "USD")
+	assert.NoError(t, err)
+	assert.Equal(t, fixedpoint.NewFromFloat(100.0), price)
+	assert.Equal(t, 1, provider.queries)
+
+	// rate limited without the cache
+	_, err = cached.QueryRate(ctx, "ETH", "USD")
+	assert.True(t, errors.Is(err, ErrRateLimited))
+
+	now = now.Add(time.Minute)
+	price, err = cached.QueryRate(ctx, "BTC", "USD")
+	assert.NoError(t, err)
+	assert.Equal(t, fixedpoint.NewFromFloat(101.0), price)
+	assert.Equal(t, 2, provider.queries)
+}
+
+func TestFallbackFXRateProvider(t *testing.T) {
+	failed := &countingFXRateProvider{err: errors.New("unavailable")}
+	ok := &countingFXRateProvider{price: fixedpoint.NewFromFloat(30.0)}
+
+	price, err := FallbackFXRateProvider{failed, ok}.QueryRate(context.Background(), "USDT", "TWD")
+	assert.NoError(t, err)
+	assert.Equal(t, fixedpoint.NewFromFloat(30.0), price)
+
+	_, err = FallbackFXRateProvider{failed}.QueryRate(context.Background(), "USDT", "TWD")
+	assert.Error(t, err)
+}
+
+func Test_scaleAnswer(t *testing.T) {
+	price, err := scaleAnswer(big.NewInt(6512345000000), 8)
+	assert.NoError(t, err)
+	assert.Equal(t, "65123.45", price.String())
+
+	_, err = scaleAnswer(big.NewInt(-1), 8)
+	assert.Error(t, err)
+}
+
+func TestIndexPriceFeedConfig_Validate(t *testing.T) {
+	config := &IndexPriceFeedConfig{Pairs: []string{"BTC/USDT"}}
+	assert.Error(t, config.Validate())
+
+	config.Coingecko = &CoingeckoFeedConfig{IDs: map[string]string{"BTC": "bitcoin"}}
+	assert.NoError(t, config.Validate())
+
+	config.Pairs = []string{"BTCUSDT"}
+	assert.Error(t, config.Validate())
+}
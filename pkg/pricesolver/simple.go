@@ -0,0 +1,155 @@
+package pricesolver
+
+import (
+	"context"
+	"sync"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// SimplePriceSolver resolves the price of an asset in a currency from the last prices of the markets,
+// the price is implied through one intermediate currency when there is no direct market,
+// e.g. BTC/USDT can be implied by BTC/USDC and USDC/USDT.
+type SimplePriceSolver struct {
+	mu sync.Mutex
+
+	markets types.MarketMap
+
+	// pricesByBase maps the base currency to the quote currencies and the prices
+	pricesByBase map[string]map[string]fixedpoint.Value
+}
+
+func NewSimplePriceResolver(markets types.MarketMap) *SimplePriceSolver {
+	return &SimplePriceSolver{
+		markets:      markets,
+		pricesByBase: make(map[string]map[string]fixedpoint.Value),
+	}
+}
+
+// Update updates the last price of the market
+func (m *SimplePriceSolver) Update(symbol string, price fixedpoint.Value) {
+	market, ok := m.markets[symbol]
+	if !ok {
+		return
+	}
+
+	m.UpdateRate(market.BaseCurrency, market.QuoteCurrency, price)
+}
+
+// UpdateRate updates the price of the base currency in the quote currency without a market,
+// e.g. the fiat exchange rates from the FXRateFeed
+func (m *SimplePriceSolver) UpdateRate(base, quote string, price fixedpoint.Value) {
+	if price.Sign() <= 0 {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	quotePrices, ok := m.pricesByBase[base]
+	if !ok {
+		quotePrices = make(map[string]fixedpoint.Value)
+		m.pricesByBase[base] = quotePrices
+	}
+
+	quotePrices[quote] = price
+}
+
+// UpdateFromTickers bootstraps the prices of the symbols from the tickers queried by a single QueryTickers request,
+// all the markets of the solver are queried if no symbol is given
+func (m *SimplePriceSolver) UpdateFromTickers(ctx context.Context, service types.ExchangeMarketDataService, symbols ...string) error {
+	if len(symbols) == 0 {
+		for symbol := range m.markets {
+			symbols = append(symbols, symbol)
+		}
+	}
+
+	tickers, err := service.QueryTickers(ctx, symbols...)
+	if err != nil {
+		return err
+	}
+
+	for symbol, ticker := range tickers {
+		if price, err := tickerMidPrice(symbol, ticker); err == nil {
+			m.Update(symbol, price)
+		}
+	}
+
+	return nil
+}
+
+// BindStream updates the prices from the kline updates of the stream
+func (m *SimplePriceSolver) BindStream(stream types.Stream) {
+	stream.OnKLine(func(k types.KLine) {
+		m.Update(k.Symbol, k.Close)
+	})
+
+	stream.OnKLineClosed(func(k types.KLine) {
+		m.Update(k.Symbol, k.Close)
+	})
+}
+
+// directPrice returns the price of the asset in the currency from the direct market or the inverse market
+func (m *SimplePriceSolver) directPrice(asset, currency string) (fixedpoint.Value, bool) {
+	if asset == currency {
+		return fixedpoint.One, true
+	}
+
+	if price, ok := m.pricesByBase[asset][currency]; ok {
+		return price, true
+	}
+
+	if price, ok := m.pricesByBase[currency][asset]; ok {
+		return fixedpoint.One.Div(price), true
+	}
+
+	return fixedpoint.Zero, false
+}
+
+// ResolvePrice resolves the price of the asset in the currency, the direct market is used first,
+// otherwise the price is implied through an intermediate currency, the preferred intermediate currencies are tried first.
+func (m *SimplePriceSolver) ResolvePrice(asset, currency string, prefers ...string) (fixedpoint.Value, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if price, ok := m.directPrice(asset, currency); ok {
+		return price, true
+	}
+
+	for _, intermediate := range prefers {
+		if price, ok := m.impliedPrice(asset, intermediate, currency); ok {
+			return price, true
+		}
+	}
+
+	for intermediate := range m.pricesByBase[asset] {
+		if price, ok := m.impliedPrice(asset, intermediate, currency); ok {
+			return price, true
+		}
+	}
+
+	for base, quotePrices := range m.pricesByBase {
+		if _, ok := quotePrices[asset]; ok {
+			if price, ok := m.impliedPrice(asset, base, currency); ok {
+				return price, true
+			}
+		}
+	}
+
+	return fixedpoint.Zero, false
+}
+
+func (m *SimplePriceSolver) impliedPrice(asset, intermediate, currency string) (fixedpoint.Value, bool) {
+	price1, ok := m.directPrice(asset, intermediate)
+	if !ok {
+		return fixedpoint.Zero, false
+	}
+
+	price2, ok := m.directPrice(intermediate, currency)
+	if !ok {
+		return fixedpoint.Zero, false
+	}
+
+	return price1.Mul(price2), true
+}
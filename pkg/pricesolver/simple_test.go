@@ -0,0 +1,82 @@
+package pricesolver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+	"github.com/c9s/bbgo/pkg/types/mocks"
+)
+
+func TestSimplePriceSolver(t *testing.T) {
+	markets := types.MarketMap{
+		"BTCUSDC":  {Symbol: "BTCUSDC", BaseCurrency: "BTC", QuoteCurrency: "USDC"},
+		"USDCUSDT": {Symbol: "USDCUSDT", BaseCurrency: "USDC", QuoteCurrency: "USDT"},
+		"ETHBTC":   {Symbol: "ETHBTC", BaseCurrency: "ETH", QuoteCurrency: "BTC"},
+	}
+
+	solver := NewSimplePriceResolver(markets)
+	solver.Update("BTCUSDC", fixedpoint.NewFromFloat(20000.0))
+	solver.Update("USDCUSDT", fixedpoint.NewFromFloat(0.999))
+	solver.Update("ETHBTC", fixedpoint.NewFromFloat(0.05))
+
+	t.Run("direct", func(t *testing.T) {
+		price, ok := solver.ResolvePrice("BTC", "USDC")
+		assert.True(t, ok)
+		assert.Equal(t, "20000", price.String())
+	})
+
+	t.Run("inverse", func(t *testing.T) {
+		price, ok := solver.ResolvePrice("USDC", "BTC")
+		assert.True(t, ok)
+		assert.Equal(t, "0.00005", price.String())
+	})
+
+	t.Run("implied", func(t *testing.T) {
+		price, ok := solver.ResolvePrice("BTC", "USDT", "USDC")
+		assert.True(t, ok)
+		assert.Equal(t, "19980", price.String())
+	})
+
+	t.Run("implied by the quote of the asset", func(t *testing.T) {
+		price, ok := solver.ResolvePrice("ETH", "USDC")
+		assert.True(t, ok)
+		assert.Equal(t, "1000", price.String())
+	})
+
+	t.Run("unresolved", func(t *testing.T) {
+		_, ok := solver.ResolvePrice("BNB", "USDT")
+		assert.False(t, ok)
+	})
+}
+
+func TestSimplePriceSolver_UpdateFromTickers(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	markets := types.MarketMap{
+		"BTCUSDT": {Symbol: "BTCUSDT", BaseCurrency: "BTC", QuoteCurrency: "USDT"},
+		"ETHBTC":  {Symbol: "ETHBTC", BaseCurrency: "ETH", QuoteCurrency: "BTC"},
+	}
+
+	ex := mocks.NewMockExchangePublic(mockCtrl)
+	ex.EXPECT().QueryTickers(gomock.Any(), "BTCUSDT", "ETHBTC").Return(map[string]types.Ticker{
+		"BTCUSDT": {Buy: fixedpoint.NewFromFloat(19999.0), Sell: fixedpoint.NewFromFloat(20001.0)},
+		"ETHBTC":  {Last: fixedpoint.NewFromFloat(0.05)},
+	}, nil).Times(1)
+
+	solver := NewSimplePriceResolver(markets)
+	assert.NoError(t, solver.UpdateFromTickers(context.Background(), ex, "BTCUSDT", "ETHBTC"))
+
+	price, ok := solver.ResolvePrice("BTC", "USDT")
+	assert.True(t, ok)
+	assert.Equal(t, "20000", price.String())
+
+	price, ok = solver.ResolvePrice("ETH", "USDT", "BTC")
+	assert.True(t, ok)
+	assert.Equal(t, "1000", price.String())
+}
@@ -0,0 +1,166 @@
+package quoting
+
+import (
+	"sort"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// Amendment is an active order that should be updated to the desired price and quantity
+type Amendment struct {
+	Order  types.Order
+	Submit types.SubmitOrder
+}
+
+// Diff is the result of comparing the desired layers with the active maker orders
+type Diff struct {
+	// Keep is the active orders that are still close to the desired layers
+	Keep []types.Order
+
+	// Amend is the active orders that moved beyond the tolerance
+	Amend []Amendment
+
+	// Cancel is the active orders that have no desired layer to match
+	Cancel []types.Order
+
+	// Submit is the desired layers that have no active order to match
+	Submit []types.SubmitOrder
+}
+
+// LockedFunds calculates the base and quote funds locked by the given open orders
+func LockedFunds(orders []types.Order) (base, quote fixedpoint.Value) {
+	for _, o := range orders {
+		remaining := o.Quantity.Sub(o.ExecutedQuantity)
+		switch o.Side {
+		case types.SideTypeBuy:
+			quote = quote.Add(remaining.Mul(o.Price))
+		case types.SideTypeSell:
+			base = base.Add(remaining)
+		}
+	}
+
+	return base, quote
+}
+
+// WithinTolerance checks if the ratio of the change from a to b is within the tolerance
+func WithinTolerance(a, b, tolerance fixedpoint.Value) bool {
+	if a.Compare(b) == 0 {
+		return true
+	}
+
+	if b.IsZero() {
+		return false
+	}
+
+	return a.Sub(b).Abs().Div(b).Compare(tolerance) <= 0
+}
+
+// DiffOrders compares the active orders with the desired orders layer by layer,
+// the orders of each side are sorted from the touch layer to the deepest layer and matched by the layer index.
+func DiffOrders(activeOrders []types.Order, desiredOrders []types.SubmitOrder, priceTolerance, quantityTolerance fixedpoint.Value) (diff Diff) {
+	for _, side := range []types.SideType{types.SideTypeBuy, types.SideTypeSell} {
+		var orders []types.Order
+		for _, o := range activeOrders {
+			if o.Side == side {
+				orders = append(orders, o)
+			}
+		}
+
+		var desired []types.SubmitOrder
+		for _, o := range desiredOrders {
+			if o.Side == side {
+				desired = append(desired, o)
+			}
+		}
+
+		descending := side == types.SideTypeBuy
+		sort.Slice(orders, func(i, j int) bool {
+			return (orders[i].Price.Compare(orders[j].Price) > 0) == descending
+		})
+		sort.Slice(desired, func(i, j int) bool {
+			return (desired[i].Price.Compare(desired[j].Price) > 0) == descending
+		})
+
+		for i := 0; i < len(orders) || i < len(desired); i++ {
+			switch {
+			case i >= len(desired):
+				diff.Cancel = append(diff.Cancel, orders[i])
+
+			case i >= len(orders):
+				diff.Submit = append(diff.Submit, desired[i])
+
+			default:
+				remaining := orders[i].Quantity.Sub(orders[i].ExecutedQuantity)
+				if WithinTolerance(orders[i].Price, desired[i].Price, priceTolerance) &&
+					WithinTolerance(remaining, desired[i].Quantity, quantityTolerance) {
+					diff.Keep = append(diff.Keep, orders[i])
+				} else {
+					diff.Amend = append(diff.Amend, Amendment{Order: orders[i], Submit: desired[i]})
+				}
+			}
+		}
+	}
+
+	return diff
+}
+
+// SortByLayer sorts the items of both sides by the layer, the touch layer is the layer closest to the spread.
+// The items of the same layer are interleaved, bid first.
+func SortByLayer[T any](items []T, layerKey func(T) (types.SideType, fixedpoint.Value), touchFirst bool) []T {
+	var bids, asks []T
+	for _, item := range items {
+		if side, _ := layerKey(item); side == types.SideTypeBuy {
+			bids = append(bids, item)
+		} else {
+			asks = append(asks, item)
+		}
+	}
+
+	price := func(item T) fixedpoint.Value {
+		_, p := layerKey(item)
+		return p
+	}
+
+	// touch layer first
+	sort.SliceStable(bids, func(i, j int) bool { return price(bids[i]).Compare(price(bids[j])) > 0 })
+	sort.SliceStable(asks, func(i, j int) bool { return price(asks[i]).Compare(price(asks[j])) < 0 })
+
+	numLayers := len(bids)
+	if len(asks) > numLayers {
+		numLayers = len(asks)
+	}
+
+	sorted := make([]T, 0, len(items))
+	for i := 0; i < numLayers; i++ {
+		layer := i
+		if !touchFirst {
+			layer = numLayers - 1 - i
+		}
+
+		if layer < len(bids) {
+			sorted = append(sorted, bids[layer])
+		}
+
+		if layer < len(asks) {
+			sorted = append(sorted, asks[layer])
+		}
+	}
+
+	return sorted
+}
+
+// OrderLayerKey is the layer key of the active orders for SortByLayer
+func OrderLayerKey(o types.Order) (types.SideType, fixedpoint.Value) {
+	return o.Side, o.Price
+}
+
+// SubmitOrderLayerKey is the layer key of the submit orders for SortByLayer
+func SubmitOrderLayerKey(o types.SubmitOrder) (types.SideType, fixedpoint.Value) {
+	return o.Side, o.Price
+}
+
+// AmendmentLayerKey is the layer key of the amendments for SortByLayer, the amendments are sorted by the active order
+func AmendmentLayerKey(a Amendment) (types.SideType, fixedpoint.Value) {
+	return a.Order.Side, a.Order.Price
+}
@@ -0,0 +1,100 @@
+package quoting
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func newTestOrder(orderID uint64, side types.SideType, price, quantity float64) types.Order {
+	return types.Order{
+		SubmitOrder: types.SubmitOrder{
+			Symbol:   "BTCUSDT",
+			Side:     side,
+			Type:     types.OrderTypeLimit,
+			Price:    fixedpoint.NewFromFloat(price),
+			Quantity: fixedpoint.NewFromFloat(quantity),
+		},
+		OrderID: orderID,
+		Status:  types.OrderStatusNew,
+	}
+}
+
+func newTestSubmitOrder(side types.SideType, price, quantity float64) types.SubmitOrder {
+	return types.SubmitOrder{
+		Symbol:   "BTCUSDT",
+		Side:     side,
+		Type:     types.OrderTypeLimit,
+		Price:    fixedpoint.NewFromFloat(price),
+		Quantity: fixedpoint.NewFromFloat(quantity),
+	}
+}
+
+func TestDiffOrders(t *testing.T) {
+	activeOrders := []types.Order{
+		newTestOrder(1, types.SideTypeBuy, 99.0, 1.0),
+		newTestOrder(2, types.SideTypeBuy, 98.0, 1.0),
+		newTestOrder(3, types.SideTypeSell, 101.0, 1.0),
+		newTestOrder(4, types.SideTypeSell, 102.0, 1.0),
+	}
+
+	desiredOrders := []types.SubmitOrder{
+		newTestSubmitOrder(types.SideTypeBuy, 99.01, 1.0),
+		newTestSubmitOrder(types.SideTypeBuy, 97.0, 1.0),
+		newTestSubmitOrder(types.SideTypeSell, 101.0, 1.0),
+	}
+
+	diff := DiffOrders(activeOrders, desiredOrders, fixedpoint.NewFromFloat(0.001), fixedpoint.Zero)
+
+	if assert.Len(t, diff.Keep, 2) {
+		assert.Equal(t, uint64(1), diff.Keep[0].OrderID)
+		assert.Equal(t, uint64(3), diff.Keep[1].OrderID)
+	}
+
+	if assert.Len(t, diff.Amend, 1) {
+		assert.Equal(t, uint64(2), diff.Amend[0].Order.OrderID)
+		assert.Equal(t, fixedpoint.NewFromFloat(97.0), diff.Amend[0].Submit.Price)
+	}
+
+	if assert.Len(t, diff.Cancel, 1) {
+		assert.Equal(t, uint64(4), diff.Cancel[0].OrderID)
+	}
+
+	assert.Empty(t, diff.Submit)
+}
+
+func TestLockedFunds(t *testing.T) {
+	bid := newTestOrder(1, types.SideTypeBuy, 100.0, 2.0)
+	bid.ExecutedQuantity = fixedpoint.NewFromFloat(0.5)
+
+	base, quote := LockedFunds([]types.Order{
+		bid,
+		newTestOrder(2, types.SideTypeSell, 101.0, 1.0),
+	})
+
+	assert.Equal(t, fixedpoint.NewFromFloat(1.0), base)
+	assert.Equal(t, fixedpoint.NewFromFloat(150.0), quote)
+}
+
+func TestSortByLayer(t *testing.T) {
+	orders := []types.SubmitOrder{
+		newTestSubmitOrder(types.SideTypeBuy, 97.0, 1.0),
+		newTestSubmitOrder(types.SideTypeSell, 101.0, 1.0),
+		newTestSubmitOrder(types.SideTypeBuy, 99.0, 1.0),
+		newTestSubmitOrder(types.SideTypeBuy, 98.0, 1.0),
+		newTestSubmitOrder(types.SideTypeSell, 102.0, 1.0),
+	}
+
+	prices := func(orders []types.SubmitOrder) (prices []float64) {
+		for _, o := range orders {
+			prices = append(prices, o.Price.Float64())
+		}
+		return prices
+	}
+
+	assert.Equal(t, []float64{99.0, 101.0, 98.0, 102.0, 97.0}, prices(SortByLayer(orders, SubmitOrderLayerKey, true)))
+	assert.Equal(t, []float64{97.0, 98.0, 102.0, 99.0, 101.0}, prices(SortByLayer(orders, SubmitOrderLayerKey, false)))
+}
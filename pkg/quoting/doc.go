@@ -0,0 +1,4 @@
+// Package quoting provides the building blocks of the spread quoting strategies: the layer price and quantity
+// generation, the quota locking of the maker and the hedge balances, and the diff-based order syncing
+// that keeps the active maker orders close to the desired layers.
+package quoting
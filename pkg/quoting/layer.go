@@ -0,0 +1,95 @@
+package quoting
+
+import (
+	"github.com/c9s/bbgo/pkg/bbgo"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// AggregatePrice returns the average price of taking the required quantity from the price volumes,
+// the price volumes should be sorted from the best price. When the book is not deep enough,
+// the average price of the whole book is returned.
+func AggregatePrice(pvs types.PriceVolumeSlice, requiredQuantity fixedpoint.Value) fixedpoint.Value {
+	if len(pvs) == 0 {
+		return fixedpoint.Zero
+	}
+
+	if pvs[0].Volume.Compare(requiredQuantity) >= 0 {
+		return pvs[0].Price
+	}
+
+	remaining := requiredQuantity
+	totalAmount := fixedpoint.Zero
+	for _, pv := range pvs {
+		if pv.Volume.Compare(remaining) >= 0 {
+			totalAmount = totalAmount.Add(remaining.Mul(pv.Price))
+			remaining = fixedpoint.Zero
+			break
+		}
+
+		remaining = remaining.Sub(pv.Volume)
+		totalAmount = totalAmount.Add(pv.Volume.Mul(pv.Price))
+	}
+
+	return totalAmount.Div(requiredQuantity.Sub(remaining))
+}
+
+// ApplyMargin moves the price away from the spread by the margin ratio,
+// the bid price is lowered and the ask price is raised.
+func ApplyMargin(side types.SideType, price, margin fixedpoint.Value) fixedpoint.Value {
+	switch side {
+	case types.SideTypeBuy:
+		return price.Mul(fixedpoint.One.Sub(margin))
+	case types.SideTypeSell:
+		return price.Mul(fixedpoint.One.Add(margin))
+	}
+
+	return price
+}
+
+// ApplyPips moves the price of the layer away from the spread by the pips of ticks per layer,
+// the layer index starts from 0, the touch layer is not moved.
+func ApplyPips(side types.SideType, price, pips, tickSize fixedpoint.Value, layer int) fixedpoint.Value {
+	if layer <= 0 || pips.Sign() <= 0 {
+		return price
+	}
+
+	offset := pips.Mul(fixedpoint.NewFromInt(int64(layer))).Mul(tickSize)
+	switch side {
+	case types.SideTypeBuy:
+		return price.Sub(offset)
+	case types.SideTypeSell:
+		return price.Add(offset)
+	}
+
+	return price
+}
+
+// LayerQuantity generates the quantity of each layer, the layer scale overrides the quantity when it's set,
+// otherwise the quantity is multiplied by the multiplier layer by layer.
+type LayerQuantity struct {
+	Quantity   fixedpoint.Value
+	Multiplier fixedpoint.Value
+	Scale      *bbgo.LayerScale
+}
+
+// At returns the quantity of the layer, the layer index starts from 0
+func (q LayerQuantity) At(layer int) (fixedpoint.Value, error) {
+	if q.Scale != nil {
+		qf, err := q.Scale.Scale(layer + 1)
+		if err != nil {
+			return fixedpoint.Zero, err
+		}
+
+		return fixedpoint.NewFromFloat(qf), nil
+	}
+
+	quantity := q.Quantity
+	if q.Multiplier.Sign() > 0 {
+		for i := 0; i < layer; i++ {
+			quantity = quantity.Mul(q.Multiplier)
+		}
+	}
+
+	return quantity, nil
+}
@@ -0,0 +1,67 @@
+package quoting
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/bbgo"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestAggregatePrice(t *testing.T) {
+	bids := types.PriceVolumeSlice{
+		{Price: fixedpoint.NewFromFloat(1000.0), Volume: fixedpoint.NewFromFloat(1.0)},
+		{Price: fixedpoint.NewFromFloat(1200.0), Volume: fixedpoint.NewFromFloat(1.0)},
+		{Price: fixedpoint.NewFromFloat(1400.0), Volume: fixedpoint.NewFromFloat(1.0)},
+	}
+
+	assert.Equal(t, fixedpoint.NewFromFloat(1000.0), AggregatePrice(bids, fixedpoint.NewFromFloat(0.5)))
+	assert.Equal(t, fixedpoint.NewFromFloat(1000.0), AggregatePrice(bids, fixedpoint.NewFromInt(1)))
+	assert.Equal(t, fixedpoint.NewFromFloat(1100.0), AggregatePrice(bids, fixedpoint.NewFromInt(2)))
+	assert.Equal(t, fixedpoint.NewFromFloat(1160.0), AggregatePrice(bids, fixedpoint.NewFromFloat(2.5)))
+
+	// the book is not deep enough
+	assert.Equal(t, fixedpoint.NewFromFloat(1200.0), AggregatePrice(bids, fixedpoint.NewFromInt(5)))
+	assert.Equal(t, fixedpoint.Zero, AggregatePrice(nil, fixedpoint.One))
+}
+
+func TestApplyMarginAndPips(t *testing.T) {
+	price := fixedpoint.NewFromFloat(100.0)
+	margin := fixedpoint.NewFromFloat(0.01)
+	assert.Equal(t, fixedpoint.NewFromFloat(99.0), ApplyMargin(types.SideTypeBuy, price, margin))
+	assert.Equal(t, fixedpoint.NewFromFloat(101.0), ApplyMargin(types.SideTypeSell, price, margin))
+
+	pips := fixedpoint.NewFromFloat(5.0)
+	tick := fixedpoint.NewFromFloat(0.01)
+	assert.Equal(t, price, ApplyPips(types.SideTypeBuy, price, pips, tick, 0))
+	assert.Equal(t, fixedpoint.NewFromFloat(99.9), ApplyPips(types.SideTypeBuy, price, pips, tick, 2))
+	assert.Equal(t, fixedpoint.NewFromFloat(100.1), ApplyPips(types.SideTypeSell, price, pips, tick, 2))
+}
+
+func TestLayerQuantity(t *testing.T) {
+	q := LayerQuantity{Quantity: fixedpoint.NewFromFloat(1.0), Multiplier: fixedpoint.NewFromFloat(2.0)}
+	for layer, expected := range []float64{1.0, 2.0, 4.0} {
+		quantity, err := q.At(layer)
+		assert.NoError(t, err)
+		assert.Equal(t, fixedpoint.NewFromFloat(expected), quantity)
+	}
+
+	q = LayerQuantity{Quantity: fixedpoint.NewFromFloat(1.0)}
+	quantity, err := q.At(3)
+	assert.NoError(t, err)
+	assert.Equal(t, fixedpoint.NewFromFloat(1.0), quantity)
+
+	q = LayerQuantity{
+		Quantity: fixedpoint.NewFromFloat(1.0),
+		Scale: &bbgo.LayerScale{
+			LayerRule: &bbgo.SlideRule{
+				LinearScale: &bbgo.LinearScale{Domain: [2]float64{1, 3}, Range: [2]float64{1, 3}},
+			},
+		},
+	}
+	quantity, err = q.At(1)
+	assert.NoError(t, err)
+	assert.Equal(t, fixedpoint.NewFromFloat(2.0), quantity)
+}
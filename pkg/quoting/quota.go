@@ -0,0 +1,58 @@
+package quoting
+
+import (
+	"github.com/c9s/bbgo/pkg/bbgo"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// QuotaLocker locks the maker balance of each maker order together with the hedge balance
+// of the opposite side, e.g. a maker bid locks the maker quote asset and the hedge base asset to sell.
+type QuotaLocker struct {
+	Maker *bbgo.QuotaTransaction
+
+	// Hedge is optional, the hedge balance is not checked when it's nil
+	Hedge *bbgo.QuotaTransaction
+}
+
+func NewQuotaLocker(maker, hedge *bbgo.QuotaTransaction) *QuotaLocker {
+	return &QuotaLocker{
+		Maker: maker,
+		Hedge: hedge,
+	}
+}
+
+// Lock locks the quota of the maker order, nothing is locked when any of the quota is not enough
+func (l *QuotaLocker) Lock(side types.SideType, price, quantity fixedpoint.Value) bool {
+	amount := quantity.Mul(price)
+
+	var ok bool
+	switch side {
+	case types.SideTypeBuy:
+		ok = l.Maker.QuoteAsset.Lock(amount) && (l.Hedge == nil || l.Hedge.BaseAsset.Lock(quantity))
+	case types.SideTypeSell:
+		ok = l.Maker.BaseAsset.Lock(quantity) && (l.Hedge == nil || l.Hedge.QuoteAsset.Lock(amount))
+	}
+
+	if !ok {
+		l.Rollback()
+	}
+
+	return ok
+}
+
+// Commit commits the locked quota of the placed order
+func (l *QuotaLocker) Commit() {
+	l.Maker.Commit()
+	if l.Hedge != nil {
+		l.Hedge.Commit()
+	}
+}
+
+// Rollback releases the locked quota of the order that is not placed
+func (l *QuotaLocker) Rollback() {
+	l.Maker.Rollback()
+	if l.Hedge != nil {
+		l.Hedge.Rollback()
+	}
+}
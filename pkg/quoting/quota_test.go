@@ -0,0 +1,44 @@
+package quoting
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/bbgo"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestQuotaLocker(t *testing.T) {
+	maker := &bbgo.QuotaTransaction{}
+	maker.BaseAsset.Add(fixedpoint.NewFromFloat(1.0))
+	maker.QuoteAsset.Add(fixedpoint.NewFromFloat(1000.0))
+
+	hedge := &bbgo.QuotaTransaction{}
+	hedge.BaseAsset.Add(fixedpoint.NewFromFloat(5.0))
+	hedge.QuoteAsset.Add(fixedpoint.NewFromFloat(100.0))
+
+	locker := NewQuotaLocker(maker, hedge)
+	price := fixedpoint.NewFromFloat(100.0)
+
+	// the maker bid locks the maker quote and the hedge base
+	assert.True(t, locker.Lock(types.SideTypeBuy, price, fixedpoint.NewFromFloat(2.0)))
+	locker.Commit()
+	assert.Equal(t, fixedpoint.NewFromFloat(800.0), maker.QuoteAsset.Available)
+	assert.Equal(t, fixedpoint.NewFromFloat(3.0), hedge.BaseAsset.Available)
+
+	// the hedge quote is not enough for the maker ask, the maker base lock is rolled back
+	assert.False(t, locker.Lock(types.SideTypeSell, fixedpoint.NewFromFloat(101.0), fixedpoint.NewFromFloat(1.0)))
+	assert.Equal(t, fixedpoint.NewFromFloat(1.0), maker.BaseAsset.Available)
+
+	assert.True(t, locker.Lock(types.SideTypeSell, price, fixedpoint.NewFromFloat(1.0)))
+	locker.Commit()
+	assert.Equal(t, fixedpoint.Zero, maker.BaseAsset.Available)
+	assert.Equal(t, fixedpoint.Zero, hedge.QuoteAsset.Available)
+
+	// without the hedge quota
+	locker = NewQuotaLocker(maker, nil)
+	assert.True(t, locker.Lock(types.SideTypeBuy, price, fixedpoint.NewFromFloat(8.0)))
+	assert.False(t, locker.Lock(types.SideTypeBuy, price, fixedpoint.NewFromFloat(0.1)))
+}
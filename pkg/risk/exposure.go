@@ -0,0 +1,188 @@
+package risk
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+var ErrExposureLimitExceeded = errors.New("portfolio exposure limit exceeded")
+
+// ExposureLimitConfig is the global exposure limits across all the running strategies
+type ExposureLimitConfig struct {
+	// MaxExposureInUSD is the max gross exposure of all the assets valued in USD
+	MaxExposureInUSD fixedpoint.Value `json:"maxExposureInUSD,omitempty" yaml:"maxExposureInUSD,omitempty"`
+
+	// MaxExposures is the max absolute net exposure of each asset, in the asset unit
+	MaxExposures map[string]fixedpoint.Value `json:"maxExposures,omitempty" yaml:"maxExposures,omitempty"`
+}
+
+func (c *ExposureLimitConfig) Validate() error {
+	if c.MaxExposureInUSD.Sign() < 0 {
+		return fmt.Errorf("portfolio risk: maxExposureInUSD should not be negative, got %v", c.MaxExposureInUSD)
+	}
+
+	for currency, limit := range c.MaxExposures {
+		if limit.Sign() <= 0 {
+			return fmt.Errorf("portfolio risk: max exposure of %s should be positive, got %v", currency, limit)
+		}
+	}
+
+	return nil
+}
+
+// PositionsFunc returns the positions of the running strategies
+type PositionsFunc func() []*types.Position
+
+// PriceInUSDFunc returns the USD price of the given currency
+type PriceInUSDFunc func(currency string) (fixedpoint.Value, bool)
+
+// AssetExposure is the net exposure of one asset aggregated from the strategy positions
+type AssetExposure struct {
+	Currency string           `json:"currency"`
+	Net      fixedpoint.Value `json:"net"`
+
+	// InUSD is zero when the USD price of the asset is not available
+	InUSD fixedpoint.Value `json:"inUSD"`
+}
+
+// Exposure is the aggregated exposure of the strategy positions
+type Exposure struct {
+	Assets map[string]*AssetExposure `json:"assets"`
+
+	// TotalInUSD is the gross exposure, the sum of the absolute USD exposure of the assets
+	TotalInUSD fixedpoint.Value `json:"totalInUSD"`
+}
+
+// PortfolioRiskManager aggregates the positions of all the running strategies into the net exposure per asset,
+// and vetoes the orders that increase the exposure over the global limits.
+//
+// Only the base assets are counted as the exposure, the quote side of the positions is treated as cash.
+type PortfolioRiskManager struct {
+	config     *ExposureLimitConfig
+	positions  PositionsFunc
+	priceInUSD PriceInUSDFunc
+}
+
+func NewPortfolioRiskManager(config *ExposureLimitConfig, positions PositionsFunc, priceInUSD PriceInUSDFunc) *PortfolioRiskManager {
+	return &PortfolioRiskManager{
+		config:     config,
+		positions:  positions,
+		priceInUSD: priceInUSD,
+	}
+}
+
+func (m *PortfolioRiskManager) price(currency string) (fixedpoint.Value, bool) {
+	if types.IsUSDFiatCurrency(currency) {
+		return fixedpoint.One, true
+	}
+
+	if m.priceInUSD == nil {
+		return fixedpoint.Zero, false
+	}
+
+	return m.priceInUSD(currency)
+}
+
+// Exposure aggregates the current positions of the strategies
+func (m *PortfolioRiskManager) Exposure() *Exposure {
+	exposure := &Exposure{
+		Assets: make(map[string]*AssetExposure),
+	}
+
+	for _, position := range m.positions() {
+		if position == nil || position.BaseCurrency == "" {
+			continue
+		}
+
+		base := position.GetBase()
+		if base.IsZero() {
+			continue
+		}
+
+		asset, ok := exposure.Assets[position.BaseCurrency]
+		if !ok {
+			asset = &AssetExposure{Currency: position.BaseCurrency}
+			exposure.Assets[position.BaseCurrency] = asset
+		}
+
+		asset.Net = asset.Net.Add(base)
+	}
+
+	for currency, asset := range exposure.Assets {
+		if price, ok := m.price(currency); ok {
+			asset.InUSD = asset.Net.Mul(price)
+			exposure.TotalInUSD = exposure.TotalInUSD.Add(asset.InUSD.Abs())
+		}
+	}
+
+	return exposure
+}
+
+// CheckOrders returns ErrExposureLimitExceeded if the orders, once filled, increase the exposure over the limits.
+// The orders that reduce the exposure are always allowed.
+// The buy orders and the sell orders are checked separately since either side can be filled on its own,
+// e.g. the symmetric bid/ask orders of a market maker don't net out, the worst side is checked against the limits.
+func (m *PortfolioRiskManager) CheckOrders(orders ...types.SubmitOrder) error {
+	buys := make(map[string]fixedpoint.Value)
+	sells := make(map[string]fixedpoint.Value)
+	for _, order := range orders {
+		currency := order.Market.BaseCurrency
+		if currency == "" {
+			continue
+		}
+
+		switch order.Side {
+		case types.SideTypeBuy:
+			buys[currency] = buys[currency].Add(order.Quantity)
+		case types.SideTypeSell:
+			sells[currency] = sells[currency].Add(order.Quantity)
+		}
+	}
+
+	currencies := make(map[string]struct{})
+	for currency := range buys {
+		currencies[currency] = struct{}{}
+	}
+	for currency := range sells {
+		currencies[currency] = struct{}{}
+	}
+
+	if len(currencies) == 0 {
+		return nil
+	}
+
+	exposure := m.Exposure()
+	totalInUSD := exposure.TotalInUSD
+	newTotalInUSD := totalInUSD
+	for currency := range currencies {
+		var net fixedpoint.Value
+		if asset, ok := exposure.Assets[currency]; ok {
+			net = asset.Net
+		}
+
+		// the worst case is the side that leaves the larger exposure when it's filled alone
+		after := net.Add(buys[currency])
+		if afterSell := net.Sub(sells[currency]); afterSell.Abs().Compare(after.Abs()) > 0 {
+			after = afterSell
+		}
+
+		increasing := after.Abs().Compare(net.Abs()) > 0
+		if limit, ok := m.config.MaxExposures[currency]; ok && increasing && after.Abs().Compare(limit) > 0 {
+			return fmt.Errorf("%w: %s exposure %v would exceed the limit %v", ErrExposureLimitExceeded, currency, after, limit)
+		}
+
+		if price, ok := m.price(currency); ok {
+			newTotalInUSD = newTotalInUSD.Sub(net.Mul(price).Abs()).Add(after.Mul(price).Abs())
+		}
+	}
+
+	maxInUSD := m.config.MaxExposureInUSD
+	if maxInUSD.Sign() > 0 && newTotalInUSD.Compare(totalInUSD) > 0 && newTotalInUSD.Compare(maxInUSD) > 0 {
+		return fmt.Errorf("%w: exposure %v USD would exceed the limit %v USD", ErrExposureLimitExceeded, newTotalInUSD, maxInUSD)
+	}
+
+	return nil
+}
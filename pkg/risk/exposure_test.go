@@ -0,0 +1,83 @@
+package risk
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestPortfolioRiskManager(t *testing.T) {
+	number := fixedpoint.MustNewFromString
+
+	btcusdt := types.Market{Symbol: "BTCUSDT", BaseCurrency: "BTC", QuoteCurrency: "USDT"}
+	btcfdusd := types.Market{Symbol: "BTCFDUSD", BaseCurrency: "BTC", QuoteCurrency: "FDUSD"}
+	ethusdt := types.Market{Symbol: "ETHUSDT", BaseCurrency: "ETH", QuoteCurrency: "USDT"}
+
+	pos1 := types.NewPositionFromMarket(btcusdt)
+	pos1.Base = number("0.3")
+	pos2 := types.NewPositionFromMarket(btcfdusd)
+	pos2.Base = number("0.1")
+	pos3 := types.NewPositionFromMarket(ethusdt)
+	pos3.Base = number("-2")
+
+	prices := map[string]fixedpoint.Value{"BTC": number("50000"), "ETH": number("2500")}
+	manager := NewPortfolioRiskManager(&ExposureLimitConfig{
+		MaxExposureInUSD: number("30000"),
+		MaxExposures:     map[string]fixedpoint.Value{"BTC": number("0.5")},
+	}, func() []*types.Position {
+		return []*types.Position{pos1, pos2, pos3}
+	}, func(currency string) (fixedpoint.Value, bool) {
+		price, ok := prices[currency]
+		return price, ok
+	})
+
+	exposure := manager.Exposure()
+	assert.Equal(t, "0.4", exposure.Assets["BTC"].Net.String())
+	assert.Equal(t, "20000", exposure.Assets["BTC"].InUSD.String())
+	assert.Equal(t, "-5000", exposure.Assets["ETH"].InUSD.String())
+	assert.Equal(t, "25000", exposure.TotalInUSD.String())
+
+	// over the BTC limit
+	err := manager.CheckOrders(types.SubmitOrder{Market: btcusdt, Side: types.SideTypeBuy, Quantity: number("0.2")})
+	assert.True(t, errors.Is(err, ErrExposureLimitExceeded))
+
+	// within the BTC limit and the USD limit
+	assert.NoError(t, manager.CheckOrders(types.SubmitOrder{Market: btcusdt, Side: types.SideTypeBuy, Quantity: number("0.05")}))
+
+	// over the USD limit
+	err = manager.CheckOrders(types.SubmitOrder{Market: ethusdt, Side: types.SideTypeSell, Quantity: number("3")})
+	assert.True(t, errors.Is(err, ErrExposureLimitExceeded))
+
+	// reducing the exposure is always allowed
+	assert.NoError(t, manager.CheckOrders(types.SubmitOrder{Market: ethusdt, Side: types.SideTypeBuy, Quantity: number("1")}))
+
+	// the BTC sell doesn't net against the BTC buy in the same batch, the buy side can be filled alone
+	err = manager.CheckOrders(
+		types.SubmitOrder{Market: btcusdt, Side: types.SideTypeBuy, Quantity: number("0.2")},
+		types.SubmitOrder{Market: btcfdusd, Side: types.SideTypeSell, Quantity: number("0.2")},
+	)
+	assert.True(t, errors.Is(err, ErrExposureLimitExceeded))
+
+	// the two-sided quotes within the limits on both sides
+	assert.NoError(t, manager.CheckOrders(
+		types.SubmitOrder{Market: btcusdt, Side: types.SideTypeBuy, Quantity: number("0.05")},
+		types.SubmitOrder{Market: btcusdt, Side: types.SideTypeSell, Quantity: number("0.05")},
+	))
+
+	// the sell side over the limit is vetoed, even though the buy side reduces the exposure
+	err = manager.CheckOrders(
+		types.SubmitOrder{Market: ethusdt, Side: types.SideTypeBuy, Quantity: number("1")},
+		types.SubmitOrder{Market: ethusdt, Side: types.SideTypeSell, Quantity: number("3")},
+	)
+	assert.True(t, errors.Is(err, ErrExposureLimitExceeded))
+}
+
+func TestExposureLimitConfig_Validate(t *testing.T) {
+	assert.NoError(t, (&ExposureLimitConfig{}).Validate())
+	assert.Error(t, (&ExposureLimitConfig{MaxExposureInUSD: fixedpoint.NewFromInt(-1)}).Validate())
+	assert.Error(t, (&ExposureLimitConfig{MaxExposures: map[string]fixedpoint.Value{"BTC": fixedpoint.Zero}}).Validate())
+}
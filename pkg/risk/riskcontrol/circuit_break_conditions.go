@@ -0,0 +1,240 @@
+package riskcontrol
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+const (
+	CircuitBreakConditionLossStreak      = "lossStreak"
+	CircuitBreakConditionOrderErrors     = "orderErrors"
+	CircuitBreakConditionStaleMarketData = "staleMarketData"
+)
+
+// LossStreakCondition trips the circuit break after the consecutive losing trades
+type LossStreakCondition struct {
+	MaxConsecutiveLosses int            `json:"maxConsecutiveLosses"`
+	CoolDown             types.Duration `json:"coolDown"`
+}
+
+// OrderErrorCondition trips the circuit break on the burst of the order placement and cancellation errors
+type OrderErrorCondition struct {
+	MaxErrors int            `json:"maxErrors"`
+	Window    types.Duration `json:"window"`
+	CoolDown  types.Duration `json:"coolDown"`
+}
+
+// StaleMarketDataCondition trips the circuit break when no market data is received for the max age
+type StaleMarketDataCondition struct {
+	MaxAge   types.Duration `json:"maxAge"`
+	CoolDown types.Duration `json:"coolDown"`
+}
+
+// CircuitBreakConditions are the additional trip conditions of the circuit break,
+// each condition has its own threshold and cool-down.
+type CircuitBreakConditions struct {
+	LossStreak      *LossStreakCondition      `json:"lossStreak,omitempty"`
+	OrderErrors     *OrderErrorCondition      `json:"orderErrors,omitempty"`
+	StaleMarketData *StaleMarketDataCondition `json:"staleMarketData,omitempty"`
+}
+
+func (c *CircuitBreakConditions) Validate() error {
+	if c.LossStreak != nil && (c.LossStreak.MaxConsecutiveLosses <= 0 || c.LossStreak.CoolDown <= 0) {
+		return fmt.Errorf("circuit break: lossStreak.maxConsecutiveLosses and lossStreak.coolDown should be positive")
+	}
+
+	if c.OrderErrors != nil && (c.OrderErrors.MaxErrors <= 0 || c.OrderErrors.Window <= 0 || c.OrderErrors.CoolDown <= 0) {
+		return fmt.Errorf("circuit break: orderErrors.maxErrors, orderErrors.window and orderErrors.coolDown should be positive")
+	}
+
+	if c.StaleMarketData != nil && c.StaleMarketData.MaxAge <= 0 {
+		return fmt.Errorf("circuit break: staleMarketData.maxAge should be positive")
+	}
+
+	return nil
+}
+
+// Halter is an external halt condition of the circuit break, e.g. the value-at-risk monitor
+type Halter interface {
+	IsHalted(t time.Time) bool
+}
+
+// ConditionCircuitBreaker evaluates the circuit break conditions,
+// the trading is halted until the cool-down of every tripped condition is over.
+type ConditionCircuitBreaker struct {
+	conditions *CircuitBreakConditions
+
+	strategy, symbol string
+
+	mu             sync.Mutex
+	lossStreak     int
+	errorTimes     []time.Time
+	lastMarketData time.Time
+	stale          bool
+	haltedUntil    map[string]time.Time
+
+	halters     map[string]Halter
+	halterNames []string
+}
+
+func NewConditionCircuitBreaker(conditions *CircuitBreakConditions, strategy, symbol string) *ConditionCircuitBreaker {
+	return &ConditionCircuitBreaker{
+		conditions:  conditions,
+		strategy:    strategy,
+		symbol:      symbol,
+		haltedUntil: make(map[string]time.Time),
+	}
+}
+
+func (b *ConditionCircuitBreaker) trip(condition string, now time.Time, coolDown time.Duration, reason string) {
+	b.haltedUntil[condition] = now.Add(coolDown)
+	metricsCircuitBreakTrips.With(prometheus.Labels{
+		"strategy":  b.strategy,
+		"symbol":    b.symbol,
+		"condition": condition,
+	}).Inc()
+
+	log.Warnf("[ConditionCircuitBreaker] %s %s circuit break is tripped by %s: %s, halted for %s",
+		b.strategy, b.symbol, condition, reason, coolDown)
+}
+
+// AddHalter adds the external halt condition, the trading is halted as long as the halter is halted
+func (b *ConditionCircuitBreaker) AddHalter(name string, halter Halter) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.halters == nil {
+		b.halters = make(map[string]Halter)
+	}
+
+	if _, ok := b.halters[name]; !ok {
+		b.halterNames = append(b.halterNames, name)
+	}
+
+	b.halters[name] = halter
+}
+
+// RecordProfit counts the consecutive losing trades, a profitable trade resets the streak
+func (b *ConditionCircuitBreaker) RecordProfit(profit fixedpoint.Value, now time.Time) {
+	c := b.conditions.LossStreak
+	if c == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch profit.Sign() {
+	case -1:
+		b.lossStreak++
+	case 1:
+		b.lossStreak = 0
+	}
+
+	if b.lossStreak >= c.MaxConsecutiveLosses {
+		b.trip(CircuitBreakConditionLossStreak, now, c.CoolDown.Duration(),
+			fmt.Sprintf("%d consecutive losing trades", b.lossStreak))
+		b.lossStreak = 0
+	}
+}
+
+// RecordOrderError counts the order errors in the window
+func (b *ConditionCircuitBreaker) RecordOrderError(now time.Time) {
+	c := b.conditions.OrderErrors
+	if c == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	since := now.Add(-c.Window.Duration())
+	errorTimes := b.errorTimes[:0]
+	for _, t := range b.errorTimes {
+		if t.After(since) {
+			errorTimes = append(errorTimes, t)
+		}
+	}
+
+	b.errorTimes = append(errorTimes, now)
+	if len(b.errorTimes) >= c.MaxErrors {
+		b.trip(CircuitBreakConditionOrderErrors, now, c.CoolDown.Duration(),
+			fmt.Sprintf("%d order errors in %s", len(b.errorTimes), c.Window.Duration()))
+		b.errorTimes = nil
+	}
+}
+
+// RecordMarketData updates the time of the last market data
+func (b *ConditionCircuitBreaker) RecordMarketData(now time.Time) {
+	b.mu.Lock()
+	b.lastMarketData = now
+	b.mu.Unlock()
+}
+
+// IsHalted returns true when any of the tripped conditions is still in its cool-down
+func (b *ConditionCircuitBreaker) IsHalted(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	// the stale market data is checked only after the first market data is received,
+	// the trading is halted as long as the market data is stale, and for the cool-down after it's back.
+	if c := b.conditions.StaleMarketData; c != nil && !b.lastMarketData.IsZero() {
+		if age := now.Sub(b.lastMarketData); age > c.MaxAge.Duration() {
+			if !b.stale {
+				b.stale = true
+				b.trip(CircuitBreakConditionStaleMarketData, now, c.CoolDown.Duration(),
+					fmt.Sprintf("no market data for %s", age))
+			}
+
+			return true
+		}
+
+		if b.stale {
+			b.stale = false
+			b.haltedUntil[CircuitBreakConditionStaleMarketData] = now.Add(c.CoolDown.Duration())
+		}
+	}
+
+	for _, until := range b.haltedUntil {
+		if now.Before(until) {
+			return true
+		}
+	}
+
+	for _, name := range b.halterNames {
+		if b.halters[name].IsHalted(now) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// HaltedConditions returns the conditions that are still in their cool-down
+func (b *ConditionCircuitBreaker) HaltedConditions(now time.Time) (conditions []string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, condition := range []string{
+		CircuitBreakConditionLossStreak, CircuitBreakConditionOrderErrors, CircuitBreakConditionStaleMarketData,
+	} {
+		if now.Before(b.haltedUntil[condition]) || (condition == CircuitBreakConditionStaleMarketData && b.stale) {
+			conditions = append(conditions, condition)
+		}
+	}
+
+	for _, name := range b.halterNames {
+		if b.halters[name].IsHalted(now) {
+			conditions = append(conditions, name)
+		}
+	}
+
+	return conditions
+}
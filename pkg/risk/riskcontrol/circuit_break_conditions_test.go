@@ -0,0 +1,99 @@
+package riskcontrol
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestConditionCircuitBreaker_LossStreak(t *testing.T) {
+	now := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	breaker := NewConditionCircuitBreaker(&CircuitBreakConditions{
+		LossStreak: &LossStreakCondition{MaxConsecutiveLosses: 3, CoolDown: types.Duration(10 * time.Minute)},
+	}, "test", "BTCUSDT")
+
+	loss := fixedpoint.NewFromFloat(-1.0)
+	breaker.RecordProfit(loss, now)
+	breaker.RecordProfit(loss, now)
+
+	// a profitable trade resets the streak
+	breaker.RecordProfit(fixedpoint.NewFromFloat(2.0), now)
+	breaker.RecordProfit(loss, now)
+	breaker.RecordProfit(loss, now)
+	assert.False(t, breaker.IsHalted(now))
+
+	breaker.RecordProfit(loss, now)
+	assert.True(t, breaker.IsHalted(now.Add(time.Minute)))
+	assert.Equal(t, []string{CircuitBreakConditionLossStreak}, breaker.HaltedConditions(now.Add(time.Minute)))
+	assert.False(t, breaker.IsHalted(now.Add(10*time.Minute)))
+}
+
+func TestConditionCircuitBreaker_OrderErrors(t *testing.T) {
+	now := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	breaker := NewConditionCircuitBreaker(&CircuitBreakConditions{
+		OrderErrors: &OrderErrorCondition{MaxErrors: 3, Window: types.Duration(time.Minute), CoolDown: types.Duration(5 * time.Minute)},
+	}, "test", "BTCUSDT")
+
+	breaker.RecordOrderError(now)
+	breaker.RecordOrderError(now.Add(40 * time.Second))
+
+	// the first error is out of the window
+	breaker.RecordOrderError(now.Add(90 * time.Second))
+	assert.False(t, breaker.IsHalted(now.Add(90*time.Second)))
+
+	breaker.RecordOrderError(now.Add(95 * time.Second))
+	assert.True(t, breaker.IsHalted(now.Add(95*time.Second)))
+	assert.False(t, breaker.IsHalted(now.Add(95*time.Second+5*time.Minute)))
+}
+
+func TestConditionCircuitBreaker_StaleMarketData(t *testing.T) {
+	now := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	breaker := NewConditionCircuitBreaker(&CircuitBreakConditions{
+		StaleMarketData: &StaleMarketDataCondition{MaxAge: types.Duration(30 * time.Second), CoolDown: types.Duration(time.Minute)},
+	}, "test", "BTCUSDT")
+
+	// no market data is received yet
+	assert.False(t, breaker.IsHalted(now))
+
+	breaker.RecordMarketData(now)
+	assert.False(t, breaker.IsHalted(now.Add(10*time.Second)))
+
+	// halted as long as the market data is stale
+	assert.True(t, breaker.IsHalted(now.Add(time.Minute)))
+	assert.True(t, breaker.IsHalted(now.Add(5*time.Minute)))
+
+	// the market data is back, halted for the cool-down
+	breaker.RecordMarketData(now.Add(5 * time.Minute))
+	assert.True(t, breaker.IsHalted(now.Add(5*time.Minute)))
+
+	breaker.RecordMarketData(now.Add(6 * time.Minute))
+	assert.False(t, breaker.IsHalted(now.Add(6*time.Minute+time.Second)))
+}
+
+func TestCircuitBreakConditions_Validate(t *testing.T) {
+	assert.NoError(t, (&CircuitBreakConditions{}).Validate())
+	assert.Error(t, (&CircuitBreakConditions{LossStreak: &LossStreakCondition{MaxConsecutiveLosses: 3}}).Validate())
+	assert.Error(t, (&CircuitBreakConditions{OrderErrors: &OrderErrorCondition{MaxErrors: 3, CoolDown: types.Duration(time.Minute)}}).Validate())
+	assert.Error(t, (&CircuitBreakConditions{StaleMarketData: &StaleMarketDataCondition{}}).Validate())
+}
+
+type fakeHalter bool
+
+func (h *fakeHalter) IsHalted(time.Time) bool { return bool(*h) }
+
+func TestConditionCircuitBreaker_Halter(t *testing.T) {
+	now := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	breaker := NewConditionCircuitBreaker(&CircuitBreakConditions{}, "test", "BTCUSDT")
+
+	halter := fakeHalter(false)
+	breaker.AddHalter("valueAtRisk", &halter)
+	assert.False(t, breaker.IsHalted(now))
+
+	halter = true
+	assert.True(t, breaker.IsHalted(now))
+	assert.Equal(t, []string{"valueAtRisk"}, breaker.HaltedConditions(now))
+}
@@ -0,0 +1,86 @@
+package riskcontrol
+
+import (
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	indicatorv2 "github.com/c9s/bbgo/pkg/indicator/v2"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// DrawdownCircuitBreakRiskControl halts the trading when the intraday drawdown,
+// the realized PnL of today plus the unrealized PnL of the position, exceeds the max drawdown ratio of the equity.
+// The halt is re-armed after the halted duration.
+type DrawdownCircuitBreakRiskControl struct {
+	price          *indicatorv2.EWMAStream
+	position       *types.Position
+	profitStats    *types.ProfitStats
+	maxDrawdown    fixedpoint.Value
+	haltedDuration time.Duration
+
+	halted   bool
+	haltedAt time.Time
+	drawdown fixedpoint.Value
+}
+
+func NewDrawdownCircuitBreakRiskControl(
+	position *types.Position,
+	price *indicatorv2.EWMAStream,
+	maxDrawdown fixedpoint.Value,
+	profitStats *types.ProfitStats,
+	haltedDuration time.Duration,
+) *DrawdownCircuitBreakRiskControl {
+	return &DrawdownCircuitBreakRiskControl{
+		price:          price,
+		position:       position,
+		profitStats:    profitStats,
+		maxDrawdown:    maxDrawdown,
+		haltedDuration: haltedDuration,
+	}
+}
+
+// Drawdown returns the last calculated drawdown ratio
+func (c *DrawdownCircuitBreakRiskControl) Drawdown() fixedpoint.Value {
+	return c.drawdown
+}
+
+// HaltedAt returns the time of the last halt
+func (c *DrawdownCircuitBreakRiskControl) HaltedAt() time.Time {
+	return c.haltedAt
+}
+
+// IsHalted checks the drawdown against the given equity and returns whether the trading should be halted.
+// Once halted, it keeps returning true until the halted duration is over.
+func (c *DrawdownCircuitBreakRiskControl) IsHalted(t time.Time, equity fixedpoint.Value) bool {
+	if c.profitStats.IsOver24Hours() {
+		c.profitStats.ResetToday(t)
+	}
+
+	if c.halted {
+		if t.Sub(c.haltedAt) < c.haltedDuration {
+			return true
+		}
+
+		log.Infof("[DrawdownCircuitBreakRiskControl] halted duration %s is over, re-arming", c.haltedDuration)
+		c.halted = false
+	}
+
+	price := fixedpoint.NewFromFloat(c.price.Last(0))
+	if price.Sign() <= 0 || equity.Sign() <= 0 {
+		return false
+	}
+
+	pnl := c.position.UnrealizedProfit(price).Add(c.profitStats.TodayPnL)
+	c.drawdown = fixedpoint.Max(pnl.Neg(), fixedpoint.Zero).Div(equity)
+
+	if c.drawdown.Compare(c.maxDrawdown) >= 0 {
+		log.Warnf("[DrawdownCircuitBreakRiskControl] drawdown %s (PnL %f) exceeds the max drawdown %s of equity %f, halting",
+			c.drawdown.Percentage(), pnl.Float64(), c.maxDrawdown.Percentage(), equity.Float64())
+		c.halted = true
+		c.haltedAt = t
+	}
+
+	return c.halted
+}
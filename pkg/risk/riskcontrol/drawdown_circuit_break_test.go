@@ -0,0 +1,44 @@
+package riskcontrol
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	indicatorv2 "github.com/c9s/bbgo/pkg/indicator/v2"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func Test_DrawdownCircuitBreak(t *testing.T) {
+	priceEWMA := indicatorv2.EWMA2(nil, 30)
+	priceEWMA.PushAndEmit(30000.0)
+
+	position := &types.Position{
+		Base:        fixedpoint.NewFromFloat(1.0),
+		AverageCost: fixedpoint.NewFromFloat(30500.0),
+	}
+
+	now := time.Now()
+	profitStats := &types.ProfitStats{}
+	profitStats.ResetToday(now)
+	profitStats.TodayPnL = fixedpoint.NewFromFloat(-100.0)
+
+	riskControl := NewDrawdownCircuitBreakRiskControl(position, priceEWMA, fixedpoint.NewFromFloat(0.05), profitStats, time.Hour)
+
+	// drawdown = (500 + 100) / 20000 = 3%
+	assert.False(t, riskControl.IsHalted(now, fixedpoint.NewFromFloat(20000.0)))
+	assert.Equal(t, "0.03", riskControl.Drawdown().String())
+
+	// drawdown = (500 + 100) / 10000 = 6%
+	assert.True(t, riskControl.IsHalted(now, fixedpoint.NewFromFloat(10000.0)))
+
+	// still halted in the halted duration even if the drawdown is recovered
+	position.AverageCost = fixedpoint.NewFromFloat(30000.0)
+	profitStats.TodayPnL = fixedpoint.Zero
+	assert.True(t, riskControl.IsHalted(now.Add(30*time.Minute), fixedpoint.NewFromFloat(10000.0)))
+
+	// re-armed after the halted duration
+	assert.False(t, riskControl.IsHalted(now.Add(61*time.Minute), fixedpoint.NewFromFloat(10000.0)))
+}
@@ -0,0 +1,15 @@
+package riskcontrol
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var metricsCircuitBreakTrips = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "bbgo_circuit_break_trips_total",
+		Help: "the number of the circuit break trips by condition",
+	},
+	[]string{"strategy", "symbol", "condition"},
+)
+
+func init() {
+	prometheus.MustRegister(metricsCircuitBreakTrips)
+}
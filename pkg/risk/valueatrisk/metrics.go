@@ -0,0 +1,35 @@
+package valueatrisk
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var (
+	metricsParametricVaR = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "bbgo_value_at_risk_parametric",
+			Help: "the parametric value-at-risk of the portfolio in USD",
+		},
+	)
+
+	metricsHistoricalVaR = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "bbgo_value_at_risk_historical",
+			Help: "the historical value-at-risk of the portfolio in USD",
+		},
+	)
+
+	metricsStressPnL = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "bbgo_stress_scenario_pnl",
+			Help: "the PnL of the portfolio under the stress scenario in USD",
+		},
+		[]string{"scenario"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(
+		metricsParametricVaR,
+		metricsHistoricalVaR,
+		metricsStressPnL,
+	)
+}
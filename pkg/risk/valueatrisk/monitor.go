@@ -0,0 +1,221 @@
+package valueatrisk
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// Config is the value-at-risk config of the aggregate portfolio
+type Config struct {
+	// Interval is the kline interval of the return series, defaults to 1h
+	Interval types.Interval `json:"interval,omitempty"`
+
+	// Window is the number of the returns, defaults to 500
+	Window int `json:"window,omitempty"`
+
+	// Confidence is the confidence level of the VaR, defaults to 0.99
+	Confidence float64 `json:"confidence,omitempty"`
+
+	// UpdateInterval is the interval of re-computing the VaR, defaults to 5m
+	UpdateInterval types.Duration `json:"updateInterval,omitempty"`
+
+	// MaxVaR is the VaR threshold in USD, the breach event is emitted when the larger VaR of
+	// the parametric VaR and the historical VaR exceeds it
+	MaxVaR fixedpoint.Value `json:"maxVaR,omitempty"`
+
+	Scenarios []StressScenario `json:"scenarios,omitempty"`
+}
+
+func (c *Config) Defaults() {
+	if c.Interval == "" {
+		c.Interval = types.Interval1h
+	}
+
+	if c.Window == 0 {
+		c.Window = 500
+	}
+
+	if c.Confidence == 0 {
+		c.Confidence = 0.99
+	}
+
+	if c.UpdateInterval == 0 {
+		c.UpdateInterval = types.Duration(5 * time.Minute)
+	}
+}
+
+func (c *Config) Validate() error {
+	if c.Confidence <= 0.5 || c.Confidence >= 1 {
+		return fmt.Errorf("value at risk: confidence should be in (0.5, 1), got %f", c.Confidence)
+	}
+
+	if c.Window < 2 {
+		return fmt.Errorf("value at risk: window should be at least 2, got %d", c.Window)
+	}
+
+	if c.MaxVaR.Sign() < 0 {
+		return fmt.Errorf("value at risk: maxVaR should not be negative, got %v", c.MaxVaR)
+	}
+
+	for _, scenario := range c.Scenarios {
+		if scenario.Name == "" {
+			return fmt.Errorf("value at risk: stress scenario name is required")
+		}
+	}
+
+	return nil
+}
+
+// Report is the value-at-risk result of the portfolio, the VaR and the scenario PnLs are in USD
+type Report struct {
+	Time       time.Time `json:"time"`
+	Confidence float64   `json:"confidence"`
+
+	Exposures map[string]float64 `json:"exposures"`
+
+	ParametricVaR float64 `json:"parametricVaR"`
+	HistoricalVaR float64 `json:"historicalVaR"`
+
+	Scenarios map[string]float64 `json:"scenarios,omitempty"`
+
+	Breached bool `json:"breached"`
+}
+
+// VaR returns the larger VaR of the parametric VaR and the historical VaR
+func (r *Report) VaR() float64 {
+	if r.ParametricVaR > r.HistoricalVaR {
+		return r.ParametricVaR
+	}
+
+	return r.HistoricalVaR
+}
+
+// ExposuresFunc returns the USD exposures of the portfolio keyed by the asset
+type ExposuresFunc func() map[string]float64
+
+// KLinesFunc queries the kline history of the asset priced in USD
+type KLinesFunc func(ctx context.Context, asset string, interval types.Interval, limit int) ([]types.KLine, error)
+
+// Monitor re-computes the value-at-risk of the portfolio on every update interval,
+// and emits the breach event when the VaR exceeds the threshold.
+//
+//go:generate callbackgen -type Monitor
+type Monitor struct {
+	config    *Config
+	exposures ExposuresFunc
+	klines    KLinesFunc
+
+	mu     sync.Mutex
+	report *Report
+
+	breachCallbacks []func(report *Report)
+}
+
+func NewMonitor(config *Config, exposures ExposuresFunc, klines KLinesFunc) *Monitor {
+	return &Monitor{
+		config:    config,
+		exposures: exposures,
+		klines:    klines,
+	}
+}
+
+// Report returns the last report, it's nil before the first update
+func (m *Monitor) Report() *Report {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.report
+}
+
+// IsHalted returns true when the last VaR exceeds the threshold,
+// it can be used as the halt condition of the circuit breakers.
+func (m *Monitor) IsHalted(_ time.Time) bool {
+	report := m.Report()
+	return report != nil && report.Breached
+}
+
+// Update queries the klines of the exposed assets and re-computes the VaR
+func (m *Monitor) Update(ctx context.Context, now time.Time) (*Report, error) {
+	exposures := m.exposures()
+
+	assets := make([]string, 0, len(exposures))
+	for asset, exposure := range exposures {
+		if exposure != 0 {
+			assets = append(assets, asset)
+		}
+	}
+
+	sort.Strings(assets)
+
+	returns := make(map[string][]float64, len(assets))
+	for _, asset := range assets {
+		// the USD stable coins have no price risk
+		if types.IsUSDFiatCurrency(asset) {
+			continue
+		}
+
+		klines, err := m.klines(ctx, asset, m.config.Interval, m.config.Window+1)
+		if err != nil {
+			return nil, fmt.Errorf("unable to query the %s klines: %w", asset, err)
+		}
+
+		returns[asset] = LogReturns(klines)
+	}
+
+	report := &Report{
+		Time:          now,
+		Confidence:    m.config.Confidence,
+		Exposures:     exposures,
+		ParametricVaR: ParametricVaR(exposures, returns, m.config.Confidence),
+		HistoricalVaR: HistoricalVaR(PortfolioPnLs(exposures, returns), m.config.Confidence),
+	}
+
+	if len(m.config.Scenarios) > 0 {
+		report.Scenarios = make(map[string]float64, len(m.config.Scenarios))
+		for _, scenario := range m.config.Scenarios {
+			pnl := scenario.PnL(exposures)
+			report.Scenarios[scenario.Name] = pnl
+			metricsStressPnL.WithLabelValues(scenario.Name).Set(pnl)
+		}
+	}
+
+	report.Breached = m.config.MaxVaR.Sign() > 0 && report.VaR() > m.config.MaxVaR.Float64()
+
+	metricsParametricVaR.Set(report.ParametricVaR)
+	metricsHistoricalVaR.Set(report.HistoricalVaR)
+
+	m.mu.Lock()
+	m.report = report
+	m.mu.Unlock()
+
+	if report.Breached {
+		m.EmitBreach(report)
+	}
+
+	return report, nil
+}
+
+// Run updates the VaR on every update interval until the context is canceled
+func (m *Monitor) Run(ctx context.Context) {
+	ticker := time.NewTicker(m.config.UpdateInterval.Duration())
+	defer ticker.Stop()
+
+	for {
+		if _, err := m.Update(ctx, time.Now()); err != nil {
+			log.WithError(err).Errorf("unable to update the value at risk")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,173 @@
+// Package valueatrisk computes the value-at-risk and the stress scenario losses of the aggregate portfolio.
+package valueatrisk
+
+import (
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/stat/distuv"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// LogReturns returns the log returns of the kline close prices
+func LogReturns(klines []types.KLine) []float64 {
+	if len(klines) < 2 {
+		return nil
+	}
+
+	returns := make([]float64, 0, len(klines)-1)
+	for i := 1; i < len(klines); i++ {
+		prev, curr := klines[i-1].Close.Float64(), klines[i].Close.Float64()
+		if prev <= 0 || curr <= 0 {
+			returns = append(returns, 0)
+			continue
+		}
+
+		returns = append(returns, math.Log(curr/prev))
+	}
+
+	return returns
+}
+
+// PortfolioPnLs returns the simulated PnL series of the portfolio, the exposures are the USD values of the assets,
+// and the returns are the aligned return series of the assets. The series are aligned to the most recent returns.
+func PortfolioPnLs(exposures map[string]float64, returns map[string][]float64) []float64 {
+	n := -1
+	for asset := range exposures {
+		r, ok := returns[asset]
+		if !ok {
+			continue
+		}
+
+		if n < 0 || len(r) < n {
+			n = len(r)
+		}
+	}
+
+	if n <= 0 {
+		return nil
+	}
+
+	pnls := make([]float64, n)
+	for asset, exposure := range exposures {
+		r, ok := returns[asset]
+		if !ok {
+			continue
+		}
+
+		r = r[len(r)-n:]
+		for i := range pnls {
+			// the simple return of the log return
+			pnls[i] += exposure * math.Expm1(r[i])
+		}
+	}
+
+	return pnls
+}
+
+// HistoricalVaR returns the loss at the confidence level of the PnL distribution, the loss is a positive number
+func HistoricalVaR(pnls []float64, confidence float64) float64 {
+	if len(pnls) == 0 {
+		return 0
+	}
+
+	sorted := make([]float64, len(pnls))
+	copy(sorted, pnls)
+	sort.Float64s(sorted)
+
+	idx := int(math.Floor((1 - confidence) * float64(len(sorted))))
+	if idx >= len(sorted) {
+		idx = len(sorted) - 1
+	}
+
+	return math.Max(0, -sorted[idx])
+}
+
+// ParametricVaR returns the variance-covariance VaR of the portfolio, assuming the normally distributed returns
+func ParametricVaR(exposures map[string]float64, returns map[string][]float64, confidence float64) float64 {
+	var assets []string
+	n := -1
+	for asset := range exposures {
+		r, ok := returns[asset]
+		if !ok || len(r) < 2 {
+			continue
+		}
+
+		assets = append(assets, asset)
+		if n < 0 || len(r) < n {
+			n = len(r)
+		}
+	}
+
+	if len(assets) == 0 {
+		return 0
+	}
+
+	sort.Strings(assets)
+
+	aligned := make([][]float64, len(assets))
+	for i, asset := range assets {
+		r := returns[asset]
+		aligned[i] = r[len(r)-n:]
+	}
+
+	var variance float64
+	for i := range assets {
+		for j := range assets {
+			variance += exposures[assets[i]] * exposures[assets[j]] * covariance(aligned[i], aligned[j])
+		}
+	}
+
+	if variance <= 0 {
+		return 0
+	}
+
+	z := distuv.UnitNormal.Quantile(confidence)
+	return z * math.Sqrt(variance)
+}
+
+func covariance(x, y []float64) float64 {
+	n := len(x)
+	if n < 2 {
+		return 0
+	}
+
+	var mx, my float64
+	for i := 0; i < n; i++ {
+		mx += x[i]
+		my += y[i]
+	}
+
+	mx /= float64(n)
+	my /= float64(n)
+
+	var cov float64
+	for i := 0; i < n; i++ {
+		cov += (x[i] - mx) * (y[i] - my)
+	}
+
+	return cov / float64(n-1)
+}
+
+// StressScenario shocks the asset prices by the given relative moves, e.g. -0.3 for a 30% drop.
+// The "*" shock applies to the assets that are not listed.
+type StressScenario struct {
+	Name   string             `json:"name"`
+	Shocks map[string]float64 `json:"shocks"`
+}
+
+// PnL returns the PnL of the portfolio under the scenario
+func (s StressScenario) PnL(exposures map[string]float64) float64 {
+	var pnl float64
+	for asset, exposure := range exposures {
+		shock, ok := s.Shocks[asset]
+		if !ok {
+			shock = s.Shocks["*"]
+		}
+
+		pnl += exposure * shock
+	}
+
+	return pnl
+}
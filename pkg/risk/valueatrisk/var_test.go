@@ -0,0 +1,119 @@
+package valueatrisk
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func closes(prices ...float64) (klines []types.KLine) {
+	for _, price := range prices {
+		klines = append(klines, types.KLine{Close: fixedpoint.NewFromFloat(price)})
+	}
+
+	return klines
+}
+
+func TestLogReturns(t *testing.T) {
+	returns := LogReturns(closes(100, 110, 99))
+	if assert.Len(t, returns, 2) {
+		assert.InDelta(t, math.Log(1.1), returns[0], 1e-9)
+		assert.InDelta(t, math.Log(0.9), returns[1], 1e-9)
+	}
+
+	assert.Nil(t, LogReturns(closes(100)))
+}
+
+func TestHistoricalVaR(t *testing.T) {
+	var pnls []float64
+	for i := 1; i <= 100; i++ {
+		pnls = append(pnls, float64(i-50))
+	}
+
+	// the 1st percentile of -49..50
+	assert.InDelta(t, 48.0, HistoricalVaR(pnls, 0.99), 1e-9)
+
+	// no loss
+	assert.Equal(t, 0.0, HistoricalVaR([]float64{1, 2, 3}, 0.99))
+	assert.Equal(t, 0.0, HistoricalVaR(nil, 0.99))
+}
+
+func TestParametricVaR(t *testing.T) {
+	returns := map[string][]float64{
+		"BTC": {0.01, -0.01, 0.01, -0.01},
+	}
+
+	// the sample stddev of the returns
+	stddev := math.Sqrt(4 * 0.0001 / 3)
+
+	v := ParametricVaR(map[string]float64{"BTC": 1000}, returns, 0.99)
+	assert.InDelta(t, 2.326348*1000*stddev, v, 1e-3)
+
+	// the perfectly hedged portfolio has no variance
+	returns["ETH"] = returns["BTC"]
+	v = ParametricVaR(map[string]float64{"BTC": 1000, "ETH": -1000}, returns, 0.99)
+	assert.InDelta(t, 0.0, v, 1e-9)
+}
+
+func TestStressScenario_PnL(t *testing.T) {
+	scenario := StressScenario{
+		Name:   "crash",
+		Shocks: map[string]float64{"BTC": -0.3, "*": -0.5},
+	}
+
+	pnl := scenario.PnL(map[string]float64{"BTC": 1000, "ETH": -200})
+	assert.InDelta(t, -300.0+100.0, pnl, 1e-9)
+}
+
+func TestMonitor_Update(t *testing.T) {
+	config := &Config{
+		MaxVaR: fixedpoint.NewFromFloat(10),
+		Scenarios: []StressScenario{
+			{Name: "crash", Shocks: map[string]float64{"*": -0.5}},
+		},
+	}
+	config.Defaults()
+	assert.NoError(t, config.Validate())
+
+	exposures := map[string]float64{"BTC": 1000, "USDT": 5000}
+	var queried []string
+	monitor := NewMonitor(config, func() map[string]float64 {
+		return exposures
+	}, func(ctx context.Context, asset string, interval types.Interval, limit int) ([]types.KLine, error) {
+		queried = append(queried, asset)
+		assert.Equal(t, types.Interval1h, interval)
+		assert.Equal(t, 501, limit)
+		return closes(100, 90, 100, 90, 100), nil
+	})
+
+	var breached *Report
+	monitor.OnBreach(func(report *Report) {
+		breached = report
+	})
+
+	assert.False(t, monitor.IsHalted(time.Now()))
+
+	report, err := monitor.Update(context.Background(), time.Now())
+	if assert.NoError(t, err) {
+		// the stable coins are not queried
+		assert.Equal(t, []string{"BTC"}, queried)
+		assert.True(t, report.HistoricalVaR > 90)
+		assert.True(t, report.Breached)
+		assert.Equal(t, report, breached)
+		assert.InDelta(t, -3000.0, report.Scenarios["crash"], 1e-9)
+		assert.True(t, monitor.IsHalted(time.Now()))
+	}
+
+	exposures = map[string]float64{"BTC": 1}
+	report, err = monitor.Update(context.Background(), time.Now())
+	if assert.NoError(t, err) {
+		assert.False(t, report.Breached)
+		assert.False(t, monitor.IsHalted(time.Now()))
+	}
+}
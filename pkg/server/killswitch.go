@@ -0,0 +1,78 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/c9s/bbgo/pkg/bbgo"
+)
+
+type tripKillSwitchRequest struct {
+	Reason string `json:"reason"`
+}
+
+// findSession finds the exchange session by the session path parameter
+func (s *Server) findSession(c *gin.Context) (*bbgo.ExchangeSession, bool) {
+	if s.Environ == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "environment is not running"})
+		return nil, false
+	}
+
+	sessionName := c.Param("session")
+	session, ok := s.Environ.Session(sessionName)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("session %s not found", sessionName)})
+		return nil, false
+	}
+
+	return session, true
+}
+
+func (s *Server) getKillSwitch(c *gin.Context) {
+	session, ok := s.findSession(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"killSwitch": session.KillSwitch().State()})
+}
+
+func (s *Server) tripKillSwitch(c *gin.Context) {
+	session, ok := s.findSession(c)
+	if !ok {
+		return
+	}
+
+	var req tripKillSwitchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if req.Reason == "" {
+		req.Reason = "tripped by the operator"
+	}
+
+	if err := session.TripKillSwitch(c, req.Reason); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "killSwitch": session.KillSwitch().State()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"killSwitch": session.KillSwitch().State()})
+}
+
+func (s *Server) resetKillSwitch(c *gin.Context) {
+	session, ok := s.findSession(c)
+	if !ok {
+		return
+	}
+
+	if err := session.ResetKillSwitch(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"killSwitch": session.KillSwitch().State()})
+}
@@ -0,0 +1,196 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/sirupsen/logrus"
+)
+
+// logSubscriberBufferSize is the number of the log events buffered for a slow websocket client,
+// the events are dropped when the buffer is full so that the logging is never blocked by the clients.
+const logSubscriberBufferSize = 256
+
+// LogEvent is the structured log event pushed to the web console
+type LogEvent struct {
+	Time    time.Time              `json:"time"`
+	Level   string                 `json:"level"`
+	Message string                 `json:"message"`
+	Fields  map[string]interface{} `json:"fields,omitempty"`
+}
+
+// LogFilter filters the log events by the strategy, the strategy instance and the minimum level
+type LogFilter struct {
+	Strategy string
+	Instance string
+	Level    logrus.Level
+}
+
+func (f LogFilter) Match(entry *logrus.Entry) bool {
+	// the lower level value is the more severe level
+	if entry.Level > f.Level {
+		return false
+	}
+
+	if f.Strategy != "" && fmt.Sprint(entry.Data["strategy"]) != f.Strategy {
+		return false
+	}
+
+	if f.Instance != "" && fmt.Sprint(entry.Data["instance"]) != f.Instance {
+		return false
+	}
+
+	return true
+}
+
+type logSubscriber struct {
+	filter LogFilter
+	C      chan LogEvent
+}
+
+// LogBroadcaster is a logrus hook that pushes the log events to the websocket subscribers
+type LogBroadcaster struct {
+	mu          sync.Mutex
+	subscribers map[*logSubscriber]struct{}
+}
+
+func NewLogBroadcaster() *LogBroadcaster {
+	return &LogBroadcaster{
+		subscribers: make(map[*logSubscriber]struct{}),
+	}
+}
+
+func (b *LogBroadcaster) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (b *LogBroadcaster) Fire(entry *logrus.Entry) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(b.subscribers) == 0 {
+		return nil
+	}
+
+	var event *LogEvent
+	for subscriber := range b.subscribers {
+		if !subscriber.filter.Match(entry) {
+			continue
+		}
+
+		if event == nil {
+			event = newLogEvent(entry)
+		}
+
+		select {
+		case subscriber.C <- *event:
+		default:
+		}
+	}
+
+	return nil
+}
+
+// Subscribe registers a subscriber of the matched log events, the returned function unsubscribes it
+func (b *LogBroadcaster) Subscribe(filter LogFilter) (<-chan LogEvent, func()) {
+	subscriber := &logSubscriber{
+		filter: filter,
+		C:      make(chan LogEvent, logSubscriberBufferSize),
+	}
+
+	b.mu.Lock()
+	b.subscribers[subscriber] = struct{}{}
+	b.mu.Unlock()
+
+	return subscriber.C, func() {
+		b.mu.Lock()
+		delete(b.subscribers, subscriber)
+		b.mu.Unlock()
+	}
+}
+
+func newLogEvent(entry *logrus.Entry) *LogEvent {
+	event := &LogEvent{
+		Time:    entry.Time,
+		Level:   entry.Level.String(),
+		Message: entry.Message,
+	}
+
+	if len(entry.Data) > 0 {
+		event.Fields = make(map[string]interface{}, len(entry.Data))
+		for k, v := range entry.Data {
+			// the error values are not json serializable
+			if err, ok := v.(error); ok {
+				v = err.Error()
+			}
+
+			event.Fields[k] = v
+		}
+	}
+
+	return event
+}
+
+var logStreamUpgrader = websocket.Upgrader{
+	// the console is served from the same server, the api is protected by the bind address
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// streamLogs pushes the log events filtered by the strategy, instance and level query parameters over websocket
+func (s *Server) streamLogs(c *gin.Context) {
+	filter := LogFilter{
+		Strategy: c.Query("strategy"),
+		Instance: c.Query("instance"),
+		Level:    logrus.InfoLevel,
+	}
+
+	if level := c.Query("level"); level != "" {
+		lvl, err := logrus.ParseLevel(level)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		filter.Level = lvl
+	}
+
+	conn, err := logStreamUpgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		logrus.WithError(err).Error("unable to upgrade the log stream connection")
+		return
+	}
+	defer conn.Close()
+
+	events, unsubscribe := s.logs.Subscribe(filter)
+	defer unsubscribe()
+
+	// the client messages are discarded, the read loop detects the closed connection
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case <-closed:
+			return
+
+		case <-c.Request.Context().Done():
+			return
+
+		case event := <-events:
+			if err := conn.WriteJSON(event); err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package server
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestLogger(b *LogBroadcaster) *logrus.Logger {
+	logger := logrus.New()
+	logger.SetLevel(logrus.DebugLevel)
+	logger.AddHook(b)
+	logger.Out = &strings.Builder{}
+	return logger
+}
+
+func numOfLogSubscribers(b *LogBroadcaster) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.subscribers)
+}
+
+func TestLogBroadcaster_FilterByInstance(t *testing.T) {
+	b := NewLogBroadcaster()
+	logger := newTestLogger(b)
+
+	events, unsubscribe := b.Subscribe(LogFilter{
+		Strategy: "xmaker",
+		Instance: "xmaker:BTCUSDT",
+		Level:    logrus.InfoLevel,
+	})
+	defer unsubscribe()
+
+	logger.WithFields(logrus.Fields{"strategy": "xmaker", "instance": "xmaker:ETHUSDT"}).Info("other instance")
+	logger.WithFields(logrus.Fields{"strategy": "xalign", "instance": "xmaker:BTCUSDT"}).Info("other strategy")
+	logger.Info("no fields")
+	logger.WithFields(logrus.Fields{"strategy": "xmaker", "instance": "xmaker:BTCUSDT"}).Info("matched")
+
+	if assert.Len(t, events, 1) {
+		event := <-events
+		assert.Equal(t, "matched", event.Message)
+		assert.Equal(t, "info", event.Level)
+		assert.Equal(t, "xmaker:BTCUSDT", event.Fields["instance"])
+	}
+}
+
+func TestLogBroadcaster_FilterByLevel(t *testing.T) {
+	b := NewLogBroadcaster()
+	logger := newTestLogger(b)
+
+	events, unsubscribe := b.Subscribe(LogFilter{Level: logrus.WarnLevel})
+	defer unsubscribe()
+
+	logger.Debug("debug")
+	logger.Info("info")
+	logger.Warn("warn")
+	logger.WithError(assert.AnError).Error("error")
+
+	if assert.Len(t, events, 2) {
+		event := <-events
+		assert.Equal(t, "warning", event.Level)
+
+		event = <-events
+		assert.Equal(t, "error", event.Level)
+		assert.Equal(t, assert.AnError.Error(), event.Fields[logrus.ErrorKey])
+	}
+}
+
+func TestLogBroadcaster_Unsubscribe(t *testing.T) {
+	b := NewLogBroadcaster()
+	logger := newTestLogger(b)
+
+	events, unsubscribe := b.Subscribe(LogFilter{Level: logrus.InfoLevel})
+	unsubscribe()
+
+	logger.Info("dropped")
+	assert.Len(t, events, 0)
+	assert.Equal(t, 0, numOfLogSubscribers(b))
+}
+
+func TestServer_streamLogs(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	s := &Server{logs: NewLogBroadcaster()}
+	logger := newTestLogger(s.logs)
+
+	r := gin.New()
+	r.GET("/api/logs/stream", s.streamLogs)
+
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	url := "ws" + strings.TrimPrefix(ts.URL, "http") + "/api/logs/stream?instance=xmaker:BTCUSDT&level=warn"
+
+	t.Run("invalid level", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/api/logs/stream?level=foo", nil)
+		r.ServeHTTP(w, req)
+		assert.Equal(t, 400, w.Code)
+	})
+
+	t.Run("stream and disconnect", func(t *testing.T) {
+		conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		assert.Eventually(t, func() bool {
+			return numOfLogSubscribers(s.logs) == 1
+		}, time.Second, 10*time.Millisecond)
+
+		logger.WithField("instance", "xmaker:BTCUSDT").Info("filtered by level")
+		logger.WithField("instance", "xmaker:ETHUSDT").Warn("filtered by instance")
+		logger.WithField("instance", "xmaker:BTCUSDT").Warn("matched")
+
+		var event LogEvent
+		assert.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+		assert.NoError(t, conn.ReadJSON(&event))
+		assert.Equal(t, "matched", event.Message)
+		assert.Equal(t, "warning", event.Level)
+
+		// the subscriber is removed once the client disconnects
+		assert.NoError(t, conn.Close())
+		assert.Eventually(t, func() bool {
+			return numOfLogSubscribers(s.logs) == 0
+		}, time.Second, 10*time.Millisecond)
+	})
+}
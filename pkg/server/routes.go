@@ -18,6 +18,7 @@ import (
 	"github.com/sirupsen/logrus"
 
 	"github.com/c9s/bbgo/pkg/bbgo"
+	"github.com/c9s/bbgo/pkg/exchange/apistats"
 	"github.com/c9s/bbgo/pkg/fixedpoint"
 	"github.com/c9s/bbgo/pkg/service"
 	"github.com/c9s/bbgo/pkg/types"
@@ -46,6 +47,9 @@ type Server struct {
 	OpenInBrowser bool
 
 	srv *http.Server
+
+	// logs pushes the log events to the websocket clients of the web console
+	logs *LogBroadcaster
 }
 
 func (s *Server) newEngine(ctx context.Context) *gin.Engine {
@@ -61,6 +65,16 @@ func (s *Server) newEngine(ctx context.Context) *gin.Engine {
 	}))
 
 	r.GET("/api/ping", s.ping)
+	r.GET("/api/health", s.health)
+	r.GET("/api/debug/api-errors", s.apiErrors)
+
+	if s.logs == nil {
+		s.logs = NewLogBroadcaster()
+		logrus.AddHook(s.logs)
+	}
+
+	// the log events are filtered by the query parameters: strategy, instance and level
+	r.GET("/api/logs/stream", s.streamLogs)
 
 	if s.Setup != nil {
 		r.POST("/api/setup/test-db", s.setupTestDB)
@@ -224,12 +238,19 @@ func (s *Server) newEngine(ctx context.Context) *gin.Engine {
 	})
 
 	r.GET("/api/assets", s.listAssets)
+	r.GET("/api/portfolio", s.getPortfolio)
+	r.GET("/api/portfolio/exposure", s.getPortfolioExposure)
+	r.GET("/api/strategies/budgets", s.getStrategyBudgets)
 	r.GET("/api/sessions/:session", s.listSessions)
 	r.GET("/api/sessions/:session/trades", s.listSessionTrades)
 	r.GET("/api/sessions/:session/open-orders", s.listSessionOpenOrders)
 	r.GET("/api/sessions/:session/account", s.getSessionAccount)
 	r.GET("/api/sessions/:session/account/balances", s.getSessionAccountBalance)
+	r.GET("/api/sessions/:session/account/statements", s.getSessionAccountStatements)
 	r.GET("/api/sessions/:session/symbols", s.listSessionSymbols)
+	r.GET("/api/sessions/:session/kill-switch", s.getKillSwitch)
+	r.POST("/api/sessions/:session/kill-switch/trip", s.tripKillSwitch)
+	r.POST("/api/sessions/:session/kill-switch/reset", s.resetKillSwitch)
 
 	r.GET("/api/sessions/:session/pnl", func(c *gin.Context) {
 		c.JSON(200, gin.H{"message": "pong"})
@@ -248,6 +269,16 @@ func (s *Server) newEngine(ctx context.Context) *gin.Engine {
 	})
 
 	r.GET("/api/strategies/single", s.listStrategies)
+
+	// runtime controls of the running strategy instances, keyed by the strategy instance ID
+	r.GET("/api/strategies/instances/:instanceID/status", s.getStrategyStatus)
+	r.POST("/api/strategies/instances/:instanceID/suspend", s.suspendStrategy)
+	r.POST("/api/strategies/instances/:instanceID/resume", s.resumeStrategy)
+	r.POST("/api/strategies/instances/:instanceID/flatten", s.flattenStrategyPosition)
+	r.POST("/api/strategies/instances/:instanceID/wind-down", s.windDownStrategy)
+	r.GET("/api/strategies/instances/:instanceID/position/lots", s.getStrategyPositionLots)
+	r.PUT("/api/strategies/instances/:instanceID/margins", s.adjustStrategyMargins)
+	r.GET("/api/strategies/instances/:instanceID/quote/diagnostics", s.getStrategyQuoteDiagnostics)
 	r.NoRoute(s.assetsHandler)
 	return r
 }
@@ -279,6 +310,27 @@ func (s *Server) ping(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "pong"})
 }
 
+// health reports the worker heartbeats and the goroutine count monitored by the watchdog,
+// it responds with 503 when a worker is stalled or the goroutine count exceeds the limit.
+func (s *Server) health(c *gin.Context) {
+	if s.Environ == nil || s.Environ.Watchdog == nil {
+		c.JSON(http.StatusOK, gin.H{"healthy": true})
+		return
+	}
+
+	status := s.Environ.Watchdog.Status()
+	if !status.Healthy {
+		c.JSON(http.StatusServiceUnavailable, status)
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
+func (s *Server) apiErrors(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"errors": apistats.Report()})
+}
+
 func (s *Server) listClosedOrders(c *gin.Context) {
 	if s.Environ.OrderService == nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "database is not configured"})
@@ -387,6 +439,22 @@ func (s *Server) getSessionAccount(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"account": session.GetAccount()})
 }
 
+func (s *Server) getSessionAccountStatements(c *gin.Context) {
+	sessionName := c.Param("session")
+	if s.Environ.AccountStatements() == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "account statements are not configured"})
+		return
+	}
+
+	current, statements, ok := s.Environ.AccountStatements().Statements(sessionName)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("session %s has no account statements", sessionName)})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"current": current, "statements": statements})
+}
+
 func (s *Server) getSessionAccountBalance(c *gin.Context) {
 	sessionName := c.Param("session")
 	session, ok := s.Environ.Session(sessionName)
@@ -480,6 +548,42 @@ func (s *Server) listAssets(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"assets": totalAssets})
 }
 
+func (s *Server) getPortfolio(c *gin.Context) {
+	portfolio, err := s.Environ.QueryPortfolio(c)
+	if err != nil {
+		logrus.WithError(err).Error("portfolio query failed")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if s.Trader != nil {
+		if err := s.Trader.AddStrategyPositions(portfolio); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"portfolio": portfolio})
+}
+
+func (s *Server) getPortfolioExposure(c *gin.Context) {
+	if s.Trader == nil || s.Trader.PortfolioRisk() == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "portfolio risk is not configured"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"exposure": s.Trader.PortfolioRisk().Exposure()})
+}
+
+func (s *Server) getStrategyBudgets(c *gin.Context) {
+	if s.Trader == nil || s.Trader.StrategyBudgets() == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "strategy budgets are not configured"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"budgets": s.Trader.StrategyBudgets().Usages(time.Now())})
+}
+
 func (s *Server) setupSaveConfig(c *gin.Context) {
 	if len(s.Config.Sessions) == 0 {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "session is not configured"})
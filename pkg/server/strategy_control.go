@@ -0,0 +1,193 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/c9s/bbgo/pkg/bbgo"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+)
+
+type adjustMarginsRequest struct {
+	BidMargin fixedpoint.Value `json:"bidMargin"`
+	AskMargin fixedpoint.Value `json:"askMargin"`
+}
+
+// findStrategy finds the running strategy by the instanceID path parameter
+func (s *Server) findStrategy(c *gin.Context) (bbgo.StrategyID, bool) {
+	if s.Trader == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "trader is not running"})
+		return nil, false
+	}
+
+	instanceID := c.Param("instanceID")
+	strategy, ok := s.Trader.FindStrategy(instanceID)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("strategy instance %s not found", instanceID)})
+		return nil, false
+	}
+
+	return strategy, true
+}
+
+func (s *Server) getStrategyStatus(c *gin.Context) {
+	strategy, ok := s.findStrategy(c)
+	if !ok {
+		return
+	}
+
+	reader, ok := strategy.(bbgo.StrategyStatusReader)
+	if !ok {
+		c.JSON(http.StatusNotImplemented, gin.H{"error": "strategy does not support status reading"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": reader.GetStatus()})
+}
+
+func (s *Server) suspendStrategy(c *gin.Context) {
+	strategy, ok := s.findStrategy(c)
+	if !ok {
+		return
+	}
+
+	toggler, ok := strategy.(bbgo.StrategyToggler)
+	if !ok {
+		c.JSON(http.StatusNotImplemented, gin.H{"error": "strategy does not support suspending"})
+		return
+	}
+
+	if err := toggler.Suspend(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": toggler.GetStatus()})
+}
+
+func (s *Server) resumeStrategy(c *gin.Context) {
+	strategy, ok := s.findStrategy(c)
+	if !ok {
+		return
+	}
+
+	toggler, ok := strategy.(bbgo.StrategyToggler)
+	if !ok {
+		c.JSON(http.StatusNotImplemented, gin.H{"error": "strategy does not support resuming"})
+		return
+	}
+
+	if err := toggler.Resume(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": toggler.GetStatus()})
+}
+
+func (s *Server) flattenStrategyPosition(c *gin.Context) {
+	strategy, ok := s.findStrategy(c)
+	if !ok {
+		return
+	}
+
+	flattener, ok := strategy.(bbgo.PositionFlattener)
+	if !ok {
+		c.JSON(http.StatusNotImplemented, gin.H{"error": "strategy does not support flattening the position"})
+		return
+	}
+
+	if err := flattener.FlattenPosition(c.Request.Context()); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "position flattened"})
+}
+
+func (s *Server) windDownStrategy(c *gin.Context) {
+	strategy, ok := s.findStrategy(c)
+	if !ok {
+		return
+	}
+
+	windDowner, ok := strategy.(bbgo.WindDowner)
+	if !ok {
+		c.JSON(http.StatusNotImplemented, gin.H{"error": "strategy does not support winding down"})
+		return
+	}
+
+	if err := windDowner.WindDown(c.Request.Context()); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if reader, ok := strategy.(bbgo.StrategyStatusReader); ok {
+		c.JSON(http.StatusOK, gin.H{"status": reader.GetStatus()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "strategy is winding down"})
+}
+
+func (s *Server) getStrategyPositionLots(c *gin.Context) {
+	strategy, ok := s.findStrategy(c)
+	if !ok {
+		return
+	}
+
+	position, ok := bbgo.FindStrategyPosition(strategy)
+	if !ok {
+		c.JSON(http.StatusNotImplemented, gin.H{"error": "strategy does not have a position"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"symbol":         position.Symbol,
+		"accountingMode": position.AccountingMode,
+		"lots":           position.GetLots(),
+	})
+}
+
+func (s *Server) getStrategyQuoteDiagnostics(c *gin.Context) {
+	strategy, ok := s.findStrategy(c)
+	if !ok {
+		return
+	}
+
+	diagnoser, ok := strategy.(bbgo.QuoteDiagnoser)
+	if !ok {
+		c.JSON(http.StatusNotImplemented, gin.H{"error": "strategy does not support the quote diagnostics"})
+		return
+	}
+
+	c.JSON(http.StatusOK, diagnoser.QuoteDiagnostics())
+}
+
+func (s *Server) adjustStrategyMargins(c *gin.Context) {
+	strategy, ok := s.findStrategy(c)
+	if !ok {
+		return
+	}
+
+	adjuster, ok := strategy.(bbgo.MarginAdjuster)
+	if !ok {
+		c.JSON(http.StatusNotImplemented, gin.H{"error": "strategy does not support adjusting the margins"})
+		return
+	}
+
+	var req adjustMarginsRequest
+	if err := c.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := adjuster.AdjustMargins(req.BidMargin, req.AskMargin); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"bidMargin": req.BidMargin, "askMargin": req.AskMargin})
+}
@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// KVOrderStorePersistence persists the orders of an order store into the persistence store (redis, json or memory),
+// the orders are saved as a whole list under the store key.
+type KVOrderStorePersistence struct {
+	store Store
+
+	mu     sync.Mutex
+	orders map[uint64]types.Order
+}
+
+func NewKVOrderStorePersistence(store Store) *KVOrderStorePersistence {
+	return &KVOrderStorePersistence{
+		store:  store,
+		orders: make(map[uint64]types.Order),
+	}
+}
+
+func (p *KVOrderStorePersistence) LoadOrders(ctx context.Context) ([]types.Order, error) {
+	var orders []types.Order
+	if err := p.store.Load(&orders); err != nil {
+		if err == ErrPersistenceNotExists {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.orders = make(map[uint64]types.Order, len(orders))
+	for _, o := range orders {
+		p.orders[o.OrderID] = o
+	}
+
+	return orders, nil
+}
+
+func (p *KVOrderStorePersistence) SaveOrder(ctx context.Context, order types.Order) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.orders[order.OrderID] = order
+	return p.save()
+}
+
+func (p *KVOrderStorePersistence) RemoveOrder(ctx context.Context, order types.Order) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, ok := p.orders[order.OrderID]; !ok {
+		return nil
+	}
+
+	delete(p.orders, order.OrderID)
+	return p.save()
+}
+
+func (p *KVOrderStorePersistence) save() error {
+	orders := make([]types.Order, 0, len(p.orders))
+	for _, o := range p.orders {
+		orders = append(orders, o)
+	}
+
+	sort.Slice(orders, func(i, j int) bool {
+		return orders[i].OrderID < orders[j].OrderID
+	})
+
+	return p.store.Save(orders)
+}
+
+// SQLOrderStorePersistence persists the orders of an order store into the order_store_orders table,
+// the orders are partitioned by the store ID, so that multiple order stores can share the same table.
+type SQLOrderStorePersistence struct {
+	DB      *sqlx.DB
+	StoreID string
+}
+
+func NewSQLOrderStorePersistence(db *sqlx.DB, storeID string) *SQLOrderStorePersistence {
+	return &SQLOrderStorePersistence{
+		DB:      db,
+		StoreID: storeID,
+	}
+}
+
+type orderStoreRecord struct {
+	StoreID   string            `db:"store_id"`
+	Exchange  string            `db:"exchange"`
+	OrderID   uint64            `db:"order_id"`
+	Status    types.OrderStatus `db:"status"`
+	Data      string            `db:"data"`
+	UpdatedAt time.Time         `db:"updated_at"`
+}
+
+func (p *SQLOrderStorePersistence) LoadOrders(ctx context.Context) ([]types.Order, error) {
+	rows, err := p.DB.QueryxContext(ctx,
+		"SELECT order_id, data FROM order_store_orders WHERE store_id = ? ORDER BY order_id ASC", p.StoreID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var orders []types.Order
+	for rows.Next() {
+		var orderID uint64
+		var data string
+		if err := rows.Scan(&orderID, &data); err != nil {
+			return orders, err
+		}
+
+		var order types.Order
+		if err := json.Unmarshal([]byte(data), &order); err != nil {
+			return orders, errors.Wrapf(err, "unable to decode the order %d", orderID)
+		}
+
+		orders = append(orders, order)
+	}
+
+	return orders, rows.Err()
+}
+
+func (p *SQLOrderStorePersistence) SaveOrder(ctx context.Context, order types.Order) error {
+	data, err := json.Marshal(order)
+	if err != nil {
+		return err
+	}
+
+	updatedAt := order.UpdateTime.Time()
+	if updatedAt.IsZero() {
+		updatedAt = time.Now()
+	}
+
+	record := orderStoreRecord{
+		StoreID:   p.StoreID,
+		Exchange:  order.Exchange.String(),
+		OrderID:   order.OrderID,
+		Status:    order.Status,
+		Data:      string(data),
+		UpdatedAt: updatedAt,
+	}
+
+	if p.DB.DriverName() == "mysql" {
+		_, err = p.DB.NamedExecContext(ctx, `
+			INSERT INTO order_store_orders (store_id, exchange, order_id, status, data, updated_at)
+			VALUES (:store_id, :exchange, :order_id, :status, :data, :updated_at)
+			ON DUPLICATE KEY UPDATE status=:status, data=:data, updated_at=:updated_at`, record)
+		return err
+	}
+
+	_, err = p.DB.NamedExecContext(ctx, `
+			INSERT INTO order_store_orders (store_id, exchange, order_id, status, data, updated_at)
+			VALUES (:store_id, :exchange, :order_id, :status, :data, :updated_at)
+			ON CONFLICT (store_id, exchange, order_id) DO UPDATE SET status=excluded.status, data=excluded.data, updated_at=excluded.updated_at`, record)
+	return err
+}
+
+func (p *SQLOrderStorePersistence) RemoveOrder(ctx context.Context, order types.Order) error {
+	_, err := p.DB.ExecContext(ctx,
+		"DELETE FROM order_store_orders WHERE store_id = ? AND exchange = ? AND order_id = ?",
+		p.StoreID, order.Exchange.String(), order.OrderID)
+	return err
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func newTestStoreOrder(orderID uint64, status types.OrderStatus) types.Order {
+	return types.Order{
+		SubmitOrder: types.SubmitOrder{
+			Symbol:   "BTCUSDT",
+			Side:     types.SideTypeBuy,
+			Type:     types.OrderTypeLimit,
+			Quantity: fixedpoint.NewFromFloat(0.1),
+			Price:    fixedpoint.NewFromFloat(40000),
+			Tag:      "grid",
+		},
+		Exchange:   types.ExchangeBinance,
+		OrderID:    orderID,
+		Status:     status,
+		UpdateTime: types.Time(time.Now()),
+	}
+}
+
+func TestKVOrderStorePersistence(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryService().NewStore("orderstore", "BTCUSDT")
+
+	p := NewKVOrderStorePersistence(store)
+	orders, err := p.LoadOrders(ctx)
+	assert.NoError(t, err)
+	assert.Empty(t, orders)
+
+	assert.NoError(t, p.SaveOrder(ctx, newTestStoreOrder(1, types.OrderStatusNew)))
+	assert.NoError(t, p.SaveOrder(ctx, newTestStoreOrder(2, types.OrderStatusNew)))
+	assert.NoError(t, p.SaveOrder(ctx, newTestStoreOrder(1, types.OrderStatusFilled)))
+	assert.NoError(t, p.RemoveOrder(ctx, newTestStoreOrder(2, types.OrderStatusCanceled)))
+
+	// simulate the restart
+	p2 := NewKVOrderStorePersistence(store)
+	orders, err = p2.LoadOrders(ctx)
+	assert.NoError(t, err)
+	if assert.Len(t, orders, 1) {
+		assert.Equal(t, uint64(1), orders[0].OrderID)
+		assert.Equal(t, types.OrderStatusFilled, orders[0].Status)
+		assert.Equal(t, "grid", orders[0].Tag)
+	}
+}
+
+func TestSQLOrderStorePersistence(t *testing.T) {
+	db, err := prepareDB(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		err := db.Close()
+		assert.NoError(t, err)
+	}()
+
+	ctx := context.Background()
+	xdb := sqlx.NewDb(db.DB, "sqlite3")
+	p := NewSQLOrderStorePersistence(xdb, "grid2:BTCUSDT")
+	other := NewSQLOrderStorePersistence(xdb, "grid2:ETHUSDT")
+
+	assert.NoError(t, p.SaveOrder(ctx, newTestStoreOrder(1, types.OrderStatusNew)))
+	assert.NoError(t, p.SaveOrder(ctx, newTestStoreOrder(2, types.OrderStatusNew)))
+	assert.NoError(t, p.SaveOrder(ctx, newTestStoreOrder(2, types.OrderStatusPartiallyFilled)))
+	assert.NoError(t, p.SaveOrder(ctx, newTestStoreOrder(3, types.OrderStatusNew)))
+	assert.NoError(t, p.RemoveOrder(ctx, newTestStoreOrder(3, types.OrderStatusCanceled)))
+	assert.NoError(t, other.SaveOrder(ctx, newTestStoreOrder(4, types.OrderStatusNew)))
+
+	orders, err := p.LoadOrders(ctx)
+	assert.NoError(t, err)
+	if assert.Len(t, orders, 2) {
+		assert.Equal(t, uint64(1), orders[0].OrderID)
+		assert.Equal(t, uint64(2), orders[1].OrderID)
+		assert.Equal(t, types.OrderStatusPartiallyFilled, orders[1].Status)
+		assert.Equal(t, "40000", orders[1].Price.String())
+	}
+
+	orders, err = other.LoadOrders(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, orders, 1)
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+type RegimeStatsService struct {
+	DB *sqlx.DB
+}
+
+func NewRegimeStatsService(db *sqlx.DB) *RegimeStatsService {
+	return &RegimeStatsService{DB: db}
+}
+
+func (s *RegimeStatsService) Insert(stats types.RegimeStats) error {
+	_, err := s.DB.NamedExec(`
+		INSERT INTO regime_stats (
+			strategy,
+			strategy_instance_id,
+			exchange,
+			symbol,
+			volatility_regime,
+			trend_regime,
+			start_time,
+			end_time,
+			num_orders,
+			num_fills,
+			quoted_volume,
+			filled_volume,
+			fill_ratio,
+			profit,
+			net_profit,
+			num_markouts,
+			average_markout
+		) VALUES (
+			:strategy,
+			:strategy_instance_id,
+			:exchange,
+			:symbol,
+			:volatility_regime,
+			:trend_regime,
+			:start_time,
+			:end_time,
+			:num_orders,
+			:num_fills,
+			:quoted_volume,
+			:filled_volume,
+			:fill_ratio,
+			:profit,
+			:net_profit,
+			:num_markouts,
+			:average_markout
+		)`,
+		map[string]interface{}{
+			"strategy":             stats.Strategy,
+			"strategy_instance_id": stats.StrategyInstanceID,
+			"exchange":             stats.Exchange.String(),
+			"symbol":               stats.Symbol,
+			"volatility_regime":    stats.VolatilityRegime,
+			"trend_regime":         stats.TrendRegime,
+			"start_time":           stats.StartTime,
+			"end_time":             stats.EndTime,
+			"num_orders":           stats.NumOrders,
+			"num_fills":            stats.NumFills,
+			"quoted_volume":        stats.QuotedVolume,
+			"filled_volume":        stats.FilledVolume,
+			"fill_ratio":           stats.FillRatio,
+			"profit":               stats.Profit,
+			"net_profit":           stats.NetProfit,
+			"num_markouts":         stats.NumMarkouts,
+			"average_markout":      stats.AverageMarkout,
+		})
+	return err
+}
+
+// Query returns the regime stats of the strategy instance that start since the given time
+func (s *RegimeStatsService) Query(ctx context.Context, strategyInstanceID string, since time.Time) ([]types.RegimeStats, error) {
+	rows, err := s.DB.NamedQueryContext(ctx, `
+		SELECT * FROM regime_stats
+		WHERE strategy_instance_id = :strategy_instance_id AND start_time >= :since
+		ORDER BY start_time ASC, gid ASC`,
+		map[string]interface{}{
+			"strategy_instance_id": strategyInstanceID,
+			"since":                since,
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var records []types.RegimeStats
+	for rows.Next() {
+		var record types.RegimeStats
+		if err := rows.StructScan(&record); err != nil {
+			return records, err
+		}
+
+		records = append(records, record)
+	}
+
+	return records, rows.Err()
+}
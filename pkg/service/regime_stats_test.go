@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestRegimeStatsService(t *testing.T) {
+	db, err := prepareDB(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		err := db.Close()
+		assert.NoError(t, err)
+	}()
+
+	xdb := sqlx.NewDb(db.DB, "sqlite3")
+	service := &RegimeStatsService{DB: xdb}
+
+	startTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 2; i++ {
+		err = service.Insert(types.RegimeStats{
+			Strategy:           "xmaker",
+			StrategyInstanceID: "xmaker-BTCUSDT",
+			Exchange:           types.ExchangeMax,
+			Symbol:             "BTCUSDT",
+			VolatilityRegime:   "high",
+			TrendRegime:        "uptrend",
+			StartTime:          types.Time(startTime.Add(time.Duration(i) * time.Hour)),
+			EndTime:            types.Time(startTime.Add(time.Duration(i+1) * time.Hour)),
+			NumOrders:          10,
+			NumFills:           2,
+			QuotedVolume:       fixedpoint.NewFromFloat(10.0),
+			FilledVolume:       fixedpoint.NewFromFloat(2.5),
+			FillRatio:          fixedpoint.NewFromFloat(0.25),
+			Profit:             fixedpoint.NewFromFloat(12.3),
+			NetProfit:          fixedpoint.NewFromFloat(11.1),
+			NumMarkouts:        2,
+			AverageMarkout:     fixedpoint.NewFromFloat(-0.0005),
+		})
+		assert.NoError(t, err)
+	}
+
+	records, err := service.Query(context.Background(), "xmaker-BTCUSDT", startTime.Add(time.Hour))
+	if assert.NoError(t, err) && assert.Len(t, records, 1) {
+		assert.Equal(t, "high-uptrend", records[0].Regime())
+		assert.Equal(t, types.ExchangeMax, records[0].Exchange)
+		assert.Equal(t, "0.25", records[0].FillRatio.String())
+		assert.Equal(t, "-0.0005", records[0].AverageMarkout.String())
+	}
+}
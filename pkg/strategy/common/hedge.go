@@ -0,0 +1,583 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/time/rate"
+
+	"github.com/c9s/bbgo/pkg/bbgo"
+	"github.com/c9s/bbgo/pkg/core"
+	"github.com/c9s/bbgo/pkg/exchange/retry"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// HedgeExecutor hedges the uncovered position of the cross-exchange strategies on the hedge market.
+//
+// The quantity is the position to trade, the positive quantity buys and the negative quantity sells,
+// e.g. the uncovered position +1 BTC is hedged by the quantity -1.
+// Hedge returns the covered position of the submitted orders, which is the negated submitted quantity,
+// e.g. +1 for selling 1 BTC, so that the caller can add it to its covered position.
+type HedgeExecutor interface {
+	Hedge(ctx context.Context, quantity fixedpoint.Value) (covered fixedpoint.Value, err error)
+}
+
+// AsyncHedgeExecutor executes the hedge in the background, the whole quantity is covered when the hedge is started.
+type AsyncHedgeExecutor interface {
+	HedgeExecutor
+
+	// Update checks the running hedge, the hedge is canceled if the uncovered position is reversed.
+	// It returns false if there is no running hedge, and the covered position of the unfilled quantity
+	// to be deducted when the hedge is finished.
+	Update(ctx context.Context, uncoveredPosition fixedpoint.Value) (running bool, uncovered fixedpoint.Value)
+
+	// Shutdown cancels the running hedge
+	Shutdown(ctx context.Context)
+}
+
+// ErrHedgeRateLimited is returned when the hedge is rate limited by the previous submit errors
+var ErrHedgeRateLimited = errors.New("hedge is rate limited by the previous errors")
+
+// lastPriceModifier adjusts the price higher by 0.1%, so that the buy quantity limited by the quote balance can be executed
+var lastPriceModifier = fixedpoint.NewFromFloat(1.001)
+
+// minGap is the ratio of the minimal notional and quantity that the hedge quantity should be greater than
+var minGap = fixedpoint.NewFromFloat(1.02)
+
+// HedgeMarket is the market of the hedge session that the hedge executors submit orders to
+type HedgeMarket struct {
+	Session *bbgo.ExchangeSession
+	Market  types.Market
+
+	// OrderExecutor submits and cancels the hedge orders,
+	// the bbgo.ExchangeOrderExecutor of the session is used if it's not set.
+	OrderExecutor bbgo.OrderExecutor
+
+	// OrderStore stores the submitted hedge orders if it's set
+	OrderStore *core.OrderStore
+
+	// BestPrices returns the best bid and ask price of the hedge market, the zero price means the price is not available
+	BestPrices func() (bid, ask fixedpoint.Value)
+
+	// SelfTradePreventionMode is set on the submitted hedge orders if it's not empty
+	SelfTradePreventionMode types.SelfTradePreventionMode
+}
+
+func (m *HedgeMarket) orderExecutor() bbgo.OrderExecutor {
+	if m.OrderExecutor != nil {
+		return m.OrderExecutor
+	}
+
+	return &bbgo.ExchangeOrderExecutor{Session: m.Session}
+}
+
+// TakerPrice returns the price that the market order of the side is executed at
+func (m *HedgeMarket) TakerPrice(side types.SideType) fixedpoint.Value {
+	bid, ask := m.BestPrices()
+	if side == types.SideTypeBuy {
+		return ask
+	}
+
+	return bid
+}
+
+// hedgeContext returns the context of the hedge orders,
+// the hedge orders preempt the quote refreshes in the order rate budget of the session
+func hedgeContext(ctx context.Context) context.Context {
+	return bbgo.WithOrderPriority(ctx, bbgo.OrderPriorityHigh)
+}
+
+func (m *HedgeMarket) submitOrders(ctx context.Context, orders ...types.SubmitOrder) (types.OrderSlice, error) {
+	ctx = hedgeContext(ctx)
+
+	if len(m.SelfTradePreventionMode) > 0 {
+		for i := range orders {
+			orders[i].SelfTradePreventionMode = m.SelfTradePreventionMode
+		}
+	}
+
+	createdOrders, err := m.orderExecutor().SubmitOrders(ctx, orders...)
+	if m.OrderStore != nil && len(createdOrders) > 0 {
+		m.OrderStore.Add(createdOrders...)
+	}
+
+	return createdOrders, err
+}
+
+// coveredPosition returns the covered position of the side and the quantity,
+// the selling adds the positive position and the buying adds the negative position.
+func coveredPosition(side types.SideType, quantity fixedpoint.Value) fixedpoint.Value {
+	if side == types.SideTypeSell {
+		return quantity
+	}
+
+	return quantity.Neg()
+}
+
+func quantitySide(quantity fixedpoint.Value) types.SideType {
+	if quantity.Sign() < 0 {
+		return types.SideTypeSell
+	}
+
+	return types.SideTypeBuy
+}
+
+// MarketOrderHedgeExecutor hedges the position by the market orders,
+// the quantity is adjusted by the balances of the hedge session.
+type MarketOrderHedgeExecutor struct {
+	*HedgeMarket
+
+	// BeforeSubmit is called before the quantity is adjusted by the balances, e.g. for borrowing the insufficient asset
+	BeforeSubmit func(ctx context.Context, side types.SideType, quantity, price fixedpoint.Value)
+
+	mu               sync.Mutex
+	errorLimiter     *rate.Limiter
+	errorReservation *rate.Reservation
+}
+
+func NewMarketOrderHedgeExecutor(market *HedgeMarket) *MarketOrderHedgeExecutor {
+	return &MarketOrderHedgeExecutor{
+		HedgeMarket:  market,
+		errorLimiter: rate.NewLimiter(rate.Every(1*time.Minute), 1),
+	}
+}
+
+// AdjustQuantity adjusts the hedge quantity by the available balance of the hedge session,
+// zero is returned if the adjusted quantity is less than the minimal notional or the minimal quantity.
+func (e *MarketOrderHedgeExecutor) AdjustQuantity(side types.SideType, quantity, price fixedpoint.Value) fixedpoint.Value {
+	market := e.Market
+	notional := quantity.Mul(price)
+
+	account := e.Session.GetAccount()
+	switch side {
+
+	case types.SideTypeBuy:
+		if quote, ok := account.Balance(market.QuoteCurrency); ok {
+			if quote.Available.Compare(notional) < 0 {
+				quantity = bbgo.AdjustQuantityByMaxAmount(quantity, price.Mul(lastPriceModifier), quote.Available)
+			}
+		}
+
+	case types.SideTypeSell:
+		if base, ok := account.Balance(market.BaseCurrency); ok {
+			if base.Available.Compare(quantity) < 0 {
+				quantity = base.Available
+			}
+		}
+	}
+
+	// truncate quantity for the supported precision
+	quantity = market.TruncateQuantity(quantity)
+
+	if notional.Compare(market.MinNotional.Mul(minGap)) <= 0 {
+		log.Warnf("the adjusted amount %v is less than minimal notional %v, skipping hedge", notional, market.MinNotional)
+		return fixedpoint.Zero
+	}
+
+	if quantity.Compare(market.MinQuantity.Mul(minGap)) <= 0 {
+		log.Warnf("the adjusted quantity %v is less than minimal quantity %v, skipping hedge", quantity, market.MinQuantity)
+		return fixedpoint.Zero
+	}
+
+	return quantity
+}
+
+// Hedge submits the market order of the quantity, the order is skipped if its notional is too small.
+func (e *MarketOrderHedgeExecutor) Hedge(ctx context.Context, quantity fixedpoint.Value) (fixedpoint.Value, error) {
+	if quantity.IsZero() {
+		return fixedpoint.Zero, nil
+	}
+
+	side := quantitySide(quantity)
+	quantity = quantity.Abs()
+
+	price := e.TakerPrice(side)
+	notional := quantity.Mul(price)
+	if notional.Compare(e.Market.MinNotional) <= 0 {
+		log.Warnf("%s %v less than min notional, skipping hedge", e.Market.Symbol, notional)
+		return fixedpoint.Zero, nil
+	}
+
+	if e.BeforeSubmit != nil {
+		e.BeforeSubmit(ctx, side, quantity, price)
+	}
+
+	quantity = e.AdjustQuantity(side, quantity, price)
+	if quantity.IsZero() {
+		return fixedpoint.Zero, nil
+	}
+
+	if _, err := e.SubmitOrder(ctx, side, quantity); err != nil {
+		return fixedpoint.Zero, err
+	}
+
+	return coveredPosition(side, quantity), nil
+}
+
+// SubmitOrder submits the market order without adjusting the quantity,
+// the submission is rate limited after an error is returned.
+func (e *MarketOrderHedgeExecutor) SubmitOrder(ctx context.Context, side types.SideType, quantity fixedpoint.Value) (types.OrderSlice, error) {
+	if err := e.waitErrorRateLimit(); err != nil {
+		return nil, err
+	}
+
+	log.Infof("submitting %s hedge order %s %v to %s", e.Market.Symbol, side.String(), quantity, e.Session.Name)
+	bbgo.Notify("Submitting %s hedge order %s %v to %s", e.Market.Symbol, side.String(), quantity, e.Session.Name)
+
+	createdOrders, err := e.submitOrders(ctx, types.SubmitOrder{
+		Market:   e.Market,
+		Symbol:   e.Market.Symbol,
+		Type:     types.OrderTypeMarket,
+		Side:     side,
+		Quantity: quantity,
+	})
+
+	if err != nil {
+		e.mu.Lock()
+		e.errorReservation = e.errorLimiter.Reserve()
+		e.mu.Unlock()
+
+		log.WithError(err).Errorf("market order submit error: %s", err.Error())
+		return nil, err
+	}
+
+	return createdOrders, nil
+}
+
+func (e *MarketOrderHedgeExecutor) waitErrorRateLimit() error {
+	e.mu.Lock()
+	reservation := e.errorReservation
+	e.mu.Unlock()
+
+	if reservation == nil {
+		return nil
+	}
+
+	if !reservation.OK() {
+		return ErrHedgeRateLimited
+	}
+
+	bbgo.Notify("Hit hedge error rate limit, waiting...")
+	time.Sleep(reservation.Delay())
+
+	e.mu.Lock()
+	e.errorReservation = nil
+	e.mu.Unlock()
+	return nil
+}
+
+// LimitChasingHedgeConfig configures the limit order hedge that chases the best price
+type LimitChasingHedgeConfig struct {
+	// UpdateInterval is the interval of repricing the limit order, defaults to 2s
+	UpdateInterval types.Duration `json:"updateInterval"`
+
+	// MaxChases is the maximal number of the repricing, the remaining quantity is hedged by the market order after that,
+	// defaults to 5.
+	MaxChases int `json:"maxChases"`
+
+	// NumOfTicks is the number of ticks to improve the best price, the price doesn't cross the opposite best price.
+	NumOfTicks int `json:"numOfTicks"`
+}
+
+func (c *LimitChasingHedgeConfig) Defaults() {
+	if c.UpdateInterval == 0 {
+		c.UpdateInterval = types.Duration(2 * time.Second)
+	}
+
+	if c.MaxChases == 0 {
+		c.MaxChases = 5
+	}
+}
+
+// LimitChasingHedgeExecutor hedges the position by the limit order at the best price of the same side,
+// the unfilled order is canceled and re-placed at the new best price on each update,
+// and the remaining quantity is hedged by the market order when the chases are exhausted.
+type LimitChasingHedgeExecutor struct {
+	*MarketOrderHedgeExecutor
+
+	Config LimitChasingHedgeConfig
+
+	// queryOrder queries the canceled order for the executed quantity
+	queryOrder func(ctx context.Context, order types.Order) (*types.Order, error)
+}
+
+func NewLimitChasingHedgeExecutor(market *HedgeMarket, config LimitChasingHedgeConfig) *LimitChasingHedgeExecutor {
+	config.Defaults()
+
+	e := &LimitChasingHedgeExecutor{
+		MarketOrderHedgeExecutor: NewMarketOrderHedgeExecutor(market),
+		Config:                   config,
+	}
+	e.queryOrder = e.queryOrderFromExchange
+	return e
+}
+
+func (e *LimitChasingHedgeExecutor) queryOrderFromExchange(ctx context.Context, order types.Order) (*types.Order, error) {
+	service, ok := e.Session.Exchange.(types.ExchangeOrderQueryService)
+	if !ok {
+		return nil, errors.New("the hedge session does not support querying orders")
+	}
+
+	return retry.QueryOrderUntilSuccessful(ctx, service, types.OrderQuery{
+		Symbol:        order.Symbol,
+		OrderID:       strconv.FormatUint(order.OrderID, 10),
+		ClientOrderID: order.ClientOrderID,
+	})
+}
+
+// chasePrice returns the best price of the side improved by the ticks, the price doesn't cross the opposite best price
+func (e *LimitChasingHedgeExecutor) chasePrice(side types.SideType) (fixedpoint.Value, bool) {
+	bid, ask := e.BestPrices()
+	if bid.IsZero() || ask.IsZero() {
+		return fixedpoint.Zero, false
+	}
+
+	ticks := e.Market.TickSize.Mul(fixedpoint.NewFromInt(int64(e.Config.NumOfTicks)))
+	if side == types.SideTypeBuy {
+		price := bid.Add(ticks)
+		if price.Compare(ask) >= 0 {
+			price = bid
+		}
+		return price, true
+	}
+
+	price := ask.Sub(ticks)
+	if price.Compare(bid) <= 0 {
+		price = ask
+	}
+	return price, true
+}
+
+func (e *LimitChasingHedgeExecutor) Hedge(ctx context.Context, quantity fixedpoint.Value) (fixedpoint.Value, error) {
+	if quantity.IsZero() {
+		return fixedpoint.Zero, nil
+	}
+
+	side := quantitySide(quantity)
+	remaining := e.AdjustQuantity(side, quantity.Abs(), e.TakerPrice(side))
+	if remaining.IsZero() {
+		return fixedpoint.Zero, nil
+	}
+
+	filled := fixedpoint.Zero
+	for i := 0; i < e.Config.MaxChases; i++ {
+		price, ok := e.chasePrice(side)
+		if !ok {
+			break
+		}
+
+		executed, err := e.chase(ctx, side, remaining, price)
+		filled = filled.Add(executed)
+		remaining = e.Market.TruncateQuantity(remaining.Sub(executed))
+		if err != nil {
+			return coveredPosition(side, filled), err
+		}
+
+		if remaining.Compare(e.Market.MinQuantity) < 0 || e.Market.IsDustQuantity(remaining, price) {
+			return coveredPosition(side, filled), nil
+		}
+	}
+
+	log.Infof("%s limit chasing hedge %s filled %v, hedging the remaining %v by the market order", e.Market.Symbol, side, filled, remaining)
+
+	covered, err := e.MarketOrderHedgeExecutor.Hedge(ctx, coveredPosition(side, remaining).Neg())
+	return coveredPosition(side, filled).Add(covered), err
+}
+
+// chase places the limit order and waits for the update interval, the order is canceled if it's not filled,
+// and it returns the executed quantity of the order.
+func (e *LimitChasingHedgeExecutor) chase(ctx context.Context, side types.SideType, quantity, price fixedpoint.Value) (fixedpoint.Value, error) {
+	createdOrders, err := e.submitOrders(ctx, types.SubmitOrder{
+		Market:      e.Market,
+		Symbol:      e.Market.Symbol,
+		Type:        types.OrderTypeLimit,
+		Side:        side,
+		Price:       price,
+		Quantity:    quantity,
+		TimeInForce: types.TimeInForceGTC,
+	})
+	if err != nil {
+		return fixedpoint.Zero, err
+	}
+
+	if len(createdOrders) == 0 {
+		return fixedpoint.Zero, errors.New("no hedge order is created")
+	}
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(e.Config.UpdateInterval.Duration()):
+	}
+
+	order := createdOrders[0]
+	if err := e.orderExecutor().CancelOrders(context.Background(), order); err != nil {
+		log.WithError(err).Warnf("unable to cancel the limit chasing hedge order %s", order.String())
+	}
+
+	updatedOrder, err := e.queryOrder(context.Background(), order)
+	if err != nil {
+		return fixedpoint.Zero, err
+	}
+
+	if updatedOrder.IsWorking {
+		return updatedOrder.ExecutedQuantity, errors.New("the limit chasing hedge order is not canceled")
+	}
+
+	return updatedOrder.ExecutedQuantity, nil
+}
+
+// TWAPHedgeConfig configures the sliced hedge executed over a duration
+type TWAPHedgeConfig struct {
+	// Duration is the duration of the whole TWAP hedge execution
+	Duration types.Duration `json:"duration"`
+
+	// NumOfSlices is the number of the child orders
+	NumOfSlices int `json:"numOfSlices"`
+
+	// UpdateInterval is the interval of updating the child order price
+	UpdateInterval types.Duration `json:"updateInterval"`
+
+	// NumOfTicks is the number of ticks to improve the best price
+	NumOfTicks int `json:"numOfTicks"`
+}
+
+func (c *TWAPHedgeConfig) Defaults() {
+	if c.Duration == 0 {
+		c.Duration = types.Duration(5 * time.Minute)
+	}
+
+	if c.NumOfSlices == 0 {
+		c.NumOfSlices = 10
+	}
+
+	if c.UpdateInterval == 0 {
+		c.UpdateInterval = types.Duration(10 * time.Second)
+	}
+}
+
+// TWAPHedgeExecutor hedges the position by the TWAP execution in the background,
+// the hedge quantity is covered until the execution is done.
+type TWAPHedgeExecutor struct {
+	*HedgeMarket
+
+	Config TWAPHedgeConfig
+
+	mu        sync.Mutex
+	execution *bbgo.TwapExecution
+	side      types.SideType
+	quantity  fixedpoint.Value
+	startTime time.Time
+}
+
+func NewTWAPHedgeExecutor(market *HedgeMarket, config TWAPHedgeConfig) *TWAPHedgeExecutor {
+	config.Defaults()
+	return &TWAPHedgeExecutor{
+		HedgeMarket: market,
+		Config:      config,
+	}
+}
+
+func (e *TWAPHedgeExecutor) Hedge(ctx context.Context, quantity fixedpoint.Value) (fixedpoint.Value, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.execution != nil {
+		return fixedpoint.Zero, errors.New("the twap hedge is still running")
+	}
+
+	side := quantitySide(quantity)
+	quantity = e.Market.TruncateQuantity(quantity.Abs())
+	if quantity.IsZero() {
+		return fixedpoint.Zero, nil
+	}
+
+	sliceQuantity := e.Market.TruncateQuantity(quantity.Div(fixedpoint.NewFromInt(int64(e.Config.NumOfSlices))))
+	if sliceQuantity.Compare(e.Market.MinQuantity) < 0 {
+		sliceQuantity = e.Market.MinQuantity
+	}
+
+	execution := &bbgo.TwapExecution{
+		Session:        e.Session,
+		Symbol:         e.Market.Symbol,
+		Side:           side,
+		TargetQuantity: quantity,
+		SliceQuantity:  sliceQuantity,
+		NumOfTicks:     e.Config.NumOfTicks,
+		UpdateInterval: e.Config.UpdateInterval.Duration(),
+		DeadlineTime:   time.Now().Add(e.Config.Duration.Duration()),
+		OrderStore:     e.OrderStore,
+	}
+
+	if err := execution.Run(hedgeContext(ctx)); err != nil {
+		return fixedpoint.Zero, err
+	}
+
+	e.execution = execution
+	e.side = side
+	e.quantity = quantity
+	e.startTime = time.Now()
+
+	log.Infof("started %s twap hedge %s %v on %s", e.Market.Symbol, side, quantity, e.Session.Name)
+	bbgo.Notify("Started %s TWAP hedge %s %v on %s", e.Market.Symbol, side, quantity, e.Session.Name)
+	return coveredPosition(side, quantity), nil
+}
+
+func (e *TWAPHedgeExecutor) Update(ctx context.Context, uncoveredPosition fixedpoint.Value) (bool, fixedpoint.Value) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.execution == nil {
+		return false, fixedpoint.Zero
+	}
+
+	select {
+	case <-e.execution.Done():
+		return false, e.finish()
+	default:
+	}
+
+	// uncoveredPosition > 0 means we need to sell, if we are buying, the position is reversed
+	minQuantity := e.Market.MinQuantity
+	reversed := (e.side == types.SideTypeBuy && uncoveredPosition.Compare(minQuantity) > 0) ||
+		(e.side == types.SideTypeSell && uncoveredPosition.Neg().Compare(minQuantity) > 0)
+
+	if reversed {
+		log.Warnf("%s uncovered position %v is reversed, canceling the twap hedge", e.Market.Symbol, uncoveredPosition)
+
+		shutdownCtx, cancelShutdown := context.WithTimeout(ctx, 30*time.Second)
+		e.execution.Shutdown(shutdownCtx)
+		cancelShutdown()
+
+		return false, e.finish()
+	}
+
+	log.Infof("%s twap hedge %s progress: %v / %v, elapsed %s",
+		e.Market.Symbol, e.side, e.execution.FilledQuantity(), e.quantity, time.Since(e.startTime))
+	return true, fixedpoint.Zero
+}
+
+// finish returns the covered position of the unfilled quantity
+func (e *TWAPHedgeExecutor) finish() fixedpoint.Value {
+	filled := e.execution.FilledQuantity()
+	remaining := fixedpoint.Max(e.quantity.Sub(filled), fixedpoint.Zero)
+	e.execution = nil
+
+	log.Infof("%s twap hedge %s finished: %v / %v", e.Market.Symbol, e.side, filled, e.quantity)
+	bbgo.Notify("%s TWAP hedge %s finished: %v / %v on %s", e.Market.Symbol, e.side, filled, e.quantity, e.Session.Name)
+	return coveredPosition(e.side, remaining)
+}
+
+func (e *TWAPHedgeExecutor) Shutdown(ctx context.Context) {
+	e.mu.Lock()
+	execution := e.execution
+	e.mu.Unlock()
+
+	if execution != nil {
+		execution.Shutdown(ctx)
+	}
+}
@@ -0,0 +1,145 @@
+package common
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/c9s/bbgo/pkg/bbgo"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+	"github.com/c9s/bbgo/pkg/types/mocks"
+)
+
+func TestCoveredPosition(t *testing.T) {
+	assert.Equal(t, "1.5", coveredPosition(types.SideTypeSell, fixedpoint.NewFromFloat(1.5)).String())
+	assert.Equal(t, "-1.5", coveredPosition(types.SideTypeBuy, fixedpoint.NewFromFloat(1.5)).String())
+	assert.Equal(t, types.SideTypeSell, quantitySide(fixedpoint.NewFromFloat(-1.0)))
+	assert.Equal(t, types.SideTypeBuy, quantitySide(fixedpoint.NewFromFloat(1.0)))
+}
+
+func TestLimitChasingHedgeExecutor_chasePrice(t *testing.T) {
+	bid, ask := fixedpoint.NewFromFloat(100.0), fixedpoint.NewFromFloat(100.5)
+	market := &HedgeMarket{
+		Market: types.Market{Symbol: "BTCUSDT", TickSize: fixedpoint.NewFromFloat(0.1)},
+		BestPrices: func() (fixedpoint.Value, fixedpoint.Value) {
+			return bid, ask
+		},
+	}
+
+	executor := NewLimitChasingHedgeExecutor(market, LimitChasingHedgeConfig{NumOfTicks: 2})
+
+	price, ok := executor.chasePrice(types.SideTypeBuy)
+	assert.True(t, ok)
+	assert.Equal(t, "100.2", price.String())
+
+	price, ok = executor.chasePrice(types.SideTypeSell)
+	assert.True(t, ok)
+	assert.Equal(t, "100.3", price.String())
+
+	// the improved price crosses the opposite side
+	executor.Config.NumOfTicks = 10
+	price, ok = executor.chasePrice(types.SideTypeBuy)
+	assert.True(t, ok)
+	assert.Equal(t, "100", price.String())
+
+	price, ok = executor.chasePrice(types.SideTypeSell)
+	assert.True(t, ok)
+	assert.Equal(t, "100.5", price.String())
+
+	bid = fixedpoint.Zero
+	_, ok = executor.chasePrice(types.SideTypeBuy)
+	assert.False(t, ok)
+}
+
+// priorityOrderExecutor records the order priority of the submitted orders
+type priorityOrderExecutor struct {
+	priorities []bbgo.OrderPriority
+}
+
+func (e *priorityOrderExecutor) SubmitOrders(ctx context.Context, orders ...types.SubmitOrder) (types.OrderSlice, error) {
+	e.priorities = append(e.priorities, bbgo.OrderPriorityFromContext(ctx))
+
+	var createdOrders types.OrderSlice
+	for _, o := range orders {
+		createdOrders = append(createdOrders, types.Order{SubmitOrder: o})
+	}
+
+	return createdOrders, nil
+}
+
+func (e *priorityOrderExecutor) CancelOrders(ctx context.Context, orders ...types.Order) error {
+	return nil
+}
+
+func TestMarketOrderHedgeExecutor_SubmitOrder_Priority(t *testing.T) {
+	orderExecutor := &priorityOrderExecutor{}
+	executor := NewMarketOrderHedgeExecutor(&HedgeMarket{
+		Session:       &bbgo.ExchangeSession{Name: "binance"},
+		Market:        types.Market{Symbol: "BTCUSDT"},
+		OrderExecutor: orderExecutor,
+	})
+
+	_, err := executor.SubmitOrder(context.Background(), types.SideTypeSell, fixedpoint.NewFromFloat(0.1))
+	assert.NoError(t, err)
+
+	// the hedge orders are always submitted in the high priority
+	_, err = executor.SubmitOrder(bbgo.WithOrderPriority(context.Background(), bbgo.OrderPriorityLow), types.SideTypeBuy, fixedpoint.NewFromFloat(0.1))
+	assert.NoError(t, err)
+
+	assert.Equal(t, []bbgo.OrderPriority{bbgo.OrderPriorityHigh, bbgo.OrderPriorityHigh}, orderExecutor.priorities)
+}
+
+func TestTWAPHedgeExecutor_Hedge_SliceQuantity(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	market := types.Market{
+		Symbol:        "BTCUSDT",
+		BaseCurrency:  "BTC",
+		QuoteCurrency: "USDT",
+		MinQuantity:   fixedpoint.NewFromFloat(0.001),
+		StepSize:      fixedpoint.NewFromFloat(0.0001),
+	}
+
+	mockEx := mocks.NewMockExchange(mockCtrl)
+	mockEx.EXPECT().NewStream().Return(&types.StandardStream{}).AnyTimes()
+	mockEx.EXPECT().CancelOrders(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	session := bbgo.NewExchangeSession("binance", mockEx)
+	session.SetMarkets(types.MarketMap{market.Symbol: market})
+
+	testCases := []struct {
+		quantity      float64
+		numOfSlices   int
+		sliceQuantity string
+		side          types.SideType
+		covered       string
+	}{
+		// the slice quantity is truncated by the step size
+		{quantity: 1.0, numOfSlices: 3, sliceQuantity: "0.3333", side: types.SideTypeBuy, covered: "-1"},
+		// the slice quantity is raised to the min quantity
+		{quantity: -0.002, numOfSlices: 10, sliceQuantity: "0.001", side: types.SideTypeSell, covered: "0.002"},
+	}
+
+	for _, testCase := range testCases {
+		ctx, cancel := context.WithCancel(context.Background())
+
+		executor := NewTWAPHedgeExecutor(&HedgeMarket{Session: session, Market: market}, TWAPHedgeConfig{
+			NumOfSlices:    testCase.numOfSlices,
+			UpdateInterval: types.Duration(time.Hour),
+		})
+
+		covered, err := executor.Hedge(ctx, fixedpoint.NewFromFloat(testCase.quantity))
+		if assert.NoError(t, err) {
+			assert.Equal(t, testCase.covered, covered.String())
+			assert.Equal(t, testCase.sliceQuantity, executor.execution.SliceQuantity.String())
+			assert.Equal(t, testCase.side, executor.execution.Side)
+		}
+
+		cancel()
+		<-executor.execution.Done()
+	}
+}
@@ -18,8 +18,15 @@ type RiskController struct {
 	CircuitBreakLossThreshold fixedpoint.Value     `json:"circuitBreakLossThreshold"`
 	CircuitBreakEMA           types.IntervalWindow `json:"circuitBreakEMA"`
 
+	// CircuitBreakConditions are the loss streak, the order error and the stale market data conditions of the circuit break
+	CircuitBreakConditions *riskcontrol.CircuitBreakConditions `json:"circuitBreakConditions,omitempty"`
+
+	// CircuitBreakOnValueAtRisk halts the strategy when the portfolio value-at-risk exceeds the configured threshold
+	CircuitBreakOnValueAtRisk bool `json:"circuitBreakOnValueAtRisk,omitempty"`
+
 	positionRiskControl     *riskcontrol.PositionRiskControl
 	circuitBreakRiskControl *riskcontrol.CircuitBreakRiskControl
+	conditionCircuitBreaker *riskcontrol.ConditionCircuitBreaker
 }
 
 // Strategy provides the core functionality that is required by a long/short strategy.
@@ -34,6 +41,12 @@ type Strategy struct {
 	Session       *bbgo.ExchangeSession
 	OrderExecutor *bbgo.GeneralOrderExecutor
 
+	// OrderValidationPolicy overrides the order validation policy of the session: adjust or reject
+	OrderValidationPolicy *bbgo.OrderValidationPolicy `json:"orderValidationPolicy,omitempty"`
+
+	// PositionAccountingMode is the lot accounting mode of the position: average, fifo or lifo
+	PositionAccountingMode types.PositionAccountingMode `json:"positionAccountingMode,omitempty"`
+
 	RiskController
 }
 
@@ -56,6 +69,16 @@ func (s *Strategy) Initialize(ctx context.Context, environ *bbgo.Environment, se
 	s.Position.Strategy = strategyID
 	s.Position.StrategyInstanceID = instanceID
 
+	// the accounting mode can only be switched when the position is closed, the open lots of the restored position are unknown
+	if s.PositionAccountingMode != "" && s.PositionAccountingMode != s.Position.AccountingMode {
+		if s.Position.GetBase().IsZero() {
+			s.Position.SetAccountingMode(s.PositionAccountingMode)
+		} else {
+			log.Warnf("unable to switch the position accounting mode to %s, the position %s is still opened",
+				s.PositionAccountingMode, s.Position.Symbol)
+		}
+	}
+
 	// if anyone of the fee rate is defined, this assumes that both are defined.
 	// so that zero maker fee could be applied
 	if session.MakerFeeRate.Sign() > 0 || session.TakerFeeRate.Sign() > 0 {
@@ -69,6 +92,10 @@ func (s *Strategy) Initialize(ctx context.Context, environ *bbgo.Environment, se
 	s.OrderExecutor.BindEnvironment(environ)
 	s.OrderExecutor.BindProfitStats(s.ProfitStats)
 	s.OrderExecutor.Bind()
+
+	if s.OrderValidationPolicy != nil {
+		s.OrderExecutor.SetOrderValidationPolicy(*s.OrderValidationPolicy)
+	}
 	/*
 		s.OrderExecutor.TradeCollector().OnPositionUpdate(func(position *types.Position) {
 			bbgo.Sync(ctx, s)
@@ -90,9 +117,74 @@ func (s *Strategy) Initialize(ctx context.Context, environ *bbgo.Environment, se
 			s.ProfitStats,
 			24*time.Hour)
 	}
+
+	if s.CircuitBreakConditions != nil {
+		if err := s.CircuitBreakConditions.Validate(); err != nil {
+			log.WithError(err).Errorf("invalid circuitBreakConditions, the circuit break conditions are disabled")
+		} else {
+			s.conditionCircuitBreaker = riskcontrol.NewConditionCircuitBreaker(s.CircuitBreakConditions, strategyID, market.Symbol)
+			s.bindConditionCircuitBreaker(session, market.Symbol)
+		}
+	}
+
+	if s.CircuitBreakOnValueAtRisk {
+		if monitor := environ.ValueAtRisk(); monitor != nil {
+			if s.conditionCircuitBreaker == nil {
+				s.conditionCircuitBreaker = riskcontrol.NewConditionCircuitBreaker(&riskcontrol.CircuitBreakConditions{}, strategyID, market.Symbol)
+			}
+
+			s.conditionCircuitBreaker.AddHalter("valueAtRisk", monitor)
+		} else {
+			log.Warnf("circuitBreakOnValueAtRisk is enabled but the valueAtRisk is not configured")
+		}
+	}
+}
+
+func (s *Strategy) bindConditionCircuitBreaker(session *bbgo.ExchangeSession, symbol string) {
+	breaker := s.conditionCircuitBreaker
+
+	s.OrderExecutor.TradeCollector().OnProfit(func(trade types.Trade, profit *types.Profit) {
+		if profit != nil {
+			breaker.RecordProfit(profit.Profit, trade.Time.Time())
+		}
+	})
+
+	s.OrderExecutor.OnOrderError(func(err error) {
+		breaker.RecordOrderError(time.Now())
+	})
+
+	session.MarketDataStream.OnKLine(func(kline types.KLine) {
+		if kline.Symbol == symbol {
+			breaker.RecordMarketData(kline.EndTime.Time())
+		}
+	})
+
+	session.MarketDataStream.OnBookUpdate(func(book types.SliceOrderBook) {
+		if book.Symbol == symbol {
+			breaker.RecordMarketData(bookTime(book))
+		}
+	})
+
+	session.MarketDataStream.OnBookSnapshot(func(book types.SliceOrderBook) {
+		if book.Symbol == symbol {
+			breaker.RecordMarketData(bookTime(book))
+		}
+	})
+}
+
+func bookTime(book types.SliceOrderBook) time.Time {
+	if book.Time.IsZero() {
+		return time.Now()
+	}
+
+	return book.Time
 }
 
 func (s *Strategy) IsHalted(t time.Time) bool {
+	if s.conditionCircuitBreaker != nil && s.conditionCircuitBreaker.IsHalted(t) {
+		return true
+	}
+
 	if s.circuitBreakRiskControl == nil {
 		return false
 	}
@@ -146,6 +146,9 @@ type Strategy struct {
 	// UseCancelAllOrdersApiWhenClose uses a different API to cancel all the orders on the market when closing a grid
 	UseCancelAllOrdersApiWhenClose bool `json:"useCancelAllOrdersApiWhenClose"`
 
+	// WebSocketOrderEntry places and cancels the grid orders through the websocket trading API of the exchange when it's supported
+	WebSocketOrderEntry bool `json:"webSocketOrderEntry"`
+
 	// ResetPositionWhenStart resets the position when the strategy is started
 	ResetPositionWhenStart bool `json:"resetPositionWhenStart"`
 
@@ -1839,6 +1842,10 @@ func (s *Strategy) Run(ctx context.Context, _ bbgo.OrderExecutor, session *bbgo.
 		s.OrderGroupID = util.FNV32(instanceID) % math.MaxInt32
 	}
 
+	if s.WebSocketOrderEntry {
+		bbgo.EnableWebSocketOrderEntry(ctx, session.Exchange)
+	}
+
 	if s.AutoRange != nil {
 		indicatorSet := session.StandardIndicatorSet(s.Symbol)
 		interval := s.AutoRange.Interval()
@@ -2,25 +2,22 @@ package xdepthmaker
 
 import (
 	"context"
-	stderrors "errors"
 	"fmt"
 	"sync"
 	"time"
 
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
-	"golang.org/x/time/rate"
 
 	"github.com/c9s/bbgo/pkg/bbgo"
 	"github.com/c9s/bbgo/pkg/exchange/retry"
 	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/quoting"
 	"github.com/c9s/bbgo/pkg/strategy/common"
 	"github.com/c9s/bbgo/pkg/types"
 	"github.com/c9s/bbgo/pkg/util"
 )
 
-var lastPriceModifier = fixedpoint.NewFromFloat(1.001)
-var minGap = fixedpoint.NewFromFloat(1.02)
 var defaultMargin = fixedpoint.NewFromFloat(0.003)
 
 var Two = fixedpoint.NewFromInt(2)
@@ -29,6 +26,18 @@ const priceUpdateTimeout = 5 * time.Minute
 
 const ID = "xdepthmaker"
 
+// HedgeMethod is the method of hedging the uncovered position
+type HedgeMethod string
+
+const (
+	// HedgeMethodMarket hedges the uncovered position by the market orders
+	HedgeMethodMarket HedgeMethod = "market"
+
+	// HedgeMethodLimitChasing hedges the uncovered position by the limit orders chasing the best price,
+	// the remaining quantity is hedged by the market order when the chases are exhausted
+	HedgeMethodLimitChasing HedgeMethod = "limitChasing"
+)
+
 var log = logrus.WithField("strategy", ID)
 
 func init() {
@@ -184,6 +193,12 @@ type Strategy struct {
 
 	DisableHedge bool `json:"disableHedge"`
 
+	// HedgeMethod is the method of hedging the uncovered position: market or limitChasing, defaults to market
+	HedgeMethod HedgeMethod `json:"hedgeMethod"`
+
+	// HedgeLimitChasing configures the limit chasing hedge method
+	HedgeLimitChasing common.LimitChasingHedgeConfig `json:"hedgeLimitChasing"`
+
 	NotifyTrade bool `json:"notifyTrade"`
 
 	// RecoverTrade tries to find the missing trades via the REStful API
@@ -196,6 +211,12 @@ type Strategy struct {
 	// Pips is the pips of the layer prices
 	Pips fixedpoint.Value `json:"pips"`
 
+	// RequotePriceTolerance is the price change ratio that is tolerated without requoting the layer
+	RequotePriceTolerance fixedpoint.Value `json:"requotePriceTolerance"`
+
+	// RequoteQuantityTolerance is the quantity change ratio that is tolerated without requoting the layer
+	RequoteQuantityTolerance fixedpoint.Value `json:"requoteQuantityTolerance"`
+
 	ProfitFixerConfig *common.ProfitFixerConfig `json:"profitFixer"`
 
 	// --------------------------------
@@ -205,8 +226,7 @@ type Strategy struct {
 	// pricingBook is the order book (depth) from the hedging session
 	pricingBook *types.StreamOrderBook
 
-	hedgeErrorLimiter         *rate.Limiter
-	hedgeErrorRateReservation *rate.Reservation
+	hedgeExecutor common.HedgeExecutor
 
 	askPriceHeartBeat, bidPriceHeartBeat *types.PriceHeartBeat
 
@@ -265,6 +285,12 @@ func (s *Strategy) Validate() error {
 		return errors.New("symbol is required")
 	}
 
+	switch s.HedgeMethod {
+	case "", HedgeMethodMarket, HedgeMethodLimitChasing:
+	default:
+		return fmt.Errorf("unsupported hedgeMethod %q", s.HedgeMethod)
+	}
+
 	return nil
 }
 
@@ -305,7 +331,11 @@ func (s *Strategy) Defaults() error {
 		}
 	}
 
-	s.hedgeErrorLimiter = rate.NewLimiter(rate.Every(1*time.Minute), 1)
+	if s.HedgeMethod == "" {
+		s.HedgeMethod = HedgeMethodMarket
+	}
+
+	s.HedgeLimitChasing.Defaults()
 	return nil
 }
 
@@ -367,6 +397,20 @@ func (s *Strategy) CrossRun(
 	s.pricingBook = types.NewStreamBook(s.Symbol)
 	s.pricingBook.BindStream(s.hedgeSession.MarketDataStream)
 
+	hedgeMarket := &common.HedgeMarket{
+		Session:       s.hedgeSession,
+		Market:        s.hedgeMarket,
+		OrderExecutor: s.HedgeOrderExecutor,
+		BestPrices:    s.hedgeBestPrices,
+	}
+
+	switch s.HedgeMethod {
+	case HedgeMethodLimitChasing:
+		s.hedgeExecutor = common.NewLimitChasingHedgeExecutor(hedgeMarket, s.HedgeLimitChasing)
+	default:
+		s.hedgeExecutor = common.NewMarketOrderHedgeExecutor(hedgeMarket)
+	}
+
 	s.stopC = make(chan struct{})
 
 	s.authedC = make(chan struct{}, 5)
@@ -498,112 +542,38 @@ func (s *Strategy) CrossRun(
 	return nil
 }
 
-func (s *Strategy) Hedge(ctx context.Context, pos fixedpoint.Value) {
-	side := types.SideTypeBuy
-	if pos.IsZero() {
-		return
-	}
-
-	quantity := pos.Abs()
-
-	if pos.Sign() < 0 {
-		side = types.SideTypeSell
-	}
-
-	lastPrice := s.lastPrice
-	sourceBook := s.pricingBook.CopyDepth(1)
-	switch side {
-
-	case types.SideTypeBuy:
-		if bestAsk, ok := sourceBook.BestAsk(); ok {
-			lastPrice = bestAsk.Price
-		}
-
-	case types.SideTypeSell:
-		if bestBid, ok := sourceBook.BestBid(); ok {
-			lastPrice = bestBid.Price
-		}
-	}
-
-	notional := quantity.Mul(lastPrice)
-	if notional.Compare(s.hedgeMarket.MinNotional) <= 0 {
-		log.Warnf("%s %v less than min notional, skipping hedge", s.Symbol, notional)
-		return
+// hedgeBestPrices returns the best prices of the hedge session, the last price is used if the book side is empty
+func (s *Strategy) hedgeBestPrices() (bid, ask fixedpoint.Value) {
+	bid, ask = s.lastPrice, s.lastPrice
+	if bestBid, ok := s.pricingBook.BestBid(); ok {
+		bid = bestBid.Price
 	}
 
-	// adjust quantity according to the balances
-	account := s.hedgeSession.GetAccount()
-	switch side {
-
-	case types.SideTypeBuy:
-		// check quote quantity
-		if quote, ok := account.Balance(s.hedgeMarket.QuoteCurrency); ok {
-			if quote.Available.Compare(notional) < 0 {
-				// adjust price to higher 0.1%, so that we can ensure that the order can be executed
-				quantity = bbgo.AdjustQuantityByMaxAmount(quantity, lastPrice.Mul(lastPriceModifier), quote.Available)
-				quantity = s.hedgeMarket.TruncateQuantity(quantity)
-			}
-		}
-
-	case types.SideTypeSell:
-		// check quote quantity
-		if base, ok := account.Balance(s.hedgeMarket.BaseCurrency); ok {
-			if base.Available.Compare(quantity) < 0 {
-				quantity = base.Available
-			}
-		}
+	if bestAsk, ok := s.pricingBook.BestAsk(); ok {
+		ask = bestAsk.Price
 	}
 
-	// truncate quantity for the supported precision
-	quantity = s.hedgeMarket.TruncateQuantity(quantity)
-
-	if notional.Compare(s.hedgeMarket.MinNotional.Mul(minGap)) <= 0 {
-		log.Warnf("the adjusted amount %v is less than minimal notional %v, skipping hedge", notional, s.hedgeMarket.MinNotional)
-		return
-	}
+	return bid, ask
+}
 
-	if quantity.Compare(s.hedgeMarket.MinQuantity.Mul(minGap)) <= 0 {
-		log.Warnf("the adjusted quantity %v is less than minimal quantity %v, skipping hedge", quantity, s.hedgeMarket.MinQuantity)
+func (s *Strategy) Hedge(ctx context.Context, pos fixedpoint.Value) {
+	if pos.IsZero() {
 		return
 	}
 
-	if s.hedgeErrorRateReservation != nil {
-		if !s.hedgeErrorRateReservation.OK() {
-			return
-		}
-		bbgo.Notify("Hit hedge error rate limit, waiting...")
-		time.Sleep(s.hedgeErrorRateReservation.Delay())
-		s.hedgeErrorRateReservation = nil
+	covered, err := s.hedgeExecutor.Hedge(ctx, pos)
+	if err != nil {
+		log.WithError(err).Errorf("%s hedge error", s.Symbol)
 	}
 
-	log.Infof("submitting %s hedge order %s %v", s.Symbol, side.String(), quantity)
-	bbgo.Notify("Submitting %s hedge order %s %v", s.Symbol, side.String(), quantity)
-
-	_, err := s.HedgeOrderExecutor.SubmitOrders(ctx, types.SubmitOrder{
-		Market:   s.hedgeMarket,
-		Symbol:   s.Symbol,
-		Type:     types.OrderTypeMarket,
-		Side:     side,
-		Quantity: quantity,
-	})
-
-	if err != nil {
-		s.hedgeErrorRateReservation = s.hedgeErrorLimiter.Reserve()
-		log.WithError(err).Errorf("market order submit error: %s", err.Error())
+	// the partially covered position is still added when the hedge fails
+	if covered.IsZero() {
 		return
 	}
 
-	// if the hedge is on sell side, then we should add positive position
-	switch side {
-	case types.SideTypeSell:
-		s.mu.Lock()
-		s.CoveredPosition = s.CoveredPosition.Add(quantity)
-		s.mu.Unlock()
-	case types.SideTypeBuy:
-		s.mu.Lock()
-		s.CoveredPosition = s.CoveredPosition.Add(quantity.Neg())
-		s.mu.Unlock()
-	}
+	s.mu.Lock()
+	s.CoveredPosition = s.CoveredPosition.Add(covered)
+	s.mu.Unlock()
 }
 
 func (s *Strategy) runTradeRecover(ctx context.Context) {
@@ -662,9 +632,14 @@ func (s *Strategy) generateMakerOrders(
 		maxLayer = s.NumLayers
 	}
 
-	var availableBalances = map[types.SideType]fixedpoint.Value{
-		types.SideTypeBuy:  availableQuote,
-		types.SideTypeSell: availableBase,
+	quota := &bbgo.QuotaTransaction{}
+	quota.BaseAsset.Add(availableBase)
+	quota.QuoteAsset.Add(availableQuote)
+	quotaLocker := quoting.NewQuotaLocker(quota, nil)
+
+	var sideQuotas = map[types.SideType]*bbgo.Quota{
+		types.SideTypeBuy:  &quota.QuoteAsset,
+		types.SideTypeSell: &quota.BaseAsset,
 	}
 
 	for _, side := range []types.SideType{types.SideTypeBuy, types.SideTypeSell} {
@@ -674,19 +649,13 @@ func (s *Strategy) generateMakerOrders(
 			continue
 		}
 
-		availableSideBalance, ok := availableBalances[side]
-		if !ok {
-			log.Warnf("no available balance for side %s side", side)
-			continue
-		}
+		sideQuota := sideQuotas[side]
 
 	layerLoop:
 		for i := 1; i <= maxLayer; i++ {
 			// simple break, we need to check the market minNotional and minQuantity later
-			if !availableSideBalance.Eq(fixedpoint.PosInf) {
-				if availableSideBalance.IsZero() || availableSideBalance.Sign() < 0 {
-					break layerLoop
-				}
+			if sideQuota.Available.Sign() <= 0 {
+				break layerLoop
 			}
 
 			requiredDepthFloat, err := s.DepthScale.Scale(i)
@@ -712,23 +681,23 @@ func (s *Strategy) generateMakerOrders(
 
 			log.Infof("side: %s required depth: %f, pvs: %+v", side, requiredDepth.Float64(), pvs)
 
-			depthPrice, err := averageDepthPrice(pvs)
-			if err != nil {
-				log.WithError(err).Errorf("error aggregating depth price")
+			depthPrice := quoting.AggregatePrice(pvs, pvs.SumDepth())
+			if depthPrice.Sign() <= 0 {
+				log.Errorf("invalid %s depth price %s, pvs: %+v", side, depthPrice, pvs)
 				continue
 			}
 
 			switch side {
 			case types.SideTypeBuy:
 				if s.BidMargin.Sign() > 0 {
-					depthPrice = depthPrice.Mul(fixedpoint.One.Sub(s.BidMargin))
+					depthPrice = quoting.ApplyMargin(side, depthPrice, s.BidMargin)
 				}
 
 				depthPrice = depthPrice.Round(s.makerMarket.PricePrecision+1, fixedpoint.Down)
 
 			case types.SideTypeSell:
 				if s.AskMargin.Sign() > 0 {
-					depthPrice = depthPrice.Mul(fixedpoint.One.Add(s.AskMargin))
+					depthPrice = quoting.ApplyMargin(side, depthPrice, s.AskMargin)
 				}
 
 				depthPrice = depthPrice.Round(s.makerMarket.PricePrecision+1, fixedpoint.Up)
@@ -748,8 +717,9 @@ func (s *Strategy) generateMakerOrders(
 				quoteQuantity := fixedpoint.Mul(quantity, depthPrice)
 				quoteQuantity = quoteQuantity.Round(s.makerMarket.PricePrecision, fixedpoint.Up)
 
-				if !availableSideBalance.Eq(fixedpoint.PosInf) && availableSideBalance.Compare(quoteQuantity) <= 0 {
-					quoteQuantity = availableSideBalance
+				// the last layer takes the rest of the quote balance
+				if sideQuota.Available.Compare(quoteQuantity) <= 0 {
+					quoteQuantity = sideQuota.Available
 					quantity = quoteQuantity.Div(depthPrice).Round(s.makerMarket.PricePrecision, fixedpoint.Down)
 				}
 
@@ -757,28 +727,25 @@ func (s *Strategy) generateMakerOrders(
 					break layerLoop
 				}
 
-				availableSideBalance = availableSideBalance.Sub(quoteQuantity)
-
 				accumulatedBidQuoteQuantity = accumulatedBidQuoteQuantity.Add(quoteQuantity)
 
 			case types.SideTypeSell:
 				quantity = quantity.Sub(accumulatedAskQuantity)
 				quoteQuantity := quantity.Mul(depthPrice)
 
-				// balance check
-				if !availableSideBalance.Eq(fixedpoint.PosInf) && availableSideBalance.Compare(quantity) <= 0 {
-					break layerLoop
-				}
-
 				if quantity.Compare(s.makerMarket.MinQuantity) <= 0 || quoteQuantity.Compare(s.makerMarket.MinNotional) <= 0 {
 					break layerLoop
 				}
 
-				availableSideBalance = availableSideBalance.Sub(quantity)
-
 				accumulatedAskQuantity = accumulatedAskQuantity.Add(quantity)
 			}
 
+			if !quotaLocker.Lock(side, depthPrice, quantity) {
+				break layerLoop
+			}
+
+			quotaLocker.Commit()
+
 			submitOrders = append(submitOrders, types.SubmitOrder{
 				Symbol:   s.Symbol,
 				Type:     types.OrderTypeLimitMaker,
@@ -793,41 +760,33 @@ func (s *Strategy) generateMakerOrders(
 	return submitOrders, nil
 }
 
-func (s *Strategy) partiallyCancelOrders(ctx context.Context, maxLayer int) error {
-	buyOrders, sellOrders := s.MakerOrderExecutor.ActiveMakerOrders().Orders().SeparateBySide()
+// refreshingOrders returns the active maker orders of the layers to refresh,
+// all the active orders are refreshed when maxLayer is 0, otherwise the orders of the first maxLayer layers of each side
+func (s *Strategy) refreshingOrders(maxLayer int) []types.Order {
+	activeOrders := s.MakerOrderExecutor.ActiveMakerOrders().Orders()
+	if maxLayer == 0 {
+		return activeOrders
+	}
+
+	buyOrders, sellOrders := activeOrders.SeparateBySide()
 	buyOrders = types.SortOrdersByPrice(buyOrders, true)
 	sellOrders = types.SortOrdersByPrice(sellOrders, false)
 
-	buyOrdersToCancel := buyOrders[0:min(maxLayer, len(buyOrders))]
-	sellOrdersToCancel := sellOrders[0:min(maxLayer, len(sellOrders))]
-
-	err1 := s.MakerOrderExecutor.GracefulCancel(ctx, buyOrdersToCancel...)
-	err2 := s.MakerOrderExecutor.GracefulCancel(ctx, sellOrdersToCancel...)
-	return stderrors.Join(err1, err2)
+	var orders []types.Order
+	orders = append(orders, buyOrders[0:min(maxLayer, len(buyOrders))]...)
+	orders = append(orders, sellOrders[0:min(maxLayer, len(sellOrders))]...)
+	return orders
 }
 
+// updateQuote syncs the maker orders of the layers to the desired layers of the pricing book,
+// only the layers that moved beyond the requote tolerance are canceled and replaced.
 func (s *Strategy) updateQuote(ctx context.Context, maxLayer int) {
-	if maxLayer == 0 {
+	bestBid, bestAsk, hasPrice := s.pricingBook.BestBidAndAsk()
+	if !hasPrice {
 		if err := s.MakerOrderExecutor.GracefulCancel(ctx); err != nil {
-			log.WithError(err).Warnf("there are some %s orders not canceled, skipping placing maker orders", s.Symbol)
+			log.WithError(err).Warnf("there are some %s orders not canceled", s.Symbol)
 			s.MakerOrderExecutor.ActiveMakerOrders().Print()
-			return
 		}
-	} else {
-		if err := s.partiallyCancelOrders(ctx, maxLayer); err != nil {
-			log.WithError(err).Warnf("%s partial order cancel failed", s.Symbol)
-			return
-		}
-	}
-
-	numOfMakerOrders := s.MakerOrderExecutor.ActiveMakerOrders().NumOfOrders()
-	if numOfMakerOrders > 0 {
-		log.Warnf("maker orders are not all canceled")
-		return
-	}
-
-	bestBid, bestAsk, hasPrice := s.pricingBook.BestBidAndAsk()
-	if !hasPrice {
 		return
 	}
 
@@ -871,7 +830,12 @@ func (s *Strategy) updateQuote(ctx context.Context, maxLayer int) {
 
 	log.Infof("quote balance: %s, base balance: %s", quoteBalance, baseBalance)
 
-	submitOrders, err := s.generateMakerOrders(s.pricingBook, maxLayer, baseBalance.Available, quoteBalance.Available)
+	// the funds locked by the refreshing orders are available for the desired layers
+	activeOrders := s.refreshingOrders(maxLayer)
+	lockedBase, lockedQuote := quoting.LockedFunds(activeOrders)
+
+	submitOrders, err := s.generateMakerOrders(s.pricingBook, maxLayer,
+		baseBalance.Available.Add(lockedBase), quoteBalance.Available.Add(lockedQuote))
 	if err != nil {
 		log.WithError(err).Errorf("generate order error")
 		return
@@ -879,10 +843,36 @@ func (s *Strategy) updateQuote(ctx context.Context, maxLayer int) {
 
 	if len(submitOrders) == 0 {
 		log.Warnf("no orders are generated")
+	}
+
+	diff := quoting.DiffOrders(activeOrders, submitOrders, s.RequotePriceTolerance, s.RequoteQuantityTolerance)
+
+	cancelOrders := diff.Cancel
+	newOrders := diff.Submit
+	for _, amendment := range diff.Amend {
+		cancelOrders = append(cancelOrders, amendment.Order)
+		newOrders = append(newOrders, amendment.Submit)
+	}
+
+	log.Infof("%s requote: keep %d, amend %d, cancel %d, submit %d orders",
+		s.Symbol, len(diff.Keep), len(diff.Amend), len(diff.Cancel), len(diff.Submit))
+
+	if len(cancelOrders) > 0 {
+		if err := s.MakerOrderExecutor.GracefulCancel(ctx, cancelOrders...); err != nil {
+			log.WithError(err).Warnf("there are some %s orders not canceled, skipping placing maker orders", s.Symbol)
+			s.MakerOrderExecutor.ActiveMakerOrders().Print()
+			return
+		}
+	}
+
+	if len(newOrders) == 0 {
 		return
 	}
 
-	_, err = s.MakerOrderExecutor.SubmitOrders(ctx, submitOrders...)
+	// place the touch layer first to minimize the time without a top-of-book quote
+	newOrders = quoting.SortByLayer(newOrders, quoting.SubmitOrderLayerKey, true)
+
+	_, err = s.MakerOrderExecutor.SubmitOrders(ctx, newOrders...)
 	if err != nil {
 		log.WithError(err).Errorf("order error: %s", err.Error())
 		return
@@ -923,25 +913,6 @@ func selectSessions2(
 	return s1, s2, nil
 }
 
-func averageDepthPrice(pvs types.PriceVolumeSlice) (price fixedpoint.Value, err error) {
-	if len(pvs) == 0 {
-		return fixedpoint.Zero, fmt.Errorf("empty pv slice")
-	}
-
-	totalQuoteAmount := fixedpoint.Zero
-	totalQuantity := fixedpoint.Zero
-
-	for i := 0; i < len(pvs); i++ {
-		pv := pvs[i]
-		quoteAmount := fixedpoint.Mul(pv.Volume, pv.Price)
-		totalQuoteAmount = totalQuoteAmount.Add(quoteAmount)
-		totalQuantity = totalQuantity.Add(pv.Volume)
-	}
-
-	price = totalQuoteAmount.Div(totalQuantity)
-	return price, nil
-}
-
 func min(a, b int) int {
 	if a < b {
 		return a
@@ -10,6 +10,7 @@ import (
 
 	"github.com/c9s/bbgo/pkg/bbgo"
 	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/quoting"
 	. "github.com/c9s/bbgo/pkg/testing/testhelper"
 	"github.com/c9s/bbgo/pkg/types"
 )
@@ -28,7 +29,23 @@ func newTestBTCUSDTMarket() types.Market {
 }
 
 func TestStrategy_generateMakerOrders(t *testing.T) {
-	s := &Strategy{
+	s := newTestDepthMakerStrategy()
+	pricingBook := newTestPricingBook()
+
+	orders, err := s.generateMakerOrders(pricingBook, 0, fixedpoint.PosInf, fixedpoint.PosInf)
+	assert.NoError(t, err)
+	AssertOrdersPriceSideQuantity(t, []PriceSideQuantityAssert{
+		{Side: types.SideTypeBuy, Price: Number("25000"), Quantity: Number("0.04")},        // =~ $1000.00
+		{Side: types.SideTypeBuy, Price: Number("24866.66"), Quantity: Number("0.281715")}, // =~ $7005.3111219, accumulated amount =~ $1000.00 + $7005.3111219 = $8005.3111219
+		{Side: types.SideTypeBuy, Price: Number("24800"), Quantity: Number("0.283123")},    // =~ $7021.4504, accumulated amount =~ $1000.00 + $7005.3111219 + $7021.4504 = $8005.3111219 + $7021.4504 =~ $15026.7615219
+		{Side: types.SideTypeSell, Price: Number("25100"), Quantity: Number("0.03984")},
+		{Side: types.SideTypeSell, Price: Number("25233.33"), Quantity: Number("0.2772")},
+		{Side: types.SideTypeSell, Price: Number("25233.33"), Quantity: Number("0.277411")},
+	}, orders)
+}
+
+func newTestDepthMakerStrategy() *Strategy {
+	return &Strategy{
 		Symbol:    "BTCUSDT",
 		NumLayers: 3,
 		DepthScale: &bbgo.LayerScale{
@@ -43,7 +60,9 @@ func TestStrategy_generateMakerOrders(t *testing.T) {
 			makerMarket: newTestBTCUSDTMarket(),
 		},
 	}
+}
 
+func newTestPricingBook() *types.StreamOrderBook {
 	pricingBook := types.NewStreamBook("BTCUSDT")
 	pricingBook.Load(types.SliceOrderBook{
 		Symbol: "BTCUSDT",
@@ -61,15 +80,43 @@ func TestStrategy_generateMakerOrders(t *testing.T) {
 		},
 		Time: time.Now(),
 	})
+	return pricingBook
+}
 
-	orders, err := s.generateMakerOrders(pricingBook, 0, fixedpoint.PosInf, fixedpoint.PosInf)
+func TestStrategy_generateMakerOrders_LimitedBalances(t *testing.T) {
+	s := newTestDepthMakerStrategy()
+
+	// the quote balance covers the first bid layer and a part of the second one,
+	// the base balance only covers the first ask layer
+	orders, err := s.generateMakerOrders(newTestPricingBook(), 0, Number("0.1"), Number("5000"))
 	assert.NoError(t, err)
 	AssertOrdersPriceSideQuantity(t, []PriceSideQuantityAssert{
-		{Side: types.SideTypeBuy, Price: Number("25000"), Quantity: Number("0.04")},        // =~ $1000.00
-		{Side: types.SideTypeBuy, Price: Number("24866.66"), Quantity: Number("0.281715")}, // =~ $7005.3111219, accumulated amount =~ $1000.00 + $7005.3111219 = $8005.3111219
-		{Side: types.SideTypeBuy, Price: Number("24800"), Quantity: Number("0.283123")},    // =~ $7021.4504, accumulated amount =~ $1000.00 + $7005.3111219 + $7021.4504 = $8005.3111219 + $7021.4504 =~ $15026.7615219
+		{Side: types.SideTypeBuy, Price: Number("25000"), Quantity: Number("0.04")},
+		{Side: types.SideTypeBuy, Price: Number("24866.66"), Quantity: Number("0.16")},
 		{Side: types.SideTypeSell, Price: Number("25100"), Quantity: Number("0.03984")},
-		{Side: types.SideTypeSell, Price: Number("25233.33"), Quantity: Number("0.2772")},
-		{Side: types.SideTypeSell, Price: Number("25233.33"), Quantity: Number("0.277411")},
 	}, orders)
 }
+
+func TestStrategy_generateMakerOrders_Diff(t *testing.T) {
+	s := newTestDepthMakerStrategy()
+
+	submitOrders, err := s.generateMakerOrders(newTestPricingBook(), 0, fixedpoint.PosInf, fixedpoint.PosInf)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var activeOrders []types.Order
+	for i, o := range submitOrders {
+		activeOrders = append(activeOrders, types.Order{SubmitOrder: o, OrderID: uint64(i + 1), Status: types.OrderStatusNew})
+	}
+
+	// the layers of the unchanged book are kept
+	orders, err := s.generateMakerOrders(newTestPricingBook(), 0, fixedpoint.PosInf, fixedpoint.PosInf)
+	if assert.NoError(t, err) {
+		diff := quoting.DiffOrders(activeOrders, orders, s.RequotePriceTolerance, s.RequoteQuantityTolerance)
+		assert.Len(t, diff.Keep, len(activeOrders))
+		assert.Empty(t, diff.Amend)
+		assert.Empty(t, diff.Cancel)
+		assert.Empty(t, diff.Submit)
+	}
+}
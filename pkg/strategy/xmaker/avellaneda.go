@@ -0,0 +1,167 @@
+package xmaker
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/bbgo"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	indicatorv2 "github.com/c9s/bbgo/pkg/indicator/v2"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// AvellanedaStoikov replaces the bid/ask margins by the optimal offsets of the Avellaneda-Stoikov model,
+// the offsets are computed from the trade intensity and the volatility of the source market and the inventory:
+//
+//	reservation offset = q * gamma * sigma^2 * tau
+//	half spread = gamma * sigma^2 * tau / 2 + ln(1 + gamma / k) / gamma
+//
+// where q is the base position normalized by the max exposure position, sigma is the volatility per second,
+// tau is the horizon in seconds, and k is the decay of the trade intensity by the distance from the mid price.
+type AvellanedaStoikov struct {
+	Enabled bool `json:"enabled"`
+
+	// IntervalWindow is the interval and the window of the trade intensity and the volatility estimation
+	types.IntervalWindow
+
+	// RiskAversion is the gamma of the model, the quotes are wider and skewed more by the inventory with the higher value
+	RiskAversion fixedpoint.Value `json:"riskAversion"`
+
+	// Horizon is the time horizon of the model, defaults to 1m
+	Horizon types.Duration `json:"horizon"`
+
+	// MaxDistance and NumLevels are the distance levels of the trade intensity estimation, defaults to 0.005 and 10
+	MaxDistance fixedpoint.Value `json:"maxDistance"`
+	NumLevels   int              `json:"numLevels"`
+
+	// MinMargin and MaxMargin limit the margins, the max margin is not limited when it's zero
+	MinMargin fixedpoint.Value `json:"minMargin"`
+	MaxMargin fixedpoint.Value `json:"maxMargin"`
+}
+
+func (c *AvellanedaStoikov) Defaults() {
+	if c.Interval == "" {
+		c.Interval = types.Interval1m
+	}
+
+	if c.Window == 0 {
+		c.Window = 30
+	}
+
+	if c.Horizon == 0 {
+		c.Horizon = types.Duration(time.Minute)
+	}
+
+	if c.MaxDistance.IsZero() {
+		c.MaxDistance = fixedpoint.NewFromFloat(0.005)
+	}
+
+	if c.NumLevels == 0 {
+		c.NumLevels = 10
+	}
+}
+
+func (c *AvellanedaStoikov) Validate() error {
+	if c.RiskAversion.Sign() <= 0 {
+		return fmt.Errorf("avellanedaStoikov riskAversion should be greater than 0")
+	}
+
+	if c.NumLevels < 0 || c.NumLevels == 1 {
+		return fmt.Errorf("avellanedaStoikov numLevels should be at least 2, got %d", c.NumLevels)
+	}
+
+	if c.MinMargin.Sign() < 0 {
+		return fmt.Errorf("avellanedaStoikov minMargin should not be negative, got %v", c.MinMargin)
+	}
+
+	if c.MaxMargin.Sign() > 0 && c.MaxMargin.Compare(c.MinMargin) < 0 {
+		return fmt.Errorf("avellanedaStoikov maxMargin %v should not be less than minMargin %v", c.MaxMargin, c.MinMargin)
+	}
+
+	return nil
+}
+
+// avellanedaStoikovModel computes the margins from the trade intensity and the realized volatility
+type avellanedaStoikovModel struct {
+	config    *AvellanedaStoikov
+	intensity *indicatorv2.TradeIntensityStream
+	vol       volatilitySeries
+}
+
+// Margins returns the bid/ask margins of the normalized inventory q, ok is false when the estimations are not ready
+func (m *avellanedaStoikovModel) Margins(q fixedpoint.Value) (bidMargin, askMargin fixedpoint.Value, ok bool) {
+	_, k, ok := m.intensity.Parameters()
+	if !ok || m.vol.Length() == 0 {
+		return fixedpoint.Zero, fixedpoint.Zero, false
+	}
+
+	// the realized volatility is per interval, convert it into the variance per second
+	variance := math.Pow(m.vol.Last(0), 2) / m.config.Interval.Duration().Seconds()
+	gamma := m.config.RiskAversion.Float64()
+	tau := m.config.Horizon.Duration().Seconds()
+
+	reservation := q.Float64() * gamma * variance * tau
+	halfSpread := gamma*variance*tau/2 + math.Log(1+gamma/k)/gamma
+
+	bidMargin = m.clamp(fixedpoint.NewFromFloat(halfSpread + reservation))
+	askMargin = m.clamp(fixedpoint.NewFromFloat(halfSpread - reservation))
+	return bidMargin, askMargin, true
+}
+
+func (m *avellanedaStoikovModel) clamp(margin fixedpoint.Value) fixedpoint.Value {
+	margin = fixedpoint.Max(margin, m.config.MinMargin)
+	if m.config.MaxMargin.Sign() > 0 {
+		margin = fixedpoint.Min(margin, m.config.MaxMargin)
+	}
+
+	return margin
+}
+
+// newAvellanedaStoikovModel binds the trade intensity and the volatility estimations to the source session,
+// the mid price of the trades is the mid price of the aggregated source book.
+func (s *Strategy) newAvellanedaStoikovModel(session *bbgo.ExchangeSession) *avellanedaStoikovModel {
+	config := s.AvellanedaStoikov
+	midPrice := func() (fixedpoint.Value, bool) {
+		bid, ask, ok := s.book.BestBidAndAskOf(s.sourceExchangeNames()...)
+		if !ok {
+			return fixedpoint.Zero, false
+		}
+
+		return bid.Price.Add(ask.Price).Div(fixedpoint.Two), true
+	}
+
+	intensity := indicatorv2.TradeIntensity(session.MarketDataStream, s.sourceSymbol(), config.IntervalWindow,
+		config.MaxDistance.Float64(), config.NumLevels, midPrice)
+
+	vol := &realizedVolatility{IntervalWindow: config.IntervalWindow}
+	session.MarketDataStream.OnKLineClosed(types.KLineWith(s.sourceSymbol(), config.Interval, vol.PushK))
+
+	return &avellanedaStoikovModel{
+		config:    config,
+		intensity: intensity,
+		vol:       vol,
+	}
+}
+
+// applyAvellanedaStoikov replaces the margins by the model margins, the margins are kept when the model is not ready
+func (s *Strategy) applyAvellanedaStoikov(bidMargin, askMargin fixedpoint.Value) (fixedpoint.Value, fixedpoint.Value) {
+	q := calculateInventorySkew(s.Position.GetBase(), s.getMaxExposurePosition(), fixedpoint.One)
+	modelBidMargin, modelAskMargin, ok := s.avellanedaStoikov.Margins(q)
+	if !ok {
+		s.logger().Infof("%s avellaneda-stoikov model is not ready, using the bid/ask margin %v/%v", s.Symbol, bidMargin, askMargin)
+		return bidMargin, askMargin
+	}
+
+	s.logger().Infof("%s avellaneda-stoikov model (inventory %v): adjusting bid/ask margin %v/%v to %v/%v",
+		s.Symbol, q, bidMargin, askMargin, modelBidMargin, modelAskMargin)
+
+	labels := s.metricsLabels()
+	labels["model"] = "avellanedaStoikov"
+	labels["side"] = "bid"
+	spreadModelMarginMetrics.With(labels).Set(modelBidMargin.Float64())
+	labels["side"] = "ask"
+	spreadModelMarginMetrics.With(labels).Set(modelAskMargin.Float64())
+
+	return modelBidMargin, modelAskMargin
+}
@@ -0,0 +1,88 @@
+package xmaker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	indicatorv2 "github.com/c9s/bbgo/pkg/indicator/v2"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func newTestTradeIntensity(t *testing.T, config *AvellanedaStoikov) *indicatorv2.TradeIntensityStream {
+	mid := fixedpoint.NewFromInt(100)
+	intensity := indicatorv2.NewTradeIntensityStream(config.IntervalWindow, config.MaxDistance.Float64(), config.NumLevels,
+		func() (fixedpoint.Value, bool) { return mid, true })
+
+	// the trades are fewer with the larger distance
+	for i := 0; i < config.NumLevels; i++ {
+		price := mid.Mul(fixedpoint.NewFromFloat(1 + config.MaxDistance.Float64()*float64(i)/float64(config.NumLevels)))
+		for j := 0; j < 1<<(config.NumLevels-i); j++ {
+			intensity.AddTrade(types.Trade{Price: price})
+		}
+	}
+	intensity.Flush()
+
+	_, _, ok := intensity.Parameters()
+	assert.True(t, ok)
+	return intensity
+}
+
+func Test_avellanedaStoikovModel_Margins(t *testing.T) {
+	config := &AvellanedaStoikov{
+		Enabled:      true,
+		RiskAversion: fixedpoint.NewFromFloat(0.1),
+		Horizon:      types.Duration(time.Minute),
+	}
+	config.Defaults()
+	assert.NoError(t, config.Validate())
+
+	intensity := newTestTradeIntensity(t, config)
+
+	t.Run("not ready", func(t *testing.T) {
+		m := &avellanedaStoikovModel{config: config, intensity: intensity, vol: testVolatilitySeries{}}
+		_, _, ok := m.Margins(fixedpoint.Zero)
+		assert.False(t, ok)
+	})
+
+	t.Run("flat inventory", func(t *testing.T) {
+		m := &avellanedaStoikovModel{config: config, intensity: intensity, vol: testVolatilitySeries{0.01}}
+		bid, ask, ok := m.Margins(fixedpoint.Zero)
+		assert.True(t, ok)
+		assert.Equal(t, bid, ask)
+		assert.True(t, bid.Sign() > 0)
+	})
+
+	t.Run("long inventory skews the quotes down", func(t *testing.T) {
+		m := &avellanedaStoikovModel{config: config, intensity: intensity, vol: testVolatilitySeries{0.01}}
+		bid, ask, ok := m.Margins(fixedpoint.NewFromFloat(0.5))
+		assert.True(t, ok)
+		assert.True(t, bid.Compare(ask) > 0)
+	})
+
+	t.Run("clamped", func(t *testing.T) {
+		clamped := *config
+		clamped.MinMargin = fixedpoint.NewFromFloat(0.001)
+		clamped.MaxMargin = fixedpoint.NewFromFloat(0.002)
+
+		m := &avellanedaStoikovModel{config: &clamped, intensity: intensity, vol: testVolatilitySeries{10.0}}
+		bid, ask, ok := m.Margins(fixedpoint.One)
+		assert.True(t, ok)
+		assert.Equal(t, "0.002", bid.String())
+		assert.Equal(t, "0.001", ask.String())
+	})
+}
+
+func TestAvellanedaStoikov_Validate(t *testing.T) {
+	config := &AvellanedaStoikov{Enabled: true}
+	assert.Error(t, config.Validate())
+
+	config.RiskAversion = fixedpoint.NewFromFloat(0.1)
+	assert.NoError(t, config.Validate())
+
+	config.MinMargin = fixedpoint.NewFromFloat(0.002)
+	config.MaxMargin = fixedpoint.NewFromFloat(0.001)
+	assert.Error(t, config.Validate())
+}
@@ -0,0 +1,52 @@
+package xmaker
+
+import (
+	"context"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/bbgo"
+)
+
+// tradingPaused checks the trading calendars of the maker session and the source sessions,
+// it returns the paused venue and the reason when any of the venues is paused.
+func (s *Strategy) tradingPaused(now time.Time) (venue string, reason string, paused bool) {
+	if paused, reason := s.makerSession.TradingPaused(now); paused {
+		return s.MakerExchange, reason, true
+	}
+
+	for _, source := range s.sourceExchangeNames() {
+		if paused, reason := s.sourceSessions[source].TradingPaused(now); paused {
+			return source, reason, true
+		}
+	}
+
+	return "", "", false
+}
+
+// checkTradingCalendar pauses quoting when the maker venue or the hedge venues are paused by the trading calendar,
+// e.g. the banking holidays and the maintenance windows, it cancels the maker orders and returns true when quoting should be paused.
+func (s *Strategy) checkTradingCalendar(ctx context.Context) bool {
+	wasPaused := s.calendarPaused
+
+	venue, reason, paused := s.tradingPaused(time.Now())
+	s.calendarPaused = paused
+
+	switch {
+	case paused && !wasPaused:
+		calendarPausedMetrics.With(s.metricsLabels()).Set(1)
+		s.logger().Warnf("%s trading on %s is paused by the calendar: %s", s.Symbol, venue, reason)
+		bbgo.Notify("%s: trading on %s is paused by the calendar (%s), pausing quoting", s.Symbol, venue, reason)
+
+	case !paused && wasPaused:
+		calendarPausedMetrics.With(s.metricsLabels()).Set(0)
+		bbgo.Notify("%s: trading calendar pause is over, resuming quoting", s.Symbol)
+	}
+
+	if paused && s.activeMakerOrders.NumOfOrders() > 0 {
+		if err := s.activeMakerOrders.GracefulCancel(ctx, s.makerSession.Exchange); err != nil {
+			s.logger().WithError(err).Errorf("unable to cancel the %s maker orders", s.Symbol)
+		}
+	}
+
+	return paused
+}
@@ -0,0 +1,75 @@
+package xmaker
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/c9s/bbgo/pkg/bbgo"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// suspended returns true when quoting is suspended by the operator
+func (s *Strategy) suspended() bool {
+	return s.GetStatus() == types.StrategyStatusStopped
+}
+
+// bindStrategyController pulls the maker quotes when the strategy is suspended or emergency stopped by the operator
+func (s *Strategy) bindStrategyController(ctx context.Context) {
+	s.Status = types.StrategyStatusRunning
+
+	s.OnSuspend(func() {
+		if err := s.activeMakerOrders.GracefulCancel(ctx, s.makerSession.Exchange); err != nil {
+			s.logger().WithError(err).Errorf("unable to cancel the %s maker orders", s.Symbol)
+		}
+
+		bbgo.Notify("%s: %s quoting is suspended", ID, s.Symbol)
+	})
+
+	s.OnResume(func() {
+		bbgo.Notify("%s: %s quoting is resumed", ID, s.Symbol)
+	})
+
+	s.OnEmergencyStop(func() {
+		if err := s.FlattenPosition(ctx); err != nil {
+			s.logger().WithError(err).Errorf("unable to flatten the %s position", s.Symbol)
+		}
+	})
+}
+
+// FlattenPosition cancels the maker orders and hedges the whole uncovered position immediately,
+// the hedge netting and the twap hedge are bypassed.
+func (s *Strategy) FlattenPosition(ctx context.Context) error {
+	if err := s.activeMakerOrders.GracefulCancel(ctx, s.makerSession.Exchange); err != nil {
+		return fmt.Errorf("unable to cancel the %s maker orders: %w", s.Symbol, err)
+	}
+
+	s.tradeCollector.Process()
+
+	uncoverPosition := s.Position.GetBase().Sub(s.CoveredPosition)
+	bbgo.Notify("%s: flattening the %s uncovered position %v", ID, s.Symbol, uncoverPosition)
+
+	s.Hedge(ctx, uncoverPosition.Neg())
+	return nil
+}
+
+// AdjustMargins updates the bid/ask margins at runtime, the new margins are applied from the next quote update
+func (s *Strategy) AdjustMargins(bidMargin, askMargin fixedpoint.Value) error {
+	if bidMargin.Sign() < 0 || askMargin.Sign() < 0 {
+		return fmt.Errorf("margins should not be negative, got bid margin %v and ask margin %v", bidMargin, askMargin)
+	}
+
+	s.marginMu.Lock()
+	s.BidMargin = bidMargin
+	s.AskMargin = askMargin
+	s.marginMu.Unlock()
+
+	bbgo.Notify("%s: %s bid/ask margins are adjusted to %v/%v", ID, s.Symbol, bidMargin, askMargin)
+	return nil
+}
+
+func (s *Strategy) getMargins() (bidMargin, askMargin fixedpoint.Value) {
+	s.marginMu.Lock()
+	defer s.marginMu.Unlock()
+	return s.BidMargin, s.AskMargin
+}
@@ -0,0 +1,38 @@
+package xmaker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestStrategy_AdjustMargins(t *testing.T) {
+	s := &Strategy{
+		Symbol:    "BTCUSDT",
+		BidMargin: fixedpoint.NewFromFloat(0.003),
+		AskMargin: fixedpoint.NewFromFloat(0.003),
+	}
+
+	assert.NoError(t, s.AdjustMargins(fixedpoint.NewFromFloat(0.002), fixedpoint.NewFromFloat(0.004)))
+
+	bidMargin, askMargin := s.getMargins()
+	assert.Equal(t, "0.002", bidMargin.String())
+	assert.Equal(t, "0.004", askMargin.String())
+
+	assert.Error(t, s.AdjustMargins(fixedpoint.NewFromFloat(-0.001), fixedpoint.NewFromFloat(0.004)))
+}
+
+func TestStrategy_suspended(t *testing.T) {
+	s := &Strategy{Symbol: "BTCUSDT"}
+	s.Status = types.StrategyStatusRunning
+	assert.False(t, s.suspended())
+
+	assert.NoError(t, s.Suspend())
+	assert.True(t, s.suspended())
+
+	assert.NoError(t, s.Resume())
+	assert.False(t, s.suspended())
+}
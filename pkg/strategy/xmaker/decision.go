@@ -0,0 +1,236 @@
+package xmaker
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/data/parquet"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// DecisionExport writes the quoting decisions into the parquet files for the offline research,
+// each quote update is a record with the reference price, the margins, the chosen layers,
+// and the fills and the hedges since the previous record.
+// The files can be loaded with pandas.read_parquet.
+type DecisionExport struct {
+	Enabled bool `json:"enabled"`
+
+	// Dir is the directory of the exported files, defaults to "output/xmaker"
+	Dir string `json:"dir"`
+
+	// RotateInterval is the interval of starting a new file, defaults to 1h.
+	// The file is only readable after it's rotated or the strategy is shut down.
+	RotateInterval types.Duration `json:"rotateInterval"`
+}
+
+func (e *DecisionExport) Defaults() {
+	if e.Dir == "" {
+		e.Dir = filepath.Join("output", "xmaker")
+	}
+
+	if e.RotateInterval == 0 {
+		e.RotateInterval = types.Duration(time.Hour)
+	}
+}
+
+func (e *DecisionExport) Validate() error {
+	if e.RotateInterval < 0 {
+		return fmt.Errorf("decisionExport rotateInterval should not be negative")
+	}
+
+	return nil
+}
+
+var decisionColumns = []parquet.Column{
+	{Name: "time", Type: parquet.ColumnTypeTimestamp},
+	{Name: "symbol", Type: parquet.ColumnTypeString},
+	{Name: "best_bid", Type: parquet.ColumnTypeDouble},
+	{Name: "best_ask", Type: parquet.ColumnTypeDouble},
+	{Name: "reference_price", Type: parquet.ColumnTypeDouble},
+	{Name: "bid_margin", Type: parquet.ColumnTypeDouble},
+	{Name: "ask_margin", Type: parquet.ColumnTypeDouble},
+	{Name: "num_bid_layers", Type: parquet.ColumnTypeInt64},
+	{Name: "num_ask_layers", Type: parquet.ColumnTypeInt64},
+	{Name: "layers", Type: parquet.ColumnTypeString},
+	{Name: "fill_buy_quantity", Type: parquet.ColumnTypeDouble},
+	{Name: "fill_sell_quantity", Type: parquet.ColumnTypeDouble},
+	{Name: "hedge_buy_quantity", Type: parquet.ColumnTypeDouble},
+	{Name: "hedge_sell_quantity", Type: parquet.ColumnTypeDouble},
+	{Name: "position", Type: parquet.ColumnTypeDouble},
+	{Name: "covered_position", Type: parquet.ColumnTypeDouble},
+}
+
+type decisionLayer struct {
+	Side     types.SideType   `json:"side"`
+	Price    fixedpoint.Value `json:"price"`
+	Quantity fixedpoint.Value `json:"quantity"`
+}
+
+// quoteDecision is the record of a quote update
+type quoteDecision struct {
+	Time                      time.Time
+	BestBid, BestAsk          fixedpoint.Value
+	ReferencePrice            fixedpoint.Value
+	BidMargin, AskMargin      fixedpoint.Value
+	Orders                    []types.SubmitOrder
+	Position, CoveredPosition fixedpoint.Value
+}
+
+// decisionRecorder writes the quote decisions into the rotated parquet files
+type decisionRecorder struct {
+	mu sync.Mutex
+
+	dir            string
+	symbol         string
+	rotateInterval time.Duration
+
+	writer   *parquet.Writer
+	openedAt time.Time
+
+	// the fill and the hedge quantities since the previous record
+	fillBuy, fillSell   fixedpoint.Value
+	hedgeBuy, hedgeSell fixedpoint.Value
+}
+
+func newDecisionRecorder(dir, symbol string, rotateInterval time.Duration) *decisionRecorder {
+	return &decisionRecorder{
+		dir:            dir,
+		symbol:         symbol,
+		rotateInterval: rotateInterval,
+	}
+}
+
+// AddTrade accumulates the maker fill or the hedge trade into the next record
+func (r *decisionRecorder) AddTrade(trade types.Trade, isHedge bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	switch {
+	case isHedge && trade.Side == types.SideTypeBuy:
+		r.hedgeBuy = r.hedgeBuy.Add(trade.Quantity)
+	case isHedge:
+		r.hedgeSell = r.hedgeSell.Add(trade.Quantity)
+	case trade.Side == types.SideTypeBuy:
+		r.fillBuy = r.fillBuy.Add(trade.Quantity)
+	default:
+		r.fillSell = r.fillSell.Add(trade.Quantity)
+	}
+}
+
+func (r *decisionRecorder) open(now time.Time) error {
+	if err := os.MkdirAll(r.dir, 0755); err != nil {
+		return err
+	}
+
+	filename := filepath.Join(r.dir, fmt.Sprintf("xmaker-%s-%s.parquet", r.symbol, now.UTC().Format("20060102T150405")))
+	writer, err := parquet.NewWriterFile(filename, decisionColumns)
+	if err != nil {
+		return err
+	}
+
+	r.writer = writer
+	r.openedAt = now
+	return nil
+}
+
+// Record writes the quote decision, the file is rotated when it's opened longer than the rotate interval
+func (r *decisionRecorder) Record(d quoteDecision) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.writer != nil && r.rotateInterval > 0 && d.Time.Sub(r.openedAt) >= r.rotateInterval {
+		err := r.writer.Close()
+		r.writer = nil
+		if err != nil {
+			return err
+		}
+	}
+
+	if r.writer == nil {
+		if err := r.open(d.Time); err != nil {
+			return err
+		}
+	}
+
+	var layers []decisionLayer
+	var numBidLayers, numAskLayers int64
+	for _, o := range d.Orders {
+		if o.Side == types.SideTypeBuy {
+			numBidLayers++
+		} else {
+			numAskLayers++
+		}
+
+		layers = append(layers, decisionLayer{Side: o.Side, Price: o.Price, Quantity: o.Quantity})
+	}
+
+	layersJson, err := json.Marshal(layers)
+	if err != nil {
+		return err
+	}
+
+	if err := r.writer.Write(
+		d.Time,
+		r.symbol,
+		d.BestBid.Float64(),
+		d.BestAsk.Float64(),
+		d.ReferencePrice.Float64(),
+		d.BidMargin.Float64(),
+		d.AskMargin.Float64(),
+		numBidLayers,
+		numAskLayers,
+		string(layersJson),
+		r.fillBuy.Float64(),
+		r.fillSell.Float64(),
+		r.hedgeBuy.Float64(),
+		r.hedgeSell.Float64(),
+		d.Position.Float64(),
+		d.CoveredPosition.Float64(),
+	); err != nil {
+		return err
+	}
+
+	r.fillBuy, r.fillSell = fixedpoint.Zero, fixedpoint.Zero
+	r.hedgeBuy, r.hedgeSell = fixedpoint.Zero, fixedpoint.Zero
+	return nil
+}
+
+// Close closes the current file, so that it can be read
+func (r *decisionRecorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.writer == nil {
+		return nil
+	}
+
+	err := r.writer.Close()
+	r.writer = nil
+	return err
+}
+
+// recordDecision exports the quote decision when the decision export is enabled
+func (s *Strategy) recordDecision(bestBid, bestAsk, bidMargin, askMargin fixedpoint.Value, submitOrders []types.SubmitOrder) {
+	if s.decisionRecorder == nil {
+		return
+	}
+
+	if err := s.decisionRecorder.Record(quoteDecision{
+		Time:            time.Now(),
+		BestBid:         bestBid,
+		BestAsk:         bestAsk,
+		ReferencePrice:  s.lastPrice,
+		BidMargin:       bidMargin,
+		AskMargin:       askMargin,
+		Orders:          submitOrders,
+		Position:        s.Position.GetBase(),
+		CoveredPosition: s.CoveredPosition,
+	}); err != nil {
+		s.logger().WithError(err).Errorf("%s unable to export the quote decision", s.Symbol)
+	}
+}
@@ -0,0 +1,57 @@
+package xmaker
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func Test_decisionRecorder(t *testing.T) {
+	dir := t.TempDir()
+	recorder := newDecisionRecorder(dir, "BTCUSDT", time.Hour)
+
+	recorder.AddTrade(types.Trade{Side: types.SideTypeBuy, Quantity: fixedpoint.NewFromFloat(0.1)}, false)
+	recorder.AddTrade(types.Trade{Side: types.SideTypeSell, Quantity: fixedpoint.NewFromFloat(0.1)}, true)
+	assert.Equal(t, fixedpoint.NewFromFloat(0.1), recorder.fillBuy)
+	assert.Equal(t, fixedpoint.NewFromFloat(0.1), recorder.hedgeSell)
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	decision := quoteDecision{
+		Time:           now,
+		BestBid:        fixedpoint.NewFromFloat(100.0),
+		BestAsk:        fixedpoint.NewFromFloat(101.0),
+		ReferencePrice: fixedpoint.NewFromFloat(100.5),
+		BidMargin:      fixedpoint.NewFromFloat(0.001),
+		AskMargin:      fixedpoint.NewFromFloat(0.001),
+		Orders: []types.SubmitOrder{
+			{Side: types.SideTypeBuy, Price: fixedpoint.NewFromFloat(99.9), Quantity: fixedpoint.NewFromFloat(0.1)},
+			{Side: types.SideTypeSell, Price: fixedpoint.NewFromFloat(101.1), Quantity: fixedpoint.NewFromFloat(0.1)},
+		},
+		Position: fixedpoint.NewFromFloat(0.1),
+	}
+	require.NoError(t, recorder.Record(decision))
+
+	// the accumulated fills and hedges are reset after the record
+	assert.Equal(t, fixedpoint.Zero, recorder.fillBuy)
+	assert.Equal(t, fixedpoint.Zero, recorder.hedgeSell)
+
+	// the file is rotated after the rotate interval
+	decision.Time = now.Add(time.Hour)
+	require.NoError(t, recorder.Record(decision))
+	require.NoError(t, recorder.Close())
+
+	for _, name := range []string{"xmaker-BTCUSDT-20240101T000000.parquet", "xmaker-BTCUSDT-20240101T010000.parquet"} {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if assert.NoError(t, err) {
+			assert.Equal(t, "PAR1", string(data[len(data)-4:]))
+			assert.Contains(t, string(data), `{"side":"BUY","price":99.90000000,"quantity":0.10000000}`)
+		}
+	}
+}
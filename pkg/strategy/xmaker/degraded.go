@@ -0,0 +1,63 @@
+package xmaker
+
+import (
+	"context"
+
+	"github.com/c9s/bbgo/pkg/bbgo"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// bindDegradedMode notifies the degraded mode events of the maker session and the source sessions
+func (s *Strategy) bindDegradedMode() {
+	sessions := map[string]*bbgo.ExchangeSession{s.MakerExchange: s.makerSession}
+	for name, session := range s.sourceSessions {
+		sessions[name] = session
+	}
+
+	for name, session := range sessions {
+		name := name
+		session.OnDegradedMode(func(degraded bool, status types.SystemStatus) {
+			if degraded {
+				s.logger().Warnf("%s venue %s is in the degraded mode: %s", s.Symbol, name, status.Message)
+			} else {
+				s.logger().Infof("%s venue %s is back to the normal mode", s.Symbol, name)
+			}
+		})
+	}
+}
+
+// degradedSources returns true if all the source sessions are in the degraded mode
+func (s *Strategy) degradedSources() bool {
+	for _, source := range s.sourceExchangeNames() {
+		if !s.sourceSessions[source].Degraded() {
+			return false
+		}
+	}
+
+	return true
+}
+
+// checkDegradedMode pulls the maker quotes when the maker session or all the source sessions are in the degraded mode,
+// it returns true when quoting should be paused.
+func (s *Strategy) checkDegradedMode(ctx context.Context) bool {
+	wasDegraded := s.degraded
+	s.degraded = s.makerSession.Degraded() || s.degradedSources()
+
+	switch {
+	case s.degraded && !wasDegraded:
+		degradedModeMetrics.With(s.metricsLabels()).Set(1)
+		bbgo.Notify("%s: the maker venue or the hedge venues are in the degraded mode, pulling the quotes", s.Symbol)
+
+	case !s.degraded && wasDegraded:
+		degradedModeMetrics.With(s.metricsLabels()).Set(0)
+		bbgo.Notify("%s: the venues are back to the normal mode, resuming quoting", s.Symbol)
+	}
+
+	if s.degraded && s.activeMakerOrders.NumOfOrders() > 0 {
+		if err := s.activeMakerOrders.GracefulCancel(ctx, s.makerSession.Exchange); err != nil {
+			s.logger().WithError(err).Errorf("unable to cancel the %s maker orders", s.Symbol)
+		}
+	}
+
+	return s.degraded
+}
@@ -0,0 +1,149 @@
+package xmaker
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// QuoteSkipReason is the reason why a quote update cycle didn't place any maker order
+type QuoteSkipReason string
+
+const (
+	// QuoteSkipReasonNone means the maker orders are placed
+	QuoteSkipReasonNone QuoteSkipReason = ""
+
+	// QuoteSkipReasonCancelPending means the previous maker orders are not canceled yet
+	QuoteSkipReasonCancelPending QuoteSkipReason = "cancel_pending"
+
+	// QuoteSkipReasonCircuitBreaker means quoting is halted by the drawdown circuit breaker
+	QuoteSkipReasonCircuitBreaker QuoteSkipReason = "circuit_breaker"
+
+	// QuoteSkipReasonTradingCalendar means the maker venue or the hedge venues are paused by the trading calendar
+	QuoteSkipReasonTradingCalendar QuoteSkipReason = "trading_calendar"
+
+	// QuoteSkipReasonDegradedMode means the maker venue or all the hedge venues are in the degraded mode
+	QuoteSkipReasonDegradedMode QuoteSkipReason = "degraded_mode"
+
+	// QuoteSkipReasonSuspended means quoting is suspended by the operator
+	QuoteSkipReasonSuspended QuoteSkipReason = "suspended"
+
+	// QuoteSkipReasonStalePrice means the source price, the index price or the conversion rate is not updating
+	QuoteSkipReasonStalePrice QuoteSkipReason = "stale_price"
+
+	// QuoteSkipReasonPriceBand means the source mid-price is out of the price band
+	QuoteSkipReasonPriceBand QuoteSkipReason = "price_band"
+
+	// QuoteSkipReasonInvalidBook means the source order book is invalid, e.g., crossed
+	QuoteSkipReasonInvalidBook QuoteSkipReason = "invalid_book"
+
+	// QuoteSkipReasonNoBalance means both sides are disabled by the balances, the exposure, the wind-down or the toxic flow
+	QuoteSkipReasonNoBalance QuoteSkipReason = "no_balance"
+
+	// QuoteSkipReasonIndicatorNotReady means the bollinger band is not ready yet
+	QuoteSkipReasonIndicatorNotReady QuoteSkipReason = "indicator_not_ready"
+
+	// QuoteSkipReasonNoLayers means none of the layers passed the minimal quantity, the maker book or the quota checks
+	QuoteSkipReasonNoLayers QuoteSkipReason = "no_layers"
+)
+
+// QuoteCycleResult is the result of a quote update cycle
+type QuoteCycleResult struct {
+	Time      time.Time       `json:"time"`
+	Reason    QuoteSkipReason `json:"reason,omitempty"`
+	NumOrders int             `json:"numOrders"`
+}
+
+// QuoteDiagnostics is the snapshot of the recent quote update cycles
+type QuoteDiagnostics struct {
+	LastCycle QuoteCycleResult `json:"lastCycle"`
+
+	// LastQuoteTime is the time of the last cycle that placed the maker orders
+	LastQuoteTime time.Time `json:"lastQuoteTime"`
+
+	// SkipCounts is the number of the skipped cycles by the reason since the strategy started
+	SkipCounts map[QuoteSkipReason]int `json:"skipCounts"`
+}
+
+// quoteDiagnostics records the results of the quote update cycles
+type quoteDiagnostics struct {
+	mu sync.Mutex
+
+	lastCycle     QuoteCycleResult
+	lastQuoteTime time.Time
+	skipCounts    map[QuoteSkipReason]int
+}
+
+func (d *quoteDiagnostics) Record(result QuoteCycleResult) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.lastCycle = result
+	if result.Reason == QuoteSkipReasonNone {
+		d.lastQuoteTime = result.Time
+		return
+	}
+
+	if d.skipCounts == nil {
+		d.skipCounts = make(map[QuoteSkipReason]int)
+	}
+
+	d.skipCounts[result.Reason]++
+}
+
+func (d *quoteDiagnostics) Snapshot() QuoteDiagnostics {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	skipCounts := make(map[QuoteSkipReason]int, len(d.skipCounts))
+	for reason, count := range d.skipCounts {
+		skipCounts[reason] = count
+	}
+
+	return QuoteDiagnostics{
+		LastCycle:     d.lastCycle,
+		LastQuoteTime: d.lastQuoteTime,
+		SkipCounts:    skipCounts,
+	}
+}
+
+// QuoteDiagnostics returns why the recent quote update cycles didn't place any maker order
+func (s *Strategy) QuoteDiagnostics() interface{} {
+	return s.quoteDiagnostics.Snapshot()
+}
+
+// recordQuoteCycle records the result of the quote update cycle, the skipped cycles are counted by the reason
+func (s *Strategy) recordQuoteCycle(reason QuoteSkipReason, numOrders int) {
+	s.quoteDiagnostics.Record(QuoteCycleResult{
+		Time:      time.Now(),
+		Reason:    reason,
+		NumOrders: numOrders,
+	})
+
+	if reason != QuoteSkipReasonNone {
+		labels := s.metricsLabels()
+		labels["reason"] = string(reason)
+		quoteSkippedMetrics.With(labels).Inc()
+	}
+}
+
+// quotingPaused checks the circuit breaker, the trading calendar, the degraded mode and the operator suspension,
+// it returns true and records the skip reason when quoting should be paused.
+func (s *Strategy) quotingPaused(ctx context.Context) bool {
+	var reason QuoteSkipReason
+	switch {
+	case s.checkDrawdownHalt(ctx):
+		reason = QuoteSkipReasonCircuitBreaker
+	case s.checkTradingCalendar(ctx):
+		reason = QuoteSkipReasonTradingCalendar
+	case s.checkDegradedMode(ctx):
+		reason = QuoteSkipReasonDegradedMode
+	case s.suspended():
+		reason = QuoteSkipReasonSuspended
+	default:
+		return false
+	}
+
+	s.recordQuoteCycle(reason, 0)
+	return true
+}
@@ -0,0 +1,35 @@
+package xmaker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStrategy_recordQuoteCycle(t *testing.T) {
+	s := &Strategy{Symbol: "BTCUSDT", MakerExchange: "max"}
+
+	s.recordQuoteCycle(QuoteSkipReasonStalePrice, 0)
+	s.recordQuoteCycle(QuoteSkipReasonStalePrice, 0)
+	s.recordQuoteCycle(QuoteSkipReasonCancelPending, 0)
+
+	diagnostics := s.QuoteDiagnostics().(QuoteDiagnostics)
+	assert.Equal(t, QuoteSkipReasonCancelPending, diagnostics.LastCycle.Reason)
+	assert.True(t, diagnostics.LastQuoteTime.IsZero())
+	assert.Equal(t, map[QuoteSkipReason]int{
+		QuoteSkipReasonStalePrice:    2,
+		QuoteSkipReasonCancelPending: 1,
+	}, diagnostics.SkipCounts)
+
+	s.recordQuoteCycle(QuoteSkipReasonNone, 4)
+
+	diagnostics = s.QuoteDiagnostics().(QuoteDiagnostics)
+	assert.Equal(t, QuoteSkipReasonNone, diagnostics.LastCycle.Reason)
+	assert.Equal(t, 4, diagnostics.LastCycle.NumOrders)
+	assert.Equal(t, diagnostics.LastCycle.Time, diagnostics.LastQuoteTime)
+	assert.Equal(t, 2, diagnostics.SkipCounts[QuoteSkipReasonStalePrice])
+
+	// the snapshot is not affected by the following cycles
+	s.recordQuoteCycle(QuoteSkipReasonStalePrice, 0)
+	assert.Equal(t, 2, diagnostics.SkipCounts[QuoteSkipReasonStalePrice])
+}
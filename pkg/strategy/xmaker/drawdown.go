@@ -0,0 +1,81 @@
+package xmaker
+
+import (
+	"context"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/bbgo"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// equity calculates the total equity of the maker session and the source sessions in the quote currency
+func (s *Strategy) equity(price fixedpoint.Value) fixedpoint.Value {
+	sessions := []*bbgo.ExchangeSession{s.makerSession}
+	for _, source := range s.sourceExchangeNames() {
+		sessions = append(sessions, s.sourceSessions[source])
+	}
+
+	var equity fixedpoint.Value
+	for _, session := range sessions {
+		balances := session.GetAccount().Balances()
+		if b, ok := balances[s.makerMarket.BaseCurrency]; ok {
+			equity = equity.Add(b.Net().Mul(price))
+		}
+
+		if b, ok := balances[s.makerMarket.QuoteCurrency]; ok {
+			equity = equity.Add(b.Net())
+		}
+	}
+
+	return equity
+}
+
+// checkDrawdownHalt checks the drawdown circuit breaker, it cancels the maker orders and flattens the uncovered position
+// (unless the halt action is cancel) when the circuit breaker is triggered, and returns true when quoting should be halted.
+func (s *Strategy) checkDrawdownHalt(ctx context.Context) bool {
+	if s.drawdownCircuitBreaker == nil {
+		return false
+	}
+
+	now := time.Now()
+	wasHalted := s.drawdownHalted
+
+	price := s.lastPrice
+	if bid, ask, ok := s.book.BestBidAndAskOf(s.sourceExchangeNames()...); ok {
+		price = s.convertSourcePrice(bid.Price.Add(ask.Price).Div(fixedpoint.Two))
+	}
+
+	s.drawdownHalted = s.drawdownCircuitBreaker.IsHalted(now, s.equity(price))
+	drawdownMetrics.With(s.metricsLabels()).Set(s.drawdownCircuitBreaker.Drawdown().Float64())
+
+	switch {
+	case s.drawdownHalted && !wasHalted:
+		drawdownHaltedMetrics.With(s.metricsLabels()).Set(1)
+		bbgo.Notify("%s: drawdown %s exceeds the max drawdown %s, halting quoting (%s) for %s",
+			s.Symbol,
+			s.drawdownCircuitBreaker.Drawdown().Percentage(),
+			s.MaxDrawdown.Percentage(),
+			s.DrawdownHaltAction,
+			s.DrawdownHaltDuration.Duration())
+
+		if err := s.activeMakerOrders.GracefulCancel(ctx, s.makerSession.Exchange); err != nil {
+			s.logger().WithError(err).Errorf("unable to cancel the %s maker orders", s.Symbol)
+		}
+
+		if s.DrawdownHaltAction == types.HaltActionCancel {
+			break
+		}
+
+		s.tradeCollector.Process()
+		if uncoverPosition := s.Position.GetBase().Sub(s.CoveredPosition); uncoverPosition.Abs().Compare(s.sourceMarket.MinQuantity) > 0 {
+			s.Hedge(ctx, uncoverPosition.Neg())
+		}
+
+	case !s.drawdownHalted && wasHalted:
+		drawdownHaltedMetrics.With(s.metricsLabels()).Set(0)
+		bbgo.Notify("%s: drawdown halt is over, resuming quoting", s.Symbol)
+	}
+
+	return s.drawdownHalted
+}
@@ -0,0 +1,40 @@
+package xmaker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/bbgo"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func newTestSessionWithBalances(balances types.BalanceMap) *bbgo.ExchangeSession {
+	account := types.NewAccount()
+	account.UpdateBalances(balances)
+	return &bbgo.ExchangeSession{Account: account}
+}
+
+func TestStrategy_equity(t *testing.T) {
+	s := &Strategy{
+		SourceExchange: "binance",
+		makerSession: newTestSessionWithBalances(types.BalanceMap{
+			"BTC":  {Currency: "BTC", Available: fixedpoint.NewFromFloat(1.0)},
+			"USDT": {Currency: "USDT", Available: fixedpoint.NewFromFloat(1000.0)},
+		}),
+		sourceSessions: map[string]*bbgo.ExchangeSession{
+			"binance": newTestSessionWithBalances(types.BalanceMap{
+				"BTC":  {Currency: "BTC", Available: fixedpoint.NewFromFloat(0.5), Borrowed: fixedpoint.NewFromFloat(0.5)},
+				"USDT": {Currency: "USDT", Available: fixedpoint.NewFromFloat(2000.0)},
+			}),
+		},
+		makerMarket: types.Market{
+			BaseCurrency:  "BTC",
+			QuoteCurrency: "USDT",
+		},
+	}
+
+	// 1 BTC * 30000 + 1000 + (0.5 - 0.5) BTC * 30000 + 2000
+	assert.Equal(t, "33000", s.equity(fixedpoint.NewFromFloat(30000.0)).String())
+}
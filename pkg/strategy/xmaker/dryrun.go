@@ -0,0 +1,163 @@
+package xmaker
+
+import (
+	"sync"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// shadowQuoter simulates the maker orders and the hedges against the live order books in the dry-run mode,
+// no real order is submitted.
+type shadowQuoter struct {
+	mu sync.Mutex
+
+	// orders are the current shadow maker orders
+	orders []types.SubmitOrder
+
+	// position is the shadow position of both the maker fills and the hedges,
+	// so the base position is the uncovered position
+	position *types.Position
+
+	profit, netProfit fixedpoint.Value
+}
+
+func newShadowQuoter(market types.Market) *shadowQuoter {
+	return &shadowQuoter{
+		position: types.NewPositionFromMarket(market),
+	}
+}
+
+// matchShadowOrders fills the shadow orders that are crossed by the book,
+// a bid is filled when the best ask drops to its price, and an ask is filled when the best bid rises to its price.
+func matchShadowOrders(orders []types.SubmitOrder, book types.OrderBook) (remaining, filled []types.SubmitOrder) {
+	bestBid, hasBid := book.BestBid()
+	bestAsk, hasAsk := book.BestAsk()
+
+	for _, order := range orders {
+		switch {
+		case order.Side == types.SideTypeBuy && hasAsk && bestAsk.Price.Compare(order.Price) <= 0:
+			filled = append(filled, order)
+		case order.Side == types.SideTypeSell && hasBid && bestBid.Price.Compare(order.Price) >= 0:
+			filled = append(filled, order)
+		default:
+			remaining = append(remaining, order)
+		}
+	}
+
+	return remaining, filled
+}
+
+// newShadowTrade creates the simulated trade with the fee in the quote currency
+func newShadowTrade(
+	exchange types.ExchangeName, market types.Market, side types.SideType, price, quantity, feeRate fixedpoint.Value,
+	isMaker bool,
+) types.Trade {
+	quoteQuantity := price.Mul(quantity)
+	return types.Trade{
+		Exchange:      exchange,
+		Symbol:        market.Symbol,
+		Side:          side,
+		Price:         price,
+		Quantity:      quantity,
+		QuoteQuantity: quoteQuantity,
+		IsBuyer:       side == types.SideTypeBuy,
+		IsMaker:       isMaker,
+		Fee:           quoteQuantity.Mul(feeRate),
+		FeeCurrency:   market.QuoteCurrency,
+		Time:          types.Time(time.Now()),
+	}
+}
+
+// addTrade adds the shadow trade to the shadow position and accumulates the profit
+func (q *shadowQuoter) addTrade(trade types.Trade) {
+	if profit, netProfit, madeProfit := q.position.AddTrade(trade); madeProfit {
+		q.profit = q.profit.Add(profit)
+		q.netProfit = q.netProfit.Add(netProfit)
+	}
+}
+
+// shadowQuote replaces the shadow maker orders with the newly generated quotes after filling the crossed ones
+func (s *Strategy) shadowQuote(submitOrders []types.SubmitOrder) {
+	s.shadow.mu.Lock()
+	defer s.shadow.mu.Unlock()
+
+	s.matchShadowOrders()
+
+	for _, order := range submitOrders {
+		s.logger().Infof("[dry-run] %s quote: %s", s.Symbol, order.String())
+	}
+
+	s.shadow.orders = submitOrders
+	s.updateShadowMetrics()
+}
+
+// matchShadowOrders fills the crossed shadow orders by the maker book and hedges the fills on the source book
+func (s *Strategy) matchShadowOrders() {
+	if s.makerBook == nil || len(s.shadow.orders) == 0 {
+		return
+	}
+
+	remaining, filled := matchShadowOrders(s.shadow.orders, s.makerBook.CopyDepth(1))
+	s.shadow.orders = remaining
+
+	for _, order := range filled {
+		trade := newShadowTrade(s.makerSession.ExchangeName, s.makerMarket, order.Side, order.Price, order.Quantity,
+			s.makerSession.MakerFeeRate, true)
+		s.logger().Infof("[dry-run] %s maker order filled: %s", s.Symbol, order.String())
+		s.shadow.addTrade(trade)
+
+		labels := s.metricsLabels()
+		labels["side"] = order.Side.String()
+		shadowFilledQuantityMetrics.With(labels).Add(order.Quantity.Float64())
+	}
+
+	if len(filled) > 0 && !s.DisableHedge {
+		s.shadowHedge()
+	}
+}
+
+// shadowHedge covers the uncovered shadow position at the best price of the source book
+func (s *Strategy) shadowHedge() {
+	uncoverPosition := s.shadow.position.GetBase()
+	quantity := s.sourceMarket.TruncateQuantity(uncoverPosition.Abs())
+	if quantity.Compare(s.sourceMarket.MinQuantity) < 0 {
+		return
+	}
+
+	side := types.SideTypeSell
+	if uncoverPosition.Sign() < 0 {
+		side = types.SideTypeBuy
+	}
+
+	sourceExchange, sourceSession, sourceMarket := s.selectHedgeSource(side, quantity)
+	sourceBook := s.book.CopyDepthOf(1, sourceExchange)
+
+	var price fixedpoint.Value
+	switch side {
+	case types.SideTypeBuy:
+		if bestAsk, ok := sourceBook.BestAsk(); ok {
+			price = bestAsk.Price
+		}
+	case types.SideTypeSell:
+		if bestBid, ok := sourceBook.BestBid(); ok {
+			price = bestBid.Price
+		}
+	}
+
+	if price.IsZero() {
+		s.logger().Warnf("[dry-run] %s source book of %s is empty, skipping shadow hedge", s.Symbol, sourceExchange)
+		return
+	}
+
+	s.logger().Infof("[dry-run] %s hedge %s %v @ %v on %s", s.Symbol, side, quantity, price, sourceExchange)
+	trade := newShadowTrade(sourceSession.ExchangeName, sourceMarket, side, price, quantity, sourceSession.TakerFeeRate, false)
+	s.shadow.addTrade(trade)
+}
+
+func (s *Strategy) updateShadowMetrics() {
+	labels := s.metricsLabels()
+	shadowPositionMetrics.With(labels).Set(s.shadow.position.GetBase().Float64())
+	shadowProfitMetrics.With(labels).Set(s.shadow.netProfit.Float64())
+}
@@ -0,0 +1,52 @@
+package xmaker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestMatchShadowOrders(t *testing.T) {
+	number := fixedpoint.MustNewFromString
+
+	book := types.NewSliceOrderBook("BTCUSDT")
+	book.Load(types.SliceOrderBook{
+		Symbol: "BTCUSDT",
+		Bids:   types.PriceVolumeSlice{{Price: number("99"), Volume: number("1")}},
+		Asks:   types.PriceVolumeSlice{{Price: number("100"), Volume: number("1")}},
+	})
+
+	orders := []types.SubmitOrder{
+		{Side: types.SideTypeBuy, Price: number("100"), Quantity: number("0.1")},
+		{Side: types.SideTypeBuy, Price: number("98"), Quantity: number("0.2")},
+		{Side: types.SideTypeSell, Price: number("99"), Quantity: number("0.3")},
+		{Side: types.SideTypeSell, Price: number("101"), Quantity: number("0.4")},
+	}
+
+	remaining, filled := matchShadowOrders(orders, book)
+	if assert.Len(t, filled, 2) {
+		assert.Equal(t, "0.1", filled[0].Quantity.String())
+		assert.Equal(t, "0.3", filled[1].Quantity.String())
+	}
+
+	if assert.Len(t, remaining, 2) {
+		assert.Equal(t, "98", remaining[0].Price.String())
+		assert.Equal(t, "101", remaining[1].Price.String())
+	}
+}
+
+func TestShadowQuoter_AddTrade(t *testing.T) {
+	number := fixedpoint.MustNewFromString
+	market := types.Market{Symbol: "BTCUSDT", BaseCurrency: "BTC", QuoteCurrency: "USDT"}
+
+	q := newShadowQuoter(market)
+	q.addTrade(newShadowTrade(types.ExchangeMax, market, types.SideTypeBuy, number("100"), number("1"), fixedpoint.Zero, true))
+	assert.Equal(t, "1", q.position.GetBase().String())
+
+	q.addTrade(newShadowTrade(types.ExchangeBinance, market, types.SideTypeSell, number("101"), number("1"), fixedpoint.Zero, false))
+	assert.True(t, q.position.GetBase().IsZero())
+	assert.Equal(t, "1", q.profit.String())
+}
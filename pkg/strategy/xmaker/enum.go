@@ -0,0 +1,22 @@
+package xmaker
+
+import (
+	"encoding/json"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// unmarshalEnum parses the enum string of the config, the empty string is kept for the defaults,
+// and the unknown value is rejected with the valid values and the closest one
+func unmarshalEnum[T ~string](data []byte, name string, values ...T) (T, error) {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return "", err
+	}
+
+	if s == "" {
+		return "", nil
+	}
+
+	return types.ParseEnum(name, s, values...)
+}
@@ -0,0 +1,114 @@
+package xmaker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/bbgo"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/pricesolver"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+const accountEquityUpdateInterval = time.Minute
+
+// equityQuantity converts the equity ratio of the account value to the base quantity at the price
+func equityQuantity(equity, ratio, price fixedpoint.Value) fixedpoint.Value {
+	if equity.Sign() <= 0 || ratio.Sign() <= 0 || price.Sign() <= 0 {
+		return fixedpoint.Zero
+	}
+
+	return equity.Mul(ratio).Div(price)
+}
+
+// updateAccountEquity updates the total account value of the maker session in the quote currency
+func (s *Strategy) updateAccountEquity(ctx context.Context) {
+	if err := s.accountValueCalculator.UpdatePrices(ctx); err != nil {
+		s.logger().WithError(err).Errorf("unable to update the prices for the %s account value", s.Symbol)
+		return
+	}
+
+	equity, err := s.accountValueCalculator.NetValue(ctx)
+	if err != nil {
+		s.logger().WithError(err).Errorf("unable to calculate the %s account value", s.Symbol)
+		return
+	}
+
+	s.equityMu.Lock()
+	s.accountEquity = equity
+	s.equityMu.Unlock()
+
+	accountEquityMetrics.With(s.metricsLabels()).Set(equity.Float64())
+}
+
+func (s *Strategy) getAccountEquity() fixedpoint.Value {
+	s.equityMu.Lock()
+	defer s.equityMu.Unlock()
+	return s.accountEquity
+}
+
+// getMaxExposurePosition returns the max exposure position in the base quantity,
+// it's converted from the account value when the maxExposurePositionByEquityRatio is set.
+func (s *Strategy) getMaxExposurePosition() fixedpoint.Value {
+	if s.MaxExposurePositionByEquityRatio.Sign() > 0 {
+		if maxExposure := equityQuantity(s.getAccountEquity(), s.MaxExposurePositionByEquityRatio, s.lastPrice); maxExposure.Sign() > 0 {
+			return maxExposure
+		}
+	}
+
+	return s.MaxExposurePosition
+}
+
+// runAccountEquityUpdater keeps the account value up-to-date for sizing the layer quantities
+// and the max exposure position by the equity ratio
+func (s *Strategy) runAccountEquityUpdater(ctx context.Context, sessions map[string]*bbgo.ExchangeSession) error {
+	s.accountValueCalculator = bbgo.NewAccountValueCalculator(s.makerSession, s.makerMarket.QuoteCurrency)
+
+	if s.IndexPriceFeed != nil {
+		var exchangeIndex pricesolver.FXRateProvider
+		if name := s.IndexPriceFeed.ExchangeIndex; name != "" {
+			session, ok := sessions[name]
+			if !ok {
+				return fmt.Errorf("index price feed: session %s is not defined", name)
+			}
+
+			service, ok := session.Exchange.(types.FundingRateService)
+			if !ok {
+				return fmt.Errorf("index price feed: exchange %s does not provide the index prices", session.ExchangeName)
+			}
+
+			exchangeIndex = pricesolver.NewExchangeIndexPriceProvider(service)
+		}
+
+		solver, feed, err := pricesolver.NewIndexPriceFeed(s.IndexPriceFeed, exchangeIndex)
+		if err != nil {
+			return err
+		}
+
+		// update the index prices before the first account value update
+		_ = feed.Update(ctx)
+		go feed.Run(ctx)
+
+		s.accountValueCalculator.SetPriceResolver(solver)
+	}
+
+	s.updateAccountEquity(ctx)
+
+	go func() {
+		ticker := time.NewTicker(accountEquityUpdateInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+
+			case <-ticker.C:
+				s.updateAccountEquity(ctx)
+			}
+		}
+	}()
+
+	return nil
+}
@@ -0,0 +1,40 @@
+package xmaker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+)
+
+func Test_equityQuantity(t *testing.T) {
+	equity := fixedpoint.NewFromFloat(20000.0)
+	ratio := fixedpoint.NewFromFloat(0.01)
+
+	assert.Equal(t, "0.005", equityQuantity(equity, ratio, fixedpoint.NewFromFloat(40000.0)).String())
+
+	// the quantity grows with the account value
+	assert.Equal(t, "0.01", equityQuantity(equity.Mul(fixedpoint.Two), ratio, fixedpoint.NewFromFloat(40000.0)).String())
+
+	assert.True(t, equityQuantity(fixedpoint.Zero, ratio, fixedpoint.NewFromFloat(40000.0)).IsZero())
+	assert.True(t, equityQuantity(equity, ratio, fixedpoint.Zero).IsZero())
+}
+
+func TestStrategy_getMaxExposurePosition(t *testing.T) {
+	s := &Strategy{
+		MaxExposurePosition:              fixedpoint.NewFromFloat(0.1),
+		MaxExposurePositionByEquityRatio: fixedpoint.NewFromFloat(0.2),
+		lastPrice:                        fixedpoint.NewFromFloat(40000.0),
+	}
+
+	// fallback to the fixed max exposure before the account value is updated
+	assert.Equal(t, "0.1", s.getMaxExposurePosition().String())
+
+	s.accountEquity = fixedpoint.NewFromFloat(100000.0)
+	assert.Equal(t, "0.5", s.getMaxExposurePosition().String())
+
+	// the limit shrinks as the account draws down
+	s.accountEquity = fixedpoint.NewFromFloat(50000.0)
+	assert.Equal(t, "0.25", s.getMaxExposurePosition().String())
+}
@@ -0,0 +1,52 @@
+package xmaker
+
+import (
+	"context"
+
+	"github.com/c9s/bbgo/pkg/bbgo"
+	"github.com/c9s/bbgo/pkg/core"
+	"github.com/c9s/bbgo/pkg/pricesolver"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// subscribeFeeSymbols subscribes the klines of the fee symbols for pricing the fee currencies
+func (s *Strategy) subscribeFeeSymbols(sourceSession *bbgo.ExchangeSession) {
+	for _, symbol := range s.FeeSymbols {
+		sourceSession.Subscribe(types.KLineChannel, symbol, types.SubscribeOptions{Interval: types.Interval1m})
+	}
+}
+
+// feePriceSolver returns the price solver of the fee currencies, the price solver of the index price is reused if it's set.
+// The fee symbol prices are bootstrapped from the tickers, so that the fees can be converted before the first kline.
+func (s *Strategy) feePriceSolver(ctx context.Context) *pricesolver.SimplePriceSolver {
+	solver := s.priceSolver
+	if solver == nil {
+		solver = pricesolver.NewSimplePriceResolver(s.sourceSession.Markets())
+		solver.BindStream(s.sourceSession.MarketDataStream)
+	}
+
+	if err := solver.UpdateFromTickers(ctx, s.sourceSession.Exchange, s.FeeSymbols...); err != nil {
+		s.logger().WithError(err).Warnf("unable to query the fee symbol tickers %v", s.FeeSymbols)
+	}
+
+	return solver
+}
+
+// bindFeeCurrencyConverter converts the fees paid in the third currencies, e.g. BNB, into the quote currency of the trades
+// before the maker trades and the hedge trades are added to the position and the profit stats
+func (s *Strategy) bindFeeCurrencyConverter(resolver core.PriceResolver) {
+	markets := types.MarketMap{}
+	for symbol, market := range s.makerSession.Markets() {
+		markets[symbol] = market
+	}
+
+	for _, session := range s.sourceSessions {
+		for symbol, market := range session.Markets() {
+			if _, ok := markets[symbol]; !ok {
+				markets[symbol] = market
+			}
+		}
+	}
+
+	s.tradeCollector.AddConverter(core.NewFeeCurrencyConverter(resolver, markets))
+}
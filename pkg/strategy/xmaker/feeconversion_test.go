@@ -0,0 +1,64 @@
+package xmaker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/bbgo"
+	"github.com/c9s/bbgo/pkg/core"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/pricesolver"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestStrategy_bindFeeCurrencyConverter(t *testing.T) {
+	btcusdt := types.Market{Symbol: "BTCUSDT", BaseCurrency: "BTC", QuoteCurrency: "USDT"}
+	bnbusdt := types.Market{Symbol: "BNBUSDT", BaseCurrency: "BNB", QuoteCurrency: "USDT"}
+
+	makerSession := &bbgo.ExchangeSession{Name: "max"}
+	makerSession.SetMarkets(types.MarketMap{"BTCUSDT": btcusdt})
+
+	sourceSession := &bbgo.ExchangeSession{Name: "binance"}
+	sourceSession.SetMarkets(types.MarketMap{"BTCUSDT": btcusdt, "BNBUSDT": bnbusdt})
+
+	solver := pricesolver.NewSimplePriceResolver(sourceSession.Markets())
+	solver.Update("BNBUSDT", fixedpoint.NewFromFloat(500.0))
+
+	position := types.NewPositionFromMarket(btcusdt)
+	orderStore := core.NewOrderStore("BTCUSDT")
+	orderStore.Add(types.Order{OrderID: 1})
+
+	s := &Strategy{
+		Symbol:         "BTCUSDT",
+		FeeSymbols:     []string{"BNBUSDT"},
+		makerSession:   makerSession,
+		sourceSessions: map[string]*bbgo.ExchangeSession{"binance": sourceSession},
+		tradeCollector: core.NewTradeCollector("BTCUSDT", position, orderStore),
+	}
+	s.bindFeeCurrencyConverter(solver)
+
+	var collected types.Trade
+	s.tradeCollector.OnTrade(func(trade types.Trade, _, _ fixedpoint.Value) {
+		collected = trade
+	})
+
+	// the hedge trade paid the fee in BNB
+	assert.True(t, s.tradeCollector.ProcessTrade(types.Trade{
+		ID:            1,
+		OrderID:       1,
+		Exchange:      types.ExchangeBinance,
+		Symbol:        "BTCUSDT",
+		Side:          types.SideTypeBuy,
+		Price:         fixedpoint.NewFromInt(40000),
+		Quantity:      fixedpoint.One,
+		QuoteQuantity: fixedpoint.NewFromInt(40000),
+		Fee:           fixedpoint.NewFromFloat(0.01),
+		FeeCurrency:   "BNB",
+	}))
+
+	assert.Equal(t, "5", collected.FeeInQuote.String())
+
+	// the fee in BNB is added to the cost of the position
+	assert.Equal(t, "40005", position.ApproximateAverageCost.String())
+}
@@ -0,0 +1,66 @@
+package xmaker
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// FundingRateMargin tilts the bid/ask margins by the predicted funding rate when the hedges are placed on the perpetual market.
+//
+// With a positive funding rate the longs pay the shorts, so the maker bids (hedged by selling the perpetual)
+// accrue the funding income and are quoted tighter, while the maker asks are quoted wider, and vice versa.
+type FundingRateMargin struct {
+	Enabled bool `json:"enabled"`
+
+	// Factor scales the funding rate into the margin adjustment, e.g. 1.0 shifts the margins by the funding rate itself
+	Factor fixedpoint.Value `json:"factor"`
+
+	// MaxAdjustment caps the absolute margin adjustment, defaults to 0.001 (0.1%)
+	MaxAdjustment fixedpoint.Value `json:"maxAdjustment"`
+
+	// UpdateInterval is the polling interval of the funding rate, defaults to 1m
+	UpdateInterval types.Duration `json:"updateInterval"`
+}
+
+func (m *FundingRateMargin) Defaults() {
+	if m.Factor.IsZero() {
+		m.Factor = fixedpoint.One
+	}
+
+	if m.MaxAdjustment.IsZero() {
+		m.MaxAdjustment = fixedpoint.NewFromFloat(0.001)
+	}
+
+	if m.UpdateInterval == 0 {
+		m.UpdateInterval = types.Duration(time.Minute)
+	}
+}
+
+func (m *FundingRateMargin) Validate() error {
+	if m.Factor.Sign() < 0 {
+		return fmt.Errorf("fundingRateMargin factor should not be negative, got %v", m.Factor)
+	}
+
+	if m.MaxAdjustment.Sign() < 0 {
+		return fmt.Errorf("fundingRateMargin maxAdjustment should not be negative, got %v", m.MaxAdjustment)
+	}
+
+	return nil
+}
+
+// calculateFundingRateAdjustment converts the funding rate into the margin adjustment within [-maxAdjustment, +maxAdjustment]
+func calculateFundingRateAdjustment(fundingRate, factor, maxAdjustment fixedpoint.Value) fixedpoint.Value {
+	adjustment := fundingRate.Mul(factor)
+	return fixedpoint.Max(fixedpoint.Min(adjustment, maxAdjustment), maxAdjustment.Neg())
+}
+
+// applyFundingRateAdjustment tightens the margin of the side that accrues the funding income and widens the paying side,
+// a positive adjustment means the longs pay the shorts.
+func applyFundingRateAdjustment(bidMargin, askMargin, adjustment fixedpoint.Value) (fixedpoint.Value, fixedpoint.Value) {
+	bidMargin = fixedpoint.Max(bidMargin.Sub(adjustment), fixedpoint.Zero)
+	askMargin = fixedpoint.Max(askMargin.Add(adjustment), fixedpoint.Zero)
+	return bidMargin, askMargin
+}
@@ -0,0 +1,28 @@
+package xmaker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+)
+
+func TestFundingRateAdjustment(t *testing.T) {
+	number := fixedpoint.MustNewFromString
+	maxAdjustment := number("0.001")
+
+	assert.Equal(t, "0.0001", calculateFundingRateAdjustment(number("0.0001"), fixedpoint.One, maxAdjustment).String())
+	assert.Equal(t, "0.001", calculateFundingRateAdjustment(number("0.01"), fixedpoint.One, maxAdjustment).String())
+	assert.Equal(t, "-0.001", calculateFundingRateAdjustment(number("-0.01"), fixedpoint.One, maxAdjustment).String())
+
+	// positive funding rate: the bids accrue the funding income and are quoted tighter
+	bidMargin, askMargin := applyFundingRateAdjustment(number("0.002"), number("0.002"), number("0.0005"))
+	assert.Equal(t, "0.0015", bidMargin.String())
+	assert.Equal(t, "0.0025", askMargin.String())
+
+	// negative funding rate: the asks accrue the funding income, the margin does not go below zero
+	bidMargin, askMargin = applyFundingRateAdjustment(number("0.002"), number("0.0003"), number("-0.0005"))
+	assert.Equal(t, "0.0025", bidMargin.String())
+	assert.Equal(t, "0", askMargin.String())
+}
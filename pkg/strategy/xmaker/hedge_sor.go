@@ -0,0 +1,268 @@
+package xmaker
+
+import (
+	"context"
+	"sort"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+const defaultHedgeRoutingDepth = 20
+
+// hedgeVenue is the routing input of one source session
+type hedgeVenue struct {
+	source string
+	market types.Market
+
+	// levels is the book side that the hedge order takes, asks for buying and bids for selling
+	levels types.PriceVolumeSlice
+
+	// feeRate is the taker fee rate of the source session
+	feeRate fixedpoint.Value
+
+	// capacity is the max base quantity that the balances of the source session can hedge
+	capacity fixedpoint.Value
+}
+
+// hedgeRoute is the hedge quantity assigned to one source session
+type hedgeRoute struct {
+	source   string
+	quantity fixedpoint.Value
+}
+
+// effectivePrice returns the price including the taker fee
+func effectivePrice(side types.SideType, price, feeRate fixedpoint.Value) fixedpoint.Value {
+	if side == types.SideTypeBuy {
+		return price.Mul(fixedpoint.One.Add(feeRate))
+	}
+
+	return price.Mul(fixedpoint.One.Sub(feeRate))
+}
+
+// splitHedgeQuantity splits the hedge quantity across the venues.
+//
+// The price levels of all the venues are taken from the best effective price (fee included),
+// and each venue takes no more than its balance capacity. The quantity beyond the available depth is assigned
+// to the venues with the best price that still have capacity. The routes that are smaller than the
+// minimal quantity or the minimal notional of their market are merged into the largest route.
+func splitHedgeQuantity(side types.SideType, quantity fixedpoint.Value, venues []hedgeVenue) []hedgeRoute {
+	type venueLevel struct {
+		venue  int
+		price  fixedpoint.Value
+		volume fixedpoint.Value
+	}
+
+	var levels []venueLevel
+	for i, venue := range venues {
+		for _, pv := range venue.levels {
+			levels = append(levels, venueLevel{
+				venue:  i,
+				price:  effectivePrice(side, pv.Price, venue.feeRate),
+				volume: pv.Volume,
+			})
+		}
+	}
+
+	sort.SliceStable(levels, func(i, j int) bool {
+		if side == types.SideTypeBuy {
+			return levels[i].price.Compare(levels[j].price) < 0
+		}
+
+		return levels[i].price.Compare(levels[j].price) > 0
+	})
+
+	allocated := make([]fixedpoint.Value, len(venues))
+	remaining := quantity
+
+	allocate := func(venue int, maxQuantity fixedpoint.Value) {
+		room := venues[venue].capacity.Sub(allocated[venue])
+		take := fixedpoint.Min(fixedpoint.Min(maxQuantity, room), remaining)
+		if take.Sign() <= 0 {
+			return
+		}
+
+		allocated[venue] = allocated[venue].Add(take)
+		remaining = remaining.Sub(take)
+	}
+
+	var venueOrder []int
+	seen := make(map[int]struct{})
+	for _, level := range levels {
+		if remaining.Sign() <= 0 {
+			break
+		}
+
+		if _, ok := seen[level.venue]; !ok {
+			seen[level.venue] = struct{}{}
+			venueOrder = append(venueOrder, level.venue)
+		}
+
+		allocate(level.venue, level.volume)
+	}
+
+	// the available depth is not enough, assign the rest to the venues by their best prices
+	for _, venue := range venueOrder {
+		if remaining.Sign() <= 0 {
+			break
+		}
+
+		allocate(venue, remaining)
+	}
+
+	// merge the routes that are too small for their market into the largest route that has room
+	for i, venue := range venues {
+		if allocated[i].IsZero() || !isTooSmallForMarket(venue, allocated[i]) {
+			continue
+		}
+
+		largest := -1
+		for j := range venues {
+			if j == i || allocated[j].IsZero() {
+				continue
+			}
+
+			if largest == -1 || allocated[j].Compare(allocated[largest]) > 0 {
+				largest = j
+			}
+		}
+
+		if largest != -1 && venues[largest].capacity.Sub(allocated[largest]).Compare(allocated[i]) >= 0 {
+			allocated[largest] = allocated[largest].Add(allocated[i])
+		}
+
+		allocated[i] = fixedpoint.Zero
+	}
+
+	var routes []hedgeRoute
+	for i, venue := range venues {
+		if allocated[i].Sign() > 0 {
+			routes = append(routes, hedgeRoute{source: venue.source, quantity: allocated[i]})
+		}
+	}
+
+	sort.SliceStable(routes, func(i, j int) bool {
+		return routes[i].quantity.Compare(routes[j].quantity) > 0
+	})
+
+	return routes
+}
+
+func isTooSmallForMarket(venue hedgeVenue, quantity fixedpoint.Value) bool {
+	if quantity.Compare(venue.market.MinQuantity) < 0 {
+		return true
+	}
+
+	if len(venue.levels) > 0 && quantity.Mul(venue.levels[0].Price).Compare(venue.market.MinNotional) < 0 {
+		return true
+	}
+
+	return false
+}
+
+// hedgeCapacity returns the max base quantity that the balances of the source session can hedge
+func (s *Strategy) hedgeCapacity(source string, side types.SideType, price fixedpoint.Value) fixedpoint.Value {
+	market := s.sourceMarkets[source]
+	account := s.sourceSessions[source].GetAccount()
+
+	switch side {
+	case types.SideTypeBuy:
+		quote, ok := account.Balance(market.QuoteCurrency)
+		if !ok || price.IsZero() {
+			return fixedpoint.Zero
+		}
+
+		available := quote.Available.Sub(s.StopHedgeQuoteBalance)
+		return fixedpoint.Max(available.Div(price.Mul(lastPriceModifier)), fixedpoint.Zero)
+
+	case types.SideTypeSell:
+		base, ok := account.Balance(market.BaseCurrency)
+		if !ok {
+			return fixedpoint.Zero
+		}
+
+		return fixedpoint.Max(base.Available.Sub(s.StopHedgeBaseBalance), fixedpoint.Zero)
+	}
+
+	return fixedpoint.Zero
+}
+
+// hedgeVenues collects the routing inputs of the source sessions
+func (s *Strategy) hedgeVenues(side types.SideType) (venues []hedgeVenue) {
+	bookSide := types.SideTypeSell
+	if side == types.SideTypeSell {
+		bookSide = types.SideTypeBuy
+	}
+
+	for _, source := range s.book.Sources() {
+		book, _ := s.book.Source(source)
+		levels := book.CopyDepth(s.HedgeRoutingDepth).SideBook(bookSide)
+		if len(levels) == 0 {
+			continue
+		}
+
+		session := s.sourceSessions[source]
+		venues = append(venues, hedgeVenue{
+			source:   source,
+			market:   s.sourceMarkets[source],
+			levels:   levels,
+			feeRate:  session.TakerFeeRate,
+			capacity: s.hedgeCapacity(source, side, levels[0].Price),
+		})
+	}
+
+	return venues
+}
+
+// hedgeBySmartRouting splits the hedge quantity across the source sessions and submits one hedge order per session
+func (s *Strategy) hedgeBySmartRouting(ctx context.Context, side types.SideType, quantity fixedpoint.Value) {
+	routes := splitHedgeQuantity(side, quantity, s.hedgeVenues(side))
+	if len(routes) == 0 {
+		s.logger().Warnf("%s no source session can hedge %s %v, skipping hedge", s.Symbol, side, quantity)
+		return
+	}
+
+	for _, route := range routes {
+		market := s.sourceMarkets[route.source]
+		routeQuantity := market.TruncateQuantity(route.quantity)
+		if routeQuantity.Compare(market.MinQuantity) < 0 {
+			continue
+		}
+
+		if err := s.submitHedgeOrder(ctx, route.source, side, routeQuantity); err != nil {
+			return
+		}
+	}
+}
+
+// sourceNameOf returns the source session name of the exchange
+func (s *Strategy) sourceNameOf(exchangeName types.ExchangeName) (string, bool) {
+	for _, source := range s.sourceExchangeNames() {
+		if s.sourceSessions[source].ExchangeName == exchangeName {
+			return source, true
+		}
+	}
+
+	return "", false
+}
+
+// updateVenueCoveredPosition records the hedge trade in the covered position of its source session
+func (s *Strategy) updateVenueCoveredPosition(trade types.Trade) {
+	source, ok := s.sourceNameOf(trade.Exchange)
+	if !ok {
+		return
+	}
+
+	s.venueCoveredPositionsMu.Lock()
+	if s.VenueCoveredPositions == nil {
+		s.VenueCoveredPositions = make(map[string]fixedpoint.Value)
+	}
+
+	position := s.VenueCoveredPositions[source].Add(trade.PositionChange())
+	s.VenueCoveredPositions[source] = position
+	s.venueCoveredPositionsMu.Unlock()
+
+	labels := s.metricsLabels()
+	labels["venue"] = source
+	hedgeVenueCoveredPositionMetrics.With(labels).Set(position.Float64())
+}
@@ -0,0 +1,105 @@
+package xmaker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func newTestHedgeVenue(source string, feeRate, capacity float64, levels ...float64) hedgeVenue {
+	venue := hedgeVenue{
+		source:   source,
+		market:   types.Market{Symbol: "BTCUSDT", MinQuantity: fixedpoint.NewFromFloat(0.001)},
+		feeRate:  fixedpoint.NewFromFloat(feeRate),
+		capacity: fixedpoint.NewFromFloat(capacity),
+	}
+
+	for i := 0; i+1 < len(levels); i += 2 {
+		venue.levels = append(venue.levels, types.PriceVolume{
+			Price:  fixedpoint.NewFromFloat(levels[i]),
+			Volume: fixedpoint.NewFromFloat(levels[i+1]),
+		})
+	}
+
+	return venue
+}
+
+func TestSplitHedgeQuantity(t *testing.T) {
+	number := fixedpoint.NewFromFloat
+
+	t.Run("split by depth", func(t *testing.T) {
+		routes := splitHedgeQuantity(types.SideTypeBuy, number(3), []hedgeVenue{
+			newTestHedgeVenue("a", 0, 100, 100, 1, 102, 5),
+			newTestHedgeVenue("b", 0, 100, 101, 1, 101.5, 5),
+		})
+		assert.Equal(t, []hedgeRoute{
+			{source: "b", quantity: number(2)},
+			{source: "a", quantity: number(1)},
+		}, routes)
+	})
+
+	t.Run("fee is included", func(t *testing.T) {
+		routes := splitHedgeQuantity(types.SideTypeBuy, number(3), []hedgeVenue{
+			newTestHedgeVenue("a", 0.02, 100, 100, 1, 102, 5),
+			newTestHedgeVenue("b", 0, 100, 101, 1, 101.5, 5),
+		})
+		assert.Equal(t, []hedgeRoute{
+			{source: "b", quantity: number(3)},
+		}, routes)
+	})
+
+	t.Run("balance capacity", func(t *testing.T) {
+		routes := splitHedgeQuantity(types.SideTypeBuy, number(3), []hedgeVenue{
+			newTestHedgeVenue("a", 0, 0.5, 100, 1, 102, 5),
+			newTestHedgeVenue("b", 0, 100, 101, 1, 101.5, 5),
+		})
+		assert.Equal(t, []hedgeRoute{
+			{source: "b", quantity: number(2.5)},
+			{source: "a", quantity: number(0.5)},
+		}, routes)
+	})
+
+	t.Run("small route is merged", func(t *testing.T) {
+		venueA := newTestHedgeVenue("a", 0, 0.5, 100, 1, 102, 5)
+		venueA.market.MinQuantity = number(1)
+
+		routes := splitHedgeQuantity(types.SideTypeBuy, number(3), []hedgeVenue{
+			venueA,
+			newTestHedgeVenue("b", 0, 100, 101, 1, 101.5, 5),
+		})
+		assert.Equal(t, []hedgeRoute{
+			{source: "b", quantity: number(3)},
+		}, routes)
+	})
+
+	t.Run("insufficient depth", func(t *testing.T) {
+		routes := splitHedgeQuantity(types.SideTypeBuy, number(10), []hedgeVenue{
+			newTestHedgeVenue("a", 0, 100, 100, 1),
+			newTestHedgeVenue("b", 0, 100, 101, 1),
+		})
+		assert.Equal(t, []hedgeRoute{
+			{source: "a", quantity: number(9)},
+			{source: "b", quantity: number(1)},
+		}, routes)
+	})
+
+	t.Run("sell takes the highest bid", func(t *testing.T) {
+		routes := splitHedgeQuantity(types.SideTypeSell, number(1), []hedgeVenue{
+			newTestHedgeVenue("a", 0, 100, 100, 1),
+			newTestHedgeVenue("b", 0, 100, 101, 1),
+		})
+		assert.Equal(t, []hedgeRoute{
+			{source: "b", quantity: number(1)},
+		}, routes)
+	})
+
+	t.Run("no capacity", func(t *testing.T) {
+		routes := splitHedgeQuantity(types.SideTypeSell, number(1), []hedgeVenue{
+			newTestHedgeVenue("a", 0, 0, 100, 1),
+		})
+		assert.Empty(t, routes)
+	})
+}
@@ -0,0 +1,65 @@
+package xmaker
+
+import (
+	"context"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/strategy/common"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// TWAPHedge configures the sliced hedging for the large uncovered positions
+type TWAPHedge struct {
+	Enabled bool `json:"enabled"`
+
+	// MinQuantity is the minimal uncovered position quantity that triggers the TWAP hedge,
+	// the uncovered position less than this quantity is still hedged by the market order.
+	MinQuantity fixedpoint.Value `json:"minQuantity"`
+
+	common.TWAPHedgeConfig
+}
+
+// shouldUseTWAPHedge checks if the hedge quantity is large enough for the TWAP hedge
+func (s *Strategy) shouldUseTWAPHedge(quantity fixedpoint.Value) bool {
+	return s.HedgeTWAP != nil && s.HedgeTWAP.Enabled && quantity.Abs().Compare(s.HedgeTWAP.MinQuantity) >= 0
+}
+
+// startTWAPHedge starts a TWAP execution for hedging the given position on the selected source,
+// the hedge quantity is counted into the covered position until the execution is done.
+func (s *Strategy) startTWAPHedge(ctx context.Context, pos fixedpoint.Value) error {
+	side := types.SideTypeBuy
+	if pos.Sign() < 0 {
+		side = types.SideTypeSell
+	}
+
+	sourceExchange, _, _ := s.selectHedgeSource(side, pos.Abs())
+
+	executor := common.NewTWAPHedgeExecutor(s.newHedgeMarket(sourceExchange), s.HedgeTWAP.TWAPHedgeConfig)
+	covered, err := executor.Hedge(ctx, pos)
+	if err != nil {
+		return err
+	}
+
+	s.CoveredPosition = s.CoveredPosition.Add(covered)
+	s.twapHedge = executor
+	s.observeHedgeLatency()
+	return nil
+}
+
+// updateTWAPHedge checks the progress of the running TWAP hedge, the execution will be canceled
+// if the uncovered position is reversed. It returns true if the TWAP hedge is still running.
+func (s *Strategy) updateTWAPHedge(ctx context.Context, uncoverPosition fixedpoint.Value) bool {
+	if s.twapHedge == nil {
+		return false
+	}
+
+	running, uncovered := s.twapHedge.Update(ctx, uncoverPosition)
+	if running {
+		return true
+	}
+
+	// remove the unfilled quantity of the TWAP hedge from the covered position
+	s.CoveredPosition = s.CoveredPosition.Sub(uncovered)
+	s.twapHedge = nil
+	return false
+}
@@ -0,0 +1,121 @@
+package xmaker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/c9s/bbgo/pkg/bbgo"
+	"github.com/c9s/bbgo/pkg/core"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/strategy/common"
+	"github.com/c9s/bbgo/pkg/types"
+	"github.com/c9s/bbgo/pkg/types/mocks"
+)
+
+func newTestTWAPHedgeStrategy(t *testing.T) *Strategy {
+	mockCtrl := gomock.NewController(t)
+
+	market := types.Market{
+		Symbol:        "BTCUSDT",
+		BaseCurrency:  "BTC",
+		QuoteCurrency: "USDT",
+		MinQuantity:   fixedpoint.NewFromFloat(0.001),
+		StepSize:      fixedpoint.NewFromFloat(0.0001),
+		TickSize:      fixedpoint.NewFromFloat(0.01),
+	}
+
+	// the session streams and the market data and user data streams of the twap execution
+	mockEx := mocks.NewMockExchange(mockCtrl)
+	mockEx.EXPECT().NewStream().Return(&types.StandardStream{}).AnyTimes()
+	mockEx.EXPECT().CancelOrders(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
+	session := bbgo.NewExchangeSession("binance", mockEx)
+	session.SetMarkets(types.MarketMap{market.Symbol: market})
+
+	book := types.NewAggregatedStreamOrderBook(market.Symbol)
+	book.AddSource("binance", newTestSourceBook(30000.0, 30001.0))
+
+	s := &Strategy{
+		Symbol:         market.Symbol,
+		SourceExchange: "binance",
+		HedgeTWAP: &TWAPHedge{
+			Enabled:     true,
+			MinQuantity: fixedpoint.One,
+			TWAPHedgeConfig: common.TWAPHedgeConfig{
+				Duration:       types.Duration(time.Hour),
+				NumOfSlices:    4,
+				UpdateInterval: types.Duration(time.Hour),
+			},
+		},
+		book:           book,
+		sourceSession:  session,
+		sourceSessions: map[string]*bbgo.ExchangeSession{"binance": session},
+		sourceMarkets:  map[string]types.Market{"binance": market},
+		sourceHeartBeats: map[string]*sourceHeartBeat{
+			"binance": {bid: types.NewPriceHeartBeat(time.Minute), ask: types.NewPriceHeartBeat(time.Minute)},
+		},
+		orderStore: core.NewOrderStore(market.Symbol),
+	}
+	s.HedgeTWAP.Defaults()
+	return s
+}
+
+func TestStrategy_shouldUseTWAPHedge(t *testing.T) {
+	s := &Strategy{}
+	assert.False(t, s.shouldUseTWAPHedge(fixedpoint.NewFromFloat(10.0)))
+
+	s.HedgeTWAP = &TWAPHedge{Enabled: true, MinQuantity: fixedpoint.One}
+	assert.False(t, s.shouldUseTWAPHedge(fixedpoint.NewFromFloat(0.5)))
+	assert.True(t, s.shouldUseTWAPHedge(fixedpoint.One))
+	assert.True(t, s.shouldUseTWAPHedge(fixedpoint.NewFromFloat(-2.0)))
+
+	s.HedgeTWAP.Enabled = false
+	assert.False(t, s.shouldUseTWAPHedge(fixedpoint.NewFromFloat(-2.0)))
+}
+
+func TestStrategy_updateTWAPHedge_Reversed(t *testing.T) {
+	ctx := context.Background()
+	s := newTestTWAPHedgeStrategy(t)
+
+	// buying 2 BTC to cover the short position, the buy quantity is counted into the covered position
+	assert.NoError(t, s.startTWAPHedge(ctx, fixedpoint.NewFromFloat(2.0)))
+	assert.Equal(t, "-2", s.CoveredPosition.String())
+
+	// the uncovered position is still short, the execution keeps running
+	assert.True(t, s.updateTWAPHedge(ctx, fixedpoint.NewFromFloat(-1.0)))
+	assert.NotNil(t, s.twapHedge)
+
+	// the uncovered position changes its sign, the execution is canceled
+	// and the unfilled quantity is removed from the covered position
+	assert.False(t, s.updateTWAPHedge(ctx, fixedpoint.NewFromFloat(0.5)))
+	assert.Nil(t, s.twapHedge)
+	assert.Equal(t, "0", s.CoveredPosition.String())
+
+	// no running execution
+	assert.False(t, s.updateTWAPHedge(ctx, fixedpoint.NewFromFloat(0.5)))
+}
+
+func TestStrategy_updateTWAPHedge_ContextCanceled(t *testing.T) {
+	s := newTestTWAPHedgeStrategy(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	assert.NoError(t, s.startTWAPHedge(ctx, fixedpoint.NewFromFloat(-2.0)))
+	assert.Equal(t, "2", s.CoveredPosition.String())
+
+	// a new hedge can not be started while the execution is running
+	_, err := s.twapHedge.Hedge(ctx, fixedpoint.NewFromFloat(-1.0))
+	assert.Error(t, err)
+
+	cancel()
+
+	// the execution stops with the context, the unfilled quantity is removed from the covered position
+	assert.Eventually(t, func() bool {
+		return !s.updateTWAPHedge(context.Background(), fixedpoint.Zero)
+	}, time.Second, 10*time.Millisecond)
+	assert.Nil(t, s.twapHedge)
+	assert.Equal(t, "0", s.CoveredPosition.String())
+}
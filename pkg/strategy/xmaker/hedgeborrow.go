@@ -0,0 +1,168 @@
+package xmaker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+	"github.com/c9s/bbgo/pkg/util"
+)
+
+// HedgeMarginBorrow borrows the insufficient asset before hedging on the margin source sessions,
+// and repays the outstanding debt when the position is flattened.
+type HedgeMarginBorrow struct {
+	Enabled bool `json:"enabled"`
+
+	// RepayInterval is the interval of checking the flattened position for repaying the debt, defaults to 5m
+	RepayInterval types.Duration `json:"repayInterval"`
+}
+
+func (b *HedgeMarginBorrow) Defaults() {
+	if b.RepayInterval == 0 {
+		b.RepayInterval = types.Duration(5 * time.Minute)
+	}
+}
+
+func (b *HedgeMarginBorrow) Validate() error {
+	if b.RepayInterval < 0 {
+		return fmt.Errorf("hedgeBorrow.repayInterval should not be negative, got %s", b.RepayInterval.Duration())
+	}
+
+	return nil
+}
+
+// hedgeRequiredAsset returns the asset and the amount spent by the hedge order
+func hedgeRequiredAsset(market types.Market, side types.SideType, quantity, price fixedpoint.Value) (string, fixedpoint.Value) {
+	if side == types.SideTypeBuy {
+		// the market buy order might be executed at a higher price
+		return market.QuoteCurrency, quantity.Mul(price).Mul(lastPriceModifier)
+	}
+
+	return market.BaseCurrency, quantity
+}
+
+// repayAmount returns the amount of the debt that can be repaid by the available balance, and the interest part of it
+func repayAmount(b types.Balance) (amount, interest fixedpoint.Value) {
+	debt := b.Borrowed.Add(b.Interest)
+	if debt.Sign() <= 0 || b.Available.Sign() <= 0 {
+		return fixedpoint.Zero, fixedpoint.Zero
+	}
+
+	amount = fixedpoint.Min(debt, b.Available)
+
+	// the interest is repaid first
+	interest = fixedpoint.Min(b.Interest, amount)
+	return amount, interest
+}
+
+// borrowForHedge borrows the deficit of the asset spent by the hedge order on the margin source session
+func (s *Strategy) borrowForHedge(ctx context.Context, sourceExchange string, side types.SideType, quantity, price fixedpoint.Value) {
+	service, ok := s.hedgeBorrowers[sourceExchange]
+	if !ok {
+		return
+	}
+
+	sourceSession, sourceMarket := s.sourceSessions[sourceExchange], s.sourceMarkets[sourceExchange]
+	asset, required := hedgeRequiredAsset(sourceMarket, side, quantity, price)
+
+	var available fixedpoint.Value
+	if b, ok := sourceSession.GetAccount().Balance(asset); ok {
+		available = b.Available
+	}
+
+	deficit := required.Sub(available)
+	if deficit.Sign() <= 0 {
+		return
+	}
+
+	maxBorrowable, err := service.QueryMarginAssetMaxBorrowable(ctx, asset)
+	if err != nil {
+		s.logger().WithError(err).Errorf("unable to query the max borrowable amount of %s on %s", asset, sourceExchange)
+		return
+	}
+
+	amount := fixedpoint.Min(deficit, maxBorrowable)
+	if amount.Sign() <= 0 {
+		s.logger().Warnf("%s unable to borrow %s on %s for hedging, the max borrowable amount is %v", s.Symbol, asset, sourceExchange, maxBorrowable)
+		return
+	}
+
+	s.logger().Infof("%s hedge %s %v requires %v %s, borrowing %v %s on %s", s.Symbol, side, quantity, required, asset, amount, asset, sourceExchange)
+	if err := service.BorrowMarginAsset(ctx, asset, amount); err != nil {
+		s.logger().WithError(err).Errorf("unable to borrow %v %s on %s", amount, asset, sourceExchange)
+		return
+	}
+
+	labels := s.metricsLabels()
+	labels["venue"] = sourceExchange
+	labels["asset"] = asset
+	hedgeBorrowMetrics.With(labels).Add(amount.Float64())
+
+	if _, err := sourceSession.UpdateAccount(ctx); err != nil {
+		s.logger().WithError(err).Errorf("unable to update the %s account", sourceExchange)
+	}
+}
+
+// repayHedgeDebts repays the outstanding debts of the margin source sessions when the position is flattened,
+// the repaid interest is recorded into the profit stats in the quote currency.
+func (s *Strategy) repayHedgeDebts(ctx context.Context) {
+	if s.Position.GetBase().Abs().Compare(s.sourceMarket.MinQuantity) > 0 {
+		return
+	}
+
+	for sourceExchange, service := range s.hedgeBorrowers {
+		sourceSession, sourceMarket := s.sourceSessions[sourceExchange], s.sourceMarkets[sourceExchange]
+		account := sourceSession.GetAccount()
+
+		repaid := false
+		for _, asset := range []string{sourceMarket.BaseCurrency, sourceMarket.QuoteCurrency} {
+			b, ok := account.Balance(asset)
+			if !ok {
+				continue
+			}
+
+			amount, interest := repayAmount(b)
+			if amount.IsZero() {
+				continue
+			}
+
+			s.logger().Infof("%s position is flattened, repaying %v %s (interest %v) on %s", s.Symbol, amount, asset, interest, sourceExchange)
+			if err := service.RepayMarginAsset(ctx, asset, amount); err != nil {
+				s.logger().WithError(err).Errorf("unable to repay %v %s on %s", amount, asset, sourceExchange)
+				continue
+			}
+
+			repaid = true
+			if interest.Sign() > 0 {
+				if asset == sourceMarket.BaseCurrency {
+					interest = interest.Mul(s.lastPrice)
+				}
+
+				s.ProfitStats.AddMarginInterest(interest)
+			}
+		}
+
+		if repaid {
+			if _, err := sourceSession.UpdateAccount(ctx); err != nil {
+				s.logger().WithError(err).Errorf("unable to update the %s account", sourceExchange)
+			}
+		}
+	}
+}
+
+func (s *Strategy) runHedgeDebtRepayer(ctx context.Context) {
+	ticker := time.NewTicker(util.MillisecondsJitter(s.HedgeBorrow.RepayInterval.Duration(), 1000))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-ticker.C:
+			s.repayHedgeDebts(ctx)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package xmaker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func Test_hedgeRequiredAsset(t *testing.T) {
+	market := types.Market{BaseCurrency: "BTC", QuoteCurrency: "USDT"}
+
+	asset, amount := hedgeRequiredAsset(market, types.SideTypeSell, fixedpoint.NewFromFloat(0.5), fixedpoint.NewFromFloat(20000.0))
+	assert.Equal(t, "BTC", asset)
+	assert.Equal(t, "0.5", amount.String())
+
+	asset, amount = hedgeRequiredAsset(market, types.SideTypeBuy, fixedpoint.NewFromFloat(0.5), fixedpoint.NewFromFloat(20000.0))
+	assert.Equal(t, "USDT", asset)
+	assert.InDelta(t, 10010.0, amount.Float64(), 0.001)
+}
+
+func Test_repayAmount(t *testing.T) {
+	// fully repaid by the available balance
+	amount, interest := repayAmount(types.Balance{
+		Currency:  "BTC",
+		Available: fixedpoint.NewFromFloat(1.0),
+		Borrowed:  fixedpoint.NewFromFloat(0.5),
+		Interest:  fixedpoint.NewFromFloat(0.001),
+	})
+	assert.Equal(t, "0.501", amount.String())
+	assert.Equal(t, "0.001", interest.String())
+
+	// partially repaid
+	amount, interest = repayAmount(types.Balance{
+		Currency:  "BTC",
+		Available: fixedpoint.NewFromFloat(0.2),
+		Borrowed:  fixedpoint.NewFromFloat(0.5),
+		Interest:  fixedpoint.NewFromFloat(0.001),
+	})
+	assert.Equal(t, "0.2", amount.String())
+	assert.Equal(t, "0.001", interest.String())
+
+	// no debt
+	amount, _ = repayAmount(types.Balance{Currency: "BTC", Available: fixedpoint.NewFromFloat(1.0)})
+	assert.True(t, amount.IsZero())
+}
+
+func TestProfitStats_AddMarginInterest(t *testing.T) {
+	stats := &ProfitStats{ProfitStats: types.NewProfitStats(types.Market{Symbol: "BTCUSDT"})}
+	stats.AddMarginInterest(fixedpoint.NewFromFloat(1.5))
+	stats.AddMarginInterest(fixedpoint.NewFromFloat(0.5))
+	assert.Equal(t, "2", stats.AccumulatedMarginInterest.String())
+	assert.Equal(t, "2", stats.TodayMarginInterest.String())
+
+	stats.ResetToday()
+	assert.True(t, stats.TodayMarginInterest.IsZero())
+	assert.Equal(t, "2", stats.AccumulatedMarginInterest.String())
+}
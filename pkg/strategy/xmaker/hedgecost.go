@@ -0,0 +1,75 @@
+package xmaker
+
+import (
+	"strconv"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// averageFillPrice returns the average price of taking the quantity from the price levels,
+// the remaining quantity beyond the book depth is assumed to be filled at the last level price.
+func averageFillPrice(pvs types.PriceVolumeSlice, quantity fixedpoint.Value) fixedpoint.Value {
+	if len(pvs) == 0 || quantity.Sign() <= 0 {
+		return fixedpoint.Zero
+	}
+
+	remaining := quantity
+	totalAmount := fixedpoint.Zero
+	for _, pv := range pvs {
+		q := fixedpoint.Min(pv.Volume, remaining)
+		totalAmount = totalAmount.Add(q.Mul(pv.Price))
+		remaining = remaining.Sub(q)
+		if remaining.Sign() <= 0 {
+			break
+		}
+	}
+
+	if remaining.Sign() > 0 {
+		totalAmount = totalAmount.Add(remaining.Mul(pvs[len(pvs)-1].Price))
+	}
+
+	return totalAmount.Div(quantity)
+}
+
+// estimateHedgeCost estimates the cost ratio of hedging the quantity against the price levels of the source book,
+// which is the taker fee rate plus the expected slippage from the best price.
+func estimateHedgeCost(pvs types.PriceVolumeSlice, quantity, feeRate fixedpoint.Value) fixedpoint.Value {
+	if len(pvs) == 0 || quantity.Sign() <= 0 {
+		return feeRate
+	}
+
+	bestPrice := pvs[0].Price
+	slippage := averageFillPrice(pvs, quantity).Sub(bestPrice).Abs().Div(bestPrice)
+	return feeRate.Add(slippage)
+}
+
+// hedgeTakerFeeRate returns the highest taker fee rate of the source sessions, so that the estimated cost is not underestimated
+func (s *Strategy) hedgeTakerFeeRate(sources []string) (feeRate fixedpoint.Value) {
+	for _, source := range sources {
+		if session, ok := s.sourceSessions[source]; ok {
+			feeRate = fixedpoint.Max(feeRate, session.TakerFeeRate)
+		}
+	}
+
+	return feeRate
+}
+
+// applyHedgeCost moves the layer price away by the estimated hedge cost of the accumulated layer quantity,
+// the maker bid is hedged by selling into the source bids and the maker ask is hedged by buying from the source asks.
+func (s *Strategy) applyHedgeCost(
+	sourceBook types.OrderBook, side types.SideType, layer int, price, quantity, feeRate fixedpoint.Value,
+) fixedpoint.Value {
+	cost := estimateHedgeCost(sourceBook.SideBook(side), quantity, feeRate)
+
+	labels := s.metricsLabels()
+	labels["side"] = side.String()
+	labels["layer"] = strconv.Itoa(layer)
+	hedgeCostMarginMetrics.With(labels).Set(cost.Float64())
+
+	if side == types.SideTypeBuy {
+		return price.Mul(fixedpoint.One.Sub(cost))
+	}
+
+	return price.Mul(fixedpoint.One.Add(cost))
+}
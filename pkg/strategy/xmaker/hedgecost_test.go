@@ -0,0 +1,30 @@
+package xmaker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func Test_estimateHedgeCost(t *testing.T) {
+	bids := types.PriceVolumeSlice{
+		{Price: fixedpoint.NewFromFloat(100.0), Volume: fixedpoint.NewFromFloat(1.0)},
+		{Price: fixedpoint.NewFromFloat(99.0), Volume: fixedpoint.NewFromFloat(1.0)},
+	}
+	feeRate := fixedpoint.NewFromFloat(0.001)
+
+	// filled by the best level, only the fee
+	assert.Equal(t, "0.001", estimateHedgeCost(bids, fixedpoint.NewFromFloat(1.0), feeRate).String())
+
+	// average price 99.5, 0.5% slippage
+	assert.InDelta(t, 0.006, estimateHedgeCost(bids, fixedpoint.NewFromFloat(2.0), feeRate).Float64(), 1e-9)
+
+	// the quantity beyond the depth is filled at the last level price
+	assert.InDelta(t, 99.333333, averageFillPrice(bids, fixedpoint.NewFromFloat(3.0)).Float64(), 1e-6)
+
+	// empty book falls back to the fee
+	assert.Equal(t, "0.001", estimateHedgeCost(nil, fixedpoint.NewFromFloat(1.0), feeRate).String())
+}
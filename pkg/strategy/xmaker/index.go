@@ -0,0 +1,36 @@
+package xmaker
+
+import (
+	"github.com/c9s/bbgo/pkg/bbgo"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// PriceSource defines where the reference price of the maker quotes comes from
+type PriceSource string
+
+const (
+	// PriceSourceBook uses the best bid/ask of the source order book
+	PriceSourceBook PriceSource = "book"
+
+	// PriceSourceIndex uses the composite price implied by the index symbols of the primary source session,
+	// e.g. BTCUSDT implied by BTCUSDC and USDCUSDT, for the symbols with thin direct books.
+	PriceSourceIndex PriceSource = "index"
+)
+
+// subscribeIndexSymbols subscribes the klines of the index symbols for updating the price solver
+func (s *Strategy) subscribeIndexSymbols(sourceSession *bbgo.ExchangeSession) {
+	for _, symbol := range s.IndexSymbols {
+		sourceSession.Subscribe(types.KLineChannel, symbol, types.SubscribeOptions{Interval: types.Interval1m})
+	}
+}
+
+// indexPrice resolves the composite price of the maker market from the price solver
+func (s *Strategy) indexPrice() (fixedpoint.Value, bool) {
+	price, ok := s.priceSolver.ResolvePrice(s.makerMarket.BaseCurrency, s.makerMarket.QuoteCurrency)
+	if ok {
+		indexPriceMetrics.With(s.metricsLabels()).Set(price.Float64())
+	}
+
+	return price, ok
+}
@@ -0,0 +1,35 @@
+package xmaker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/pricesolver"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestStrategy_indexPrice(t *testing.T) {
+	solver := pricesolver.NewSimplePriceResolver(types.MarketMap{
+		"BTCUSDC":  {Symbol: "BTCUSDC", BaseCurrency: "BTC", QuoteCurrency: "USDC"},
+		"USDCUSDT": {Symbol: "USDCUSDT", BaseCurrency: "USDC", QuoteCurrency: "USDT"},
+	})
+
+	s := &Strategy{
+		Symbol:      "BTCUSDT",
+		makerMarket: types.Market{Symbol: "BTCUSDT", BaseCurrency: "BTC", QuoteCurrency: "USDT"},
+		priceSolver: solver,
+	}
+
+	// the index symbols are not updated yet
+	_, ok := s.indexPrice()
+	assert.False(t, ok)
+
+	solver.Update("BTCUSDC", fixedpoint.NewFromFloat(20000.0))
+	solver.Update("USDCUSDT", fixedpoint.NewFromFloat(1.001))
+
+	price, ok := s.indexPrice()
+	assert.True(t, ok)
+	assert.InDelta(t, 20020.0, price.Float64(), 0.001)
+}
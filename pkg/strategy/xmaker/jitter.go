@@ -0,0 +1,34 @@
+package xmaker
+
+import (
+	"math/rand"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// applyQuantityJitter scales the quantity by (1 + jitter * r), where r is a random number in [-1, 1).
+// The jittered quantity is truncated to the step size and never goes below the min quantity or the min notional,
+// quantities that are already below the min quantity are returned as they are.
+func applyQuantityJitter(quantity, price, jitter fixedpoint.Value, r float64, market types.Market) fixedpoint.Value {
+	if jitter.Sign() <= 0 || quantity.Compare(market.MinQuantity) < 0 {
+		return quantity
+	}
+
+	jittered := quantity.Mul(fixedpoint.One.Add(jitter.Mul(fixedpoint.NewFromFloat(r))))
+	if market.StepSize.Sign() > 0 {
+		jittered = market.TruncateQuantity(jittered)
+	}
+
+	jittered = market.AdjustQuantityByMinQuantity(jittered)
+	if price.Sign() > 0 && market.MinNotional.Sign() > 0 && market.StepSize.Sign() > 0 {
+		jittered = market.AdjustQuantityByMinNotional(jittered, price)
+	}
+
+	return jittered
+}
+
+// jitterQuantity randomizes the maker layer quantity by the QuantityJitter ratio
+func (s *Strategy) jitterQuantity(quantity, price fixedpoint.Value) fixedpoint.Value {
+	return applyQuantityJitter(quantity, price, s.QuantityJitter, rand.Float64()*2-1, s.makerMarket)
+}
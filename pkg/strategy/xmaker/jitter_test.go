@@ -0,0 +1,44 @@
+package xmaker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestApplyQuantityJitter(t *testing.T) {
+	number := fixedpoint.MustNewFromString
+	market := types.Market{
+		Symbol:      "BTCUSDT",
+		StepSize:    number("0.0001"),
+		MinQuantity: number("0.001"),
+		MinNotional: number("10"),
+	}
+
+	price := number("20000")
+	jitter := number("0.1")
+
+	assert.Equal(t, "1", applyQuantityJitter(number("1"), price, fixedpoint.Zero, 0.5, market).String())
+	assert.Equal(t, "1.05", applyQuantityJitter(number("1"), price, jitter, 0.5, market).String())
+	assert.Equal(t, "0.9", applyQuantityJitter(number("1"), price, jitter, -1, market).String())
+
+	// the jittered quantity is truncated by the step size
+	assert.Equal(t, "0.1033", applyQuantityJitter(number("0.1"), price, jitter, 0.333, market).String())
+
+	// the quantity below the min quantity is kept as it is
+	assert.Equal(t, "0.0005", applyQuantityJitter(number("0.0005"), price, jitter, -1, market).String())
+
+	// the jittered quantity respects the min quantity and the min notional
+	assert.Equal(t, "0.001", applyQuantityJitter(number("0.001"), number("10000"), jitter, -1, market).String())
+	assert.Equal(t, "0.0012", applyQuantityJitter(number("0.0011"), number("9000"), jitter, -1, market).String())
+
+	// random bounds
+	for i := 0; i < 100; i++ {
+		r := float64(i)/50.0 - 1.0
+		q := applyQuantityJitter(number("1"), price, jitter, r, market)
+		assert.True(t, q.Compare(number("0.9")) >= 0 && q.Compare(number("1.1")) <= 0, "quantity %v out of bounds", q)
+	}
+}
@@ -0,0 +1,113 @@
+package xmaker
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// layerTagPrefix marks the client order id of the maker orders tagged with the layer index,
+// the tag is searched in the client order id since some exchanges prepend their broker prefix to it.
+const layerTagPrefix = "xmL"
+
+// layerTagRandomLength is the length of the random part of the client order id,
+// the tagged id is kept short so that it's not truncated by the exchange prefixes.
+const layerTagRandomLength = 10
+
+// newLayerClientOrderID encodes the layer index (starting from 1) into a new client order id, e.g. xmL02a1b2c3d4e5
+func newLayerClientOrderID(layer int) string {
+	random := strings.ReplaceAll(uuid.NewString(), "-", "")
+	return fmt.Sprintf("%s%02d%s", layerTagPrefix, layer, random[:layerTagRandomLength])
+}
+
+// parseLayerClientOrderID decodes the layer index from the client order id
+func parseLayerClientOrderID(clientOrderID string) (int, bool) {
+	idx := strings.Index(clientOrderID, layerTagPrefix)
+	if idx < 0 {
+		return 0, false
+	}
+
+	s := clientOrderID[idx+len(layerTagPrefix):]
+	if len(s) < 2 {
+		return 0, false
+	}
+
+	layer, err := strconv.Atoi(s[:2])
+	if err != nil || layer <= 0 {
+		return 0, false
+	}
+
+	return layer, true
+}
+
+// layerClientOrderID returns the tagged client order id of the layer when the layer profit stats is enabled
+func (s *Strategy) layerClientOrderID(layer int) string {
+	if !s.LayerProfitStats {
+		return ""
+	}
+
+	return newLayerClientOrderID(layer)
+}
+
+// LayerProfitStats is the breakdown of the maker fills of a layer.
+//
+// The edge is the spread captured by the maker fills against the source mid price at the fill time,
+// net of the maker fee, which is the profit of the layer if the fills are hedged at the mid price.
+type LayerProfitStats struct {
+	NumFills int `json:"numFills"`
+
+	AccumulatedMakerVolume      fixedpoint.Value `json:"accumulatedMakerVolume,omitempty"`
+	AccumulatedMakerBidVolume   fixedpoint.Value `json:"accumulatedMakerBidVolume,omitempty"`
+	AccumulatedMakerAskVolume   fixedpoint.Value `json:"accumulatedMakerAskVolume,omitempty"`
+	AccumulatedMakerQuoteVolume fixedpoint.Value `json:"accumulatedMakerQuoteVolume,omitempty"`
+	AccumulatedEdge             fixedpoint.Value `json:"accumulatedEdge,omitempty"`
+
+	TodayMakerVolume fixedpoint.Value `json:"todayMakerVolume,omitempty"`
+	TodayEdge        fixedpoint.Value `json:"todayEdge,omitempty"`
+}
+
+// tradeEdge returns the captured edge of the maker trade against the reference price in the quote currency
+func tradeEdge(trade types.Trade, referencePrice fixedpoint.Value, market types.Market) fixedpoint.Value {
+	var edge fixedpoint.Value
+	switch trade.Side {
+	case types.SideTypeBuy:
+		edge = referencePrice.Sub(trade.Price).Mul(trade.Quantity)
+	case types.SideTypeSell:
+		edge = trade.Price.Sub(referencePrice).Mul(trade.Quantity)
+	}
+
+	switch trade.FeeCurrency {
+	case market.QuoteCurrency:
+		edge = edge.Sub(trade.Fee)
+	case market.BaseCurrency:
+		edge = edge.Sub(trade.Fee.Mul(trade.Price))
+	}
+
+	return edge
+}
+
+// addLayerTrade attributes the maker trade to the layer of its order
+func (s *Strategy) addLayerTrade(trade types.Trade) {
+	order, ok := s.orderStore.Get(trade.OrderID)
+	if !ok {
+		return
+	}
+
+	layer, ok := parseLayerClientOrderID(order.ClientOrderID)
+	if !ok {
+		return
+	}
+
+	edge := tradeEdge(trade, s.lastPrice, s.makerMarket)
+	s.ProfitStats.AddLayerTrade(layer, trade, edge)
+
+	labels := s.metricsLabels()
+	labels["layer"] = strconv.Itoa(layer)
+	layerEdgeMetrics.With(labels).Add(edge.Float64())
+	layerVolumeMetrics.With(labels).Add(trade.Quantity.Float64())
+}
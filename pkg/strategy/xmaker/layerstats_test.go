@@ -0,0 +1,75 @@
+package xmaker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func Test_layerClientOrderID(t *testing.T) {
+	clientOrderID := newLayerClientOrderID(3)
+	assert.Len(t, clientOrderID, len(layerTagPrefix)+2+layerTagRandomLength)
+	assert.Regexp(t, "^[a-zA-Z0-9]+$", clientOrderID)
+
+	layer, ok := parseLayerClientOrderID(clientOrderID)
+	assert.True(t, ok)
+	assert.Equal(t, 3, layer)
+
+	// the exchange broker prefix is prepended
+	layer, ok = parseLayerClientOrderID("x-bbgo-" + clientOrderID)
+	assert.True(t, ok)
+	assert.Equal(t, 3, layer)
+
+	_, ok = parseLayerClientOrderID("x-bbgo-2f1e3d4c")
+	assert.False(t, ok)
+
+	_, ok = parseLayerClientOrderID("xmLab")
+	assert.False(t, ok)
+}
+
+func Test_tradeEdge(t *testing.T) {
+	market := types.Market{Symbol: "BTCUSDT", BaseCurrency: "BTC", QuoteCurrency: "USDT"}
+	referencePrice := fixedpoint.NewFromFloat(100.0)
+
+	edge := tradeEdge(types.Trade{
+		Side:        types.SideTypeBuy,
+		Price:       fixedpoint.NewFromFloat(99.0),
+		Quantity:    fixedpoint.NewFromFloat(2.0),
+		Fee:         fixedpoint.NewFromFloat(0.1),
+		FeeCurrency: "USDT",
+	}, referencePrice, market)
+	assert.InDelta(t, 1.9, edge.Float64(), 1e-8)
+
+	edge = tradeEdge(types.Trade{
+		Side:        types.SideTypeSell,
+		Price:       fixedpoint.NewFromFloat(100.5),
+		Quantity:    fixedpoint.NewFromFloat(1.0),
+		Fee:         fixedpoint.NewFromFloat(0.001),
+		FeeCurrency: "BTC",
+	}, referencePrice, market)
+	assert.InDelta(t, 0.5-0.1005, edge.Float64(), 1e-8)
+}
+
+func TestProfitStats_AddLayerTrade(t *testing.T) {
+	stats := &ProfitStats{ProfitStats: types.NewProfitStats(types.Market{Symbol: "BTCUSDT"})}
+
+	stats.AddLayerTrade(1, types.Trade{Side: types.SideTypeBuy, Quantity: fixedpoint.NewFromFloat(0.1), QuoteQuantity: fixedpoint.NewFromFloat(10)}, fixedpoint.NewFromFloat(0.05))
+	stats.AddLayerTrade(1, types.Trade{Side: types.SideTypeSell, Quantity: fixedpoint.NewFromFloat(0.2), QuoteQuantity: fixedpoint.NewFromFloat(20)}, fixedpoint.NewFromFloat(-0.01))
+	stats.AddLayerTrade(2, types.Trade{Side: types.SideTypeSell, Quantity: fixedpoint.NewFromFloat(0.3), QuoteQuantity: fixedpoint.NewFromFloat(30)}, fixedpoint.NewFromFloat(0.2))
+
+	if assert.Len(t, stats.Layers, 2) {
+		assert.Equal(t, 2, stats.Layers[1].NumFills)
+		assert.Equal(t, fixedpoint.NewFromFloat(0.3), stats.Layers[1].AccumulatedMakerVolume)
+		assert.Equal(t, fixedpoint.NewFromFloat(0.1), stats.Layers[1].AccumulatedMakerBidVolume)
+		assert.Equal(t, fixedpoint.NewFromFloat(0.2), stats.Layers[1].AccumulatedMakerAskVolume)
+		assert.Equal(t, fixedpoint.NewFromFloat(0.04), stats.Layers[1].AccumulatedEdge)
+		assert.Equal(t, fixedpoint.NewFromFloat(0.2), stats.Layers[2].TodayEdge)
+	}
+
+	stats.ResetToday()
+	assert.Equal(t, fixedpoint.Zero, stats.Layers[2].TodayEdge)
+	assert.Equal(t, fixedpoint.NewFromFloat(0.2), stats.Layers[2].AccumulatedEdge)
+}
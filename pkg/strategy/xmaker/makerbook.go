@@ -0,0 +1,47 @@
+package xmaker
+
+import (
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// checkMakerBookPrice cross-checks the computed maker order price against the maker venue's current book,
+// so that we won't place a bid above the maker best ask (or an ask below the maker best bid) beyond the tolerance,
+// which will be taken immediately when the source venue and the maker venue diverge sharply.
+//
+// It returns the adjusted price and false if the maker book is not available.
+func (s *Strategy) checkMakerBookPrice(side types.SideType, price fixedpoint.Value) (fixedpoint.Value, bool) {
+	if !s.MakerBookCheck {
+		return price, true
+	}
+
+	makerBid, makerAsk, ok := s.makerBook.BestBidAndAsk()
+	if !ok {
+		s.logger().Warnf("%s maker book is empty, skip placing %s order", s.Symbol, side)
+		return price, false
+	}
+
+	switch side {
+
+	case types.SideTypeBuy:
+		limit := makerAsk.Price.Mul(fixedpoint.One.Add(s.MakerBookCheckTolerance))
+		if price.Compare(limit) >= 0 {
+			adjusted := makerAsk.Price.Sub(s.makerMarket.TickSize)
+			s.logger().Warnf("%s bid price %v crosses the maker best ask %v, adjusting to %v",
+				s.Symbol, price, makerAsk.Price, adjusted)
+			return adjusted, true
+		}
+
+	case types.SideTypeSell:
+		limit := makerBid.Price.Mul(fixedpoint.One.Sub(s.MakerBookCheckTolerance))
+		if price.Compare(limit) <= 0 {
+			adjusted := makerBid.Price.Add(s.makerMarket.TickSize)
+			s.logger().Warnf("%s ask price %v crosses the maker best bid %v, adjusting to %v",
+				s.Symbol, price, makerBid.Price, adjusted)
+			return adjusted, true
+		}
+
+	}
+
+	return price, true
+}
@@ -0,0 +1,190 @@
+package xmaker
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+	"github.com/c9s/bbgo/pkg/util"
+)
+
+// MakerBorrowMode defines when the maker session borrows the asset
+type MakerBorrowMode string
+
+const (
+	// MakerBorrowModeAutoBorrow submits the maker orders with the margin buy side effect,
+	// the exchange borrows the insufficient amount when the order is placed.
+	MakerBorrowModeAutoBorrow MakerBorrowMode = "autoBorrow"
+
+	// MakerBorrowModePostFill borrows the spent asset after the maker order is filled,
+	// so that the next quotes still have enough free balance.
+	MakerBorrowModePostFill MakerBorrowMode = "postFill"
+)
+
+// UnmarshalJSON validates the maker borrow mode when the config is loaded
+func (m *MakerBorrowMode) UnmarshalJSON(data []byte) error {
+	mode, err := unmarshalEnum(data, "maker borrow mode", MakerBorrowModeAutoBorrow, MakerBorrowModePostFill)
+	if err != nil {
+		return err
+	}
+
+	*m = mode
+	return nil
+}
+
+// MakerMarginBorrow allows the margin maker session to quote beyond its free balance by borrowing
+type MakerMarginBorrow struct {
+	Enabled bool `json:"enabled"`
+
+	Mode MakerBorrowMode `json:"mode"`
+
+	// MinMarginLevel stops borrowing when the margin level of the maker account is lower than this level
+	MinMarginLevel fixedpoint.Value `json:"minMarginLevel"`
+
+	// MaxBorrowBase and MaxBorrowQuote cap the total borrowed amount of the base and quote asset
+	MaxBorrowBase  fixedpoint.Value `json:"maxBorrowBase"`
+	MaxBorrowQuote fixedpoint.Value `json:"maxBorrowQuote"`
+
+	// UpdateInterval is the interval of querying the max borrowable amount
+	UpdateInterval types.Duration `json:"updateInterval"`
+}
+
+func (b *MakerMarginBorrow) Defaults() {
+	if b.Mode == "" {
+		b.Mode = MakerBorrowModeAutoBorrow
+	}
+
+	if b.UpdateInterval == 0 {
+		b.UpdateInterval = types.Duration(time.Minute)
+	}
+}
+
+func (b *MakerMarginBorrow) Validate() error {
+	switch b.Mode {
+	case "", MakerBorrowModeAutoBorrow, MakerBorrowModePostFill:
+	default:
+		return fmt.Errorf("invalid maker borrow mode %q", b.Mode)
+	}
+
+	if b.MinMarginLevel.Compare(fixedpoint.One) <= 0 {
+		return fmt.Errorf("makerBorrow.minMarginLevel should be greater than 1.0, got %v", b.MinMarginLevel)
+	}
+
+	return nil
+}
+
+// makerBorrower keeps the max borrowable amounts queried from the maker exchange
+type makerBorrower struct {
+	service types.MarginBorrowRepayService
+
+	mu            sync.Mutex
+	maxBorrowable map[string]fixedpoint.Value
+}
+
+func (b *makerBorrower) setMaxBorrowable(asset string, amount fixedpoint.Value) {
+	b.mu.Lock()
+	b.maxBorrowable[asset] = amount
+	b.mu.Unlock()
+}
+
+func (b *makerBorrower) getMaxBorrowable(asset string) fixedpoint.Value {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.maxBorrowable[asset]
+}
+
+// borrowCap returns the borrow cap of the asset on the maker session
+func (s *Strategy) borrowCap(asset string) fixedpoint.Value {
+	if asset == s.makerMarket.BaseCurrency {
+		return s.MakerBorrow.MaxBorrowBase
+	}
+
+	return s.MakerBorrow.MaxBorrowQuote
+}
+
+// makerBorrowable returns the amount that the maker session can still borrow for quoting,
+// it's zero when the margin level of the maker account is lower than the MinMarginLevel.
+func (s *Strategy) makerBorrowable(asset string) fixedpoint.Value {
+	if s.makerBorrower == nil {
+		return fixedpoint.Zero
+	}
+
+	account := s.makerSession.GetAccount()
+
+	// zero margin level means the margin level is not reported, usually because there is no debt
+	if account.MarginLevel.Sign() > 0 && account.MarginLevel.Compare(s.MakerBorrow.MinMarginLevel) < 0 {
+		return fixedpoint.Zero
+	}
+
+	amount := s.makerBorrower.getMaxBorrowable(asset)
+	if borrowCap := s.borrowCap(asset); borrowCap.Sign() > 0 {
+		var borrowed fixedpoint.Value
+		if b, ok := account.Balance(asset); ok {
+			borrowed = b.Borrowed
+		}
+
+		amount = fixedpoint.Min(amount, borrowCap.Sub(borrowed))
+	}
+
+	return fixedpoint.Max(amount, fixedpoint.Zero)
+}
+
+func (s *Strategy) updateMaxBorrowable(ctx context.Context) {
+	for _, asset := range []string{s.makerMarket.BaseCurrency, s.makerMarket.QuoteCurrency} {
+		amount, err := s.makerBorrower.service.QueryMarginAssetMaxBorrowable(ctx, asset)
+		if err != nil {
+			s.logger().WithError(err).Errorf("unable to query the max borrowable amount of %s", asset)
+			continue
+		}
+
+		s.makerBorrower.setMaxBorrowable(asset, amount)
+	}
+}
+
+func (s *Strategy) runMaxBorrowableUpdater(ctx context.Context) {
+	s.updateMaxBorrowable(ctx)
+
+	ticker := time.NewTicker(util.MillisecondsJitter(s.MakerBorrow.UpdateInterval.Duration(), 1000))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-ticker.C:
+			s.updateMaxBorrowable(ctx)
+		}
+	}
+}
+
+// borrowAfterMakerFill borrows the deficit of the asset spent by the maker trade in the post-fill borrow mode
+func (s *Strategy) borrowAfterMakerFill(ctx context.Context, trade types.Trade) {
+	asset, spent := s.makerMarket.QuoteCurrency, trade.QuoteQuantity
+	if trade.Side == types.SideTypeSell {
+		asset, spent = s.makerMarket.BaseCurrency, trade.Quantity
+	}
+
+	var available fixedpoint.Value
+	if b, ok := s.makerSession.GetAccount().Balance(asset); ok {
+		available = b.Available
+	}
+
+	amount := fixedpoint.Min(spent.Sub(available), s.makerBorrowable(asset))
+	if amount.Sign() <= 0 {
+		return
+	}
+
+	s.logger().Infof("%s maker trade spent %v %s, borrowing %v %s", s.Symbol, spent, asset, amount, asset)
+	if err := s.makerBorrower.service.BorrowMarginAsset(ctx, asset, amount); err != nil {
+		s.logger().WithError(err).Errorf("unable to borrow %v %s", amount, asset)
+		return
+	}
+
+	if _, err := s.makerSession.UpdateAccount(ctx); err != nil {
+		s.logger().WithError(err).Errorf("unable to update the maker account")
+	}
+}
@@ -0,0 +1,49 @@
+package xmaker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/bbgo"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestStrategy_makerBorrowable(t *testing.T) {
+	account := types.NewAccount()
+	account.UpdateBalances(types.BalanceMap{
+		"BTC":  {Currency: "BTC", Available: fixedpoint.NewFromFloat(1.0)},
+		"USDT": {Currency: "USDT", Available: fixedpoint.NewFromFloat(1000.0), Borrowed: fixedpoint.NewFromFloat(4000.0)},
+	})
+
+	s := &Strategy{
+		MakerBorrow: &MakerMarginBorrow{
+			Enabled:        true,
+			MinMarginLevel: fixedpoint.NewFromFloat(3.0),
+			MaxBorrowQuote: fixedpoint.NewFromFloat(5000.0),
+		},
+		makerSession: &bbgo.ExchangeSession{Account: account},
+		makerMarket: types.Market{
+			BaseCurrency:  "BTC",
+			QuoteCurrency: "USDT",
+		},
+		makerBorrower: &makerBorrower{maxBorrowable: map[string]fixedpoint.Value{
+			"BTC":  fixedpoint.NewFromFloat(0.5),
+			"USDT": fixedpoint.NewFromFloat(10000.0),
+		}},
+	}
+
+	// no base cap, limited by the max borrowable
+	assert.Equal(t, "0.5", s.makerBorrowable("BTC").String())
+
+	// limited by the quote cap minus the borrowed amount
+	assert.Equal(t, "1000", s.makerBorrowable("USDT").String())
+
+	// margin level is lower than the min margin level
+	account.MarginLevel = fixedpoint.NewFromFloat(2.0)
+	assert.Equal(t, "0", s.makerBorrowable("BTC").String())
+
+	account.MarginLevel = fixedpoint.NewFromFloat(5.0)
+	assert.Equal(t, "0.5", s.makerBorrowable("BTC").String())
+}
@@ -0,0 +1,300 @@
+package xmaker
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var (
+	inventorySkewMetrics = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "xmaker_inventory_skew",
+			Help: "the inventory skew ratio applied to the bid/ask margins",
+		},
+		[]string{"strategy_type", "strategy_id", "exchange", "symbol"},
+	)
+
+	spreadModelMarginMetrics = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "xmaker_spread_model_margin",
+			Help: "the bid/ask margin applied by the spread model",
+		},
+		[]string{"strategy_type", "strategy_id", "exchange", "symbol", "model", "side"},
+	)
+
+	fundingRateMarginMetrics = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "xmaker_funding_rate_margin_adjustment",
+			Help: "the bid/ask margin adjustment derived from the predicted funding rate",
+		},
+		[]string{"strategy_type", "strategy_id", "exchange", "symbol"},
+	)
+
+	drawdownMetrics = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "xmaker_drawdown",
+			Help: "the intraday drawdown ratio of the equity",
+		},
+		[]string{"strategy_type", "strategy_id", "exchange", "symbol"},
+	)
+
+	hedgeVenueCoveredPositionMetrics = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "xmaker_hedge_venue_covered_position",
+			Help: "the net position of the hedge trades on each source session",
+		},
+		[]string{"strategy_type", "strategy_id", "exchange", "symbol", "venue"},
+	)
+
+	hedgeVenueQuantityMetrics = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "xmaker_hedge_venue_quantity_total",
+			Help: "the submitted hedge quantity on each source session",
+		},
+		[]string{"strategy_type", "strategy_id", "exchange", "symbol", "venue", "side"},
+	)
+
+	shadowPositionMetrics = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "xmaker_shadow_position",
+			Help: "the shadow base position of the dry-run mode",
+		},
+		[]string{"strategy_type", "strategy_id", "exchange", "symbol"},
+	)
+
+	shadowProfitMetrics = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "xmaker_shadow_net_profit",
+			Help: "the shadow net profit of the dry-run mode",
+		},
+		[]string{"strategy_type", "strategy_id", "exchange", "symbol"},
+	)
+
+	shadowFilledQuantityMetrics = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "xmaker_shadow_filled_quantity_total",
+			Help: "the filled quantity of the shadow maker orders in the dry-run mode",
+		},
+		[]string{"strategy_type", "strategy_id", "exchange", "symbol", "side"},
+	)
+
+	partialFillTopUpMetrics = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "xmaker_partial_fill_top_up_total",
+			Help: "the number of the partially filled maker layers that are topped up",
+		},
+		[]string{"strategy_type", "strategy_id", "exchange", "symbol", "side"},
+	)
+
+	quotedSpreadMetrics = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "xmaker_quoted_spread_bps",
+			Help:    "the spread between the best bid and the best ask of the maker quotes in bps",
+			Buckets: prometheus.ExponentialBuckets(1, 2, 12),
+		},
+		[]string{"strategy_type", "strategy_id", "exchange", "symbol"},
+	)
+
+	quoteUptimeMetrics = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "xmaker_two_sided_quote_uptime_ratio",
+			Help: "the time-weighted ratio of the time that the maker quotes are two-sided",
+		},
+		[]string{"strategy_type", "strategy_id", "exchange", "symbol"},
+	)
+
+	makerOrdersMetrics = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "xmaker_maker_orders_total",
+			Help: "the number of the submitted maker orders",
+		},
+		[]string{"strategy_type", "strategy_id", "exchange", "symbol"},
+	)
+
+	makerTradesMetrics = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "xmaker_maker_trades_total",
+			Help: "the number of the maker trades",
+		},
+		[]string{"strategy_type", "strategy_id", "exchange", "symbol"},
+	)
+
+	orderToTradeRatioMetrics = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "xmaker_order_to_trade_ratio",
+			Help: "the ratio of the submitted maker orders to the maker trades",
+		},
+		[]string{"strategy_type", "strategy_id", "exchange", "symbol"},
+	)
+
+	hedgeLatencyMetrics = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "xmaker_hedge_latency_seconds",
+			Help:    "the latency from the maker trade time to the hedge submit time",
+			Buckets: prometheus.ExponentialBuckets(0.05, 2, 12),
+		},
+		[]string{"strategy_type", "strategy_id", "exchange", "symbol"},
+	)
+
+	priceBandDeviationMetrics = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "xmaker_price_band_deviation",
+			Help: "the deviation ratio of the source mid-price from the price band reference price",
+		},
+		[]string{"strategy_type", "strategy_id", "exchange", "symbol"},
+	)
+
+	priceBandSuppressedMetrics = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "xmaker_price_band_suppressed",
+			Help: "1 if quoting is suppressed by the price band, otherwise 0",
+		},
+		[]string{"strategy_type", "strategy_id", "exchange", "symbol"},
+	)
+
+	calendarPausedMetrics = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "xmaker_calendar_paused",
+			Help: "1 if quoting is paused by the trading calendar of the maker or the hedge venues, otherwise 0",
+		},
+		[]string{"strategy_type", "strategy_id", "exchange", "symbol"},
+	)
+
+	indexPriceMetrics = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "xmaker_index_price",
+			Help: "the composite price implied by the index symbols, used as the reference price of the quotes",
+		},
+		[]string{"strategy_type", "strategy_id", "exchange", "symbol"},
+	)
+
+	hedgeCostMarginMetrics = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "xmaker_hedge_cost_margin",
+			Help: "the estimated hedge cost ratio added to the layer margin, the taker fee plus the expected slippage",
+		},
+		[]string{"strategy_type", "strategy_id", "exchange", "symbol", "side", "layer"},
+	)
+
+	hedgeBorrowMetrics = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "xmaker_hedge_borrow_amount_total",
+			Help: "the amount borrowed on the margin source sessions for hedging",
+		},
+		[]string{"strategy_type", "strategy_id", "exchange", "symbol", "venue", "asset"},
+	)
+
+	accountEquityMetrics = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "xmaker_account_equity",
+			Help: "the total account value of the maker session in the quote currency, used for sizing the quantity by the equity ratio",
+		},
+		[]string{"strategy_type", "strategy_id", "exchange", "symbol"},
+	)
+
+	degradedModeMetrics = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "xmaker_degraded_mode",
+			Help: "1 if quoting is paused by the degraded mode of the maker or the hedge venues, otherwise 0",
+		},
+		[]string{"strategy_type", "strategy_id", "exchange", "symbol"},
+	)
+
+	markoutMetrics = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "xmaker_maker_fill_markout",
+			Help: "the average markout ratio of the recent maker fills, negative when the fills are adversely selected",
+		},
+		[]string{"strategy_type", "strategy_id", "exchange", "symbol", "side"},
+	)
+
+	toxicityPausedMetrics = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "xmaker_toxicity_paused",
+			Help: "1 if the side is paused by the toxicity filter, otherwise 0",
+		},
+		[]string{"strategy_type", "strategy_id", "exchange", "symbol", "side"},
+	)
+
+	fxPositionMetrics = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "xmaker_fx_position",
+			Help: "the net position of the source quote currency from the hedge trades, valued in the maker quote currency",
+		},
+		[]string{"strategy_type", "strategy_id", "exchange", "symbol", "currency"},
+	)
+
+	layerEdgeMetrics = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "xmaker_layer_edge_total",
+			Help: "the captured edge of the maker fills by the layer against the source mid price, net of the maker fee",
+		},
+		[]string{"strategy_type", "strategy_id", "exchange", "symbol", "layer"},
+	)
+
+	layerVolumeMetrics = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "xmaker_layer_volume_total",
+			Help: "the base volume of the maker fills by the layer",
+		},
+		[]string{"strategy_type", "strategy_id", "exchange", "symbol", "layer"},
+	)
+
+	drawdownHaltedMetrics = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "xmaker_drawdown_halted",
+			Help: "1 if quoting is halted by the drawdown circuit breaker, otherwise 0",
+		},
+		[]string{"strategy_type", "strategy_id", "exchange", "symbol"},
+	)
+
+	quoteSkippedMetrics = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "xmaker_quote_skipped_total",
+			Help: "the number of the quote update cycles that didn't place any maker order, by the skip reason",
+		},
+		[]string{"strategy_type", "strategy_id", "exchange", "symbol", "reason"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(
+		inventorySkewMetrics,
+		spreadModelMarginMetrics,
+		fundingRateMarginMetrics,
+		drawdownMetrics,
+		drawdownHaltedMetrics,
+		hedgeVenueCoveredPositionMetrics,
+		hedgeVenueQuantityMetrics,
+		shadowPositionMetrics,
+		shadowProfitMetrics,
+		shadowFilledQuantityMetrics,
+		partialFillTopUpMetrics,
+		quotedSpreadMetrics,
+		quoteUptimeMetrics,
+		makerOrdersMetrics,
+		makerTradesMetrics,
+		orderToTradeRatioMetrics,
+		hedgeLatencyMetrics,
+		priceBandDeviationMetrics,
+		priceBandSuppressedMetrics,
+		calendarPausedMetrics,
+		degradedModeMetrics,
+		accountEquityMetrics,
+		hedgeBorrowMetrics,
+		hedgeCostMarginMetrics,
+		indexPriceMetrics,
+		markoutMetrics,
+		toxicityPausedMetrics,
+		fxPositionMetrics,
+		layerEdgeMetrics,
+		layerVolumeMetrics,
+		quoteSkippedMetrics,
+	)
+}
+
+func (s *Strategy) metricsLabels() prometheus.Labels {
+	return prometheus.Labels{
+		"strategy_type": ID,
+		"strategy_id":   s.InstanceID(),
+		"exchange":      s.MakerExchange,
+		"symbol":        s.Symbol,
+	}
+}
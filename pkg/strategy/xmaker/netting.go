@@ -0,0 +1,56 @@
+package xmaker
+
+import (
+	"sync"
+	"time"
+)
+
+// hedgeNetting tracks the maker fills that are not hedged yet,
+// so that the fills within the netting window are netted before hedging.
+type hedgeNetting struct {
+	mu sync.Mutex
+
+	// firstFillTime is the time of the first fill that is not hedged yet
+	firstFillTime time.Time
+
+	// lastFillTime is the time of the last fill
+	lastFillTime time.Time
+}
+
+// AddFill records the maker fill time
+func (n *hedgeNetting) AddFill(t time.Time) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.firstFillTime.IsZero() {
+		n.firstFillTime = t
+	}
+
+	n.lastFillTime = t
+}
+
+// Ready checks if the pending fills should be hedged now,
+// the fills are hedged when no new fill comes in the netting window,
+// or when the first pending fill has been uncovered for longer than maxUncoveredDuration.
+func (n *hedgeNetting) Ready(now time.Time, window, maxUncoveredDuration time.Duration) bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.firstFillTime.IsZero() {
+		return true
+	}
+
+	if maxUncoveredDuration > 0 && now.Sub(n.firstFillTime) >= maxUncoveredDuration {
+		return true
+	}
+
+	return now.Sub(n.lastFillTime) >= window
+}
+
+// Reset clears the pending fills after they are hedged or netted out
+func (n *hedgeNetting) Reset() {
+	n.mu.Lock()
+	n.firstFillTime = time.Time{}
+	n.lastFillTime = time.Time{}
+	n.mu.Unlock()
+}
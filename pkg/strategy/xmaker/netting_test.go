@@ -0,0 +1,33 @@
+package xmaker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHedgeNetting(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	window := 3 * time.Second
+	maxUncovered := 10 * time.Second
+
+	var netting hedgeNetting
+	assert.True(t, netting.Ready(now, window, maxUncovered), "no pending fill")
+
+	netting.AddFill(now)
+	assert.False(t, netting.Ready(now.Add(time.Second), window, maxUncovered))
+	assert.True(t, netting.Ready(now.Add(3*time.Second), window, maxUncovered))
+
+	// the fills keep coming in the netting window
+	for i := 2; i <= 10; i += 2 {
+		netting.AddFill(now.Add(time.Duration(i) * time.Second))
+	}
+
+	assert.False(t, netting.Ready(now.Add(9*time.Second), window, maxUncovered))
+	assert.True(t, netting.Ready(now.Add(10*time.Second), window, maxUncovered), "max uncovered duration exceeded")
+	assert.False(t, netting.Ready(now.Add(10*time.Second), window, 0), "no max uncovered duration")
+
+	netting.Reset()
+	assert.True(t, netting.Ready(now.Add(10*time.Second), window, maxUncovered))
+}
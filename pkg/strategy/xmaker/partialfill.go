@@ -0,0 +1,252 @@
+package xmaker
+
+import (
+	"context"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/c9s/bbgo/pkg/bbgo"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// makerLayer is the desired quantity of a maker order layer
+type makerLayer struct {
+	side  types.SideType
+	price fixedpoint.Value
+
+	// index is the layer index decoded from the client order id, 0 if the order is not tagged
+	index int
+
+	// quantity is the desired open quantity of the layer
+	quantity fixedpoint.Value
+
+	// executed is the executed quantity of the order that has been handled
+	executed fixedpoint.Value
+}
+
+// partialFill is the consumed quantity of a partially filled maker order
+type partialFill struct {
+	order    types.Order
+	consumed fixedpoint.Value
+}
+
+// layerTracker tracks the remaining quantity of the maker order layers from the order updates
+type layerTracker struct {
+	mu     sync.Mutex
+	layers map[uint64]*makerLayer
+
+	// C receives the partially filled orders
+	C chan partialFill
+
+	logger logrus.FieldLogger
+}
+
+func newLayerTracker(logger logrus.FieldLogger) *layerTracker {
+	return &layerTracker{
+		layers: make(map[uint64]*makerLayer),
+		C:      make(chan partialFill, 32),
+		logger: logger,
+	}
+}
+
+// Add tracks the orders as the layers with the order quantity as the desired quantity
+func (t *layerTracker) Add(orders ...types.Order) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, o := range orders {
+		index, _ := parseLayerClientOrderID(o.ClientOrderID)
+		t.layers[o.OrderID] = &makerLayer{
+			index:    index,
+			side:     o.Side,
+			price:    o.Price,
+			quantity: o.Quantity.Sub(o.ExecutedQuantity),
+			executed: o.ExecutedQuantity,
+		}
+	}
+}
+
+// Get returns the layer of the order
+func (t *layerTracker) Get(orderID uint64) (makerLayer, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	layer, ok := t.layers[orderID]
+	if !ok {
+		return makerLayer{}, false
+	}
+
+	return *layer, true
+}
+
+// Remove stops tracking the layer of the order
+func (t *layerTracker) Remove(orderID uint64) {
+	t.mu.Lock()
+	delete(t.layers, orderID)
+	t.mu.Unlock()
+}
+
+// Update updates the layer by the order update and returns the newly consumed quantity of the layer,
+// the closed orders are removed from the tracker.
+func (t *layerTracker) Update(order types.Order) fixedpoint.Value {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	layer, ok := t.layers[order.OrderID]
+	if !ok {
+		return fixedpoint.Zero
+	}
+
+	switch order.Status {
+	case types.OrderStatusPartiallyFilled:
+		consumed := order.ExecutedQuantity.Sub(layer.executed)
+		if consumed.Sign() <= 0 {
+			return fixedpoint.Zero
+		}
+
+		layer.executed = order.ExecutedQuantity
+		return consumed
+
+	case types.OrderStatusFilled, types.OrderStatusCanceled, types.OrderStatusRejected:
+		delete(t.layers, order.OrderID)
+	}
+
+	return fixedpoint.Zero
+}
+
+// BindStream emits the partially filled orders of the stream to the channel
+func (t *layerTracker) BindStream(stream types.Stream) {
+	stream.OnOrderUpdate(func(order types.Order) {
+		consumed := t.Update(order)
+		if consumed.IsZero() {
+			return
+		}
+
+		select {
+		case t.C <- partialFill{order: order, consumed: consumed}:
+		default:
+			t.logger.Warnf("partial fill channel is full, dropping the partial fill of order #%d", order.OrderID)
+		}
+	})
+}
+
+// trackMakerOrders adds the created maker orders to the active order book, the order store and the layer tracker
+func (s *Strategy) trackMakerOrders(orders ...types.Order) {
+	s.activeMakerOrders.Add(orders...)
+	s.orderStore.Add(orders...)
+	s.addMakerOrderMetrics(len(orders))
+
+	if s.layerTracker != nil {
+		s.layerTracker.Add(orders...)
+	}
+
+	if s.regimeAttributor != nil {
+		s.regimeAttributor.AddOrders(orders...)
+	}
+}
+
+// handlePartialFill hedges the filled portion of the partially filled maker order immediately,
+// and tops up the consumed layer without touching the other layers.
+func (s *Strategy) handlePartialFill(ctx context.Context, orderExecutionRouter bbgo.OrderExecutionRouter, fill partialFill) {
+	s.logger().Infof("%s maker order #%d partially filled, consumed %v: %s", s.Symbol, fill.order.OrderID, fill.consumed, fill.order.String())
+
+	if !s.DisableHedge {
+		s.tradeCollector.Process()
+		s.hedgeUncoveredPosition(ctx)
+	}
+
+	s.quoteScheduler.Submit(s.MakerExchange, func() {
+		s.topUpLayer(ctx, orderExecutionRouter, fill)
+	})
+}
+
+// topUpLayer restores the open quantity of the partially filled layer to the desired quantity,
+// the order is amended in place when the maker exchange supports it, otherwise the order is canceled and replaced.
+func (s *Strategy) topUpLayer(ctx context.Context, orderExecutionRouter bbgo.OrderExecutionRouter, fill partialFill) {
+	layer, ok := s.layerTracker.Get(fill.order.OrderID)
+	if !ok {
+		return
+	}
+
+	order, ok := s.activeMakerOrders.Get(fill.order.OrderID)
+	if !ok {
+		return
+	}
+
+	if !s.hasTopUpBalance(layer.side, layer.price, fill.consumed) {
+		s.logger().Warnf("%s insufficient balance for topping up the %s layer at %v", s.Symbol, layer.side, layer.price)
+		return
+	}
+
+	labels := s.metricsLabels()
+	labels["side"] = layer.side.String()
+
+	if _, hasAmend := s.makerSession.Exchange.(types.ExchangeOrderAmendService); hasAmend {
+		amendedOrder, err := s.makerSession.AmendOrder(bbgo.WithOrderPriority(ctx, bbgo.OrderPriorityLow), order, layer.price, layer.quantity)
+		if err == nil {
+			if amendedOrder != nil {
+				s.activeMakerOrders.Update(*amendedOrder)
+				s.orderStore.Add(*amendedOrder)
+			}
+
+			partialFillTopUpMetrics.With(labels).Inc()
+			return
+		}
+
+		s.logger().WithError(err).Errorf("%s order amend error, falling back to cancel/replace: %s", s.Symbol, order.String())
+	}
+
+	if err := s.activeMakerOrders.GracefulCancel(ctx, s.makerSession.Exchange, order); err != nil {
+		s.logger().WithError(err).Warnf("unable to cancel the partially filled %s order #%d", s.Symbol, order.OrderID)
+		return
+	}
+
+	s.layerTracker.Remove(order.OrderID)
+
+	topUpOrder := types.SubmitOrder{
+		Symbol:      s.Symbol,
+		Market:      s.makerMarket,
+		Type:        types.OrderTypeLimit,
+		Side:        layer.side,
+		Price:       layer.price,
+		Quantity:    layer.quantity,
+		TimeInForce: types.TimeInForceGTC,
+		GroupID:     s.groupID,
+	}
+	if layer.index > 0 {
+		topUpOrder.ClientOrderID = s.layerClientOrderID(layer.index)
+	}
+
+	makerOrders, err := s.submitMakerOrders(ctx, orderExecutionRouter, []types.SubmitOrder{topUpOrder})
+	if err != nil {
+		s.logger().WithError(err).Errorf("%s top-up order error", s.Symbol)
+	}
+
+	// the orders created before the error still need to be tracked
+	s.trackMakerOrders(makerOrders...)
+
+	if len(makerOrders) > 0 {
+		partialFillTopUpMetrics.With(labels).Inc()
+	}
+}
+
+// hasTopUpBalance checks if the maker account has enough balance for topping up the consumed quantity
+func (s *Strategy) hasTopUpBalance(side types.SideType, price, quantity fixedpoint.Value) bool {
+	account := s.makerSession.GetAccount()
+
+	switch side {
+	case types.SideTypeBuy:
+		if b, ok := account.Balance(s.makerMarket.QuoteCurrency); ok {
+			return b.Available.Add(s.makerBorrowable(s.makerMarket.QuoteCurrency)).Compare(quantity.Mul(price)) >= 0
+		}
+
+	case types.SideTypeSell:
+		if b, ok := account.Balance(s.makerMarket.BaseCurrency); ok {
+			return b.Available.Add(s.makerBorrowable(s.makerMarket.BaseCurrency)).Compare(quantity) >= 0
+		}
+	}
+
+	return false
+}
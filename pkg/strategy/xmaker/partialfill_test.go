@@ -0,0 +1,82 @@
+package xmaker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestLayerTracker_Update(t *testing.T) {
+	number := fixedpoint.MustNewFromString
+
+	order := types.Order{
+		SubmitOrder: types.SubmitOrder{
+			Symbol:   "BTCUSDT",
+			Side:     types.SideTypeBuy,
+			Price:    number("100"),
+			Quantity: number("1"),
+		},
+		OrderID: 1,
+		Status:  types.OrderStatusNew,
+	}
+
+	tracker := newLayerTracker(log)
+	tracker.Add(order)
+
+	// the untracked orders are ignored
+	assert.True(t, tracker.Update(types.Order{OrderID: 2, Status: types.OrderStatusPartiallyFilled, ExecutedQuantity: number("0.1")}).IsZero())
+
+	order.Status = types.OrderStatusPartiallyFilled
+	order.ExecutedQuantity = number("0.3")
+	assert.Equal(t, "0.3", tracker.Update(order).String())
+
+	// the same update is only consumed once
+	assert.True(t, tracker.Update(order).IsZero())
+
+	order.ExecutedQuantity = number("0.5")
+	assert.Equal(t, "0.2", tracker.Update(order).String())
+
+	layer, ok := tracker.Get(order.OrderID)
+	if assert.True(t, ok) {
+		assert.Equal(t, "1", layer.quantity.String())
+		assert.Equal(t, "0.5", layer.executed.String())
+		assert.Equal(t, types.SideTypeBuy, layer.side)
+	}
+
+	order.Status = types.OrderStatusFilled
+	order.ExecutedQuantity = number("1")
+	assert.True(t, tracker.Update(order).IsZero())
+
+	_, ok = tracker.Get(order.OrderID)
+	assert.False(t, ok)
+}
+
+func TestLayerTracker_BindStream(t *testing.T) {
+	number := fixedpoint.MustNewFromString
+
+	stream := types.NewStandardStream()
+	tracker := newLayerTracker(log)
+	tracker.BindStream(&stream)
+
+	order := types.Order{
+		SubmitOrder: types.SubmitOrder{Symbol: "BTCUSDT", Side: types.SideTypeSell, Price: number("101"), Quantity: number("2")},
+		OrderID:     1,
+		Status:      types.OrderStatusNew,
+	}
+	tracker.Add(order)
+
+	order.Status = types.OrderStatusPartiallyFilled
+	order.ExecutedQuantity = number("0.5")
+	stream.EmitOrderUpdate(order)
+
+	select {
+	case fill := <-tracker.C:
+		assert.Equal(t, uint64(1), fill.order.OrderID)
+		assert.Equal(t, "0.5", fill.consumed.String())
+	default:
+		t.Fatal("expected the partial fill")
+	}
+}
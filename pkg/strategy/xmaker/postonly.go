@@ -0,0 +1,125 @@
+package xmaker
+
+import (
+	"context"
+	"strings"
+
+	"go.uber.org/multierr"
+
+	"github.com/c9s/bbgo/pkg/bbgo"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+const defaultPostOnlyMaxReprices = 1
+
+// postOnlyRejectionKeywords are the error message fragments that the exchanges return
+// when a post-only order is rejected for crossing the book
+var postOnlyRejectionKeywords = []string{
+	"post only",
+	"post-only",
+	"post_only",
+	"postonly",
+	"immediately match",
+	"would immediately",
+	"would take",
+	"limit_maker",
+	"limit maker",
+}
+
+// isPostOnlyRejection checks whether the submit error is caused by a post-only order crossing the book
+func isPostOnlyRejection(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	msg := strings.ToLower(err.Error())
+	for _, keyword := range postOnlyRejectionKeywords {
+		if strings.Contains(msg, keyword) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// repricePostOnlyOrder moves the order price one tick away from the spread
+func repricePostOnlyOrder(order types.SubmitOrder, market types.Market) types.SubmitOrder {
+	switch order.Side {
+	case types.SideTypeBuy:
+		order.Price = order.Price.Sub(market.TickSize)
+	case types.SideTypeSell:
+		order.Price = order.Price.Add(market.TickSize)
+	}
+
+	return order
+}
+
+// submitMakerOrders submits the maker orders to the maker session.
+//
+// In the post-only mode, the orders are submitted as limit maker orders one by one,
+// and the orders rejected for crossing the book are re-priced one tick away and submitted again.
+func (s *Strategy) submitMakerOrders(
+	ctx context.Context, orderExecutionRouter bbgo.OrderExecutionRouter, submitOrders []types.SubmitOrder,
+) (types.OrderSlice, error) {
+	// the quote refreshes yield to the hedge orders in the order rate budget of the session
+	quoteCtx := bbgo.WithOrderPriority(ctx, bbgo.OrderPriorityLow)
+	if len(s.SelfTradePreventionMode) > 0 {
+		for i := range submitOrders {
+			submitOrders[i].SelfTradePreventionMode = s.SelfTradePreventionMode
+		}
+	}
+
+	if !s.PostOnly {
+		return orderExecutionRouter.SubmitOrdersTo(quoteCtx, s.MakerExchange, submitOrders...)
+	}
+
+	for i := range submitOrders {
+		submitOrders[i].Type = types.OrderTypeLimitMaker
+	}
+
+	formattedOrders, err := s.makerSession.FormatOrders(submitOrders)
+	if err != nil {
+		return nil, err
+	}
+
+	// the orders are submitted to the exchange directly, so the order middlewares are applied here,
+	// and the order rate budget is waited for each attempt
+	if err := s.makerSession.ApplyOrderMiddlewares(quoteCtx, formattedOrders); err != nil {
+		return nil, err
+	}
+
+	var createdOrders types.OrderSlice
+	var errs error
+	for _, submitOrder := range formattedOrders {
+		for attempt := 0; ; attempt++ {
+			if err := s.makerSession.WaitOrderRateBudget(quoteCtx, 1); err != nil {
+				return createdOrders, err
+			}
+
+			createdOrder, err := s.makerSession.Exchange.SubmitOrder(ctx, submitOrder)
+			if err == nil {
+				if createdOrder != nil {
+					createdOrders = append(createdOrders, *createdOrder)
+				}
+				break
+			}
+
+			if attempt >= s.PostOnlyMaxReprices || !isPostOnlyRejection(err) {
+				errs = multierr.Append(errs, err)
+				break
+			}
+
+			repricedOrder := repricePostOnlyOrder(submitOrder, s.makerMarket)
+			if layer, ok := parseLayerClientOrderID(submitOrder.ClientOrderID); ok {
+				// the client order id of the rejected order might not be reusable
+				repricedOrder.ClientOrderID = newLayerClientOrderID(layer)
+			}
+
+			s.logger().Infof("%s post-only %s order rejected for crossing the book, re-pricing %v -> %v: %v",
+				s.Symbol, submitOrder.Side, submitOrder.Price, repricedOrder.Price, err)
+			submitOrder = repricedOrder
+		}
+	}
+
+	return createdOrders, errs
+}
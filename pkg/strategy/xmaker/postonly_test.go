@@ -0,0 +1,179 @@
+package xmaker
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/c9s/bbgo/pkg/bbgo"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+	"github.com/c9s/bbgo/pkg/types/mocks"
+)
+
+func TestIsPostOnlyRejection(t *testing.T) {
+	assert.False(t, isPostOnlyRejection(nil))
+	assert.False(t, isPostOnlyRejection(errors.New("insufficient balance")))
+	assert.True(t, isPostOnlyRejection(errors.New("Order would immediately match and take.")))
+	assert.True(t, isPostOnlyRejection(errors.New("the Post-Only order is rejected")))
+	assert.True(t, isPostOnlyRejection(errors.New("post_only order would cross the book")))
+}
+
+func TestRepricePostOnlyOrder(t *testing.T) {
+	number := fixedpoint.MustNewFromString
+	market := types.Market{Symbol: "BTCUSDT", TickSize: number("0.01")}
+
+	bid := repricePostOnlyOrder(types.SubmitOrder{Side: types.SideTypeBuy, Price: number("100.00")}, market)
+	assert.Equal(t, "99.99", bid.Price.String())
+
+	ask := repricePostOnlyOrder(types.SubmitOrder{Side: types.SideTypeSell, Price: number("100.00")}, market)
+	assert.Equal(t, "100.01", ask.Price.String())
+}
+
+func TestStrategy_SubmitMakerOrders_PostOnly(t *testing.T) {
+	number := fixedpoint.MustNewFromString
+	ctx := context.Background()
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	market := types.Market{
+		Symbol:        "BTCUSDT",
+		BaseCurrency:  "BTC",
+		QuoteCurrency: "USDT",
+		TickSize:      number("0.01"),
+		StepSize:      number("0.0001"),
+	}
+
+	mockEx := mocks.NewMockExchange(mockCtrl)
+	mockEx.EXPECT().NewStream().Return(&types.StandardStream{}).Times(2)
+	session := bbgo.NewExchangeSession("max", mockEx)
+	session.SetMarkets(types.MarketMap{market.Symbol: market})
+
+	s := &Strategy{
+		Symbol:              market.Symbol,
+		PostOnly:            true,
+		PostOnlyMaxReprices: 1,
+		makerSession:        session,
+		makerMarket:         market,
+	}
+
+	rejection := errors.New("Order would immediately match and take.")
+
+	gomock.InOrder(
+		// the bid is rejected twice, the second rejection exceeds the max re-pricing attempts
+		mockEx.EXPECT().SubmitOrder(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, o types.SubmitOrder) (*types.Order, error) {
+			assert.Equal(t, types.OrderTypeLimitMaker, o.Type)
+			assert.Equal(t, "100", o.Price.String())
+			return nil, rejection
+		}),
+		mockEx.EXPECT().SubmitOrder(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, o types.SubmitOrder) (*types.Order, error) {
+			assert.Equal(t, "99.99", o.Price.String())
+			return nil, rejection
+		}),
+
+		// the ask is accepted after re-pricing
+		mockEx.EXPECT().SubmitOrder(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, o types.SubmitOrder) (*types.Order, error) {
+			assert.Equal(t, "101", o.Price.String())
+			return nil, rejection
+		}),
+		mockEx.EXPECT().SubmitOrder(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, o types.SubmitOrder) (*types.Order, error) {
+			assert.Equal(t, "101.01", o.Price.String())
+			return &types.Order{SubmitOrder: o, OrderID: 1}, nil
+		}),
+	)
+
+	createdOrders, err := s.submitMakerOrders(ctx, nil, []types.SubmitOrder{
+		{Symbol: market.Symbol, Side: types.SideTypeBuy, Type: types.OrderTypeLimit, Price: number("100"), Quantity: number("0.1")},
+		{Symbol: market.Symbol, Side: types.SideTypeSell, Type: types.OrderTypeLimit, Price: number("101"), Quantity: number("0.1")},
+	})
+
+	assert.Error(t, err)
+	if assert.Len(t, createdOrders, 1) {
+		assert.Equal(t, types.SideTypeSell, createdOrders[0].Side)
+		assert.Equal(t, "101.01", createdOrders[0].Price.String())
+	}
+}
+
+func TestStrategy_SubmitMakerOrders_SelfTradePrevention(t *testing.T) {
+	number := fixedpoint.MustNewFromString
+	ctx := context.Background()
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	market := types.Market{
+		Symbol:        "BTCUSDT",
+		BaseCurrency:  "BTC",
+		QuoteCurrency: "USDT",
+		TickSize:      number("0.01"),
+		StepSize:      number("0.0001"),
+	}
+
+	mockEx := mocks.NewMockExchange(mockCtrl)
+	mockEx.EXPECT().NewStream().Return(&types.StandardStream{}).Times(2)
+	session := bbgo.NewExchangeSession("binance", mockEx)
+	session.SetMarkets(types.MarketMap{market.Symbol: market})
+
+	s := &Strategy{
+		Symbol:                  market.Symbol,
+		PostOnly:                true,
+		SelfTradePreventionMode: types.SelfTradePreventionModeExpireMaker,
+		makerSession:            session,
+		makerMarket:             market,
+	}
+
+	mockEx.EXPECT().SubmitOrder(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, o types.SubmitOrder) (*types.Order, error) {
+		assert.Equal(t, types.SelfTradePreventionModeExpireMaker, o.SelfTradePreventionMode)
+		return &types.Order{SubmitOrder: o, OrderID: 1}, nil
+	})
+
+	createdOrders, err := s.submitMakerOrders(ctx, nil, []types.SubmitOrder{
+		{Symbol: market.Symbol, Side: types.SideTypeBuy, Type: types.OrderTypeLimit, Price: number("100"), Quantity: number("0.1")},
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, createdOrders, 1)
+}
+
+func TestStrategy_SubmitMakerOrders_PostOnly_Vetoed(t *testing.T) {
+	number := fixedpoint.MustNewFromString
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	market := types.Market{
+		Symbol:        "BTCUSDT",
+		BaseCurrency:  "BTC",
+		QuoteCurrency: "USDT",
+		TickSize:      number("0.01"),
+		StepSize:      number("0.0001"),
+	}
+
+	// no order is submitted to the exchange once the orders are vetoed
+	mockEx := mocks.NewMockExchange(mockCtrl)
+	mockEx.EXPECT().NewStream().Return(&types.StandardStream{}).Times(2)
+	session := bbgo.NewExchangeSession("max", mockEx)
+	session.SetMarkets(types.MarketMap{market.Symbol: market})
+
+	veto := errors.New("exposure limit exceeded")
+	session.SetOrderMiddleware("veto", func(ctx context.Context, session *bbgo.ExchangeSession, orders []types.SubmitOrder) error {
+		return veto
+	})
+
+	s := &Strategy{
+		Symbol:       market.Symbol,
+		PostOnly:     true,
+		makerSession: session,
+		makerMarket:  market,
+	}
+
+	createdOrders, err := s.submitMakerOrders(context.Background(), nil, []types.SubmitOrder{
+		{Symbol: market.Symbol, Side: types.SideTypeBuy, Type: types.OrderTypeLimit, Price: number("100"), Quantity: number("0.1")},
+	})
+	assert.Equal(t, veto, err)
+	assert.Empty(t, createdOrders)
+}
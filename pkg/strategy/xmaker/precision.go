@@ -0,0 +1,93 @@
+package xmaker
+
+import (
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// LayerPrecisionPolicy defines how the layers are adjusted when their prices collapse into the same maker price,
+// which happens when the maker tick size is coarser than the source and the layer spacing is below one tick.
+type LayerPrecisionPolicy string
+
+const (
+	// LayerPrecisionPolicySkip drops the layers that have the same maker price as the previous layer
+	LayerPrecisionPolicySkip LayerPrecisionPolicy = "skip"
+
+	// LayerPrecisionPolicyMerge adds the quantity of the collapsed layers to the previous layer of the same price
+	LayerPrecisionPolicyMerge LayerPrecisionPolicy = "merge"
+)
+
+// roundLayerPrice rounds the price to the tick size away from the spread,
+// the bid prices are rounded down and the ask prices are rounded up, so that the margin is never reduced.
+func roundLayerPrice(side types.SideType, price, tickSize fixedpoint.Value) fixedpoint.Value {
+	if tickSize.Sign() <= 0 {
+		return price
+	}
+
+	ticks := price.Div(tickSize)
+	if side == types.SideTypeSell {
+		return ticks.Ceil().Mul(tickSize)
+	}
+
+	return ticks.Floor().Mul(tickSize)
+}
+
+// adjustLayerPrecision rounds the maker layer prices to the maker tick size, and skips or merges the layers
+// whose rounded price is not deeper than the previous layer of the same side, those orders would be rejected
+// by the exchange as the duplicated price levels.
+func adjustLayerPrecision(
+	policy LayerPrecisionPolicy, market types.Market, submitOrders []types.SubmitOrder,
+) (adjusted []types.SubmitOrder, numCollapsed int) {
+	lastIndex := map[types.SideType]int{}
+	for _, submitOrder := range submitOrders {
+		submitOrder.Price = roundLayerPrice(submitOrder.Side, submitOrder.Price, market.TickSize)
+
+		idx, ok := lastIndex[submitOrder.Side]
+		if ok && !isDeeperLayerPrice(submitOrder.Side, submitOrder.Price, adjusted[idx].Price) {
+			numCollapsed++
+			if policy == LayerPrecisionPolicyMerge {
+				adjusted[idx].Quantity = adjusted[idx].Quantity.Add(submitOrder.Quantity)
+			}
+			continue
+		}
+
+		lastIndex[submitOrder.Side] = len(adjusted)
+		adjusted = append(adjusted, submitOrder)
+	}
+
+	return adjusted, numCollapsed
+}
+
+func isDeeperLayerPrice(side types.SideType, price, previous fixedpoint.Value) bool {
+	if side == types.SideTypeSell {
+		return price.Compare(previous) > 0
+	}
+
+	return price.Compare(previous) < 0
+}
+
+// detectCoarseMakerTickSize warns when the maker tick size is coarser than the tick size of the source markets,
+// the layers that are spaced below one maker tick collapse into the same price in this case.
+func (s *Strategy) detectCoarseMakerTickSize() {
+	// the tick sizes are not comparable when the source prices are converted into another quote currency
+	if s.quoteConverter != nil || s.makerMarket.TickSize.Sign() <= 0 {
+		return
+	}
+
+	for sourceExchange, sourceMarket := range s.sourceMarkets {
+		if s.makerMarket.TickSize.Compare(sourceMarket.TickSize) <= 0 {
+			continue
+		}
+
+		if s.LayerPrecisionPolicy == "" {
+			s.logger().Warnf("%s maker tick size %v is coarser than the %s source tick size %v, "+
+				"the layers spaced below one tick may be placed at the same price and rejected, "+
+				"set layerPrecisionPolicy to skip or merge the collapsed layers",
+				s.Symbol, s.makerMarket.TickSize, sourceExchange, sourceMarket.TickSize)
+		} else {
+			s.logger().Warnf("%s maker tick size %v is coarser than the %s source tick size %v, "+
+				"the collapsed layers will be handled by the %q layer precision policy",
+				s.Symbol, s.makerMarket.TickSize, sourceExchange, sourceMarket.TickSize, s.LayerPrecisionPolicy)
+		}
+	}
+}
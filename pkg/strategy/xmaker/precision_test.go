@@ -0,0 +1,71 @@
+package xmaker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestAdjustLayerPrecision(t *testing.T) {
+	number := fixedpoint.MustNewFromString
+	market := types.Market{
+		Symbol:   "BTCUSDT",
+		TickSize: number("0.1"),
+	}
+
+	// the layers are spaced by 0.03 on the source, which is below one maker tick
+	submitOrders := []types.SubmitOrder{
+		{Side: types.SideTypeBuy, Price: number("100.05"), Quantity: number("1")},
+		{Side: types.SideTypeSell, Price: number("100.25"), Quantity: number("1")},
+		{Side: types.SideTypeBuy, Price: number("100.02"), Quantity: number("2")},
+		{Side: types.SideTypeSell, Price: number("100.28"), Quantity: number("2")},
+		{Side: types.SideTypeBuy, Price: number("99.99"), Quantity: number("3")},
+		{Side: types.SideTypeSell, Price: number("100.31"), Quantity: number("3")},
+	}
+
+	t.Run("skip", func(t *testing.T) {
+		adjusted, numCollapsed := adjustLayerPrecision(LayerPrecisionPolicySkip, market, submitOrders)
+		assert.Equal(t, 2, numCollapsed)
+		if assert.Len(t, adjusted, 4) {
+			assert.Equal(t, "100", adjusted[0].Price.String())
+			assert.Equal(t, "1", adjusted[0].Quantity.String())
+			assert.Equal(t, "100.3", adjusted[1].Price.String())
+			assert.Equal(t, "1", adjusted[1].Quantity.String())
+			assert.Equal(t, "99.9", adjusted[2].Price.String())
+			assert.Equal(t, "3", adjusted[2].Quantity.String())
+			assert.Equal(t, "100.4", adjusted[3].Price.String())
+			assert.Equal(t, "3", adjusted[3].Quantity.String())
+		}
+	})
+
+	t.Run("merge", func(t *testing.T) {
+		adjusted, numCollapsed := adjustLayerPrecision(LayerPrecisionPolicyMerge, market, submitOrders)
+		assert.Equal(t, 2, numCollapsed)
+		if assert.Len(t, adjusted, 4) {
+			assert.Equal(t, "100", adjusted[0].Price.String())
+			assert.Equal(t, "3", adjusted[0].Quantity.String())
+			assert.Equal(t, "100.3", adjusted[1].Price.String())
+			assert.Equal(t, "3", adjusted[1].Quantity.String())
+			assert.Equal(t, "99.9", adjusted[2].Price.String())
+			assert.Equal(t, "100.4", adjusted[3].Price.String())
+		}
+	})
+
+	// the input orders are not modified
+	assert.Equal(t, "100.05", submitOrders[0].Price.String())
+}
+
+func TestStrategy_Validate_layerPrecisionPolicy(t *testing.T) {
+	s := &Strategy{
+		Symbol:                "BTCUSDT",
+		QuantityByEquityRatio: fixedpoint.NewFromFloat(0.1),
+		LayerPrecisionPolicy:  LayerPrecisionPolicyMerge,
+	}
+	assert.NoError(t, s.Validate())
+
+	s.LayerPrecisionPolicy = "round"
+	assert.Error(t, s.Validate())
+}
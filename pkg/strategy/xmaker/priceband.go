@@ -0,0 +1,96 @@
+package xmaker
+
+import (
+	"fmt"
+
+	"github.com/c9s/bbgo/pkg/bbgo"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// PriceBand suppresses quoting when the source mid-price deviates from a slower reference price beyond the threshold,
+// it protects the maker quotes from the corrupted book data or the flash wicks on the source exchange.
+type PriceBand struct {
+	Enabled bool `json:"enabled"`
+
+	// ReferenceEMA is the EMA of the source price used as the reference price, defaults to 1m EMA with window 30
+	ReferenceEMA types.IntervalWindow `json:"referenceEMA"`
+
+	// MaxDeviation is the max deviation ratio of the source mid-price from the reference price, defaults to 0.02 (2%)
+	MaxDeviation fixedpoint.Value `json:"maxDeviation"`
+}
+
+func (b *PriceBand) Defaults() {
+	if b.ReferenceEMA.Interval == "" {
+		b.ReferenceEMA.Interval = types.Interval1m
+	}
+
+	if b.ReferenceEMA.Window == 0 {
+		b.ReferenceEMA.Window = 30
+	}
+
+	if b.MaxDeviation.IsZero() {
+		b.MaxDeviation = fixedpoint.NewFromFloat(0.02)
+	}
+}
+
+func (b *PriceBand) Validate() error {
+	if b.MaxDeviation.Sign() < 0 {
+		return fmt.Errorf("priceBand maxDeviation should not be negative, got %v", b.MaxDeviation)
+	}
+
+	if b.ReferenceEMA.Window < 0 {
+		return fmt.Errorf("priceBand referenceEMA window should not be negative, got %d", b.ReferenceEMA.Window)
+	}
+
+	return nil
+}
+
+// priceDeviation calculates the absolute deviation ratio of the price from the reference price
+func priceDeviation(price, reference fixedpoint.Value) fixedpoint.Value {
+	return price.Sub(reference).Abs().Div(reference)
+}
+
+// checkPriceBand checks the source mid-price against the reference price,
+// it returns false when the quoting should be suppressed.
+func (s *Strategy) checkPriceBand(midPrice fixedpoint.Value) bool {
+	if s.priceBandEMA == nil {
+		return true
+	}
+
+	reference := fixedpoint.NewFromFloat(s.priceBandEMA.Last(0))
+
+	// the reference price is not ready yet
+	if reference.Sign() <= 0 {
+		return true
+	}
+
+	deviation := priceDeviation(midPrice, reference)
+	priceBandDeviationMetrics.With(s.metricsLabels()).Set(deviation.Float64())
+
+	withinBand := deviation.Compare(s.PriceBand.MaxDeviation) <= 0
+	if withinBand {
+		if s.priceBandSuppressed {
+			s.logger().Infof("%s source mid-price %v is back in the price band of the reference price %v, resuming quoting",
+				s.Symbol, midPrice, reference)
+			bbgo.Notify("%s: %s quoting resumed, the source mid-price %v is back in the price band", ID, s.Symbol, midPrice)
+		}
+	} else {
+		s.logger().Warnf("%s source mid-price %v deviates %v from the reference price %v, exceeding the max deviation %v, suppressing quoting",
+			s.Symbol, midPrice, deviation, reference, s.PriceBand.MaxDeviation)
+
+		if !s.priceBandSuppressed {
+			bbgo.Notify("%s: %s quoting suppressed, the source mid-price %v deviates %v from the reference price %v",
+				ID, s.Symbol, midPrice, deviation, reference)
+		}
+	}
+
+	s.priceBandSuppressed = !withinBand
+	if s.priceBandSuppressed {
+		priceBandSuppressedMetrics.With(s.metricsLabels()).Set(1.0)
+	} else {
+		priceBandSuppressedMetrics.With(s.metricsLabels()).Set(0.0)
+	}
+
+	return withinBand
+}
@@ -0,0 +1,49 @@
+package xmaker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	indicatorv2 "github.com/c9s/bbgo/pkg/indicator/v2"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestPriceBand_Defaults(t *testing.T) {
+	band := &PriceBand{Enabled: true}
+	band.Defaults()
+
+	assert.Equal(t, types.Interval1m, band.ReferenceEMA.Interval)
+	assert.Equal(t, 30, band.ReferenceEMA.Window)
+	assert.Equal(t, "0.02", band.MaxDeviation.String())
+	assert.NoError(t, band.Validate())
+
+	band.MaxDeviation = fixedpoint.NewFromFloat(-0.01)
+	assert.Error(t, band.Validate())
+}
+
+func TestStrategy_CheckPriceBand(t *testing.T) {
+	number := fixedpoint.MustNewFromString
+
+	source := types.NewFloat64Series()
+	s := &Strategy{
+		Symbol:       "BTCUSDT",
+		PriceBand:    &PriceBand{Enabled: true, MaxDeviation: number("0.02")},
+		priceBandEMA: indicatorv2.EWMA2(source, 30),
+	}
+
+	// the reference price is not ready yet
+	assert.True(t, s.checkPriceBand(number("30000")))
+
+	source.PushAndEmit(20000.0)
+	assert.True(t, s.checkPriceBand(number("20300")))
+	assert.False(t, s.priceBandSuppressed)
+
+	// a flash wick on the source exchange
+	assert.False(t, s.checkPriceBand(number("21000")))
+	assert.True(t, s.priceBandSuppressed)
+
+	assert.True(t, s.checkPriceBand(number("19700")))
+	assert.False(t, s.priceBandSuppressed)
+}
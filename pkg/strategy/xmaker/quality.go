@@ -0,0 +1,167 @@
+package xmaker
+
+import (
+	"sync"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+var bps = fixedpoint.NewFromInt(10000)
+
+// quotedSpreadBps calculates the spread between the best bid and the best ask of the quotes in bps of the mid-price,
+// ok is false when the quotes are not two-sided.
+func quotedSpreadBps(orders []types.SubmitOrder) (fixedpoint.Value, bool) {
+	var bestBid, bestAsk fixedpoint.Value
+	for _, o := range orders {
+		switch o.Side {
+		case types.SideTypeBuy:
+			if bestBid.IsZero() || o.Price.Compare(bestBid) > 0 {
+				bestBid = o.Price
+			}
+
+		case types.SideTypeSell:
+			if bestAsk.IsZero() || o.Price.Compare(bestAsk) < 0 {
+				bestAsk = o.Price
+			}
+		}
+	}
+
+	if bestBid.IsZero() || bestAsk.IsZero() {
+		return fixedpoint.Zero, false
+	}
+
+	mid := bestBid.Add(bestAsk).Div(fixedpoint.Two)
+	return bestAsk.Sub(bestBid).Div(mid).Mul(bps), true
+}
+
+// quoteUptime measures the time-weighted ratio of the time that the quotes are two-sided
+type quoteUptime struct {
+	mu sync.Mutex
+
+	startTime    time.Time
+	lastTime     time.Time
+	lastTwoSided bool
+
+	twoSidedDuration time.Duration
+}
+
+// Update accumulates the duration since the last update by the last two-sided state, and returns the uptime ratio
+func (u *quoteUptime) Update(now time.Time, twoSided bool) float64 {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if u.startTime.IsZero() {
+		u.startTime = now
+	} else if u.lastTwoSided {
+		u.twoSidedDuration += now.Sub(u.lastTime)
+	}
+
+	u.lastTime = now
+	u.lastTwoSided = twoSided
+
+	total := now.Sub(u.startTime)
+	if total <= 0 {
+		if twoSided {
+			return 1.0
+		}
+
+		return 0.0
+	}
+
+	return u.twoSidedDuration.Seconds() / total.Seconds()
+}
+
+// hedgeLatency tracks the time of the earliest maker trade that is not hedged yet
+type hedgeLatency struct {
+	mu            sync.Mutex
+	unhedgedSince time.Time
+}
+
+// AddMakerTrade records the maker trade time if there is no pending maker trade
+func (l *hedgeLatency) AddMakerTrade(tradeTime time.Time) {
+	l.mu.Lock()
+	if l.unhedgedSince.IsZero() || tradeTime.Before(l.unhedgedSince) {
+		l.unhedgedSince = tradeTime
+	}
+	l.mu.Unlock()
+}
+
+// Hedged returns the latency from the earliest pending maker trade to the hedge submission
+func (l *hedgeLatency) Hedged(now time.Time) (time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.unhedgedSince.IsZero() {
+		return 0, false
+	}
+
+	latency := now.Sub(l.unhedgedSince)
+	l.unhedgedSince = time.Time{}
+	return latency, true
+}
+
+// updateQuotedSpreadMetrics observes the spread of the generated quotes
+func (s *Strategy) updateQuotedSpreadMetrics(orders []types.SubmitOrder) {
+	if spread, ok := quotedSpreadBps(orders); ok {
+		quotedSpreadMetrics.With(s.metricsLabels()).Observe(spread.Float64())
+	}
+}
+
+// updateQuoteUptimeMetrics samples whether the maker quotes are two-sided
+func (s *Strategy) updateQuoteUptimeMetrics() {
+	var hasBid, hasAsk bool
+	if s.shadow != nil {
+		s.shadow.mu.Lock()
+		for _, o := range s.shadow.orders {
+			hasBid = hasBid || o.Side == types.SideTypeBuy
+			hasAsk = hasAsk || o.Side == types.SideTypeSell
+		}
+		s.shadow.mu.Unlock()
+	} else {
+		for _, o := range s.activeMakerOrders.Orders() {
+			hasBid = hasBid || o.Side == types.SideTypeBuy
+			hasAsk = hasAsk || o.Side == types.SideTypeSell
+		}
+	}
+
+	ratio := s.quoteUptime.Update(time.Now(), hasBid && hasAsk)
+	quoteUptimeMetrics.With(s.metricsLabels()).Set(ratio)
+}
+
+// addMakerOrderMetrics counts the submitted maker orders for the order-to-trade ratio
+func (s *Strategy) addMakerOrderMetrics(numOrders int) {
+	if numOrders == 0 {
+		return
+	}
+
+	makerOrdersMetrics.With(s.metricsLabels()).Add(float64(numOrders))
+	s.updateOrderToTradeRatioMetrics(numOrders, 0)
+}
+
+// addMakerTradeMetrics counts the maker trade for the order-to-trade ratio, and starts the hedge latency measurement
+func (s *Strategy) addMakerTradeMetrics(trade types.Trade) {
+	makerTradesMetrics.With(s.metricsLabels()).Inc()
+	s.updateOrderToTradeRatioMetrics(0, 1)
+	s.hedgeLatency.AddMakerTrade(trade.Time.Time())
+}
+
+func (s *Strategy) updateOrderToTradeRatioMetrics(numOrders, numTrades int) {
+	s.qualityMu.Lock()
+	s.numMakerOrders += numOrders
+	s.numMakerTrades += numTrades
+	orders, trades := s.numMakerOrders, s.numMakerTrades
+	s.qualityMu.Unlock()
+
+	if trades > 0 {
+		orderToTradeRatioMetrics.With(s.metricsLabels()).Set(float64(orders) / float64(trades))
+	}
+}
+
+// observeHedgeLatency observes the latency from the maker trade to the hedge submission
+func (s *Strategy) observeHedgeLatency() {
+	if latency, ok := s.hedgeLatency.Hedged(time.Now()); ok {
+		hedgeLatencyMetrics.With(s.metricsLabels()).Observe(latency.Seconds())
+	}
+}
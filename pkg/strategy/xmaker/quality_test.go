@@ -0,0 +1,59 @@
+package xmaker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestQuotedSpreadBps(t *testing.T) {
+	number := fixedpoint.MustNewFromString
+
+	_, ok := quotedSpreadBps([]types.SubmitOrder{{Side: types.SideTypeBuy, Price: number("99")}})
+	assert.False(t, ok)
+
+	spread, ok := quotedSpreadBps([]types.SubmitOrder{
+		{Side: types.SideTypeBuy, Price: number("99.9")},
+		{Side: types.SideTypeBuy, Price: number("99.8")},
+		{Side: types.SideTypeSell, Price: number("100.1")},
+		{Side: types.SideTypeSell, Price: number("100.2")},
+	})
+	if assert.True(t, ok) {
+		assert.Equal(t, "20", spread.String())
+	}
+}
+
+func TestQuoteUptime(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	var uptime quoteUptime
+	assert.Equal(t, 1.0, uptime.Update(now, true))
+
+	// two-sided for 30s, then one-sided for 10s
+	assert.Equal(t, 1.0, uptime.Update(now.Add(30*time.Second), false))
+	assert.Equal(t, 0.75, uptime.Update(now.Add(40*time.Second), true))
+	assert.InDelta(t, 0.8, uptime.Update(now.Add(50*time.Second), true), 1e-9)
+}
+
+func TestHedgeLatency(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	var latency hedgeLatency
+	_, ok := latency.Hedged(now)
+	assert.False(t, ok)
+
+	latency.AddMakerTrade(now.Add(time.Second))
+	latency.AddMakerTrade(now)
+
+	d, ok := latency.Hedged(now.Add(3 * time.Second))
+	if assert.True(t, ok) {
+		assert.Equal(t, 3*time.Second, d)
+	}
+
+	_, ok = latency.Hedged(now.Add(4 * time.Second))
+	assert.False(t, ok)
+}
@@ -0,0 +1,181 @@
+package xmaker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/bbgo"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/pricesolver"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// QuoteConversion quotes the maker market from a source market of a different quote currency,
+// e.g. quoting BTCUSDT on the maker session while hedging BTCUSDC on the source sessions.
+// The source prices and the hedge trades are converted into the maker quote currency by the price solver,
+// and the exposure of the source quote currency (the FX leg) is tracked separately by the FXPosition.
+type QuoteConversion struct {
+	Enabled bool `json:"enabled"`
+
+	// SourceSymbol is the symbol of the hedge market on the source sessions, e.g. BTCUSDC
+	SourceSymbol string `json:"sourceSymbol"`
+
+	// ConversionSymbols are the markets of the primary source session used for converting
+	// the source quote currency into the maker quote currency, e.g. [USDCUSDT]
+	ConversionSymbols []string `json:"conversionSymbols"`
+}
+
+func (c *QuoteConversion) Validate() error {
+	if c.SourceSymbol == "" {
+		return fmt.Errorf("quoteConversion sourceSymbol is required")
+	}
+
+	if len(c.ConversionSymbols) == 0 {
+		return fmt.Errorf("quoteConversion conversionSymbols is required")
+	}
+
+	return nil
+}
+
+// sourceSymbol returns the symbol of the source markets
+func (s *Strategy) sourceSymbol() string {
+	if s.QuoteConversion != nil && s.QuoteConversion.Enabled {
+		return s.QuoteConversion.SourceSymbol
+	}
+
+	return s.Symbol
+}
+
+// subscribeConversionSymbols subscribes the klines of the conversion symbols for updating the price solver
+func (s *Strategy) subscribeConversionSymbols(sourceSession *bbgo.ExchangeSession) {
+	for _, symbol := range s.QuoteConversion.ConversionSymbols {
+		sourceSession.Subscribe(types.KLineChannel, symbol, types.SubscribeOptions{Interval: types.Interval1m})
+	}
+}
+
+// quoteConverter converts the prices of the source quote currency into the maker quote currency
+type quoteConverter struct {
+	solver *pricesolver.SimplePriceSolver
+
+	sourceQuote, makerQuote string
+}
+
+func newQuoteConverter(solver *pricesolver.SimplePriceSolver, sourceQuote, makerQuote string) *quoteConverter {
+	return &quoteConverter{
+		solver:      solver,
+		sourceQuote: sourceQuote,
+		makerQuote:  makerQuote,
+	}
+}
+
+// Rate returns the price of one unit of the source quote currency in the maker quote currency
+func (c *quoteConverter) Rate() (fixedpoint.Value, bool) {
+	return c.solver.ResolvePrice(c.sourceQuote, c.makerQuote)
+}
+
+// convertTrade converts the source trade into the trade of the maker symbol,
+// the price, the quote quantity and the fee of the source quote currency are converted by the rate.
+func convertTrade(trade types.Trade, symbol, sourceQuote, makerQuote string, rate fixedpoint.Value) types.Trade {
+	trade.Symbol = symbol
+	trade.Price = trade.Price.Mul(rate)
+	trade.QuoteQuantity = trade.QuoteQuantity.Mul(rate)
+
+	if trade.FeeCurrency == sourceQuote {
+		trade.Fee = trade.Fee.Mul(rate)
+		trade.FeeCurrency = makerQuote
+	}
+
+	return trade
+}
+
+// convertOrderBook converts the prices of the order book by the rate
+func convertOrderBook(book types.OrderBook, symbol string, rate fixedpoint.Value) types.OrderBook {
+	converted := types.NewSliceOrderBook(symbol)
+	converted.Time = book.LastUpdateTime()
+
+	for _, pv := range book.SideBook(types.SideTypeBuy) {
+		converted.Bids = append(converted.Bids, types.PriceVolume{Price: pv.Price.Mul(rate), Volume: pv.Volume})
+	}
+
+	for _, pv := range book.SideBook(types.SideTypeSell) {
+		converted.Asks = append(converted.Asks, types.PriceVolume{Price: pv.Price.Mul(rate), Volume: pv.Volume})
+	}
+
+	return converted
+}
+
+// convertSourcePrice converts the source price into the maker quote currency,
+// the price is returned as-is when the conversion is disabled or the rate is not available.
+func (s *Strategy) convertSourcePrice(price fixedpoint.Value) fixedpoint.Value {
+	if s.quoteConverter == nil {
+		return price
+	}
+
+	if rate, ok := s.quoteConverter.Rate(); ok {
+		return price.Mul(rate)
+	}
+
+	return price
+}
+
+// handleSourceTrade converts the trade of the source symbol before it's collected,
+// so that the position and the profit stats are all in the maker quote currency.
+func (s *Strategy) handleSourceTrade(trade types.Trade) {
+	if trade.Symbol != s.QuoteConversion.SourceSymbol {
+		return
+	}
+
+	rate, ok := s.quoteConverter.Rate()
+	if !ok {
+		s.logger().Errorf("%s unable to convert the trade %s, the %s/%s rate is not available",
+			s.Symbol, trade.String(), s.quoteConverter.sourceQuote, s.quoteConverter.makerQuote)
+		return
+	}
+
+	s.tradeCollector.ProcessTrade(convertTrade(trade, s.Symbol, s.quoteConverter.sourceQuote, s.quoteConverter.makerQuote, rate))
+}
+
+// recoverSourceTrades recovers the missing trades of the source symbol, the trades are converted before they're collected
+func (s *Strategy) recoverSourceTrades(ctx context.Context, sourceSession *bbgo.ExchangeSession, startTime time.Time) error {
+	service, ok := sourceSession.Exchange.(types.ExchangeTradeHistoryService)
+	if !ok {
+		return fmt.Errorf("source session %s does not support querying trades", sourceSession.Name)
+	}
+
+	trades, err := service.QueryTrades(ctx, s.QuoteConversion.SourceSymbol, &types.TradeQueryOptions{
+		StartTime: &startTime,
+	})
+	if err != nil {
+		return err
+	}
+
+	rate, ok := s.quoteConverter.Rate()
+	if !ok {
+		return fmt.Errorf("the %s/%s rate is not available", s.quoteConverter.sourceQuote, s.quoteConverter.makerQuote)
+	}
+
+	for _, trade := range trades {
+		s.tradeCollector.RecoverTrade(convertTrade(trade, s.Symbol, s.quoteConverter.sourceQuote, s.quoteConverter.makerQuote, rate))
+	}
+
+	return nil
+}
+
+// updateFXPosition updates the net position of the source quote currency by the converted hedge trade,
+// the position is valued in the maker quote currency at the conversion rates of the trades.
+func (s *Strategy) updateFXPosition(trade types.Trade) {
+	if trade.Side == types.SideTypeSell {
+		s.FXPosition = s.FXPosition.Add(trade.QuoteQuantity)
+	} else {
+		s.FXPosition = s.FXPosition.Sub(trade.QuoteQuantity)
+	}
+
+	if trade.FeeCurrency == s.quoteConverter.makerQuote {
+		s.FXPosition = s.FXPosition.Sub(trade.Fee)
+	}
+
+	labels := s.metricsLabels()
+	labels["currency"] = s.quoteConverter.sourceQuote
+	fxPositionMetrics.With(labels).Set(s.FXPosition.Float64())
+}
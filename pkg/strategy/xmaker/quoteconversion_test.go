@@ -0,0 +1,110 @@
+package xmaker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/pricesolver"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestQuoteConversion_Validate(t *testing.T) {
+	c := &QuoteConversion{Enabled: true}
+	assert.Error(t, c.Validate())
+
+	c.SourceSymbol = "BTCUSDC"
+	assert.Error(t, c.Validate())
+
+	c.ConversionSymbols = []string{"USDCUSDT"}
+	assert.NoError(t, c.Validate())
+}
+
+func TestStrategy_sourceSymbol(t *testing.T) {
+	s := &Strategy{Symbol: "BTCUSDT"}
+	assert.Equal(t, "BTCUSDT", s.sourceSymbol())
+
+	s.QuoteConversion = &QuoteConversion{Enabled: true, SourceSymbol: "BTCUSDC"}
+	assert.Equal(t, "BTCUSDC", s.sourceSymbol())
+}
+
+func Test_convertTrade(t *testing.T) {
+	trade := types.Trade{
+		Symbol:        "BTCUSDC",
+		Side:          types.SideTypeSell,
+		Price:         fixedpoint.NewFromFloat(20000.0),
+		Quantity:      fixedpoint.NewFromFloat(0.1),
+		QuoteQuantity: fixedpoint.NewFromFloat(2000.0),
+		Fee:           fixedpoint.NewFromFloat(2.0),
+		FeeCurrency:   "USDC",
+	}
+
+	converted := convertTrade(trade, "BTCUSDT", "USDC", "USDT", fixedpoint.NewFromFloat(1.001))
+	assert.Equal(t, "BTCUSDT", converted.Symbol)
+	assert.InDelta(t, 20020.0, converted.Price.Float64(), 1e-3)
+	assert.InDelta(t, 2002.0, converted.QuoteQuantity.Float64(), 1e-3)
+	assert.InDelta(t, 2.002, converted.Fee.Float64(), 1e-3)
+	assert.Equal(t, "USDT", converted.FeeCurrency)
+	assert.Equal(t, trade.Quantity, converted.Quantity)
+
+	// the fee of the other currencies is kept as-is
+	trade.FeeCurrency = "BNB"
+	converted = convertTrade(trade, "BTCUSDT", "USDC", "USDT", fixedpoint.NewFromFloat(1.001))
+	assert.Equal(t, trade.Fee, converted.Fee)
+	assert.Equal(t, "BNB", converted.FeeCurrency)
+}
+
+func Test_convertOrderBook(t *testing.T) {
+	book := types.NewSliceOrderBook("BTCUSDC")
+	book.Bids = types.PriceVolumeSlice{{Price: fixedpoint.NewFromFloat(100.0), Volume: fixedpoint.One}}
+	book.Asks = types.PriceVolumeSlice{{Price: fixedpoint.NewFromFloat(101.0), Volume: fixedpoint.Two}}
+
+	converted := convertOrderBook(book, "BTCUSDT", fixedpoint.NewFromFloat(0.5))
+
+	bid, ok := converted.BestBid()
+	assert.True(t, ok)
+	assert.Equal(t, fixedpoint.NewFromFloat(50.0), bid.Price)
+	assert.Equal(t, fixedpoint.One, bid.Volume)
+
+	ask, ok := converted.BestAsk()
+	assert.True(t, ok)
+	assert.Equal(t, fixedpoint.NewFromFloat(50.5), ask.Price)
+	assert.Equal(t, fixedpoint.Two, ask.Volume)
+}
+
+func TestStrategy_updateFXPosition(t *testing.T) {
+	solver := pricesolver.NewSimplePriceResolver(types.MarketMap{
+		"USDCUSDT": {Symbol: "USDCUSDT", BaseCurrency: "USDC", QuoteCurrency: "USDT"},
+	})
+
+	s := &Strategy{
+		Symbol:         "BTCUSDT",
+		quoteConverter: newQuoteConverter(solver, "USDC", "USDT"),
+	}
+
+	_, ok := s.quoteConverter.Rate()
+	assert.False(t, ok)
+
+	solver.Update("USDCUSDT", fixedpoint.NewFromFloat(1.001))
+	rate, ok := s.quoteConverter.Rate()
+	assert.True(t, ok)
+	assert.Equal(t, fixedpoint.NewFromFloat(1.001), rate)
+
+	// the hedge sell receives the source quote currency
+	s.updateFXPosition(types.Trade{
+		Side:          types.SideTypeSell,
+		QuoteQuantity: fixedpoint.NewFromFloat(2002.0),
+		Fee:           fixedpoint.NewFromFloat(2.0),
+		FeeCurrency:   "USDT",
+	})
+	assert.Equal(t, fixedpoint.NewFromFloat(2000.0), s.FXPosition)
+
+	s.updateFXPosition(types.Trade{
+		Side:          types.SideTypeBuy,
+		QuoteQuantity: fixedpoint.NewFromFloat(1500.0),
+		FeeCurrency:   "BNB",
+		Fee:           fixedpoint.NewFromFloat(0.01),
+	})
+	assert.Equal(t, fixedpoint.NewFromFloat(500.0), s.FXPosition)
+}
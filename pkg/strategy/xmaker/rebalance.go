@@ -0,0 +1,300 @@
+package xmaker
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/bbgo"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+	"github.com/c9s/bbgo/pkg/util"
+)
+
+// RebalanceMode defines how the balance imbalance between the maker session and the source session is restored
+type RebalanceMode string
+
+const (
+	// RebalanceModeTransfer withdraws the asset from the session that holds too much of it to the other session
+	RebalanceModeTransfer RebalanceMode = "transfer"
+
+	// RebalanceModeQuote adjusts the maker bid/ask quantity, so that the fills move the balances back
+	RebalanceModeQuote RebalanceMode = "quote"
+)
+
+// UnmarshalJSON validates the rebalance mode when the config is loaded
+func (m *RebalanceMode) UnmarshalJSON(data []byte) error {
+	mode, err := unmarshalEnum(data, "rebalance mode", RebalanceModeTransfer, RebalanceModeQuote)
+	if err != nil {
+		return err
+	}
+
+	*m = mode
+	return nil
+}
+
+// RebalanceAddress is the deposit address of the asset on the exchange session
+type RebalanceAddress struct {
+	Address    string `json:"address"`
+	AddressTag string `json:"addressTag"`
+	Network    string `json:"network"`
+}
+
+// BalanceRebalance configures the balance rebalancing between the maker session and the primary source session.
+//
+// The maker fills and the hedges move the base asset to one venue and the quote asset to the other,
+// the rebalancing is triggered when the ratio of an asset held on one venue exceeds the threshold.
+type BalanceRebalance struct {
+	Enabled bool `json:"enabled"`
+
+	Mode RebalanceMode `json:"mode"`
+
+	// Interval is the interval of checking the balances
+	Interval types.Duration `json:"interval"`
+
+	// Threshold is the ratio of the asset held on one venue for triggering the rebalancing, e.g. 0.8
+	Threshold fixedpoint.Value `json:"threshold"`
+
+	// TargetRatio is the ratio of the asset on the maker session after the transfer, defaults to 0.5
+	TargetRatio fixedpoint.Value `json:"targetRatio"`
+
+	// QuantityAdjustment is the ratio of adjusting the maker quantity in the quote mode, defaults to 0.5,
+	// the quantity of the side that restores the balances is increased, and the other side is decreased.
+	QuantityAdjustment fixedpoint.Value `json:"quantityAdjustment"`
+
+	// TransferCooldown is the minimal duration between two transfers of the same asset
+	TransferCooldown types.Duration `json:"transferCooldown"`
+
+	// Addresses is the deposit addresses by session name and asset, used in the transfer mode
+	Addresses map[string]map[string]RebalanceAddress `json:"addresses"`
+}
+
+func (r *BalanceRebalance) Defaults() {
+	if r.Mode == "" {
+		r.Mode = RebalanceModeQuote
+	}
+
+	if r.Interval == 0 {
+		r.Interval = types.Duration(10 * time.Minute)
+	}
+
+	if r.TargetRatio.IsZero() {
+		r.TargetRatio = fixedpoint.NewFromFloat(0.5)
+	}
+
+	if r.QuantityAdjustment.IsZero() {
+		r.QuantityAdjustment = fixedpoint.NewFromFloat(0.5)
+	}
+
+	if r.TransferCooldown == 0 {
+		r.TransferCooldown = types.Duration(time.Hour)
+	}
+}
+
+func (r *BalanceRebalance) Validate() error {
+	switch r.Mode {
+	case "", RebalanceModeTransfer, RebalanceModeQuote:
+	default:
+		return fmt.Errorf("invalid rebalance mode %q", r.Mode)
+	}
+
+	if r.Threshold.Compare(fixedpoint.NewFromFloat(0.5)) <= 0 || r.Threshold.Compare(fixedpoint.One) >= 0 {
+		return fmt.Errorf("rebalance threshold should be between 0.5 and 1.0, got %v", r.Threshold)
+	}
+
+	return nil
+}
+
+// balanceRebalancer keeps the rebalancing state shared between the balance checker and the quoting loop
+type balanceRebalancer struct {
+	mu sync.Mutex
+
+	// bidScale and askScale are the maker quantity scales in the quote mode
+	bidScale, askScale fixedpoint.Value
+
+	lastTransferTimes map[string]time.Time
+}
+
+func newBalanceRebalancer() *balanceRebalancer {
+	return &balanceRebalancer{
+		bidScale:          fixedpoint.One,
+		askScale:          fixedpoint.One,
+		lastTransferTimes: make(map[string]time.Time),
+	}
+}
+
+func (r *balanceRebalancer) setScales(bidScale, askScale fixedpoint.Value) {
+	r.mu.Lock()
+	r.bidScale, r.askScale = bidScale, askScale
+	r.mu.Unlock()
+}
+
+func (r *balanceRebalancer) scale(side types.SideType) fixedpoint.Value {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if side == types.SideTypeBuy {
+		return r.bidScale
+	}
+
+	return r.askScale
+}
+
+// makerBalanceRatio returns the ratio of the asset total balance held on the maker session
+func makerBalanceRatio(makerBalance, sourceBalance fixedpoint.Value) (fixedpoint.Value, bool) {
+	total := makerBalance.Add(sourceBalance)
+	if total.Sign() <= 0 {
+		return fixedpoint.Zero, false
+	}
+
+	return makerBalance.Div(total), true
+}
+
+// calculateRebalanceScales calculates the maker bid/ask quantity scales from the base asset ratio on the maker session.
+// When the base asset piles up on the maker session, we sell more on the maker session (and buy on the source session),
+// and vice versa.
+func calculateRebalanceScales(makerBaseRatio, threshold, adjustment fixedpoint.Value) (bidScale, askScale fixedpoint.Value) {
+	increased := fixedpoint.One.Add(adjustment)
+	decreased := fixedpoint.Max(fixedpoint.Zero, fixedpoint.One.Sub(adjustment))
+
+	switch {
+	case makerBaseRatio.Compare(threshold) >= 0:
+		return decreased, increased
+
+	case makerBaseRatio.Compare(fixedpoint.One.Sub(threshold)) <= 0:
+		return increased, decreased
+	}
+
+	return fixedpoint.One, fixedpoint.One
+}
+
+// rebalanceQuantity applies the rebalancing quantity scale of the side
+func (s *Strategy) rebalanceQuantity(side types.SideType, quantity fixedpoint.Value) fixedpoint.Value {
+	if s.rebalancer == nil {
+		return quantity
+	}
+
+	return quantity.Mul(s.rebalancer.scale(side))
+}
+
+func (s *Strategy) runBalanceRebalance(ctx context.Context) {
+	ticker := time.NewTicker(util.MillisecondsJitter(s.Rebalance.Interval.Duration(), 1000))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-ticker.C:
+			s.checkBalanceRebalance(ctx)
+		}
+	}
+}
+
+func (s *Strategy) checkBalanceRebalance(ctx context.Context) {
+	makerBalances := s.makerSession.GetAccount().Balances()
+	sourceBalances := s.sourceSession.GetAccount().Balances()
+
+	baseCurrency := s.makerMarket.BaseCurrency
+	makerBaseRatio, ok := makerBalanceRatio(makerBalances[baseCurrency].Total(), sourceBalances[baseCurrency].Total())
+	if !ok {
+		return
+	}
+
+	switch s.Rebalance.Mode {
+
+	case RebalanceModeQuote:
+		bidScale, askScale := calculateRebalanceScales(makerBaseRatio, s.Rebalance.Threshold, s.Rebalance.QuantityAdjustment)
+		if bidScale.Compare(fixedpoint.One) != 0 || askScale.Compare(fixedpoint.One) != 0 {
+			s.logger().Infof("%s %s balance ratio on maker session %v exceeds the threshold %v, adjusting bid/ask quantity scale to %v/%v",
+				s.Symbol, baseCurrency, makerBaseRatio.Percentage(), s.Rebalance.Threshold, bidScale, askScale)
+		}
+
+		s.rebalancer.setScales(bidScale, askScale)
+
+	case RebalanceModeTransfer:
+		for _, currency := range []string{s.makerMarket.BaseCurrency, s.makerMarket.QuoteCurrency} {
+			s.transferRebalance(ctx, currency, makerBalances[currency], sourceBalances[currency])
+		}
+	}
+}
+
+// transferRebalance withdraws the asset from the session that holds more than the threshold to the other session
+func (s *Strategy) transferRebalance(ctx context.Context, currency string, makerBalance, sourceBalance types.Balance) {
+	ratio, ok := makerBalanceRatio(makerBalance.Total(), sourceBalance.Total())
+	if !ok {
+		return
+	}
+
+	total := makerBalance.Total().Add(sourceBalance.Total())
+
+	var fromSession, toSession *bbgo.ExchangeSession
+	var amount fixedpoint.Value
+	switch {
+	case ratio.Compare(s.Rebalance.Threshold) >= 0:
+		fromSession, toSession = s.makerSession, s.sourceSession
+		amount = makerBalance.Total().Sub(total.Mul(s.Rebalance.TargetRatio))
+		amount = fixedpoint.Min(amount, makerBalance.Available)
+
+	case ratio.Compare(fixedpoint.One.Sub(s.Rebalance.Threshold)) <= 0:
+		fromSession, toSession = s.sourceSession, s.makerSession
+		amount = sourceBalance.Total().Sub(total.Mul(fixedpoint.One.Sub(s.Rebalance.TargetRatio)))
+		amount = fixedpoint.Min(amount, sourceBalance.Available)
+
+	default:
+		return
+	}
+
+	if amount.Sign() <= 0 {
+		return
+	}
+
+	s.rebalancer.mu.Lock()
+	lastTransferTime := s.rebalancer.lastTransferTimes[currency]
+	s.rebalancer.mu.Unlock()
+
+	if time.Since(lastTransferTime) < s.Rebalance.TransferCooldown.Duration() {
+		s.logger().Infof("%s rebalance transfer is cooling down, last transfer at %s", currency, lastTransferTime)
+		return
+	}
+
+	withdrawalService, ok := fromSession.Exchange.(types.ExchangeWithdrawalService)
+	if !ok {
+		s.logger().Errorf("exchange %s does not support withdrawal, can not rebalance %s", fromSession.ExchangeName, currency)
+		return
+	}
+
+	if !fromSession.Withdrawal {
+		s.logger().Errorf("the withdrawal of session %s is not enabled, can not rebalance %s", fromSession.Name, currency)
+		return
+	}
+
+	address, ok := s.Rebalance.Addresses[toSession.Name][currency]
+	if !ok {
+		s.logger().Errorf("%s deposit address of session %s is not configured, can not rebalance", currency, toSession.Name)
+		return
+	}
+
+	if s.DryRun {
+		s.logger().Infof("[dry-run] %s: rebalancing %s, sending %v %s from %s to %s", s.Symbol, currency, amount, currency, fromSession.Name, toSession.Name)
+		return
+	}
+
+	bbgo.Notify("%s: rebalancing %s, sending %v %s from %s to %s (maker ratio %s)",
+		s.Symbol, currency, amount, currency, fromSession.Name, toSession.Name, ratio.Percentage())
+
+	if err := withdrawalService.Withdraw(ctx, currency, amount, address.Address, &types.WithdrawalOptions{
+		Network:    address.Network,
+		AddressTag: address.AddressTag,
+	}); err != nil {
+		s.logger().WithError(err).Errorf("%s rebalance withdrawal failed", currency)
+		bbgo.Notify("%s: %s rebalance withdrawal failed: %v", s.Symbol, currency, err)
+		return
+	}
+
+	s.rebalancer.mu.Lock()
+	s.rebalancer.lastTransferTimes[currency] = time.Now()
+	s.rebalancer.mu.Unlock()
+}
@@ -0,0 +1,47 @@
+package xmaker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func Test_makerBalanceRatio(t *testing.T) {
+	ratio, ok := makerBalanceRatio(fixedpoint.NewFromFloat(8.0), fixedpoint.NewFromFloat(2.0))
+	assert.True(t, ok)
+	assert.Equal(t, "0.8", ratio.String())
+
+	_, ok = makerBalanceRatio(fixedpoint.Zero, fixedpoint.Zero)
+	assert.False(t, ok)
+}
+
+func Test_calculateRebalanceScales(t *testing.T) {
+	threshold := fixedpoint.NewFromFloat(0.8)
+	adjustment := fixedpoint.NewFromFloat(0.5)
+
+	// base asset piles up on the maker session, sell more on the maker session
+	bidScale, askScale := calculateRebalanceScales(fixedpoint.NewFromFloat(0.9), threshold, adjustment)
+	assert.Equal(t, "0.5", bidScale.String())
+	assert.Equal(t, "1.5", askScale.String())
+
+	// base asset piles up on the source session, buy more on the maker session
+	bidScale, askScale = calculateRebalanceScales(fixedpoint.NewFromFloat(0.1), threshold, adjustment)
+	assert.Equal(t, "1.5", bidScale.String())
+	assert.Equal(t, "0.5", askScale.String())
+
+	bidScale, askScale = calculateRebalanceScales(fixedpoint.NewFromFloat(0.5), threshold, adjustment)
+	assert.Equal(t, "1", bidScale.String())
+	assert.Equal(t, "1", askScale.String())
+}
+
+func Test_balanceRebalancer_scale(t *testing.T) {
+	r := newBalanceRebalancer()
+	assert.Equal(t, fixedpoint.One, r.scale(types.SideTypeBuy))
+
+	r.setScales(fixedpoint.NewFromFloat(0.5), fixedpoint.NewFromFloat(1.5))
+	assert.Equal(t, "0.5", r.scale(types.SideTypeBuy).String())
+	assert.Equal(t, "1.5", r.scale(types.SideTypeSell).String())
+}
@@ -0,0 +1,371 @@
+package xmaker
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/bbgo"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+const (
+	VolatilityRegimeLow    = "low"
+	VolatilityRegimeNormal = "normal"
+	VolatilityRegimeHigh   = "high"
+
+	TrendRegimeUp    = "uptrend"
+	TrendRegimeDown  = "downtrend"
+	TrendRegimeRange = "range"
+)
+
+// RegimeAttribution slices the trading time by the source klines, classifies each slice into
+// the volatility and the trend regimes, and attributes the PnL, the fill ratio and the markouts
+// of the maker fills to the regimes. The slices are recorded into the database (the regime_stats table),
+// accumulated in the profit stats and summarized in the daily report.
+type RegimeAttribution struct {
+	Enabled bool `json:"enabled"`
+
+	// Interval is the kline interval of the time slices, defaults to 1h
+	Interval types.Interval `json:"interval"`
+
+	// Window is the number of the previous slices for the baseline volatility, defaults to 24
+	Window int `json:"window"`
+
+	// HighVolatilityRatio classifies the slice as the high volatility regime
+	// when its range is greater than the ratio of the baseline range, defaults to 1.5
+	HighVolatilityRatio fixedpoint.Value `json:"highVolatilityRatio"`
+
+	// LowVolatilityRatio classifies the slice as the low volatility regime
+	// when its range is less than the ratio of the baseline range, defaults to 0.5
+	LowVolatilityRatio fixedpoint.Value `json:"lowVolatilityRatio"`
+
+	// TrendThreshold is the minimal ratio of the kline body to the kline range of the trending slice, defaults to 0.6
+	TrendThreshold fixedpoint.Value `json:"trendThreshold"`
+
+	// MarkoutDelay is the delay after the fill for measuring the markout, defaults to 10s
+	MarkoutDelay types.Duration `json:"markoutDelay"`
+}
+
+func (a *RegimeAttribution) Defaults() {
+	if a.Interval == "" {
+		a.Interval = types.Interval1h
+	}
+
+	if a.Window == 0 {
+		a.Window = 24
+	}
+
+	if a.HighVolatilityRatio.IsZero() {
+		a.HighVolatilityRatio = fixedpoint.NewFromFloat(1.5)
+	}
+
+	if a.LowVolatilityRatio.IsZero() {
+		a.LowVolatilityRatio = fixedpoint.NewFromFloat(0.5)
+	}
+
+	if a.TrendThreshold.IsZero() {
+		a.TrendThreshold = fixedpoint.NewFromFloat(0.6)
+	}
+
+	if a.MarkoutDelay == 0 {
+		a.MarkoutDelay = types.Duration(10 * time.Second)
+	}
+}
+
+func (a *RegimeAttribution) Validate() error {
+	if a.Window < 0 || a.MarkoutDelay < 0 {
+		return fmt.Errorf("regimeAttribution window and markoutDelay should not be negative")
+	}
+
+	if a.LowVolatilityRatio.Sign() < 0 || a.HighVolatilityRatio.Compare(a.LowVolatilityRatio) <= 0 {
+		return fmt.Errorf("regimeAttribution highVolatilityRatio %v should be greater than lowVolatilityRatio %v",
+			a.HighVolatilityRatio, a.LowVolatilityRatio)
+	}
+
+	if a.TrendThreshold.Sign() <= 0 || a.TrendThreshold.Compare(fixedpoint.One) > 0 {
+		return fmt.Errorf("regimeAttribution trendThreshold should be in the range of (0, 1], got %v", a.TrendThreshold)
+	}
+
+	return nil
+}
+
+// klineRangeRatio is the high-low range of the kline relative to its open price
+func klineRangeRatio(k types.KLine) fixedpoint.Value {
+	if k.Open.Sign() <= 0 {
+		return fixedpoint.Zero
+	}
+
+	return k.High.Sub(k.Low).Div(k.Open)
+}
+
+// classifyVolatilityRegime compares the range ratio of the slice with the average range ratio of the previous slices
+func classifyVolatilityRegime(rangeRatio fixedpoint.Value, baselines []fixedpoint.Value, config *RegimeAttribution) string {
+	if len(baselines) == 0 {
+		return VolatilityRegimeNormal
+	}
+
+	sum := fixedpoint.Zero
+	for _, baseline := range baselines {
+		sum = sum.Add(baseline)
+	}
+
+	baseline := sum.Div(fixedpoint.NewFromInt(int64(len(baselines))))
+	if baseline.IsZero() {
+		return VolatilityRegimeNormal
+	}
+
+	ratio := rangeRatio.Div(baseline)
+	switch {
+	case ratio.Compare(config.HighVolatilityRatio) > 0:
+		return VolatilityRegimeHigh
+	case ratio.Compare(config.LowVolatilityRatio) < 0:
+		return VolatilityRegimeLow
+	}
+
+	return VolatilityRegimeNormal
+}
+
+// classifyTrendRegime classifies the slice as trending when the kline body covers most of the kline range
+func classifyTrendRegime(k types.KLine, threshold fixedpoint.Value) string {
+	priceRange := k.High.Sub(k.Low)
+	if priceRange.Sign() <= 0 {
+		return TrendRegimeRange
+	}
+
+	body := k.Close.Sub(k.Open)
+	if body.Abs().Div(priceRange).Compare(threshold) < 0 {
+		return TrendRegimeRange
+	}
+
+	if body.Sign() > 0 {
+		return TrendRegimeUp
+	}
+
+	return TrendRegimeDown
+}
+
+// regimeSlice accumulates the maker activities of the current time slice
+type regimeSlice struct {
+	numOrders, numFills, numMarkouts int
+
+	quotedVolume, filledVolume fixedpoint.Value
+	profit, netProfit          fixedpoint.Value
+	markoutSum                 fixedpoint.Value
+}
+
+// regimeAttributor attributes the maker activities to the time slices closed by the source klines
+type regimeAttributor struct {
+	mu sync.Mutex
+
+	config *RegimeAttribution
+
+	slice     regimeSlice
+	baselines []fixedpoint.Value
+
+	// markouts measures the markouts of the maker fills, the markouts are attributed to the slice they are measured in
+	markouts *markoutTracker
+
+	// day is the start of the day of the daily report
+	day   time.Time
+	daily map[string]*RegimeProfitStats
+}
+
+func newRegimeAttributor(config *RegimeAttribution) *regimeAttributor {
+	a := &regimeAttributor{
+		config:   config,
+		markouts: newMarkoutTracker(config.MarkoutDelay.Duration(), 1),
+		daily:    make(map[string]*RegimeProfitStats),
+	}
+
+	a.markouts.OnMarkout(func(side types.SideType, markout fixedpoint.Value) {
+		a.mu.Lock()
+		a.slice.numMarkouts++
+		a.slice.markoutSum = a.slice.markoutSum.Add(markout)
+		a.mu.Unlock()
+	})
+
+	return a
+}
+
+func (a *regimeAttributor) AddOrders(orders ...types.Order) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for _, order := range orders {
+		a.slice.numOrders++
+		a.slice.quotedVolume = a.slice.quotedVolume.Add(order.Quantity)
+	}
+}
+
+func (a *regimeAttributor) AddFill(trade types.Trade, now time.Time) {
+	a.markouts.AddFill(trade.Side, trade.Price, now)
+
+	a.mu.Lock()
+	a.slice.numFills++
+	a.slice.filledVolume = a.slice.filledVolume.Add(trade.Quantity)
+	a.mu.Unlock()
+}
+
+func (a *regimeAttributor) AddProfit(profit, netProfit fixedpoint.Value) {
+	a.mu.Lock()
+	a.slice.profit = a.slice.profit.Add(profit)
+	a.slice.netProfit = a.slice.netProfit.Add(netProfit)
+	a.mu.Unlock()
+}
+
+// UpdateMarkouts marks out the pending maker fills with the source mid-price
+func (a *regimeAttributor) UpdateMarkouts(now time.Time, midPrice fixedpoint.Value) {
+	a.markouts.Update(now, midPrice)
+}
+
+// Close classifies the slice of the closed kline and returns its stats,
+// the daily report of the previous day is returned when the slice starts a new day.
+func (a *regimeAttributor) Close(k types.KLine) (stats types.RegimeStats, report *regimeDailyReport) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	rangeRatio := klineRangeRatio(k)
+
+	stats = types.RegimeStats{
+		VolatilityRegime: classifyVolatilityRegime(rangeRatio, a.baselines, a.config),
+		TrendRegime:      classifyTrendRegime(k, a.config.TrendThreshold),
+		StartTime:        k.StartTime,
+		EndTime:          k.EndTime,
+		NumOrders:        a.slice.numOrders,
+		NumFills:         a.slice.numFills,
+		QuotedVolume:     a.slice.quotedVolume,
+		FilledVolume:     a.slice.filledVolume,
+		Profit:           a.slice.profit,
+		NetProfit:        a.slice.netProfit,
+		NumMarkouts:      a.slice.numMarkouts,
+	}
+
+	if stats.QuotedVolume.Sign() > 0 {
+		stats.FillRatio = stats.FilledVolume.Div(stats.QuotedVolume)
+	}
+
+	if stats.NumMarkouts > 0 {
+		stats.AverageMarkout = a.slice.markoutSum.Div(fixedpoint.NewFromInt(int64(stats.NumMarkouts)))
+	}
+
+	a.slice = regimeSlice{}
+
+	a.baselines = append(a.baselines, rangeRatio)
+	if len(a.baselines) > a.config.Window {
+		a.baselines = a.baselines[len(a.baselines)-a.config.Window:]
+	}
+
+	day := k.StartTime.Time().UTC().Truncate(24 * time.Hour)
+	if !a.day.IsZero() && day.After(a.day) && len(a.daily) > 0 {
+		report = &regimeDailyReport{day: a.day, regimes: a.daily}
+		a.daily = make(map[string]*RegimeProfitStats)
+	}
+
+	a.day = day
+
+	regimeStats, ok := a.daily[stats.Regime()]
+	if !ok {
+		regimeStats = &RegimeProfitStats{}
+		a.daily[stats.Regime()] = regimeStats
+	}
+	regimeStats.Add(stats)
+
+	return stats, report
+}
+
+// RegimeProfitStats is the aggregated performance of the time slices of a regime
+type RegimeProfitStats struct {
+	NumSlices   int `json:"numSlices"`
+	NumOrders   int `json:"numOrders"`
+	NumFills    int `json:"numFills"`
+	NumMarkouts int `json:"numMarkouts"`
+
+	QuotedVolume fixedpoint.Value `json:"quotedVolume,omitempty"`
+	FilledVolume fixedpoint.Value `json:"filledVolume,omitempty"`
+	Profit       fixedpoint.Value `json:"profit,omitempty"`
+	NetProfit    fixedpoint.Value `json:"netProfit,omitempty"`
+	MarkoutSum   fixedpoint.Value `json:"markoutSum,omitempty"`
+}
+
+func (s *RegimeProfitStats) Add(stats types.RegimeStats) {
+	s.NumSlices++
+	s.NumOrders += stats.NumOrders
+	s.NumFills += stats.NumFills
+	s.NumMarkouts += stats.NumMarkouts
+	s.QuotedVolume = s.QuotedVolume.Add(stats.QuotedVolume)
+	s.FilledVolume = s.FilledVolume.Add(stats.FilledVolume)
+	s.Profit = s.Profit.Add(stats.Profit)
+	s.NetProfit = s.NetProfit.Add(stats.NetProfit)
+	s.MarkoutSum = s.MarkoutSum.Add(stats.AverageMarkout.Mul(fixedpoint.NewFromInt(int64(stats.NumMarkouts))))
+}
+
+func (s *RegimeProfitStats) FillRatio() fixedpoint.Value {
+	if s.QuotedVolume.Sign() <= 0 {
+		return fixedpoint.Zero
+	}
+
+	return s.FilledVolume.Div(s.QuotedVolume)
+}
+
+func (s *RegimeProfitStats) AverageMarkout() fixedpoint.Value {
+	if s.NumMarkouts == 0 {
+		return fixedpoint.Zero
+	}
+
+	return s.MarkoutSum.Div(fixedpoint.NewFromInt(int64(s.NumMarkouts)))
+}
+
+// regimeDailyReport is the daily summary of the performance by the regimes
+type regimeDailyReport struct {
+	symbol  string
+	day     time.Time
+	regimes map[string]*RegimeProfitStats
+}
+
+func (r *regimeDailyReport) PlainText() string {
+	var regimes []string
+	for regime := range r.regimes {
+		regimes = append(regimes, regime)
+	}
+	sort.Strings(regimes)
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%s: %s regime attribution of %s\n", ID, r.symbol, r.day.Format(time.DateOnly)))
+	for _, regime := range regimes {
+		stats := r.regimes[regime]
+		sb.WriteString(fmt.Sprintf("- %s: %d hours, pnl %v, net pnl %v, fills %d, fill ratio %.2f%%, avg markout %.4f%%\n",
+			regime,
+			stats.NumSlices,
+			stats.Profit,
+			stats.NetProfit,
+			stats.NumFills,
+			stats.FillRatio().Float64()*100.0,
+			stats.AverageMarkout().Float64()*100.0))
+	}
+
+	return sb.String()
+}
+
+// closeRegimeSlice attributes the closed slice to its regime, records it and notifies the daily report
+func (s *Strategy) closeRegimeSlice(k types.KLine) {
+	stats, report := s.regimeAttributor.Close(k)
+	stats.Strategy = ID
+	stats.StrategyInstanceID = s.InstanceID()
+	stats.Exchange = s.makerSession.ExchangeName
+	stats.Symbol = s.Symbol
+
+	s.logger().Infof("%s regime %s slice %s: pnl %v, fills %d, fill ratio %v, avg markout %v",
+		s.Symbol, stats.Regime(), stats.StartTime.Time(), stats.Profit, stats.NumFills, stats.FillRatio, stats.AverageMarkout)
+
+	s.ProfitStats.AddRegimeStats(stats)
+	s.Environment.RecordRegimeStats(stats)
+
+	if report != nil {
+		report.symbol = s.Symbol
+		bbgo.Notify(report)
+	}
+}
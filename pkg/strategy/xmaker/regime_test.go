@@ -0,0 +1,124 @@
+package xmaker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func regimeKLine(startTime time.Time, open, high, low, close float64) types.KLine {
+	return types.KLine{
+		Symbol:    "BTCUSDT",
+		Interval:  types.Interval1h,
+		StartTime: types.Time(startTime),
+		EndTime:   types.Time(startTime.Add(time.Hour - time.Millisecond)),
+		Open:      fixedpoint.NewFromFloat(open),
+		High:      fixedpoint.NewFromFloat(high),
+		Low:       fixedpoint.NewFromFloat(low),
+		Close:     fixedpoint.NewFromFloat(close),
+		Closed:    true,
+	}
+}
+
+func TestClassifyTrendRegime(t *testing.T) {
+	threshold := fixedpoint.NewFromFloat(0.6)
+	now := time.Now()
+	assert.Equal(t, TrendRegimeUp, classifyTrendRegime(regimeKLine(now, 100, 111, 99, 110), threshold))
+	assert.Equal(t, TrendRegimeDown, classifyTrendRegime(regimeKLine(now, 110, 111, 99, 100), threshold))
+	assert.Equal(t, TrendRegimeRange, classifyTrendRegime(regimeKLine(now, 100, 110, 90, 101), threshold))
+	assert.Equal(t, TrendRegimeRange, classifyTrendRegime(regimeKLine(now, 100, 100, 100, 100), threshold))
+}
+
+func TestClassifyVolatilityRegime(t *testing.T) {
+	config := &RegimeAttribution{}
+	config.Defaults()
+
+	baselines := []fixedpoint.Value{fixedpoint.NewFromFloat(0.01), fixedpoint.NewFromFloat(0.01)}
+	assert.Equal(t, VolatilityRegimeNormal, classifyVolatilityRegime(fixedpoint.NewFromFloat(0.02), nil, config))
+	assert.Equal(t, VolatilityRegimeHigh, classifyVolatilityRegime(fixedpoint.NewFromFloat(0.02), baselines, config))
+	assert.Equal(t, VolatilityRegimeLow, classifyVolatilityRegime(fixedpoint.NewFromFloat(0.004), baselines, config))
+	assert.Equal(t, VolatilityRegimeNormal, classifyVolatilityRegime(fixedpoint.NewFromFloat(0.01), baselines, config))
+}
+
+func TestRegimeAttributor(t *testing.T) {
+	config := &RegimeAttribution{Enabled: true}
+	config.Defaults()
+	assert.NoError(t, config.Validate())
+
+	a := newRegimeAttributor(config)
+
+	day := time.Date(2024, 1, 1, 22, 0, 0, 0, time.UTC)
+	a.AddOrders(
+		types.Order{SubmitOrder: types.SubmitOrder{Side: types.SideTypeBuy, Quantity: fixedpoint.NewFromFloat(1.0)}},
+		types.Order{SubmitOrder: types.SubmitOrder{Side: types.SideTypeSell, Quantity: fixedpoint.NewFromFloat(1.0)}},
+	)
+	a.AddFill(types.Trade{Side: types.SideTypeBuy, Price: fixedpoint.NewFromFloat(100.0), Quantity: fixedpoint.NewFromFloat(0.5)}, day)
+	a.AddProfit(fixedpoint.NewFromFloat(2.0), fixedpoint.NewFromFloat(1.5))
+
+	// the price goes up after the maker buy
+	a.UpdateMarkouts(day.Add(time.Second), fixedpoint.NewFromFloat(102.0))
+	a.UpdateMarkouts(day.Add(config.MarkoutDelay.Duration()), fixedpoint.NewFromFloat(101.0))
+
+	stats, report := a.Close(regimeKLine(day, 100, 101, 99, 100.1))
+	assert.Nil(t, report)
+	assert.Equal(t, VolatilityRegimeNormal, stats.VolatilityRegime)
+	assert.Equal(t, TrendRegimeRange, stats.TrendRegime)
+	assert.Equal(t, 2, stats.NumOrders)
+	assert.Equal(t, 1, stats.NumFills)
+	assert.Equal(t, fixedpoint.NewFromFloat(0.25), stats.FillRatio)
+	assert.Equal(t, fixedpoint.NewFromFloat(2.0), stats.Profit)
+	assert.Equal(t, 1, stats.NumMarkouts)
+	assert.Equal(t, fixedpoint.NewFromFloat(0.01), stats.AverageMarkout)
+
+	// the slice is reset after it's closed
+	stats, report = a.Close(regimeKLine(day.Add(time.Hour), 100, 110, 99, 109))
+	assert.Nil(t, report)
+	assert.Equal(t, "high-uptrend", stats.Regime())
+	assert.Equal(t, 0, stats.NumFills)
+	assert.True(t, stats.FillRatio.IsZero())
+
+	// the new day starts
+	stats, report = a.Close(regimeKLine(day.Add(2*time.Hour), 100, 100.5, 99.5, 100))
+	if assert.NotNil(t, report) {
+		assert.Len(t, report.regimes, 2)
+		assert.Equal(t, fixedpoint.NewFromFloat(2.0), report.regimes["normal-range"].Profit)
+		assert.Equal(t, fixedpoint.NewFromFloat(0.01), report.regimes["normal-range"].AverageMarkout())
+		assert.Contains(t, report.PlainText(), "normal-range: 1 hours")
+	}
+
+	assert.Equal(t, VolatilityRegimeLow, stats.VolatilityRegime)
+}
+
+func TestProfitStats_AddRegimeStats(t *testing.T) {
+	stats := &ProfitStats{}
+	stats.AddRegimeStats(types.RegimeStats{
+		VolatilityRegime: VolatilityRegimeHigh,
+		TrendRegime:      TrendRegimeUp,
+		QuotedVolume:     fixedpoint.NewFromFloat(4.0),
+		FilledVolume:     fixedpoint.NewFromFloat(1.0),
+		Profit:           fixedpoint.NewFromFloat(1.0),
+		NumMarkouts:      2,
+		AverageMarkout:   fixedpoint.NewFromFloat(-0.001),
+	})
+	stats.AddRegimeStats(types.RegimeStats{
+		VolatilityRegime: VolatilityRegimeHigh,
+		TrendRegime:      TrendRegimeUp,
+		QuotedVolume:     fixedpoint.NewFromFloat(4.0),
+		FilledVolume:     fixedpoint.NewFromFloat(3.0),
+		Profit:           fixedpoint.NewFromFloat(-3.0),
+		NumMarkouts:      2,
+		AverageMarkout:   fixedpoint.NewFromFloat(0.002),
+	})
+
+	regime := stats.Regimes["high-uptrend"]
+	if assert.NotNil(t, regime) {
+		assert.Equal(t, 2, regime.NumSlices)
+		assert.Equal(t, fixedpoint.NewFromFloat(0.5), regime.FillRatio())
+		assert.Equal(t, fixedpoint.NewFromFloat(-2.0), regime.Profit)
+		assert.Equal(t, fixedpoint.NewFromFloat(0.0005), regime.AverageMarkout())
+	}
+}
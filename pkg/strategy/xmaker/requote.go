@@ -0,0 +1,93 @@
+package xmaker
+
+import (
+	"context"
+
+	"github.com/c9s/bbgo/pkg/bbgo"
+	"github.com/c9s/bbgo/pkg/quoting"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// RequoteLayerOrder defines the order of refreshing the layers in the diff requote mode
+type RequoteLayerOrder string
+
+const (
+	// RequoteLayerOrderDeepestFirst refreshes the deepest layers first and the touch layer last
+	RequoteLayerOrderDeepestFirst RequoteLayerOrder = "deepestFirst"
+
+	// RequoteLayerOrderTouchFirst refreshes the touch layer first and the deepest layers last
+	RequoteLayerOrderTouchFirst RequoteLayerOrder = "touchFirst"
+)
+
+// requote applies the difference between the active maker orders and the desired orders,
+// the order-amend API is used when the maker exchange supports it, otherwise the orders are canceled and replaced.
+func (s *Strategy) requote(ctx context.Context, orderExecutionRouter bbgo.OrderExecutionRouter, submitOrders []types.SubmitOrder) {
+	diff := quoting.DiffOrders(s.activeMakerOrders.Orders(), submitOrders, s.RequotePriceTolerance, s.RequoteQuantityTolerance)
+
+	cancelOrders := diff.Cancel
+	newOrders := diff.Submit
+
+	// refresh the layers in the configured order, by default, the deepest layers are refreshed first
+	// and the touch layer is refreshed last, so that we keep the queue priority of the touch layer as long as possible.
+	touchFirst := s.RequoteLayerOrder == RequoteLayerOrderTouchFirst
+	amendments := quoting.SortByLayer(diff.Amend, quoting.AmendmentLayerKey, touchFirst)
+
+	// the amended orders are quote refreshes, they yield to the hedge orders in the order rate budget
+	quoteCtx := bbgo.WithOrderPriority(ctx, bbgo.OrderPriorityLow)
+
+	_, hasAmend := s.makerSession.Exchange.(types.ExchangeOrderAmendService)
+	for _, amendment := range amendments {
+		if !hasAmend {
+			cancelOrders = append(cancelOrders, amendment.Order)
+			newOrders = append(newOrders, amendment.Submit)
+			continue
+		}
+
+		// the orders updated by the user data stream may not carry the market
+		amendment.Order.Market = s.makerMarket
+
+		amendedOrder, err := s.makerSession.AmendOrder(quoteCtx, amendment.Order, amendment.Submit.Price, amendment.Submit.Quantity)
+		if err != nil {
+			s.logger().WithError(err).Errorf("%s order amend error, falling back to cancel/replace: %s", s.Symbol, amendment.Order.String())
+			cancelOrders = append(cancelOrders, amendment.Order)
+			newOrders = append(newOrders, amendment.Submit)
+			continue
+		}
+
+		if amendedOrder != nil {
+			s.activeMakerOrders.Update(*amendedOrder)
+			s.orderStore.Add(*amendedOrder)
+
+			if s.layerTracker != nil {
+				s.layerTracker.Add(*amendedOrder)
+			}
+		}
+	}
+
+	s.logger().Infof("%s requote: keep %d, amend %d, cancel %d, submit %d orders",
+		s.Symbol, len(diff.Keep), len(diff.Amend), len(diff.Cancel), len(diff.Submit))
+
+	if len(cancelOrders) > 0 {
+		cancelOrders = quoting.SortByLayer(cancelOrders, quoting.OrderLayerKey, touchFirst)
+
+		if err := s.activeMakerOrders.GracefulCancel(ctx, s.makerSession.Exchange, cancelOrders...); err != nil {
+			s.logger().WithError(err).Warnf("there are some %s orders not canceled, skipping placing the new maker orders", s.Symbol)
+			return
+		}
+	}
+
+	if len(newOrders) == 0 {
+		return
+	}
+
+	// always place the touch layer first to minimize the time without a top-of-book quote
+	newOrders = quoting.SortByLayer(newOrders, quoting.SubmitOrderLayerKey, true)
+
+	makerOrders, err := s.submitMakerOrders(ctx, orderExecutionRouter, newOrders)
+	if err != nil {
+		s.logger().WithError(err).Errorf("order error: %s", err.Error())
+	}
+
+	// the orders created before the error still need to be tracked
+	s.trackMakerOrders(makerOrders...)
+}
@@ -0,0 +1,66 @@
+package xmaker
+
+import (
+	"runtime"
+	"sync"
+
+	"github.com/c9s/bbgo/pkg/util"
+)
+
+// defaultQuoteScheduler is shared by all the xmaker instances in the same process,
+// the parallelism can be configured by the env var XMAKER_QUOTE_CONCURRENCY.
+var defaultQuoteScheduler = newQuoteScheduler(defaultQuoteConcurrency())
+
+func defaultQuoteConcurrency() int {
+	if n, ok := util.GetEnvVarInt("XMAKER_QUOTE_CONCURRENCY"); ok && n > 0 {
+		return n
+	}
+
+	return runtime.NumCPU()
+}
+
+// quoteScheduler bounds the number of the concurrent quote computations (book copy and quote plans)
+// of the independent symbols, and serializes the order submissions per session to respect the rate limits.
+type quoteScheduler struct {
+	computeSlots chan struct{}
+
+	mu           sync.Mutex
+	sessionLocks map[string]*sync.Mutex
+}
+
+func newQuoteScheduler(concurrency int) *quoteScheduler {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	return &quoteScheduler{
+		computeSlots: make(chan struct{}, concurrency),
+		sessionLocks: make(map[string]*sync.Mutex),
+	}
+}
+
+// Compute runs the quote computation with a bounded parallelism
+func (q *quoteScheduler) Compute(f func()) {
+	q.computeSlots <- struct{}{}
+	defer func() {
+		<-q.computeSlots
+	}()
+
+	f()
+}
+
+// Submit runs the order submission exclusively for the given session
+func (q *quoteScheduler) Submit(session string, f func()) {
+	q.mu.Lock()
+	lock, ok := q.sessionLocks[session]
+	if !ok {
+		lock = &sync.Mutex{}
+		q.sessionLocks[session] = lock
+	}
+	q.mu.Unlock()
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	f()
+}
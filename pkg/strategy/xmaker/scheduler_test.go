@@ -0,0 +1,56 @@
+package xmaker
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuoteScheduler(t *testing.T) {
+	scheduler := newQuoteScheduler(2)
+
+	var running, maxRunning int32
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			scheduler.Compute(func() {
+				n := atomic.AddInt32(&running, 1)
+				for {
+					m := atomic.LoadInt32(&maxRunning)
+					if n <= m || atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+						break
+					}
+				}
+
+				time.Sleep(5 * time.Millisecond)
+				atomic.AddInt32(&running, -1)
+			})
+		}()
+	}
+	wg.Wait()
+	assert.LessOrEqual(t, maxRunning, int32(2))
+
+	var submitting, maxSubmitting int32
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			scheduler.Submit("max", func() {
+				n := atomic.AddInt32(&submitting, 1)
+				if n > atomic.LoadInt32(&maxSubmitting) {
+					atomic.StoreInt32(&maxSubmitting, n)
+				}
+
+				time.Sleep(time.Millisecond)
+				atomic.AddInt32(&submitting, -1)
+			})
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), maxSubmitting)
+}
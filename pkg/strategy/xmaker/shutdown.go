@@ -0,0 +1,216 @@
+package xmaker
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/c9s/bbgo/pkg/bbgo"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+type ShutdownCloseOrderType string
+
+const (
+	// ShutdownCloseOrderTypeMarket closes the position with the market orders
+	ShutdownCloseOrderTypeMarket ShutdownCloseOrderType = "market"
+
+	// ShutdownCloseOrderTypeAggressiveLimit closes the position with the IOC limit orders
+	// priced through the best price by the slippage ratio
+	ShutdownCloseOrderTypeAggressiveLimit ShutdownCloseOrderType = "aggressiveLimit"
+)
+
+// UnmarshalJSON validates the shutdown close order type when the config is loaded
+func (t *ShutdownCloseOrderType) UnmarshalJSON(data []byte) error {
+	orderType, err := unmarshalEnum(data, "shutdown close order type",
+		ShutdownCloseOrderTypeMarket, ShutdownCloseOrderTypeAggressiveLimit)
+	if err != nil {
+		return err
+	}
+
+	*t = orderType
+	return nil
+}
+
+// ShutdownPositionClose closes the position when the strategy is shut down.
+// The uncovered position is hedged on the source session, and when Unwind is enabled,
+// the maker leg and the hedge legs are all closed, so that no inventory is left on any venue.
+type ShutdownPositionClose struct {
+	Enabled bool `json:"enabled"`
+
+	// Unwind closes the full position, the maker leg on the maker session and the hedge legs on the source sessions,
+	// instead of only hedging the uncovered position
+	Unwind bool `json:"unwind"`
+
+	// OrderType is the order type of the unwind orders, valid values are "market" and "aggressiveLimit", defaults to "market"
+	OrderType ShutdownCloseOrderType `json:"orderType,omitempty"`
+
+	// Slippage is the ratio that the aggressive limit price crosses the best price, defaults to 0.002 (0.2%)
+	Slippage fixedpoint.Value `json:"slippage"`
+}
+
+func (c *ShutdownPositionClose) Defaults() {
+	if c.OrderType == "" {
+		c.OrderType = ShutdownCloseOrderTypeMarket
+	}
+
+	if c.Slippage.IsZero() {
+		c.Slippage = fixedpoint.NewFromFloat(0.002)
+	}
+}
+
+func (c *ShutdownPositionClose) Validate() error {
+	switch c.OrderType {
+	case "", ShutdownCloseOrderTypeMarket, ShutdownCloseOrderTypeAggressiveLimit:
+	default:
+		return fmt.Errorf("invalid closePositionOnShutdown orderType %q", c.OrderType)
+	}
+
+	if c.Slippage.Sign() < 0 {
+		return fmt.Errorf("closePositionOnShutdown slippage should not be negative, got %v", c.Slippage)
+	}
+
+	return nil
+}
+
+// aggressiveLimitPrice prices the closing order through the reference price by the slippage ratio
+func aggressiveLimitPrice(side types.SideType, referencePrice, slippage fixedpoint.Value) fixedpoint.Value {
+	if side == types.SideTypeBuy {
+		return referencePrice.Mul(fixedpoint.One.Add(slippage))
+	}
+
+	return referencePrice.Mul(fixedpoint.One.Sub(slippage))
+}
+
+// closingSide returns the side and the quantity that close the given position
+func closingSide(position fixedpoint.Value) (types.SideType, fixedpoint.Value) {
+	if position.Sign() > 0 {
+		return types.SideTypeSell, position
+	}
+
+	return types.SideTypeBuy, position.Abs()
+}
+
+// closePositionOnShutdown hedges the uncovered position, or unwinds the full position, before the shutdown completes
+func (s *Strategy) closePositionOnShutdown(ctx context.Context) {
+	s.tradeCollector.Process()
+
+	if !s.ClosePositionOnShutdown.Unwind {
+		uncoverPosition := s.Position.GetBase().Sub(s.CoveredPosition)
+		if uncoverPosition.Abs().Compare(s.sourceMarket.MinQuantity) <= 0 {
+			return
+		}
+
+		s.logger().Infof("%s hedging the uncovered position %v on shutdown", s.Symbol, uncoverPosition)
+		bbgo.Notify("%s: hedging the %s uncovered position %v on shutdown", ID, s.Symbol, uncoverPosition)
+		s.Hedge(ctx, uncoverPosition.Neg())
+		return
+	}
+
+	// the maker leg is the position that is not offset by the hedge legs of the source sessions
+	makerLeg := s.Position.GetBase()
+	venueLegs := make(map[string]fixedpoint.Value)
+
+	s.venueCoveredPositionsMu.Lock()
+	for source, position := range s.VenueCoveredPositions {
+		venueLegs[source] = position
+		makerLeg = makerLeg.Sub(position)
+	}
+	s.venueCoveredPositionsMu.Unlock()
+
+	s.logger().Infof("%s unwinding the full position on shutdown, maker leg %v, hedge legs %v", s.Symbol, makerLeg, venueLegs)
+	bbgo.Notify("%s: unwinding the %s full position on shutdown, maker leg %v, hedge legs %v", ID, s.Symbol, makerLeg, venueLegs)
+
+	if makerLeg.Abs().Compare(s.makerMarket.MinQuantity) >= 0 {
+		side, quantity := closingSide(makerLeg)
+
+		referencePrice := s.lastPrice
+		if s.makerBook != nil {
+			if bestBid, bestAsk, ok := s.makerBook.BestBidAndAsk(); ok {
+				if side == types.SideTypeBuy {
+					referencePrice = bestAsk.Price
+				} else {
+					referencePrice = bestBid.Price
+				}
+			}
+		}
+
+		if _, err := s.submitCloseOrder(ctx, s.makerSession, s.makerMarket, side, quantity, referencePrice); err != nil {
+			s.logger().WithError(err).Errorf("%s unable to close the maker leg %v", s.Symbol, makerLeg)
+		}
+	}
+
+	for source, position := range venueLegs {
+		sourceMarket := s.sourceMarkets[source]
+		if position.Abs().Compare(sourceMarket.MinQuantity) < 0 {
+			continue
+		}
+
+		side, quantity := closingSide(position)
+
+		referencePrice := s.lastPrice
+		sourceBook := s.book.CopyDepthOf(1, source)
+		if side == types.SideTypeBuy {
+			if bestAsk, ok := sourceBook.BestAsk(); ok {
+				referencePrice = bestAsk.Price
+			}
+		} else if bestBid, ok := sourceBook.BestBid(); ok {
+			referencePrice = bestBid.Price
+		}
+
+		submitted, err := s.submitCloseOrder(ctx, s.sourceSessions[source], sourceMarket, side, quantity, referencePrice)
+		if err != nil {
+			s.logger().WithError(err).Errorf("%s unable to close the hedge leg %v on %s", s.Symbol, position, source)
+			continue
+		}
+
+		// offset the covered position the same way as the hedge orders, the trades of the order offset it back
+		if side == types.SideTypeSell {
+			s.CoveredPosition = s.CoveredPosition.Add(submitted)
+		} else {
+			s.CoveredPosition = s.CoveredPosition.Sub(submitted)
+		}
+	}
+}
+
+// submitCloseOrder submits the closing order with the configured order type and returns the submitted quantity
+func (s *Strategy) submitCloseOrder(
+	ctx context.Context, session *bbgo.ExchangeSession, market types.Market, side types.SideType,
+	quantity, referencePrice fixedpoint.Value,
+) (fixedpoint.Value, error) {
+	quantity = market.TruncateQuantity(quantity)
+	if quantity.Compare(market.MinQuantity) < 0 {
+		return fixedpoint.Zero, nil
+	}
+
+	submitOrder := types.SubmitOrder{
+		Symbol:   market.Symbol,
+		Market:   market,
+		Type:     types.OrderTypeMarket,
+		Side:     side,
+		Quantity: quantity,
+
+		SelfTradePreventionMode: s.SelfTradePreventionMode,
+	}
+
+	if s.ClosePositionOnShutdown.OrderType == ShutdownCloseOrderTypeAggressiveLimit {
+		if referencePrice.Sign() <= 0 {
+			return fixedpoint.Zero, fmt.Errorf("no reference price for the aggressive limit order")
+		}
+
+		submitOrder.Type = types.OrderTypeLimit
+		submitOrder.Price = market.TruncatePrice(aggressiveLimitPrice(side, referencePrice, s.ClosePositionOnShutdown.Slippage))
+		submitOrder.TimeInForce = types.TimeInForceIOC
+	}
+
+	s.logger().Infof("submitting %s closing order %s %v to %s", s.Symbol, side, quantity, session.Name)
+
+	orderExecutor := &bbgo.ExchangeOrderExecutor{Session: session}
+	createdOrders, err := orderExecutor.SubmitOrders(ctx, submitOrder)
+	if err != nil {
+		return fixedpoint.Zero, err
+	}
+
+	s.orderStore.Add(createdOrders...)
+	return quantity, nil
+}
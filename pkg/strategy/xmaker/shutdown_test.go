@@ -0,0 +1,41 @@
+package xmaker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestShutdownPositionClose_Defaults(t *testing.T) {
+	c := &ShutdownPositionClose{Enabled: true}
+	c.Defaults()
+
+	assert.Equal(t, ShutdownCloseOrderTypeMarket, c.OrderType)
+	assert.Equal(t, "0.002", c.Slippage.String())
+	assert.NoError(t, c.Validate())
+
+	c.OrderType = "stop"
+	assert.Error(t, c.Validate())
+}
+
+func TestAggressiveLimitPrice(t *testing.T) {
+	number := fixedpoint.MustNewFromString
+
+	assert.Equal(t, "101", aggressiveLimitPrice(types.SideTypeBuy, number("100"), number("0.01")).String())
+	assert.Equal(t, "99", aggressiveLimitPrice(types.SideTypeSell, number("100"), number("0.01")).String())
+}
+
+func TestClosingSide(t *testing.T) {
+	number := fixedpoint.MustNewFromString
+
+	side, quantity := closingSide(number("1.5"))
+	assert.Equal(t, types.SideTypeSell, side)
+	assert.Equal(t, "1.5", quantity.String())
+
+	side, quantity = closingSide(number("-0.5"))
+	assert.Equal(t, types.SideTypeBuy, side)
+	assert.Equal(t, "0.5", quantity.String())
+}
@@ -0,0 +1,25 @@
+package xmaker
+
+import (
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+)
+
+// calculateInventorySkew calculates the inventory skew ratio from the current base position,
+// the ratio is in the range of [-factor, +factor], positive means we are holding too much base asset.
+func calculateInventorySkew(position, maxExposurePosition, factor fixedpoint.Value) fixedpoint.Value {
+	if maxExposurePosition.Sign() <= 0 || factor.IsZero() {
+		return fixedpoint.Zero
+	}
+
+	ratio := position.Div(maxExposurePosition)
+	ratio = fixedpoint.Max(fixedpoint.Min(ratio, fixedpoint.One), fixedpoint.One.Neg())
+	return ratio.Mul(factor)
+}
+
+// applyInventorySkew shifts the bid/ask margins by the skew ratio, so that the quotes lean toward reducing the inventory.
+// When we are long, the bid margin is increased and the ask margin is decreased, and vice versa.
+func applyInventorySkew(bidMargin, askMargin, skew fixedpoint.Value) (fixedpoint.Value, fixedpoint.Value) {
+	bidMargin = fixedpoint.Max(bidMargin.Mul(fixedpoint.One.Add(skew)), fixedpoint.Zero)
+	askMargin = fixedpoint.Max(askMargin.Mul(fixedpoint.One.Sub(skew)), fixedpoint.Zero)
+	return bidMargin, askMargin
+}
@@ -0,0 +1,34 @@
+package xmaker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+)
+
+func Test_calculateInventorySkew(t *testing.T) {
+	maxExposure := fixedpoint.NewFromFloat(10.0)
+	factor := fixedpoint.NewFromFloat(0.5)
+
+	assert.Equal(t, fixedpoint.NewFromFloat(0.25), calculateInventorySkew(fixedpoint.NewFromFloat(5.0), maxExposure, factor))
+	assert.Equal(t, fixedpoint.NewFromFloat(-0.25), calculateInventorySkew(fixedpoint.NewFromFloat(-5.0), maxExposure, factor))
+
+	// should be capped by the max exposure
+	assert.Equal(t, fixedpoint.NewFromFloat(0.5), calculateInventorySkew(fixedpoint.NewFromFloat(20.0), maxExposure, factor))
+
+	assert.Equal(t, fixedpoint.Zero, calculateInventorySkew(fixedpoint.NewFromFloat(5.0), fixedpoint.Zero, factor))
+}
+
+func Test_applyInventorySkew(t *testing.T) {
+	margin := fixedpoint.NewFromFloat(0.002)
+
+	bidMargin, askMargin := applyInventorySkew(margin, margin, fixedpoint.NewFromFloat(0.5))
+	assert.Equal(t, fixedpoint.NewFromFloat(0.003), bidMargin)
+	assert.Equal(t, fixedpoint.NewFromFloat(0.001), askMargin)
+
+	bidMargin, askMargin = applyInventorySkew(margin, margin, fixedpoint.NewFromFloat(-1.0))
+	assert.Equal(t, fixedpoint.Zero, bidMargin)
+	assert.Equal(t, fixedpoint.NewFromFloat(0.004), askMargin)
+}
@@ -0,0 +1,296 @@
+package xmaker
+
+import (
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/c9s/bbgo/pkg/bbgo"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/timerwheel"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// HedgeSourcePolicy defines how we select the source session for sending the hedge order
+type HedgeSourcePolicy string
+
+const (
+	// HedgeSourcePolicyPrimary hedges on the first source session that is still updating
+	HedgeSourcePolicyPrimary HedgeSourcePolicy = "primary"
+
+	// HedgeSourcePolicyBestPrice hedges on the source session that has the best price for the hedge side
+	HedgeSourcePolicyBestPrice HedgeSourcePolicy = "bestPrice"
+
+	// HedgeSourcePolicySmartRouting splits the hedge quantity across the source sessions by their depth, fees and balances
+	HedgeSourcePolicySmartRouting HedgeSourcePolicy = "smartRouting"
+)
+
+// UnmarshalJSON validates the hedge source policy when the config is loaded, the empty policy is the primary policy
+func (p *HedgeSourcePolicy) UnmarshalJSON(data []byte) error {
+	policy, err := unmarshalEnum(data, "hedge source policy",
+		HedgeSourcePolicyPrimary, HedgeSourcePolicyBestPrice, HedgeSourcePolicySmartRouting)
+	if err != nil {
+		return err
+	}
+
+	*p = policy
+	return nil
+}
+
+// sourceHeartBeat monitors the best bid/ask price updates of one source book
+type sourceHeartBeat struct {
+	bid, ask *types.PriceHeartBeat
+}
+
+func newSourceHeartBeat() *sourceHeartBeat {
+	return &sourceHeartBeat{
+		bid: types.NewPriceHeartBeat(priceUpdateTimeout),
+		ask: types.NewPriceHeartBeat(priceUpdateTimeout),
+	}
+}
+
+// watch reports the stalled source prices from the shared timer wheel, so that the stall is logged
+// when it happens even if the quote worker is blocked, and returns the function to stop watching.
+func (h *sourceHeartBeat) watch(logger logrus.FieldLogger, symbol, source string) (stop func()) {
+	wheel := timerwheel.Default()
+	stopBid := h.bid.Watch(wheel, func(last types.PriceVolume, lastUpdatedTime time.Time) {
+		logger.Warnf("%s source %s bid price %v has not been updated since %s", symbol, source, last, lastUpdatedTime)
+	})
+	stopAsk := h.ask.Watch(wheel, func(last types.PriceVolume, lastUpdatedTime time.Time) {
+		logger.Warnf("%s source %s ask price %v has not been updated since %s", symbol, source, last, lastUpdatedTime)
+	})
+
+	return func() {
+		stopBid()
+		stopAsk()
+	}
+}
+
+// sourceExchangeNames returns the configured source session names,
+// the legacy SourceExchange is used when SourceExchanges is not configured.
+func (s *Strategy) sourceExchangeNames() []string {
+	if len(s.SourceExchanges) > 0 {
+		return s.SourceExchanges
+	}
+
+	return []string{s.SourceExchange}
+}
+
+// isSourceExchange checks whether the exchange is one of the source session exchanges
+func (s *Strategy) isSourceExchange(exchangeName types.ExchangeName) bool {
+	for _, session := range s.sourceSessions {
+		if session.ExchangeName == exchangeName {
+			return true
+		}
+	}
+
+	return false
+}
+
+// activeSources updates the price heart beats of the source books and
+// returns the names of the sources that are still updating.
+func (s *Strategy) activeSources() (sources []string) {
+	for _, source := range s.book.Sources() {
+		// the degraded venue is excluded from quoting since it can not be used for hedging
+		if session, ok := s.sourceSessions[source]; ok && session.Degraded() {
+			continue
+		}
+
+		book, _ := s.book.Source(source)
+		bestBid, bestAsk, hasPrice := book.BestBidAndAsk()
+		if !hasPrice {
+			continue
+		}
+
+		heartBeat, ok := s.sourceHeartBeats[source]
+		if !ok {
+			continue
+		}
+
+		if _, err := heartBeat.bid.Update(bestBid); err != nil {
+			s.logger().WithError(err).Warnf("%s source %s bid price not updating, order book last update: %s, excluding it from quoting",
+				s.Symbol, source, book.LastUpdateTime())
+			continue
+		}
+
+		if _, err := heartBeat.ask.Update(bestAsk); err != nil {
+			s.logger().WithError(err).Warnf("%s source %s ask price not updating, order book last update: %s, excluding it from quoting",
+				s.Symbol, source, book.LastUpdateTime())
+			continue
+		}
+
+		sources = append(sources, source)
+	}
+
+	return sources
+}
+
+// selectHedgeSource selects the source session for hedging the given side and quantity by the hedge source policy,
+// only the sources that are still updating are selected, and the primary source is used if none of them is updating.
+// The sources without enough balance for the hedge quantity are skipped unless none of the sources can cover it.
+func (s *Strategy) selectHedgeSource(side types.SideType, quantity fixedpoint.Value) (string, *bbgo.ExchangeSession, types.Market) {
+	selected := s.book.Sources()[0]
+
+	sources := s.activeSources()
+	if quantity.Sign() > 0 {
+		var coveringSources []string
+		for _, source := range sources {
+			if s.canCoverHedge(source, side, quantity) {
+				coveringSources = append(coveringSources, source)
+			}
+		}
+
+		if len(coveringSources) > 0 {
+			sources = coveringSources
+		}
+	}
+
+	if len(sources) > 0 {
+		selected = sources[0]
+	}
+
+	if s.HedgeSourcePolicy == HedgeSourcePolicyBestPrice {
+		var bestPrice fixedpoint.Value
+		for _, source := range sources {
+			book, _ := s.book.Source(source)
+
+			switch side {
+			case types.SideTypeBuy:
+				if bestAsk, ok := book.BestAsk(); ok && (bestPrice.IsZero() || bestAsk.Price.Compare(bestPrice) < 0) {
+					bestPrice = bestAsk.Price
+					selected = source
+				}
+
+			case types.SideTypeSell:
+				if bestBid, ok := book.BestBid(); ok && bestBid.Price.Compare(bestPrice) > 0 {
+					bestPrice = bestBid.Price
+					selected = source
+				}
+			}
+		}
+	}
+
+	return selected, s.sourceSessions[selected], s.sourceMarkets[selected]
+}
+
+// canCoverHedge checks if the available balance of the source session covers the hedge quantity,
+// the source without the balance of the currency, e.g. a futures session, is not limited by the balance.
+func (s *Strategy) canCoverHedge(source string, side types.SideType, quantity fixedpoint.Value) bool {
+	availableBase, availableQuote, hasBase, hasQuote := s.sourceAvailableBalances(source)
+
+	switch side {
+	case types.SideTypeSell:
+		return !hasBase || availableBase.Compare(quantity) >= 0
+
+	case types.SideTypeBuy:
+		if !hasQuote {
+			return true
+		}
+
+		book, ok := s.book.Source(source)
+		if !ok {
+			return false
+		}
+
+		bestAsk, ok := book.BestAsk()
+		if !ok {
+			return false
+		}
+
+		return availableQuote.Compare(quantity.Mul(bestAsk.Price)) >= 0
+	}
+
+	return false
+}
+
+// sourceAvailableBalances returns the available base and quote balances of the source session for hedging,
+// the StopHedgeBaseBalance and the StopHedgeQuoteBalance are excluded from the available balances.
+func (s *Strategy) sourceAvailableBalances(source string) (availableBase, availableQuote fixedpoint.Value, hasBase, hasQuote bool) {
+	session, ok := s.sourceSessions[source]
+	if !ok || session.GetAccount() == nil {
+		return availableBase, availableQuote, false, false
+	}
+
+	market := s.sourceMarkets[source]
+	balances := session.GetAccount().Balances()
+
+	if b, ok := balances[market.BaseCurrency]; ok {
+		hasBase = true
+
+		// to make bid orders, we need enough base asset in the foreign exchange,
+		// if the base asset balance is not enough for selling
+		if s.StopHedgeBaseBalance.Sign() > 0 {
+			minAvailable := s.StopHedgeBaseBalance.Add(market.MinQuantity)
+			if b.Available.Compare(minAvailable) > 0 {
+				availableBase = b.Available.Sub(minAvailable)
+			}
+		} else if b.Available.Compare(market.MinQuantity) > 0 {
+			availableBase = b.Available
+		}
+	}
+
+	if b, ok := balances[market.QuoteCurrency]; ok {
+		hasQuote = true
+
+		// to make ask orders, we need enough quote asset in the foreign exchange,
+		// if the quote asset balance is not enough for buying
+		if s.StopHedgeQuoteBalance.Sign() > 0 {
+			minAvailable := s.StopHedgeQuoteBalance.Add(market.MinNotional)
+			if b.Available.Compare(minAvailable) > 0 {
+				availableQuote = b.Available.Sub(minAvailable)
+			}
+		} else if b.Available.Compare(market.MinNotional) > 0 {
+			availableQuote = b.Available
+		}
+	}
+
+	return availableBase, availableQuote, hasBase, hasQuote
+}
+
+// hedgeQuota calculates the available hedge quota of the source sessions,
+// and returns whether the maker bid or maker ask should be disabled.
+//
+// Since the whole hedge is sent to one source session, the quota is the largest balance of a single source,
+// except for the smart routing that splits the hedge across the source sessions.
+func (s *Strategy) hedgeQuota() (quota *bbgo.QuotaTransaction, disableMakerBid, disableMakerAsk bool) {
+	quota = &bbgo.QuotaTransaction{}
+
+	sources := s.sourceExchangeNames()
+	splitHedge := s.HedgeSourcePolicy == HedgeSourcePolicySmartRouting && len(sources) > 1
+
+	var hasBase, hasQuote bool
+	var availableBase, availableQuote fixedpoint.Value
+	for _, source := range sources {
+		base, quote, sourceHasBase, sourceHasQuote := s.sourceAvailableBalances(source)
+		hasBase = hasBase || sourceHasBase
+		hasQuote = hasQuote || sourceHasQuote
+
+		if splitHedge {
+			availableBase = availableBase.Add(base)
+			availableQuote = availableQuote.Add(quote)
+		} else {
+			availableBase = fixedpoint.Max(availableBase, base)
+			availableQuote = fixedpoint.Max(availableQuote, quote)
+		}
+	}
+
+	if hasBase {
+		if availableBase.Sign() > 0 {
+			quota.BaseAsset.Add(availableBase)
+		} else {
+			s.logger().Warnf("%s maker bid disabled: insufficient base balance on the source sessions", s.Symbol)
+			disableMakerBid = true
+		}
+	}
+
+	if hasQuote {
+		if availableQuote.Sign() > 0 {
+			quota.QuoteAsset.Add(availableQuote)
+		} else {
+			s.logger().Warnf("%s maker ask disabled: insufficient quote balance on the source sessions", s.Symbol)
+			disableMakerAsk = true
+		}
+	}
+
+	return quota, disableMakerBid, disableMakerAsk
+}
@@ -0,0 +1,142 @@
+package xmaker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/bbgo"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func newTestSourceBook(bid, ask float64) *types.StreamOrderBook {
+	book := types.NewStreamBook("BTCUSDT")
+	book.Load(types.SliceOrderBook{
+		Symbol: "BTCUSDT",
+		Bids:   types.PriceVolumeSlice{{Price: fixedpoint.NewFromFloat(bid), Volume: fixedpoint.One}},
+		Asks:   types.PriceVolumeSlice{{Price: fixedpoint.NewFromFloat(ask), Volume: fixedpoint.One}},
+	})
+	return book
+}
+
+func TestStrategy_selectHedgeSource(t *testing.T) {
+	book := types.NewAggregatedStreamOrderBook("BTCUSDT")
+	book.AddSource("binance", newTestSourceBook(30000.0, 30001.0))
+	book.AddSource("okex", newTestSourceBook(29990.0, 29991.0))
+
+	s := &Strategy{
+		Symbol:            "BTCUSDT",
+		HedgeSourcePolicy: HedgeSourcePolicyBestPrice,
+		book:              book,
+		sourceSessions: map[string]*bbgo.ExchangeSession{
+			"binance": {Name: "binance"},
+			"okex":    {Name: "okex"},
+		},
+		sourceHeartBeats: map[string]*sourceHeartBeat{
+			"binance": {bid: types.NewPriceHeartBeat(time.Millisecond), ask: types.NewPriceHeartBeat(time.Millisecond)},
+			"okex":    {bid: types.NewPriceHeartBeat(time.Minute), ask: types.NewPriceHeartBeat(time.Minute)},
+		},
+	}
+
+	selected, _, _ := s.selectHedgeSource(types.SideTypeSell, fixedpoint.Zero)
+	assert.Equal(t, "binance", selected)
+
+	selected, _, _ = s.selectHedgeSource(types.SideTypeBuy, fixedpoint.Zero)
+	assert.Equal(t, "okex", selected)
+
+	// the binance book is frozen with the better bid price, it's excluded from hedging
+	time.Sleep(5 * time.Millisecond)
+	selected, _, _ = s.selectHedgeSource(types.SideTypeSell, fixedpoint.Zero)
+	assert.Equal(t, "okex", selected)
+
+	// the primary source is used when none of the sources is updating
+	s.sourceHeartBeats["okex"] = &sourceHeartBeat{bid: types.NewPriceHeartBeat(time.Millisecond), ask: types.NewPriceHeartBeat(time.Millisecond)}
+	s.activeSources()
+	time.Sleep(5 * time.Millisecond)
+
+	selected, _, _ = s.selectHedgeSource(types.SideTypeBuy, fixedpoint.Zero)
+	assert.Equal(t, "binance", selected)
+}
+
+func newTestBalanceSession(name string, btc, usdt float64) *bbgo.ExchangeSession {
+	account := types.NewAccount()
+	account.UpdateBalances(types.BalanceMap{
+		"BTC":  {Currency: "BTC", Available: fixedpoint.NewFromFloat(btc)},
+		"USDT": {Currency: "USDT", Available: fixedpoint.NewFromFloat(usdt)},
+	})
+	return &bbgo.ExchangeSession{Name: name, Account: account}
+}
+
+func newTestMultiSourceStrategy() *Strategy {
+	book := types.NewAggregatedStreamOrderBook("BTCUSDT")
+	book.AddSource("binance", newTestSourceBook(30000.0, 30001.0))
+	book.AddSource("okex", newTestSourceBook(29990.0, 29991.0))
+
+	market := types.Market{
+		Symbol:        "BTCUSDT",
+		BaseCurrency:  "BTC",
+		QuoteCurrency: "USDT",
+		MinQuantity:   fixedpoint.NewFromFloat(0.001),
+		MinNotional:   fixedpoint.NewFromFloat(10.0),
+	}
+
+	return &Strategy{
+		Symbol:            "BTCUSDT",
+		SourceExchange:    "binance",
+		SourceExchanges:   []string{"binance", "okex"},
+		HedgeSourcePolicy: HedgeSourcePolicyBestPrice,
+		book:              book,
+		sourceSessions: map[string]*bbgo.ExchangeSession{
+			"binance": newTestBalanceSession("binance", 3.0, 30000.0),
+			"okex":    newTestBalanceSession("okex", 1.0, 100000.0),
+		},
+		sourceMarkets: map[string]types.Market{"binance": market, "okex": market},
+		sourceHeartBeats: map[string]*sourceHeartBeat{
+			"binance": {bid: types.NewPriceHeartBeat(time.Minute), ask: types.NewPriceHeartBeat(time.Minute)},
+			"okex":    {bid: types.NewPriceHeartBeat(time.Minute), ask: types.NewPriceHeartBeat(time.Minute)},
+		},
+	}
+}
+
+func TestStrategy_selectHedgeSource_Balance(t *testing.T) {
+	s := newTestMultiSourceStrategy()
+
+	// okex has the best ask and enough quote balance, binance can not buy 2 BTC
+	selected, _, _ := s.selectHedgeSource(types.SideTypeBuy, fixedpoint.NewFromFloat(2.0))
+	assert.Equal(t, "okex", selected)
+
+	// binance has the best bid and enough base balance, okex can not sell 2 BTC
+	selected, _, _ = s.selectHedgeSource(types.SideTypeSell, fixedpoint.NewFromFloat(2.0))
+	assert.Equal(t, "binance", selected)
+
+	// none of the sources can buy 4 BTC, the source of the best price is used
+	selected, _, _ = s.selectHedgeSource(types.SideTypeBuy, fixedpoint.NewFromFloat(4.0))
+	assert.Equal(t, "okex", selected)
+
+	s.sourceSessions["okex"] = newTestBalanceSession("okex", 1.0, 10000.0)
+	selected, _, _ = s.selectHedgeSource(types.SideTypeBuy, fixedpoint.NewFromFloat(0.3))
+	assert.Equal(t, "okex", selected)
+
+	// okex has the best ask, but it can not buy 0.5 BTC with 10000 USDT
+	selected, _, _ = s.selectHedgeSource(types.SideTypeBuy, fixedpoint.NewFromFloat(0.5))
+	assert.Equal(t, "binance", selected)
+}
+
+func TestStrategy_hedgeQuota(t *testing.T) {
+	s := newTestMultiSourceStrategy()
+
+	// the whole hedge is sent to one source, so the quota is the largest balance of a single source
+	quota, disableBid, disableAsk := s.hedgeQuota()
+	assert.False(t, disableBid)
+	assert.False(t, disableAsk)
+	assert.Equal(t, "3", quota.BaseAsset.Available.String())
+	assert.Equal(t, "100000", quota.QuoteAsset.Available.String())
+
+	// the smart routing splits the hedge across the sources
+	s.HedgeSourcePolicy = HedgeSourcePolicySmartRouting
+	quota, _, _ = s.hedgeQuota()
+	assert.Equal(t, "4", quota.BaseAsset.Available.String())
+	assert.Equal(t, "130000", quota.QuoteAsset.Available.String())
+}
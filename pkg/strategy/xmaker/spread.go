@@ -0,0 +1,216 @@
+package xmaker
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/c9s/bbgo/pkg/bbgo"
+	"github.com/c9s/bbgo/pkg/datatype/floats"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/indicator"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// SpreadModelType is the volatility measure of the spread model
+type SpreadModelType string
+
+const (
+	SpreadModelTypeATR         SpreadModelType = "atr"
+	SpreadModelTypeRealizedVol SpreadModelType = "realizedVol"
+)
+
+// UnmarshalJSON validates the spread model type when the config is loaded
+func (t *SpreadModelType) UnmarshalJSON(data []byte) error {
+	modelType, err := unmarshalEnum(data, "spread model type", SpreadModelTypeATR, SpreadModelTypeRealizedVol)
+	if err != nil {
+		return err
+	}
+
+	*t = modelType
+	return nil
+}
+
+// SpreadModel adjusts the bid/ask margins by the market condition
+type SpreadModel interface {
+	Name() string
+
+	// Margins returns the adjusted bid/ask margins, ok is false when the model is not ready
+	Margins(bidMargin, askMargin fixedpoint.Value) (adjustedBidMargin, adjustedAskMargin fixedpoint.Value, ok bool)
+}
+
+// SpreadModelConfig configures the volatility-adaptive spread model,
+// the margins are scaled by the ratio of the current volatility to the baseline volatility.
+type SpreadModelConfig struct {
+	// Type is the model type, valid values are "atr" and "realizedVol"
+	Type SpreadModelType `json:"type"`
+
+	types.IntervalWindow
+
+	// BaselineVolatility is the volatility ratio in a normal market, e.g. 0.002 means 0.2%,
+	// the margins are widened when the volatility is higher than the baseline and tightened when lower.
+	BaselineVolatility fixedpoint.Value `json:"baselineVolatility"`
+
+	// MinScale and MaxScale limit the scale of the margins
+	MinScale fixedpoint.Value `json:"minScale"`
+	MaxScale fixedpoint.Value `json:"maxScale"`
+}
+
+func (c *SpreadModelConfig) Defaults() {
+	if c.Interval == "" {
+		c.Interval = types.Interval1m
+	}
+
+	if c.Window == 0 {
+		c.Window = 14
+	}
+
+	if c.MinScale.IsZero() {
+		c.MinScale = fixedpoint.NewFromFloat(0.5)
+	}
+
+	if c.MaxScale.IsZero() {
+		c.MaxScale = fixedpoint.NewFromFloat(3.0)
+	}
+}
+
+func (c *SpreadModelConfig) Validate() error {
+	switch c.Type {
+	case SpreadModelTypeATR, SpreadModelTypeRealizedVol:
+	default:
+		return fmt.Errorf("invalid spread model type %q", c.Type)
+	}
+
+	if c.BaselineVolatility.Sign() <= 0 {
+		return fmt.Errorf("spread model baselineVolatility should be greater than 0")
+	}
+
+	return nil
+}
+
+// volatilitySeries provides the last volatility ratio
+type volatilitySeries interface {
+	Length() int
+	Last(i int) float64
+}
+
+// volatilitySpreadModel scales the margins by the volatility from the series
+type volatilitySpreadModel struct {
+	name   string
+	config *SpreadModelConfig
+	series volatilitySeries
+
+	// ratio is used to convert the series value into the volatility ratio
+	ratio float64
+}
+
+func (m *volatilitySpreadModel) Name() string {
+	return m.name
+}
+
+func (m *volatilitySpreadModel) Scale() (fixedpoint.Value, bool) {
+	if m.series.Length() == 0 {
+		return fixedpoint.Zero, false
+	}
+
+	volatility := fixedpoint.NewFromFloat(m.series.Last(0) * m.ratio)
+	if volatility.Sign() <= 0 {
+		return fixedpoint.Zero, false
+	}
+
+	scale := volatility.Div(m.config.BaselineVolatility)
+	scale = fixedpoint.Max(scale, m.config.MinScale)
+	scale = fixedpoint.Min(scale, m.config.MaxScale)
+	return scale, true
+}
+
+func (m *volatilitySpreadModel) Margins(bidMargin, askMargin fixedpoint.Value) (fixedpoint.Value, fixedpoint.Value, bool) {
+	scale, ok := m.Scale()
+	if !ok {
+		return bidMargin, askMargin, false
+	}
+
+	return bidMargin.Mul(scale), askMargin.Mul(scale), true
+}
+
+// realizedVolatility is the standard deviation of the log returns of the closed klines
+type realizedVolatility struct {
+	types.IntervalWindow
+
+	prevClose float64
+	returns   floats.Slice
+	values    floats.Slice
+}
+
+func (r *realizedVolatility) Length() int {
+	return r.values.Length()
+}
+
+func (r *realizedVolatility) Last(i int) float64 {
+	return r.values.Last(i)
+}
+
+func (r *realizedVolatility) PushK(k types.KLine) {
+	closePrice := k.Close.Float64()
+	if r.prevClose > 0 && closePrice > 0 {
+		r.returns.Push(math.Log(closePrice / r.prevClose))
+		if r.returns.Length() > r.Window {
+			r.returns = r.returns.Tail(r.Window)
+		}
+	}
+
+	r.prevClose = closePrice
+
+	if r.returns.Length() < 2 {
+		return
+	}
+
+	mean := r.returns.Mean()
+	sum := 0.0
+	for _, v := range r.returns {
+		sum += (v - mean) * (v - mean)
+	}
+
+	r.values.Push(math.Sqrt(sum / float64(r.returns.Length()-1)))
+	if r.values.Length() > r.Window {
+		r.values = r.values.Tail(r.Window)
+	}
+}
+
+// newSpreadModel creates the spread model from the config and binds the indicators to the session
+func newSpreadModel(config *SpreadModelConfig, symbol string, session *bbgo.ExchangeSession) SpreadModel {
+	switch config.Type {
+
+	case SpreadModelTypeATR:
+		atrp := session.StandardIndicatorSet(symbol).ATRP(config.IntervalWindow)
+		return &volatilitySpreadModel{
+			name:   string(SpreadModelTypeATR),
+			config: config,
+			series: atrp,
+			// ATRP is in percentage
+			ratio: 0.01,
+		}
+
+	case SpreadModelTypeRealizedVol:
+		vol := &realizedVolatility{IntervalWindow: config.IntervalWindow}
+		if store, ok := session.MarketDataStore(symbol); ok {
+			if klines, ok2 := store.KLinesOfInterval(config.Interval); ok2 {
+				for _, k := range *klines {
+					vol.PushK(k)
+				}
+			}
+		}
+
+		session.MarketDataStream.OnKLineClosed(types.KLineWith(symbol, config.Interval, vol.PushK))
+		return &volatilitySpreadModel{
+			name:   string(SpreadModelTypeRealizedVol),
+			config: config,
+			series: vol,
+			ratio:  1.0,
+		}
+	}
+
+	return nil
+}
+
+var _ SpreadModel = &volatilitySpreadModel{}
+var _ indicator.KLinePusher = &realizedVolatility{}
@@ -0,0 +1,73 @@
+package xmaker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+type testVolatilitySeries []float64
+
+func (s testVolatilitySeries) Length() int { return len(s) }
+
+func (s testVolatilitySeries) Last(i int) float64 { return s[len(s)-1-i] }
+
+func Test_volatilitySpreadModel_Margins(t *testing.T) {
+	config := &SpreadModelConfig{
+		Type:               SpreadModelTypeATR,
+		BaselineVolatility: fixedpoint.NewFromFloat(0.002),
+	}
+	config.Defaults()
+
+	margin := fixedpoint.NewFromFloat(0.001)
+
+	t.Run("not ready", func(t *testing.T) {
+		m := &volatilitySpreadModel{config: config, series: testVolatilitySeries{}, ratio: 0.01}
+		_, _, ok := m.Margins(margin, margin)
+		assert.False(t, ok)
+	})
+
+	t.Run("volatility spike", func(t *testing.T) {
+		// 0.4% ATRP => 2x
+		m := &volatilitySpreadModel{config: config, series: testVolatilitySeries{0.4}, ratio: 0.01}
+		bid, ask, ok := m.Margins(margin, margin)
+		assert.True(t, ok)
+		assert.Equal(t, "0.002", bid.String())
+		assert.Equal(t, "0.002", ask.String())
+	})
+
+	t.Run("clamped by max scale", func(t *testing.T) {
+		m := &volatilitySpreadModel{config: config, series: testVolatilitySeries{5.0}, ratio: 0.01}
+		bid, _, ok := m.Margins(margin, margin)
+		assert.True(t, ok)
+		assert.Equal(t, "0.003", bid.String())
+	})
+
+	t.Run("calm market clamped by min scale", func(t *testing.T) {
+		m := &volatilitySpreadModel{config: config, series: testVolatilitySeries{0.01}, ratio: 0.01}
+		bid, _, ok := m.Margins(margin, margin)
+		assert.True(t, ok)
+		assert.Equal(t, "0.0005", bid.String())
+	})
+}
+
+func Test_realizedVolatility(t *testing.T) {
+	vol := &realizedVolatility{IntervalWindow: types.IntervalWindow{Interval: types.Interval1m, Window: 5}}
+
+	for _, price := range []float64{100, 100, 100, 100} {
+		vol.PushK(types.KLine{Close: fixedpoint.NewFromFloat(price)})
+	}
+
+	assert.Equal(t, 2, vol.Length())
+	assert.InDelta(t, 0.0, vol.Last(0), 1e-9)
+
+	for _, price := range []float64{101, 99, 101, 99, 101} {
+		vol.PushK(types.KLine{Close: fixedpoint.NewFromFloat(price)})
+	}
+
+	assert.Greater(t, vol.Last(0), 0.015)
+	assert.LessOrEqual(t, vol.Length(), 5)
+}
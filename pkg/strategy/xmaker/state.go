@@ -32,6 +32,72 @@ type ProfitStats struct {
 	TodayMakerVolume    fixedpoint.Value `json:"todayMakerVolume,omitempty"`
 	TodayMakerBidVolume fixedpoint.Value `json:"todayMakerBidVolume,omitempty"`
 	TodayMakerAskVolume fixedpoint.Value `json:"todayMakerAskVolume,omitempty"`
+
+	// AccumulatedMarginInterest is the margin interest repaid for the hedge borrowing in the quote currency
+	AccumulatedMarginInterest fixedpoint.Value `json:"accumulatedMarginInterest,omitempty"`
+	TodayMarginInterest       fixedpoint.Value `json:"todayMarginInterest,omitempty"`
+
+	// Layers is the breakdown of the maker fills by the layer index, starting from 1
+	Layers map[int]*LayerProfitStats `json:"layers,omitempty"`
+
+	// Regimes is the breakdown of the performance by the market regime, e.g. high-uptrend
+	Regimes map[string]*RegimeProfitStats `json:"regimes,omitempty"`
+}
+
+// AddRegimeStats accumulates the stats of the time slice to its regime
+func (s *ProfitStats) AddRegimeStats(stats types.RegimeStats) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.Regimes == nil {
+		s.Regimes = make(map[string]*RegimeProfitStats)
+	}
+
+	regimeStats, ok := s.Regimes[stats.Regime()]
+	if !ok {
+		regimeStats = &RegimeProfitStats{}
+		s.Regimes[stats.Regime()] = regimeStats
+	}
+
+	regimeStats.Add(stats)
+}
+
+// AddLayerTrade records the maker trade and its captured edge to the layer
+func (s *ProfitStats) AddLayerTrade(layer int, trade types.Trade, edge fixedpoint.Value) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.Layers == nil {
+		s.Layers = make(map[int]*LayerProfitStats)
+	}
+
+	stats, ok := s.Layers[layer]
+	if !ok {
+		stats = &LayerProfitStats{}
+		s.Layers[layer] = stats
+	}
+
+	stats.NumFills++
+	stats.AccumulatedMakerVolume = stats.AccumulatedMakerVolume.Add(trade.Quantity)
+	stats.AccumulatedMakerQuoteVolume = stats.AccumulatedMakerQuoteVolume.Add(trade.QuoteQuantity)
+	stats.AccumulatedEdge = stats.AccumulatedEdge.Add(edge)
+	stats.TodayMakerVolume = stats.TodayMakerVolume.Add(trade.Quantity)
+	stats.TodayEdge = stats.TodayEdge.Add(edge)
+
+	switch trade.Side {
+	case types.SideTypeSell:
+		stats.AccumulatedMakerAskVolume = stats.AccumulatedMakerAskVolume.Add(trade.Quantity)
+	case types.SideTypeBuy:
+		stats.AccumulatedMakerBidVolume = stats.AccumulatedMakerBidVolume.Add(trade.Quantity)
+	}
+}
+
+// AddMarginInterest records the repaid margin interest in the quote currency
+func (s *ProfitStats) AddMarginInterest(interest fixedpoint.Value) {
+	s.lock.Lock()
+	s.AccumulatedMarginInterest = s.AccumulatedMarginInterest.Add(interest)
+	s.TodayMarginInterest = s.TodayMarginInterest.Add(interest)
+	s.lock.Unlock()
 }
 
 func (s *ProfitStats) AddTrade(trade types.Trade) {
@@ -64,5 +130,10 @@ func (s *ProfitStats) ResetToday() {
 	s.TodayMakerVolume = fixedpoint.Zero
 	s.TodayMakerBidVolume = fixedpoint.Zero
 	s.TodayMakerAskVolume = fixedpoint.Zero
+	s.TodayMarginInterest = fixedpoint.Zero
+	for _, stats := range s.Layers {
+		stats.TodayMakerVolume = fixedpoint.Zero
+		stats.TodayEdge = fixedpoint.Zero
+	}
 	s.lock.Unlock()
 }
@@ -8,12 +8,16 @@ import (
 
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
-	"golang.org/x/time/rate"
 
 	"github.com/c9s/bbgo/pkg/bbgo"
 	"github.com/c9s/bbgo/pkg/core"
 	"github.com/c9s/bbgo/pkg/fixedpoint"
 	"github.com/c9s/bbgo/pkg/indicator"
+	indicatorv2 "github.com/c9s/bbgo/pkg/indicator/v2"
+	"github.com/c9s/bbgo/pkg/pricesolver"
+	"github.com/c9s/bbgo/pkg/quoting"
+	"github.com/c9s/bbgo/pkg/risk/riskcontrol"
+	"github.com/c9s/bbgo/pkg/strategy/common"
 	"github.com/c9s/bbgo/pkg/types"
 	"github.com/c9s/bbgo/pkg/util"
 )
@@ -34,29 +38,97 @@ func init() {
 type Strategy struct {
 	Environment *bbgo.Environment
 
+	bbgo.StrategyController
+
 	Symbol string `json:"symbol"`
 
 	// SourceExchange session name
 	SourceExchange string `json:"sourceExchange"`
 
+	// SourceExchanges is the list of the source session names, when it's configured,
+	// the quote price will be derived from the aggregated order book of these sessions.
+	// The first session is the primary source session.
+	SourceExchanges []string `json:"sourceExchanges,omitempty"`
+
+	// HedgeSourcePolicy is the policy for selecting the source session that receives the hedge order,
+	// valid values are "primary", "bestPrice" and "smartRouting", defaults to "primary".
+	// The smartRouting policy splits the hedge quantity across the source sessions by their depth, fees and balances.
+	HedgeSourcePolicy HedgeSourcePolicy `json:"hedgeSourcePolicy,omitempty"`
+
+	// HedgeRoutingDepth is the number of the source book levels used by the smartRouting policy, defaults to 20
+	HedgeRoutingDepth int `json:"hedgeRoutingDepth,omitempty"`
+
 	// MakerExchange session name
 	MakerExchange string `json:"makerExchange"`
 
+	// PriceSource is where the reference price of the quotes comes from, valid values are "book" and "index",
+	// defaults to "book". The index price is implied by the IndexSymbols of the primary source session.
+	PriceSource PriceSource `json:"priceSource,omitempty"`
+
+	// IndexSymbols are the markets of the primary source session used for implying the index price,
+	// e.g. [BTCUSDC, USDCUSDT] for BTCUSDT
+	IndexSymbols []string `json:"indexSymbols,omitempty"`
+
+	// QuoteConversion quotes the maker market from the source market of a different quote currency,
+	// e.g. quoting BTCUSDT while hedging BTCUSDC
+	QuoteConversion *QuoteConversion `json:"quoteConversion,omitempty"`
+
 	UpdateInterval      types.Duration `json:"updateInterval"`
 	HedgeInterval       types.Duration `json:"hedgeInterval"`
 	OrderCancelWaitTime types.Duration `json:"orderCancelWaitTime"`
 
+	// UpdateTrigger is what drives the quote updates, valid values are "ticker" and "bookChange", defaults to "ticker".
+	// In the bookChange mode, the quotes are updated on the source order book changes,
+	// and UpdateInterval becomes the max interval between two updates.
+	UpdateTrigger UpdateTrigger `json:"updateTrigger,omitempty"`
+
+	// MinUpdateInterval is the minimal interval between two quote updates in the bookChange mode, defaults to 200ms
+	MinUpdateInterval types.Duration `json:"minUpdateInterval,omitempty"`
+
+	// DryRun computes and logs the quotes and the hedges without submitting any real order,
+	// the quotes are filled by the maker book and hedged on the source book to update the shadow position and metrics.
+	DryRun bool `json:"dryRun,omitempty"`
+
+	// PostOnly submits the maker orders as post-only (limit maker) orders to guarantee the maker fees,
+	// the orders rejected for crossing the book are re-priced one tick away and submitted again.
+	PostOnly bool `json:"postOnly,omitempty"`
+
+	// PostOnlyMaxReprices is the max number of re-pricing attempts of a rejected post-only order, defaults to 1
+	PostOnlyMaxReprices int `json:"postOnlyMaxReprices,omitempty"`
+
+	// WebSocketOrderEntry places and cancels the maker orders and the hedge orders through the websocket trading API
+	// of the exchanges that support it, the orders are placed through the REST API when the websocket connection is down.
+	WebSocketOrderEntry bool `json:"webSocketOrderEntry,omitempty"`
+
+	// SelfTradePreventionMode is set on the maker orders and the hedge orders, so that the exchanges supporting
+	// the self trade prevention expire the orders matching our own orders, e.g. EXPIRE_TAKER, EXPIRE_MAKER, EXPIRE_BOTH.
+	// The Coinbase stp values CANCEL_NEWEST, CANCEL_OLDEST and CANCEL_BOTH are accepted as the aliases.
+	SelfTradePreventionMode types.SelfTradePreventionMode `json:"selfTradePreventionMode,omitempty"`
+
 	Margin        fixedpoint.Value `json:"margin"`
 	BidMargin     fixedpoint.Value `json:"bidMargin"`
 	AskMargin     fixedpoint.Value `json:"askMargin"`
 	UseDepthPrice bool             `json:"useDepthPrice"`
 	DepthQuantity fixedpoint.Value `json:"depthQuantity"`
 
+	// MakerBookCheck enables the sanity check of the quote prices against the maker venue's order book,
+	// bid prices won't cross the maker best ask and ask prices won't cross the maker best bid.
+	MakerBookCheck bool `json:"makerBookCheck"`
+
+	// MakerBookCheckTolerance is the ratio that the quote prices are allowed to cross the maker book
+	MakerBookCheckTolerance fixedpoint.Value `json:"makerBookCheckTolerance"`
+
 	EnableBollBandMargin bool             `json:"enableBollBandMargin"`
 	BollBandInterval     types.Interval   `json:"bollBandInterval"`
 	BollBandMargin       fixedpoint.Value `json:"bollBandMargin"`
 	BollBandMarginFactor fixedpoint.Value `json:"bollBandMarginFactor"`
 
+	// BollBandWindow is the window of the bollinger band indicator, default to 21
+	BollBandWindow int `json:"bollBandWindow"`
+
+	// BollBandWidth is the multiplier of the standard deviation of the bollinger band, default to 1.0
+	BollBandWidth float64 `json:"bollBandWidth"`
+
 	StopHedgeQuoteBalance fixedpoint.Value `json:"stopHedgeQuoteBalance"`
 	StopHedgeBaseBalance  fixedpoint.Value `json:"stopHedgeBaseBalance"`
 
@@ -66,14 +138,108 @@ type Strategy struct {
 	// QuantityMultiplier is the factor that multiplies the quantity of the previous layer
 	QuantityMultiplier fixedpoint.Value `json:"quantityMultiplier"`
 
+	// QuantityByEquityRatio sizes the first layer by the ratio of the total account value of the maker session,
+	// e.g. 0.01 quotes 1% of the account value, so that the exposure stays proportional to the account.
+	// The quantity multiplier is still applied to the following layers, and the quantityScale takes precedence.
+	QuantityByEquityRatio fixedpoint.Value `json:"quantityByEquityRatio,omitempty"`
+
 	// QuantityScale helps user to define the quantity by layer scale
 	QuantityScale *bbgo.LayerScale `json:"quantityScale,omitempty"`
 
+	// QuantityJitter randomizes the quantity of each layer within the ratio, e.g. 0.1 for +-10%,
+	// so that the maker orders don't have constant sizes. The min quantity and the min notional are still respected.
+	QuantityJitter fixedpoint.Value `json:"quantityJitter,omitempty"`
+
 	// MaxExposurePosition defines the unhedged quantity of stop
 	MaxExposurePosition fixedpoint.Value `json:"maxExposurePosition"`
 
+	// MaxExposurePositionByEquityRatio expresses the max exposure position as the ratio of the account value of the maker session,
+	// e.g. 0.2 limits the position value to 20% of the account value. The limit is re-evaluated when the account value is updated,
+	// so that it scales as the account grows or draws down. MaxExposurePosition is used when the account value is not available.
+	MaxExposurePositionByEquityRatio fixedpoint.Value `json:"maxExposurePositionByEquityRatio,omitempty"`
+
+	// FeeSymbols are the symbols of the primary source session for pricing the fee currencies other than
+	// the base and the quote currencies, e.g. BNBUSDT. The fees paid in these currencies are converted
+	// into the quote currency before the trades are added to the position and the profit stats.
+	FeeSymbols []string `json:"feeSymbols,omitempty"`
+
+	// IndexPriceFeed marks the account value with the external index prices instead of the last prices of the maker session
+	IndexPriceFeed *pricesolver.IndexPriceFeedConfig `json:"indexPriceFeed,omitempty"`
+
+	// InventorySkewFactor shifts the bid/ask margins proportionally to the position relative to the MaxExposurePosition,
+	// so that the quotes lean toward reducing the inventory. 1.0 means the margin can be doubled or reduced to zero
+	// when the position reaches the MaxExposurePosition.
+	InventorySkewFactor fixedpoint.Value `json:"inventorySkewFactor"`
+
+	// HedgeCostMargin adds the estimated hedge cost of each layer, the taker fee plus the expected slippage
+	// on the current source book depth, to the layer margin, so that the quoted edge is net of the hedge cost.
+	HedgeCostMargin bool `json:"hedgeCostMargin"`
+
+	// SpreadModel scales the bid/ask margins by the short-term volatility of the source market,
+	// the margins are widened when the volatility spikes and tightened in calm markets.
+	SpreadModel *SpreadModelConfig `json:"spreadModel,omitempty"`
+
+	// AvellanedaStoikov computes the bid/ask margins by the Avellaneda-Stoikov model from the estimated trade intensity,
+	// the volatility of the source market and the inventory, instead of the fixed margins.
+	AvellanedaStoikov *AvellanedaStoikov `json:"avellanedaStoikov,omitempty"`
+
+	// FundingRateMargin tilts the bid/ask margins by the predicted funding rate when the source session is a perpetual market
+	FundingRateMargin *FundingRateMargin `json:"fundingRateMargin,omitempty"`
+
+	// PriceBand suppresses quoting when the source mid-price deviates too far from the reference price
+	PriceBand *PriceBand `json:"priceBand,omitempty"`
+
+	// ToxicityFilter widens the margin or pauses the side whose maker fills are adversely selected,
+	// measured by the source mid-price move after the fills
+	ToxicityFilter *ToxicityFilter `json:"toxicityFilter,omitempty"`
+
 	DisableHedge bool `json:"disableHedge"`
 
+	// HedgeNettingWindow nets the maker fills within the window before hedging,
+	// the hedge is delayed until no new fill comes in the window, so that the fills of both sides cancel out each other.
+	HedgeNettingWindow types.Duration `json:"hedgeNettingWindow,omitempty"`
+
+	// MaxUncoveredDuration forces the hedge when the first netted fill has been uncovered for longer than the duration,
+	// regardless of the netting window.
+	MaxUncoveredDuration types.Duration `json:"maxUncoveredDuration,omitempty"`
+
+	// HedgeTWAP slices the hedge of the large uncovered position into child orders over a duration
+	HedgeTWAP *TWAPHedge `json:"hedgeTwap,omitempty"`
+
+	// Rebalance restores the balances between the maker session and the source session
+	// when the assets pile up on one venue after prolonged quoting
+	Rebalance *BalanceRebalance `json:"rebalance,omitempty"`
+
+	// MakerBorrow allows the margin maker session to quote beyond the free balance by borrowing
+	MakerBorrow *MakerMarginBorrow `json:"makerBorrow,omitempty"`
+
+	// HedgeBorrow borrows the insufficient asset before hedging on the margin source sessions,
+	// and repays the debt when the position is flattened
+	HedgeBorrow *HedgeMarginBorrow `json:"hedgeBorrow,omitempty"`
+
+	// ClosePositionOnShutdown hedges the uncovered position, or unwinds the full position, when the strategy is shut down
+	ClosePositionOnShutdown *ShutdownPositionClose `json:"closePositionOnShutdown,omitempty"`
+
+	// DecisionExport writes the quoting decisions into the parquet files for the offline research
+	DecisionExport *DecisionExport `json:"decisionExport,omitempty"`
+
+	// RegimeAttribution attributes the PnL, the fill ratio and the markouts to the volatility and the trend regimes
+	RegimeAttribution *RegimeAttribution `json:"regimeAttribution,omitempty"`
+
+	// MaxDrawdown halts quoting and flattens the uncovered position when the intraday drawdown
+	// (realized + unrealized PnL) exceeds this ratio of the equity, e.g. 0.05 means 5%
+	MaxDrawdown fixedpoint.Value `json:"maxDrawdown"`
+
+	// DrawdownHaltDuration is the cool-down duration before quoting is resumed, defaults to 1h
+	DrawdownHaltDuration types.Duration `json:"drawdownHaltDuration"`
+
+	// DrawdownHaltAction is the action taken when the max drawdown is hit, "cancel" only cancels the maker orders,
+	// "flatten" also hedges the uncovered position, defaults to "flatten"
+	DrawdownHaltAction types.HaltAction `json:"drawdownHaltAction,omitempty"`
+
+	// DrawdownPriceEMA is the EMA of the source price for calculating the unrealized PnL
+	DrawdownPriceEMA types.IntervalWindow `json:"drawdownPriceEMA"`
+
 	NotifyTrade bool `json:"notifyTrade"`
 
 	// RecoverTrade tries to find the missing trades via the REStful API
@@ -86,6 +252,35 @@ type Strategy struct {
 	// Pips is the pips of the layer prices
 	Pips fixedpoint.Value `json:"pips"`
 
+	// DiffRequote compares the desired layers with the active maker orders on every update,
+	// and only amends (or cancel/replaces) the layers whose price or quantity moved beyond the tolerance,
+	// instead of canceling all the maker orders on every update.
+	DiffRequote bool `json:"diffRequote"`
+
+	// RequotePriceTolerance is the price change ratio that is tolerated without requoting the layer
+	RequotePriceTolerance fixedpoint.Value `json:"requotePriceTolerance"`
+
+	// RequoteQuantityTolerance is the quantity change ratio that is tolerated without requoting the layer
+	RequoteQuantityTolerance fixedpoint.Value `json:"requoteQuantityTolerance"`
+
+	// RequoteLayerOrder is the order of refreshing the layers in the diff requote mode,
+	// valid values are "deepestFirst" and "touchFirst", defaults to "deepestFirst".
+	RequoteLayerOrder RequoteLayerOrder `json:"requoteLayerOrder,omitempty"`
+
+	// PartialFillRequote tracks the remaining quantity of each maker layer, when a layer is partially filled,
+	// the filled portion is hedged immediately and only the consumed layer is topped up,
+	// instead of waiting for the next cancel/requote cycle.
+	PartialFillRequote bool `json:"partialFillRequote"`
+
+	// LayerProfitStats tags the maker orders with their layer index in the client order id,
+	// and breaks down the maker fills and the captured edge by the layer in the profit stats.
+	LayerProfitStats bool `json:"layerProfitStats"`
+
+	// LayerPrecisionPolicy rounds the layer prices to the maker tick size and handles the layers that collapse
+	// into the same price when the maker tick size is coarser than the source, valid values are "skip" and "merge".
+	// The layer prices are not adjusted when it's empty.
+	LayerPrecisionPolicy LayerPrecisionPolicy `json:"layerPrecisionPolicy,omitempty"`
+
 	// --------------------------------
 	// private field
 
@@ -93,9 +288,36 @@ type Strategy struct {
 
 	makerMarket, sourceMarket types.Market
 
+	// sourceSessions and sourceMarkets are indexed by the source session name
+	sourceSessions map[string]*bbgo.ExchangeSession
+	sourceMarkets  map[string]types.Market
+
 	// boll is the BOLLINGER indicator we used for predicting the price.
 	boll *indicator.BOLL
 
+	spreadModel SpreadModel
+
+	avellanedaStoikov *avellanedaStoikovModel
+
+	fundingRateFeed *bbgo.FundingRateFeed
+
+	priceSolver *pricesolver.SimplePriceSolver
+
+	quoteConverter *quoteConverter
+
+	priceBandEMA        *indicatorv2.EWMAStream
+	priceBandSuppressed bool
+
+	// markoutTracker measures the markouts of the maker fills for the toxicity filter
+	markoutTracker *markoutTracker
+
+	decisionRecorder *decisionRecorder
+
+	// quoteDiagnostics records why the quote update cycles didn't place any maker order
+	quoteDiagnostics quoteDiagnostics
+
+	regimeAttributor *regimeAttributor
+
 	state *State
 
 	// persistence fields
@@ -103,16 +325,68 @@ type Strategy struct {
 	ProfitStats     *ProfitStats     `json:"profitStats,omitempty" persistence:"profit_stats"`
 	CoveredPosition fixedpoint.Value `json:"coveredPosition,omitempty" persistence:"covered_position"`
 
-	book              *types.StreamOrderBook
+	// VenueCoveredPositions is the net position of the hedge trades on each source session
+	VenueCoveredPositions   map[string]fixedpoint.Value `json:"venueCoveredPositions,omitempty" persistence:"venue_covered_positions"`
+	venueCoveredPositionsMu sync.Mutex
+
+	// FXPosition is the net position of the source quote currency from the hedge trades in the quote conversion mode,
+	// valued in the maker quote currency
+	FXPosition fixedpoint.Value `json:"fxPosition,omitempty" persistence:"fx_position"`
+
+	book              *types.AggregatedStreamOrderBook
+	makerBook         *types.StreamOrderBook
 	activeMakerOrders *bbgo.ActiveOrderBook
 
-	hedgeErrorLimiter         *rate.Limiter
-	hedgeErrorRateReservation *rate.Reservation
+	// shadow simulates the quotes and the hedges in the dry-run mode
+	shadow *shadowQuoter
+
+	// layerTracker tracks the remaining quantity of the maker layers in the partial fill requote mode
+	layerTracker *layerTracker
+
+	hedgeNetting hedgeNetting
+
+	// quality metrics states
+	quoteUptime                    quoteUptime
+	hedgeLatency                   hedgeLatency
+	qualityMu                      sync.Mutex
+	numMakerOrders, numMakerTrades int
+
+	// hedgeExecutors are the market order hedge executors of the source sessions
+	hedgeExecutors map[string]*common.MarketOrderHedgeExecutor
 
 	orderStore     *core.OrderStore
 	tradeCollector *core.TradeCollector
 
-	askPriceHeartBeat, bidPriceHeartBeat *types.PriceHeartBeat
+	sourceHeartBeats map[string]*sourceHeartBeat
+
+	twapHedge *common.TWAPHedgeExecutor
+
+	rebalancer *balanceRebalancer
+
+	makerBorrower *makerBorrower
+
+	// hedgeBorrowers are the borrow/repay services of the margin source sessions
+	hedgeBorrowers map[string]types.MarginBorrowRepayService
+
+	drawdownCircuitBreaker *riskcontrol.DrawdownCircuitBreakRiskControl
+	drawdownHalted         bool
+
+	// calendarPaused is true when the maker venue or the hedge venues are paused by the trading calendar
+	calendarPaused bool
+
+	// degraded is true when the maker venue or all the hedge venues are in the degraded mode
+	degraded bool
+
+	accountValueCalculator *bbgo.AccountValueCalculator
+	equityMu               sync.Mutex
+	accountEquity          fixedpoint.Value
+
+	quoteScheduler *quoteScheduler
+
+	bookChangeTrigger *bookChangeTrigger
+
+	// marginMu protects the bid/ask margins adjusted at runtime
+	marginMu sync.Mutex
 
 	lastPrice fixedpoint.Value
 	groupID   uint32
@@ -129,92 +403,246 @@ func (s *Strategy) InstanceID() string {
 }
 
 func (s *Strategy) CrossSubscribe(sessions map[string]*bbgo.ExchangeSession) {
-	sourceSession, ok := sessions[s.SourceExchange]
-	if !ok {
-		panic(fmt.Errorf("source session %s is not defined", s.SourceExchange))
+	if s.SpreadModel != nil {
+		s.SpreadModel.Defaults()
+	}
+
+	if s.AvellanedaStoikov != nil {
+		s.AvellanedaStoikov.Defaults()
 	}
 
-	sourceSession.Subscribe(types.BookChannel, s.Symbol, types.SubscribeOptions{})
-	sourceSession.Subscribe(types.KLineChannel, s.Symbol, types.SubscribeOptions{Interval: "1m"})
+	if s.FundingRateMargin != nil {
+		s.FundingRateMargin.Defaults()
+	}
 
-	makerSession, ok := sessions[s.MakerExchange]
-	if !ok {
-		panic(fmt.Errorf("maker session %s is not defined", s.MakerExchange))
+	if s.PriceBand != nil {
+		s.PriceBand.Defaults()
+	}
+
+	if s.ToxicityFilter != nil {
+		s.ToxicityFilter.Defaults()
+	}
+
+	if s.ClosePositionOnShutdown != nil {
+		s.ClosePositionOnShutdown.Defaults()
 	}
-	makerSession.Subscribe(types.KLineChannel, s.Symbol, types.SubscribeOptions{Interval: "1m"})
-}
 
-func aggregatePrice(pvs types.PriceVolumeSlice, requiredQuantity fixedpoint.Value) (price fixedpoint.Value) {
-	q := requiredQuantity
-	totalAmount := fixedpoint.Zero
+	if s.DecisionExport != nil {
+		s.DecisionExport.Defaults()
+	}
 
-	if len(pvs) == 0 {
-		price = fixedpoint.Zero
-		return price
-	} else if pvs[0].Volume.Compare(requiredQuantity) >= 0 {
-		return pvs[0].Price
+	if s.RegimeAttribution != nil {
+		s.RegimeAttribution.Defaults()
 	}
 
-	for i := 0; i < len(pvs); i++ {
-		pv := pvs[i]
-		if pv.Volume.Compare(q) >= 0 {
-			totalAmount = totalAmount.Add(q.Mul(pv.Price))
-			break
+	for _, sourceExchange := range s.sourceExchangeNames() {
+		sourceSession, ok := sessions[sourceExchange]
+		if !ok {
+			panic(fmt.Errorf("source session %s is not defined", sourceExchange))
+		}
+
+		sourceSession.Subscribe(types.BookChannel, s.sourceSymbol(), types.SubscribeOptions{})
+		sourceSession.Subscribe(types.KLineChannel, s.sourceSymbol(), types.SubscribeOptions{Interval: "1m"})
+
+		if s.SpreadModel != nil {
+			sourceSession.Subscribe(types.KLineChannel, s.sourceSymbol(), types.SubscribeOptions{Interval: s.SpreadModel.Interval})
+		}
+
+		if s.MaxDrawdown.Sign() > 0 && s.DrawdownPriceEMA.Interval != "" {
+			sourceSession.Subscribe(types.KLineChannel, s.sourceSymbol(), types.SubscribeOptions{Interval: s.DrawdownPriceEMA.Interval})
+		}
+
+		if s.PriceBand != nil && s.PriceBand.Enabled {
+			sourceSession.Subscribe(types.KLineChannel, s.sourceSymbol(), types.SubscribeOptions{Interval: s.PriceBand.ReferenceEMA.Interval})
+		}
+
+		if s.RegimeAttribution != nil && s.RegimeAttribution.Enabled {
+			sourceSession.Subscribe(types.KLineChannel, s.sourceSymbol(), types.SubscribeOptions{Interval: s.RegimeAttribution.Interval})
 		}
+	}
+
+	if s.AvellanedaStoikov != nil && s.AvellanedaStoikov.Enabled {
+		// the trade intensity is estimated on the primary source session
+		sourceSession := sessions[s.sourceExchangeNames()[0]]
+		sourceSession.Subscribe(types.MarketTradeChannel, s.sourceSymbol(), types.SubscribeOptions{})
+		sourceSession.Subscribe(types.KLineChannel, s.sourceSymbol(), types.SubscribeOptions{Interval: s.AvellanedaStoikov.Interval})
+	}
 
-		q = q.Sub(pv.Volume)
-		totalAmount = totalAmount.Add(pv.Volume.Mul(pv.Price))
+	if s.PriceSource == PriceSourceIndex {
+		s.subscribeIndexSymbols(sessions[s.sourceExchangeNames()[0]])
+	}
+
+	if s.QuoteConversion != nil && s.QuoteConversion.Enabled {
+		s.subscribeConversionSymbols(sessions[s.sourceExchangeNames()[0]])
+	}
+
+	if len(s.FeeSymbols) > 0 {
+		s.subscribeFeeSymbols(sessions[s.sourceExchangeNames()[0]])
+	}
+
+	makerSession, ok := sessions[s.MakerExchange]
+	if !ok {
+		panic(fmt.Errorf("maker session %s is not defined", s.MakerExchange))
 	}
+	makerSession.Subscribe(types.KLineChannel, s.Symbol, types.SubscribeOptions{Interval: "1m"})
 
-	price = totalAmount.Div(requiredQuantity)
-	return price
+	// the dry-run mode fills the shadow quotes by the maker book
+	if s.MakerBookCheck || s.DryRun {
+		makerSession.Subscribe(types.BookChannel, s.Symbol, types.SubscribeOptions{})
+	}
 }
 
 func (s *Strategy) Initialize() error {
-	s.bidPriceHeartBeat = types.NewPriceHeartBeat(priceUpdateTimeout)
-	s.askPriceHeartBeat = types.NewPriceHeartBeat(priceUpdateTimeout)
+	s.sourceHeartBeats = make(map[string]*sourceHeartBeat)
+	for _, sourceExchange := range s.sourceExchangeNames() {
+		s.sourceHeartBeats[sourceExchange] = newSourceHeartBeat()
+	}
 	return nil
 }
 
+// quoteCycleInterval returns the interval of the quoting cycles, the quotes are updated by the book changes
+// at most once per MinUpdateInterval when the book change trigger is enabled
+func (s *Strategy) quoteCycleInterval() time.Duration {
+	if s.bookChangeTrigger != nil {
+		return s.MinUpdateInterval.Duration()
+	}
+
+	return s.UpdateInterval.Duration()
+}
+
 func (s *Strategy) updateQuote(ctx context.Context, orderExecutionRouter bbgo.OrderExecutionRouter) {
-	if err := s.activeMakerOrders.GracefulCancel(ctx, s.makerSession.Exchange); err != nil {
-		log.Warnf("there are some %s orders not canceled, skipping placing maker orders", s.Symbol)
+	defer bbgo.TrackStrategyCallback(ctx, "updateQuote")()
+
+	// the maker orders of the same quoting cycle get the same client order IDs,
+	// so that the orders placed before a timeout or a restart are not placed again
+	ctx = bbgo.WithOrderEpoch(ctx, s.InstanceID(), bbgo.OrderEpoch(time.Now(), s.quoteCycleInterval()))
+
+	var submitOrders []types.SubmitOrder
+	var skipReason QuoteSkipReason
+
+	if s.DryRun {
+		s.quoteScheduler.Compute(func() {
+			submitOrders, skipReason = s.generateMakerOrders()
+		})
+
+		s.recordQuoteCycle(skipReason, len(submitOrders))
+		s.shadowQuote(submitOrders)
+		return
+	}
+
+	if s.DiffRequote {
+		s.quoteScheduler.Compute(func() {
+			submitOrders, skipReason = s.generateMakerOrders()
+		})
+
+		s.recordQuoteCycle(skipReason, len(submitOrders))
+		s.quoteScheduler.Submit(s.MakerExchange, func() {
+			s.requote(ctx, orderExecutionRouter, submitOrders)
+		})
+		return
+	}
+
+	var cancelErr error
+	s.quoteScheduler.Submit(s.MakerExchange, func() {
+		cancelErr = s.activeMakerOrders.GracefulCancel(ctx, s.makerSession.Exchange)
+	})
+
+	if cancelErr != nil {
+		s.logger().Warnf("there are some %s orders not canceled, skipping placing maker orders", s.Symbol)
 		s.activeMakerOrders.Print()
+		s.recordQuoteCycle(QuoteSkipReasonCancelPending, 0)
 		return
 	}
 
 	if s.activeMakerOrders.NumOfOrders() > 0 {
+		s.recordQuoteCycle(QuoteSkipReasonCancelPending, 0)
 		return
 	}
 
-	bestBid, bestAsk, hasPrice := s.book.BestBidAndAsk()
-	if !hasPrice {
+	s.quoteScheduler.Compute(func() {
+		submitOrders, skipReason = s.generateMakerOrders()
+	})
+
+	s.recordQuoteCycle(skipReason, len(submitOrders))
+	if len(submitOrders) == 0 {
+		s.logger().Warnf("no orders generated: %s", skipReason)
 		return
 	}
 
-	// use mid-price for the last price
-	s.lastPrice = bestBid.Price.Add(bestAsk.Price).Div(Two)
+	s.quoteScheduler.Submit(s.MakerExchange, func() {
+		makerOrders, err := s.submitMakerOrders(ctx, orderExecutionRouter, submitOrders)
+		if err != nil {
+			s.logger().WithError(err).Errorf("order error: %s", err.Error())
+		}
 
-	bookLastUpdateTime := s.book.LastUpdateTime()
+		// the orders created before the error still need to be tracked
+		s.trackMakerOrders(makerOrders...)
+	})
+}
 
-	if _, err := s.bidPriceHeartBeat.Update(bestBid); err != nil {
-		log.WithError(err).Errorf("quote update error, %s price not updating, order book last update: %s ago",
+// generateMakerOrders generates the maker orders of all the layers from the source book,
+// it returns nil and the skip reason when the maker orders should not be placed.
+func (s *Strategy) generateMakerOrders() ([]types.SubmitOrder, QuoteSkipReason) {
+	// only the sources that are still updating are used for quoting
+	sources := s.activeSources()
+	if len(sources) == 0 {
+		s.logger().Errorf("quote update error, %s price not updating on all the sources, order book last update: %s ago",
 			s.Symbol,
-			time.Since(bookLastUpdateTime))
-		return
+			time.Since(s.book.LastUpdateTime()))
+		return nil, QuoteSkipReasonStalePrice
 	}
 
-	if _, err := s.askPriceHeartBeat.Update(bestAsk); err != nil {
-		log.WithError(err).Errorf("quote update error, %s price not updating, order book last update: %s ago",
-			s.Symbol,
-			time.Since(bookLastUpdateTime))
-		return
+	bestBid, bestAsk, hasPrice := s.book.BestBidAndAskOf(sources...)
+	if !hasPrice {
+		return nil, QuoteSkipReasonStalePrice
+	}
+
+	// the index price replaces the best bid/ask of the thin source book as the reference price,
+	// the spread of the quotes is made by the margins.
+	if s.priceSolver != nil {
+		indexPrice, ok := s.indexPrice()
+		if !ok {
+			s.logger().Warnf("%s index price is not available from the index symbols %v, skip quoting", s.Symbol, s.IndexSymbols)
+			return nil, QuoteSkipReasonStalePrice
+		}
+
+		bestBid.Price = indexPrice
+		bestAsk.Price = indexPrice
+	}
+
+	// convert the source prices into the maker quote currency, the index price is already in the maker quote currency
+	conversionRate := fixedpoint.One
+	if s.quoteConverter != nil {
+		rate, ok := s.quoteConverter.Rate()
+		if !ok {
+			s.logger().Warnf("%s conversion rate %s/%s is not available, skip quoting",
+				s.Symbol, s.quoteConverter.sourceQuote, s.quoteConverter.makerQuote)
+			return nil, QuoteSkipReasonStalePrice
+		}
+
+		conversionRate = rate
+		if s.priceSolver == nil {
+			bestBid.Price = bestBid.Price.Mul(rate)
+			bestAsk.Price = bestAsk.Price.Mul(rate)
+		}
+	}
+
+	// use mid-price for the last price
+	s.lastPrice = bestBid.Price.Add(bestAsk.Price).Div(Two)
+
+	// the reference EMA of the price band is in the source quote currency
+	if !s.checkPriceBand(s.lastPrice.Div(conversionRate)) {
+		return nil, QuoteSkipReasonPriceBand
 	}
 
-	sourceBook := s.book.CopyDepth(10)
+	sourceBook := s.book.CopyDepthOf(10, sources...)
 	if valid, err := sourceBook.IsValid(); !valid {
-		log.WithError(err).Errorf("%s invalid copied order book, skip quoting: %v", s.Symbol, err)
-		return
+		s.logger().WithError(err).Errorf("%s invalid copied order book, skip quoting: %v", s.Symbol, err)
+		return nil, QuoteSkipReasonInvalidBook
+	}
+
+	if s.quoteConverter != nil {
+		sourceBook = convertOrderBook(sourceBook, s.Symbol, conversionRate)
 	}
 
 	var disableMakerBid = false
@@ -222,107 +650,116 @@ func (s *Strategy) updateQuote(ctx context.Context, orderExecutionRouter bbgo.Or
 
 	// check maker's balance quota
 	// we load the balances from the account while we're generating the orders,
-	// the balance may have a chance to be deducted by other strategies or manual orders submitted by the user
-	makerBalances := s.makerSession.GetAccount().Balances()
+	// the balance may have a chance to be deducted by other strategies or manual orders submitted by the user.
+	// the balances are loaded from the atomic snapshot so that they are not mixed from the different stream updates.
+	makerBalances := s.makerSession.AtomicSnapshot().Balances
 	makerQuota := &bbgo.QuotaTransaction{}
+
+	// in the diff requote mode, the active maker orders are not canceled before generating the new orders,
+	// so the funds locked by the active maker orders are still available for the new orders.
+	var lockedBase, lockedQuote fixedpoint.Value
+	if s.DiffRequote {
+		lockedBase, lockedQuote = quoting.LockedFunds(s.activeMakerOrders.Orders())
+	}
+
 	if b, ok := makerBalances[s.makerMarket.BaseCurrency]; ok {
-		if b.Available.Compare(s.makerMarket.MinQuantity) > 0 {
-			makerQuota.BaseAsset.Add(b.Available)
+		available := b.Available.Add(lockedBase).Add(s.makerBorrowable(s.makerMarket.BaseCurrency))
+		if available.Compare(s.makerMarket.MinQuantity) > 0 {
+			makerQuota.BaseAsset.Add(available)
 		} else {
 			disableMakerAsk = true
 		}
 	}
 
 	if b, ok := makerBalances[s.makerMarket.QuoteCurrency]; ok {
-		if b.Available.Compare(s.makerMarket.MinNotional) > 0 {
-			makerQuota.QuoteAsset.Add(b.Available)
-		} else {
-			disableMakerBid = true
-		}
-	}
-
-	hedgeBalances := s.sourceSession.GetAccount().Balances()
-	hedgeQuota := &bbgo.QuotaTransaction{}
-	if b, ok := hedgeBalances[s.sourceMarket.BaseCurrency]; ok {
-		// to make bid orders, we need enough base asset in the foreign exchange,
-		// if the base asset balance is not enough for selling
-		if s.StopHedgeBaseBalance.Sign() > 0 {
-			minAvailable := s.StopHedgeBaseBalance.Add(s.sourceMarket.MinQuantity)
-			if b.Available.Compare(minAvailable) > 0 {
-				hedgeQuota.BaseAsset.Add(b.Available.Sub(minAvailable))
-			} else {
-				log.Warnf("%s maker bid disabled: insufficient base balance %s", s.Symbol, b.String())
-				disableMakerBid = true
-			}
-		} else if b.Available.Compare(s.sourceMarket.MinQuantity) > 0 {
-			hedgeQuota.BaseAsset.Add(b.Available)
+		available := b.Available.Add(lockedQuote).Add(s.makerBorrowable(s.makerMarket.QuoteCurrency))
+		if available.Compare(s.makerMarket.MinNotional) > 0 {
+			makerQuota.QuoteAsset.Add(available)
 		} else {
-			log.Warnf("%s maker bid disabled: insufficient base balance %s", s.Symbol, b.String())
 			disableMakerBid = true
 		}
 	}
 
-	if b, ok := hedgeBalances[s.sourceMarket.QuoteCurrency]; ok {
-		// to make ask orders, we need enough quote asset in the foreign exchange,
-		// if the quote asset balance is not enough for buying
-		if s.StopHedgeQuoteBalance.Sign() > 0 {
-			minAvailable := s.StopHedgeQuoteBalance.Add(s.sourceMarket.MinNotional)
-			if b.Available.Compare(minAvailable) > 0 {
-				hedgeQuota.QuoteAsset.Add(b.Available.Sub(minAvailable))
-			} else {
-				log.Warnf("%s maker ask disabled: insufficient quote balance %s", s.Symbol, b.String())
-				disableMakerAsk = true
-			}
-		} else if b.Available.Compare(s.sourceMarket.MinNotional) > 0 {
-			hedgeQuota.QuoteAsset.Add(b.Available)
-		} else {
-			log.Warnf("%s maker ask disabled: insufficient quote balance %s", s.Symbol, b.String())
-			disableMakerAsk = true
-		}
-	}
+	hedgeQuota, disableHedgeBid, disableHedgeAsk := s.hedgeQuota()
+	disableMakerBid = disableMakerBid || disableHedgeBid
+	disableMakerAsk = disableMakerAsk || disableHedgeAsk
 
 	// if max exposure position is configured, we should not:
 	// 1. place bid orders when we already bought too much
 	// 2. place ask orders when we already sold too much
-	if s.MaxExposurePosition.Sign() > 0 {
+	maxExposurePosition := s.getMaxExposurePosition()
+	if maxExposurePosition.Sign() > 0 {
 		pos := s.Position.GetBase()
 
-		if pos.Compare(s.MaxExposurePosition.Neg()) > 0 {
+		if pos.Compare(maxExposurePosition.Neg()) > 0 {
 			// stop sell if we over-sell
 			disableMakerAsk = true
-		} else if pos.Compare(s.MaxExposurePosition) > 0 {
+		} else if pos.Compare(maxExposurePosition) > 0 {
 			// stop buy if we over buy
 			disableMakerBid = true
 		}
 	}
 
+	// in the wind-down mode, we only quote the side that reduces the position,
+	// and the quoted quantity is capped by the position size
+	windingDown := s.windingDown()
+	var windDownQuantity fixedpoint.Value
+	if windingDown {
+		pos := s.Position.GetBase()
+		windDownBid, windDownAsk := windDownSides(pos)
+		disableMakerBid = disableMakerBid || windDownBid
+		disableMakerAsk = disableMakerAsk || windDownAsk
+		windDownQuantity = pos.Abs()
+	}
+
+	// pause the side that is adversely selected by the taker flow
+	pauseBid, pauseAsk := s.updateToxicity(time.Now(), s.lastPrice)
+
+	if s.regimeAttributor != nil {
+		s.regimeAttributor.UpdateMarkouts(time.Now(), s.lastPrice)
+	}
+	disableMakerBid = disableMakerBid || pauseBid
+	disableMakerAsk = disableMakerAsk || pauseAsk
+
 	if disableMakerAsk && disableMakerBid {
-		log.Warnf("%s bid/ask maker is disabled due to insufficient balances", s.Symbol)
-		return
+		s.logger().Warnf("%s bid/ask maker is disabled due to insufficient balances or the toxic flow", s.Symbol)
+		return nil, QuoteSkipReasonNoBalance
 	}
 
 	bestBidPrice := bestBid.Price
 	bestAskPrice := bestAsk.Price
-	log.Infof("%s book ticker: best ask / best bid = %v / %v", s.Symbol, bestAskPrice, bestBidPrice)
+	s.logger().Infof("%s book ticker: best ask / best bid = %v / %v", s.Symbol, bestAskPrice, bestBidPrice)
 
 	var submitOrders []types.SubmitOrder
 	var accumulativeBidQuantity, accumulativeAskQuantity fixedpoint.Value
 	var bidQuantity = s.Quantity
 	var askQuantity = s.Quantity
-	var bidMargin = s.BidMargin
-	var askMargin = s.AskMargin
+	if s.QuantityByEquityRatio.Sign() > 0 {
+		equity := s.getAccountEquity()
+		if equity.Sign() <= 0 {
+			s.logger().Warnf("%s account value is not available, skip sizing the quantity by the equity ratio", s.Symbol)
+			return nil, QuoteSkipReasonNoBalance
+		}
+
+		bidQuantity = s.makerMarket.TruncateQuantity(equityQuantity(equity, s.QuantityByEquityRatio, bestBidPrice))
+		askQuantity = s.makerMarket.TruncateQuantity(equityQuantity(equity, s.QuantityByEquityRatio, bestAskPrice))
+		s.logger().Infof("%s sizing the quantity by %v of the account value %v: bid %v, ask %v",
+			s.Symbol, s.QuantityByEquityRatio, equity, bidQuantity, askQuantity)
+	}
+
+	bidMargin, askMargin := s.getMargins()
 	var pips = s.Pips
 
 	if s.EnableBollBandMargin {
-		lastDownBand := fixedpoint.NewFromFloat(s.boll.DownBand.Last(0))
-		lastUpBand := fixedpoint.NewFromFloat(s.boll.UpBand.Last(0))
+		lastDownBand := fixedpoint.NewFromFloat(s.boll.DownBand.Last(0)).Mul(conversionRate)
+		lastUpBand := fixedpoint.NewFromFloat(s.boll.UpBand.Last(0)).Mul(conversionRate)
 
 		if lastUpBand.IsZero() || lastDownBand.IsZero() {
-			log.Warnf("bollinger band value is zero, skipping")
-			return
+			s.logger().Warnf("bollinger band value is zero, skipping")
+			return nil, QuoteSkipReasonIndicatorNotReady
 		}
 
-		log.Infof("bollinger band: up/down = %f/%f", lastUpBand.Float64(), lastDownBand.Float64())
+		s.logger().Infof("bollinger band: up/down = %f/%f", lastUpBand.Float64(), lastDownBand.Float64())
 
 		// when bid price is lower than the down band, then it's in the downtrend
 		// when ask price is higher than the up band, then it's in the uptrend
@@ -333,7 +770,7 @@ func (s *Strategy) updateQuote(ctx context.Context, orderExecutionRouter bbgo.Or
 			// so that the original bid margin can be multiplied by 1.x
 			bollMargin := s.BollBandMargin.Mul(ratio).Mul(s.BollBandMarginFactor)
 
-			log.Infof("%s bollband downtrend: adjusting ask margin %v + %v = %v",
+			s.logger().Infof("%s bollband downtrend: adjusting ask margin %v + %v = %v",
 				s.Symbol,
 				askMargin,
 				bollMargin,
@@ -350,7 +787,7 @@ func (s *Strategy) updateQuote(ctx context.Context, orderExecutionRouter bbgo.Or
 			// so that the original bid margin can be multiplied by 1.x
 			bollMargin := s.BollBandMargin.Mul(ratio).Mul(s.BollBandMarginFactor)
 
-			log.Infof("%s bollband uptrend adjusting bid margin %v + %v = %v",
+			s.logger().Infof("%s bollband uptrend adjusting bid margin %v + %v = %v",
 				s.Symbol,
 				bidMargin,
 				bollMargin,
@@ -361,134 +798,192 @@ func (s *Strategy) updateQuote(ctx context.Context, orderExecutionRouter bbgo.Or
 		}
 	}
 
+	if s.spreadModel != nil {
+		if adjustedBidMargin, adjustedAskMargin, ok := s.spreadModel.Margins(bidMargin, askMargin); ok {
+			s.logger().Infof("%s spread model %s: adjusting bid/ask margin %v/%v to %v/%v",
+				s.Symbol, s.spreadModel.Name(), bidMargin, askMargin, adjustedBidMargin, adjustedAskMargin)
+
+			bidMargin, askMargin = adjustedBidMargin, adjustedAskMargin
+
+			labels := s.metricsLabels()
+			labels["model"] = s.spreadModel.Name()
+			labels["side"] = "bid"
+			spreadModelMarginMetrics.With(labels).Set(bidMargin.Float64())
+			labels["side"] = "ask"
+			spreadModelMarginMetrics.With(labels).Set(askMargin.Float64())
+		}
+	}
+
+	if s.avellanedaStoikov != nil {
+		bidMargin, askMargin = s.applyAvellanedaStoikov(bidMargin, askMargin)
+	}
+
+	if s.fundingRateFeed != nil {
+		if fundingRate, ok := s.fundingRateFeed.FundingRate(); ok {
+			adjustment := calculateFundingRateAdjustment(fundingRate, s.FundingRateMargin.Factor, s.FundingRateMargin.MaxAdjustment)
+			bidMargin, askMargin = applyFundingRateAdjustment(bidMargin, askMargin, adjustment)
+			fundingRateMarginMetrics.With(s.metricsLabels()).Set(adjustment.Float64())
+
+			s.logger().Infof("%s funding rate %v applied: bid/ask margin = %v/%v", s.Symbol, fundingRate, bidMargin, askMargin)
+		}
+	}
+
+	if s.InventorySkewFactor.Sign() > 0 {
+		skew := calculateInventorySkew(s.Position.GetBase(), s.getMaxExposurePosition(), s.InventorySkewFactor)
+		bidMargin, askMargin = applyInventorySkew(bidMargin, askMargin, skew)
+		inventorySkewMetrics.With(s.metricsLabels()).Set(skew.Float64())
+
+		s.logger().Infof("%s inventory skew %v applied: bid/ask margin = %v/%v", s.Symbol, skew, bidMargin, askMargin)
+	}
+
+	if s.markoutTracker != nil {
+		bidMargin, askMargin = s.applyToxicityMargins(bidMargin, askMargin)
+	}
+
+	var hedgeFeeRate fixedpoint.Value
+	if s.HedgeCostMargin {
+		hedgeFeeRate = s.hedgeTakerFeeRate(sources)
+	}
+
+	bidLayerQuantity := quoting.LayerQuantity{Quantity: bidQuantity, Multiplier: s.QuantityMultiplier, Scale: s.QuantityScale}
+	askLayerQuantity := quoting.LayerQuantity{Quantity: askQuantity, Multiplier: s.QuantityMultiplier, Scale: s.QuantityScale}
+	quotaLocker := quoting.NewQuotaLocker(makerQuota, hedgeQuota)
+
 	bidPrice := bestBidPrice
 	askPrice := bestAskPrice
 	for i := 0; i < s.NumLayers; i++ {
 		// for maker bid orders
 		if !disableMakerBid {
-			if s.QuantityScale != nil {
-				qf, err := s.QuantityScale.Scale(i + 1)
-				if err != nil {
-					log.WithError(err).Errorf("quantityScale error")
-					return
-				}
-
-				log.Infof("%s scaling bid #%d quantity to %f", s.Symbol, i+1, qf)
-
-				// override the default bid quantity
-				bidQuantity = fixedpoint.NewFromFloat(qf)
+			bidQuantity, err := bidLayerQuantity.At(i)
+			if err != nil {
+				s.logger().WithError(err).Errorf("quantityScale error")
+				return nil, QuoteSkipReasonNoLayers
 			}
 
 			accumulativeBidQuantity = accumulativeBidQuantity.Add(bidQuantity)
 			if s.UseDepthPrice {
 				if s.DepthQuantity.Sign() > 0 {
-					bidPrice = aggregatePrice(sourceBook.SideBook(types.SideTypeBuy), s.DepthQuantity)
+					bidPrice = quoting.AggregatePrice(sourceBook.SideBook(types.SideTypeBuy), s.DepthQuantity)
 				} else {
-					bidPrice = aggregatePrice(sourceBook.SideBook(types.SideTypeBuy), accumulativeBidQuantity)
+					bidPrice = quoting.AggregatePrice(sourceBook.SideBook(types.SideTypeBuy), accumulativeBidQuantity)
 				}
 			}
 
-			bidPrice = bidPrice.Mul(fixedpoint.One.Sub(bidMargin))
-			if i > 0 && pips.Sign() > 0 {
-				bidPrice = bidPrice.Sub(pips.Mul(fixedpoint.NewFromInt(int64(i)).
-					Mul(s.makerMarket.TickSize)))
+			bidPrice = quoting.ApplyMargin(types.SideTypeBuy, bidPrice, bidMargin)
+			bidPrice = quoting.ApplyPips(types.SideTypeBuy, bidPrice, pips, s.makerMarket.TickSize, i)
+
+			layerBidPrice := bidPrice
+			if s.HedgeCostMargin {
+				layerBidPrice = s.applyHedgeCost(sourceBook, types.SideTypeBuy, i+1, bidPrice, accumulativeBidQuantity, hedgeFeeRate)
+			}
+
+			makerBidPrice, hasMakerBook := s.checkMakerBookPrice(types.SideTypeBuy, layerBidPrice)
+			makerBidQuantity := s.jitterQuantity(s.rebalanceQuantity(types.SideTypeBuy, bidQuantity), makerBidPrice)
+			if windingDown {
+				makerBidQuantity = s.makerMarket.TruncateQuantity(fixedpoint.Min(makerBidQuantity, windDownQuantity))
 			}
 
-			if makerQuota.QuoteAsset.Lock(bidQuantity.Mul(bidPrice)) && hedgeQuota.BaseAsset.Lock(bidQuantity) {
+			if hasMakerBook && makerBidQuantity.Compare(s.makerMarket.MinQuantity) >= 0 &&
+				quotaLocker.Lock(types.SideTypeBuy, makerBidPrice, makerBidQuantity) {
 				// if we bought, then we need to sell the base from the hedge session
 				submitOrders = append(submitOrders, types.SubmitOrder{
-					Symbol:      s.Symbol,
-					Type:        types.OrderTypeLimit,
-					Side:        types.SideTypeBuy,
-					Price:       bidPrice,
-					Quantity:    bidQuantity,
-					TimeInForce: types.TimeInForceGTC,
-					GroupID:     s.groupID,
+					Symbol:        s.Symbol,
+					Market:        s.makerMarket,
+					Type:          types.OrderTypeLimit,
+					Side:          types.SideTypeBuy,
+					Price:         makerBidPrice,
+					Quantity:      makerBidQuantity,
+					TimeInForce:   types.TimeInForceGTC,
+					GroupID:       s.groupID,
+					ClientOrderID: s.layerClientOrderID(i + 1),
 				})
 
-				makerQuota.Commit()
-				hedgeQuota.Commit()
+				quotaLocker.Commit()
+				windDownQuantity = windDownQuantity.Sub(makerBidQuantity)
 			} else {
-				makerQuota.Rollback()
-				hedgeQuota.Rollback()
-			}
-
-			if s.QuantityMultiplier.Sign() > 0 {
-				bidQuantity = bidQuantity.Mul(s.QuantityMultiplier)
+				quotaLocker.Rollback()
 			}
 		}
 
 		// for maker ask orders
 		if !disableMakerAsk {
-			if s.QuantityScale != nil {
-				qf, err := s.QuantityScale.Scale(i + 1)
-				if err != nil {
-					log.WithError(err).Errorf("quantityScale error")
-					return
-				}
-
-				log.Infof("%s scaling ask #%d quantity to %f", s.Symbol, i+1, qf)
-
-				// override the default bid quantity
-				askQuantity = fixedpoint.NewFromFloat(qf)
+			askQuantity, err := askLayerQuantity.At(i)
+			if err != nil {
+				s.logger().WithError(err).Errorf("quantityScale error")
+				return nil, QuoteSkipReasonNoLayers
 			}
+
 			accumulativeAskQuantity = accumulativeAskQuantity.Add(askQuantity)
 
 			if s.UseDepthPrice {
 				if s.DepthQuantity.Sign() > 0 {
-					askPrice = aggregatePrice(sourceBook.SideBook(types.SideTypeSell), s.DepthQuantity)
+					askPrice = quoting.AggregatePrice(sourceBook.SideBook(types.SideTypeSell), s.DepthQuantity)
 				} else {
-					askPrice = aggregatePrice(sourceBook.SideBook(types.SideTypeSell), accumulativeAskQuantity)
+					askPrice = quoting.AggregatePrice(sourceBook.SideBook(types.SideTypeSell), accumulativeAskQuantity)
 				}
 			}
 
-			askPrice = askPrice.Mul(fixedpoint.One.Add(askMargin))
-			if i > 0 && pips.Sign() > 0 {
-				askPrice = askPrice.Add(pips.Mul(fixedpoint.NewFromInt(int64(i)).Mul(s.makerMarket.TickSize)))
+			askPrice = quoting.ApplyMargin(types.SideTypeSell, askPrice, askMargin)
+			askPrice = quoting.ApplyPips(types.SideTypeSell, askPrice, pips, s.makerMarket.TickSize, i)
+
+			layerAskPrice := askPrice
+			if s.HedgeCostMargin {
+				layerAskPrice = s.applyHedgeCost(sourceBook, types.SideTypeSell, i+1, askPrice, accumulativeAskQuantity, hedgeFeeRate)
+			}
+
+			makerAskPrice, hasMakerBook := s.checkMakerBookPrice(types.SideTypeSell, layerAskPrice)
+			makerAskQuantity := s.jitterQuantity(s.rebalanceQuantity(types.SideTypeSell, askQuantity), makerAskPrice)
+			if windingDown {
+				makerAskQuantity = s.makerMarket.TruncateQuantity(fixedpoint.Min(makerAskQuantity, windDownQuantity))
 			}
 
-			if makerQuota.BaseAsset.Lock(askQuantity) && hedgeQuota.QuoteAsset.Lock(askQuantity.Mul(askPrice)) {
+			if hasMakerBook && makerAskQuantity.Compare(s.makerMarket.MinQuantity) >= 0 &&
+				quotaLocker.Lock(types.SideTypeSell, makerAskPrice, makerAskQuantity) {
 				// if we bought, then we need to sell the base from the hedge session
 				submitOrders = append(submitOrders, types.SubmitOrder{
-					Symbol:      s.Symbol,
-					Market:      s.makerMarket,
-					Type:        types.OrderTypeLimit,
-					Side:        types.SideTypeSell,
-					Price:       askPrice,
-					Quantity:    askQuantity,
-					TimeInForce: types.TimeInForceGTC,
-					GroupID:     s.groupID,
+					Symbol:        s.Symbol,
+					Market:        s.makerMarket,
+					Type:          types.OrderTypeLimit,
+					Side:          types.SideTypeSell,
+					Price:         makerAskPrice,
+					Quantity:      makerAskQuantity,
+					TimeInForce:   types.TimeInForceGTC,
+					GroupID:       s.groupID,
+					ClientOrderID: s.layerClientOrderID(i + 1),
 				})
-				makerQuota.Commit()
-				hedgeQuota.Commit()
+				quotaLocker.Commit()
+				windDownQuantity = windDownQuantity.Sub(makerAskQuantity)
 			} else {
-				makerQuota.Rollback()
-				hedgeQuota.Rollback()
+				quotaLocker.Rollback()
 			}
+		}
+	}
 
-			if s.QuantityMultiplier.Sign() > 0 {
-				askQuantity = askQuantity.Mul(s.QuantityMultiplier)
-			}
+	if s.makerBorrower != nil && s.MakerBorrow.Mode == MakerBorrowModeAutoBorrow {
+		for i := range submitOrders {
+			submitOrders[i].MarginSideEffect = types.SideEffectTypeMarginBuy
 		}
 	}
 
-	if len(submitOrders) == 0 {
-		log.Warnf("no orders generated")
-		return
+	if s.LayerPrecisionPolicy != "" {
+		var numCollapsed int
+		submitOrders, numCollapsed = adjustLayerPrecision(s.LayerPrecisionPolicy, s.makerMarket, submitOrders)
+		if numCollapsed > 0 {
+			s.logger().Warnf("%s %d layers collapsed into the same price on the maker tick size %v, applied the %q layer precision policy",
+				s.Symbol, numCollapsed, s.makerMarket.TickSize, s.LayerPrecisionPolicy)
+		}
 	}
 
-	makerOrders, err := orderExecutionRouter.SubmitOrdersTo(ctx, s.MakerExchange, submitOrders...)
-	if err != nil {
-		log.WithError(err).Errorf("order error: %s", err.Error())
-		return
+	s.updateQuotedSpreadMetrics(submitOrders)
+	s.recordDecision(bestBidPrice, bestAskPrice, bidMargin, askMargin, submitOrders)
+	if len(submitOrders) == 0 {
+		return nil, QuoteSkipReasonNoLayers
 	}
 
-	s.activeMakerOrders.Add(makerOrders...)
-	s.orderStore.Add(makerOrders...)
+	return submitOrders, QuoteSkipReasonNone
 }
 
 var lastPriceModifier = fixedpoint.NewFromFloat(1.001)
-var minGap = fixedpoint.NewFromFloat(1.02)
 
 func (s *Strategy) Hedge(ctx context.Context, pos fixedpoint.Value) {
 	side := types.SideTypeBuy
@@ -502,89 +997,108 @@ func (s *Strategy) Hedge(ctx context.Context, pos fixedpoint.Value) {
 		side = types.SideTypeSell
 	}
 
-	lastPrice := s.lastPrice
-	sourceBook := s.book.CopyDepth(1)
-	switch side {
+	if s.DryRun {
+		s.logger().Infof("[dry-run] %s skipping hedge %s %v", s.Symbol, side, quantity)
+		return
+	}
 
-	case types.SideTypeBuy:
-		if bestAsk, ok := sourceBook.BestAsk(); ok {
-			lastPrice = bestAsk.Price
-		}
+	// the hedge orders preempt the quote refreshes in the order rate budget of the session
+	ctx = bbgo.WithOrderPriority(ctx, bbgo.OrderPriorityHigh)
 
-	case types.SideTypeSell:
-		if bestBid, ok := sourceBook.BestBid(); ok {
-			lastPrice = bestBid.Price
-		}
+	if s.HedgeSourcePolicy == HedgeSourcePolicySmartRouting && len(s.book.Sources()) > 1 {
+		s.hedgeBySmartRouting(ctx, side, quantity)
+		return
 	}
 
-	notional := quantity.Mul(lastPrice)
-	if notional.Compare(s.sourceMarket.MinNotional) <= 0 {
-		log.Warnf("%s %v less than min notional, skipping hedge", s.Symbol, notional)
+	sourceExchange, _, _ := s.selectHedgeSource(side, quantity)
+
+	covered, err := s.hedgeExecutors[sourceExchange].Hedge(ctx, pos)
+	if err != nil || covered.IsZero() {
 		return
 	}
 
-	// adjust quantity according to the balances
-	account := s.sourceSession.GetAccount()
-	switch side {
-
-	case types.SideTypeBuy:
-		// check quote quantity
-		if quote, ok := account.Balance(s.sourceMarket.QuoteCurrency); ok {
-			if quote.Available.Compare(notional) < 0 {
-				// adjust price to higher 0.1%, so that we can ensure that the order can be executed
-				quantity = bbgo.AdjustQuantityByMaxAmount(quantity, lastPrice.Mul(lastPriceModifier), quote.Available)
-				quantity = s.sourceMarket.TruncateQuantity(quantity)
-			}
-		}
+	s.coverHedge(sourceExchange, side, covered.Abs())
+}
 
-	case types.SideTypeSell:
-		// check quote quantity
-		if base, ok := account.Balance(s.sourceMarket.BaseCurrency); ok {
-			if base.Available.Compare(quantity) < 0 {
-				quantity = base.Available
-			}
-		}
+// hedgeUncoveredPosition hedges the position that is not covered by the hedge trades yet
+func (s *Strategy) hedgeUncoveredPosition(ctx context.Context) {
+	// For positive position and positive covered position:
+	// uncover position = +5 - +3 (covered position) = 2
+	//
+	// For positive position and negative covered position:
+	// uncover position = +5 - (-3) (covered position) = 8
+	//
+	// meaning we bought 5 on MAX and sent buy order with 3 on binance
+	//
+	// For negative position:
+	// uncover position = -5 - -3 (covered position) = -2
+	position := s.Position.GetBase()
+
+	uncoverPosition := position.Sub(s.CoveredPosition)
+
+	// skip hedging while the twap hedge is still running
+	if s.updateTWAPHedge(ctx, uncoverPosition) {
+		return
 	}
 
-	// truncate quantity for the supported precision
-	quantity = s.sourceMarket.TruncateQuantity(quantity)
+	// the covered position might be updated by the finished twap hedge
+	uncoverPosition = position.Sub(s.CoveredPosition)
+	absPos := uncoverPosition.Abs()
+	if s.DisableHedge {
+		return
+	}
 
-	if notional.Compare(s.sourceMarket.MinNotional.Mul(minGap)) <= 0 {
-		log.Warnf("the adjusted amount %v is less than minimal notional %v, skipping hedge", notional, s.sourceMarket.MinNotional)
+	// stop hedging when all the hedge venues are in the degraded mode, the hedge is retried after the venues recover
+	if s.degradedSources() {
+		s.logger().Warnf("%s all the hedge venues are in the degraded mode, skip hedging the uncovered position %v", s.Symbol, uncoverPosition)
 		return
 	}
 
-	if quantity.Compare(s.sourceMarket.MinQuantity.Mul(minGap)) <= 0 {
-		log.Warnf("the adjusted quantity %v is less than minimal quantity %v, skipping hedge", quantity, s.sourceMarket.MinQuantity)
+	// the pending fills are netted out
+	if absPos.Compare(s.sourceMarket.MinQuantity) <= 0 {
+		s.hedgeNetting.Reset()
 		return
 	}
 
-	if s.hedgeErrorRateReservation != nil {
-		if !s.hedgeErrorRateReservation.OK() {
+	if s.HedgeNettingWindow > 0 {
+		if !s.hedgeNetting.Ready(time.Now(), s.HedgeNettingWindow.Duration(), s.MaxUncoveredDuration.Duration()) {
+			s.logger().Infof("%s netting the fills before hedging, uncovered position: %v", s.Symbol, uncoverPosition)
 			return
 		}
-		bbgo.Notify("Hit hedge error rate limit, waiting...")
-		time.Sleep(s.hedgeErrorRateReservation.Delay())
-		s.hedgeErrorRateReservation = nil
-	}
 
-	log.Infof("submitting %s hedge order %s %v", s.Symbol, side.String(), quantity)
-	bbgo.Notify("Submitting %s hedge order %s %v", s.Symbol, side.String(), quantity)
-	orderExecutor := &bbgo.ExchangeOrderExecutor{Session: s.sourceSession}
-	returnOrders, err := orderExecutor.SubmitOrders(ctx, types.SubmitOrder{
-		Market:   s.sourceMarket,
-		Symbol:   s.Symbol,
-		Type:     types.OrderTypeMarket,
-		Side:     side,
-		Quantity: quantity,
-	})
+		s.hedgeNetting.Reset()
+	}
 
-	if err != nil {
-		s.hedgeErrorRateReservation = s.hedgeErrorLimiter.Reserve()
-		log.WithError(err).Errorf("market order submit error: %s", err.Error())
+	s.logger().Infof("%s base position %v coveredPosition: %v uncoverPosition: %v",
+		s.Symbol,
+		position,
+		s.CoveredPosition,
+		uncoverPosition,
+	)
+
+	if s.shouldUseTWAPHedge(absPos) {
+		if err := s.startTWAPHedge(ctx, uncoverPosition.Neg()); err != nil {
+			s.logger().WithError(err).Errorf("%s twap hedge error, falling back to the market order hedge", s.Symbol)
+			s.Hedge(ctx, uncoverPosition.Neg())
+		}
 		return
 	}
 
+	s.Hedge(ctx, uncoverPosition.Neg())
+}
+
+// submitHedgeOrder submits the market hedge order to the source session and updates the covered position
+func (s *Strategy) submitHedgeOrder(ctx context.Context, sourceExchange string, side types.SideType, quantity fixedpoint.Value) error {
+	if _, err := s.hedgeExecutors[sourceExchange].SubmitOrder(ctx, side, quantity); err != nil {
+		return err
+	}
+
+	s.coverHedge(sourceExchange, side, quantity)
+	return nil
+}
+
+// coverHedge adds the submitted hedge quantity to the covered position
+func (s *Strategy) coverHedge(sourceExchange string, side types.SideType, quantity fixedpoint.Value) {
 	// if it's selling, than we should add positive position
 	if side == types.SideTypeSell {
 		s.CoveredPosition = s.CoveredPosition.Add(quantity)
@@ -592,7 +1106,39 @@ func (s *Strategy) Hedge(ctx context.Context, pos fixedpoint.Value) {
 		s.CoveredPosition = s.CoveredPosition.Add(quantity.Neg())
 	}
 
-	s.orderStore.Add(returnOrders...)
+	labels := s.metricsLabels()
+	labels["venue"] = sourceExchange
+	labels["side"] = side.String()
+	hedgeVenueQuantityMetrics.With(labels).Add(quantity.Float64())
+	s.observeHedgeLatency()
+}
+
+// sourceBestPrices returns the best prices of the source exchange, the last price is used if the book side is empty
+func (s *Strategy) sourceBestPrices(sourceExchange string) (bid, ask fixedpoint.Value) {
+	bid, ask = s.lastPrice, s.lastPrice
+	sourceBook := s.book.CopyDepthOf(1, sourceExchange)
+	if bestBid, ok := sourceBook.BestBid(); ok {
+		bid = bestBid.Price
+	}
+
+	if bestAsk, ok := sourceBook.BestAsk(); ok {
+		ask = bestAsk.Price
+	}
+
+	return bid, ask
+}
+
+// newHedgeMarket creates the hedge market of the source exchange for the hedge executors
+func (s *Strategy) newHedgeMarket(sourceExchange string) *common.HedgeMarket {
+	return &common.HedgeMarket{
+		Session:    s.sourceSessions[sourceExchange],
+		Market:     s.sourceMarkets[sourceExchange],
+		OrderStore: s.orderStore,
+		BestPrices: func() (fixedpoint.Value, fixedpoint.Value) {
+			return s.sourceBestPrices(sourceExchange)
+		},
+		SelfTradePreventionMode: s.SelfTradePreventionMode,
+	}
 }
 
 func (s *Strategy) tradeRecover(ctx context.Context) {
@@ -606,23 +1152,37 @@ func (s *Strategy) tradeRecover(ctx context.Context) {
 	tradeScanTicker := time.NewTicker(tradeScanInterval)
 	defer tradeScanTicker.Stop()
 
+	heartbeat := s.registerWorker("tradeRecover", 3*tradeScanInterval)
+	defer heartbeat.Unregister()
+
 	for {
+		heartbeat.Beat()
+
 		select {
 		case <-ctx.Done():
 			return
 
 		case <-tradeScanTicker.C:
-			log.Infof("scanning trades from %s ago...", tradeScanInterval)
+			s.logger().Infof("scanning trades from %s ago...", tradeScanInterval)
 
 			if s.RecoverTrade {
 				startTime := time.Now().Add(-tradeScanInterval).Add(-tradeScanOverlapBufferPeriod)
 
-				if err := s.tradeCollector.Recover(ctx, s.sourceSession.Exchange.(types.ExchangeTradeHistoryService), s.Symbol, startTime); err != nil {
-					log.WithError(err).Errorf("query trades error")
+				for _, sourceSession := range s.sourceSessions {
+					if s.quoteConverter != nil {
+						if err := s.recoverSourceTrades(ctx, sourceSession, startTime); err != nil {
+							s.logger().WithError(err).Errorf("query trades error")
+						}
+						continue
+					}
+
+					if err := s.tradeCollector.Recover(ctx, sourceSession.Exchange.(types.ExchangeTradeHistoryService), s.Symbol, startTime); err != nil {
+						s.logger().WithError(err).Errorf("query trades error")
+					}
 				}
 
 				if err := s.tradeCollector.Recover(ctx, s.makerSession.Exchange.(types.ExchangeTradeHistoryService), s.Symbol, startTime); err != nil {
-					log.WithError(err).Errorf("query trades error")
+					s.logger().WithError(err).Errorf("query trades error")
 				}
 			}
 		}
@@ -630,10 +1190,20 @@ func (s *Strategy) tradeRecover(ctx context.Context) {
 }
 
 func (s *Strategy) Validate() error {
-	if s.Quantity.IsZero() || s.QuantityScale == nil {
+	if s.QuantityByEquityRatio.IsZero() && (s.Quantity.IsZero() || s.QuantityScale == nil) {
 		return errors.New("quantity or quantityScale can not be empty")
 	}
 
+	if s.IndexPriceFeed != nil {
+		if err := s.IndexPriceFeed.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if s.QuantityByEquityRatio.Sign() < 0 || s.QuantityByEquityRatio.Compare(fixedpoint.One) > 0 {
+		return fmt.Errorf("quantityByEquityRatio should be in the range of 0 to 1, got %v", s.QuantityByEquityRatio)
+	}
+
 	if !s.QuantityMultiplier.IsZero() && s.QuantityMultiplier.Sign() < 0 {
 		return errors.New("quantityMultiplier can not be a negative number")
 	}
@@ -642,6 +1212,154 @@ func (s *Strategy) Validate() error {
 		return errors.New("symbol is required")
 	}
 
+	if s.MaxExposurePositionByEquityRatio.Sign() < 0 {
+		return fmt.Errorf("maxExposurePositionByEquityRatio should not be negative, got %v", s.MaxExposurePositionByEquityRatio)
+	}
+
+	if s.InventorySkewFactor.Sign() > 0 && s.MaxExposurePosition.Sign() <= 0 && s.MaxExposurePositionByEquityRatio.Sign() <= 0 {
+		return errors.New("maxExposurePosition or maxExposurePositionByEquityRatio is required for inventorySkewFactor")
+	}
+
+	if s.SpreadModel != nil {
+		if err := s.SpreadModel.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if s.AvellanedaStoikov != nil && s.AvellanedaStoikov.Enabled {
+		if err := s.AvellanedaStoikov.Validate(); err != nil {
+			return err
+		}
+
+		if s.MaxExposurePosition.Sign() <= 0 && s.MaxExposurePositionByEquityRatio.Sign() <= 0 {
+			return errors.New("maxExposurePosition or maxExposurePositionByEquityRatio is required for avellanedaStoikov")
+		}
+	}
+
+	if s.FundingRateMargin != nil {
+		if err := s.FundingRateMargin.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if s.PriceBand != nil && s.PriceBand.Enabled {
+		if err := s.PriceBand.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if s.ToxicityFilter != nil && s.ToxicityFilter.Enabled {
+		if err := s.ToxicityFilter.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if s.ClosePositionOnShutdown != nil && s.ClosePositionOnShutdown.Enabled {
+		if err := s.ClosePositionOnShutdown.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if s.DecisionExport != nil && s.DecisionExport.Enabled {
+		if err := s.DecisionExport.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if s.RegimeAttribution != nil && s.RegimeAttribution.Enabled {
+		if err := s.RegimeAttribution.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if s.QuoteConversion != nil && s.QuoteConversion.Enabled {
+		if err := s.QuoteConversion.Validate(); err != nil {
+			return err
+		}
+
+		if s.Rebalance != nil && s.Rebalance.Enabled {
+			return fmt.Errorf("rebalance is not supported in the quote conversion mode, the quote currencies of the sessions are different")
+		}
+	}
+
+	if s.Rebalance != nil && s.Rebalance.Enabled {
+		if err := s.Rebalance.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if s.HedgeBorrow != nil && s.HedgeBorrow.Enabled {
+		if err := s.HedgeBorrow.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if s.MakerBorrow != nil && s.MakerBorrow.Enabled {
+		if err := s.MakerBorrow.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if s.MaxDrawdown.Sign() < 0 || s.MaxDrawdown.Compare(fixedpoint.One) >= 0 {
+		return fmt.Errorf("maxDrawdown should be between 0 and 1.0, got %v", s.MaxDrawdown)
+	}
+
+	if err := s.UpdateTrigger.Validate(); err != nil {
+		return err
+	}
+
+	if s.HedgeNettingWindow < 0 || s.MaxUncoveredDuration < 0 {
+		return errors.New("hedgeNettingWindow and maxUncoveredDuration should not be negative")
+	}
+
+	if s.PostOnlyMaxReprices < 0 {
+		return fmt.Errorf("postOnlyMaxReprices should not be negative, got %d", s.PostOnlyMaxReprices)
+	}
+
+	if s.QuantityJitter.Sign() < 0 || s.QuantityJitter.Compare(fixedpoint.One) >= 0 {
+		return fmt.Errorf("quantityJitter should be between 0 and 1.0, got %v", s.QuantityJitter)
+	}
+
+	if s.MakerBookCheckTolerance.Sign() < 0 {
+		return errors.New("makerBookCheckTolerance can not be a negative number")
+	}
+
+	if s.BollBandWindow < 0 {
+		return fmt.Errorf("bollBandWindow should not be negative, got %d", s.BollBandWindow)
+	}
+
+	if s.BollBandWidth < 0 {
+		return fmt.Errorf("bollBandWidth should not be negative, got %f", s.BollBandWidth)
+	}
+
+	switch s.RequoteLayerOrder {
+	case "", RequoteLayerOrderDeepestFirst, RequoteLayerOrderTouchFirst:
+	default:
+		return fmt.Errorf("invalid requoteLayerOrder %q", s.RequoteLayerOrder)
+	}
+
+	switch s.LayerPrecisionPolicy {
+	case "", LayerPrecisionPolicySkip, LayerPrecisionPolicyMerge:
+	default:
+		return fmt.Errorf("invalid layerPrecisionPolicy %q", s.LayerPrecisionPolicy)
+	}
+
+	switch s.HedgeSourcePolicy {
+	case "", HedgeSourcePolicyPrimary, HedgeSourcePolicyBestPrice, HedgeSourcePolicySmartRouting:
+	default:
+		return fmt.Errorf("invalid hedgeSourcePolicy %q", s.HedgeSourcePolicy)
+	}
+
+	switch s.PriceSource {
+	case "", PriceSourceBook:
+	case PriceSourceIndex:
+		if len(s.IndexSymbols) == 0 {
+			return errors.New("indexSymbols is required for the index price source")
+		}
+	default:
+		return fmt.Errorf("invalid priceSource %q", s.PriceSource)
+	}
+
 	return nil
 }
 
@@ -659,11 +1377,35 @@ func (s *Strategy) CrossRun(
 		s.BollBandMargin = fixedpoint.NewFromFloat(0.001)
 	}
 
+	if s.BollBandWindow == 0 {
+		s.BollBandWindow = 21
+	}
+
+	if s.BollBandWidth == 0 {
+		s.BollBandWidth = 1.0
+	}
+
 	// configure default values
 	if s.UpdateInterval == 0 {
 		s.UpdateInterval = types.Duration(time.Second)
 	}
 
+	if s.HedgeRoutingDepth == 0 {
+		s.HedgeRoutingDepth = defaultHedgeRoutingDepth
+	}
+
+	if s.PostOnlyMaxReprices == 0 {
+		s.PostOnlyMaxReprices = defaultPostOnlyMaxReprices
+	}
+
+	if s.UpdateTrigger == "" {
+		s.UpdateTrigger = UpdateTriggerTicker
+	}
+
+	if s.MinUpdateInterval == 0 {
+		s.MinUpdateInterval = types.Duration(defaultMinUpdateInterval)
+	}
+
 	if s.HedgeInterval == 0 {
 		s.HedgeInterval = types.Duration(10 * time.Second)
 	}
@@ -688,15 +1430,39 @@ func (s *Strategy) CrossRun(
 		}
 	}
 
-	s.hedgeErrorLimiter = rate.NewLimiter(rate.Every(1*time.Minute), 1)
+	if s.HedgeTWAP != nil {
+		s.HedgeTWAP.Defaults()
+	}
+
+	if s.Rebalance != nil && s.Rebalance.Enabled {
+		s.Rebalance.Defaults()
+		s.rebalancer = newBalanceRebalancer()
+	}
+
+	s.quoteScheduler = defaultQuoteScheduler
 
 	// configure sessions
-	sourceSession, ok := sessions[s.SourceExchange]
-	if !ok {
-		return fmt.Errorf("source exchange session %s is not defined", s.SourceExchange)
+	s.sourceSessions = make(map[string]*bbgo.ExchangeSession)
+	s.sourceMarkets = make(map[string]types.Market)
+	for _, sourceExchange := range s.sourceExchangeNames() {
+		sourceSession, ok := sessions[sourceExchange]
+		if !ok {
+			return fmt.Errorf("source exchange session %s is not defined", sourceExchange)
+		}
+
+		sourceMarket, ok := sourceSession.Market(s.sourceSymbol())
+		if !ok {
+			return fmt.Errorf("source session %s market %s is not defined", sourceExchange, s.sourceSymbol())
+		}
+
+		s.sourceSessions[sourceExchange] = sourceSession
+		s.sourceMarkets[sourceExchange] = sourceMarket
 	}
 
-	s.sourceSession = sourceSession
+	// the first source session is the primary source session
+	primarySource := s.sourceExchangeNames()[0]
+	s.sourceSession = s.sourceSessions[primarySource]
+	s.sourceMarket = s.sourceMarkets[primarySource]
 
 	makerSession, ok := sessions[s.MakerExchange]
 	if !ok {
@@ -705,27 +1471,63 @@ func (s *Strategy) CrossRun(
 
 	s.makerSession = makerSession
 
-	s.sourceMarket, ok = s.sourceSession.Market(s.Symbol)
-	if !ok {
-		return fmt.Errorf("source session market %s is not defined", s.Symbol)
-	}
-
 	s.makerMarket, ok = s.makerSession.Market(s.Symbol)
 	if !ok {
 		return fmt.Errorf("maker session market %s is not defined", s.Symbol)
 	}
 
-	standardIndicatorSet := s.sourceSession.StandardIndicatorSet(s.Symbol)
+	if s.WebSocketOrderEntry && !s.DryRun {
+		bbgo.EnableWebSocketOrderEntry(ctx, s.makerSession.Exchange)
+		for _, sourceSession := range s.sourceSessions {
+			bbgo.EnableWebSocketOrderEntry(ctx, sourceSession.Exchange)
+		}
+	}
+
+	if s.MakerBorrow != nil && s.MakerBorrow.Enabled {
+		if !s.makerSession.Margin {
+			return fmt.Errorf("makerBorrow requires the maker session %s to be a margin session", s.MakerExchange)
+		}
+
+		service, ok := s.makerSession.Exchange.(types.MarginBorrowRepayService)
+		if !ok {
+			return fmt.Errorf("exchange %s does not support margin borrowing", s.makerSession.ExchangeName)
+		}
+
+		s.MakerBorrow.Defaults()
+		s.makerBorrower = &makerBorrower{
+			service:       service,
+			maxBorrowable: make(map[string]fixedpoint.Value),
+		}
+	}
+
+	if s.HedgeBorrow != nil && s.HedgeBorrow.Enabled {
+		s.HedgeBorrow.Defaults()
+		s.hedgeBorrowers = make(map[string]types.MarginBorrowRepayService)
+		for name, sourceSession := range s.sourceSessions {
+			if !sourceSession.Margin {
+				return fmt.Errorf("hedgeBorrow requires the source session %s to be a margin session", name)
+			}
+
+			service, ok := sourceSession.Exchange.(types.MarginBorrowRepayService)
+			if !ok {
+				return fmt.Errorf("exchange %s does not support margin borrowing", sourceSession.ExchangeName)
+			}
+
+			s.hedgeBorrowers[name] = service
+		}
+	}
+
+	standardIndicatorSet := s.sourceSession.StandardIndicatorSet(s.sourceSymbol())
 	if !ok {
 		return fmt.Errorf("%s standard indicator set not found", s.Symbol)
 	}
 
 	s.boll = standardIndicatorSet.BOLL(types.IntervalWindow{
 		Interval: s.BollBandInterval,
-		Window:   21,
-	}, 1.0)
+		Window:   s.BollBandWindow,
+	}, s.BollBandWidth)
 
-	if store, ok := s.sourceSession.MarketDataStore(s.Symbol); ok {
+	if store, ok := s.sourceSession.MarketDataStore(s.sourceSymbol()); ok {
 		if klines, ok2 := store.KLinesOfInterval(s.BollBandInterval); ok2 {
 			for i := 0; i < len(*klines); i++ {
 				s.boll.CalculateAndUpdate((*klines)[0 : i+1])
@@ -733,10 +1535,58 @@ func (s *Strategy) CrossRun(
 		}
 	}
 
+	if s.SpreadModel != nil {
+		s.spreadModel = newSpreadModel(s.SpreadModel, s.sourceSymbol(), s.sourceSession)
+	}
+
+	if s.AvellanedaStoikov != nil && s.AvellanedaStoikov.Enabled {
+		s.avellanedaStoikov = s.newAvellanedaStoikovModel(s.sourceSession)
+	}
+
+	if s.PriceBand != nil && s.PriceBand.Enabled {
+		s.priceBandEMA = s.sourceSession.Indicators(s.sourceSymbol()).EWMA(s.PriceBand.ReferenceEMA)
+	}
+
+	if s.ToxicityFilter != nil && s.ToxicityFilter.Enabled {
+		s.markoutTracker = newMarkoutTracker(s.ToxicityFilter.MarkoutDelay.Duration(), s.ToxicityFilter.Window)
+	}
+
+	if s.DecisionExport != nil && s.DecisionExport.Enabled {
+		s.decisionRecorder = newDecisionRecorder(s.DecisionExport.Dir, s.Symbol, s.DecisionExport.RotateInterval.Duration())
+	}
+
+	if s.RegimeAttribution != nil && s.RegimeAttribution.Enabled {
+		s.regimeAttributor = newRegimeAttributor(s.RegimeAttribution)
+		s.sourceSession.MarketDataStream.OnKLineClosed(types.KLineWith(s.sourceSymbol(), s.RegimeAttribution.Interval, s.closeRegimeSlice))
+	}
+
+	if s.PriceSource == PriceSourceIndex {
+		s.priceSolver = pricesolver.NewSimplePriceResolver(s.sourceSession.Markets())
+		s.priceSolver.BindStream(s.sourceSession.MarketDataStream)
+	}
+
+	if s.QuoteConversion != nil && s.QuoteConversion.Enabled {
+		conversionSolver := pricesolver.NewSimplePriceResolver(s.sourceSession.Markets())
+		conversionSolver.BindStream(s.sourceSession.MarketDataStream)
+		s.quoteConverter = newQuoteConverter(conversionSolver, s.sourceMarket.QuoteCurrency, s.makerMarket.QuoteCurrency)
+	}
+
+	s.detectCoarseMakerTickSize()
+
+	if s.FundingRateMargin != nil && s.FundingRateMargin.Enabled {
+		if feed, ok := s.sourceSession.FundingRateFeed(s.sourceSymbol(), s.FundingRateMargin.UpdateInterval.Duration()); ok {
+			s.fundingRateFeed = feed
+			go s.fundingRateFeed.Run(ctx)
+		} else {
+			s.logger().Warnf("source session %s does not provide the %s funding rate, fundingRateMargin is disabled",
+				s.sourceSession.Name, s.Symbol)
+		}
+	}
+
 	// restore state
 	instanceID := s.InstanceID()
 	s.groupID = util.FNV32(instanceID)
-	log.Infof("using group id %d from fnv(%s)", s.groupID, instanceID)
+	s.logger().Infof("using group id %d from fnv(%s)", s.groupID, instanceID)
 
 	if s.Position == nil {
 		s.Position = types.NewPositionFromMarket(s.makerMarket)
@@ -767,25 +1617,89 @@ func (s *Strategy) CrossRun(
 		})
 	}
 
-	if s.sourceSession.MakerFeeRate.Sign() > 0 || s.sourceSession.TakerFeeRate.Sign() > 0 {
-		s.Position.SetExchangeFeeRate(types.ExchangeName(s.SourceExchange), types.ExchangeFee{
-			MakerFeeRate: s.sourceSession.MakerFeeRate,
-			TakerFeeRate: s.sourceSession.TakerFeeRate,
-		})
+	for sourceExchange, sourceSession := range s.sourceSessions {
+		if sourceSession.MakerFeeRate.Sign() > 0 || sourceSession.TakerFeeRate.Sign() > 0 {
+			s.Position.SetExchangeFeeRate(types.ExchangeName(sourceExchange), types.ExchangeFee{
+				MakerFeeRate: sourceSession.MakerFeeRate,
+				TakerFeeRate: sourceSession.TakerFeeRate,
+			})
+		}
+	}
+
+	s.book = types.NewAggregatedStreamOrderBook(s.sourceSymbol())
+	if s.UpdateTrigger == UpdateTriggerBookChange {
+		s.bookChangeTrigger = newBookChangeTrigger(s.MinUpdateInterval.Duration())
+	}
+
+	for _, sourceExchange := range s.sourceExchangeNames() {
+		sourceBook := s.book.BindStream(sourceExchange, s.sourceSessions[sourceExchange].MarketDataStream)
+		if s.bookChangeTrigger != nil {
+			s.bookChangeTrigger.BindBook(sourceBook)
+		}
+	}
+
+	if s.MakerBookCheck || s.DryRun {
+		s.makerBook = types.NewStreamBook(s.Symbol)
+		s.makerBook.BindStream(s.makerSession.MarketDataStream)
 	}
 
-	s.book = types.NewStreamBook(s.Symbol)
-	s.book.BindStream(s.sourceSession.MarketDataStream)
+	if s.DryRun {
+		s.logger().Warnf("%s xmaker is running in the dry-run mode, no order will be submitted", s.Symbol)
+		s.shadow = newShadowQuoter(s.makerMarket)
+	}
 
 	s.activeMakerOrders = bbgo.NewActiveOrderBook(s.Symbol)
 	s.activeMakerOrders.BindStream(s.makerSession.UserDataStream)
 
+	s.bindDegradedMode()
+	s.bindStrategyController(ctx)
+
+	if s.QuantityByEquityRatio.Sign() > 0 || s.MaxExposurePositionByEquityRatio.Sign() > 0 {
+		if err := s.runAccountEquityUpdater(ctx, sessions); err != nil {
+			return err
+		}
+	}
+
+	if s.PartialFillRequote && !s.DryRun {
+		s.layerTracker = newLayerTracker(s.logger())
+		s.layerTracker.BindStream(s.makerSession.UserDataStream)
+	}
+
 	s.orderStore = core.NewOrderStore(s.Symbol)
-	s.orderStore.BindStream(s.sourceSession.UserDataStream)
+	for _, sourceSession := range s.sourceSessions {
+		if s.quoteConverter != nil {
+			// the order store is filtered by the maker symbol, the orders of the source symbol are handled separately
+			sourceSession.UserDataStream.OnOrderUpdate(func(order types.Order) {
+				if order.Symbol == s.QuoteConversion.SourceSymbol {
+					s.orderStore.HandleOrderUpdate(order)
+				}
+			})
+			continue
+		}
+
+		s.orderStore.BindStream(sourceSession.UserDataStream)
+	}
 	s.orderStore.BindStream(s.makerSession.UserDataStream)
 
+	s.hedgeExecutors = make(map[string]*common.MarketOrderHedgeExecutor)
+	for sourceExchange := range s.sourceSessions {
+		sourceExchange := sourceExchange
+		executor := common.NewMarketOrderHedgeExecutor(s.newHedgeMarket(sourceExchange))
+		executor.BeforeSubmit = func(ctx context.Context, side types.SideType, quantity, price fixedpoint.Value) {
+			// borrow the insufficient asset on the margin source session before adjusting the quantity by the balances
+			if s.hedgeBorrowers != nil {
+				s.borrowForHedge(ctx, sourceExchange, side, quantity, price)
+			}
+		}
+		s.hedgeExecutors[sourceExchange] = executor
+	}
+
 	s.tradeCollector = core.NewTradeCollector(s.Symbol, s.Position, s.orderStore)
 
+	if len(s.FeeSymbols) > 0 {
+		s.bindFeeCurrencyConverter(s.feePriceSolver(ctx))
+	}
+
 	if s.NotifyTrade {
 		s.tradeCollector.OnTrade(func(trade types.Trade, profit, netProfit fixedpoint.Value) {
 			bbgo.Notify(trade)
@@ -794,8 +1708,35 @@ func (s *Strategy) CrossRun(
 
 	s.tradeCollector.OnTrade(func(trade types.Trade, profit, netProfit fixedpoint.Value) {
 		c := trade.PositionChange()
-		if trade.Exchange == s.sourceSession.ExchangeName {
+		if s.decisionRecorder != nil {
+			s.decisionRecorder.AddTrade(trade, s.isSourceExchange(trade.Exchange))
+		}
+
+		if s.isSourceExchange(trade.Exchange) {
 			s.CoveredPosition = s.CoveredPosition.Add(c)
+			s.updateVenueCoveredPosition(trade)
+
+			if s.quoteConverter != nil {
+				s.updateFXPosition(trade)
+			}
+		} else {
+			s.addMakerTradeMetrics(trade)
+			if s.LayerProfitStats {
+				s.addLayerTrade(trade)
+			}
+			s.hedgeNetting.AddFill(time.Now())
+
+			if s.markoutTracker != nil {
+				s.markoutTracker.AddFill(trade.Side, trade.Price, time.Now())
+			}
+
+			if s.regimeAttributor != nil {
+				s.regimeAttributor.AddFill(trade, time.Now())
+			}
+		}
+
+		if s.regimeAttributor != nil && !profit.IsZero() {
+			s.regimeAttributor.AddProfit(profit, netProfit)
 		}
 
 		s.ProfitStats.AddTrade(trade)
@@ -803,7 +1744,7 @@ func (s *Strategy) CrossRun(
 		if profit.Compare(fixedpoint.Zero) == 0 {
 			s.Environment.RecordPosition(s.Position, trade, nil)
 		} else {
-			log.Infof("%s generated profit: %v", s.Symbol, profit)
+			s.logger().Infof("%s generated profit: %v", s.Symbol, profit)
 
 			p := s.Position.NewProfit(trade, profit, netProfit)
 			p.Strategy = ID
@@ -815,13 +1756,28 @@ func (s *Strategy) CrossRun(
 		}
 	})
 
+	if s.makerBorrower != nil && s.MakerBorrow.Mode == MakerBorrowModePostFill {
+		s.tradeCollector.OnTrade(func(trade types.Trade, profit, netProfit fixedpoint.Value) {
+			if !s.isSourceExchange(trade.Exchange) {
+				go s.borrowAfterMakerFill(ctx, trade)
+			}
+		})
+	}
+
 	s.tradeCollector.OnPositionUpdate(func(position *types.Position) {
 		bbgo.Notify(position)
 	})
 	s.tradeCollector.OnRecover(func(trade types.Trade) {
 		bbgo.Notify("Recovered trade", trade)
 	})
-	s.tradeCollector.BindStream(s.sourceSession.UserDataStream)
+	for _, sourceSession := range s.sourceSessions {
+		if s.quoteConverter != nil {
+			sourceSession.UserDataStream.OnTradeUpdate(s.handleSourceTrade)
+			continue
+		}
+
+		s.tradeCollector.BindStream(sourceSession.UserDataStream)
+	}
 	s.tradeCollector.BindStream(s.makerSession.UserDataStream)
 
 	s.stopC = make(chan struct{})
@@ -830,6 +1786,61 @@ func (s *Strategy) CrossRun(
 		go s.tradeRecover(ctx)
 	}
 
+	if s.rebalancer != nil {
+		go s.runBalanceRebalance(ctx)
+	}
+
+	if s.MaxDrawdown.Sign() > 0 {
+		if s.DrawdownHaltDuration == 0 {
+			s.DrawdownHaltDuration = types.Duration(time.Hour)
+		}
+
+		if s.DrawdownHaltAction == "" {
+			s.DrawdownHaltAction = types.HaltActionFlatten
+		}
+
+		if s.DrawdownPriceEMA.Interval == "" {
+			s.DrawdownPriceEMA.Interval = types.Interval1m
+		}
+
+		if s.DrawdownPriceEMA.Window == 0 {
+			s.DrawdownPriceEMA.Window = 30
+		}
+
+		s.drawdownCircuitBreaker = riskcontrol.NewDrawdownCircuitBreakRiskControl(
+			s.Position,
+			s.sourceSession.Indicators(s.sourceSymbol()).EWMA(s.DrawdownPriceEMA),
+			s.MaxDrawdown,
+			s.ProfitStats.ProfitStats,
+			s.DrawdownHaltDuration.Duration())
+	}
+
+	if s.makerBorrower != nil {
+		go s.runMaxBorrowableUpdater(ctx)
+	}
+
+	if s.hedgeBorrowers != nil {
+		go s.runHedgeDebtRepayer(ctx)
+	}
+
+	var stopSourceWatches []func()
+	for source, heartBeat := range s.sourceHeartBeats {
+		stopSourceWatches = append(stopSourceWatches, heartBeat.watch(s.logger(), s.Symbol, source))
+	}
+
+	// bookChangeC is nil in the ticker mode, so that the select case is never chosen
+	var bookChangeC <-chan struct{}
+	if s.bookChangeTrigger != nil {
+		bookChangeC = s.bookChangeTrigger.C
+		go s.bookChangeTrigger.Run(ctx)
+	}
+
+	// partialFillC is nil when the partial fill requote is disabled
+	var partialFillC <-chan partialFill
+	if s.layerTracker != nil {
+		partialFillC = s.layerTracker.C
+	}
+
 	go func() {
 		posTicker := time.NewTicker(util.MillisecondsJitter(s.HedgeInterval.Duration(), 200))
 		defer posTicker.Stop()
@@ -840,56 +1851,69 @@ func (s *Strategy) CrossRun(
 		reportTicker := time.NewTicker(time.Hour)
 		defer reportTicker.Stop()
 
+		heartbeat := s.registerWorker("quoteWorker", s.quoteWorkerTimeout())
+		defer heartbeat.Unregister()
+
 		defer func() {
 			if err := s.activeMakerOrders.GracefulCancel(context.Background(), s.makerSession.Exchange); err != nil {
-				log.WithError(err).Errorf("can not cancel %s orders", s.Symbol)
+				s.logger().WithError(err).Errorf("can not cancel %s orders", s.Symbol)
+			}
+
+			if s.twapHedge != nil {
+				shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), 30*time.Second)
+				s.twapHedge.Shutdown(shutdownCtx)
+				cancelShutdown()
 			}
 		}()
 
 		for {
+			heartbeat.Beat()
+
 			select {
 
 			case <-s.stopC:
-				log.Warnf("%s maker goroutine stopped, due to the stop signal", s.Symbol)
+				s.logger().Warnf("%s maker goroutine stopped, due to the stop signal", s.Symbol)
 				return
 
 			case <-ctx.Done():
-				log.Warnf("%s maker goroutine stopped, due to the cancelled context", s.Symbol)
+				s.logger().Warnf("%s maker goroutine stopped, due to the cancelled context", s.Symbol)
 				return
 
 			case <-quoteTicker.C:
+				if s.quotingPaused(ctx) {
+					break
+				}
+
 				s.updateQuote(ctx, orderExecutionRouter)
+				s.updateQuoteUptimeMetrics()
 
-			case <-reportTicker.C:
-				bbgo.Notify(s.ProfitStats)
+			case <-bookChangeC:
+				if s.quotingPaused(ctx) {
+					break
+				}
 
-			case <-posTicker.C:
-				// For positive position and positive covered position:
-				// uncover position = +5 - +3 (covered position) = 2
-				//
-				// For positive position and negative covered position:
-				// uncover position = +5 - (-3) (covered position) = 8
-				//
-				// meaning we bought 5 on MAX and sent buy order with 3 on binance
-				//
-				// For negative position:
-				// uncover position = -5 - -3 (covered position) = -2
-				s.tradeCollector.Process()
+				s.updateQuote(ctx, orderExecutionRouter)
+				s.updateQuoteUptimeMetrics()
+
+			case fill := <-partialFillC:
+				if s.quotingPaused(ctx) {
+					break
+				}
 
-				position := s.Position.GetBase()
+				s.handlePartialFill(ctx, orderExecutionRouter, fill)
 
-				uncoverPosition := position.Sub(s.CoveredPosition)
-				absPos := uncoverPosition.Abs()
-				if !s.DisableHedge && absPos.Compare(s.sourceMarket.MinQuantity) > 0 {
-					log.Infof("%s base position %v coveredPosition: %v uncoverPosition: %v",
-						s.Symbol,
-						position,
-						s.CoveredPosition,
-						uncoverPosition,
-					)
+			case <-reportTicker.C:
+				bbgo.Notify(s.ProfitStats)
 
-					s.Hedge(ctx, uncoverPosition.Neg())
+			case <-posTicker.C:
+				// the shadow fills are hedged in the shadow quoter
+				if s.DryRun {
+					break
 				}
+
+				s.tradeCollector.Process()
+				s.hedgeUncoveredPosition(ctx)
+				s.checkWindDown(ctx)
 			}
 		}
 	}()
@@ -899,6 +1923,10 @@ func (s *Strategy) CrossRun(
 
 		close(s.stopC)
 
+		for _, stop := range stopSourceWatches {
+			stop()
+		}
+
 		// wait for the quoter to stop
 		time.Sleep(s.UpdateInterval.Duration())
 
@@ -906,11 +1934,36 @@ func (s *Strategy) CrossRun(
 		defer cancelShutdown()
 
 		if err := s.activeMakerOrders.GracefulCancel(shutdownCtx, s.makerSession.Exchange); err != nil {
-			log.WithError(err).Errorf("graceful cancel error")
+			s.logger().WithError(err).Errorf("graceful cancel error")
+		}
+
+		if s.ClosePositionOnShutdown != nil && s.ClosePositionOnShutdown.Enabled && !s.DryRun {
+			s.closePositionOnShutdown(shutdownCtx)
 		}
 
 		bbgo.Notify("%s: %s position", ID, s.Symbol, s.Position)
+
+		if s.quoteConverter != nil {
+			bbgo.Notify("%s: %s %s FX position %v", ID, s.Symbol, s.quoteConverter.sourceQuote, s.FXPosition)
+		}
+
+		if s.decisionRecorder != nil {
+			if err := s.decisionRecorder.Close(); err != nil {
+				s.logger().WithError(err).Errorf("%s unable to close the decision export file", s.Symbol)
+			}
+		}
+
+		if s.shadow != nil {
+			s.shadow.mu.Lock()
+			bbgo.Notify("%s: %s dry-run shadow position %s, net profit %v", ID, s.Symbol, s.shadow.position, s.shadow.netProfit)
+			s.shadow.mu.Unlock()
+		}
 	})
 
 	return nil
 }
+
+// logger returns the logger of the strategy instance, the instance field is used for filtering the logs of the instance
+func (s *Strategy) logger() logrus.FieldLogger {
+	return log.WithField("instance", s.InstanceID())
+}
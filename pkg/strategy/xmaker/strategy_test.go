@@ -1,36 +1,179 @@
 package xmaker
 
 import (
+	"context"
+	"encoding/json"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/bbgo"
 	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/strategy/common"
 	"github.com/c9s/bbgo/pkg/types"
-	"github.com/stretchr/testify/assert"
 )
 
-func Test_aggregatePrice(t *testing.T) {
-	bids := types.PriceVolumeSlice{
-		{
-			Price:  fixedpoint.NewFromFloat(1000.0),
-			Volume: fixedpoint.NewFromFloat(1.0),
-		},
-		{
-			Price:  fixedpoint.NewFromFloat(1200.0),
-			Volume: fixedpoint.NewFromFloat(1.0),
-		},
-		{
-			Price:  fixedpoint.NewFromFloat(1400.0),
-			Volume: fixedpoint.NewFromFloat(1.0),
+func TestStrategy_checkMakerBookPrice(t *testing.T) {
+	s := &Strategy{
+		Symbol:         "BTCUSDT",
+		MakerBookCheck: true,
+		makerMarket: types.Market{
+			Symbol:   "BTCUSDT",
+			TickSize: fixedpoint.NewFromFloat(0.01),
 		},
+		makerBook: types.NewStreamBook("BTCUSDT"),
+	}
+
+	_, ok := s.checkMakerBookPrice(types.SideTypeBuy, fixedpoint.NewFromFloat(100.0))
+	assert.False(t, ok, "should return false when the maker book is empty")
+
+	s.makerBook.Load(types.SliceOrderBook{
+		Symbol: "BTCUSDT",
+		Bids:   types.PriceVolumeSlice{{Price: fixedpoint.NewFromFloat(99.0), Volume: fixedpoint.One}},
+		Asks:   types.PriceVolumeSlice{{Price: fixedpoint.NewFromFloat(101.0), Volume: fixedpoint.One}},
+	})
+
+	price, ok := s.checkMakerBookPrice(types.SideTypeBuy, fixedpoint.NewFromFloat(100.0))
+	assert.True(t, ok)
+	assert.Equal(t, fixedpoint.NewFromFloat(100.0), price)
+
+	price, ok = s.checkMakerBookPrice(types.SideTypeBuy, fixedpoint.NewFromFloat(102.0))
+	assert.True(t, ok)
+	assert.Equal(t, fixedpoint.NewFromFloat(100.99), price)
+
+	price, ok = s.checkMakerBookPrice(types.SideTypeSell, fixedpoint.NewFromFloat(98.0))
+	assert.True(t, ok)
+	assert.Equal(t, fixedpoint.NewFromFloat(99.01), price)
+
+	// within the tolerance
+	s.MakerBookCheckTolerance = fixedpoint.NewFromFloat(0.02)
+	price, ok = s.checkMakerBookPrice(types.SideTypeBuy, fixedpoint.NewFromFloat(102.0))
+	assert.True(t, ok)
+	assert.Equal(t, fixedpoint.NewFromFloat(102.0), price)
+}
+
+func TestStrategy_Validate_bollBand(t *testing.T) {
+	s := &Strategy{
+		Symbol:                "BTCUSDT",
+		QuantityByEquityRatio: fixedpoint.NewFromFloat(0.1),
+	}
+	assert.NoError(t, s.Validate())
+
+	s.BollBandWindow = -1
+	assert.Error(t, s.Validate())
+
+	s.BollBandWindow = 20
+	s.BollBandWidth = -2.0
+	assert.Error(t, s.Validate())
+
+	s.BollBandWidth = 2.0
+	assert.NoError(t, s.Validate())
+}
+
+func TestStrategy_UnmarshalJSON_enums(t *testing.T) {
+	var s Strategy
+	assert.NoError(t, json.Unmarshal([]byte(`{"hedgeSourcePolicy":"BestPrice","drawdownHaltAction":"cancel"}`), &s))
+	assert.Equal(t, HedgeSourcePolicyBestPrice, s.HedgeSourcePolicy)
+	assert.Equal(t, types.HaltActionCancel, s.DrawdownHaltAction)
+
+	err := json.Unmarshal([]byte(`{"hedgeSourcePolicy":"smartRoute"}`), &s)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), `did you mean "smartRouting"?`)
 	}
 
-	aggregatedPrice1 := aggregatePrice(bids, fixedpoint.NewFromFloat(0.5))
-	assert.Equal(t, fixedpoint.NewFromFloat(1000.0), aggregatedPrice1)
+	s = Strategy{}
+	assert.NoError(t, json.Unmarshal([]byte(`{
+		"spreadModel": {"type": "realizedvol"},
+		"makerBorrow": {"mode": "postFill"},
+		"rebalance": {"mode": "transfer"},
+		"closePositionOnShutdown": {"orderType": "aggressivelimit"}
+	}`), &s))
+	assert.Equal(t, SpreadModelTypeRealizedVol, s.SpreadModel.Type)
+	assert.Equal(t, MakerBorrowModePostFill, s.MakerBorrow.Mode)
+	assert.Equal(t, RebalanceModeTransfer, s.Rebalance.Mode)
+	assert.Equal(t, ShutdownCloseOrderTypeAggressiveLimit, s.ClosePositionOnShutdown.OrderType)
 
-	aggregatedPrice2 := aggregatePrice(bids, fixedpoint.NewFromInt(1))
-	assert.Equal(t, fixedpoint.NewFromFloat(1000.0), aggregatedPrice2)
+	for config, suggestion := range map[string]string{
+		`{"spreadModel": {"type": "atrr"}}`:                              `did you mean "atr"?`,
+		`{"makerBorrow": {"mode": "autoborow"}}`:                         `did you mean "autoBorrow"?`,
+		`{"rebalance": {"mode": "quotes"}}`:                              `did you mean "quote"?`,
+		`{"closePositionOnShutdown": {"orderType": "aggressiveLimits"}}`: `did you mean "aggressiveLimit"?`,
+	} {
+		err := json.Unmarshal([]byte(config), &Strategy{})
+		if assert.Error(t, err, config) {
+			assert.Contains(t, err.Error(), suggestion, config)
+		}
+	}
+}
+
+// priorityOrderExecutor records the order priority of the submitted orders
+type priorityOrderExecutor struct {
+	priorities []bbgo.OrderPriority
+}
+
+func (e *priorityOrderExecutor) SubmitOrders(ctx context.Context, orders ...types.SubmitOrder) (types.OrderSlice, error) {
+	e.priorities = append(e.priorities, bbgo.OrderPriorityFromContext(ctx))
+
+	var createdOrders types.OrderSlice
+	for _, o := range orders {
+		createdOrders = append(createdOrders, types.Order{SubmitOrder: o})
+	}
+
+	return createdOrders, nil
+}
+
+func (e *priorityOrderExecutor) CancelOrders(ctx context.Context, orders ...types.Order) error {
+	return nil
+}
+
+func TestStrategy_Hedge_Priority(t *testing.T) {
+	market := types.Market{
+		Symbol:        "BTCUSDT",
+		BaseCurrency:  "BTC",
+		QuoteCurrency: "USDT",
+		MinQuantity:   fixedpoint.NewFromFloat(0.0001),
+		MinNotional:   fixedpoint.NewFromFloat(10.0),
+		StepSize:      fixedpoint.NewFromFloat(0.0001),
+	}
+
+	session := newTestSessionWithBalances(types.BalanceMap{
+		"BTC":  {Currency: "BTC", Available: fixedpoint.NewFromFloat(1.0)},
+		"USDT": {Currency: "USDT", Available: fixedpoint.NewFromFloat(100000.0)},
+	})
+	session.Name = "binance"
+
+	book := types.NewAggregatedStreamOrderBook("BTCUSDT")
+	book.AddSource("binance", types.NewStreamBook("BTCUSDT"))
+
+	orderExecutor := &priorityOrderExecutor{}
+	executor := common.NewMarketOrderHedgeExecutor(&common.HedgeMarket{
+		Session:       session,
+		Market:        market,
+		OrderExecutor: orderExecutor,
+		BestPrices: func() (fixedpoint.Value, fixedpoint.Value) {
+			return fixedpoint.NewFromFloat(30000.0), fixedpoint.NewFromFloat(30001.0)
+		},
+	})
+
+	var beforeSubmitPriority bbgo.OrderPriority
+	executor.BeforeSubmit = func(ctx context.Context, side types.SideType, quantity, price fixedpoint.Value) {
+		beforeSubmitPriority = bbgo.OrderPriorityFromContext(ctx)
+	}
+
+	s := &Strategy{
+		Symbol:         "BTCUSDT",
+		SourceExchange: "binance",
+		book:           book,
+		sourceSessions: map[string]*bbgo.ExchangeSession{"binance": session},
+		sourceMarkets:  map[string]types.Market{"binance": market},
+		hedgeExecutors: map[string]*common.MarketOrderHedgeExecutor{"binance": executor},
+	}
 
-	aggregatedPrice3 := aggregatePrice(bids, fixedpoint.NewFromInt(2))
-	assert.Equal(t, fixedpoint.NewFromFloat(1100.0), aggregatedPrice3)
+	// the quote context of the caller is low priority, the hedge still runs in the high priority
+	ctx := bbgo.WithOrderPriority(context.Background(), bbgo.OrderPriorityLow)
+	s.Hedge(ctx, fixedpoint.NewFromFloat(-0.1))
 
+	assert.Equal(t, bbgo.OrderPriorityHigh, beforeSubmitPriority)
+	assert.Equal(t, []bbgo.OrderPriority{bbgo.OrderPriorityHigh}, orderExecutor.priorities)
+	assert.Equal(t, "0.1", s.CoveredPosition.String())
 }
@@ -0,0 +1,266 @@
+package xmaker
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/bbgo"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// ToxicityFilter tracks the markouts of the maker fills, the source mid-price move MarkoutDelay after the fill,
+// and widens the margin or pauses the side that is being adversely selected by the informed taker flow.
+type ToxicityFilter struct {
+	Enabled bool `json:"enabled"`
+
+	// MarkoutDelay is the delay after the fill for measuring the markout, defaults to 10s
+	MarkoutDelay types.Duration `json:"markoutDelay"`
+
+	// Window is the number of the recent markouts of each side used for the average markout, defaults to 20
+	Window int `json:"window"`
+
+	// WidenThreshold is the average adverse markout ratio that starts widening the margin of the side, defaults to 0.0005
+	WidenThreshold fixedpoint.Value `json:"widenThreshold"`
+
+	// WidenFactor scales the average adverse markout into the margin added to the side, defaults to 1.0
+	WidenFactor fixedpoint.Value `json:"widenFactor"`
+
+	// PauseThreshold is the average adverse markout ratio that pauses the side, defaults to 0.002
+	PauseThreshold fixedpoint.Value `json:"pauseThreshold"`
+
+	// PauseDuration is how long the side is paused, defaults to 5m
+	PauseDuration types.Duration `json:"pauseDuration"`
+}
+
+func (f *ToxicityFilter) Defaults() {
+	if f.MarkoutDelay == 0 {
+		f.MarkoutDelay = types.Duration(10 * time.Second)
+	}
+
+	if f.Window == 0 {
+		f.Window = 20
+	}
+
+	if f.WidenThreshold.IsZero() {
+		f.WidenThreshold = fixedpoint.NewFromFloat(0.0005)
+	}
+
+	if f.WidenFactor.IsZero() {
+		f.WidenFactor = fixedpoint.One
+	}
+
+	if f.PauseThreshold.IsZero() {
+		f.PauseThreshold = fixedpoint.NewFromFloat(0.002)
+	}
+
+	if f.PauseDuration == 0 {
+		f.PauseDuration = types.Duration(5 * time.Minute)
+	}
+}
+
+func (f *ToxicityFilter) Validate() error {
+	if f.MarkoutDelay < 0 || f.PauseDuration < 0 {
+		return fmt.Errorf("toxicityFilter markoutDelay and pauseDuration should not be negative")
+	}
+
+	if f.Window < 0 {
+		return fmt.Errorf("toxicityFilter window should not be negative, got %d", f.Window)
+	}
+
+	if f.WidenThreshold.Sign() < 0 || f.WidenFactor.Sign() < 0 || f.PauseThreshold.Sign() < 0 {
+		return fmt.Errorf("toxicityFilter widenThreshold, widenFactor and pauseThreshold should not be negative")
+	}
+
+	return nil
+}
+
+type pendingMarkout struct {
+	side  types.SideType
+	price fixedpoint.Value
+	time  time.Time
+}
+
+// markoutTracker measures the markouts of the maker fills against the source mid-price.
+// The markout is positive when the price moves in favor of the fill, e.g. the price goes up after a maker buy.
+type markoutTracker struct {
+	mu sync.Mutex
+
+	delay  time.Duration
+	window int
+
+	pending  []pendingMarkout
+	markouts map[types.SideType][]fixedpoint.Value
+
+	pausedUntil map[types.SideType]time.Time
+
+	markoutCallbacks []func(side types.SideType, markout fixedpoint.Value)
+}
+
+func newMarkoutTracker(delay time.Duration, window int) *markoutTracker {
+	return &markoutTracker{
+		delay:       delay,
+		window:      window,
+		markouts:    make(map[types.SideType][]fixedpoint.Value),
+		pausedUntil: make(map[types.SideType]time.Time),
+	}
+}
+
+// OnMarkout registers the callback of the measured markouts, the callbacks are called with the lock held
+func (t *markoutTracker) OnMarkout(cb func(side types.SideType, markout fixedpoint.Value)) {
+	t.mu.Lock()
+	t.markoutCallbacks = append(t.markoutCallbacks, cb)
+	t.mu.Unlock()
+}
+
+// AddFill records the maker fill to be marked out after the delay
+func (t *markoutTracker) AddFill(side types.SideType, price fixedpoint.Value, fillTime time.Time) {
+	if price.Sign() <= 0 {
+		return
+	}
+
+	t.mu.Lock()
+	t.pending = append(t.pending, pendingMarkout{side: side, price: price, time: fillTime})
+	t.mu.Unlock()
+}
+
+// Update marks out the pending fills that are older than the delay with the given mid-price
+func (t *markoutTracker) Update(now time.Time, midPrice fixedpoint.Value) {
+	if midPrice.Sign() <= 0 {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var i int
+	for ; i < len(t.pending); i++ {
+		fill := t.pending[i]
+		if now.Sub(fill.time) < t.delay {
+			break
+		}
+
+		markout := midPrice.Sub(fill.price).Div(fill.price)
+		if fill.side == types.SideTypeSell {
+			markout = markout.Neg()
+		}
+
+		markouts := append(t.markouts[fill.side], markout)
+		if len(markouts) > t.window {
+			markouts = markouts[len(markouts)-t.window:]
+		}
+
+		t.markouts[fill.side] = markouts
+
+		for _, cb := range t.markoutCallbacks {
+			cb(fill.side, markout)
+		}
+	}
+
+	t.pending = t.pending[i:]
+}
+
+// AverageMarkout returns the average markout of the recent fills of the side
+func (t *markoutTracker) AverageMarkout(side types.SideType) (fixedpoint.Value, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	markouts := t.markouts[side]
+	if len(markouts) == 0 {
+		return fixedpoint.Zero, false
+	}
+
+	sum := fixedpoint.Zero
+	for _, markout := range markouts {
+		sum = sum.Add(markout)
+	}
+
+	return sum.Div(fixedpoint.NewFromInt(int64(len(markouts)))), true
+}
+
+// Pause pauses the side until the given time, the markouts of the side are cleared,
+// so that the side is judged by the new fills after it's resumed.
+func (t *markoutTracker) Pause(side types.SideType, until time.Time) {
+	t.mu.Lock()
+	t.pausedUntil[side] = until
+	delete(t.markouts, side)
+	t.mu.Unlock()
+}
+
+func (t *markoutTracker) IsPaused(side types.SideType, now time.Time) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return now.Before(t.pausedUntil[side])
+}
+
+// toxicityMargin converts the average markout into the extra margin of the side,
+// it returns zero when the adverse markout is below the threshold.
+func toxicityMargin(averageMarkout, threshold, factor fixedpoint.Value) fixedpoint.Value {
+	adverse := averageMarkout.Neg()
+	if adverse.Compare(threshold) < 0 {
+		return fixedpoint.Zero
+	}
+
+	return adverse.Mul(factor)
+}
+
+// updateToxicity marks out the pending fills with the source mid-price and pauses the adversely selected sides,
+// it returns whether the bid and the ask sides are paused.
+func (s *Strategy) updateToxicity(now time.Time, midPrice fixedpoint.Value) (pauseBid, pauseAsk bool) {
+	if s.markoutTracker == nil {
+		return false, false
+	}
+
+	s.markoutTracker.Update(now, midPrice)
+
+	for _, side := range []types.SideType{types.SideTypeBuy, types.SideTypeSell} {
+		labels := s.metricsLabels()
+		labels["side"] = side.String()
+
+		if averageMarkout, ok := s.markoutTracker.AverageMarkout(side); ok {
+			markoutMetrics.With(labels).Set(averageMarkout.Float64())
+
+			if averageMarkout.Neg().Compare(s.ToxicityFilter.PauseThreshold) >= 0 {
+				s.logger().Warnf("%s %s maker fills are adversely selected, average markout %v exceeds the pause threshold %v, pausing the side for %s",
+					s.Symbol, side, averageMarkout, s.ToxicityFilter.PauseThreshold, s.ToxicityFilter.PauseDuration.Duration())
+				bbgo.Notify("%s: %s %s quoting paused, the average markout %v of the maker fills is toxic",
+					ID, s.Symbol, side, averageMarkout)
+
+				s.markoutTracker.Pause(side, now.Add(s.ToxicityFilter.PauseDuration.Duration()))
+			}
+		}
+
+		paused := s.markoutTracker.IsPaused(side, now)
+		if paused {
+			toxicityPausedMetrics.With(labels).Set(1.0)
+		} else {
+			toxicityPausedMetrics.With(labels).Set(0.0)
+		}
+
+		if side == types.SideTypeBuy {
+			pauseBid = paused
+		} else {
+			pauseAsk = paused
+		}
+	}
+
+	return pauseBid, pauseAsk
+}
+
+// applyToxicityMargins widens the bid/ask margins by the adverse markouts of the maker fills
+func (s *Strategy) applyToxicityMargins(bidMargin, askMargin fixedpoint.Value) (fixedpoint.Value, fixedpoint.Value) {
+	if s.markoutTracker == nil {
+		return bidMargin, askMargin
+	}
+
+	if averageMarkout, ok := s.markoutTracker.AverageMarkout(types.SideTypeBuy); ok {
+		bidMargin = bidMargin.Add(toxicityMargin(averageMarkout, s.ToxicityFilter.WidenThreshold, s.ToxicityFilter.WidenFactor))
+	}
+
+	if averageMarkout, ok := s.markoutTracker.AverageMarkout(types.SideTypeSell); ok {
+		askMargin = askMargin.Add(toxicityMargin(averageMarkout, s.ToxicityFilter.WidenThreshold, s.ToxicityFilter.WidenFactor))
+	}
+
+	return bidMargin, askMargin
+}
@@ -0,0 +1,71 @@
+package xmaker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestToxicityFilter_Defaults(t *testing.T) {
+	filter := &ToxicityFilter{Enabled: true}
+	filter.Defaults()
+
+	assert.Equal(t, 10*time.Second, filter.MarkoutDelay.Duration())
+	assert.Equal(t, 20, filter.Window)
+	assert.Equal(t, "0.0005", filter.WidenThreshold.String())
+	assert.Equal(t, "0.002", filter.PauseThreshold.String())
+	assert.NoError(t, filter.Validate())
+
+	filter.PauseThreshold = fixedpoint.NewFromFloat(-0.1)
+	assert.Error(t, filter.Validate())
+}
+
+func TestMarkoutTracker(t *testing.T) {
+	number := fixedpoint.MustNewFromString
+	now := time.Now()
+
+	tracker := newMarkoutTracker(10*time.Second, 2)
+	tracker.AddFill(types.SideTypeBuy, number("100"), now)
+	tracker.AddFill(types.SideTypeSell, number("100"), now)
+	tracker.AddFill(types.SideTypeBuy, number("100"), now.Add(5*time.Second))
+
+	// not marked out before the delay
+	tracker.Update(now.Add(5*time.Second), number("99"))
+	_, ok := tracker.AverageMarkout(types.SideTypeBuy)
+	assert.False(t, ok)
+
+	// the price drops 1% after the fills, the buy is adversely selected and the sell is in favor
+	tracker.Update(now.Add(10*time.Second), number("99"))
+	markout, ok := tracker.AverageMarkout(types.SideTypeBuy)
+	assert.True(t, ok)
+	assert.Equal(t, "-0.01", markout.String())
+
+	markout, ok = tracker.AverageMarkout(types.SideTypeSell)
+	assert.True(t, ok)
+	assert.Equal(t, "0.01", markout.String())
+
+	// the second buy fill is marked out later
+	tracker.Update(now.Add(15*time.Second), number("101"))
+	markout, _ = tracker.AverageMarkout(types.SideTypeBuy)
+	assert.Equal(t, "0", markout.String())
+
+	tracker.Pause(types.SideTypeBuy, now.Add(time.Minute))
+	assert.True(t, tracker.IsPaused(types.SideTypeBuy, now.Add(30*time.Second)))
+	assert.False(t, tracker.IsPaused(types.SideTypeBuy, now.Add(2*time.Minute)))
+	assert.False(t, tracker.IsPaused(types.SideTypeSell, now))
+
+	_, ok = tracker.AverageMarkout(types.SideTypeBuy)
+	assert.False(t, ok, "the markouts should be cleared after pausing")
+}
+
+func TestToxicityMargin(t *testing.T) {
+	number := fixedpoint.MustNewFromString
+
+	assert.Equal(t, "0", toxicityMargin(number("-0.0001"), number("0.0005"), fixedpoint.One).String())
+	assert.Equal(t, "0", toxicityMargin(number("0.001"), number("0.0005"), fixedpoint.One).String())
+	assert.Equal(t, "0.002", toxicityMargin(number("-0.001"), number("0.0005"), Two).String())
+}
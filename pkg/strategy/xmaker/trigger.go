@@ -0,0 +1,97 @@
+package xmaker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// UpdateTrigger defines what drives the quote updates
+type UpdateTrigger string
+
+const (
+	// UpdateTriggerTicker updates the quotes on every UpdateInterval
+	UpdateTriggerTicker UpdateTrigger = "ticker"
+
+	// UpdateTriggerBookChange updates the quotes when the source order book changes,
+	// the updates are throttled by MinUpdateInterval, and UpdateInterval is used as the max interval between updates.
+	UpdateTriggerBookChange UpdateTrigger = "bookChange"
+)
+
+const defaultMinUpdateInterval = 200 * time.Millisecond
+
+func (t UpdateTrigger) Validate() error {
+	switch t {
+	case "", UpdateTriggerTicker, UpdateTriggerBookChange:
+		return nil
+	}
+
+	return fmt.Errorf("invalid update trigger %q", t)
+}
+
+// bookChangeTrigger coalesces the order book change events into the quote update signals,
+// two signals are at least minInterval apart.
+type bookChangeTrigger struct {
+	minInterval time.Duration
+
+	changeC chan struct{}
+
+	// C receives the quote update signals
+	C chan struct{}
+}
+
+func newBookChangeTrigger(minInterval time.Duration) *bookChangeTrigger {
+	return &bookChangeTrigger{
+		minInterval: minInterval,
+		changeC:     make(chan struct{}, 1),
+		C:           make(chan struct{}, 1),
+	}
+}
+
+// BindBook registers the change callbacks of the stream order book
+func (t *bookChangeTrigger) BindBook(book *types.StreamOrderBook) {
+	book.OnSnapshot(func(types.SliceOrderBook) { t.Notify() })
+	book.OnUpdate(func(types.SliceOrderBook) { t.Notify() })
+}
+
+// Notify records a book change without blocking the stream handler
+func (t *bookChangeTrigger) Notify() {
+	select {
+	case t.changeC <- struct{}{}:
+	default:
+	}
+}
+
+func (t *bookChangeTrigger) Run(ctx context.Context) {
+	var lastSignalTime time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-t.changeC:
+		}
+
+		if wait := t.minInterval - time.Since(lastSignalTime); wait > 0 {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(wait):
+			}
+		}
+
+		// the changes during the wait are covered by this signal since the quote reads the latest book
+		select {
+		case <-t.changeC:
+		default:
+		}
+
+		lastSignalTime = time.Now()
+		select {
+		case t.C <- struct{}{}:
+		default:
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package xmaker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestUpdateTrigger_Validate(t *testing.T) {
+	assert.NoError(t, UpdateTrigger("").Validate())
+	assert.NoError(t, UpdateTriggerTicker.Validate())
+	assert.NoError(t, UpdateTriggerBookChange.Validate())
+	assert.Error(t, UpdateTrigger("depth").Validate())
+}
+
+func TestBookChangeTrigger(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	minInterval := 100 * time.Millisecond
+	trigger := newBookChangeTrigger(minInterval)
+
+	book := types.NewStreamBook("BTCUSDT")
+	stream := &types.StandardStream{}
+	book.BindStream(stream)
+	trigger.BindBook(book)
+
+	go trigger.Run(ctx)
+
+	stream.EmitBookSnapshot(types.SliceOrderBook{Symbol: "BTCUSDT"})
+
+	select {
+	case <-trigger.C:
+	case <-time.After(time.Second):
+		t.Fatal("expected a quote update signal after the book snapshot")
+	}
+
+	// the burst of the book updates is coalesced into one signal after the min interval
+	for i := 0; i < 10; i++ {
+		stream.EmitBookUpdate(types.SliceOrderBook{Symbol: "BTCUSDT"})
+	}
+
+	select {
+	case <-trigger.C:
+	case <-time.After(time.Second):
+		t.Fatal("expected a quote update signal after the book updates")
+	}
+
+	select {
+	case <-trigger.C:
+		t.Fatal("unexpected quote update signal")
+	case <-time.After(3 * minInterval):
+	}
+
+	// updates of the other symbols are ignored
+	stream.EmitBookUpdate(types.SliceOrderBook{Symbol: "ETHUSDT"})
+	select {
+	case <-trigger.C:
+		t.Fatal("unexpected quote update signal")
+	case <-time.After(3 * minInterval):
+	}
+}
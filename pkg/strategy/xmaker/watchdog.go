@@ -0,0 +1,34 @@
+package xmaker
+
+import (
+	"time"
+
+	"github.com/c9s/bbgo/pkg/bbgo"
+)
+
+const minWorkerHeartbeatTimeout = time.Minute
+
+// registerWorker registers the worker to the watchdog of the environment,
+// the returned heartbeat handle is nil when the watchdog is not available.
+func (s *Strategy) registerWorker(name string, timeout time.Duration) *bbgo.WorkerHeartbeat {
+	if s.Environment == nil || s.Environment.Watchdog == nil {
+		return nil
+	}
+
+	if timeout < minWorkerHeartbeatTimeout {
+		timeout = minWorkerHeartbeatTimeout
+	}
+
+	return s.Environment.Watchdog.Register(s.InstanceID()+"/"+name, timeout)
+}
+
+// quoteWorkerTimeout is the heartbeat timeout of the quote worker, the worker ticks at least every update interval
+// and every hedge interval, so that the timeout is a multiple of the longer interval.
+func (s *Strategy) quoteWorkerTimeout() time.Duration {
+	interval := s.UpdateInterval.Duration()
+	if s.HedgeInterval.Duration() > interval {
+		interval = s.HedgeInterval.Duration()
+	}
+
+	return 10 * interval
+}
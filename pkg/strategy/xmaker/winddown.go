@@ -0,0 +1,72 @@
+package xmaker
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/c9s/bbgo/pkg/bbgo"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// windingDown returns true when the strategy only quotes the side that reduces the position
+func (s *Strategy) windingDown() bool {
+	return s.GetStatus() == types.StrategyStatusWindingDown
+}
+
+// WindDown stops opening new exposure: the maker quotes are only placed on the side that reduces the position,
+// the uncovered position is still hedged, and the strategy stops quoting once the position is flat.
+// Resume cancels the wind-down and returns to the normal quoting.
+func (s *Strategy) WindDown(ctx context.Context) error {
+	switch s.GetStatus() {
+	case types.StrategyStatusStopped:
+		return fmt.Errorf("%s strategy is already stopped", s.Symbol)
+	case types.StrategyStatusWindingDown:
+		return nil
+	}
+
+	s.Status = types.StrategyStatusWindingDown
+
+	// pull the current quotes so that the side that increases the position is not left on the book
+	if err := s.activeMakerOrders.GracefulCancel(ctx, s.makerSession.Exchange); err != nil {
+		s.logger().WithError(err).Errorf("unable to cancel the %s maker orders", s.Symbol)
+	}
+
+	bbgo.Notify("%s: %s is winding down, position %v", ID, s.Symbol, s.Position.GetBase())
+	return nil
+}
+
+// windDownSides returns the maker sides to disable in the wind-down mode,
+// only the side that reduces the given position is kept, both sides are disabled when the position is flat.
+func windDownSides(position fixedpoint.Value) (disableBid, disableAsk bool) {
+	switch position.Sign() {
+	case 1:
+		return true, false
+	case -1:
+		return false, true
+	default:
+		return true, true
+	}
+}
+
+// checkWindDown stops the strategy once the position is flat in the wind-down mode,
+// it returns true when the wind-down is completed.
+func (s *Strategy) checkWindDown(ctx context.Context) bool {
+	if !s.windingDown() {
+		return false
+	}
+
+	pos := s.Position.GetBase()
+	if pos.Abs().Compare(s.makerMarket.MinQuantity) >= 0 {
+		return false
+	}
+
+	if err := s.activeMakerOrders.GracefulCancel(ctx, s.makerSession.Exchange); err != nil {
+		s.logger().WithError(err).Errorf("unable to cancel the %s maker orders", s.Symbol)
+		return false
+	}
+
+	s.Status = types.StrategyStatusStopped
+	bbgo.Notify("%s: %s wind-down is completed, the position %v is flat and quoting is stopped", ID, s.Symbol, pos)
+	return true
+}
@@ -0,0 +1,78 @@
+package xmaker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/c9s/bbgo/pkg/bbgo"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+	"github.com/c9s/bbgo/pkg/types/mocks"
+)
+
+func TestWindDownSides(t *testing.T) {
+	disableBid, disableAsk := windDownSides(fixedpoint.NewFromFloat(0.5))
+	assert.True(t, disableBid)
+	assert.False(t, disableAsk)
+
+	disableBid, disableAsk = windDownSides(fixedpoint.NewFromFloat(-0.5))
+	assert.False(t, disableBid)
+	assert.True(t, disableAsk)
+
+	disableBid, disableAsk = windDownSides(fixedpoint.Zero)
+	assert.True(t, disableBid)
+	assert.True(t, disableAsk)
+}
+
+func TestStrategy_WindDown(t *testing.T) {
+	ctx := context.Background()
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	market := types.Market{
+		Symbol:        "BTCUSDT",
+		BaseCurrency:  "BTC",
+		QuoteCurrency: "USDT",
+		MinQuantity:   fixedpoint.NewFromFloat(0.001),
+		StepSize:      fixedpoint.NewFromFloat(0.0001),
+	}
+
+	mockEx := mocks.NewMockExchange(mockCtrl)
+	mockEx.EXPECT().NewStream().Return(&types.StandardStream{}).Times(2)
+	mockEx.EXPECT().CancelOrders(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	session := bbgo.NewExchangeSession("max", mockEx)
+
+	s := &Strategy{
+		Symbol:            market.Symbol,
+		makerSession:      session,
+		makerMarket:       market,
+		activeMakerOrders: bbgo.NewActiveOrderBook(market.Symbol),
+		Position:          types.NewPositionFromMarket(market),
+	}
+	s.Status = types.StrategyStatusRunning
+
+	// not winding down, nothing to complete
+	assert.False(t, s.checkWindDown(ctx))
+
+	s.Position.Base = fixedpoint.NewFromFloat(0.5)
+	assert.NoError(t, s.WindDown(ctx))
+	assert.True(t, s.windingDown())
+	assert.False(t, s.suspended())
+
+	// the position is not flat yet
+	assert.False(t, s.checkWindDown(ctx))
+	assert.Equal(t, types.StrategyStatusWindingDown, s.GetStatus())
+
+	// the dust position below the min quantity is treated as flat
+	s.Position.Base = fixedpoint.NewFromFloat(0.0001)
+	assert.True(t, s.checkWindDown(ctx))
+	assert.Equal(t, types.StrategyStatusStopped, s.GetStatus())
+	assert.True(t, s.suspended())
+
+	// a stopped strategy can not be wound down
+	assert.Error(t, s.WindDown(ctx))
+}
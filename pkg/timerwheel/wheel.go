@@ -0,0 +1,302 @@
+// Package timerwheel provides a hierarchical timer wheel that runs many timer callbacks on one goroutine.
+//
+// The strategies and the sessions register their heartbeats and expiry checks on the shared wheel,
+// instead of spawning one time.Ticker goroutine for each of them.
+package timerwheel
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultTick is the tick of the default wheel
+	DefaultTick = 100 * time.Millisecond
+
+	// the first level covers 256 ticks, each of the higher levels covers 64 slots of the lower level
+	level0Slots = 256
+	levelNSlots = 64
+	numLevels   = 4
+)
+
+// Timer is the timer registered on the wheel
+type Timer struct {
+	f func()
+
+	// expire is the tick that the timer fires at
+	expire uint64
+
+	// period is the number of ticks of the repeated timer, zero for the one-shot timer
+	period uint64
+
+	level, slot int
+	scheduled   bool
+	stopped     bool
+
+	wheel *Wheel
+}
+
+// Stop stops the timer, it returns false if the one-shot timer has already fired or the timer has been stopped.
+func (t *Timer) Stop() bool {
+	w := t.wheel
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if t.stopped {
+		return false
+	}
+
+	t.stopped = true
+	if !t.scheduled {
+		return false
+	}
+
+	w.unschedule(t)
+	return true
+}
+
+// Reset re-schedules the timer to fire after the duration, the stopped or fired timer is scheduled again,
+// and the repeated timer keeps its interval after the next fire.
+func (t *Timer) Reset(d time.Duration) {
+	w := t.wheel
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if t.scheduled {
+		w.unschedule(t)
+	}
+
+	t.stopped = false
+	t.expire = w.current + w.ticks(d)
+	w.schedule(t)
+}
+
+// Wheel is a hierarchical timer wheel. A timer is placed in the slot of the lowest level that covers
+// its expiry, and the timers of the higher level slot are cascaded to the lower levels when the lower level wraps,
+// so that adding and stopping a timer are O(1) regardless of the number of timers.
+//
+// The callbacks are called on the goroutine that advances the wheel, they should not block.
+type Wheel struct {
+	tick  time.Duration
+	start time.Time
+
+	mu sync.Mutex
+
+	// current is the number of the processed ticks
+	current uint64
+	levels  [numLevels][]map[*Timer]struct{}
+	spans   [numLevels + 1]uint64
+	count   int
+}
+
+// New creates a wheel that starts at the start time with the given tick resolution
+func New(tick time.Duration, start time.Time) *Wheel {
+	if tick <= 0 {
+		tick = DefaultTick
+	}
+
+	w := &Wheel{
+		tick:  tick,
+		start: start,
+	}
+
+	span := uint64(1)
+	for i := 0; i < numLevels; i++ {
+		n := levelNSlots
+		if i == 0 {
+			n = level0Slots
+		}
+
+		w.levels[i] = make([]map[*Timer]struct{}, n)
+		for j := range w.levels[i] {
+			w.levels[i][j] = make(map[*Timer]struct{})
+		}
+
+		w.spans[i] = span
+		span *= uint64(n)
+	}
+
+	w.spans[numLevels] = span
+	return w
+}
+
+var defaultWheel *Wheel
+var defaultWheelOnce sync.Once
+
+// Default returns the shared wheel, the wheel is started on the first call and runs until the process exits
+func Default() *Wheel {
+	defaultWheelOnce.Do(func() {
+		defaultWheel = New(DefaultTick, time.Now())
+		go defaultWheel.Run(context.Background())
+	})
+
+	return defaultWheel
+}
+
+// Len returns the number of the scheduled timers
+func (w *Wheel) Len() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.count
+}
+
+// AfterFunc calls f once after the duration
+func (w *Wheel) AfterFunc(d time.Duration, f func()) *Timer {
+	return w.add(d, 0, f)
+}
+
+// Every calls f repeatedly with the interval until the timer is stopped
+func (w *Wheel) Every(interval time.Duration, f func()) *Timer {
+	return w.add(interval, w.ticks(interval), f)
+}
+
+func (w *Wheel) add(d time.Duration, period uint64, f func()) *Timer {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	t := &Timer{
+		f:      f,
+		period: period,
+		expire: w.current + w.ticks(d),
+		wheel:  w,
+	}
+
+	w.schedule(t)
+	return t
+}
+
+// ticks converts the duration to the number of ticks, rounded up and at least one tick
+func (w *Wheel) ticks(d time.Duration) uint64 {
+	n := uint64((d + w.tick - 1) / w.tick)
+	if n == 0 {
+		n = 1
+	}
+
+	return n
+}
+
+func (w *Wheel) schedule(t *Timer) {
+	expire := t.expire
+	if expire < w.current {
+		expire = w.current
+	}
+
+	delta := expire - w.current
+	level := numLevels - 1
+	for i := 0; i < numLevels; i++ {
+		if delta < w.spans[i+1] {
+			level = i
+			break
+		}
+	}
+
+	// the timer beyond the top level is parked in the farthest top level slot, and re-scheduled when it's cascaded
+	if delta >= w.spans[numLevels] {
+		expire = w.current + w.spans[numLevels] - 1
+	}
+
+	slots := w.levels[level]
+	t.level = level
+	t.slot = int((expire / w.spans[level]) % uint64(len(slots)))
+	t.scheduled = true
+	slots[t.slot][t] = struct{}{}
+	w.count++
+}
+
+func (w *Wheel) unschedule(t *Timer) {
+	delete(w.levels[t.level][t.slot], t)
+	t.scheduled = false
+	w.count--
+}
+
+// Run advances the wheel by the tick until the context is canceled
+func (w *Wheel) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.tick)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case now := <-ticker.C:
+			w.Advance(now)
+		}
+	}
+}
+
+// Advance processes the ticks until the given time and calls the callbacks of the expired timers
+func (w *Wheel) Advance(now time.Time) {
+	if now.Before(w.start) {
+		return
+	}
+
+	target := uint64(now.Sub(w.start) / w.tick)
+	for {
+		w.mu.Lock()
+		if w.current >= target {
+			w.mu.Unlock()
+			return
+		}
+
+		w.current++
+		w.cascade()
+		expired := w.expire()
+		w.mu.Unlock()
+
+		for _, t := range expired {
+			t.f()
+		}
+	}
+}
+
+// cascade moves the timers of the higher level slots that are reached by the current tick to the lower levels,
+// the highest level is cascaded first so that its timers can be cascaded again by the lower levels.
+func (w *Wheel) cascade() {
+	for level := numLevels - 1; level > 0; level-- {
+		if w.current%w.spans[level] != 0 {
+			continue
+		}
+
+		slots := w.levels[level]
+		slot := int((w.current / w.spans[level]) % uint64(len(slots)))
+		timers := slots[slot]
+		if len(timers) == 0 {
+			continue
+		}
+
+		slots[slot] = make(map[*Timer]struct{})
+		for t := range timers {
+			w.count--
+			w.schedule(t)
+		}
+	}
+}
+
+// expire removes the expired timers of the current first level slot and re-schedules the repeated timers
+func (w *Wheel) expire() []*Timer {
+	slots := w.levels[0]
+	slot := int(w.current % uint64(len(slots)))
+	timers := slots[slot]
+	if len(timers) == 0 {
+		return nil
+	}
+
+	var expired []*Timer
+	for t := range timers {
+		if t.expire > w.current {
+			continue
+		}
+
+		w.unschedule(t)
+		expired = append(expired, t)
+
+		if t.period > 0 {
+			t.expire = w.current + t.period
+			w.schedule(t)
+		}
+	}
+
+	return expired
+}
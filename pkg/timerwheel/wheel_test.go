@@ -0,0 +1,105 @@
+package timerwheel
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWheel_AfterFunc(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	w := New(time.Second, start)
+
+	var fired []time.Duration
+	for _, d := range []time.Duration{
+		3 * time.Second,
+		300 * time.Second,     // the second level
+		20000 * time.Second,   // the third level
+		2000000 * time.Second, // the fourth level
+	} {
+		d := d
+		w.AfterFunc(d, func() {
+			fired = append(fired, d)
+		})
+	}
+
+	assert.Equal(t, 4, w.Len())
+
+	w.Advance(start.Add(2 * time.Second))
+	assert.Empty(t, fired)
+
+	w.Advance(start.Add(3 * time.Second))
+	assert.Equal(t, []time.Duration{3 * time.Second}, fired)
+
+	w.Advance(start.Add(299 * time.Second))
+	assert.Len(t, fired, 1)
+
+	w.Advance(start.Add(300 * time.Second))
+	assert.Len(t, fired, 2)
+
+	w.Advance(start.Add(19999 * time.Second))
+	assert.Len(t, fired, 2)
+
+	w.Advance(start.Add(20000 * time.Second))
+	assert.Len(t, fired, 3)
+
+	w.Advance(start.Add(1999999 * time.Second))
+	assert.Len(t, fired, 3)
+
+	w.Advance(start.Add(2000000 * time.Second))
+	assert.Len(t, fired, 4)
+	assert.Equal(t, 0, w.Len())
+}
+
+func TestWheel_Every(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	w := New(100*time.Millisecond, start)
+
+	count := 0
+	timer := w.Every(time.Second, func() {
+		count++
+	})
+
+	w.Advance(start.Add(10 * time.Second))
+	assert.Equal(t, 10, count)
+
+	// the interval crosses the first level of 256 ticks
+	w.Advance(start.Add(60 * time.Second))
+	assert.Equal(t, 60, count)
+
+	assert.True(t, timer.Stop())
+	assert.False(t, timer.Stop())
+
+	w.Advance(start.Add(70 * time.Second))
+	assert.Equal(t, 60, count)
+	assert.Equal(t, 0, w.Len())
+}
+
+func TestWheel_StopAndReset(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	w := New(time.Second, start)
+
+	fired := 0
+	timer := w.AfterFunc(5*time.Second, func() {
+		fired++
+	})
+
+	w.Advance(start.Add(4 * time.Second))
+	timer.Reset(5 * time.Second)
+
+	w.Advance(start.Add(8 * time.Second))
+	assert.Equal(t, 0, fired)
+
+	w.Advance(start.Add(9 * time.Second))
+	assert.Equal(t, 1, fired)
+	assert.False(t, timer.Stop())
+
+	stopped := w.AfterFunc(time.Second, func() {
+		fired++
+	})
+	assert.True(t, stopped.Stop())
+
+	w.Advance(start.Add(20 * time.Second))
+	assert.Equal(t, 1, fired)
+}
@@ -21,6 +21,7 @@ type IsolatedMarginAssetMap map[string]IsolatedMarginAsset
 type MarginAssetMap map[string]MarginUserAsset
 type FuturesAssetMap map[string]FuturesUserAsset
 type FuturesPositionMap map[string]FuturesPosition
+type PortfolioMarginAssetMap map[string]PortfolioMarginAsset
 
 type AccountType string
 
@@ -39,6 +40,9 @@ type Account struct {
 	MarginInfo         *MarginAccountInfo
 	IsolatedMarginInfo *IsolatedMarginAccountInfo
 
+	// PortfolioMarginInfo is set when the margin or the futures account is in the portfolio margin mode
+	PortfolioMarginInfo *PortfolioMarginAccountInfo `json:"portfolioMarginInfo,omitempty"`
+
 	// Margin related common field
 	// From binance:
 	// Margin Level = Total Asset Value / (Total Borrowed + Total Accrued Interest)
@@ -101,6 +105,39 @@ type IsolatedMarginAccountInfo struct {
 	Assets              IsolatedMarginAssetMap `json:"userAssets"`
 }
 
+// PortfolioMarginAccountInfo is the unified account info of the portfolio margin account,
+// the collateral is shared by the cross margin and the futures positions.
+type PortfolioMarginAccountInfo struct {
+	// UniMMR is the unified maintenance margin ratio = collateral value / maintenance margin,
+	// the account is liquidated when the ratio drops to 1.05
+	UniMMR fixedpoint.Value `json:"uniMMR"`
+
+	// AccountEquity is the collateral value of the account in USD, the asset values are discounted by the collateral rates
+	AccountEquity fixedpoint.Value `json:"accountEquity"`
+
+	// ActualEquity is the equity of the account in USD without the collateral rates
+	ActualEquity fixedpoint.Value `json:"actualEquity"`
+
+	AccountInitialMargin  fixedpoint.Value `json:"accountInitialMargin"`
+	AccountMaintMargin    fixedpoint.Value `json:"accountMaintMargin"`
+	AccountStatus         string           `json:"accountStatus"`
+	TotalAvailableBalance fixedpoint.Value `json:"totalAvailableBalance"`
+
+	Assets PortfolioMarginAssetMap `json:"assets"`
+
+	// CollateralRates maps the asset to the rate of its value counted as the collateral
+	CollateralRates map[string]fixedpoint.Value `json:"collateralRates,omitempty"`
+}
+
+// CollateralRate returns the collateral rate of the asset, the asset is counted in full when its rate is unknown
+func (i *PortfolioMarginAccountInfo) CollateralRate(asset string) fixedpoint.Value {
+	if rate, ok := i.CollateralRates[asset]; ok {
+		return rate
+	}
+
+	return fixedpoint.One
+}
+
 func NewAccount() *Account {
 	return &Account{
 		AccountType:        "spot",
@@ -0,0 +1,141 @@
+package types
+
+import (
+	"sync"
+	"time"
+)
+
+// AggregatedStreamOrderBook aggregates the stream order books of the same symbol from multiple sources
+// (usually exchange sessions) into one order book, the volumes at the same price level are summed up.
+type AggregatedStreamOrderBook struct {
+	Symbol string
+
+	mu      sync.Mutex
+	sources []string
+	books   map[string]*StreamOrderBook
+}
+
+func NewAggregatedStreamOrderBook(symbol string) *AggregatedStreamOrderBook {
+	return &AggregatedStreamOrderBook{
+		Symbol: symbol,
+		books:  make(map[string]*StreamOrderBook),
+	}
+}
+
+// AddSource registers the stream order book of the given source name,
+// the order of the added sources is kept and used as the default priority.
+func (b *AggregatedStreamOrderBook) AddSource(source string, book *StreamOrderBook) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.books[source]; !ok {
+		b.sources = append(b.sources, source)
+	}
+
+	b.books[source] = book
+}
+
+// BindStream creates a new stream order book for the given source and binds it to the stream
+func (b *AggregatedStreamOrderBook) BindStream(source string, stream Stream) *StreamOrderBook {
+	book := NewStreamBook(b.Symbol)
+	book.BindStream(stream)
+	b.AddSource(source, book)
+	return book
+}
+
+// Sources returns the source names in the order they were added
+func (b *AggregatedStreamOrderBook) Sources() []string {
+	b.mu.Lock()
+	sources := make([]string, len(b.sources))
+	copy(sources, b.sources)
+	b.mu.Unlock()
+	return sources
+}
+
+func (b *AggregatedStreamOrderBook) Source(source string) (*StreamOrderBook, bool) {
+	b.mu.Lock()
+	book, ok := b.books[source]
+	b.mu.Unlock()
+	return book, ok
+}
+
+// LastUpdateTime returns the latest update time among all the source books
+func (b *AggregatedStreamOrderBook) LastUpdateTime() (t time.Time) {
+	for _, source := range b.Sources() {
+		book, _ := b.Source(source)
+		if ut := book.LastUpdateTime(); ut.After(t) {
+			t = ut
+		}
+	}
+
+	return t
+}
+
+// BestBidAndAsk returns the best bid and the best ask across all the sources
+func (b *AggregatedStreamOrderBook) BestBidAndAsk() (bid, ask PriceVolume, ok bool) {
+	return b.BestBidAndAskOf(b.Sources()...)
+}
+
+// BestBidAndAskOf returns the best bid and the best ask across the given sources,
+// the volume of the same price level is summed up.
+func (b *AggregatedStreamOrderBook) BestBidAndAskOf(sources ...string) (bid, ask PriceVolume, ok bool) {
+	book := b.CopyDepthOf(1, sources...)
+	bid, ok1 := book.BestBid()
+	ask, ok2 := book.BestAsk()
+	return bid, ask, ok1 && ok2
+}
+
+// CopyDepth copies and merges the given depth of all the source books
+func (b *AggregatedStreamOrderBook) CopyDepth(depth int) OrderBook {
+	return b.CopyDepthOf(depth, b.Sources()...)
+}
+
+// CopyDepthOf copies and merges the given depth of the given source books,
+// unknown source names are ignored.
+func (b *AggregatedStreamOrderBook) CopyDepthOf(depth int, sources ...string) OrderBook {
+	var books []OrderBook
+	var lastUpdateTime time.Time
+	for _, source := range sources {
+		if book, ok := b.Source(source); ok {
+			books = append(books, book.CopyDepth(depth))
+			if ut := book.LastUpdateTime(); ut.After(lastUpdateTime) {
+				lastUpdateTime = ut
+			}
+		}
+	}
+
+	merged := MergeOrderBooks(b.Symbol, books...)
+	merged.Time = lastUpdateTime
+	merged.lastUpdateTime = lastUpdateTime
+	merged.Bids = merged.Bids.CopyDepth(depth)
+	merged.Asks = merged.Asks.CopyDepth(depth)
+	return merged
+}
+
+// MergeOrderBooks merges the given order books into a new slice order book.
+// The volumes of the same price level are summed up.
+func MergeOrderBooks(symbol string, books ...OrderBook) *SliceOrderBook {
+	merged := NewSliceOrderBook(symbol)
+	for _, book := range books {
+		merged.Bids = mergePriceVolumes(merged.Bids, book.SideBook(SideTypeBuy), true)
+		merged.Asks = mergePriceVolumes(merged.Asks, book.SideBook(SideTypeSell), false)
+	}
+
+	return merged
+}
+
+func mergePriceVolumes(slice, pvs PriceVolumeSlice, descending bool) PriceVolumeSlice {
+	for _, pv := range pvs {
+		if pv.Volume.IsZero() {
+			continue
+		}
+
+		if existing, _ := slice.Find(pv.Price, descending); !existing.Price.IsZero() {
+			pv.Volume = pv.Volume.Add(existing.Volume)
+		}
+
+		slice = slice.Upsert(pv, descending)
+	}
+
+	return slice
+}
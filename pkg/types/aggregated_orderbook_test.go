@@ -0,0 +1,90 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+)
+
+func TestMergeOrderBooks(t *testing.T) {
+	a := &SliceOrderBook{
+		Symbol: "BTCUSDT",
+		Bids: PriceVolumeSlice{
+			{Price: fixedpoint.NewFromFloat(100.0), Volume: fixedpoint.NewFromFloat(1.0)},
+			{Price: fixedpoint.NewFromFloat(99.0), Volume: fixedpoint.NewFromFloat(1.0)},
+		},
+		Asks: PriceVolumeSlice{
+			{Price: fixedpoint.NewFromFloat(101.0), Volume: fixedpoint.NewFromFloat(1.0)},
+			{Price: fixedpoint.NewFromFloat(102.0), Volume: fixedpoint.NewFromFloat(1.0)},
+		},
+	}
+
+	b := &SliceOrderBook{
+		Symbol: "BTCUSDT",
+		Bids: PriceVolumeSlice{
+			{Price: fixedpoint.NewFromFloat(100.5), Volume: fixedpoint.NewFromFloat(2.0)},
+			{Price: fixedpoint.NewFromFloat(99.0), Volume: fixedpoint.NewFromFloat(3.0)},
+		},
+		Asks: PriceVolumeSlice{
+			{Price: fixedpoint.NewFromFloat(101.0), Volume: fixedpoint.NewFromFloat(2.0)},
+			{Price: fixedpoint.NewFromFloat(103.0), Volume: fixedpoint.NewFromFloat(1.0)},
+		},
+	}
+
+	merged := MergeOrderBooks("BTCUSDT", a, b)
+	assert.Equal(t, PriceVolumeSlice{
+		{Price: fixedpoint.NewFromFloat(100.5), Volume: fixedpoint.NewFromFloat(2.0)},
+		{Price: fixedpoint.NewFromFloat(100.0), Volume: fixedpoint.NewFromFloat(1.0)},
+		{Price: fixedpoint.NewFromFloat(99.0), Volume: fixedpoint.NewFromFloat(4.0)},
+	}, merged.Bids)
+
+	assert.Equal(t, PriceVolumeSlice{
+		{Price: fixedpoint.NewFromFloat(101.0), Volume: fixedpoint.NewFromFloat(3.0)},
+		{Price: fixedpoint.NewFromFloat(102.0), Volume: fixedpoint.NewFromFloat(1.0)},
+		{Price: fixedpoint.NewFromFloat(103.0), Volume: fixedpoint.NewFromFloat(1.0)},
+	}, merged.Asks)
+
+	// the source books should not be modified
+	assert.Equal(t, fixedpoint.NewFromFloat(1.0), a.Bids[1].Volume)
+}
+
+func TestAggregatedStreamOrderBook_CopyDepthOf(t *testing.T) {
+	book := NewAggregatedStreamOrderBook("BTCUSDT")
+
+	binanceBook := NewStreamBook("BTCUSDT")
+	binanceBook.Load(SliceOrderBook{
+		Symbol: "BTCUSDT",
+		Bids:   PriceVolumeSlice{{Price: fixedpoint.NewFromFloat(100.0), Volume: fixedpoint.One}},
+		Asks:   PriceVolumeSlice{{Price: fixedpoint.NewFromFloat(102.0), Volume: fixedpoint.One}},
+	})
+	book.AddSource("binance", binanceBook)
+
+	okexBook := NewStreamBook("BTCUSDT")
+	okexBook.Load(SliceOrderBook{
+		Symbol: "BTCUSDT",
+		Bids:   PriceVolumeSlice{{Price: fixedpoint.NewFromFloat(100.5), Volume: fixedpoint.One}},
+		Asks:   PriceVolumeSlice{{Price: fixedpoint.NewFromFloat(101.5), Volume: fixedpoint.One}},
+	})
+	book.AddSource("okex", okexBook)
+
+	assert.Equal(t, []string{"binance", "okex"}, book.Sources())
+
+	bid, ask, ok := book.BestBidAndAsk()
+	if assert.True(t, ok) {
+		assert.Equal(t, fixedpoint.NewFromFloat(100.5), bid.Price)
+		assert.Equal(t, fixedpoint.NewFromFloat(101.5), ask.Price)
+	}
+
+	bid, ask, ok = book.BestBidAndAskOf("binance")
+	if assert.True(t, ok) {
+		assert.Equal(t, fixedpoint.NewFromFloat(100.0), bid.Price)
+		assert.Equal(t, fixedpoint.NewFromFloat(102.0), ask.Price)
+	}
+
+	merged := book.CopyDepth(1)
+	assert.Len(t, merged.SideBook(SideTypeBuy), 1)
+	assert.Len(t, merged.SideBook(SideTypeSell), 1)
+	assert.False(t, merged.LastUpdateTime().IsZero())
+}
@@ -0,0 +1,97 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+// EnumError is returned when a config value does not belong to the values of the enum,
+// the closest valid value is suggested for the typos.
+type EnumError struct {
+	// Name is the name of the enum, e.g. "price type"
+	Name string
+
+	// Value is the invalid value
+	Value string
+
+	// Values are the valid values of the enum
+	Values []string
+
+	// Err is the sentinel error of the enum, it can be checked by errors.Is
+	Err error
+}
+
+// Suggestion returns the closest valid value of the invalid value, it returns false if nothing is close enough.
+func (e *EnumError) Suggestion() (string, bool) {
+	value := strings.ToLower(e.Value)
+
+	suggestion, minDistance := "", -1
+	for _, v := range e.Values {
+		distance := editDistance(value, strings.ToLower(v))
+		if minDistance < 0 || distance < minDistance {
+			suggestion, minDistance = v, distance
+		}
+	}
+
+	// the suggestion is only made when less than half of the characters are different
+	if minDistance < 0 || minDistance*2 > len(suggestion) {
+		return "", false
+	}
+
+	return suggestion, true
+}
+
+func (e *EnumError) Error() string {
+	if suggestion, ok := e.Suggestion(); ok {
+		return fmt.Sprintf("invalid %s %q, did you mean %q? valid values are: %s",
+			e.Name, e.Value, suggestion, strings.Join(e.Values, ", "))
+	}
+
+	return fmt.Sprintf("invalid %s %q, valid values are: %s", e.Name, e.Value, strings.Join(e.Values, ", "))
+}
+
+func (e *EnumError) Unwrap() error {
+	return e.Err
+}
+
+// ParseEnum parses the case-insensitive string into one of the enum values,
+// the canonical enum value is returned, and an *EnumError is returned if the string does not belong to the values.
+func ParseEnum[T ~string](name string, s string, values ...T) (T, error) {
+	for _, v := range values {
+		if strings.EqualFold(s, string(v)) {
+			return v, nil
+		}
+	}
+
+	var strs = make([]string, len(values))
+	for i, v := range values {
+		strs[i] = string(v)
+	}
+
+	return T(s), &EnumError{Name: name, Value: s, Values: strs}
+}
+
+// editDistance returns the levenshtein distance of the two strings
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+
+		prev, curr = curr, prev
+	}
+
+	return prev[len(b)]
+}
@@ -0,0 +1,75 @@
+package types
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func TestParseEnum(t *testing.T) {
+	mode, err := ParseMarginMode("Isolated")
+	assert.NoError(t, err)
+	assert.Equal(t, MarginModeIsolated, mode)
+
+	_, err = ParseMarginMode("isloated")
+	if assert.Error(t, err) {
+		var enumErr *EnumError
+		if assert.True(t, errors.As(err, &enumErr)) {
+			suggestion, ok := enumErr.Suggestion()
+			assert.True(t, ok)
+			assert.Equal(t, "isolated", suggestion)
+		}
+
+		assert.Equal(t, `invalid margin mode "isloated", did you mean "isolated"? valid values are: cross, isolated`, err.Error())
+	}
+
+	// nothing is close to the value
+	_, err = ParseMarginMode("futures")
+	if assert.Error(t, err) {
+		assert.Equal(t, `invalid margin mode "futures", valid values are: cross, isolated`, err.Error())
+	}
+}
+
+func TestPriceType_Unmarshal(t *testing.T) {
+	var config struct {
+		PriceType PriceType `json:"priceType" yaml:"priceType"`
+	}
+
+	assert.NoError(t, yaml.Unmarshal([]byte("priceType: mid"), &config))
+	assert.Equal(t, PriceTypeMid, config.PriceType)
+
+	assert.NoError(t, json.Unmarshal([]byte(`{"priceType":"maker"}`), &config))
+	assert.Equal(t, PriceTypeMaker, config.PriceType)
+
+	err := yaml.Unmarshal([]byte("priceType: MIDD"), &config)
+	if assert.Error(t, err) {
+		assert.True(t, errors.Is(err, ErrInvalidPriceType))
+		assert.Contains(t, err.Error(), `did you mean "MID"?`)
+	}
+
+	err = json.Unmarshal([]byte(`{"priceType":"takre"}`), &config)
+	if assert.Error(t, err) {
+		assert.True(t, errors.Is(err, ErrInvalidPriceType))
+		assert.Contains(t, err.Error(), `did you mean "TAKER"?`)
+	}
+}
+
+func TestHaltAction_Unmarshal(t *testing.T) {
+	var config struct {
+		HaltAction HaltAction `json:"haltAction" yaml:"haltAction"`
+	}
+
+	assert.NoError(t, yaml.Unmarshal([]byte("haltAction: Flatten"), &config))
+	assert.Equal(t, HaltActionFlatten, config.HaltAction)
+
+	assert.NoError(t, json.Unmarshal([]byte(`{"haltAction":""}`), &config))
+	assert.Equal(t, HaltAction(""), config.HaltAction)
+
+	err := json.Unmarshal([]byte(`{"haltAction":"cancle"}`), &config)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), `did you mean "cancel"?`)
+	}
+}
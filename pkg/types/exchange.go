@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql/driver"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -23,6 +24,8 @@ const (
 	ExchangeBitget   ExchangeName = "bitget"
 	ExchangeBacktest ExchangeName = "backtest"
 	ExchangeBybit    ExchangeName = "bybit"
+	ExchangeMEXC     ExchangeName = "mexc"
+	ExchangeBitstamp ExchangeName = "bitstamp"
 )
 
 var SupportedExchanges = []ExchangeName{
@@ -32,6 +35,8 @@ var SupportedExchanges = []ExchangeName{
 	ExchangeKucoin,
 	ExchangeBitget,
 	ExchangeBybit,
+	ExchangeMEXC,
+	ExchangeBitstamp,
 	// note: we are not using "backtest"
 }
 
@@ -55,7 +60,7 @@ func (n *ExchangeName) UnmarshalJSON(data []byte) error {
 
 func (n ExchangeName) IsValid() bool {
 	switch n {
-	case ExchangeBinance, ExchangeBitget, ExchangeBybit, ExchangeMax, ExchangeOKEx, ExchangeKucoin:
+	case ExchangeBinance, ExchangeBitget, ExchangeBybit, ExchangeMax, ExchangeOKEx, ExchangeKucoin, ExchangeMEXC, ExchangeBitstamp:
 		return true
 	}
 	return false
@@ -118,6 +123,34 @@ type ExchangeTradeService interface {
 	CancelOrders(ctx context.Context, orders ...Order) error
 }
 
+// ExchangeOrderAmendService provides an interface for amending the price and the quantity of an open order in place.
+// The quantity is the new remaining (open) quantity of the order, not including the executed quantity,
+// the drivers whose amend API takes the total quantity add the executed quantity of the order.
+// The returned order has the total quantity, i.e., the executed quantity plus the new remaining quantity.
+type ExchangeOrderAmendService interface {
+	AmendOrder(ctx context.Context, order Order, price, quantity fixedpoint.Value) (*Order, error)
+}
+
+// ErrWebSocketOrderEntryUnavailable is returned by the websocket order entry when the order is not sent,
+// e.g. the websocket connection is not established, so the order can be placed through the REST API safely.
+var ErrWebSocketOrderEntryUnavailable = errors.New("websocket order entry is unavailable")
+
+// ExchangeWebSocketOrderEntryService provides an interface for placing the orders through the websocket trading API
+// of the exchange, which has a much lower latency than the REST API.
+type ExchangeWebSocketOrderEntryService interface {
+	// WebSocketOrderEntryAvailable returns true when the websocket trading connection is established and authenticated
+	WebSocketOrderEntryAvailable() bool
+
+	// SubmitOrderByWebSocket places the order through the websocket trading API,
+	// ErrWebSocketOrderEntryUnavailable is returned if the order is not sent.
+	SubmitOrderByWebSocket(ctx context.Context, order SubmitOrder) (createdOrder *Order, err error)
+}
+
+// ExchangeWebSocketOrderEntryEnabler is implemented by the exchanges that open the websocket trading connection on demand
+type ExchangeWebSocketOrderEntryEnabler interface {
+	EnableWebSocketOrderEntry(ctx context.Context)
+}
+
 type ExchangeDefaultFeeRates interface {
 	DefaultFeeRates() ExchangeFee
 }
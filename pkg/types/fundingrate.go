@@ -1,6 +1,7 @@
 package types
 
 import (
+	"context"
 	"time"
 
 	"github.com/c9s/bbgo/pkg/fixedpoint"
@@ -11,3 +12,9 @@ type FundingRate struct {
 	FundingTime time.Time
 	Time        time.Time
 }
+
+// FundingRateService provides the predicted funding rate of the perpetual futures market,
+// the last funding rate of the premium index is the predicted rate of the current funding period.
+type FundingRateService interface {
+	QueryPremiumIndex(ctx context.Context, symbol string) (*PremiumIndex, error)
+}
@@ -0,0 +1,54 @@
+package types
+
+import (
+	"encoding/json"
+)
+
+// HaltAction is the action taken when the trading is halted by a circuit breaker
+type HaltAction string
+
+const (
+	// HaltActionCancel cancels the open orders and keeps the position
+	HaltActionCancel HaltAction = "cancel"
+
+	// HaltActionFlatten cancels the open orders and closes the position
+	HaltActionFlatten HaltAction = "flatten"
+)
+
+func ParseHaltAction(s string) (HaltAction, error) {
+	return ParseEnum("halt action", s, HaltActionCancel, HaltActionFlatten)
+}
+
+func (a *HaltAction) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	return a.parse(s)
+}
+
+func (a *HaltAction) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var s string
+	if err := unmarshal(&s); err != nil {
+		return err
+	}
+
+	return a.parse(s)
+}
+
+func (a *HaltAction) parse(s string) error {
+	// the empty halt action falls back to the default action of the strategy
+	if s == "" {
+		*a = ""
+		return nil
+	}
+
+	action, err := ParseHaltAction(s)
+	if err != nil {
+		return err
+	}
+
+	*a = action
+	return nil
+}
@@ -64,6 +64,12 @@ func (i Interval) Duration() time.Duration {
 	return time.Duration(i.Milliseconds()) * time.Millisecond
 }
 
+// IsSubSecond returns true for the intervals shorter than one second, e.g. 100ms,
+// these intervals are not provided by the exchanges and can only be synthesized from the market trades.
+func (i Interval) IsSubSecond() bool {
+	return i.Duration() < time.Second
+}
+
 func (i *Interval) UnmarshalJSON(b []byte) (err error) {
 	var a string
 	err = json.Unmarshal(b, &a)
@@ -94,6 +100,8 @@ func (s IntervalSlice) StringSlice() (slice []string) {
 	return slice
 }
 
+var Interval100ms = Interval("100ms")
+var Interval250ms = Interval("250ms")
 var Interval1s = Interval("1s")
 var Interval1m = Interval("1m")
 var Interval3m = Interval("3m")
@@ -123,6 +131,8 @@ func ParseInterval(input Interval) int {
 		}
 	}
 	switch strings.ToLower(string(input[index:])) {
+	case "ms":
+		return t / 1000
 	case "s":
 		return t
 	case "m":
@@ -0,0 +1,159 @@
+package types
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+)
+
+// SyntheticIntervals are the kline intervals that can be synthesized from the market trades,
+// the sub-second intervals are not provided by the exchanges, and the 1s interval is synthesized
+// when the exchange does not support it.
+var SyntheticIntervals = map[Interval]struct{}{
+	Interval100ms: {},
+	Interval250ms: {},
+	Interval1s:    {},
+}
+
+// maxSyntheticKLineGap is the max number of the flat klines that are closed for the intervals without trades,
+// the gap beyond this is skipped, e.g. when the process was suspended.
+const maxSyntheticKLineGap = 1000
+
+// KLineAggregator builds the klines of the interval from the market trades, it's used for the synthetic intervals
+// like 100ms and 250ms which are not provided by the exchanges.
+//
+// The trades are bucketed by their trade time, and the klines are closed by the next trade or by Advance,
+// the intervals without trades are closed as the flat klines of the last close price with zero volume.
+//
+//go:generate callbackgen -type KLineAggregator
+type KLineAggregator struct {
+	Symbol   string
+	Interval Interval
+
+	mu sync.Mutex
+
+	// current is the kline of the current interval, it's nil before the first trade
+	current *KLine
+
+	kLineClosedCallbacks []func(k KLine)
+}
+
+func NewKLineAggregator(symbol string, interval Interval) *KLineAggregator {
+	return &KLineAggregator{
+		Symbol:   symbol,
+		Interval: interval,
+	}
+}
+
+// BindStream builds the klines from the market trades of the stream
+func (a *KLineAggregator) BindStream(stream Stream) {
+	stream.OnMarketTrade(a.AddTrade)
+}
+
+// Run closes the klines on time even if there is no trade
+func (a *KLineAggregator) Run(ctx context.Context) {
+	ticker := time.NewTicker(a.Interval.Duration())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case now := <-ticker.C:
+			a.Advance(now)
+		}
+	}
+}
+
+func (a *KLineAggregator) AddTrade(trade Trade) {
+	if trade.Symbol != a.Symbol {
+		return
+	}
+
+	tradeTime := trade.Time.Time()
+
+	a.mu.Lock()
+	closed := a.advance(tradeTime)
+	if a.current == nil {
+		a.current = a.newKLine(tradeTime.Truncate(a.Interval.Duration()), trade.Price)
+		a.current.Exchange = trade.Exchange
+	}
+
+	// the late trades of the closed intervals are added to the current kline
+	k := a.current
+	if k.NumberOfTrades == 0 {
+		k.Open, k.High, k.Low = trade.Price, trade.Price, trade.Price
+	} else {
+		k.High = fixedpoint.Max(k.High, trade.Price)
+		k.Low = fixedpoint.Min(k.Low, trade.Price)
+	}
+
+	k.Close = trade.Price
+	k.Volume = k.Volume.Add(trade.Quantity)
+	k.QuoteVolume = k.QuoteVolume.Add(trade.QuoteQuantity)
+	if trade.IsBuyer {
+		k.TakerBuyBaseAssetVolume = k.TakerBuyBaseAssetVolume.Add(trade.Quantity)
+		k.TakerBuyQuoteAssetVolume = k.TakerBuyQuoteAssetVolume.Add(trade.QuoteQuantity)
+	}
+	k.NumberOfTrades++
+	a.mu.Unlock()
+
+	for _, k := range closed {
+		a.EmitKLineClosed(k)
+	}
+}
+
+// Advance closes the klines that end before the given time
+func (a *KLineAggregator) Advance(now time.Time) {
+	a.mu.Lock()
+	closed := a.advance(now)
+	a.mu.Unlock()
+
+	for _, k := range closed {
+		a.EmitKLineClosed(k)
+	}
+}
+
+func (a *KLineAggregator) advance(now time.Time) (closed []KLine) {
+	if a.current == nil {
+		return nil
+	}
+
+	duration := a.Interval.Duration()
+	for {
+		startTime := a.current.StartTime.Time()
+		if now.Before(startTime.Add(duration)) {
+			return closed
+		}
+
+		k := *a.current
+		k.Closed = true
+		closed = append(closed, k)
+
+		next := startTime.Add(duration)
+		if now.Sub(next) > maxSyntheticKLineGap*duration {
+			next = now.Truncate(duration)
+		}
+
+		a.current = a.newKLine(next, k.Close)
+		a.current.Exchange = k.Exchange
+	}
+}
+
+// newKLine creates the flat kline of the price, the open, high and low prices are reset by the first trade
+func (a *KLineAggregator) newKLine(startTime time.Time, price fixedpoint.Value) *KLine {
+	duration := a.Interval.Duration()
+	return &KLine{
+		Symbol:    a.Symbol,
+		Interval:  a.Interval,
+		StartTime: Time(startTime),
+		EndTime:   Time(startTime.Add(duration - time.Millisecond)),
+		Open:      price,
+		High:      price,
+		Low:       price,
+		Close:     price,
+	}
+}
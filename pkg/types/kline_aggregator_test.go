@@ -0,0 +1,76 @@
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+)
+
+func TestKLineAggregator(t *testing.T) {
+	startTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	newTrade := func(offset time.Duration, price, quantity float64) Trade {
+		return Trade{
+			Exchange:      ExchangeBinance,
+			Symbol:        "BTCUSDT",
+			Price:         fixedpoint.NewFromFloat(price),
+			Quantity:      fixedpoint.NewFromFloat(quantity),
+			QuoteQuantity: fixedpoint.NewFromFloat(price * quantity),
+			Time:          Time(startTime.Add(offset)),
+		}
+	}
+
+	aggregator := NewKLineAggregator("BTCUSDT", Interval100ms)
+
+	var klines []KLine
+	aggregator.OnKLineClosed(func(k KLine) { klines = append(klines, k) })
+
+	aggregator.AddTrade(newTrade(10*time.Millisecond, 100, 1))
+	aggregator.AddTrade(newTrade(20*time.Millisecond, 102, 1))
+	aggregator.AddTrade(newTrade(90*time.Millisecond, 99, 2))
+	assert.Empty(t, klines)
+
+	// the trade of the next interval closes the kline
+	aggregator.AddTrade(newTrade(120*time.Millisecond, 101, 1))
+	if assert.Len(t, klines, 1) {
+		k := klines[0]
+		assert.Equal(t, Interval100ms, k.Interval)
+		assert.Equal(t, ExchangeBinance, k.Exchange)
+		assert.Equal(t, startTime, k.StartTime.Time())
+		assert.Equal(t, startTime.Add(99*time.Millisecond), k.EndTime.Time())
+		assert.Equal(t, "100", k.Open.String())
+		assert.Equal(t, "102", k.High.String())
+		assert.Equal(t, "99", k.Low.String())
+		assert.Equal(t, "99", k.Close.String())
+		assert.Equal(t, "4", k.Volume.String())
+		assert.Equal(t, uint64(3), k.NumberOfTrades)
+		assert.True(t, k.Closed)
+	}
+
+	// the intervals without trades are closed as the flat klines
+	aggregator.Advance(startTime.Add(400 * time.Millisecond))
+	if assert.Len(t, klines, 4) {
+		assert.Equal(t, "101", klines[1].Close.String())
+		assert.Equal(t, startTime.Add(200*time.Millisecond), klines[2].StartTime.Time())
+		assert.Equal(t, "101", klines[2].Open.String())
+		assert.Equal(t, "101", klines[3].Close.String())
+		assert.True(t, klines[3].Volume.IsZero())
+	}
+
+	// the first trade of a flat kline resets the open price
+	aggregator.AddTrade(newTrade(450*time.Millisecond, 105, 1))
+	aggregator.Advance(startTime.Add(500 * time.Millisecond))
+	if assert.Len(t, klines, 5) {
+		assert.Equal(t, "105", klines[4].Open.String())
+		assert.Equal(t, "105", klines[4].Low.String())
+	}
+}
+
+func TestInterval_IsSubSecond(t *testing.T) {
+	assert.True(t, Interval100ms.IsSubSecond())
+	assert.Equal(t, 250*time.Millisecond, Interval250ms.Duration())
+	assert.False(t, Interval1s.IsSubSecond())
+	assert.Equal(t, 0, Interval250ms.Seconds())
+}
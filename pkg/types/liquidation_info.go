@@ -2,7 +2,10 @@ package types
 
 import "github.com/c9s/bbgo/pkg/fixedpoint"
 
+// LiquidationInfo is the forced liquidation order of the market, it's pushed from the ForceOrderChannel.
+// The Side is the side of the liquidation order, e.g. a long position is liquidated by a sell order.
 type LiquidationInfo struct {
+	Exchange     ExchangeName
 	Symbol       string
 	Side         SideType
 	OrderType    OrderType
@@ -12,4 +15,17 @@ type LiquidationInfo struct {
 	AveragePrice fixedpoint.Value
 	OrderStatus  OrderStatus
 	TradeTime    Time
+
+	// BankruptcyLoss is the loss covered by the insurance fund, it's only provided by some exchanges
+	BankruptcyLoss fixedpoint.Value
+}
+
+// QuoteQuantity returns the notional value of the liquidation order
+func (i LiquidationInfo) QuoteQuantity() fixedpoint.Value {
+	price := i.AveragePrice
+	if price.IsZero() {
+		price = i.Price
+	}
+
+	return i.Quantity.Mul(price)
 }
@@ -0,0 +1,30 @@
+package types
+
+import (
+	"context"
+	"time"
+)
+
+// SystemStatus is the system status of the exchange
+type SystemStatus struct {
+	// Maintenance is true when the exchange is under the system maintenance
+	Maintenance bool   `json:"maintenance"`
+	Message     string `json:"message"`
+}
+
+// ExchangeSystemStatusService queries the system status announced by the exchange status endpoint
+type ExchangeSystemStatusService interface {
+	QuerySystemStatus(ctx context.Context) (*SystemStatus, error)
+}
+
+// MaintenanceWindow is a scheduled maintenance window of the exchange
+type MaintenanceWindow struct {
+	Start  time.Time `json:"start" yaml:"start"`
+	End    time.Time `json:"end" yaml:"end"`
+	Reason string    `json:"reason,omitempty" yaml:"reason,omitempty"`
+}
+
+// Contains checks if the time is in the maintenance window, the end time is exclusive
+func (w MaintenanceWindow) Contains(t time.Time) bool {
+	return !t.Before(w.Start) && t.Before(w.End)
+}
@@ -155,6 +155,23 @@ type MarginUserAsset struct {
 	NetAsset fixedpoint.Value `json:"netAsset"`
 }
 
+// PortfolioMarginAsset defines the unified asset of the portfolio margin account,
+// the wallet balance is shared by the cross margin and the futures
+type PortfolioMarginAsset struct {
+	Asset         string           `json:"asset"`
+	WalletBalance fixedpoint.Value `json:"walletBalance"`
+	Borrowed      fixedpoint.Value `json:"borrowed"`
+	Interest      fixedpoint.Value `json:"interest"`
+
+	// UnrealizedProfit is the unrealized profit of the futures positions settled in the asset
+	UnrealizedProfit fixedpoint.Value `json:"unrealizedProfit"`
+}
+
+// Equity returns the wallet balance with the unrealized profit, minus the debt
+func (a PortfolioMarginAsset) Equity() fixedpoint.Value {
+	return a.WalletBalance.Add(a.UnrealizedProfit).Sub(a.Borrowed).Sub(a.Interest)
+}
+
 // IsolatedMarginAccount defines isolated user assets of margin account
 type IsolatedMarginAccount struct {
 	TotalAssetOfBTC     fixedpoint.Value       `json:"totalAssetOfBtc"`
@@ -0,0 +1,51 @@
+package types
+
+import (
+	"encoding/json"
+)
+
+// MarginMode is the margin mode of the session
+type MarginMode string
+
+const (
+	MarginModeCross    MarginMode = "cross"
+	MarginModeIsolated MarginMode = "isolated"
+)
+
+func ParseMarginMode(s string) (MarginMode, error) {
+	return ParseEnum("margin mode", s, MarginModeCross, MarginModeIsolated)
+}
+
+func (m *MarginMode) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	return m.parse(s)
+}
+
+func (m *MarginMode) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var s string
+	if err := unmarshal(&s); err != nil {
+		return err
+	}
+
+	return m.parse(s)
+}
+
+func (m *MarginMode) parse(s string) error {
+	// the empty margin mode is the spot mode
+	if s == "" {
+		*m = ""
+		return nil
+	}
+
+	mode, err := ParseMarginMode(s)
+	if err != nil {
+		return err
+	}
+
+	*m = mode
+	return nil
+}
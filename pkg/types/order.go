@@ -70,6 +70,52 @@ func (t *MarginOrderSideEffectType) UnmarshalJSON(data []byte) error {
 	return fmt.Errorf("invalid side effect type: %s", data)
 }
 
+// SelfTradePreventionMode defines which side the exchange expires when an order would match
+// another order of the same account (or the same account group), e.g. Binance selfTradePreventionMode
+// or Coinbase stp. The empty mode leaves the exchange default.
+type SelfTradePreventionMode string
+
+var (
+	SelfTradePreventionModeNone        SelfTradePreventionMode = "NONE"
+	SelfTradePreventionModeExpireTaker SelfTradePreventionMode = "EXPIRE_TAKER"
+	SelfTradePreventionModeExpireMaker SelfTradePreventionMode = "EXPIRE_MAKER"
+	SelfTradePreventionModeExpireBoth  SelfTradePreventionMode = "EXPIRE_BOTH"
+)
+
+func (m *SelfTradePreventionMode) UnmarshalJSON(data []byte) error {
+	var s string
+	var err = json.Unmarshal(data, &s)
+	if err != nil {
+		return errors.Wrapf(err, "unable to unmarshal self trade prevention mode: %s", data)
+	}
+
+	switch strings.ToUpper(s) {
+
+	case "":
+		*m = ""
+		return nil
+
+	case string(SelfTradePreventionModeNone):
+		*m = SelfTradePreventionModeNone
+		return nil
+
+	case string(SelfTradePreventionModeExpireTaker), "CANCEL_NEWEST", "CANCEL_TAKER":
+		*m = SelfTradePreventionModeExpireTaker
+		return nil
+
+	case string(SelfTradePreventionModeExpireMaker), "CANCEL_OLDEST", "CANCEL_MAKER":
+		*m = SelfTradePreventionModeExpireMaker
+		return nil
+
+	case string(SelfTradePreventionModeExpireBoth), "CANCEL_BOTH":
+		*m = SelfTradePreventionModeExpireBoth
+		return nil
+
+	}
+
+	return fmt.Errorf("invalid self trade prevention mode: %s", data)
+}
+
 // OrderType define order type
 type OrderType string
 
@@ -79,6 +125,10 @@ const (
 	OrderTypeMarket     OrderType = "MARKET"
 	OrderTypeStopLimit  OrderType = "STOP_LIMIT"
 	OrderTypeStopMarket OrderType = "STOP_MARKET"
+
+	// OrderTypeOCO is a one-cancels-the-other order list of a limit maker order at Price and a stop market order
+	// triggered at StopPrice, when one of the orders is filled, the other order is canceled.
+	OrderTypeOCO OrderType = "OCO"
 )
 
 /*
@@ -137,6 +187,11 @@ type SubmitOrder struct {
 
 	StopPrice fixedpoint.Value `json:"stopPrice,omitempty" db:"stop_price"`
 
+	// TakeProfitPrice and StopLossPrice are the trigger prices of the attached take-profit and stop-loss orders,
+	// they're only supported by the exchanges that support the algo orders, e.g. OKX.
+	TakeProfitPrice fixedpoint.Value `json:"takeProfitPrice,omitempty" db:"-"`
+	StopLossPrice   fixedpoint.Value `json:"stopLossPrice,omitempty" db:"-"`
+
 	Market Market `json:"-" db:"-"`
 
 	TimeInForce TimeInForce `json:"timeInForce,omitempty" db:"time_in_force"` // GTC, IOC, FOK
@@ -148,6 +203,9 @@ type SubmitOrder struct {
 	ReduceOnly    bool `json:"reduceOnly,omitempty" db:"reduce_only"`
 	ClosePosition bool `json:"closePosition,omitempty" db:"close_position"`
 
+	// SelfTradePreventionMode is only supported by the exchanges that support the self trade prevention, e.g. Binance
+	SelfTradePreventionMode SelfTradePreventionMode `json:"selfTradePreventionMode,omitempty" db:"-"`
+
 	Tag string `json:"tag,omitempty" db:"-"`
 }
 
@@ -278,6 +336,12 @@ type Order struct {
 	IsFutures  bool `json:"isFutures,omitempty" db:"is_futures"`
 	IsMargin   bool `json:"isMargin,omitempty" db:"is_margin"`
 	IsIsolated bool `json:"isIsolated,omitempty" db:"is_isolated"`
+
+	// OrderListID is the id of the order list (e.g. OCO) that the order belongs to
+	OrderListID uint64 `json:"orderListID,omitempty" db:"-"`
+
+	// LinkedOrderIDs are the ids of the other orders of the same order list, they're canceled when this order is filled
+	LinkedOrderIDs []uint64 `json:"linkedOrderIDs,omitempty" db:"-"`
 }
 
 func (o Order) CsvHeader() []string {
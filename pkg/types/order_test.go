@@ -0,0 +1,33 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelfTradePreventionMode_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		input string
+		want  SelfTradePreventionMode
+	}{
+		{`""`, ""},
+		{`"none"`, SelfTradePreventionModeNone},
+		{`"EXPIRE_TAKER"`, SelfTradePreventionModeExpireTaker},
+		{`"expire_maker"`, SelfTradePreventionModeExpireMaker},
+		{`"CANCEL_NEWEST"`, SelfTradePreventionModeExpireTaker},
+		{`"cancel_oldest"`, SelfTradePreventionModeExpireMaker},
+		{`"CANCEL_BOTH"`, SelfTradePreventionModeExpireBoth},
+	}
+
+	for _, tt := range tests {
+		var mode SelfTradePreventionMode
+		if assert.NoError(t, json.Unmarshal([]byte(tt.input), &mode), tt.input) {
+			assert.Equal(t, tt.want, mode, tt.input)
+		}
+	}
+
+	var mode SelfTradePreventionMode
+	assert.Error(t, json.Unmarshal([]byte(`"DECREMENT_AND_CANCEL"`), &mode))
+}
@@ -58,6 +58,12 @@ type Position struct {
 
 	AccumulatedProfit fixedpoint.Value `json:"accumulatedProfit,omitempty" db:"accumulated_profit"`
 
+	// AccountingMode is the lot accounting mode of the realized profit, the default is the weighted average cost
+	AccountingMode PositionAccountingMode `json:"accountingMode,omitempty" db:"-"`
+
+	// Lots are the open lots of the position, only tracked in the FIFO and LIFO accounting modes
+	Lots []PositionLot `json:"lots,omitempty" db:"-"`
+
 	// closing is a flag for marking this position is closing
 	closing bool
 
@@ -321,6 +327,7 @@ func (p *Position) Reset() {
 	p.Quote = fixedpoint.Zero
 	p.AverageCost = fixedpoint.Zero
 	p.TotalFee = make(map[string]fixedpoint.Value)
+	p.Lots = nil
 }
 
 func (p *Position) SetFeeRate(exchangeFee ExchangeFee) {
@@ -501,7 +508,10 @@ func (p *Position) AddTrade(td Trade) (profit fixedpoint.Value, netProfit fixedp
 		}
 
 	default:
-		if !td.Fee.IsZero() {
+		if !td.FeeInQuote.IsZero() {
+			// the fee is converted by the trade collector with the market prices
+			feeInQuote = td.FeeInQuote
+		} else if !td.Fee.IsZero() {
 			if p.ExchangeFeeRates != nil {
 				if exchangeFee, ok := p.ExchangeFeeRates[td.Exchange]; ok {
 					if td.IsMaker {
@@ -530,6 +540,10 @@ func (p *Position) AddTrade(td Trade) (profit fixedpoint.Value, netProfit fixedp
 
 	p.addTradeFee(td)
 
+	if p.AccountingMode.IsLotAccounting() {
+		return p.addLotTrade(td, quantity, quoteQuantity, feeInQuote)
+	}
+
 	// Base > 0 means we're in long position
 	// Base < 0  means we're in short position
 	switch td.Side {
@@ -0,0 +1,195 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+)
+
+// PositionAccountingMode is the lot accounting mode used for computing the realized profit of the position
+type PositionAccountingMode string
+
+const (
+	// PositionAccountingAverage is the default weighted average cost accounting
+	PositionAccountingAverage PositionAccountingMode = "average"
+
+	// PositionAccountingFIFO closes the earliest opened lots first
+	PositionAccountingFIFO PositionAccountingMode = "fifo"
+
+	// PositionAccountingLIFO closes the latest opened lots first
+	PositionAccountingLIFO PositionAccountingMode = "lifo"
+)
+
+func (m *PositionAccountingMode) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	switch mode := PositionAccountingMode(strings.ToLower(s)); mode {
+	case "", PositionAccountingAverage, PositionAccountingFIFO, PositionAccountingLIFO:
+		*m = mode
+		return nil
+	}
+
+	return fmt.Errorf("invalid position accounting mode: %q, valid modes are average, fifo and lifo", s)
+}
+
+// IsLotAccounting returns true if the position lots are tracked in this mode
+func (m PositionAccountingMode) IsLotAccounting() bool {
+	return m == PositionAccountingFIFO || m == PositionAccountingLIFO
+}
+
+// PositionLot is an open lot of the position, it's created by the trade that opens or increases the position.
+// Quantity is positive for the long lot and negative for the short lot.
+type PositionLot struct {
+	TradeID  uint64           `json:"tradeID"`
+	Quantity fixedpoint.Value `json:"quantity"`
+	Price    fixedpoint.Value `json:"price"`
+
+	// ApproximatePrice is the price with the trade fee in quote, used for calculating the net profit
+	ApproximatePrice fixedpoint.Value `json:"approximatePrice"`
+
+	Time time.Time `json:"time"`
+}
+
+// SetAccountingMode sets the lot accounting mode, it should be called before adding any trade
+func (p *Position) SetAccountingMode(mode PositionAccountingMode) {
+	p.Lock()
+	p.AccountingMode = mode
+	p.Unlock()
+}
+
+// GetLots returns a copy of the open lots of the position, in the opened order.
+// The lots are only tracked in the FIFO and LIFO accounting modes.
+func (p *Position) GetLots() []PositionLot {
+	p.Lock()
+	defer p.Unlock()
+
+	lots := make([]PositionLot, len(p.Lots))
+	copy(lots, p.Lots)
+	return lots
+}
+
+// addLotTrade adds the trade by the lot accounting, the closing quantity is matched against the open lots
+// in the FIFO or LIFO order, and the rest of the quantity opens a new lot.
+// It must be called with the position lock held.
+func (p *Position) addLotTrade(
+	td Trade, quantity, quoteQuantity, feeInQuote fixedpoint.Value,
+) (profit fixedpoint.Value, netProfit fixedpoint.Value, madeProfit bool) {
+	price := td.Price
+	sign := 1
+	if td.Side == SideTypeSell {
+		sign = -1
+	}
+
+	wasDust := p.IsDust(price)
+
+	remaining := quantity
+	closed := fixedpoint.Zero
+	for remaining.Sign() > 0 && len(p.Lots) > 0 && p.Lots[0].Quantity.Sign() != sign {
+		idx := 0
+		if p.AccountingMode == PositionAccountingLIFO {
+			idx = len(p.Lots) - 1
+		}
+
+		lot := p.Lots[idx]
+		lotQuantity := lot.Quantity.Abs()
+		matched := fixedpoint.Min(lotQuantity, remaining)
+
+		if lot.Quantity.Sign() > 0 {
+			profit = profit.Add(price.Sub(lot.Price).Mul(matched))
+			netProfit = netProfit.Add(price.Sub(lot.ApproximatePrice).Mul(matched))
+		} else {
+			profit = profit.Add(lot.Price.Sub(price).Mul(matched))
+			netProfit = netProfit.Add(lot.ApproximatePrice.Sub(price).Mul(matched))
+		}
+
+		if matched.Compare(lotQuantity) >= 0 {
+			p.Lots = append(p.Lots[:idx], p.Lots[idx+1:]...)
+		} else if lot.Quantity.Sign() > 0 {
+			p.Lots[idx].Quantity = lot.Quantity.Sub(matched)
+		} else {
+			p.Lots[idx].Quantity = lot.Quantity.Add(matched)
+		}
+
+		remaining = remaining.Sub(matched)
+		closed = closed.Add(matched)
+	}
+
+	// the fee is allocated by the closed and the opened quantity
+	var feePerUnit fixedpoint.Value
+	if quantity.Sign() > 0 {
+		feePerUnit = feeInQuote.Div(quantity)
+	}
+
+	if closed.Sign() > 0 {
+		netProfit = netProfit.Sub(feePerUnit.Mul(closed))
+	}
+
+	if remaining.Sign() > 0 {
+		approximatePrice := price.Add(feePerUnit)
+		if sign < 0 {
+			approximatePrice = price.Sub(feePerUnit)
+		}
+
+		lotQuantity := remaining
+		if sign < 0 {
+			lotQuantity = remaining.Neg()
+		}
+
+		p.Lots = append(p.Lots, PositionLot{
+			TradeID:          td.ID,
+			Quantity:         lotQuantity,
+			Price:            price,
+			ApproximatePrice: approximatePrice,
+			Time:             td.Time.Time(),
+		})
+
+		// the position is opened or reversed by this trade
+		if wasDust || closed.Sign() > 0 {
+			p.OpenedAt = td.Time.Time()
+		}
+	}
+
+	if sign > 0 {
+		p.Base = p.Base.Add(quantity)
+		p.Quote = p.Quote.Sub(quoteQuantity)
+	} else {
+		p.Base = p.Base.Sub(quantity)
+		p.Quote = p.Quote.Add(quoteQuantity)
+	}
+
+	p.updateLotAverageCost()
+
+	if closed.IsZero() {
+		return fixedpoint.Zero, fixedpoint.Zero, false
+	}
+
+	p.AccumulatedProfit = p.AccumulatedProfit.Add(profit)
+	return profit, netProfit, true
+}
+
+// updateLotAverageCost updates the average costs by the open lots,
+// so that the unrealized profit is still calculated with the average cost
+func (p *Position) updateLotAverageCost() {
+	totalQuantity := fixedpoint.Zero
+	totalCost := fixedpoint.Zero
+	totalApproximateCost := fixedpoint.Zero
+	for _, lot := range p.Lots {
+		q := lot.Quantity.Abs()
+		totalQuantity = totalQuantity.Add(q)
+		totalCost = totalCost.Add(lot.Price.Mul(q))
+		totalApproximateCost = totalApproximateCost.Add(lot.ApproximatePrice.Mul(q))
+	}
+
+	if totalQuantity.IsZero() {
+		return
+	}
+
+	p.AverageCost = totalCost.Div(totalQuantity)
+	p.ApproximateAverageCost = totalApproximateCost.Div(totalQuantity)
+}
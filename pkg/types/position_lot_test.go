@@ -0,0 +1,108 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+)
+
+func newLotTestTrade(id uint64, side SideType, price, quantity float64) Trade {
+	return Trade{
+		ID:            id,
+		Side:          side,
+		Price:         fixedpoint.NewFromFloat(price),
+		Quantity:      fixedpoint.NewFromFloat(quantity),
+		QuoteQuantity: fixedpoint.NewFromFloat(price * quantity),
+		Time:          Time(time.Unix(int64(id), 0)),
+	}
+}
+
+func TestPosition_AddTrade_FIFO(t *testing.T) {
+	pos := NewPosition("BTCUSDT", "BTC", "USDT")
+	pos.SetAccountingMode(PositionAccountingFIFO)
+
+	pos.AddTrade(newLotTestTrade(1, SideTypeBuy, 1000, 1))
+	pos.AddTrade(newLotTestTrade(2, SideTypeBuy, 2000, 1))
+
+	profit, netProfit, madeProfit := pos.AddTrade(newLotTestTrade(3, SideTypeSell, 3000, 1.5))
+	assert.True(t, madeProfit)
+	// (3000 - 1000) * 1 + (3000 - 2000) * 0.5
+	assert.Equal(t, "2500", profit.String())
+	assert.Equal(t, "2500", netProfit.String())
+	assert.Equal(t, "0.5", pos.Base.String())
+	assert.Equal(t, "2000", pos.AverageCost.String())
+
+	lots := pos.GetLots()
+	if assert.Len(t, lots, 1) {
+		assert.Equal(t, uint64(2), lots[0].TradeID)
+		assert.Equal(t, "0.5", lots[0].Quantity.String())
+	}
+}
+
+func TestPosition_AddTrade_LIFO(t *testing.T) {
+	pos := NewPosition("BTCUSDT", "BTC", "USDT")
+	pos.SetAccountingMode(PositionAccountingLIFO)
+
+	pos.AddTrade(newLotTestTrade(1, SideTypeBuy, 1000, 1))
+	pos.AddTrade(newLotTestTrade(2, SideTypeBuy, 2000, 1))
+
+	profit, _, madeProfit := pos.AddTrade(newLotTestTrade(3, SideTypeSell, 3000, 1.5))
+	assert.True(t, madeProfit)
+	// (3000 - 2000) * 1 + (3000 - 1000) * 0.5
+	assert.Equal(t, "2000", profit.String())
+	assert.Equal(t, "0.5", pos.Base.String())
+	assert.Equal(t, "1000", pos.AverageCost.String())
+
+	lots := pos.GetLots()
+	if assert.Len(t, lots, 1) {
+		assert.Equal(t, uint64(1), lots[0].TradeID)
+	}
+}
+
+func TestPosition_AddTrade_LotReverse(t *testing.T) {
+	pos := NewPosition("BTCUSDT", "BTC", "USDT")
+	pos.SetAccountingMode(PositionAccountingFIFO)
+
+	pos.AddTrade(newLotTestTrade(1, SideTypeBuy, 1000, 1))
+
+	// close the long lot and open a short lot
+	profit, _, _ := pos.AddTrade(newLotTestTrade(2, SideTypeSell, 1200, 3))
+	assert.Equal(t, "200", profit.String())
+	assert.Equal(t, "-2", pos.Base.String())
+	assert.Equal(t, "1200", pos.AverageCost.String())
+	assert.Equal(t, time.Unix(2, 0), pos.OpenedAt)
+
+	profit, _, _ = pos.AddTrade(newLotTestTrade(3, SideTypeBuy, 1100, 2))
+	assert.Equal(t, "200", profit.String())
+	assert.True(t, pos.Base.IsZero())
+	assert.Empty(t, pos.GetLots())
+	assert.Equal(t, "400", pos.AccumulatedProfit.String())
+}
+
+func TestPosition_AddTrade_LotFee(t *testing.T) {
+	pos := NewPosition("BTCUSDT", "BTC", "USDT")
+	pos.SetAccountingMode(PositionAccountingFIFO)
+
+	buy := newLotTestTrade(1, SideTypeBuy, 1000, 1)
+	buy.FeeCurrency = "BNB"
+	buy.FeeInQuote = fixedpoint.NewFromInt(2)
+	pos.AddTrade(buy)
+
+	sell := newLotTestTrade(2, SideTypeSell, 1100, 1)
+	sell.FeeCurrency = "BNB"
+	sell.FeeInQuote = fixedpoint.NewFromInt(3)
+	profit, netProfit, _ := pos.AddTrade(sell)
+	assert.Equal(t, "100", profit.String())
+	assert.Equal(t, "95", netProfit.String())
+}
+
+func TestPositionAccountingMode_UnmarshalJSON(t *testing.T) {
+	var mode PositionAccountingMode
+	assert.NoError(t, json.Unmarshal([]byte(`"FIFO"`), &mode))
+	assert.Equal(t, PositionAccountingFIFO, mode)
+	assert.Error(t, json.Unmarshal([]byte(`"hifo"`), &mode))
+}
@@ -22,12 +22,13 @@ const (
 var ErrInvalidPriceType = errors.New("invalid price type")
 
 func ParsePriceType(s string) (p PriceType, err error) {
-	p = PriceType(strings.ToUpper(s))
-	switch p {
-	case PriceTypeLast, PriceTypeBuy, PriceTypeSell, PriceTypeMid, PriceTypeMaker, PriceTypeTaker:
-		return p, err
+	p, err = ParseEnum("price type", s, PriceTypeLast, PriceTypeBuy, PriceTypeSell, PriceTypeMid, PriceTypeMaker, PriceTypeTaker)
+	if err != nil {
+		err.(*EnumError).Err = ErrInvalidPriceType
+		return PriceType(strings.ToUpper(s)), err
 	}
-	return p, ErrInvalidPriceType
+
+	return p, nil
 }
 
 func (p *PriceType) UnmarshalJSON(data []byte) error {
@@ -47,6 +48,21 @@ func (p *PriceType) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+func (p *PriceType) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var s string
+	if err := unmarshal(&s); err != nil {
+		return err
+	}
+
+	t, err := ParsePriceType(s)
+	if err != nil {
+		return err
+	}
+
+	*p = t
+	return nil
+}
+
 func (p PriceType) Map(ticker *Ticker, side SideType) fixedpoint.Value {
 	price := ticker.Last
 
@@ -2,14 +2,22 @@ package types
 
 import (
 	"fmt"
+	"sync"
 	"time"
+
+	"github.com/c9s/bbgo/pkg/timerwheel"
 )
 
 // PriceHeartBeat is used for monitoring the price volume update.
 type PriceHeartBeat struct {
+	mu              sync.Mutex
 	last            PriceVolume
 	lastUpdatedTime time.Time
 	timeout         time.Duration
+
+	// timer fires the timeout callback when the price is not updated within the timeout, it's only set by Watch
+	timer     *timerwheel.Timer
+	onTimeout func(last PriceVolume, lastUpdatedTime time.Time)
 }
 
 func NewPriceHeartBeat(timeout time.Duration) *PriceHeartBeat {
@@ -19,6 +27,8 @@ func NewPriceHeartBeat(timeout time.Duration) *PriceHeartBeat {
 }
 
 func (b *PriceHeartBeat) Last() PriceVolume {
+	b.mu.Lock()
+	defer b.mu.Unlock()
 	return b.last
 }
 
@@ -27,9 +37,16 @@ func (b *PriceHeartBeat) Last() PriceVolume {
 // If the price is not updated (same price) and the last time exceeded the timeout,
 // Then false, and an error will be returned
 func (b *PriceHeartBeat) Update(current PriceVolume) (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
 	if b.last.Price.IsZero() || b.last != current {
 		b.last = current
 		b.lastUpdatedTime = time.Now()
+
+		if b.timer != nil {
+			b.timer.Reset(b.timeout)
+		}
 		return true, nil // successfully updated
 	} else {
 		// if price and volume is not changed
@@ -43,3 +60,38 @@ func (b *PriceHeartBeat) Update(current PriceVolume) (bool, error) {
 
 	return false, nil
 }
+
+// Watch registers the timeout timer on the timer wheel, the callback is called on the wheel goroutine
+// when the price is not updated within the timeout, without waiting for the next Update call.
+// The callback is called once for each stall, and the returned function stops watching.
+func (b *PriceHeartBeat) Watch(wheel *timerwheel.Wheel, callback func(last PriceVolume, lastUpdatedTime time.Time)) (stop func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.timer != nil {
+		b.timer.Stop()
+	}
+
+	b.onTimeout = callback
+	b.timer = wheel.AfterFunc(b.timeout, b.fireTimeout)
+	timer := b.timer
+	return func() {
+		b.mu.Lock()
+		if b.timer == timer {
+			b.timer = nil
+		}
+		b.mu.Unlock()
+
+		timer.Stop()
+	}
+}
+
+func (b *PriceHeartBeat) fireTimeout() {
+	b.mu.Lock()
+	last, lastUpdatedTime, callback := b.last, b.lastUpdatedTime, b.onTimeout
+	b.mu.Unlock()
+
+	if callback != nil {
+		callback(last, lastUpdatedTime)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/timerwheel"
 )
 
 func TestPriceHeartBeat_Update(t *testing.T) {
@@ -28,3 +29,36 @@ func TestPriceHeartBeat_Update(t *testing.T) {
 	assert.NoError(t, err)
 	assert.True(t, updated, "should be updated when the volume is changed")
 }
+
+func TestPriceHeartBeat_Watch(t *testing.T) {
+	start := time.Now()
+	wheel := timerwheel.New(time.Second, start)
+	hb := NewPriceHeartBeat(5 * time.Second)
+
+	var timeouts []PriceVolume
+	stop := hb.Watch(wheel, func(last PriceVolume, lastUpdatedTime time.Time) {
+		timeouts = append(timeouts, last)
+	})
+
+	pv := PriceVolume{Price: fixedpoint.NewFromFloat(22.0), Volume: fixedpoint.NewFromFloat(100.0)}
+	_, _ = hb.Update(pv)
+
+	wheel.Advance(start.Add(4 * time.Second))
+	assert.Empty(t, timeouts)
+
+	// the unchanged price does not reset the timeout
+	_, _ = hb.Update(pv)
+	wheel.Advance(start.Add(5 * time.Second))
+	assert.Equal(t, []PriceVolume{pv}, timeouts)
+
+	// the timeout is fired once for each stall
+	wheel.Advance(start.Add(20 * time.Second))
+	assert.Len(t, timeouts, 1)
+
+	_, _ = hb.Update(PriceVolume{Price: fixedpoint.NewFromFloat(23.0), Volume: fixedpoint.NewFromFloat(100.0)})
+	stop()
+
+	wheel.Advance(start.Add(40 * time.Second))
+	assert.Len(t, timeouts, 1)
+	assert.Equal(t, 0, wheel.Len())
+}
@@ -0,0 +1,47 @@
+package types
+
+import (
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+)
+
+// RegimeStats is the performance of a strategy instance in a time slice (usually an hour),
+// attributed to the volatility and the trend regimes classified from the klines of the slice.
+type RegimeStats struct {
+	GID                int64        `json:"gid,omitempty" db:"gid"`
+	Strategy           string       `json:"strategy" db:"strategy"`
+	StrategyInstanceID string       `json:"strategyInstanceID" db:"strategy_instance_id"`
+	Exchange           ExchangeName `json:"exchange" db:"exchange"`
+	Symbol             string       `json:"symbol" db:"symbol"`
+
+	VolatilityRegime string `json:"volatilityRegime" db:"volatility_regime"`
+	TrendRegime      string `json:"trendRegime" db:"trend_regime"`
+
+	StartTime Time `json:"startTime" db:"start_time"`
+	EndTime   Time `json:"endTime" db:"end_time"`
+
+	// NumOrders is the number of the maker orders placed in the slice
+	NumOrders int `json:"numOrders" db:"num_orders"`
+
+	// NumFills is the number of the maker fills in the slice
+	NumFills int `json:"numFills" db:"num_fills"`
+
+	QuotedVolume fixedpoint.Value `json:"quotedVolume" db:"quoted_volume"`
+	FilledVolume fixedpoint.Value `json:"filledVolume" db:"filled_volume"`
+
+	// FillRatio is the filled volume over the quoted volume
+	FillRatio fixedpoint.Value `json:"fillRatio" db:"fill_ratio"`
+
+	Profit    fixedpoint.Value `json:"profit" db:"profit"`
+	NetProfit fixedpoint.Value `json:"netProfit" db:"net_profit"`
+
+	// NumMarkouts is the number of the markouts of the maker fills measured in the slice
+	NumMarkouts int `json:"numMarkouts" db:"num_markouts"`
+
+	// AverageMarkout is the average markout ratio of the maker fills, negative means adverse selection
+	AverageMarkout fixedpoint.Value `json:"averageMarkout" db:"average_markout"`
+}
+
+// Regime returns the regime key of the stats, e.g. high-uptrend
+func (s *RegimeStats) Regime() string {
+	return s.VolatilityRegime + "-" + s.TrendRegime
+}
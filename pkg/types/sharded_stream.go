@@ -0,0 +1,216 @@
+package types
+
+import (
+	"context"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// StreamFactory creates a new stream connection of the exchange
+type StreamFactory func() Stream
+
+// ShardedStream shards the subscriptions across multiple stream connections,
+// since the exchanges cap the number of the streams per connection.
+//
+// The subscriptions of the same symbol are kept in the same shard, and each shard carries at most
+// maxSubscriptions subscriptions unless a single symbol needs more. The events of all the shards are fanned in
+// to the callbacks of the sharded stream, and the subscriptions are re-balanced across the shards on reconnect.
+type ShardedStream struct {
+	StandardStream
+
+	factory          StreamFactory
+	maxSubscriptions int
+
+	ctx context.Context
+
+	shardsMu sync.Mutex
+	shards   []Stream
+}
+
+func NewShardedStream(factory StreamFactory, maxSubscriptions int) *ShardedStream {
+	return &ShardedStream{
+		StandardStream:   NewStandardStream(),
+		factory:          factory,
+		maxSubscriptions: maxSubscriptions,
+	}
+}
+
+// Shards returns the current shard connections
+func (s *ShardedStream) Shards() []Stream {
+	s.shardsMu.Lock()
+	defer s.shardsMu.Unlock()
+
+	shards := make([]Stream, len(s.shards))
+	copy(shards, s.shards)
+	return shards
+}
+
+// Connect creates the shard connections and connects them
+func (s *ShardedStream) Connect(ctx context.Context) error {
+	s.shardsMu.Lock()
+	defer s.shardsMu.Unlock()
+
+	s.ctx = ctx
+	return s.connectShards(ctx)
+}
+
+// Reconnect closes the shard connections, and re-balances the subscriptions across the new shard connections
+func (s *ShardedStream) Reconnect() {
+	go func() {
+		if err := s.rebalance(); err != nil {
+			log.WithError(err).Errorf("[websocket] sharded stream rebalance error")
+		}
+	}()
+}
+
+func (s *ShardedStream) Resubscribe(fn func(old []Subscription) (new []Subscription, err error)) error {
+	s.subLock.Lock()
+	subs, err := fn(s.Subscriptions)
+	if err != nil {
+		s.subLock.Unlock()
+		return err
+	}
+
+	s.Subscriptions = subs
+	s.subLock.Unlock()
+
+	s.Reconnect()
+	return nil
+}
+
+func (s *ShardedStream) Close() error {
+	s.shardsMu.Lock()
+	defer s.shardsMu.Unlock()
+
+	return s.closeShards()
+}
+
+func (s *ShardedStream) rebalance() error {
+	s.shardsMu.Lock()
+	defer s.shardsMu.Unlock()
+
+	if s.ctx == nil {
+		return nil
+	}
+
+	if err := s.closeShards(); err != nil {
+		log.WithError(err).Warnf("[websocket] unable to close the shard connections")
+	}
+
+	return s.connectShards(s.ctx)
+}
+
+func (s *ShardedStream) closeShards() (err error) {
+	for _, shard := range s.shards {
+		if closeErr := shard.Close(); closeErr != nil {
+			err = closeErr
+		}
+	}
+
+	s.shards = nil
+	return err
+}
+
+func (s *ShardedStream) connectShards(ctx context.Context) error {
+	groups := ShardSubscriptions(s.GetSubscriptions(), s.maxSubscriptions)
+
+	// the stream connects without any subscription, e.g., the private user data stream
+	if len(groups) == 0 {
+		groups = append(groups, nil)
+	}
+
+	log.Infof("[websocket] connecting %d shard connections", len(groups))
+
+	for _, subs := range groups {
+		shard := s.factory()
+		if s.PublicOnly {
+			shard.SetPublicOnly()
+		}
+
+		s.bindShard(shard)
+
+		for _, sub := range subs {
+			shard.Subscribe(sub.Channel, sub.Symbol, sub.Options)
+		}
+
+		if err := shard.Connect(ctx); err != nil {
+			return err
+		}
+
+		s.shards = append(s.shards, shard)
+	}
+
+	return nil
+}
+
+// bindShard fans in the events of the shard to the callbacks of the sharded stream
+func (s *ShardedStream) bindShard(shard Stream) {
+	shard.OnStart(s.EmitStart)
+	shard.OnConnect(s.EmitConnect)
+	shard.OnDisconnect(s.EmitDisconnect)
+	shard.OnAuth(s.EmitAuth)
+	shard.OnRawMessage(s.EmitRawMessage)
+	shard.OnTradeUpdate(s.EmitTradeUpdate)
+	shard.OnOrderUpdate(s.EmitOrderUpdate)
+	shard.OnBalanceSnapshot(s.EmitBalanceSnapshot)
+	shard.OnBalanceUpdate(s.EmitBalanceUpdate)
+	shard.OnKLineClosed(s.EmitKLineClosed)
+	shard.OnKLine(s.EmitKLine)
+	shard.OnBookUpdate(s.EmitBookUpdate)
+	shard.OnBookTickerUpdate(s.EmitBookTickerUpdate)
+	shard.OnBookSnapshot(s.EmitBookSnapshot)
+	shard.OnMarketTrade(s.EmitMarketTrade)
+	shard.OnAggTrade(s.EmitAggTrade)
+	shard.OnForceOrder(s.EmitForceOrder)
+	shard.OnFuturesPositionUpdate(s.EmitFuturesPositionUpdate)
+	shard.OnFuturesPositionSnapshot(s.EmitFuturesPositionSnapshot)
+}
+
+// ShardSubscriptions groups the subscriptions into the shards, the subscriptions of the same symbol are in the same shard.
+// Each symbol group is assigned to the least loaded shard that still has room, so that the shards are balanced.
+// All the subscriptions are in one shard if maxSubscriptions is not positive.
+func ShardSubscriptions(subs []Subscription, maxSubscriptions int) [][]Subscription {
+	if len(subs) == 0 {
+		return nil
+	}
+
+	if maxSubscriptions <= 0 {
+		return [][]Subscription{subs}
+	}
+
+	var symbols []string
+	symbolSubs := make(map[string][]Subscription)
+	for _, sub := range subs {
+		if _, ok := symbolSubs[sub.Symbol]; !ok {
+			symbols = append(symbols, sub.Symbol)
+		}
+
+		symbolSubs[sub.Symbol] = append(symbolSubs[sub.Symbol], sub)
+	}
+
+	var shards [][]Subscription
+	for _, symbol := range symbols {
+		group := symbolSubs[symbol]
+
+		target := -1
+		for i, shard := range shards {
+			if len(shard)+len(group) > maxSubscriptions {
+				continue
+			}
+
+			if target == -1 || len(shard) < len(shards[target]) {
+				target = i
+			}
+		}
+
+		if target == -1 {
+			shards = append(shards, nil)
+			target = len(shards) - 1
+		}
+
+		shards[target] = append(shards[target], group...)
+	}
+
+	return shards
+}
@@ -0,0 +1,56 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShardSubscriptions(t *testing.T) {
+	subs := []Subscription{
+		{Symbol: "BTCUSDT", Channel: BookChannel},
+		{Symbol: "BTCUSDT", Channel: KLineChannel, Options: SubscribeOptions{Interval: Interval1m}},
+		{Symbol: "ETHUSDT", Channel: BookChannel},
+		{Symbol: "BNBUSDT", Channel: BookChannel},
+		{Symbol: "ETHUSDT", Channel: KLineChannel, Options: SubscribeOptions{Interval: Interval1m}},
+		{Symbol: "LTCUSDT", Channel: BookChannel},
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		shards := ShardSubscriptions(subs, 0)
+		assert.Len(t, shards, 1)
+		assert.Len(t, shards[0], len(subs))
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		assert.Nil(t, ShardSubscriptions(nil, 2))
+	})
+
+	t.Run("sharded", func(t *testing.T) {
+		shards := ShardSubscriptions(subs, 3)
+		assert.Len(t, shards, 2)
+
+		total := 0
+		symbolShard := map[string]int{}
+		for i, shard := range shards {
+			assert.LessOrEqual(t, len(shard), 3)
+			total += len(shard)
+
+			for _, sub := range shard {
+				if idx, ok := symbolShard[sub.Symbol]; ok {
+					assert.Equal(t, idx, i, "subscriptions of %s should be in the same shard", sub.Symbol)
+				}
+
+				symbolShard[sub.Symbol] = i
+			}
+		}
+
+		assert.Equal(t, len(subs), total)
+	})
+
+	t.Run("oversized symbol", func(t *testing.T) {
+		shards := ShardSubscriptions(subs, 1)
+		assert.Len(t, shards, 4)
+		assert.Len(t, shards[0], 2)
+	})
+}
@@ -6,5 +6,9 @@ type StrategyStatus string
 const (
 	StrategyStatusRunning StrategyStatus = "RUNNING"
 	StrategyStatusStopped StrategyStatus = "STOPPED"
+
+	// StrategyStatusWindingDown means the strategy only reduces its position and stops once the position is flat
+	StrategyStatusWindingDown StrategyStatus = "WINDING_DOWN"
+
 	StrategyStatusUnknown StrategyStatus = "UNKNOWN"
 )
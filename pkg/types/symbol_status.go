@@ -0,0 +1,41 @@
+package types
+
+import (
+	"context"
+	"time"
+)
+
+// SymbolTradingStatus is the trading status of a symbol on the exchange
+type SymbolTradingStatus string
+
+const (
+	SymbolTradingStatusTrading  SymbolTradingStatus = "TRADING"
+	SymbolTradingStatusHalted   SymbolTradingStatus = "HALTED"
+	SymbolTradingStatusDelisted SymbolTradingStatus = "DELISTED"
+)
+
+// Tradable returns true when the symbol can be traded
+func (s SymbolTradingStatus) Tradable() bool {
+	return s == "" || s == SymbolTradingStatusTrading
+}
+
+// SymbolStatus is the trading status of a symbol reported by the exchange
+type SymbolStatus struct {
+	Symbol  string              `json:"symbol"`
+	Status  SymbolTradingStatus `json:"status"`
+	Message string              `json:"message,omitempty"`
+
+	// DelistTime is the announced delisting time, it's zero when the delisting time is not announced
+	DelistTime time.Time `json:"delistTime,omitempty"`
+}
+
+// Normal returns true when the symbol is tradable and no delisting is announced
+func (s SymbolStatus) Normal() bool {
+	return s.Status.Tradable() && s.DelistTime.IsZero()
+}
+
+// ExchangeSymbolStatusService queries the trading status of the symbols,
+// the symbols that are not returned are considered tradable
+type ExchangeSymbolStatusService interface {
+	QuerySymbolStatus(ctx context.Context) (map[string]SymbolStatus, error)
+}
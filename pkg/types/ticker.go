@@ -16,8 +16,32 @@ type Ticker struct {
 	Low    fixedpoint.Value // `low` from Max, `lowPrice` from binance
 	Buy    fixedpoint.Value // `buy` from Max, `bidPrice` from binance
 	Sell   fixedpoint.Value // `sell` from Max, `askPrice` from binance
+
+	// BuySize, SellSize and LastSize are the best bid size, the best ask size and the last trade size,
+	// they are zero if the exchange does not provide them.
+	BuySize  fixedpoint.Value // `bidQty` from binance
+	SellSize fixedpoint.Value // `askQty` from binance
+	LastSize fixedpoint.Value // `lastQty` from binance
 }
 
 func (t *Ticker) String() string {
-	return fmt.Sprintf("O:%s H:%s L:%s LAST:%s BID/ASK:%s/%s TIME:%s", t.Open, t.High, t.Low, t.Last, t.Buy, t.Sell, t.Time.String())
+	return fmt.Sprintf("O:%s H:%s L:%s LAST:%s BID/ASK:%s/%s BID/ASK SIZE:%s/%s TIME:%s",
+		t.Open, t.High, t.Low, t.Last, t.Buy, t.Sell, t.BuySize, t.SellSize, t.Time.String())
+}
+
+// FilterTickers returns the tickers of the given symbols, all the tickers are returned if no symbol is given.
+// It's used by the exchanges that query all the tickers in one request for querying multiple symbols.
+func FilterTickers(tickers map[string]Ticker, symbols ...string) map[string]Ticker {
+	if len(symbols) == 0 {
+		return tickers
+	}
+
+	filtered := make(map[string]Ticker, len(symbols))
+	for _, symbol := range symbols {
+		if ticker, ok := tickers[symbol]; ok {
+			filtered[symbol] = ticker
+		}
+	}
+
+	return filtered
 }
@@ -0,0 +1,21 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+)
+
+func TestFilterTickers(t *testing.T) {
+	tickers := map[string]Ticker{
+		"BTCUSDT": {Last: fixedpoint.NewFromFloat(60000.0)},
+		"ETHUSDT": {Last: fixedpoint.NewFromFloat(3000.0)},
+	}
+
+	assert.Equal(t, tickers, FilterTickers(tickers))
+	assert.Equal(t, map[string]Ticker{
+		"ETHUSDT": {Last: fixedpoint.NewFromFloat(3000.0)},
+	}, FilterTickers(tickers, "ETHUSDT", "LTCUSDT"))
+}
@@ -83,6 +83,10 @@ type Trade struct {
 	// This is only used by the MAX exchange
 	FeeDiscounted bool `json:"feeDiscounted" db:"-"`
 
+	// FeeInQuote is the fee converted into the quote currency when the fee is paid in a third currency,
+	// it's set by the fee currency converter of the trade collector, and it's zero if the fee is not converted.
+	FeeInQuote fixedpoint.Value `json:"feeInQuote,omitempty" db:"-"`
+
 	IsMargin   bool `json:"isMargin" db:"is_margin"`
 	IsFutures  bool `json:"isFutures" db:"is_futures"`
 	IsIsolated bool `json:"isIsolated" db:"is_isolated"`